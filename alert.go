@@ -0,0 +1,199 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrAlertNotFound is the error msg for a missing alert.
+const ErrAlertNotFound = "alert not found"
+
+// ops for alert error.
+const (
+	OpFindAlertByID    = "FindAlertByID"
+	OpFindAlerts       = "FindAlerts"
+	OpAcknowledgeAlert = "AcknowledgeAlert"
+	OpResolveAlert     = "ResolveAlert"
+	OpTrackStatuses    = "TrackStatuses"
+	OpEscalateAlert    = "EscalateAlert"
+)
+
+// AlertLevelOK is the level of the statuses that resolve alerts.
+const AlertLevelOK = "ok"
+
+// MaxAlertEventsCount is the maximum number of events kept by an alert.
+const MaxAlertEventsCount = 100
+
+// statuses of alerts.
+const (
+	// AlertOpen is the status of an alert nobody handles yet.
+	AlertOpen = "open"
+	// AlertAcknowledged is the status of an alert a user handles.
+	AlertAcknowledged = "acknowledged"
+	// AlertResolved is the status of an alert which series went back to ok, or which a user resolved.
+	AlertResolved = "resolved"
+)
+
+// types of alert events.
+const (
+	AlertEventOpened       = "opened"
+	AlertEventLevel        = "level"
+	AlertEventAcknowledged = "acknowledged"
+	AlertEventEscalated    = "escalated"
+	AlertEventResolved     = "resolved"
+)
+
+// AlertService describes a service for finding and handling the alerts of checks.
+type AlertService interface {
+	// FindAlertByID finds a single alert by its ID.
+	FindAlertByID(ctx context.Context, id ID) (*Alert, error)
+
+	// FindAlerts returns the alerts matching the filter and their count,
+	// the most recently opened first.
+	FindAlerts(ctx context.Context, filter AlertFilter, opt ...FindOptions) ([]*Alert, int, error)
+
+	// AcknowledgeAlert records that the user handles the open alert.
+	AcknowledgeAlert(ctx context.Context, id, userID ID, comment string) (*Alert, error)
+
+	// ResolveAlert resolves the alert on behalf of the user.
+	ResolveAlert(ctx context.Context, id, userID ID, comment string) (*Alert, error)
+}
+
+// AlertTracker keeps track of the alerts of checks from their statuses, and of their escalations.
+type AlertTracker interface {
+	// TrackStatuses opens an alert for the series of the non ok statuses that have none,
+	// updates the level of the alerts of the others and resolves the alerts of the ok statuses.
+	TrackStatuses(ctx context.Context, statuses []AlertStatus) error
+
+	// EscalateAlert records that the notification rule escalated the alert to the endpoint.
+	EscalateAlert(ctx context.Context, id, ruleID, endpointID ID, at time.Time) (*Alert, error)
+}
+
+// AlertNotifier sends the escalations of alerts to notification endpoints.
+type AlertNotifier interface {
+	// NotifyAlert sends n to the endpoint of the organization of the alert.
+	NotifyAlert(ctx context.Context, endpointID ID, n AlertNotification) error
+}
+
+// Alert is the state of a series of a check since it went non ok.
+// It is open until it is acknowledged, and resolved once the series is ok again
+// or a user resolves it. The next non ok status of a resolved series opens a new alert.
+type Alert struct {
+	ID        ID                `json:"id,omitempty"`
+	OrgID     ID                `json:"orgID"`
+	CheckID   ID                `json:"checkID"`
+	CheckName string            `json:"checkName"`
+	Tags      map[string]string `json:"tags"`
+	Status    string            `json:"status"`
+	// Level and Message are the ones of the last status of the series.
+	Level          string       `json:"level"`
+	Message        string       `json:"message,omitempty"`
+	OpenedAt       time.Time    `json:"openedAt"`
+	LastStatusAt   time.Time    `json:"lastStatusAt"`
+	AcknowledgedAt *time.Time   `json:"acknowledgedAt,omitempty"`
+	AcknowledgedBy ID           `json:"acknowledgedBy,omitempty"`
+	ResolvedAt     *time.Time   `json:"resolvedAt,omitempty"`
+	ResolvedBy     ID           `json:"resolvedBy,omitempty"`
+	Events         []AlertEvent `json:"events"`
+}
+
+// AlertEvent is a change of an alert.
+type AlertEvent struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	// Level is the level of the status that opened the alert or changed its level.
+	Level string `json:"level,omitempty"`
+	// UserID is the user who acknowledged or resolved the alert.
+	UserID  ID     `json:"userID,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// RuleID and EndpointID are the notification rule that escalated the alert and the endpoint it was escalated to.
+	RuleID     ID `json:"ruleID,omitempty"`
+	EndpointID ID `json:"endpointID,omitempty"`
+}
+
+// AddEvent appends e to the events of the alert, dropping the oldest events past MaxAlertEventsCount
+// but the one that opened the alert.
+func (a *Alert) AddEvent(e AlertEvent) {
+	a.Events = append(a.Events, e)
+	if len(a.Events) > MaxAlertEventsCount {
+		a.Events = append(a.Events[:1], a.Events[len(a.Events)-MaxAlertEventsCount+1:]...)
+	}
+}
+
+// EscalatedBy returns whether the notification rule escalated the alert.
+func (a *Alert) EscalatedBy(ruleID ID) bool {
+	for _, e := range a.Events {
+		if e.Type == AlertEventEscalated && e.RuleID == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// SeriesKey returns the key of the series of the alert within its check.
+func (a *Alert) SeriesKey() string {
+	return AlertSeriesKey(a.Tags)
+}
+
+// AlertSeriesKey returns the key of the series of the tags.
+func AlertSeriesKey(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// AlertStatus is a status of a series of a check an alert is tracked from.
+type AlertStatus struct {
+	OrgID     ID
+	CheckID   ID
+	CheckName string
+	Tags      map[string]string
+	Level     string
+	Message   string
+	Time      time.Time
+}
+
+// AlertFilter represents a set of filters that restrict the returned alerts.
+type AlertFilter struct {
+	OrgID   *ID
+	CheckID *ID
+	Status  *string
+}
+
+// QueryParams implements PagingFilter.
+//
+// It converts AlertFilter fields to url query params.
+func (f AlertFilter) QueryParams() map[string][]string {
+	qp := url.Values{}
+	if f.OrgID != nil {
+		qp.Add("orgID", f.OrgID.String())
+	}
+	if f.CheckID != nil {
+		qp.Add("checkID", f.CheckID.String())
+	}
+	if f.Status != nil {
+		qp.Add("status", *f.Status)
+	}
+	return qp
+}
+
+// AlertNotification is the notification of an alert escalated by a notification rule.
+type AlertNotification struct {
+	Alert    Alert  `json:"alert"`
+	RuleID   ID     `json:"ruleID"`
+	RuleName string `json:"ruleName"`
+	// Unacknowledged is how long the alert has been open.
+	Unacknowledged Duration `json:"unacknowledged"`
+}
+
+// Title returns a one line summary of the notification.
+func (n AlertNotification) Title() string {
+	return fmt.Sprintf("Alert of check %q is %s and unacknowledged for %s", n.Alert.CheckName, n.Alert.Level, n.Unacknowledged.String())
+}
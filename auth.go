@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // AuthorizationKind is returned by (*Authorization).Kind().
@@ -14,6 +15,9 @@ var ErrUnableToCreateToken = &Error{
 	Code: EInvalid,
 }
 
+// ErrAuthorizationExpired is the error message for expired authorizations.
+const ErrAuthorizationExpired = "authorization has expired"
+
 // Authorization is an authorization. 🎉
 type Authorization struct {
 	ID          ID           `json:"id"`
@@ -23,6 +27,8 @@ type Authorization struct {
 	OrgID       ID           `json:"orgID"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions"`
+	// ExpiresAt is when the token of the authorization stops being valid, it never expires when unset.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 	CRUDLog
 }
 
@@ -46,13 +52,17 @@ func (a *Authorization) Valid() error {
 	return nil
 }
 
-// Allowed returns true if the authorization is active and request permission
+// Allowed returns true if the authorization is active, unexpired and request permission
 // exists in the authorization's list of permissions.
 func (a *Authorization) Allowed(p Permission) bool {
 	if !a.IsActive() {
 		return false
 	}
 
+	if err := a.CheckExpiry(time.Now()); err != nil {
+		return false
+	}
+
 	return PermissionAllowed(p, a.Permissions)
 }
 
@@ -66,6 +76,18 @@ func (a *Authorization) IsActive() bool {
 	return a.Status == Active
 }
 
+// CheckExpiry returns an error if the authorization is expired at now.
+func (a *Authorization) CheckExpiry(now time.Time) error {
+	if a.ExpiresAt != nil && !now.Before(*a.ExpiresAt) {
+		return &Error{
+			Code: EUnauthorized,
+			Msg:  ErrAuthorizationExpired,
+		}
+	}
+
+	return nil
+}
+
 // GetUserID returns the user id.
 func (a *Authorization) GetUserID() ID {
 	return a.UserID
@@ -85,6 +107,7 @@ const (
 	OpCreateAuthorization      = "CreateAuthorization"
 	OpUpdateAuthorization      = "UpdateAuthorization"
 	OpDeleteAuthorization      = "DeleteAuthorization"
+	OpRotateAuthorization      = "RotateAuthorization"
 )
 
 // AuthorizationService represents a service for managing authorization data.
@@ -109,6 +132,13 @@ type AuthorizationService interface {
 	DeleteAuthorization(ctx context.Context, id ID) error
 }
 
+// AuthorizationRotator replaces the tokens of authorizations.
+type AuthorizationRotator interface {
+	// RotateAuthorization creates an authorization with a new token and the permissions of the authorization,
+	// and expires the authorization after the grace period.
+	RotateAuthorization(ctx context.Context, id ID, grace time.Duration) (*Authorization, error)
+}
+
 // AuthorizationFilter represents a set of filter that restrict the returned results.
 type AuthorizationFilter struct {
 	Token *string
@@ -0,0 +1,88 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.AlertService = (*AlertService)(nil)
+
+// AlertService wraps a influxdb.AlertService and authorizes actions
+// against it appropriately.
+type AlertService struct {
+	s influxdb.AlertService
+}
+
+// NewAlertService constructs an instance of an authorizing alert service.
+func NewAlertService(s influxdb.AlertService) *AlertService {
+	return &AlertService{
+		s: s,
+	}
+}
+
+func authorizeAlert(ctx context.Context, a influxdb.Action, orgID, id influxdb.ID) error {
+	p, err := influxdb.NewPermissionAtID(id, a, influxdb.AlertsResourceType, orgID)
+	if err != nil {
+		return err
+	}
+	return IsAllowed(ctx, *p)
+}
+
+// FindAlertByID checks to see if the authorizer on context has read access to the id provided.
+func (s *AlertService) FindAlertByID(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	a, err := s.s.FindAlertByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeAlert(ctx, influxdb.ReadAction, a.OrgID, id); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// FindAlerts retrieves all alerts that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *AlertService) FindAlerts(ctx context.Context, filter influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, int, error) {
+	as, _, err := s.s.FindAlerts(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	alerts := as[:0]
+	for _, a := range as {
+		err := authorizeAlert(ctx, influxdb.ReadAction, a.OrgID, a.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, len(alerts), nil
+}
+
+// AcknowledgeAlert checks to see if the authorizer on context has write access to the alert provided.
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, id, userID influxdb.ID, comment string) (*influxdb.Alert, error) {
+	a, err := s.FindAlertByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAlert(ctx, influxdb.WriteAction, a.OrgID, id); err != nil {
+		return nil, err
+	}
+	return s.s.AcknowledgeAlert(ctx, id, userID, comment)
+}
+
+// ResolveAlert checks to see if the authorizer on context has write access to the alert provided.
+func (s *AlertService) ResolveAlert(ctx context.Context, id, userID influxdb.ID, comment string) (*influxdb.Alert, error) {
+	a, err := s.FindAlertByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAlert(ctx, influxdb.WriteAction, a.OrgID, id); err != nil {
+		return nil, err
+	}
+	return s.s.ResolveAlert(ctx, id, userID, comment)
+}
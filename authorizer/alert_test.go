@@ -0,0 +1,127 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newMockAlertService() *mock.AlertService {
+	as := mock.NewAlertService()
+	as.FindAlertByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+		return &influxdb.Alert{ID: id, OrgID: 10}, nil
+	}
+	as.FindAlertsFn = func(ctx context.Context, filter influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, int, error) {
+		return []*influxdb.Alert{
+			{ID: 1, OrgID: 10},
+			{ID: 2, OrgID: 10},
+			{ID: 3, OrgID: 11},
+		}, 3, nil
+	}
+	as.AcknowledgeAlertFn = func(ctx context.Context, id, userID influxdb.ID, comment string) (*influxdb.Alert, error) {
+		return &influxdb.Alert{ID: id, OrgID: 10}, nil
+	}
+	as.ResolveAlertFn = func(ctx context.Context, id, userID influxdb.ID, comment string) (*influxdb.Alert, error) {
+		return &influxdb.Alert{ID: id, OrgID: 10}, nil
+	}
+	return as
+}
+
+func TestAlertService_FindAlerts(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		want        []influxdb.ID
+	}{
+		{
+			name: "authorized to read all alerts",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.AlertsResourceType},
+			}},
+			want: []influxdb.ID{1, 2, 3},
+		},
+		{
+			name: "authorized to read the alerts of an organization",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.AlertsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+			want: []influxdb.ID{1, 2},
+		},
+		{
+			name: "authorized to read a single alert",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.AlertsResourceType, ID: influxdbtesting.IDPtr(2)},
+			}},
+			want: []influxdb.ID{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewAlertService(newMockAlertService())
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			as, n, err := s.FindAlerts(ctx, influxdb.AlertFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []influxdb.ID
+			for _, a := range as {
+				got = append(got, a.ID)
+			}
+			if !cmp.Equal(tt.want, got) || n != len(tt.want) {
+				t.Errorf("unexpected alerts -want/+got:\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestAlertService_Write(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name: "authorized to write the alerts of the organization",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.AlertsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}, {
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.AlertsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+		},
+		{
+			name: "unauthorized to write the alerts of the organization",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.AlertsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/alerts/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewAlertService(newMockAlertService())
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			_, err := s.AcknowledgeAlert(ctx, 1, 5, "")
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+
+			_, err = s.ResolveAlert(ctx, 1, 5, "")
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package authorizer
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/influxdb"
 )
@@ -170,3 +171,38 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id influ
 
 	return s.s.DeleteAuthorization(ctx, id)
 }
+
+var _ influxdb.AuthorizationRotator = (*AuthorizationRotator)(nil)
+
+// AuthorizationRotator wraps a influxdb.AuthorizationRotator and authorizes rotating authorizations.
+type AuthorizationRotator struct {
+	s influxdb.AuthorizationService
+	r influxdb.AuthorizationRotator
+}
+
+// NewAuthorizationRotator constructs an instance of an authorizing authorization rotator.
+func NewAuthorizationRotator(s influxdb.AuthorizationService, r influxdb.AuthorizationRotator) *AuthorizationRotator {
+	return &AuthorizationRotator{
+		s: s,
+		r: r,
+	}
+}
+
+// RotateAuthorization checks to see if the authorizer on context has write access to the authorization provided
+// and is allowed all of its permissions.
+func (s *AuthorizationRotator) RotateAuthorization(ctx context.Context, id influxdb.ID, grace time.Duration) (*influxdb.Authorization, error) {
+	a, err := s.s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteAuthorization(ctx, a.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := VerifyPermissions(ctx, a.Permissions); err != nil {
+		return nil, err
+	}
+
+	return s.r.RotateAuthorization(ctx, id, grace)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
@@ -247,7 +248,11 @@ func TestAuthorizationService_WriteAuthorization(t *testing.T) {
 			m.UpdateAuthorizationFn = func(ctx context.Context, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
 				return nil, nil
 			}
+			m.RotateAuthorizationFn = func(ctx context.Context, id influxdb.ID, grace time.Duration) (*influxdb.Authorization, error) {
+				return nil, nil
+			}
 			s := authorizer.NewAuthorizationService(m)
+			r := authorizer.NewAuthorizationRotator(m, m)
 
 			ctx := context.Background()
 			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
@@ -267,6 +272,11 @@ func TestAuthorizationService_WriteAuthorization(t *testing.T) {
 				influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
 			})
 
+			t.Run("rotate authorization", func(t *testing.T) {
+				_, err := r.RotateAuthorization(ctx, 10, time.Hour)
+				influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+			})
+
 		})
 	}
 }
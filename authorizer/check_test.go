@@ -163,7 +163,19 @@ func TestCheckService_FindChecks(t *testing.T) {
 									OrgID: 11,
 								},
 							},
-						}, 3, nil
+							&check.Rate{
+								Base: check.Base{
+									ID:    4,
+									OrgID: 10,
+								},
+							},
+							&check.Absence{
+								Base: check.Base{
+									ID:    5,
+									OrgID: 11,
+								},
+							},
+						}, 5, nil
 					},
 				},
 			},
@@ -195,6 +207,18 @@ func TestCheckService_FindChecks(t *testing.T) {
 							OrgID: 11,
 						},
 					},
+					&check.Rate{
+						Base: check.Base{
+							ID:    4,
+							OrgID: 10,
+						},
+					},
+					&check.Absence{
+						Base: check.Base{
+							ID:    5,
+							OrgID: 11,
+						},
+					},
 				},
 			},
 		},
@@ -222,7 +246,19 @@ func TestCheckService_FindChecks(t *testing.T) {
 									OrgID: 11,
 								},
 							},
-						}, 3, nil
+							&check.Rate{
+								Base: check.Base{
+									ID:    4,
+									OrgID: 10,
+								},
+							},
+							&check.Absence{
+								Base: check.Base{
+									ID:    5,
+									OrgID: 11,
+								},
+							},
+						}, 5, nil
 					},
 				},
 			},
@@ -249,6 +285,12 @@ func TestCheckService_FindChecks(t *testing.T) {
 							OrgID: 10,
 						},
 					},
+					&check.Rate{
+						Base: check.Base{
+							ID:    4,
+							OrgID: 10,
+						},
+					},
 				},
 			},
 		},
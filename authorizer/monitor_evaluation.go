@@ -0,0 +1,39 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.MonitorEvaluationService = (*MonitorEvaluationService)(nil)
+
+// MonitorEvaluationService wraps a influxdb.MonitorEvaluationService and authorizes actions
+// against it appropriately.
+type MonitorEvaluationService struct {
+	s influxdb.MonitorEvaluationService
+}
+
+// NewMonitorEvaluationService constructs an instance of an authorizing monitor evaluation service.
+func NewMonitorEvaluationService(s influxdb.MonitorEvaluationService) *MonitorEvaluationService {
+	return &MonitorEvaluationService{
+		s: s,
+	}
+}
+
+// EvaluateMonitor checks to see if the authorizer on context has read access to the checks, or
+// to the notification rules, of the organization. The buckets the script reads are authorized by the query itself.
+func (s *MonitorEvaluationService) EvaluateMonitor(ctx context.Context, req influxdb.MonitorEvaluationRequest) (*influxdb.MonitorEvaluation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.ReadAction, req.ResourceType, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := IsAllowed(ctx, *p); err != nil {
+		return nil, err
+	}
+	return s.s.EvaluateMonitor(ctx, req)
+}
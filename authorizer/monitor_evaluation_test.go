@@ -0,0 +1,80 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestMonitorEvaluationService(t *testing.T) {
+	es := mock.NewMonitorEvaluationService()
+	es.EvaluateMonitorFn = func(ctx context.Context, req influxdb.MonitorEvaluationRequest) (*influxdb.MonitorEvaluation, error) {
+		return &influxdb.MonitorEvaluation{}, nil
+	}
+	s := authorizer.NewMonitorEvaluationService(es)
+
+	tests := []struct {
+		name         string
+		resourceType influxdb.ResourceType
+		permissions  []influxdb.Permission
+		err          error
+	}{
+		{
+			name:         "authorized to read checks",
+			resourceType: influxdb.ChecksResourceType,
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.ChecksResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+		},
+		{
+			name:         "unauthorized to read checks",
+			resourceType: influxdb.ChecksResourceType,
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.ChecksResourceType, OrgID: influxdbtesting.IDPtr(11)},
+			}},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/checks is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name:         "authorized to read notification rules",
+			resourceType: influxdb.NotificationRuleResourceType,
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.NotificationRuleResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+		},
+		{
+			name:         "unauthorized to read notification rules with checks",
+			resourceType: influxdb.NotificationRuleResourceType,
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.ChecksResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/notificationRules is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			_, err := s.EvaluateMonitor(ctx, influxdb.MonitorEvaluationRequest{
+				Flux:           "from(bucket: \"b\")",
+				OrganizationID: 10,
+				ResourceType:   tt.resourceType,
+			})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+// RoleService wraps a influxdb.RoleService and authorizes actions
+// against it appropriately.
+type RoleService struct {
+	s influxdb.RoleService
+}
+
+// NewRoleService constructs an instance of an authorizing role service.
+func NewRoleService(s influxdb.RoleService) *RoleService {
+	return &RoleService{
+		s: s,
+	}
+}
+
+func authorizeRole(ctx context.Context, a influxdb.Action, orgID, id influxdb.ID) error {
+	p, err := influxdb.NewPermissionAtID(id, a, influxdb.RolesResourceType, orgID)
+	if err != nil {
+		return err
+	}
+	return IsAllowed(ctx, *p)
+}
+
+// FindRoleByID checks to see if the authorizer on context has read access to the id provided.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	r, err := s.s.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeRole(ctx, influxdb.ReadAction, r.OrgID, id); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// FindRoles retrieves all roles that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	rs, _, err := s.s.FindRoles(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	roles := rs[:0]
+	for _, r := range rs {
+		err := authorizeRole(ctx, influxdb.ReadAction, r.OrgID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		roles = append(roles, r)
+	}
+	return roles, len(roles), nil
+}
+
+// CreateRole checks to see if the authorizer on context has write access to the roles of the organization,
+// and holds the permissions of the role.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.RolesResourceType, r.OrgID)
+	if err != nil {
+		return err
+	}
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+	if err := VerifyPermissions(ctx, r.Permissions); err != nil {
+		return err
+	}
+	return s.s.CreateRole(ctx, r)
+}
+
+// UpdateRole checks to see if the authorizer on context has write access to the role provided,
+// and holds the permissions it is updated with.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	r, err := s.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeRole(ctx, influxdb.WriteAction, r.OrgID, id); err != nil {
+		return nil, err
+	}
+	if upd.Permissions != nil {
+		if err := VerifyPermissions(ctx, *upd.Permissions); err != nil {
+			return nil, err
+		}
+	}
+	return s.s.UpdateRole(ctx, id, upd)
+}
+
+// DeleteRole checks to see if the authorizer on context has write access to the role provided.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	r, err := s.FindRoleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeRole(ctx, influxdb.WriteAction, r.OrgID, id); err != nil {
+		return err
+	}
+	return s.s.DeleteRole(ctx, id)
+}
+
+// FindRoleAssignments retrieves all role assignments that match the provided filter and then filters the list down
+// to the assignments of the roles that are authorized.
+func (s *RoleService) FindRoleAssignments(ctx context.Context, filter influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+	as, _, err := s.s.FindRoleAssignments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	assignments := as[:0]
+	for _, a := range as {
+		err := authorizeRole(ctx, influxdb.ReadAction, a.OrgID, a.RoleID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, len(assignments), nil
+}
+
+// AssignRole checks to see if the authorizer on context has write access to the role provided,
+// and holds the permissions the role grants.
+func (s *RoleService) AssignRole(ctx context.Context, a *influxdb.RoleAssignment) error {
+	r, err := s.FindRoleByID(ctx, a.RoleID)
+	if err != nil {
+		return err
+	}
+	if err := authorizeRole(ctx, influxdb.WriteAction, r.OrgID, r.ID); err != nil {
+		return err
+	}
+	if err := VerifyPermissions(ctx, r.Permissions); err != nil {
+		return err
+	}
+	return s.s.AssignRole(ctx, a)
+}
+
+// UnassignRole checks to see if the authorizer on context has write access to the role provided.
+func (s *RoleService) UnassignRole(ctx context.Context, roleID, userID influxdb.ID) error {
+	r, err := s.FindRoleByID(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if err := authorizeRole(ctx, influxdb.WriteAction, r.OrgID, roleID); err != nil {
+		return err
+	}
+	return s.s.UnassignRole(ctx, roleID, userID)
+}
@@ -0,0 +1,160 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+var dashboardsWrite = influxdb.Permission{
+	Action:   influxdb.WriteAction,
+	Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+}
+
+func newMockRoleService() *mock.RoleService {
+	rs := mock.NewRoleService()
+	rs.FindRoleByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+		return &influxdb.Role{ID: id, OrgID: 10, Permissions: []influxdb.Permission{dashboardsWrite}}, nil
+	}
+	rs.FindRolesFn = func(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+		return []*influxdb.Role{
+			{ID: 1, OrgID: 10},
+			{ID: 2, OrgID: 10},
+			{ID: 3, OrgID: 11},
+		}, 3, nil
+	}
+	rs.UpdateRoleFn = func(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+		return &influxdb.Role{ID: id, OrgID: 10}, nil
+	}
+	return rs
+}
+
+func TestRoleService_FindRoles(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		want        []influxdb.ID
+	}{
+		{
+			name: "authorized to read all roles",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.RolesResourceType},
+			}},
+			want: []influxdb.ID{1, 2, 3},
+		},
+		{
+			name: "authorized to read the roles of an organization",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.RolesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+			want: []influxdb.ID{1, 2},
+		},
+		{
+			name: "authorized to read a single role",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.RolesResourceType, ID: influxdbtesting.IDPtr(2)},
+			}},
+			want: []influxdb.ID{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRoleService(newMockRoleService())
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			rs, n, err := s.FindRoles(ctx, influxdb.RoleFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []influxdb.ID
+			for _, r := range rs {
+				got = append(got, r.ID)
+			}
+			if !cmp.Equal(tt.want, got) || n != len(tt.want) {
+				t.Errorf("unexpected roles -want/+got:\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestRoleService_Write(t *testing.T) {
+	rolesRead := influxdb.Permission{
+		Action:   influxdb.ReadAction,
+		Resource: influxdb.Resource{Type: influxdb.RolesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+	}
+	rolesWrite := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.RolesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+		writeErr    error
+		grantErr    error
+	}{
+		{
+			name:        "authorized to write the roles of the organization and holding their permissions",
+			permissions: []influxdb.Permission{rolesRead, rolesWrite, dashboardsWrite},
+		},
+		{
+			name:        "unauthorized to grant the permissions of the role",
+			permissions: []influxdb.Permission{rolesRead, rolesWrite},
+			err: &influxdb.Error{
+				Msg:  "permission write:orgs/000000000000000a/dashboards is not allowed",
+				Code: influxdb.EForbidden,
+			},
+			grantErr: &influxdb.Error{
+				Msg:  "permission write:orgs/000000000000000a/dashboards is not allowed",
+				Code: influxdb.EForbidden,
+			},
+		},
+		{
+			name:        "unauthorized to write the roles of the organization",
+			permissions: []influxdb.Permission{rolesRead, dashboardsWrite},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/roles is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+			writeErr: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/roles/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+			grantErr: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/roles/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewRoleService(newMockRoleService())
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			err := s.CreateRole(ctx, &influxdb.Role{OrgID: 10, Permissions: []influxdb.Permission{dashboardsWrite}})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+
+			_, err = s.UpdateRole(ctx, 1, influxdb.RoleUpdate{})
+			influxdbtesting.ErrorsEqual(t, err, tt.writeErr)
+
+			err = s.AssignRole(ctx, &influxdb.RoleAssignment{RoleID: 1, UserID: 2})
+			influxdbtesting.ErrorsEqual(t, err, tt.grantErr)
+
+			err = s.UnassignRole(ctx, 1, 2)
+			influxdbtesting.ErrorsEqual(t, err, tt.writeErr)
+
+			err = s.DeleteRole(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.writeErr)
+		})
+	}
+}
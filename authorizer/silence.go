@@ -0,0 +1,100 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// SilenceService wraps a influxdb.SilenceService and authorizes actions
+// against it appropriately.
+type SilenceService struct {
+	s influxdb.SilenceService
+}
+
+// NewSilenceService constructs an instance of an authorizing silence service.
+func NewSilenceService(s influxdb.SilenceService) *SilenceService {
+	return &SilenceService{
+		s: s,
+	}
+}
+
+func authorizeSilence(ctx context.Context, a influxdb.Action, orgID, id influxdb.ID) error {
+	p, err := influxdb.NewPermissionAtID(id, a, influxdb.SilencesResourceType, orgID)
+	if err != nil {
+		return err
+	}
+	return IsAllowed(ctx, *p)
+}
+
+// FindSilenceByID checks to see if the authorizer on context has read access to the id provided.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	sil, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeSilence(ctx, influxdb.ReadAction, sil.OrgID, id); err != nil {
+		return nil, err
+	}
+	return sil, nil
+}
+
+// FindSilences retrieves all silences that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+	ss, _, err := s.s.FindSilences(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sils := ss[:0]
+	for _, sil := range ss {
+		err := authorizeSilence(ctx, influxdb.ReadAction, sil.OrgID, sil.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		sils = append(sils, sil)
+	}
+	return sils, len(sils), nil
+}
+
+// CreateSilence checks to see if the authorizer on context has write access to the silences of the organization.
+func (s *SilenceService) CreateSilence(ctx context.Context, sil *influxdb.Silence) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.SilencesResourceType, sil.OrgID)
+	if err != nil {
+		return err
+	}
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+	return s.s.CreateSilence(ctx, sil)
+}
+
+// UpdateSilence checks to see if the authorizer on context has write access to the silence provided.
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	sil, err := s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeSilence(ctx, influxdb.WriteAction, sil.OrgID, id); err != nil {
+		return nil, err
+	}
+	return s.s.UpdateSilence(ctx, id, upd)
+}
+
+// DeleteSilence checks to see if the authorizer on context has write access to the silence provided.
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	sil, err := s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeSilence(ctx, influxdb.WriteAction, sil.OrgID, id); err != nil {
+		return err
+	}
+	return s.s.DeleteSilence(ctx, id)
+}
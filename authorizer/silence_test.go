@@ -0,0 +1,132 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newMockSilenceService() *mock.SilenceService {
+	ss := mock.NewSilenceService()
+	ss.FindSilenceByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+		return &influxdb.Silence{ID: id, OrgID: 10}, nil
+	}
+	ss.FindSilencesFn = func(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+		return []*influxdb.Silence{
+			{ID: 1, OrgID: 10},
+			{ID: 2, OrgID: 10},
+			{ID: 3, OrgID: 11},
+		}, 3, nil
+	}
+	ss.UpdateSilenceFn = func(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+		return &influxdb.Silence{ID: id, OrgID: 10}, nil
+	}
+	return ss
+}
+
+func TestSilenceService_FindSilences(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		want        []influxdb.ID
+	}{
+		{
+			name: "authorized to read all silences",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.SilencesResourceType},
+			}},
+			want: []influxdb.ID{1, 2, 3},
+		},
+		{
+			name: "authorized to read the silences of an organization",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.SilencesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+			want: []influxdb.ID{1, 2},
+		},
+		{
+			name: "authorized to read a single silence",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.SilencesResourceType, ID: influxdbtesting.IDPtr(2)},
+			}},
+			want: []influxdb.ID{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewSilenceService(newMockSilenceService())
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			ss, n, err := s.FindSilences(ctx, influxdb.SilenceFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []influxdb.ID
+			for _, sil := range ss {
+				got = append(got, sil.ID)
+			}
+			if !cmp.Equal(tt.want, got) || n != len(tt.want) {
+				t.Errorf("unexpected silences -want/+got:\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestSilenceService_Write(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+		readErr     error
+	}{
+		{
+			name: "authorized to write the silences of the organization",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.SilencesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}, {
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.SilencesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+		},
+		{
+			name: "unauthorized to write the silences of the organization",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.SilencesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/silences is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+			readErr: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/silences/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewSilenceService(newMockSilenceService())
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			err := s.CreateSilence(ctx, &influxdb.Silence{OrgID: 10})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+
+			_, err = s.UpdateSilence(ctx, 1, influxdb.SilenceUpdate{})
+			influxdbtesting.ErrorsEqual(t, err, tt.readErr)
+
+			err = s.DeleteSilence(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.readErr)
+		})
+	}
+}
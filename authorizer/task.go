@@ -127,6 +127,10 @@ func (ts *taskServiceValidator) CreateTask(ctx context.Context, t influxdb.TaskC
 		return nil, err
 	}
 
+	if err := ts.validateNotification(ctx, t.Notification, t.OrganizationID, loggerFields...); err != nil {
+		return nil, err
+	}
+
 	return ts.TaskService.CreateTask(ctx, t)
 }
 
@@ -150,6 +154,10 @@ func (ts *taskServiceValidator) UpdateTask(ctx context.Context, id influxdb.ID,
 		return nil, err
 	}
 
+	if err := ts.validateNotification(ctx, upd.Notification, task.OrganizationID, loggerFields...); err != nil {
+		return nil, err
+	}
+
 	return ts.TaskService.UpdateTask(ctx, id, upd)
 }
 
@@ -338,3 +346,16 @@ func (ts *taskServiceValidator) validatePermission(ctx context.Context, perm inf
 
 	return nil
 }
+
+// validateNotification checks that the notification endpoint a task notifies can be read.
+func (ts *taskServiceValidator) validateNotification(ctx context.Context, n *influxdb.TaskNotification, orgID influxdb.ID, loggerFields ...zap.Field) error {
+	if n == nil || !n.EndpointID.Valid() {
+		return nil
+	}
+
+	p, err := influxdb.NewPermissionAtID(n.EndpointID, influxdb.ReadAction, influxdb.NotificationEndpointResourceType, orgID)
+	if err != nil {
+		return err
+	}
+	return ts.validatePermission(ctx, *p, loggerFields...)
+}
@@ -0,0 +1,84 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.TaskBackfillService = (*TaskBackfillService)(nil)
+
+// TaskBackfillService wraps a influxdb.TaskBackfillService and authorizes actions
+// against it appropriately.
+type TaskBackfillService struct {
+	s  influxdb.TaskBackfillService
+	ts influxdb.TaskService
+}
+
+// NewTaskBackfillService constructs an instance of an authorizing backfill service.
+// The tasks of backfills are looked up with ts to identify their organization.
+func NewTaskBackfillService(s influxdb.TaskBackfillService, ts influxdb.TaskService) *TaskBackfillService {
+	return &TaskBackfillService{
+		s:  s,
+		ts: ts,
+	}
+}
+
+// CreateBackfill checks to see if the authorizer on context has write access to the task.
+func (s *TaskBackfillService) CreateBackfill(ctx context.Context, b influxdb.BackfillCreate) (*influxdb.Backfill, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.authorizeTask(ctx, influxdb.WriteAction, b.TaskID); err != nil {
+		return nil, err
+	}
+	return s.s.CreateBackfill(ctx, b)
+}
+
+// FindBackfillByID checks to see if the authorizer on context has read access to the task.
+func (s *TaskBackfillService) FindBackfillByID(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.authorizeTask(ctx, influxdb.ReadAction, taskID); err != nil {
+		return nil, err
+	}
+	return s.s.FindBackfillByID(ctx, taskID, id)
+}
+
+// FindBackfills checks to see if the authorizer on context has read access to the task.
+func (s *TaskBackfillService) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.authorizeTask(ctx, influxdb.ReadAction, taskID); err != nil {
+		return nil, err
+	}
+	return s.s.FindBackfills(ctx, taskID)
+}
+
+// CancelBackfill checks to see if the authorizer on context has write access to the task.
+func (s *TaskBackfillService) CancelBackfill(ctx context.Context, taskID, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.authorizeTask(ctx, influxdb.WriteAction, taskID); err != nil {
+		return err
+	}
+	return s.s.CancelBackfill(ctx, taskID, id)
+}
+
+func (s *TaskBackfillService) authorizeTask(ctx context.Context, a influxdb.Action, taskID influxdb.ID) error {
+	// Unauthenticated task lookup, to identify the task's organization.
+	task, err := s.ts.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	p, err := influxdb.NewPermissionAtID(taskID, a, influxdb.TasksResourceType, task.OrganizationID)
+	if err != nil {
+		return err
+	}
+	return IsAllowed(ctx, *p)
+}
@@ -0,0 +1,84 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestTaskBackfillService(t *testing.T) {
+	ts := mock.NewTaskService()
+	ts.FindTaskByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		return &influxdb.Task{ID: id, OrganizationID: 10}, nil
+	}
+	bs := mock.NewTaskBackfillService()
+	bs.CreateBackfillFn = func(ctx context.Context, b influxdb.BackfillCreate) (*influxdb.Backfill, error) {
+		return &influxdb.Backfill{ID: 100, TaskID: b.TaskID}, nil
+	}
+	bs.FindBackfillByIDFn = func(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+		return &influxdb.Backfill{ID: id, TaskID: taskID}, nil
+	}
+	s := authorizer.NewTaskBackfillService(bs, ts)
+
+	taskPermission := func(a influxdb.Action, id influxdb.ID) influxdb.Permission {
+		return influxdb.Permission{
+			Action: a,
+			Resource: influxdb.Resource{
+				Type: influxdb.TasksResourceType,
+				ID:   influxdbtesting.IDPtr(id),
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		readErr     error
+		writeErr    error
+	}{
+		{
+			name:        "authorized to read and write task",
+			permissions: []influxdb.Permission{taskPermission(influxdb.ReadAction, 1), taskPermission(influxdb.WriteAction, 1)},
+		},
+		{
+			name:        "authorized to read task",
+			permissions: []influxdb.Permission{taskPermission(influxdb.ReadAction, 1)},
+			writeErr: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/tasks/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name:        "unauthorized to access task",
+			permissions: []influxdb.Permission{taskPermission(influxdb.ReadAction, 2), taskPermission(influxdb.WriteAction, 2)},
+			readErr: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/tasks/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+			writeErr: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/tasks/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			_, err := s.FindBackfillByID(ctx, 1, 100)
+			influxdbtesting.ErrorsEqual(t, err, tt.readErr)
+			_, err = s.FindBackfills(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.readErr)
+
+			_, err = s.CreateBackfill(ctx, influxdb.BackfillCreate{TaskID: 1})
+			influxdbtesting.ErrorsEqual(t, err, tt.writeErr)
+			err = s.CancelBackfill(ctx, 1, 100)
+			influxdbtesting.ErrorsEqual(t, err, tt.writeErr)
+		})
+	}
+}
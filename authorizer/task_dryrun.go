@@ -0,0 +1,39 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.TaskDryRunService = (*TaskDryRunService)(nil)
+
+// TaskDryRunService wraps a influxdb.TaskDryRunService and authorizes actions
+// against it appropriately.
+type TaskDryRunService struct {
+	s influxdb.TaskDryRunService
+}
+
+// NewTaskDryRunService constructs an instance of an authorizing task dry run service.
+func NewTaskDryRunService(s influxdb.TaskDryRunService) *TaskDryRunService {
+	return &TaskDryRunService{
+		s: s,
+	}
+}
+
+// DryRunTask checks to see if the authorizer on context has write access to the tasks of the organization.
+// The buckets the script reads are authorized by the query itself.
+func (s *TaskDryRunService) DryRunTask(ctx context.Context, req influxdb.TaskDryRunRequest) (*influxdb.TaskDryRun, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.TasksResourceType, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := IsAllowed(ctx, *p); err != nil {
+		return nil, err
+	}
+	return s.s.DryRunTask(ctx, req)
+}
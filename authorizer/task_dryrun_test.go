@@ -0,0 +1,53 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestTaskDryRunService(t *testing.T) {
+	ds := mock.NewTaskDryRunService()
+	ds.DryRunTaskFn = func(ctx context.Context, req influxdb.TaskDryRunRequest) (*influxdb.TaskDryRun, error) {
+		return &influxdb.TaskDryRun{}, nil
+	}
+	s := authorizer.NewTaskDryRunService(ds)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name: "authorized to write tasks",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+		},
+		{
+			name: "unauthorized to write tasks",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: influxdbtesting.IDPtr(10)},
+			}},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/tasks is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			_, err := s.DryRunTask(ctx, influxdb.TaskDryRunRequest{Flux: "from(bucket: \"b\")", OrganizationID: 10})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -229,6 +229,39 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 				return err
 			},
 		},
+		{
+			name: "create with notification failure",
+			auth: &influxdb.Authorization{Permissions: orgWriteAllTaskPermissions},
+			check: func(ctx context.Context, svc influxdb.TaskService) error {
+				_, err := svc.CreateTask(ctx, influxdb.TaskCreate{
+					OrganizationID: r.Org.ID,
+					OwnerID:        r.Auth.GetUserID(),
+					Flux: `option task = {
+ name: "my_task",
+ every: 1s,
+}
+from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
+					Notification: &influxdb.TaskNotification{EndpointID: 1, Policy: influxdb.TaskNotifyEvery},
+				})
+				if err == nil {
+					return errors.New("failed to error without permission to read the notification endpoint")
+				}
+				return nil
+			},
+		},
+		{
+			name: "update with notification failure",
+			auth: &influxdb.Authorization{Permissions: orgWriteTaskPermissions},
+			check: func(ctx context.Context, svc influxdb.TaskService) error {
+				_, err := svc.UpdateTask(ctx, taskID, influxdb.TaskUpdate{
+					Notification: &influxdb.TaskNotification{EndpointID: 1, Policy: influxdb.TaskNotifyEvery},
+				})
+				if err == nil {
+					return errors.New("failed to error without permission to read the notification endpoint")
+				}
+				return nil
+			},
+		},
 		{
 			name: "FindTaskByID missing auth",
 			auth: &influxdb.Authorization{Permissions: []influxdb.Permission{}},
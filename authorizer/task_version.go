@@ -0,0 +1,62 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.TaskVersionService = (*TaskVersionService)(nil)
+
+// TaskVersionService wraps a influxdb.TaskVersionService and authorizes actions
+// against it appropriately.
+type TaskVersionService struct {
+	s  influxdb.TaskVersionService
+	ts influxdb.TaskService
+}
+
+// NewTaskVersionService constructs an instance of an authorizing task version service.
+// The tasks of versions are looked up with ts to identify their organization.
+func NewTaskVersionService(s influxdb.TaskVersionService, ts influxdb.TaskService) *TaskVersionService {
+	return &TaskVersionService{
+		s:  s,
+		ts: ts,
+	}
+}
+
+// FindTaskVersions checks to see if the authorizer on context has read access to the task.
+func (s *TaskVersionService) FindTaskVersions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.authorizeTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.s.FindTaskVersions(ctx, taskID)
+}
+
+// FindTaskVersion checks to see if the authorizer on context has read access to the task.
+func (s *TaskVersionService) FindTaskVersion(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := s.authorizeTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.s.FindTaskVersion(ctx, taskID, version)
+}
+
+func (s *TaskVersionService) authorizeTask(ctx context.Context, taskID influxdb.ID) error {
+	// Unauthenticated task lookup, to identify the task's organization.
+	task, err := s.ts.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	p, err := influxdb.NewPermissionAtID(taskID, influxdb.ReadAction, influxdb.TasksResourceType, task.OrganizationID)
+	if err != nil {
+		return err
+	}
+	return IsAllowed(ctx, *p)
+}
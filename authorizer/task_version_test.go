@@ -0,0 +1,59 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestTaskVersionService(t *testing.T) {
+	ts := mock.NewTaskService()
+	ts.FindTaskByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		return &influxdb.Task{ID: id, OrganizationID: 10}, nil
+	}
+	vs := mock.NewTaskVersionService()
+	vs.FindTaskVersionFn = func(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+		return &influxdb.TaskVersion{TaskID: taskID, Version: version}, nil
+	}
+	s := authorizer.NewTaskVersionService(vs, ts)
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name: "authorized to read task",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.TasksResourceType, ID: influxdbtesting.IDPtr(1)},
+			}},
+		},
+		{
+			name: "unauthorized to read task",
+			permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.TasksResourceType, ID: influxdbtesting.IDPtr(2)},
+			}},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/tasks/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			_, err := s.FindTaskVersions(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+			_, err = s.FindTaskVersion(ctx, 1, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
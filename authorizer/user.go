@@ -130,6 +130,17 @@ func (s *UserService) UpdateUser(ctx context.Context, id influxdb.ID, upd influx
 		return nil, err
 	}
 
+	// whoever holds the identity a user is linked to can log in as the user.
+	if upd.OAuthID != nil {
+		p, err := influxdb.NewGlobalPermission(influxdb.WriteAction, influxdb.UsersResourceType)
+		if err != nil {
+			return nil, err
+		}
+		if err := IsAllowed(ctx, *p); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.s.UpdateUser(ctx, id, upd)
 }
 
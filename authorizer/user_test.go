@@ -396,6 +396,37 @@ func TestUserService_UpdateUser(t *testing.T) {
 	}
 }
 
+func TestUserService_UpdateUser_OAuthID(t *testing.T) {
+	s := authorizer.NewUserService(&mock.UserService{
+		UpdateUserFn: func(ctx context.Context, id influxdb.ID, upd influxdb.UserUpdate) (*influxdb.User, error) {
+			return &influxdb.User{ID: id, OAuthID: *upd.OAuthID}, nil
+		},
+	})
+	subject := "1234"
+	upd := influxdb.UserUpdate{OAuthID: &subject}
+
+	// users are allowed to update themselves, but not to link themselves to an identity.
+	self := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.UsersResourceType, ID: influxdbtesting.IDPtr(1)},
+	}
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{self}})
+	_, err := s.UpdateUser(ctx, 1, upd)
+	influxdbtesting.ErrorsEqual(t, err, &influxdb.Error{
+		Msg:  "write:users is unauthorized",
+		Code: influxdb.EUnauthorized,
+	})
+
+	all := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.UsersResourceType},
+	}
+	ctx = influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{all}})
+	if _, err := s.UpdateUser(ctx, 1, upd); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestUserService_DeleteUser(t *testing.T) {
 	type fields struct {
 		UserService influxdb.UserService
@@ -129,6 +129,12 @@ const (
 	NotificationEndpointResourceType = ResourceType("notificationEndpoints") // 15
 	// ChecksResourceType gives permission to one or more Checks.
 	ChecksResourceType = ResourceType("checks") // 16
+	// SilencesResourceType gives permission to one or more silences.
+	SilencesResourceType = ResourceType("silences") // 17
+	// AlertsResourceType gives permission to one or more alerts.
+	AlertsResourceType = ResourceType("alerts") // 18
+	// RolesResourceType gives permission to one or more roles.
+	RolesResourceType = ResourceType("roles") // 19
 )
 
 // AllResourceTypes is the list of all known resource types.
@@ -150,6 +156,9 @@ var AllResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	SilencesResourceType,             // 17
+	AlertsResourceType,               // 18
+	RolesResourceType,                // 19
 	// NOTE: when modifying this list, please update the swagger for components.schemas.Permission resource enum.
 }
 
@@ -167,6 +176,9 @@ var OrgResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	SilencesResourceType,             // 17
+	AlertsResourceType,               // 18
+	RolesResourceType,                // 19
 }
 
 // Valid checks if the resource type is a member of the ResourceType enum.
@@ -194,6 +206,9 @@ func (t ResourceType) Valid() (err error) {
 	case NotificationRuleResourceType: // 14
 	case NotificationEndpointResourceType: // 15
 	case ChecksResourceType: // 16
+	case SilencesResourceType: // 17
+	case AlertsResourceType: // 18
+	case RolesResourceType: // 19
 	default:
 		err = ErrInvalidResourceType
 	}
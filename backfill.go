@@ -0,0 +1,101 @@
+package influxdb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultBackfillConcurrency is the number of runs of a backfill executed at once
+	// when no concurrency is requested.
+	DefaultBackfillConcurrency = 1
+
+	// MaxBackfillConcurrency is the maximum number of runs of a backfill executed at once.
+	MaxBackfillConcurrency = 32
+
+	// MaxBackfillRuns is the maximum number of schedule points a single backfill may cover.
+	MaxBackfillRuns = 100000
+)
+
+// Possible statuses of a backfill.
+const (
+	BackfillStatusRunning  = "running"
+	BackfillStatusSuccess  = "success"
+	BackfillStatusFailed   = "failed"
+	BackfillStatusCanceled = "canceled"
+)
+
+var (
+	// ErrBackfillNotFound is returned when searching for a backfill that doesn't exist.
+	ErrBackfillNotFound = &Error{
+		Code: ENotFound,
+		Msg:  "backfill not found",
+	}
+
+	// ErrBackfillTooLarge is returned when a backfill covers more schedule points than allowed.
+	ErrBackfillTooLarge = &Error{
+		Code: EInvalid,
+		Msg:  "backfill range covers too many runs",
+	}
+)
+
+// Backfill executes the runs of a task for every schedule point of a historical range.
+// The runs are executed independently of the live schedule of the task.
+type Backfill struct {
+	ID          ID        `json:"id"`
+	TaskID      ID        `json:"taskID"`
+	Start       time.Time `json:"start"`
+	Stop        time.Time `json:"stop"`
+	Concurrency int       `json:"concurrency"`
+	Status      string    `json:"status"`
+	Total       int       `json:"total"`     // Total is the number of schedule points in the range.
+	Succeeded   int       `json:"succeeded"` // Succeeded is the number of runs that completed successfully.
+	Failed      int       `json:"failed"`    // Failed is the number of runs that failed or were canceled.
+	CreatedAt   time.Time `json:"createdAt"`
+	FinishedAt  time.Time `json:"finishedAt,omitempty"`
+}
+
+// Done reports whether the backfill no longer executes runs.
+func (b *Backfill) Done() bool {
+	return b.Status != BackfillStatusRunning
+}
+
+// BackfillCreate is the set of values to create a backfill.
+// The schedule points of the task within [Start, Stop) are executed.
+type BackfillCreate struct {
+	TaskID      ID        `json:"-"`
+	Start       time.Time `json:"start"`
+	Stop        time.Time `json:"stop"`
+	Concurrency int       `json:"concurrency,omitempty"`
+}
+
+// Validate returns an error if the backfill cannot be created.
+func (b BackfillCreate) Validate() error {
+	switch {
+	case !b.TaskID.Valid():
+		return ErrInvalidTaskID
+	case b.Start.IsZero() || b.Stop.IsZero():
+		return errors.New("missing start or stop")
+	case !b.Start.Before(b.Stop):
+		return errors.New("start must be before stop")
+	case b.Concurrency < 0 || b.Concurrency > MaxBackfillConcurrency:
+		return errors.New("concurrency must be between 1 and 32")
+	}
+	return nil
+}
+
+// TaskBackfillService manages the backfills of tasks.
+type TaskBackfillService interface {
+	// CreateBackfill starts executing the runs of a task over a historical range.
+	CreateBackfill(ctx context.Context, b BackfillCreate) (*Backfill, error)
+
+	// FindBackfillByID returns the progress of a single backfill.
+	FindBackfillByID(ctx context.Context, taskID, id ID) (*Backfill, error)
+
+	// FindBackfills returns the backfills of a task.
+	FindBackfills(ctx context.Context, taskID ID) ([]*Backfill, error)
+
+	// CancelBackfill stops a backfill, canceling the runs it is executing.
+	CancelBackfill(ctx context.Context, taskID, id ID) error
+}
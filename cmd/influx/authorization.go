@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
@@ -23,14 +25,17 @@ func cmdAuth() *cobra.Command {
 		authDeleteCmd(),
 		authFindCmd(),
 		authInactiveCmd(),
+		authRotateCmd(),
 	)
 
 	return cmd
 }
 
 var authCreateFlags struct {
-	user string
-	org  organization
+	user      string
+	org       organization
+	expiresIn time.Duration
+	roles     []string
 
 	writeUserPermission bool
 	readUserPermission  bool
@@ -61,6 +66,15 @@ var authCreateFlags struct {
 
 	writeNotificationEndpointPermission bool
 	readNotificationEndpointPermission  bool
+
+	writeSilencePermission bool
+	readSilencePermission  bool
+
+	writeAlertPermission bool
+	readAlertPermission  bool
+
+	writeRolePermission bool
+	readRolePermission  bool
 }
 
 func authCreateCmd() *cobra.Command {
@@ -72,6 +86,8 @@ func authCreateCmd() *cobra.Command {
 	authCreateFlags.org.register(cmd, false)
 
 	cmd.Flags().StringVarP(&authCreateFlags.user, "user", "u", "", "The user name")
+	cmd.Flags().DurationVarP(&authCreateFlags.expiresIn, "expires-in", "", 0, "Duration the token is valid for, it never expires if unset")
+	cmd.Flags().StringArrayVarP(&authCreateFlags.roles, "role", "", []string{}, "Grants the permissions of the role with this id, can be repeated")
 
 	cmd.Flags().BoolVarP(&authCreateFlags.writeUserPermission, "write-user", "", false, "Grants the permission to perform mutative actions against organization users")
 	cmd.Flags().BoolVarP(&authCreateFlags.readUserPermission, "read-user", "", false, "Grants the permission to perform read actions against organization users")
@@ -103,6 +119,15 @@ func authCreateCmd() *cobra.Command {
 	cmd.Flags().BoolVarP(&authCreateFlags.writeCheckPermission, "write-checks", "", false, "Grants the permission to create checks")
 	cmd.Flags().BoolVarP(&authCreateFlags.readCheckPermission, "read-checks", "", false, "Grants the permission to read checks")
 
+	cmd.Flags().BoolVarP(&authCreateFlags.writeSilencePermission, "write-silences", "", false, "Grants the permission to create silences")
+	cmd.Flags().BoolVarP(&authCreateFlags.readSilencePermission, "read-silences", "", false, "Grants the permission to read silences")
+
+	cmd.Flags().BoolVarP(&authCreateFlags.writeAlertPermission, "write-alerts", "", false, "Grants the permission to acknowledge and resolve alerts")
+	cmd.Flags().BoolVarP(&authCreateFlags.readAlertPermission, "read-alerts", "", false, "Grants the permission to read alerts")
+
+	cmd.Flags().BoolVarP(&authCreateFlags.writeRolePermission, "write-roles", "", false, "Grants the permission to create and assign roles")
+	cmd.Flags().BoolVarP(&authCreateFlags.readRolePermission, "read-roles", "", false, "Grants the permission to read roles")
+
 	return cmd
 }
 
@@ -180,6 +205,21 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 			writePerm:    authCreateFlags.writeOrganizationsPermission,
 			ResourceType: platform.OrgsResourceType,
 		},
+		{
+			readPerm:     authCreateFlags.readSilencePermission,
+			writePerm:    authCreateFlags.writeSilencePermission,
+			ResourceType: platform.SilencesResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readAlertPermission,
+			writePerm:    authCreateFlags.writeAlertPermission,
+			ResourceType: platform.AlertsResourceType,
+		},
+		{
+			readPerm:     authCreateFlags.readRolePermission,
+			writePerm:    authCreateFlags.writeRolePermission,
+			ResourceType: platform.RolesResourceType,
+		},
 		{
 			readPerm:     authCreateFlags.readTasksPermission,
 			writePerm:    authCreateFlags.writeTasksPermission,
@@ -216,10 +256,22 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(authCreateFlags.roles) > 0 {
+		rolePerms, err := findRolePermissions(orgID, authCreateFlags.roles)
+		if err != nil {
+			return err
+		}
+		permissions = append(permissions, rolePerms...)
+	}
+
 	authorization := &platform.Authorization{
 		Permissions: permissions,
 		OrgID:       orgID,
 	}
+	if authCreateFlags.expiresIn > 0 {
+		expiresAt := time.Now().Add(authCreateFlags.expiresIn)
+		authorization.ExpiresAt = &expiresAt
+	}
 
 	if userName := authCreateFlags.user; userName != "" {
 		userSvc, err := newUserService()
@@ -244,31 +296,68 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	writeAuthorization(authorization)
+
+	return nil
+}
+
+// findRolePermissions returns the permissions of the roles of the organization with the ids.
+func findRolePermissions(orgID platform.ID, ids []string) ([]platform.Permission, error) {
+	roleSvc, err := newRoleService()
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []platform.Permission
+	for _, s := range ids {
+		id, err := platform.IDFromString(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode role id %q: %v", s, err)
+		}
+		r, err := roleSvc.FindRoleByID(context.Background(), *id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find role with id %q: %v", s, err)
+		}
+		if r.OrgID != orgID {
+			return nil, fmt.Errorf("role %q is not a role of org id %q", s, orgID)
+		}
+		permissions = append(permissions, r.Permissions...)
+	}
+	return permissions, nil
+}
+
+// writeAuthorization writes a created authorization with its token.
+func writeAuthorization(a *platform.Authorization) {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
 		"Token",
 		"Status",
 		"UserID",
+		"ExpiresAt",
 		"Permissions",
 	)
 
 	ps := []string{}
-	for _, p := range authorization.Permissions {
+	for _, p := range a.Permissions {
 		ps = append(ps, p.String())
 	}
 
+	var expiresAt string
+	if a.ExpiresAt != nil {
+		expiresAt = a.ExpiresAt.Format(time.RFC3339)
+	}
+
 	w.Write(map[string]interface{}{
-		"ID":          authorization.ID.String(),
-		"Token":       authorization.Token,
-		"Status":      authorization.Status,
-		"UserID":      authorization.UserID.String(),
+		"ID":          a.ID.String(),
+		"Token":       a.Token,
+		"Status":      a.Status,
+		"UserID":      a.UserID.String(),
+		"ExpiresAt":   expiresAt,
 		"Permissions": ps,
 	})
 
 	w.Flush()
-
-	return nil
 }
 
 var authorizationFindFlags struct {
@@ -579,3 +668,59 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+var authorizationRotateFlags struct {
+	id          string
+	gracePeriod time.Duration
+}
+
+func authRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace the token of an authorization",
+		Long:  "Creates an authorization with a new token and the permissions of the authorization. The previous token remains valid for the grace period.",
+		RunE:  wrapCheckSetup(authorizationRotateF),
+	}
+
+	cmd.Flags().StringVarP(&authorizationRotateFlags.id, "id", "i", "", "The authorization ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().DurationVarP(&authorizationRotateFlags.gracePeriod, "grace-period", "", 0, "Duration the previous token remains valid for")
+
+	return cmd
+}
+
+func newAuthorizationRotator() (platform.AuthorizationRotator, error) {
+	if flags.local {
+		return newLocalKVService()
+	}
+
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.AuthorizationService{
+		Client: httpClient,
+	}, nil
+}
+
+func authorizationRotateF(cmd *cobra.Command, args []string) error {
+	s, err := newAuthorizationRotator()
+	if err != nil {
+		return err
+	}
+
+	id, err := platform.IDFromString(authorizationRotateFlags.id)
+	if err != nil {
+		return err
+	}
+
+	a, err := s.RotateAuthorization(context.Background(), *id, authorizationRotateFlags.gracePeriod)
+	if err != nil {
+		return err
+	}
+
+	writeAuthorization(a)
+
+	return nil
+}
@@ -132,8 +132,10 @@ func influxCmd(opts ...genericCLIOptFn) *cobra.Command {
 		cmdQuery(),
 		cmdTranspile(),
 		cmdREPL(),
+		cmdRole(runEWrapper),
 		cmdSecret(runEWrapper),
 		cmdSetup(),
+		cmdSilence(runEWrapper),
 		cmdTask(),
 		cmdUser(runEWrapper),
 		cmdWrite(),
@@ -1,17 +1,26 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/repl"
 	_ "github.com/influxdata/flux/stdlib"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/stdlib"
 	"github.com/spf13/cobra"
 )
 
 var queryFlags struct {
-	org organization
+	org     organization
+	explain bool
+	profile bool
 }
 
 func cmdQuery() *cobra.Command {
@@ -24,6 +33,8 @@ or execute a literal Flux query contained in a file by specifying the file prefi
 		RunE: wrapCheckSetup(fluxQueryF),
 	}
 	queryFlags.org.register(cmd, true)
+	cmd.Flags().BoolVar(&queryFlags.explain, "explain", false, "Print the query plans instead of executing the query")
+	cmd.Flags().BoolVar(&queryFlags.profile, "profile", false, "Execute the query and print the query plans with the output of every operator")
 
 	return cmd
 }
@@ -52,6 +63,10 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if queryFlags.explain || queryFlags.profile {
+		return fluxExplainF(q, orgID)
+	}
+
 	flux.FinalizeBuiltIns()
 
 	r, err := getFluxREPL(flags.host, flags.token, flags.skipVerify, orgID)
@@ -65,3 +80,81 @@ func fluxQueryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func fluxExplainF(q string, orgID platform.ID) error {
+	s := &http.FluxService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+	e, err := s.Explain(context.Background(), orgID, &http.QueryExplainRequest{
+		Query:   q,
+		Profile: queryFlags.profile,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to explain query: %v", err)
+	}
+	printExplanation(os.Stdout, e)
+	return nil
+}
+
+func printExplanation(w io.Writer, e *query.Explanation) {
+	operators := make(map[string]*query.OperatorProfile)
+	if e.Profile != nil {
+		for _, op := range e.Profile.Operators {
+			operators[op.Node] = op
+		}
+	}
+
+	fmt.Fprintln(w, "Logical Plan:")
+	for _, root := range e.Logical {
+		printPlanNode(w, root, "", "", nil)
+	}
+
+	fmt.Fprintln(w, "\nPhysical Plan:")
+	for _, root := range e.Physical {
+		printPlanNode(w, root, "", "", operators)
+	}
+
+	if len(e.Pushdowns) > 0 {
+		fmt.Fprintln(w, "\nPushed Down to Storage:")
+		for _, pd := range e.Pushdowns {
+			ops := make([]string, 0, len(pd.Operations))
+			for _, op := range pd.Operations {
+				ops = append(ops, op.ID)
+			}
+			fmt.Fprintf(w, "%s (%s): %s\n", pd.Node, pd.StorageOperation, strings.Join(ops, " |> "))
+		}
+	}
+
+	if p := e.Profile; p != nil {
+		fmt.Fprintln(w, "\nProfile:")
+		fmt.Fprintf(w, "total: %v, compile: %v, queue: %v, execute: %v\n",
+			p.TotalDuration, p.CompileDuration, p.QueueDuration, p.ExecuteDuration)
+		fmt.Fprintf(w, "max allocated: %d bytes, total allocated: %d bytes\n", p.MaxAllocated, p.TotalAllocated)
+	}
+}
+
+// printPlanNode prints the node and its predecessors as a tree.
+func printPlanNode(w io.Writer, n *query.PlanNode, prefix, childPrefix string, operators map[string]*query.OperatorProfile) {
+	line := prefix + n.ID
+	if n.StorageOperation != "" {
+		line += " [" + n.StorageOperation + "]"
+	}
+	if op, ok := operators[n.ID]; ok {
+		line += fmt.Sprintf(" (tables: %d, rows: %d, bytes: %d, duration: %v", op.Tables, op.Rows, op.Bytes, op.Duration)
+		if op.StorageOperation != "" {
+			line += fmt.Sprintf(", scanned values: %d, scanned bytes: %d", op.ScannedValues, op.ScannedBytes)
+		}
+		line += ")"
+	}
+	fmt.Fprintln(w, line)
+
+	for i, pred := range n.Predecessors {
+		if i == len(n.Predecessors)-1 {
+			printPlanNode(w, pred, childPrefix+"└── ", childPrefix+"    ", operators)
+		} else {
+			printPlanNode(w, pred, childPrefix+"├── ", childPrefix+"│   ", operators)
+		}
+	}
+}
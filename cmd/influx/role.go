@@ -0,0 +1,456 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/spf13/cobra"
+)
+
+type roleSVCsFn func() (influxdb.RoleService, influxdb.OrganizationService, error)
+
+func cmdRole(opts ...genericCLIOptFn) *cobra.Command {
+	return newCmdRoleBuilder(newRoleSVCs, opts...).cmd()
+}
+
+type cmdRoleBuilder struct {
+	genericCLIOpts
+
+	svcFn roleSVCsFn
+
+	id          string
+	org         organization
+	name        string
+	description string
+	permissions []string
+	userID      string
+}
+
+func newCmdRoleBuilder(svcsFn roleSVCsFn, opts ...genericCLIOptFn) *cmdRoleBuilder {
+	opt := genericCLIOpts{
+		in: os.Stdin,
+		w:  os.Stdout,
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	return &cmdRoleBuilder{
+		genericCLIOpts: opt,
+		svcFn:          svcsFn,
+	}
+}
+
+func (b *cmdRoleBuilder) cmd() *cobra.Command {
+	cmd := b.newCmd("role", nil)
+	cmd.Short = "Role management commands"
+	cmd.TraverseChildren = true
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdAssign(),
+		b.cmdCreate(),
+		b.cmdDelete(),
+		b.cmdFind(),
+		b.cmdMembers(),
+		b.cmdUnassign(),
+		b.cmdUpdate(),
+	)
+
+	return cmd
+}
+
+func (b *cmdRoleBuilder) registerRoleFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "The role name")
+	cmd.Flags().StringVarP(&b.description, "description", "d", "", "The role description")
+	cmd.Flags().StringArrayVarP(&b.permissions, "permission", "p", nil, "Grants the permission of the form action:resources or action:resources/id, such as write:dashboards, can be repeated")
+}
+
+func (b *cmdRoleBuilder) cmdCreate() *cobra.Command {
+	cmd := b.newCmd("create", b.cmdCreateRunEFn)
+	cmd.Short = "Create role"
+	b.registerRoleFlags(cmd)
+	cmd.MarkFlagRequired("name")
+	b.org.register(cmd, false)
+
+	return cmd
+}
+
+func (b *cmdRoleBuilder) cmdCreateRunEFn(*cobra.Command, []string) error {
+	if err := b.org.validOrgFlags(); err != nil {
+		return err
+	}
+
+	roleSVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	r := &influxdb.Role{
+		Name:        b.name,
+		Description: b.description,
+	}
+	r.OrgID, err = b.org.getID(orgSVC)
+	if err != nil {
+		return err
+	}
+	if r.Permissions, err = parseRolePermissions(r.OrgID, b.permissions); err != nil {
+		return err
+	}
+
+	if err := roleSVC.CreateRole(context.Background(), r); err != nil {
+		return fmt.Errorf("failed to create role: %v", err)
+	}
+
+	b.printRoles(r)
+	return nil
+}
+
+func (b *cmdRoleBuilder) cmdFind() *cobra.Command {
+	cmd := b.newCmd("find", b.cmdFindRunEFn)
+	cmd.Short = "Find roles"
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The role ID")
+	cmd.Flags().StringVarP(&b.name, "name", "n", "", "The role name")
+	b.org.register(cmd, false)
+
+	return cmd
+}
+
+func (b *cmdRoleBuilder) cmdFindRunEFn(*cobra.Command, []string) error {
+	roleSVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	var filter influxdb.RoleFilter
+	if b.id != "" {
+		id, err := influxdb.IDFromString(b.id)
+		if err != nil {
+			return fmt.Errorf("failed to decode role id %q: %v", b.id, err)
+		}
+		filter.ID = id
+	}
+	if b.name != "" {
+		filter.Name = &b.name
+	}
+	if b.org.id != "" || b.org.name != "" {
+		orgID, err := b.org.getID(orgSVC)
+		if err != nil {
+			return err
+		}
+		filter.OrgID = &orgID
+	}
+
+	rs, _, err := roleSVC.FindRoles(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to find roles: %v", err)
+	}
+	b.printRoles(rs...)
+	return nil
+}
+
+func (b *cmdRoleBuilder) cmdUpdate() *cobra.Command {
+	cmd := b.newCmd("update", b.cmdUpdateRunEFn)
+	cmd.Short = "Update role"
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The role ID (required)")
+	cmd.MarkFlagRequired("id")
+	b.registerRoleFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdRoleBuilder) cmdUpdateRunEFn(cmd *cobra.Command, args []string) error {
+	roleSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode role id %q: %v", b.id, err)
+	}
+
+	var upd influxdb.RoleUpdate
+	if cmd.Flags().Changed("name") {
+		upd.Name = &b.name
+	}
+	if cmd.Flags().Changed("description") {
+		upd.Description = &b.description
+	}
+	if cmd.Flags().Changed("permission") {
+		r, err := roleSVC.FindRoleByID(context.Background(), *id)
+		if err != nil {
+			return fmt.Errorf("failed to find role with id %q: %v", id, err)
+		}
+		ps, err := parseRolePermissions(r.OrgID, b.permissions)
+		if err != nil {
+			return err
+		}
+		upd.Permissions = &ps
+	}
+
+	r, err := roleSVC.UpdateRole(context.Background(), *id, upd)
+	if err != nil {
+		return fmt.Errorf("failed to update role: %v", err)
+	}
+
+	b.printRoles(r)
+	return nil
+}
+
+func (b *cmdRoleBuilder) cmdDelete() *cobra.Command {
+	cmd := b.newCmd("delete", b.cmdDeleteRunEFn)
+	cmd.Short = "Delete role"
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The role ID (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func (b *cmdRoleBuilder) cmdDeleteRunEFn(*cobra.Command, []string) error {
+	roleSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode role id %q: %v", b.id, err)
+	}
+
+	ctx := context.Background()
+	r, err := roleSVC.FindRoleByID(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("failed to find role with id %q: %v", id, err)
+	}
+	if err := roleSVC.DeleteRole(ctx, *id); err != nil {
+		return fmt.Errorf("failed to delete role with id %q: %v", id, err)
+	}
+
+	w := b.newTabWriter()
+	w.WriteHeaders("ID", "Name", "OrganizationID", "Deleted")
+	w.Write(map[string]interface{}{
+		"ID":             r.ID.String(),
+		"Name":           r.Name,
+		"OrganizationID": r.OrgID.String(),
+		"Deleted":        true,
+	})
+	w.Flush()
+
+	return nil
+}
+
+func (b *cmdRoleBuilder) registerMemberFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The role ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVarP(&b.userID, "user-id", "u", "", "The user ID (required)")
+	cmd.MarkFlagRequired("user-id")
+}
+
+func (b *cmdRoleBuilder) memberIDs() (roleID, userID influxdb.ID, err error) {
+	if err := roleID.DecodeFromString(b.id); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode role id %q: %v", b.id, err)
+	}
+	if err := userID.DecodeFromString(b.userID); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode user id %q: %v", b.userID, err)
+	}
+	return roleID, userID, nil
+}
+
+func (b *cmdRoleBuilder) cmdAssign() *cobra.Command {
+	cmd := b.newCmd("assign", b.cmdAssignRunEFn)
+	cmd.Short = "Assign role to a user"
+	b.registerMemberFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdRoleBuilder) cmdAssignRunEFn(*cobra.Command, []string) error {
+	roleSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	roleID, userID, err := b.memberIDs()
+	if err != nil {
+		return err
+	}
+
+	a := &influxdb.RoleAssignment{RoleID: roleID, UserID: userID}
+	if err := roleSVC.AssignRole(context.Background(), a); err != nil {
+		return fmt.Errorf("failed to assign role: %v", err)
+	}
+
+	b.printAssignments(a)
+	return nil
+}
+
+func (b *cmdRoleBuilder) cmdUnassign() *cobra.Command {
+	cmd := b.newCmd("unassign", b.cmdUnassignRunEFn)
+	cmd.Short = "Unassign role from a user"
+	b.registerMemberFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdRoleBuilder) cmdUnassignRunEFn(*cobra.Command, []string) error {
+	roleSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	roleID, userID, err := b.memberIDs()
+	if err != nil {
+		return err
+	}
+
+	if err := roleSVC.UnassignRole(context.Background(), roleID, userID); err != nil {
+		return fmt.Errorf("failed to unassign role: %v", err)
+	}
+
+	w := b.newTabWriter()
+	w.WriteHeaders("RoleID", "UserID", "Unassigned")
+	w.Write(map[string]interface{}{
+		"RoleID":     roleID.String(),
+		"UserID":     userID.String(),
+		"Unassigned": true,
+	})
+	w.Flush()
+
+	return nil
+}
+
+func (b *cmdRoleBuilder) cmdMembers() *cobra.Command {
+	cmd := b.newCmd("members", b.cmdMembersRunEFn)
+	cmd.Short = "List the users a role is assigned to"
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The role ID (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func (b *cmdRoleBuilder) cmdMembersRunEFn(*cobra.Command, []string) error {
+	roleSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode role id %q: %v", b.id, err)
+	}
+
+	as, _, err := roleSVC.FindRoleAssignments(context.Background(), influxdb.RoleAssignmentFilter{RoleID: id})
+	if err != nil {
+		return fmt.Errorf("failed to find the members of the role: %v", err)
+	}
+
+	b.printAssignments(as...)
+	return nil
+}
+
+func (b *cmdRoleBuilder) printRoles(rs ...*influxdb.Role) {
+	w := b.newTabWriter()
+	w.WriteHeaders("ID", "Name", "OrganizationID", "BuiltIn", "Permissions")
+	for _, r := range rs {
+		ps := make([]string, 0, len(r.Permissions))
+		for _, p := range r.Permissions {
+			ps = append(ps, p.String())
+		}
+		w.Write(map[string]interface{}{
+			"ID":             r.ID.String(),
+			"Name":           r.Name,
+			"OrganizationID": r.OrgID.String(),
+			"BuiltIn":        r.BuiltIn,
+			"Permissions":    ps,
+		})
+	}
+	w.Flush()
+}
+
+func (b *cmdRoleBuilder) printAssignments(as ...*influxdb.RoleAssignment) {
+	w := b.newTabWriter()
+	w.WriteHeaders("RoleID", "UserID", "OrganizationID")
+	for _, a := range as {
+		w.Write(map[string]interface{}{
+			"RoleID":         a.RoleID.String(),
+			"UserID":         a.UserID.String(),
+			"OrganizationID": a.OrgID.String(),
+		})
+	}
+	w.Flush()
+}
+
+// parseRolePermissions parses the permissions of the form action:resources and action:resources/id
+// to permissions on the resources of the organization.
+func parseRolePermissions(orgID influxdb.ID, ps []string) ([]influxdb.Permission, error) {
+	permissions := make([]influxdb.Permission, 0, len(ps))
+	for _, s := range ps {
+		parts := strings.SplitN(s, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid permission %q, must be of the form action:resources or action:resources/id", s)
+		}
+		action := influxdb.Action(parts[0])
+		if err := action.Valid(); err != nil {
+			return nil, fmt.Errorf("invalid permission %q: %v", s, err)
+		}
+
+		rt, id := parts[1], ""
+		if i := strings.Index(rt, "/"); i >= 0 {
+			rt, id = rt[:i], rt[i+1:]
+		}
+		resourceType := influxdb.ResourceType(rt)
+		if err := resourceType.Valid(); err != nil {
+			return nil, fmt.Errorf("invalid permission %q: %v", s, err)
+		}
+
+		var p *influxdb.Permission
+		var err error
+		switch {
+		case id != "":
+			var resourceID influxdb.ID
+			if err := resourceID.DecodeFromString(id); err != nil {
+				return nil, fmt.Errorf("invalid permission %q: %v", s, err)
+			}
+			p, err = influxdb.NewPermissionAtID(resourceID, action, resourceType, orgID)
+		case resourceType == influxdb.OrgsResourceType:
+			p, err = influxdb.NewPermissionAtID(orgID, action, resourceType, orgID)
+		default:
+			p, err = influxdb.NewPermission(action, resourceType, orgID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, *p)
+	}
+	return permissions, nil
+}
+
+func newRoleService() (influxdb.RoleService, error) {
+	if flags.local {
+		return newLocalKVService()
+	}
+
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.RoleService{Client: httpClient}, nil
+}
+
+func newRoleSVCs() (influxdb.RoleService, influxdb.OrganizationService, error) {
+	roleSvc, err := newRoleService()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgSvc, err := newOrganizationService()
+	if err != nil {
+		return nil, nil, err
+	}
+	return roleSvc, orgSvc, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdRole(t *testing.T) {
+	orgID := influxdb.ID(9000)
+
+	fakeSVCFn := func(svc influxdb.RoleService) roleSVCsFn {
+		return func() (influxdb.RoleService, influxdb.OrganizationService, error) {
+			return svc, &mock.OrganizationService{
+				FindOrganizationF: func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+					return &influxdb.Organization{ID: orgID, Name: "influxdata"}, nil
+				},
+			}, nil
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			flags    []string
+			expected *influxdb.Role
+			wantErr  bool
+		}{
+			{
+				name: "dashboard editor",
+				flags: []string{
+					"--org=influxdata",
+					"--name=dashboard editor",
+					"-p", "write:dashboards",
+					"-p", "read:buckets/" + influxdb.ID(1).String(),
+					"-p", "read:orgs",
+				},
+				expected: &influxdb.Role{
+					OrgID: orgID,
+					Name:  "dashboard editor",
+					Permissions: []influxdb.Permission{
+						{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID}},
+						{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, ID: idPtr(1), OrgID: &orgID}},
+						{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID, OrgID: &orgID}},
+					},
+				},
+			},
+			{
+				name:    "invalid action",
+				flags:   []string{"--org-id=" + orgID.String(), "--name=r", "-p", "delete:dashboards"},
+				wantErr: true,
+			},
+			{
+				name:    "invalid resource",
+				flags:   []string{"--org-id=" + orgID.String(), "--name=r", "-p", "write:widgets"},
+				wantErr: true,
+			},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				var got *influxdb.Role
+				svc := mock.NewRoleService()
+				svc.CreateRoleFn = func(ctx context.Context, r *influxdb.Role) error {
+					got = r
+					return nil
+				}
+
+				builder := newCmdRoleBuilder(fakeSVCFn(svc), out(ioutil.Discard))
+				cmd := builder.cmdCreate()
+				cmd.RunE = builder.cmdCreateRunEFn
+				cmd.SetArgs(tt.flags)
+
+				err := cmd.Execute()
+				if tt.wantErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, got)
+			}
+
+			t.Run(tt.name, fn)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		var upd influxdb.RoleUpdate
+		svc := mock.NewRoleService()
+		svc.FindRoleByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+			return &influxdb.Role{ID: id, OrgID: orgID}, nil
+		}
+		svc.UpdateRoleFn = func(ctx context.Context, id influxdb.ID, u influxdb.RoleUpdate) (*influxdb.Role, error) {
+			upd = u
+			return &influxdb.Role{ID: id, OrgID: orgID}, nil
+		}
+
+		builder := newCmdRoleBuilder(fakeSVCFn(svc), out(ioutil.Discard))
+		cmd := builder.cmdUpdate()
+		cmd.RunE = builder.cmdUpdateRunEFn
+		cmd.SetArgs([]string{"--id=" + influxdb.ID(1).String(), "--description=", "-p", "read:alerts"})
+
+		require.NoError(t, cmd.Execute())
+		description := ""
+		ps := []influxdb.Permission{
+			{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.AlertsResourceType, OrgID: &orgID}},
+		}
+		assert.Equal(t, influxdb.RoleUpdate{Description: &description, Permissions: &ps}, upd)
+	})
+
+	t.Run("assign", func(t *testing.T) {
+		var got *influxdb.RoleAssignment
+		svc := mock.NewRoleService()
+		svc.AssignRoleFn = func(ctx context.Context, a *influxdb.RoleAssignment) error {
+			got = a
+			return nil
+		}
+
+		builder := newCmdRoleBuilder(fakeSVCFn(svc), out(ioutil.Discard))
+		cmd := builder.cmdAssign()
+		cmd.RunE = builder.cmdAssignRunEFn
+		cmd.SetArgs([]string{"--id=" + influxdb.ID(1).String(), "--user-id=" + influxdb.ID(2).String()})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, &influxdb.RoleAssignment{RoleID: 1, UserID: 2}, got)
+	})
+}
+
+func idPtr(id influxdb.ID) *influxdb.ID {
+	return &id
+}
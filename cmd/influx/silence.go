@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/task/options"
+	"github.com/spf13/cobra"
+)
+
+type silenceSVCsFn func() (influxdb.SilenceService, influxdb.OrganizationService, error)
+
+func cmdSilence(opts ...genericCLIOptFn) *cobra.Command {
+	return newCmdSilenceBuilder(newSilenceSVCs, opts...).cmd()
+}
+
+type cmdSilenceBuilder struct {
+	genericCLIOpts
+
+	svcFn silenceSVCsFn
+
+	id       string
+	org      organization
+	matchers []string
+	start    string
+	end      string
+	repeat   string
+	until    string
+	comment  string
+	active   bool
+}
+
+func newCmdSilenceBuilder(svcsFn silenceSVCsFn, opts ...genericCLIOptFn) *cmdSilenceBuilder {
+	opt := genericCLIOpts{
+		in: os.Stdin,
+		w:  os.Stdout,
+	}
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	return &cmdSilenceBuilder{
+		genericCLIOpts: opt,
+		svcFn:          svcsFn,
+	}
+}
+
+func (b *cmdSilenceBuilder) cmd() *cobra.Command {
+	cmd := b.newCmd("silence", nil)
+	cmd.Short = "Notification silence and maintenance window management commands"
+	cmd.TraverseChildren = true
+	cmd.Run = seeHelp
+	cmd.AddCommand(
+		b.cmdCreate(),
+		b.cmdDelete(),
+		b.cmdFind(),
+		b.cmdUpdate(),
+	)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) registerWindowFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&b.matchers, "matcher", "m", nil, "Silence the statuses matching key=value, key!=value, key=~regex or key!~regex, can be repeated")
+	cmd.Flags().StringVar(&b.start, "start", "", "The RFC3339 time the silence starts at")
+	cmd.Flags().StringVar(&b.end, "end", "", "The RFC3339 time the silence ends at")
+	cmd.Flags().StringVar(&b.repeat, "repeat", "", "Repeat the silence as a maintenance window every duration, such as 1w")
+	cmd.Flags().StringVar(&b.until, "until", "", "The RFC3339 time a repeated silence stops repeating at")
+	cmd.Flags().StringVarP(&b.comment, "comment", "c", "", "The reason of the silence")
+}
+
+func (b *cmdSilenceBuilder) cmdCreate() *cobra.Command {
+	cmd := b.newCmd("create", b.cmdCreateRunEFn)
+	cmd.Short = "Create silence"
+	b.registerWindowFlags(cmd)
+	cmd.MarkFlagRequired("end")
+	b.org.register(cmd, false)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdCreateRunEFn(*cobra.Command, []string) error {
+	if err := b.org.validOrgFlags(); err != nil {
+		return err
+	}
+
+	silenceSVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	s := &influxdb.Silence{
+		Start:   time.Now().UTC(),
+		Comment: b.comment,
+	}
+	s.OrgID, err = b.org.getID(orgSVC)
+	if err != nil {
+		return err
+	}
+	if s.Matchers, err = parseMatchers(b.matchers); err != nil {
+		return err
+	}
+	if b.start != "" {
+		if s.Start, err = parseSilenceTime("start", b.start); err != nil {
+			return err
+		}
+	}
+	if s.End, err = parseSilenceTime("end", b.end); err != nil {
+		return err
+	}
+	if b.repeat != "" {
+		if s.Repeat, err = parseRepeat(b.repeat); err != nil {
+			return err
+		}
+	}
+	if b.until != "" {
+		until, err := parseSilenceTime("until", b.until)
+		if err != nil {
+			return err
+		}
+		s.Until = &until
+	}
+
+	if err := silenceSVC.CreateSilence(context.Background(), s); err != nil {
+		return fmt.Errorf("failed to create silence: %v", err)
+	}
+
+	b.printSilences(s)
+	return nil
+}
+
+func (b *cmdSilenceBuilder) cmdFind() *cobra.Command {
+	cmd := b.newCmd("find", b.cmdFindRunEFn)
+	cmd.Short = "Find silences"
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The silence ID")
+	cmd.Flags().BoolVar(&b.active, "active", false, "Only find the silences active now")
+	b.org.register(cmd, false)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdFindRunEFn(*cobra.Command, []string) error {
+	silenceSVC, orgSVC, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	if b.id != "" {
+		id, err := influxdb.IDFromString(b.id)
+		if err != nil {
+			return fmt.Errorf("failed to decode silence id %q: %v", b.id, err)
+		}
+		s, err := silenceSVC.FindSilenceByID(context.Background(), *id)
+		if err != nil {
+			return fmt.Errorf("failed to find silence: %v", err)
+		}
+		b.printSilences(s)
+		return nil
+	}
+
+	var filter influxdb.SilenceFilter
+	if b.org.id != "" || b.org.name != "" {
+		orgID, err := b.org.getID(orgSVC)
+		if err != nil {
+			return err
+		}
+		filter.OrgID = &orgID
+	}
+	if b.active {
+		now := time.Now().UTC()
+		filter.ActiveAt = &now
+	}
+
+	ss, _, err := silenceSVC.FindSilences(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to find silences: %v", err)
+	}
+	b.printSilences(ss...)
+	return nil
+}
+
+func (b *cmdSilenceBuilder) cmdUpdate() *cobra.Command {
+	cmd := b.newCmd("update", b.cmdUpdateRunEFn)
+	cmd.Short = "Update silence"
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The silence ID (required)")
+	cmd.MarkFlagRequired("id")
+	b.registerWindowFlags(cmd)
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdUpdateRunEFn(cmd *cobra.Command, args []string) error {
+	silenceSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode silence id %q: %v", b.id, err)
+	}
+
+	var upd influxdb.SilenceUpdate
+	if cmd.Flags().Changed("matcher") {
+		matchers, err := parseMatchers(b.matchers)
+		if err != nil {
+			return err
+		}
+		upd.Matchers = &matchers
+	}
+	for _, f := range []struct {
+		name  string
+		value string
+		dest  **time.Time
+	}{
+		{"start", b.start, &upd.Start},
+		{"end", b.end, &upd.End},
+		{"until", b.until, &upd.Until},
+	} {
+		if f.value == "" {
+			continue
+		}
+		t, err := parseSilenceTime(f.name, f.value)
+		if err != nil {
+			return err
+		}
+		*f.dest = &t
+	}
+	if b.repeat != "" {
+		if upd.Repeat, err = parseRepeat(b.repeat); err != nil {
+			return err
+		}
+	}
+	if cmd.Flags().Changed("comment") {
+		upd.Comment = &b.comment
+	}
+
+	s, err := silenceSVC.UpdateSilence(context.Background(), *id, upd)
+	if err != nil {
+		return fmt.Errorf("failed to update silence: %v", err)
+	}
+
+	b.printSilences(s)
+	return nil
+}
+
+func (b *cmdSilenceBuilder) cmdDelete() *cobra.Command {
+	cmd := b.newCmd("delete", b.cmdDeleteRunEFn)
+	cmd.Short = "Delete silence"
+	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The silence ID (required)")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func (b *cmdSilenceBuilder) cmdDeleteRunEFn(*cobra.Command, []string) error {
+	silenceSVC, _, err := b.svcFn()
+	if err != nil {
+		return err
+	}
+
+	id, err := influxdb.IDFromString(b.id)
+	if err != nil {
+		return fmt.Errorf("failed to decode silence id %q: %v", b.id, err)
+	}
+
+	ctx := context.Background()
+	s, err := silenceSVC.FindSilenceByID(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("failed to find silence with id %q: %v", id, err)
+	}
+	if err := silenceSVC.DeleteSilence(ctx, *id); err != nil {
+		return fmt.Errorf("failed to delete silence with id %q: %v", id, err)
+	}
+
+	w := b.newTabWriter()
+	w.WriteHeaders("ID", "OrganizationID", "Deleted")
+	w.Write(map[string]interface{}{
+		"ID":             s.ID.String(),
+		"OrganizationID": s.OrgID.String(),
+		"Deleted":        true,
+	})
+	w.Flush()
+
+	return nil
+}
+
+func (b *cmdSilenceBuilder) printSilences(ss ...*influxdb.Silence) {
+	w := b.newTabWriter()
+	w.WriteHeaders("ID", "OrganizationID", "Matchers", "Start", "End", "Repeat", "Until", "Comment")
+	for _, s := range ss {
+		var repeat, until string
+		if s.Repeat != nil {
+			repeat = s.Repeat.String()
+		}
+		if s.Until != nil {
+			until = s.Until.Format(time.RFC3339)
+		}
+		w.Write(map[string]interface{}{
+			"ID":             s.ID.String(),
+			"OrganizationID": s.OrgID.String(),
+			"Matchers":       formatMatchers(s.Matchers),
+			"Start":          s.Start.Format(time.RFC3339),
+			"End":            s.End.Format(time.RFC3339),
+			"Repeat":         repeat,
+			"Until":          until,
+			"Comment":        s.Comment,
+		})
+	}
+	w.Flush()
+}
+
+// matcherOperators are the operators of the matcher flags, the two characters operators are matched first.
+var matcherOperators = []struct {
+	token string
+	op    influxdb.Operator
+}{
+	{"!=", influxdb.NotEqual},
+	{"=~", influxdb.RegexEqual},
+	{"!~", influxdb.NotRegexEqual},
+	{"=", influxdb.Equal},
+}
+
+// parseMatchers parses the matchers of the form key=value, key!=value, key=~regex and key!~regex.
+func parseMatchers(ms []string) ([]influxdb.TagRule, error) {
+	rules := make([]influxdb.TagRule, 0, len(ms))
+	for _, m := range ms {
+		i := strings.IndexAny(m, "!=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid matcher %q, must be of the form key=value, key!=value, key=~regex or key!~regex", m)
+		}
+		key, rest := m[:i], m[i:]
+
+		var rule *influxdb.TagRule
+		for _, o := range matcherOperators {
+			if strings.HasPrefix(rest, o.token) {
+				rule = &influxdb.TagRule{
+					Tag:      influxdb.Tag{Key: key, Value: rest[len(o.token):]},
+					Operator: o.op,
+				}
+				break
+			}
+		}
+		if rule == nil {
+			return nil, fmt.Errorf("invalid matcher %q, must be of the form key=value, key!=value, key=~regex or key!~regex", m)
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, nil
+}
+
+func formatMatchers(rules []influxdb.TagRule) string {
+	ms := make([]string, 0, len(rules))
+	for _, r := range rules {
+		token := "="
+		for _, o := range matcherOperators {
+			if o.op == r.Operator {
+				token = o.token
+				break
+			}
+		}
+		ms = append(ms, r.Key+token+r.Value)
+	}
+	return strings.Join(ms, ",")
+}
+
+func parseSilenceTime(flag, v string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s time %q, must be an RFC3339 time: %v", flag, v, err)
+	}
+	return t, nil
+}
+
+func parseRepeat(v string) (*options.Duration, error) {
+	d := &options.Duration{}
+	if err := d.Parse(v); err != nil {
+		return nil, fmt.Errorf("invalid repeat duration %q: %v", v, err)
+	}
+	return d, nil
+}
+
+func newSilenceSVCs() (influxdb.SilenceService, influxdb.OrganizationService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgSvc := &http.OrganizationService{Client: httpClient}
+	return &http.SilenceService{Client: httpClient}, orgSvc, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/task/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdSilence(t *testing.T) {
+	orgID := influxdb.ID(9000)
+
+	fakeSVCFn := func(svc influxdb.SilenceService) silenceSVCsFn {
+		return func() (influxdb.SilenceService, influxdb.OrganizationService, error) {
+			return svc, &mock.OrganizationService{
+				FindOrganizationF: func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+					return &influxdb.Organization{ID: orgID, Name: "influxdata"}, nil
+				},
+			}, nil
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		start := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+		until := start.Add(30 * 24 * time.Hour)
+
+		tests := []struct {
+			name     string
+			flags    []string
+			expected *influxdb.Silence
+			wantErr  bool
+		}{
+			{
+				name: "maintenance window",
+				flags: []string{
+					"--org=influxdata",
+					"--start=2019-12-01T10:00:00Z",
+					"--end=2019-12-01T11:00:00Z",
+					"--repeat=1w",
+					"--until=2019-12-31T10:00:00Z",
+					"--comment=weekly upgrade",
+					"-m", "host=a",
+					"-m", "_level!=ok",
+					"-m", "region=~^us-",
+					"-m", "env!~prod",
+				},
+				expected: &influxdb.Silence{
+					OrgID: orgID,
+					Matchers: []influxdb.TagRule{
+						{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal},
+						{Tag: influxdb.Tag{Key: "_level", Value: "ok"}, Operator: influxdb.NotEqual},
+						{Tag: influxdb.Tag{Key: "region", Value: "^us-"}, Operator: influxdb.RegexEqual},
+						{Tag: influxdb.Tag{Key: "env", Value: "prod"}, Operator: influxdb.NotRegexEqual},
+					},
+					Start:   start,
+					End:     start.Add(time.Hour),
+					Repeat:  options.MustParseDuration("1w"),
+					Until:   &until,
+					Comment: "weekly upgrade",
+				},
+			},
+			{
+				name:    "invalid matcher",
+				flags:   []string{"--org-id=" + orgID.String(), "--end=2019-12-01T11:00:00Z", "-m", "host"},
+				wantErr: true,
+			},
+			{
+				name:    "invalid end",
+				flags:   []string{"--org-id=" + orgID.String(), "--end=tomorrow"},
+				wantErr: true,
+			},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				var got *influxdb.Silence
+				svc := mock.NewSilenceService()
+				svc.CreateSilenceFn = func(ctx context.Context, s *influxdb.Silence) error {
+					got = s
+					return nil
+				}
+
+				builder := newCmdSilenceBuilder(fakeSVCFn(svc), out(ioutil.Discard))
+				cmd := builder.cmdCreate()
+				cmd.RunE = builder.cmdCreateRunEFn
+				cmd.SetArgs(tt.flags)
+
+				err := cmd.Execute()
+				if tt.wantErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, got)
+			}
+
+			t.Run(tt.name, fn)
+		}
+	})
+
+	t.Run("find", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			flags      []string
+			wantOrg    bool
+			wantActive bool
+		}{
+			{
+				name: "all",
+			},
+			{
+				name:       "active silences of an organization",
+				flags:      []string{"--org=influxdata", "--active"},
+				wantOrg:    true,
+				wantActive: true,
+			},
+		}
+
+		for _, tt := range tests {
+			fn := func(t *testing.T) {
+				var filter influxdb.SilenceFilter
+				svc := mock.NewSilenceService()
+				svc.FindSilencesFn = func(ctx context.Context, f influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+					filter = f
+					return nil, 0, nil
+				}
+
+				builder := newCmdSilenceBuilder(fakeSVCFn(svc), out(ioutil.Discard))
+				cmd := builder.cmdFind()
+				cmd.RunE = builder.cmdFindRunEFn
+				cmd.SetArgs(tt.flags)
+
+				require.NoError(t, cmd.Execute())
+				assert.Equal(t, tt.wantOrg, filter.OrgID != nil && *filter.OrgID == orgID)
+				assert.Equal(t, tt.wantActive, filter.ActiveAt != nil)
+			}
+
+			t.Run(tt.name, fn)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		var upd influxdb.SilenceUpdate
+		svc := mock.NewSilenceService()
+		svc.UpdateSilenceFn = func(ctx context.Context, id influxdb.ID, u influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+			upd = u
+			return &influxdb.Silence{ID: id, OrgID: orgID}, nil
+		}
+
+		builder := newCmdSilenceBuilder(fakeSVCFn(svc), out(ioutil.Discard))
+		cmd := builder.cmdUpdate()
+		cmd.RunE = builder.cmdUpdateRunEFn
+		cmd.SetArgs([]string{"--id=" + influxdb.ID(1).String(), "--end=2019-12-01T12:00:00Z", "--comment="})
+
+		require.NoError(t, cmd.Execute())
+		end := time.Date(2019, 12, 1, 12, 0, 0, 0, time.UTC)
+		comment := ""
+		assert.Equal(t, influxdb.SilenceUpdate{End: &end, Comment: &comment}, upd)
+	})
+}
@@ -28,19 +28,22 @@ func cmdTask() *cobra.Command {
 	}
 
 	cmd.AddCommand(
+		taskBackfillCmd(),
 		taskLogCmd(),
 		taskRunCmd(),
 		taskCreateCmd(),
 		taskDeleteCmd(),
 		taskFindCmd(),
 		taskUpdateCmd(),
+		taskVersionCmd(),
 	)
 
 	return cmd
 }
 
 var taskCreateFlags struct {
-	org organization
+	org    organization
+	notify taskNotifyFlags
 }
 
 func taskCreateCmd() *cobra.Command {
@@ -52,10 +55,50 @@ func taskCreateCmd() *cobra.Command {
 	}
 
 	taskCreateFlags.org.register(cmd, false)
+	taskCreateFlags.notify.register(cmd, false)
 
 	return cmd
 }
 
+// taskNotifyFlags are the flags of the notification endpoint a task notifies of its failed runs.
+type taskNotifyFlags struct {
+	endpointID  string
+	policy      string
+	consecutive int
+	remove      bool
+}
+
+func (f *taskNotifyFlags) register(cmd *cobra.Command, update bool) {
+	cmd.Flags().StringVar(&f.endpointID, "notify-endpoint-id", "", "ID of the notification endpoint notified of failed runs")
+	cmd.Flags().StringVar(&f.policy, "notify-policy", platform.TaskNotifyFirst, "when failed runs are notified: first, every or consecutive")
+	cmd.Flags().IntVar(&f.consecutive, "notify-consecutive", 0, "number of consecutive failed runs notified with the consecutive policy")
+	if update {
+		cmd.Flags().BoolVar(&f.remove, "no-notify", false, "stop notifying a notification endpoint of failed runs")
+	}
+}
+
+// notification returns the notification of the flags, nil if no endpoint is set.
+func (f *taskNotifyFlags) notification() (*platform.TaskNotification, error) {
+	if f.remove {
+		if f.endpointID != "" {
+			return nil, fmt.Errorf("must specify exactly one of notify-endpoint-id and no-notify")
+		}
+		return &platform.TaskNotification{}, nil
+	}
+	if f.endpointID == "" {
+		return nil, nil
+	}
+
+	n := &platform.TaskNotification{Policy: f.policy, Consecutive: f.consecutive}
+	if err := n.EndpointID.DecodeFromString(f.endpointID); err != nil {
+		return nil, fmt.Errorf("error parsing notification endpoint ID: %s", err)
+	}
+	if err := n.Valid(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
 func taskCreateF(cmd *cobra.Command, args []string) error {
 	if err := taskCreateFlags.org.validOrgFlags(); err != nil {
 		return err
@@ -76,6 +119,9 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		Flux:         flux,
 		Organization: taskCreateFlags.org.name,
 	}
+	if tc.Notification, err = taskCreateFlags.notify.notification(); err != nil {
+		return err
+	}
 	if taskCreateFlags.org.id != "" || taskCreateFlags.org.name != "" {
 		svc, err := newOrganizationService()
 		if err != nil {
@@ -103,6 +149,7 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"Every",
 		"Cron",
+		"Location",
 	)
 	w.Write(map[string]interface{}{
 		"ID":             t.ID.String(),
@@ -112,6 +159,7 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		"Status":         t.Status,
 		"Every":          t.Every,
 		"Cron":           t.Cron,
+		"Location":       t.Location,
 	})
 	w.Flush()
 
@@ -210,6 +258,7 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"Every",
 		"Cron",
+		"Location",
 	)
 	for _, t := range tasks {
 		w.Write(map[string]interface{}{
@@ -220,6 +269,7 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 			"Status":         t.Status,
 			"Every":          t.Every,
 			"Cron":           t.Cron,
+			"Location":       t.Location,
 		})
 	}
 	w.Flush()
@@ -228,8 +278,10 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 }
 
 var taskUpdateFlags struct {
-	id     string
-	status string
+	id                 string
+	status             string
+	versionDescription string
+	notify             taskNotifyFlags
 }
 
 func taskUpdateCmd() *cobra.Command {
@@ -241,6 +293,8 @@ func taskUpdateCmd() *cobra.Command {
 
 	taskUpdateCmd.Flags().StringVarP(&taskUpdateFlags.id, "id", "i", "", "task ID (required)")
 	taskUpdateCmd.Flags().StringVarP(&taskUpdateFlags.status, "status", "", "", "update task status")
+	taskUpdateCmd.Flags().StringVarP(&taskUpdateFlags.versionDescription, "version-description", "m", "", "description of the change recorded with the new version of the script")
+	taskUpdateFlags.notify.register(taskUpdateCmd, true)
 	taskUpdateCmd.MarkFlagRequired("id")
 
 	return taskUpdateCmd
@@ -270,6 +324,13 @@ func taskUpdateF(cmd *cobra.Command, args []string) error {
 		}
 		update.Flux = &flux
 	}
+	update.VersionDescription = taskUpdateFlags.versionDescription
+
+	n, err := taskUpdateFlags.notify.notification()
+	if err != nil {
+		return err
+	}
+	update.Notification = n
 
 	t, err := s.UpdateTask(context.Background(), id, update)
 	if err != nil {
@@ -286,6 +347,7 @@ func taskUpdateF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"Every",
 		"Cron",
+		"Location",
 	)
 	w.Write(map[string]interface{}{
 		"ID":             t.ID.String(),
@@ -295,6 +357,7 @@ func taskUpdateF(cmd *cobra.Command, args []string) error {
 		"Status":         t.Status,
 		"Every":          t.Every,
 		"Cron":           t.Cron,
+		"Location":       t.Location,
 	})
 	w.Flush()
 
@@ -351,6 +414,7 @@ func taskDeleteF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"Every",
 		"Cron",
+		"Location",
 	)
 	w.Write(map[string]interface{}{
 		"ID":             t.ID.String(),
@@ -360,6 +424,7 @@ func taskDeleteF(cmd *cobra.Command, args []string) error {
 		"Status":         t.Status,
 		"Every":          t.Every,
 		"Cron":           t.Cron,
+		"Location":       t.Location,
 	})
 	w.Flush()
 
@@ -445,20 +510,108 @@ func taskLogFindF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var taskRunFlags struct {
+	dryRun       bool
+	org          organization
+	scheduledFor string
+}
+
 func taskRunCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "run",
+		Use:   "run [query literal or @/path/to/query.flux]",
 		Short: "Run related commands",
-		Run:   seeHelp,
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  wrapCheckSetup(taskRunF),
 	}
 	cmd.AddCommand(
 		taskRunFindCmd(),
 		taskRunRetryCmd(),
 	)
 
+	cmd.Flags().BoolVarP(&taskRunFlags.dryRun, "dry-run", "", false, "execute the task script once without writing its output")
+	cmd.Flags().StringVarP(&taskRunFlags.scheduledFor, "scheduled-for", "", "", "RFC3339 time the dry run is scheduled for, now by default")
+	taskRunFlags.org.register(cmd, false)
+
 	return cmd
 }
 
+func taskRunF(cmd *cobra.Command, args []string) error {
+	if !taskRunFlags.dryRun {
+		seeHelp(cmd, args)
+		return nil
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("must provide the task script to dry run")
+	}
+	if err := taskRunFlags.org.validOrgFlags(); err != nil {
+		return err
+	}
+
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	flux, err := repl.LoadQuery(args[0])
+	if err != nil {
+		return fmt.Errorf("error parsing flux script: %s", err)
+	}
+
+	req := platform.TaskDryRunRequest{
+		Flux:         flux,
+		Organization: taskRunFlags.org.name,
+	}
+	if taskRunFlags.org.id != "" {
+		id, err := platform.IDFromString(taskRunFlags.org.id)
+		if err != nil {
+			return fmt.Errorf("error parsing organization ID: %s", err)
+		}
+		req.OrganizationID = *id
+	}
+	if taskRunFlags.scheduledFor != "" {
+		req.ScheduledFor, err = time.Parse(time.RFC3339, taskRunFlags.scheduledFor)
+		if err != nil {
+			return fmt.Errorf("error parsing scheduled for time: %s", err)
+		}
+	}
+
+	dr, err := s.DryRunTask(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range dr.Log {
+		fmt.Printf("%s\t%s\n", l.Time, l.Message)
+	}
+	for _, t := range dr.Tables {
+		fmt.Printf("\nTable written by %s\n", t.Sink)
+
+		headers := make([]string, 0, len(t.Columns))
+		for _, c := range t.Columns {
+			headers = append(headers, c.Label)
+		}
+		w := internal.NewTabWriter(os.Stdout)
+		w.WriteHeaders(headers...)
+		for _, r := range t.Records {
+			m := make(map[string]interface{}, len(r))
+			for i, v := range r {
+				m[headers[i]] = v
+			}
+			w.Write(m)
+		}
+		w.Flush()
+	}
+	if dr.Truncated {
+		fmt.Printf("\nOnly the first %d records are shown.\n", platform.MaxDryRunRecords)
+	}
+	if dr.Error != "" {
+		return fmt.Errorf("dry run failed: %s", dr.Error)
+	}
+
+	return nil
+}
+
 var taskRunFindFlags struct {
 	runID      string
 	taskID     string
@@ -530,6 +683,7 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 		"StartedAt",
 		"FinishedAt",
 		"RequestedAt",
+		"TaskVersion",
 	)
 
 	for _, r := range runs {
@@ -546,6 +700,7 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 			"StartedAt":    startedAt,
 			"FinishedAt":   finishedAt,
 			"RequestedAt":  requestedAt,
+			"TaskVersion":  r.TaskVersion,
 		})
 	}
 	w.Flush()
@@ -597,3 +752,375 @@ func runRetryF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func taskBackfillCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Backfill related commands",
+		Run:   seeHelp,
+	}
+	cmd.AddCommand(
+		taskBackfillCreateCmd(),
+		taskBackfillFindCmd(),
+		taskBackfillCancelCmd(),
+	)
+
+	return cmd
+}
+
+var taskBackfillCreateFlags struct {
+	taskID      string
+	start       string
+	stop        string
+	concurrency int
+}
+
+func taskBackfillCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Run a task for every schedule point between start and stop",
+		RunE:  wrapCheckSetup(taskBackfillCreateF),
+	}
+
+	cmd.Flags().StringVarP(&taskBackfillCreateFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&taskBackfillCreateFlags.start, "start", "", "", "RFC3339 time of the first schedule point to run (required)")
+	cmd.Flags().StringVarP(&taskBackfillCreateFlags.stop, "stop", "", "", "RFC3339 time the schedule points to run are before (required)")
+	cmd.Flags().IntVarP(&taskBackfillCreateFlags.concurrency, "concurrency", "c", platform.DefaultBackfillConcurrency, "number of runs to execute at once")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("start")
+	cmd.MarkFlagRequired("stop")
+
+	return cmd
+}
+
+func taskBackfillCreateF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	var (
+		bc  = platform.BackfillCreate{Concurrency: taskBackfillCreateFlags.concurrency}
+		err error
+	)
+	if err := bc.TaskID.DecodeFromString(taskBackfillCreateFlags.taskID); err != nil {
+		return err
+	}
+	if bc.Start, err = time.Parse(time.RFC3339, taskBackfillCreateFlags.start); err != nil {
+		return fmt.Errorf("invalid start: %v", err)
+	}
+	if bc.Stop, err = time.Parse(time.RFC3339, taskBackfillCreateFlags.stop); err != nil {
+		return fmt.Errorf("invalid stop: %v", err)
+	}
+
+	b, err := s.CreateBackfill(context.Background(), bc)
+	if err != nil {
+		return err
+	}
+
+	writeBackfills(b)
+	return nil
+}
+
+var taskBackfillFindFlags struct {
+	taskID, backfillID string
+}
+
+func taskBackfillFindCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find the backfills of a task and their progress",
+		RunE:  wrapCheckSetup(taskBackfillFindF),
+	}
+
+	cmd.Flags().StringVarP(&taskBackfillFindFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&taskBackfillFindFlags.backfillID, "backfill-id", "b", "", "backfill id")
+	cmd.MarkFlagRequired("task-id")
+
+	return cmd
+}
+
+func taskBackfillFindF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskBackfillFindFlags.taskID); err != nil {
+		return err
+	}
+
+	var backfills []*platform.Backfill
+	if taskBackfillFindFlags.backfillID != "" {
+		var id platform.ID
+		if err := id.DecodeFromString(taskBackfillFindFlags.backfillID); err != nil {
+			return err
+		}
+		b, err := s.FindBackfillByID(context.Background(), taskID, id)
+		if err != nil {
+			return err
+		}
+		backfills = append(backfills, b)
+	} else {
+		var err error
+		backfills, err = s.FindBackfills(context.Background(), taskID)
+		if err != nil {
+			return err
+		}
+	}
+
+	writeBackfills(backfills...)
+	return nil
+}
+
+var taskBackfillCancelFlags struct {
+	taskID, backfillID string
+}
+
+func taskBackfillCancelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a backfill and the runs it is executing",
+		RunE:  wrapCheckSetup(taskBackfillCancelF),
+	}
+
+	cmd.Flags().StringVarP(&taskBackfillCancelFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&taskBackfillCancelFlags.backfillID, "backfill-id", "b", "", "backfill id (required)")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("backfill-id")
+
+	return cmd
+}
+
+func taskBackfillCancelF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	var taskID, id platform.ID
+	if err := taskID.DecodeFromString(taskBackfillCancelFlags.taskID); err != nil {
+		return err
+	}
+	if err := id.DecodeFromString(taskBackfillCancelFlags.backfillID); err != nil {
+		return err
+	}
+
+	if err := s.CancelBackfill(context.Background(), taskID, id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backfill %s of task %s canceled.\n", id, taskID)
+	return nil
+}
+
+func writeBackfills(backfills ...*platform.Backfill) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"TaskID",
+		"Start",
+		"Stop",
+		"Status",
+		"Total",
+		"Succeeded",
+		"Failed",
+	)
+	for _, b := range backfills {
+		w.Write(map[string]interface{}{
+			"ID":        b.ID,
+			"TaskID":    b.TaskID,
+			"Start":     b.Start.Format(time.RFC3339),
+			"Stop":      b.Stop.Format(time.RFC3339),
+			"Status":    b.Status,
+			"Total":     b.Total,
+			"Succeeded": b.Succeeded,
+			"Failed":    b.Failed,
+		})
+	}
+	w.Flush()
+}
+
+func taskVersionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Task script version related commands",
+		Run:   seeHelp,
+	}
+	cmd.AddCommand(
+		taskVersionFindCmd(),
+		taskVersionDiffCmd(),
+		taskVersionRestoreCmd(),
+	)
+
+	return cmd
+}
+
+var taskVersionFindFlags struct {
+	taskID  string
+	version int
+}
+
+func taskVersionFindCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find the versions of the script of a task",
+		RunE:  wrapCheckSetup(taskVersionFindF),
+	}
+
+	cmd.Flags().StringVarP(&taskVersionFindFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().IntVarP(&taskVersionFindFlags.version, "version", "v", 0, "version number, prints its script")
+	cmd.MarkFlagRequired("task-id")
+
+	return cmd
+}
+
+func taskVersionFindF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskVersionFindFlags.taskID); err != nil {
+		return err
+	}
+
+	if taskVersionFindFlags.version != 0 {
+		v, err := s.FindTaskVersion(context.Background(), taskID, taskVersionFindFlags.version)
+		if err != nil {
+			return err
+		}
+		writeTaskVersions(v)
+		fmt.Println()
+		fmt.Println(v.Flux)
+		return nil
+	}
+
+	vs, err := s.FindTaskVersions(context.Background(), taskID)
+	if err != nil {
+		return err
+	}
+
+	writeTaskVersions(vs...)
+	return nil
+}
+
+var taskVersionDiffFlags struct {
+	taskID   string
+	from, to int
+}
+
+func taskVersionDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the changes of the script of a task between two versions",
+		RunE:  wrapCheckSetup(taskVersionDiffF),
+	}
+
+	cmd.Flags().StringVarP(&taskVersionDiffFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().IntVarP(&taskVersionDiffFlags.to, "version", "v", 0, "version to show the changes of (required)")
+	cmd.Flags().IntVarP(&taskVersionDiffFlags.from, "from", "f", -1, "version to compare to, the previous version by default")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("version")
+
+	return cmd
+}
+
+func taskVersionDiffF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskVersionDiffFlags.taskID); err != nil {
+		return err
+	}
+
+	from := taskVersionDiffFlags.from
+	if from < 0 {
+		from = taskVersionDiffFlags.to - 1
+	}
+
+	d, err := s.DiffTaskVersions(context.Background(), taskID, from, taskVersionDiffFlags.to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("--- version %d\n+++ version %d\n", d.From, d.To)
+	fmt.Print(d.Diff)
+	return nil
+}
+
+var taskVersionRestoreFlags struct {
+	taskID      string
+	version     int
+	description string
+}
+
+func taskVersionRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Update a task with the script of one of its versions",
+		RunE:  wrapCheckSetup(taskVersionRestoreF),
+	}
+
+	cmd.Flags().StringVarP(&taskVersionRestoreFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().IntVarP(&taskVersionRestoreFlags.version, "version", "v", 0, "version to restore (required)")
+	cmd.Flags().StringVarP(&taskVersionRestoreFlags.description, "description", "m", "", "description recorded with the new version")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("version")
+
+	return cmd
+}
+
+func taskVersionRestoreF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:               flags.host,
+		Token:              flags.token,
+		InsecureSkipVerify: flags.skipVerify,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskVersionRestoreFlags.taskID); err != nil {
+		return err
+	}
+
+	t, err := s.RestoreTaskVersion(context.Background(), taskID, taskVersionRestoreFlags.version, taskVersionRestoreFlags.description)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Version %d of task %s restored as version %d.\n", taskVersionRestoreFlags.version, taskID, t.Version)
+	return nil
+}
+
+func writeTaskVersions(versions ...*platform.TaskVersion) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"Version",
+		"TaskID",
+		"Name",
+		"AuthorID",
+		"CreatedAt",
+		"Description",
+	)
+	for _, v := range versions {
+		w.Write(map[string]interface{}{
+			"Version":     v.Version,
+			"TaskID":      v.TaskID,
+			"Name":        v.Options.Name,
+			"AuthorID":    v.AuthorID,
+			"CreatedAt":   v.CreatedAt.Format(time.RFC3339),
+			"Description": v.Description,
+		})
+	}
+	w.Flush()
+}
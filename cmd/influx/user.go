@@ -36,6 +36,7 @@ type cmdUserBuilder struct {
 
 	id       string
 	name     string
+	oauthID  string
 	password string
 	org      organization
 }
@@ -86,6 +87,7 @@ func (b *cmdUserBuilder) cmdUpdate() *cobra.Command {
 
 	cmd.Flags().StringVarP(&b.id, "id", "i", "", "The user ID (required)")
 	cmd.Flags().StringVarP(&b.name, "name", "n", "", "The user name")
+	cmd.Flags().StringVar(&b.oauthID, "oauth-id", "", "The subject the identity provider identifies the user with, to link the user to the identity provider")
 	cmd.MarkFlagRequired("id")
 
 	return cmd
@@ -177,6 +179,9 @@ func (b *cmdUserBuilder) cmdUpdateRunEFn(cmd *cobra.Command, args []string) erro
 	if b.name != "" {
 		update.Name = &b.name
 	}
+	if b.oauthID != "" {
+		update.OAuthID = &b.oauthID
+	}
 
 	user, err := dep.userSVC.UpdateUser(context.Background(), id, update)
 	if err != nil {
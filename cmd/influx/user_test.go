@@ -288,9 +288,11 @@ func TestCmdUser(t *testing.T) {
 				flags: []string{
 					"--id=" + influxdb.ID(3).String(),
 					"--name=new name",
+					"--oauth-id=1234",
 				},
 				expected: influxdb.UserUpdate{
-					Name: strPtr("new name"),
+					Name:    strPtr("new name"),
+					OAuthID: strPtr("1234"),
 				},
 			},
 			{
@@ -34,9 +34,13 @@ import (
 	"github.com/influxdata/influxdb/kv"
 	influxlogger "github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/nats"
+	"github.com/influxdata/influxdb/notification/delivery"
+	"github.com/influxdata/influxdb/notification/escalation"
+	"github.com/influxdata/influxdb/oidc"
 	"github.com/influxdata/influxdb/pkger"
 	infprom "github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/query"
+	querycache "github.com/influxdata/influxdb/query/cache"
 	"github.com/influxdata/influxdb/query/control"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 	"github.com/influxdata/influxdb/snowflake"
@@ -45,9 +49,11 @@ import (
 	"github.com/influxdata/influxdb/storage/reads"
 	"github.com/influxdata/influxdb/storage/readservice"
 	taskbackend "github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/backfill"
 	"github.com/influxdata/influxdb/task/backend/coordinator"
 	"github.com/influxdata/influxdb/task/backend/executor"
 	"github.com/influxdata/influxdb/task/backend/middleware"
+	"github.com/influxdata/influxdb/task/backend/notify"
 	"github.com/influxdata/influxdb/task/backend/scheduler"
 	"github.com/influxdata/influxdb/telemetry"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1" // needed for tsi1
@@ -198,6 +204,122 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP: &l.oidcConfig.Issuer,
+			Flag:  "oauth-issuer",
+			Desc:  "URL of the OpenID Connect provider users sign in with; its endpoints are discovered from /.well-known/openid-configuration",
+		},
+		{
+			DestP: &l.oidcConfig.AuthURL,
+			Flag:  "oauth-auth-url",
+			Desc:  "authorization endpoint of the OAuth2 provider users sign in with, when it is not discovered from the OpenID Connect issuer",
+		},
+		{
+			DestP: &l.oidcConfig.TokenURL,
+			Flag:  "oauth-token-url",
+			Desc:  "token endpoint of the OAuth2 provider, when it is not discovered from the OpenID Connect issuer",
+		},
+		{
+			DestP: &l.oidcConfig.UserInfoURL,
+			Flag:  "oauth-userinfo-url",
+			Desc:  "user info endpoint of the OAuth2 provider, when it is not discovered from the OpenID Connect issuer",
+		},
+		{
+			DestP: &l.oidcConfig.ClientID,
+			Flag:  "oauth-client-id",
+			Desc:  "client id registered with the identity provider; enables signing in through /api/v2/signin/oauth",
+		},
+		{
+			DestP: &l.oidcConfig.ClientSecret,
+			Flag:  "oauth-client-secret",
+			Desc:  "client secret registered with the identity provider",
+		},
+		{
+			DestP: &l.oidcConfig.RedirectURL,
+			Flag:  "oauth-redirect-url",
+			Desc:  "URL the identity provider redirects users back to, for example: https://influxdb.example.com/api/v2/signin/oauth/callback",
+		},
+		{
+			DestP: &l.oidcConfig.Scopes,
+			Flag:  "oauth-scopes",
+			Desc:  "scopes requested from the identity provider. The default is openid, profile and email",
+		},
+		{
+			DestP:   &l.oidcConfig.UsernameClaim,
+			Flag:    "oauth-username-claim",
+			Default: oidc.DefaultUsernameClaim,
+			Desc:    "claim the user name is read from; sign-ins without it fail, and an email claim must be verified",
+		},
+		{
+			DestP:   &l.oidcConfig.GroupsClaim,
+			Flag:    "oauth-groups-claim",
+			Default: oidc.DefaultGroupsClaim,
+			Desc:    "claim the groups of the user are read from",
+		},
+		{
+			DestP: &l.oidcGroupMappings,
+			Flag:  "oauth-group-mapping",
+			Desc:  "grants the members of an identity provider group a role in an organization, in the form <group>:<org name>:<owner|member|role name>; leaving the group does not revoke the role",
+		},
+		{
+			DestP:   &l.oidcConfig.AutoProvision,
+			Flag:    "oauth-auto-provision",
+			Default: false,
+			Desc:    "create users signing in through the identity provider for the first time; otherwise users must be linked to the identity provider with their oauth id",
+		},
+		{
+			DestP:   &l.scanLimits.Query.MaxScannedValues,
+			Flag:    "query-max-scanned-values",
+			Default: 0,
+			Desc:    "maximum number of values a single query may scan. 0 means unlimited",
+		},
+		{
+			DestP:   &l.scanLimits.Query.MaxSeries,
+			Flag:    "query-max-series",
+			Default: 0,
+			Desc:    "maximum number of series a single query may read. 0 means unlimited",
+		},
+		{
+			DestP:   &l.scanLimits.Task.MaxScannedValues,
+			Flag:    "task-query-max-scanned-values",
+			Default: 0,
+			Desc:    "maximum number of values a single task query may scan. 0 means unlimited",
+		},
+		{
+			DestP:   &l.scanLimits.Task.MaxSeries,
+			Flag:    "task-query-max-series",
+			Default: 0,
+			Desc:    "maximum number of series a single task query may read. 0 means unlimited",
+		},
+		{
+			DestP:   &l.scanLimits.Check.MaxScannedValues,
+			Flag:    "check-query-max-scanned-values",
+			Default: 0,
+			Desc:    "maximum number of values a single check or notification rule query may scan. 0 means unlimited",
+		},
+		{
+			DestP:   &l.scanLimits.Check.MaxSeries,
+			Flag:    "check-query-max-series",
+			Default: 0,
+			Desc:    "maximum number of series a single check or notification rule query may read. 0 means unlimited",
+		},
+		{
+			DestP: &l.orgScanLimits,
+			Flag:  "query-org-scan-limits",
+			Desc:  "per organization scan limits that override the defaults, in the form <org-id>:<query|task|check>:<max-scanned-values>:<max-series>",
+		},
+		{
+			DestP:   &l.queryCacheMaxBytes,
+			Flag:    "query-cache-max-bytes",
+			Default: 0,
+			Desc:    "maximum total size of the cached Flux query results. 0 disables the query result cache",
+		},
+		{
+			DestP:   &l.queryCacheInterval,
+			Flag:    "query-cache-interval",
+			Default: querycache.DefaultInterval,
+			Desc:    "refresh interval of the query result cache; the now time of cached queries is truncated to it",
+		},
 		{
 			DestP: &vaultConfig.Address,
 			Flag:  "vault-addr",
@@ -248,6 +370,17 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Flag:  "vault-token",
 			Desc:  "vault authentication token",
 		},
+		{
+			DestP: &l.schedulerInstance,
+			Flag:  "task-scheduler-instance",
+			Desc:  "unique name of this instance among the instances sharing the metadata store; when set, tasks are divided among the instances through leases",
+		},
+		{
+			DestP:   &l.schedulerLeaseDuration,
+			Flag:    "task-scheduler-lease-duration",
+			Default: scheduler.DefaultLeaseDuration,
+			Desc:    "time after which the tasks of an instance that stopped renewing its leases are taken over by the other instances",
+		},
 		{
 			DestP:   &l.httpTLSCert,
 			Flag:    "tls-cert",
@@ -279,6 +412,18 @@ type Launcher struct {
 	sessionLength        int // in minutes
 	sessionRenewDisabled bool
 
+	oidcConfig        oidc.Config
+	oidcGroupMappings []string
+
+	scanLimits    query.ScanLimitsConfig
+	orgScanLimits []string
+
+	queryCacheMaxBytes int
+	queryCacheInterval time.Duration
+
+	schedulerInstance      string
+	schedulerLeaseDuration time.Duration
+
 	logLevel          string
 	tracingType       string
 	reportingDisabled bool
@@ -304,7 +449,9 @@ type Launcher struct {
 	natsPort   int
 
 	scheduler          *scheduler.TreeScheduler
+	leasedScheduler    *scheduler.LeasedScheduler
 	executor           *executor.Executor
+	backfills          *backfill.Service
 	taskControlService taskbackend.TaskControlService
 
 	jaegerTracerCloser io.Closer
@@ -369,7 +516,14 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 
 	m.log.Info("Stopping", zap.String("service", "task"))
 
+	if m.leasedScheduler != nil {
+		if err := m.leasedScheduler.Stop(ctx); err != nil {
+			m.log.Info("Failed releasing task scheduler leases", zap.Error(err))
+		}
+	}
 	m.scheduler.Stop()
+	m.backfills.Close()
+	m.executor.Close()
 
 	m.log.Info("Stopping", zap.String("service", "nats"))
 	m.natsServer.Close()
@@ -514,6 +668,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		infprom.NewInfluxCollector(m.boltClient, info),
 	)
 	m.reg.MustRegister(m.boltClient)
+	m.reg.MustRegister(infprom.NewAuthorizationExpiryCollector(m.kvService, infprom.DefaultExpiryWindow))
 
 	var (
 		orgSvc                    platform.OrganizationService             = m.kvService
@@ -563,13 +718,34 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	// The query cache is created before the engine and the query controller, so that
+	// the writes and deletes of both, including those of Flux queries, invalidate it.
+	var queryCache *querycache.ProxyQueryService
+	engineOpts := []storage.Option{storage.WithRetentionEnforcer(bucketSvc)}
+	if m.queryCacheMaxBytes > 0 {
+		queryCache = querycache.NewProxyQueryService(
+			m.log.With(zap.String("service", "query-cache")),
+			querycache.Config{
+				MaxBytes: int64(m.queryCacheMaxBytes),
+				Interval: m.queryCacheInterval,
+			},
+			nil,
+			bucketSvc,
+		)
+		m.reg.MustRegister(queryCache.PrometheusCollectors()...)
+		// Data expired by the retention enforcer is deleted from the cache as well.
+		engineOpts = append(engineOpts, storage.WithRetentionEnforcerDeleter(func(d storage.Deleter) storage.Deleter {
+			return &querycache.Deleter{Deleter: d, Cache: queryCache}
+		}))
+	}
+
 	if m.testing {
 		// the testing engine will write/read into a temporary directory
-		engine := NewTemporaryEngine(m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		engine := NewTemporaryEngine(m.StorageConfig, engineOpts...)
 		flushers = append(flushers, engine)
 		m.engine = engine
 	} else {
-		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, engineOpts...)
 	}
 	m.engine.WithLogger(m.log)
 	if err := m.engine.Open(ctx); err != nil {
@@ -584,6 +760,12 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		pointsWriter  storage.PointsWriter   = m.engine
 		backupService platform.BackupService = m.engine
 	)
+	if queryCache != nil {
+		// Writes invalidate the cached results of the buckets they write to.
+		pointsWriter = &querycache.PointsWriter{PointsWriter: pointsWriter, Cache: queryCache}
+		// So do deletes.
+		deleteService = &querycache.DeleteService{DeleteService: deleteService, Cache: queryCache}
+	}
 
 	// TODO(cwolff): Figure out a good default per-query memory limit:
 	//   https://github.com/influxdata/influxdb/issues/13642
@@ -593,9 +775,18 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		QueueSize                = 10
 	)
 
+	for _, s := range m.orgScanLimits {
+		l, err := query.ParseOrgScanLimits(s)
+		if err != nil {
+			m.log.Error("Failed to parse organization scan limits", zap.Error(err))
+			return err
+		}
+		m.scanLimits.Orgs = append(m.scanLimits.Orgs, l)
+	}
+
 	deps, err := influxdb.NewDependencies(
 		reads.NewReader(readservice.NewStore(m.engine)),
-		m.engine,
+		pointsWriter,
 		authorizer.NewBucketService(bucketSvc),
 		authorizer.NewOrgService(orgSvc),
 		authorizer.NewSecretService(secretSvc),
@@ -612,6 +803,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		QueueSize:                QueueSize,
 		Logger:                   m.log.With(zap.String("service", "storage-reads")),
 		ExecutorDependencies:     []flux.Dependency{deps},
+		ScanLimits:               m.scanLimits,
 	})
 	if err != nil {
 		m.log.Error("Failed to create query controller", zap.Error(err))
@@ -620,8 +812,17 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	m.reg.MustRegister(m.queryController.PrometheusCollectors()...)
 
-	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
-	var taskSvc platform.TaskService
+	var storageQueryService query.ProxyQueryService = readservice.NewProxyQueryService(m.queryController)
+	if queryCache != nil {
+		queryCache.SetProxyQueryService(storageQueryService)
+		storageQueryService = queryCache
+	}
+	var (
+		taskSvc       platform.TaskService
+		taskScheduler scheduler.Scheduler
+		notifier      = notify.NewService(m.log.With(zap.String("service", "task-notify")), notificationEndpointStore, secretSvc)
+		deliverer     = delivery.NewService(m.log.With(zap.String("service", "notification-delivery")), m.kvService, m.kvService, pointsWriter, query.QueryServiceBridge{AsyncQueryService: m.queryController})
+	)
 	{
 		// create the task stack
 		combinedTaskService := taskbackend.NewAnalyticalStorage(m.log.With(zap.String("service", "task-analytical-store")), m.kvService, m.kvService, m.kvService, pointsWriter, query.QueryServiceBridge{AsyncQueryService: m.queryController})
@@ -635,11 +836,57 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		)
 		m.executor = executor
 		m.reg.MustRegister(executorMetrics.PrometheusCollectors()...)
+		executor.SetNotifier(notifier)
+		executor.SetSilenceService(m.kvService)
+		executor.SetAlertTracker(m.kvService)
+		executor.SetNotificationDeliverer(deliverer)
 		schLogger := m.log.With(zap.String("service", "task-scheduler"))
 
+		var (
+			schExecutor     scheduler.Executor           = executor
+			schCheckpointer scheduler.SchedulableService = taskbackend.NewSchedulableTaskService(m.kvService)
+		)
+		if m.schedulerInstance != "" {
+			// the tasks are divided among the instances sharing the metadata store.
+			leased, err := scheduler.NewLeasedScheduler(
+				m.schedulerInstance,
+				m.kvService,
+				coordinator.NewTaskLister(schLogger, m.kvService),
+				executor,
+				schCheckpointer,
+				scheduler.WithLeaseDuration(m.schedulerLeaseDuration),
+				scheduler.WithLeaseOnErrorFn(func(ctx context.Context, taskID scheduler.ID, scheduledAt time.Time, err error) {
+					schLogger.Info(
+						"error in scheduler leases",
+						zap.String("taskID", platform.ID(taskID).String()),
+						zap.Error(err))
+				}),
+				scheduler.WithResumeFn(func(ctx context.Context, taskID scheduler.ID) error {
+					runs, err := combinedTaskService.CurrentlyRunning(ctx, platform.ID(taskID))
+					if err != nil {
+						return err
+					}
+					for _, r := range runs {
+						if _, err := executor.ResumeCurrentRun(ctx, r.TaskID, r.ID); err != nil {
+							return err
+						}
+					}
+					return nil
+				}),
+			)
+			if err != nil {
+				m.log.Fatal("could not start task scheduler leases", zap.Error(err))
+			}
+			m.leasedScheduler = leased
+			schExecutor, schCheckpointer = leased, leased
+		}
+
+		chain := scheduler.NewChain()
+		executor.SetChain(chain)
 		sch, sm, err := scheduler.NewScheduler(
-			executor,
-			taskbackend.NewSchedulableTaskService(m.kvService),
+			schExecutor,
+			schCheckpointer,
+			scheduler.WithChain(chain),
 			scheduler.WithOnErrorFn(func(ctx context.Context, taskID scheduler.ID, scheduledAt time.Time, err error) {
 				schLogger.Info(
 					"error in scheduler run",
@@ -653,15 +900,24 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		}
 		m.scheduler = sch
 		m.reg.MustRegister(sm.PrometheusCollectors()...)
+		taskScheduler = sch
+		if m.leasedScheduler != nil {
+			m.leasedScheduler.SetScheduler(sch)
+			taskScheduler = m.leasedScheduler
+		}
 		coordLogger := m.log.With(zap.String("service", "task-coordinator"))
 		taskCoord := coordinator.NewCoordinator(
 			coordLogger,
-			sch,
+			taskScheduler,
 			executor)
 
 		taskSvc = middleware.New(combinedTaskService, taskCoord)
+		m.backfills = backfill.NewService(m.log.With(zap.String("service", "task-backfill")), combinedTaskService, executor)
 		m.taskControlService = combinedTaskService
-		if err := taskbackend.TaskNotifyCoordinatorOfExisting(
+		if m.leasedScheduler != nil {
+			// the leased scheduler schedules the existing tasks of the partitions it acquires and resumes their runs.
+			m.leasedScheduler.Start(ctx)
+		} else if err := taskbackend.TaskNotifyCoordinatorOfExisting(
 			ctx,
 			taskSvc,
 			combinedTaskService,
@@ -677,16 +933,31 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	var checkSvc platform.CheckService
 	{
-		coordinator := coordinator.NewCoordinator(m.log, m.scheduler, m.executor)
+		coordinator := coordinator.NewCoordinator(m.log, taskScheduler, m.executor)
 		checkSvc = middleware.NewCheckService(m.kvService, m.kvService, coordinator)
 	}
 
 	var notificationRuleSvc platform.NotificationRuleStore
 	{
-		coordinator := coordinator.NewCoordinator(m.log, m.scheduler, m.executor)
+		coordinator := coordinator.NewCoordinator(m.log, taskScheduler, m.executor)
 		notificationRuleSvc = middleware.NewNotificationRuleStore(m.kvService, m.kvService, coordinator)
 	}
 
+	// the alerts nobody acknowledged in time are escalated following the policies of notification rules.
+	escalator := escalation.NewEscalator(m.log, m.kvService, m.kvService, m.kvService, taskSvc, notifier)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		escalator.Run(ctx)
+	}()
+
+	// the notifications failing to be delivered are retried until they are delivered or dropped.
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		deliverer.Run(ctx)
+	}()
+
 	// NATS streaming server
 	natsOpts := nats.NewDefaultServerOptions()
 
@@ -761,10 +1032,41 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		log.Info("Stopping")
 	}(m.log)
 
+	var oauthSvc platform.OAuthService
+	if m.oidcConfig.ClientID != "" {
+		for _, s := range m.oidcGroupMappings {
+			gm, err := oidc.ParseGroupMapping(s)
+			if err != nil {
+				m.log.Error("Failed to parse identity provider group mapping", zap.Error(err))
+				return err
+			}
+			m.oidcConfig.GroupMappings = append(m.oidcConfig.GroupMappings, gm)
+		}
+
+		svc, err := oidc.NewService(ctx, m.oidcConfig,
+			oidc.WithLogger(m.log.With(zap.String("service", "oidc"))),
+			oidc.WithUserSVC(m.kvService),
+			oidc.WithOrganizationSVC(m.kvService),
+			oidc.WithUserResourceMappingSVC(m.kvService),
+			oidc.WithRoleSVC(m.kvService),
+		)
+		if err != nil {
+			m.log.Error("Failed to configure the identity provider", zap.Error(err))
+			return err
+		}
+		oauthSvc = svc
+	}
+
 	m.httpServer = &nethttp.Server{
 		Addr: m.httpBindAddress,
 	}
 
+	var apiBucketSvc platform.BucketService = storage.NewBucketService(bucketSvc, m.engine)
+	if queryCache != nil {
+		// Deleting buckets or changing their retention invalidates their cached results.
+		apiBucketSvc = &querycache.BucketService{BucketService: apiBucketSvc, Cache: queryCache}
+	}
+
 	m.apibackend = &http.APIBackend{
 		AssetsPath:           m.assetsPath,
 		HTTPErrorHandler:     kithttp.ErrorHandler(0),
@@ -777,8 +1079,9 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		BackupService:        backupService,
 		KVBackupService:      m.kvService,
 		AuthorizationService: authSvc,
+		AuthorizationRotator: m.kvService,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
-		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
+		BucketService:                   apiBucketSvc,
 		SessionService:                  sessionSvc,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
@@ -791,11 +1094,20 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		OrganizationOperationLogService: orgLogSvc,
 		SourceService:                   sourceSvc,
 		VariableService:                 variableSvc,
+		SilenceService:                  m.kvService,
+		AlertService:                    m.kvService,
+		RoleService:                     m.kvService,
 		PasswordsService:                passwdsSvc,
+		OAuthService:                    oauthSvc,
 		OnboardingService:               onboardingSvc,
 		InfluxQLService:                 storageQueryService,
 		FluxService:                     storageQueryService,
 		TaskService:                     taskSvc,
+		TaskBackfillService:             m.backfills,
+		TaskVersionService:              m.kvService,
+		TaskDryRunService:               m.executor,
+		MonitorEvaluationService:        m.executor,
+		DeliveryLog:                     deliverer,
 		TelegrafService:                 telegrafSvc,
 		NotificationRuleStore:           notificationRuleSvc,
 		NotificationEndpointService:     endpoints.NewService(notificationEndpointStore, secretSvc, userResourceSvc, orgSvc),
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	nethttp "net/http"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -153,6 +154,255 @@ func TestPipeline_QueryMemoryLimits(t *testing.T) {
 	}
 }
 
+// This test starts a launcher with scan limits, writes more values and series
+// than a query may read and checks that queries are aborted by the limits.
+func TestPipeline_QueryScanLimits(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx,
+		"--query-max-scanned-values", "150",
+		"--query-max-series", "2",
+	)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	var points []string
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		points = append(points, fmt.Sprintf("m,k=v1 f=%di %d", i, now.Add(-time.Duration(i)*time.Second).UnixNano()))
+		points = append(points, fmt.Sprintf("m,k=v2 f=%di %d", i, now.Add(-time.Duration(i)*time.Second).UnixNano()))
+		points = append(points, fmt.Sprintf("n,k=v1 f=%di %d", i, now.Add(-time.Duration(i)*time.Second).UnixNano()))
+	}
+	l.WritePointsOrFail(t, strings.Join(points, "\n"))
+
+	for _, tt := range []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "within limits",
+			query: `from(bucket:"%s") |> range(start:-5m) |> filter(fn: (r) => r._measurement == "m" and r.k == "v1")`,
+		},
+		{
+			name:    "max scanned values",
+			query:   `from(bucket:"%s") |> range(start:-5m) |> filter(fn: (r) => r._measurement == "m")`,
+			wantErr: "query-max-scanned-values",
+		},
+		{
+			name:    "max series",
+			query:   `from(bucket:"%s") |> range(start:-10s)`,
+			wantErr: "query-max-series",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, err := flux.Parse(fmt.Sprintf(tt.query, l.Bucket.Name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := &query.Request{
+				Authorization:  l.Auth,
+				OrganizationID: l.Org.ID,
+				Compiler: lang.ASTCompiler{
+					AST: pkg,
+				},
+			}
+			err = l.QueryAndNopConsume(context.Background(), req)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected query to be aborted by %s, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// This test starts a launcher with the query result cache enabled and checks
+// that repeated queries are served from the cache until the bucket is written to.
+func TestPipeline_QueryCache(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx,
+		"--query-cache-max-bytes", "1048576",
+		"--query-cache-interval", "1h",
+	)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	now := time.Now()
+	l.WritePointsOrFail(t, fmt.Sprintf("m,k=v1 f=1i %d", now.UnixNano()))
+
+	q := fmt.Sprintf(`from(bucket:"%s") |> range(start:-3h, stop: 1h) |> count()`, l.Bucket.Name)
+	doQuery := func(t *testing.T) (string, string) {
+		t.Helper()
+		req := l.NewHTTPRequestOrFail(t, "POST", "/api/v2/query?orgID="+l.Org.ID.String(), l.Auth.Token, q)
+		req.Header.Set("Content-Type", "application/vnd.flux")
+		resp, err := nethttp.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body bytes.Buffer
+		if _, err := io.Copy(&body, resp.Body); err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != nethttp.StatusOK {
+			t.Fatalf("unexpected status %d: %s", resp.StatusCode, body.String())
+		}
+		return resp.Header.Get("Query-Cache-Status"), body.String()
+	}
+
+	status, first := doQuery(t)
+	if status != "miss" {
+		t.Fatalf("expected a miss, got %q", status)
+	}
+	status, second := doQuery(t)
+	if status != "hit" {
+		t.Fatalf("expected a hit, got %q", status)
+	}
+	if first != second {
+		t.Errorf("unexpected cached result; got %q, want %q", second, first)
+	}
+
+	// Writing to the bucket invalidates the cached result.
+	l.WritePointsOrFail(t, fmt.Sprintf("m,k=v1 f=2i %d", now.Add(-time.Second).UnixNano()))
+	status, third := doQuery(t)
+	if status != "miss" {
+		t.Fatalf("expected a miss after writing to the bucket, got %q", status)
+	}
+	if third == first {
+		t.Errorf("expected the result to include the new point, got %q", third)
+	}
+}
+
+func TestPipeline_PromQL(t *testing.T) {
+	l := launcher.RunTestLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	// Counters increasing by 0.1/s and 0.2/s scraped every 15s.
+	end := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	var lines []string
+	for i := 0; i <= 40; i++ {
+		ts := end.Add(time.Duration(i-40) * 15 * time.Second).UnixNano()
+		lines = append(lines,
+			fmt.Sprintf("prometheus,job=api http_requests_total=%v %d", 1.5*float64(i), ts),
+			fmt.Sprintf("prometheus,job=web http_requests_total=%v %d", 3*float64(i), ts),
+			fmt.Sprintf("prometheus,le=0.1 latency_bucket=%v %d", 1*float64(i), ts),
+			fmt.Sprintf("prometheus,le=1 latency_bucket=%v %d", 3*float64(i), ts),
+			fmt.Sprintf("prometheus,le=+Inf latency_bucket=%v %d", 4*float64(i), ts),
+		)
+	}
+	l.WritePointsOrFail(t, strings.Join(lines, "\n"))
+
+	for _, tt := range []struct {
+		name   string
+		path   string
+		params url.Values
+		status int
+		want   string
+	}{
+		{
+			name:   "vector",
+			path:   "/api/v1/query",
+			params: url.Values{"query": {`http_requests_total`}, "time": {"1572566400"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"http_requests_total","job":"api"},"value":[1572566400,"60"]},{"metric":{"__name__":"http_requests_total","job":"web"},"value":[1572566400,"120"]}]}}`,
+		},
+		{
+			name:   "matrix",
+			path:   "/api/v1/query",
+			params: url.Values{"query": {`http_requests_total{job=~"a.*"}[1m]`}, "time": {"1572566400"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"http_requests_total","job":"api"},"values":[[1572566355,"55.5"],[1572566370,"57"],[1572566385,"58.5"],[1572566400,"60"]]}]}}`,
+		},
+		{
+			name:   "irate",
+			path:   "/api/v1/query",
+			params: url.Values{"query": {`irate(http_requests_total[5m])`}, "time": {"1572566400"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"api"},"value":[1572566400,"0.1"]},{"metric":{"job":"web"},"value":[1572566400,"0.2"]}]}}`,
+		},
+		{
+			name:   "histogram_quantile",
+			path:   "/api/v1/query",
+			params: url.Values{"query": {`histogram_quantile(0.5, rate(latency_bucket[5m]))`}, "time": {"1572566400"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1572566400,"0.5499999999999999"]}]}}`,
+		},
+		{
+			name:   "vector and scalar",
+			path:   "/api/v1/query",
+			params: url.Values{"query": {`http_requests_total / 3 > 15`}, "time": {"1572566400"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"api"},"value":[1572566400,"20"]},{"metric":{"job":"web"},"value":[1572566400,"40"]}]}}`,
+		},
+		{
+			name:   "vector and vector",
+			path:   "/api/v1/query",
+			params: url.Values{"query": {`http_requests_total - http_requests_total offset 1m`}, "time": {"1572566400"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"api"},"value":[1572566400,"6"]},{"metric":{"job":"web"},"value":[1572566400,"12"]}]}}`,
+		},
+		{
+			name:   "topk",
+			path:   "/api/v1/query",
+			params: url.Values{"query": {`topk(1, http_requests_total)`}, "time": {"1572566400"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"http_requests_total","job":"web"},"value":[1572566400,"120"]}]}}`,
+		},
+		{
+			name:   "scalar",
+			path:   "/api/v1/query",
+			params: url.Values{"query": {`1 + 2`}, "time": {"1572566400"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"scalar","result":[1572566400,"3"]}}`,
+		},
+		{
+			name:   "range",
+			path:   "/api/v1/query_range",
+			params: url.Values{"query": {`http_requests_total`}, "start": {"1572566100"}, "end": {"1572566400"}, "step": {"60"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"http_requests_total","job":"api"},"values":[[1572566100,"30"],[1572566160,"36"],[1572566220,"42"],[1572566280,"48"],[1572566340,"54"],[1572566400,"60"]]},{"metric":{"__name__":"http_requests_total","job":"web"},"values":[[1572566100,"60"],[1572566160,"72"],[1572566220,"84"],[1572566280,"96"],[1572566340,"108"],[1572566400,"120"]]}]}}`,
+		},
+		{
+			name:   "range rate",
+			path:   "/api/v1/query_range",
+			params: url.Values{"query": {`sum by (job) (rate(http_requests_total[2m]))`}, "start": {"1572566100"}, "end": {"1572566400"}, "step": {"1m"}},
+			status: nethttp.StatusOK,
+			want:   `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"api"},"values":[[1572566100,"0.1"],[1572566160,"0.1"],[1572566220,"0.1"],[1572566280,"0.1"],[1572566340,"0.1"],[1572566400,"0.1"]]},{"metric":{"job":"web"},"values":[[1572566100,"0.2"],[1572566160,"0.2"],[1572566220,"0.2"],[1572566280,"0.2"],[1572566340,"0.2"],[1572566400,"0.2"]]}]}}`,
+		},
+		{
+			name:   "bad data",
+			path:   "/api/v1/query",
+			params: url.Values{"query": {`rate(http_requests_total)`}},
+			status: nethttp.StatusBadRequest,
+			want:   `{"status":"error","errorType":"bad_data","error":"expected type range vector in call to function \"rate\""}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.params.Set("bucket", l.Bucket.Name)
+			tt.params.Set("orgID", l.Org.ID.String())
+			req := l.NewHTTPRequestOrFail(t, "GET", tt.path+"?"+tt.params.Encode(), l.Auth.Token, "")
+			resp, err := nethttp.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var body bytes.Buffer
+			if _, err := io.Copy(&body, resp.Body); err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("unexpected status %d: %s", resp.StatusCode, body.String())
+			}
+			if got := strings.TrimSpace(body.String()); got != tt.want {
+				t.Errorf("unexpected response:\ngot  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPipeline_Query_LoadSecret_Success(t *testing.T) {
 	l := launcher.RunTestLauncherOrFail(t, ctx)
 	l.SetupOrFail(t)
@@ -0,0 +1,119 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ErrQueuedDeliveryNotFound is the error msg for a missing queued delivery.
+const ErrQueuedDeliveryNotFound = "queued delivery not found"
+
+// ops for notification deliveries.
+const (
+	OpDeliverNotifications = "DeliverNotifications"
+	OpQueueDelivery        = "QueueDelivery"
+	OpFindDueDeliveries    = "FindDueDeliveries"
+	OpDeleteQueuedDelivery = "DeleteQueuedDelivery"
+	OpFindDeliveryAttempts = "FindDeliveryAttempts"
+)
+
+// DeliveryMeasurement is the measurement of the delivery attempts recorded in the _monitoring bucket.
+const DeliveryMeasurement = "deliveries"
+
+// limits of the delivery attempts returned at once.
+const (
+	DefaultDeliveryAttemptsLimit = 100
+	MaxDeliveryAttemptsLimit     = 500
+)
+
+// statuses of delivery attempts.
+const (
+	// DeliveryDelivered is the status of the attempt that delivered a notification.
+	DeliveryDelivered = "delivered"
+	// DeliveryRetrying is the status of a failed attempt, the notification is queued to be retried.
+	DeliveryRetrying = "retrying"
+	// DeliveryFailed is the status of the last failed attempt, the notification is dropped.
+	DeliveryFailed = "failed"
+)
+
+// NotificationDeliverer delivers the notifications generated by the runs of the tasks of notification rules.
+type NotificationDeliverer interface {
+	// DeliverNotifications sends the notifications to their endpoints and records them and their delivery attempts
+	// in the _monitoring bucket. The notifications failing to be delivered are queued to be retried.
+	DeliverNotifications(ctx context.Context, ns []RuleNotification) error
+}
+
+// DeliveryLog describes a service for finding the delivery attempts of the notifications of notification rules.
+type DeliveryLog interface {
+	// FindDeliveryAttempts returns the delivery attempts matching the filter and their count, the latest first.
+	FindDeliveryAttempts(ctx context.Context, filter DeliveryAttemptFilter) ([]*DeliveryAttempt, int, error)
+}
+
+// DeliveryQueue is a durable queue of the notifications to retry the delivery of.
+type DeliveryQueue interface {
+	// QueueDelivery adds the delivery to the queue, or replaces it once it has an ID.
+	QueueDelivery(ctx context.Context, d *QueuedDelivery) error
+
+	// FindDueDeliveries returns at most limit deliveries due at now, the earliest due first.
+	FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*QueuedDelivery, error)
+
+	// DeleteQueuedDelivery removes a delivery from the queue by its ID.
+	DeleteQueuedDelivery(ctx context.Context, id ID) error
+}
+
+// NotificationRequest is the HTTP request delivering a notification to its endpoint, as built by the flux package of the endpoint.
+type NotificationRequest struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+// RuleNotification is a notification generated by a run of the task of a notification rule.
+type RuleNotification struct {
+	OrgID      ID
+	RuleID     ID
+	EndpointID ID
+	Request    NotificationRequest
+	// Measurement, Tags, Fields and Time make up the record of the notification in the _monitoring bucket.
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]interface{}
+	Time        time.Time
+}
+
+// QueuedDelivery is a notification which delivery failed, to be retried.
+type QueuedDelivery struct {
+	ID            ID                  `json:"id,omitempty"`
+	OrgID         ID                  `json:"orgID"`
+	RuleID        ID                  `json:"ruleID"`
+	EndpointID    ID                  `json:"endpointID"`
+	Request       NotificationRequest `json:"request"`
+	Attempts      int                 `json:"attempts"`
+	NextAttemptAt time.Time           `json:"nextAttemptAt"`
+	CreatedAt     time.Time           `json:"createdAt"`
+}
+
+// DeliveryAttempt is an attempt to deliver a notification of a notification rule to a notification endpoint.
+type DeliveryAttempt struct {
+	Time       time.Time `json:"time"`
+	RuleID     ID        `json:"ruleID"`
+	EndpointID ID        `json:"endpointID"`
+	// DeliveryID is the ID of the queued delivery the attempt retried.
+	DeliveryID ID     `json:"deliveryID,omitempty"`
+	Attempt    int    `json:"attempt"`
+	Status     string `json:"status"`
+	// StatusCode is the status code of the response of the endpoint, 0 when it didn't respond.
+	StatusCode int      `json:"statusCode"`
+	Latency    Duration `json:"latency"`
+	// PayloadHash is the hex encoded SHA-256 hash of the body of the request.
+	PayloadHash string `json:"payloadHash"`
+	Error       string `json:"error,omitempty"`
+}
+
+// DeliveryAttemptFilter represents a set of filters that restrict the returned delivery attempts.
+type DeliveryAttemptFilter struct {
+	OrgID  ID
+	RuleID ID
+	Status *string
+	Limit  int
+}
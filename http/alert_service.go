@@ -0,0 +1,369 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pctx "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixAlerts = "/api/v2/alerts"
+)
+
+// AlertBackend is all services and associated parameters required to construct
+// the AlertHandler.
+type AlertBackend struct {
+	influxdb.HTTPErrorHandler
+	log                 *zap.Logger
+	AlertService        influxdb.AlertService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewAlertBackend creates a backend used by the alert handler.
+func NewAlertBackend(log *zap.Logger, b *APIBackend) *AlertBackend {
+	return &AlertBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		log:                 log,
+		AlertService:        b.AlertService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// AlertHandler is the handler for the alert service
+type AlertHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	AlertService        influxdb.AlertService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewAlertHandler creates a new AlertHandler
+func NewAlertHandler(log *zap.Logger, b *AlertBackend) *AlertHandler {
+	h := &AlertHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		AlertService:        b.AlertService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixAlerts)
+
+	h.HandlerFunc("GET", prefixAlerts, h.handleGetAlerts)
+	h.HandlerFunc("GET", entityPath, h.handleGetAlert)
+	h.HandlerFunc("POST", entityPath+"/acknowledge", h.handlePostAlertAcknowledge)
+	h.HandlerFunc("POST", entityPath+"/resolve", h.handlePostAlertResolve)
+
+	return h
+}
+
+type alertLinks struct {
+	Self        string `json:"self"`
+	Org         string `json:"org"`
+	Check       string `json:"check"`
+	Acknowledge string `json:"acknowledge"`
+	Resolve     string `json:"resolve"`
+}
+
+type alertResponse struct {
+	*influxdb.Alert
+	Links alertLinks `json:"links"`
+}
+
+func newAlertResponse(a *influxdb.Alert) alertResponse {
+	self := fmt.Sprintf("%s/%s", prefixAlerts, a.ID)
+	return alertResponse{
+		Alert: a,
+		Links: alertLinks{
+			Self:        self,
+			Org:         fmt.Sprintf("/api/v2/orgs/%s", a.OrgID),
+			Check:       fmt.Sprintf("/api/v2/checks/%s", a.CheckID),
+			Acknowledge: self + "/acknowledge",
+			Resolve:     self + "/resolve",
+		},
+	}
+}
+
+type getAlertsResponse struct {
+	Alerts []alertResponse       `json:"alerts"`
+	Links  *influxdb.PagingLinks `json:"links"`
+}
+
+func (r getAlertsResponse) toInfluxDB() []*influxdb.Alert {
+	as := make([]*influxdb.Alert, len(r.Alerts))
+	for i := range r.Alerts {
+		as[i] = r.Alerts[i].Alert
+	}
+	return as
+}
+
+func newGetAlertsResponse(as []*influxdb.Alert, f influxdb.AlertFilter, opts influxdb.FindOptions) getAlertsResponse {
+	resp := getAlertsResponse{
+		Alerts: make([]alertResponse, 0, len(as)),
+		Links:  newPagingLinks(prefixAlerts, opts, f, len(as)),
+	}
+	for _, a := range as {
+		resp.Alerts = append(resp.Alerts, newAlertResponse(a))
+	}
+	return resp
+}
+
+type getAlertsRequest struct {
+	filter influxdb.AlertFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetAlertsRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*getAlertsRequest, error) {
+	opts, err := decodeFindOptions(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getAlertsRequest{
+		opts: *opts,
+	}
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "orgID is invalid",
+				Err:  err,
+			}
+		}
+		req.filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = &o.ID
+	}
+
+	if checkID := qp.Get("checkID"); checkID != "" {
+		id, err := influxdb.IDFromString(checkID)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "checkID is invalid",
+				Err:  err,
+			}
+		}
+		req.filter.CheckID = id
+	}
+
+	if status := qp.Get("status"); status != "" {
+		switch status {
+		case influxdb.AlertOpen, influxdb.AlertAcknowledged, influxdb.AlertResolved:
+		default:
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("status %q is invalid, it must be one of open, acknowledged or resolved", status),
+			}
+		}
+		req.filter.Status = &status
+	}
+
+	return req, nil
+}
+
+func (h *AlertHandler) handleGetAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetAlertsRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	as, _, err := h.AlertService.FindAlerts(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Alerts retrieved", zap.Int("alerts", len(as)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetAlertsResponse(as, req.filter, req.opts)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func requestAlertID(ctx context.Context) (influxdb.ID, error) {
+	urlID := httprouter.ParamsFromContext(ctx).ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+	return *id, nil
+}
+
+func (h *AlertHandler) handleGetAlert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestAlertID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AlertService.FindAlertByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Alert retrieved", zap.String("alert", fmt.Sprint(a)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newAlertResponse(a)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// alertActionRequest is the body of the requests acknowledging or resolving an alert.
+type alertActionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// decodeAlertActionRequest decodes the ID of the alert and the optional body of the request,
+// and returns the user of the authorizer the alert is handled by.
+func decodeAlertActionRequest(ctx context.Context, r *http.Request) (id, userID influxdb.ID, comment string, err error) {
+	if id, err = requestAlertID(ctx); err != nil {
+		return
+	}
+	var req alertActionRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		err = &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+		return
+	}
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		return
+	}
+	return id, auth.GetUserID(), req.Comment, nil
+}
+
+func (h *AlertHandler) handlePostAlertAcknowledge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, userID, comment, err := decodeAlertActionRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AlertService.AcknowledgeAlert(ctx, id, userID, comment)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Alert acknowledged", zap.String("alert", fmt.Sprint(a)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newAlertResponse(a)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *AlertHandler) handlePostAlertResolve(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, userID, comment, err := decodeAlertActionRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AlertService.ResolveAlert(ctx, id, userID, comment)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Alert resolved", zap.String("alert", fmt.Sprint(a)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newAlertResponse(a)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// AlertService is an alert service over HTTP to the influxdb server
+type AlertService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.AlertService = (*AlertService)(nil)
+
+// FindAlertByID finds a single alert by its ID.
+func (s *AlertService) FindAlertByID(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	var resp alertResponse
+	err := s.Client.
+		Get(prefixAlerts, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Alert, nil
+}
+
+// FindAlerts returns the alerts matching the filter and their count.
+func (s *AlertService) FindAlerts(ctx context.Context, filter influxdb.AlertFilter, opts ...influxdb.FindOptions) ([]*influxdb.Alert, int, error) {
+	params := findOptionParams(opts...)
+	for k, vs := range filter.QueryParams() {
+		for _, v := range vs {
+			params = append(params, [2]string{k, v})
+		}
+	}
+
+	var resp getAlertsResponse
+	err := s.Client.
+		Get(prefixAlerts).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	as := resp.toInfluxDB()
+	return as, len(as), nil
+}
+
+// AcknowledgeAlert acknowledges the open alert on behalf of the user of the token of the client,
+// the user ID is ignored.
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, id, _ influxdb.ID, comment string) (*influxdb.Alert, error) {
+	return s.postAction(ctx, id, "acknowledge", comment)
+}
+
+// ResolveAlert resolves the alert on behalf of the user of the token of the client,
+// the user ID is ignored.
+func (s *AlertService) ResolveAlert(ctx context.Context, id, _ influxdb.ID, comment string) (*influxdb.Alert, error) {
+	return s.postAction(ctx, id, "resolve", comment)
+}
+
+func (s *AlertService) postAction(ctx context.Context, id influxdb.ID, action, comment string) (*influxdb.Alert, error) {
+	var resp alertResponse
+	err := s.Client.
+		PostJSON(alertActionRequest{Comment: comment}, prefixAlerts, id.String(), action).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Alert, nil
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	influxTesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func newMockAlertBackend(t *testing.T, as influxdb.AlertService) *AlertBackend {
+	return &AlertBackend{
+		HTTPErrorHandler:    kithttp.ErrorHandler(0),
+		log:                 zaptest.NewLogger(t),
+		AlertService:        as,
+		OrganizationService: mock.NewOrganizationService(),
+	}
+}
+
+func TestAlertHandler(t *testing.T) {
+	var (
+		orgID   = influxTesting.MustIDBase16("020f755c3c082001")
+		userID  = influxTesting.MustIDBase16("020f755c3c082002")
+		checkID = influxTesting.MustIDBase16("020f755c3c082003")
+		id      = influxTesting.MustIDBase16("020f755c3c082000")
+		opened  = time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+		stored  = &influxdb.Alert{
+			ID:           id,
+			OrgID:        orgID,
+			CheckID:      checkID,
+			CheckName:    "cpu",
+			Tags:         map[string]string{"host": "a"},
+			Status:       influxdb.AlertOpen,
+			Level:        "crit",
+			OpenedAt:     opened,
+			LastStatusAt: opened,
+			Events:       []influxdb.AlertEvent{{Time: opened, Type: influxdb.AlertEventOpened, Level: "crit"}},
+		}
+		open = influxdb.AlertOpen
+	)
+
+	type action struct {
+		id      influxdb.ID
+		userID  influxdb.ID
+		comment string
+	}
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		want       *influxdb.Alert
+		wantFilter *influxdb.AlertFilter
+		wantAction *action
+	}{
+		{
+			name:       "find the open alerts of a check",
+			method:     "GET",
+			path:       prefixAlerts + "?orgID=020f755c3c082001&checkID=020f755c3c082003&status=open",
+			wantStatus: http.StatusOK,
+			wantFilter: &influxdb.AlertFilter{OrgID: &orgID, CheckID: &checkID, Status: &open},
+		},
+		{
+			name:       "find alerts with an invalid status",
+			method:     "GET",
+			path:       prefixAlerts + "?status=closed",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "get an alert",
+			method:     "GET",
+			path:       prefixAlerts + "/020f755c3c082000",
+			wantStatus: http.StatusOK,
+			want:       stored,
+		},
+		{
+			name:       "acknowledge an alert",
+			method:     "POST",
+			path:       prefixAlerts + "/020f755c3c082000/acknowledge",
+			body:       `{"comment": "on it"}`,
+			wantStatus: http.StatusOK,
+			want:       stored,
+			wantAction: &action{id: id, userID: userID, comment: "on it"},
+		},
+		{
+			name:       "resolve an alert without a comment",
+			method:     "POST",
+			path:       prefixAlerts + "/020f755c3c082000/resolve",
+			wantStatus: http.StatusOK,
+			want:       stored,
+			wantAction: &action{id: id, userID: userID},
+		},
+		{
+			name:       "acknowledge an alert with an invalid body",
+			method:     "POST",
+			path:       prefixAlerts + "/020f755c3c082000/acknowledge",
+			body:       `{"comment":`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				filter *influxdb.AlertFilter
+				got    *action
+			)
+			as := mock.NewAlertService()
+			as.FindAlertByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+				return stored, nil
+			}
+			as.FindAlertsFn = func(ctx context.Context, f influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, int, error) {
+				filter = &f
+				return []*influxdb.Alert{stored}, 1, nil
+			}
+			as.AcknowledgeAlertFn = func(ctx context.Context, id, userID influxdb.ID, comment string) (*influxdb.Alert, error) {
+				got = &action{id: id, userID: userID, comment: comment}
+				return stored, nil
+			}
+			as.ResolveAlertFn = as.AcknowledgeAlertFn
+			h := NewAlertHandler(zaptest.NewLogger(t), newMockAlertBackend(t, as))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Authorization{UserID: userID}))
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.want != nil {
+				var resp alertResponse
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatal(err)
+				}
+				if !cmp.Equal(tt.want, resp.Alert) {
+					t.Errorf("unexpected alert -want/+got:\n%s", cmp.Diff(tt.want, resp.Alert))
+				}
+				if want := "/api/v2/checks/020f755c3c082003"; resp.Links.Check != want {
+					t.Errorf("unexpected check link %q, want %q", resp.Links.Check, want)
+				}
+			}
+			if tt.wantFilter != nil && !cmp.Equal(tt.wantFilter, filter) {
+				t.Errorf("unexpected filter -want/+got:\n%s", cmp.Diff(tt.wantFilter, filter))
+			}
+			if tt.wantAction != nil && !cmp.Equal(*tt.wantAction, *got, cmp.AllowUnexported(action{})) {
+				t.Errorf("unexpected action %+v, want %+v", *got, *tt.wantAction)
+			}
+		})
+	}
+}
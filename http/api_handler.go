@@ -55,6 +55,7 @@ type APIBackend struct {
 	BackupService                   influxdb.BackupService
 	KVBackupService                 influxdb.KVBackupService
 	AuthorizationService            influxdb.AuthorizationService
+	AuthorizationRotator            influxdb.AuthorizationRotator
 	BucketService                   influxdb.BucketService
 	SessionService                  influxdb.SessionService
 	UserService                     influxdb.UserService
@@ -68,11 +69,20 @@ type APIBackend struct {
 	OrganizationOperationLogService influxdb.OrganizationOperationLogService
 	SourceService                   influxdb.SourceService
 	VariableService                 influxdb.VariableService
+	SilenceService                  influxdb.SilenceService
+	AlertService                    influxdb.AlertService
+	RoleService                     influxdb.RoleService
 	PasswordsService                influxdb.PasswordsService
+	OAuthService                    influxdb.OAuthService
 	OnboardingService               influxdb.OnboardingService
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
 	TaskService                     influxdb.TaskService
+	TaskBackfillService             influxdb.TaskBackfillService
+	TaskVersionService              influxdb.TaskVersionService
+	TaskDryRunService               influxdb.TaskDryRunService
+	MonitorEvaluationService        influxdb.MonitorEvaluationService
+	DeliveryLog                     influxdb.DeliveryLog
 	CheckService                    influxdb.CheckService
 	TelegrafService                 influxdb.TelegrafConfigStore
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
@@ -124,6 +134,12 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 
 	authorizationBackend := NewAuthorizationBackend(b.Logger.With(zap.String("handler", "authorization")), b)
 	authorizationBackend.AuthorizationService = authorizer.NewAuthorizationService(b.AuthorizationService)
+	if b.AuthorizationRotator != nil {
+		authorizationBackend.AuthorizationRotator = authorizer.NewAuthorizationRotator(b.AuthorizationService, b.AuthorizationRotator)
+	}
+	if b.RoleService != nil {
+		authorizationBackend.RoleService = authorizer.NewRoleService(b.RoleService)
+	}
 	h.Mount(prefixAuthorization, NewAuthorizationHandler(b.Logger, authorizationBackend))
 
 	bucketBackend := NewBucketBackend(b.Logger.With(zap.String("handler", "bucket")), b)
@@ -133,6 +149,9 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	checkBackend := NewCheckBackend(b.Logger.With(zap.String("handler", "check")), b)
 	checkBackend.CheckService = authorizer.NewCheckService(b.CheckService,
 		b.UserResourceMappingService, b.OrganizationService)
+	if b.MonitorEvaluationService != nil {
+		checkBackend.MonitorEvaluationService = authorizer.NewMonitorEvaluationService(b.MonitorEvaluationService)
+	}
 	h.Mount(prefixChecks, NewCheckHandler(b.Logger, checkBackend))
 
 	h.Mount(prefixChronograf, NewChronografHandler(b.ChronografService, b.HTTPErrorHandler))
@@ -150,6 +169,9 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	fluxBackend := NewFluxBackend(b.Logger.With(zap.String("handler", "query")), b)
 	h.Mount(prefixQuery, NewFluxHandler(b.Logger, fluxBackend))
 
+	promQLBackend := NewPromQLBackend(b.Logger.With(zap.String("handler", "promql")), b)
+	h.Mount(prefixPromQL, NewPromQLHandler(b.Logger, promQLBackend))
+
 	h.Mount(prefixLabels, NewLabelHandler(b.Logger, authorizer.NewLabelService(b.LabelService), b.HTTPErrorHandler))
 
 	notificationEndpointBackend := NewNotificationEndpointBackend(b.Logger.With(zap.String("handler", "notificationEndpoint")), b)
@@ -160,6 +182,9 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	notificationRuleBackend := NewNotificationRuleBackend(b.Logger.With(zap.String("handler", "notification_rule")), b)
 	notificationRuleBackend.NotificationRuleStore = authorizer.NewNotificationRuleStore(b.NotificationRuleStore,
 		b.UserResourceMappingService, b.OrganizationService)
+	if b.MonitorEvaluationService != nil {
+		notificationRuleBackend.MonitorEvaluationService = authorizer.NewMonitorEvaluationService(b.MonitorEvaluationService)
+	}
 	h.Mount(prefixNotificationRules, NewNotificationRuleHandler(b.Logger, notificationRuleBackend))
 
 	orgBackend := NewOrgBackend(b.Logger.With(zap.String("handler", "org")), b)
@@ -188,6 +213,15 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount("/api/v2/swagger.json", newSwaggerLoader(b.Logger.With(zap.String("service", "swagger-loader")), b.HTTPErrorHandler))
 
 	taskBackend := NewTaskBackend(b.Logger.With(zap.String("handler", "task")), b)
+	if b.TaskBackfillService != nil {
+		taskBackend.TaskBackfillService = authorizer.NewTaskBackfillService(b.TaskBackfillService, b.TaskService)
+	}
+	if b.TaskVersionService != nil {
+		taskBackend.TaskVersionService = authorizer.NewTaskVersionService(b.TaskVersionService, b.TaskService)
+	}
+	if b.TaskDryRunService != nil {
+		taskBackend.TaskDryRunService = authorizer.NewTaskDryRunService(b.TaskDryRunService)
+	}
 	taskHandler := NewTaskHandler(b.Logger, taskBackend)
 	taskHandler.UserResourceMappingService = internalURM
 	h.Mount(prefixTasks, taskHandler)
@@ -208,6 +242,18 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	variableBackend.VariableService = authorizer.NewVariableService(b.VariableService)
 	h.Mount(prefixVariables, NewVariableHandler(b.Logger, variableBackend))
 
+	silenceBackend := NewSilenceBackend(b.Logger.With(zap.String("handler", "silence")), b)
+	silenceBackend.SilenceService = authorizer.NewSilenceService(b.SilenceService)
+	h.Mount(prefixSilences, NewSilenceHandler(b.Logger, silenceBackend))
+
+	alertBackend := NewAlertBackend(b.Logger.With(zap.String("handler", "alert")), b)
+	alertBackend.AlertService = authorizer.NewAlertService(b.AlertService)
+	h.Mount(prefixAlerts, NewAlertHandler(b.Logger, alertBackend))
+
+	roleBackend := NewRoleBackend(b.Logger.With(zap.String("handler", "role")), b)
+	roleBackend.RoleService = authorizer.NewRoleService(b.RoleService)
+	h.Mount(prefixRoles, NewRoleHandler(b.Logger, roleBackend))
+
 	backupBackend := NewBackupBackend(b)
 	backupBackend.BackupService = authorizer.NewBackupService(backupBackend.BackupService)
 	h.Mount(prefixBackup, NewBackupHandler(backupBackend))
@@ -229,6 +275,7 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
+	"alerts":         "/api/v2/alerts",
 	"authorizations": "/api/v2/authorizations",
 	"backup":         "/api/v2/backup",
 	"buckets":        "/api/v2/buckets",
@@ -246,11 +293,14 @@ var apiLinks = map[string]interface{}{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
 		"analyze":     "/api/v2/query/analyze",
+		"explain":     "/api/v2/query/explain",
 		"suggestions": "/api/v2/query/suggestions",
 	},
+	"roles":    "/api/v2/roles",
 	"setup":    "/api/v2/setup",
 	"signin":   "/api/v2/signin",
 	"signout":  "/api/v2/signout",
+	"silences": "/api/v2/silences",
 	"sources":  "/api/v2/sources",
 	"scrapers": "/api/v2/scrapers",
 	"swagger":  "/api/v2/swagger.json",
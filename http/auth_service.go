@@ -24,9 +24,11 @@ type AuthorizationBackend struct {
 	log *zap.Logger
 
 	AuthorizationService platform.AuthorizationService
+	AuthorizationRotator platform.AuthorizationRotator
 	OrganizationService  platform.OrganizationService
 	UserService          platform.UserService
 	LookupService        platform.LookupService
+	RoleService          platform.RoleService
 }
 
 // NewAuthorizationBackend returns a new instance of AuthorizationBackend.
@@ -36,9 +38,11 @@ func NewAuthorizationBackend(log *zap.Logger, b *APIBackend) *AuthorizationBacke
 		log:              log,
 
 		AuthorizationService: b.AuthorizationService,
+		AuthorizationRotator: b.AuthorizationRotator,
 		OrganizationService:  b.OrganizationService,
 		UserService:          b.UserService,
 		LookupService:        b.LookupService,
+		RoleService:          b.RoleService,
 	}
 }
 
@@ -51,7 +55,9 @@ type AuthorizationHandler struct {
 	OrganizationService  platform.OrganizationService
 	UserService          platform.UserService
 	AuthorizationService platform.AuthorizationService
+	AuthorizationRotator platform.AuthorizationRotator
 	LookupService        platform.LookupService
+	RoleService          platform.RoleService
 }
 
 // NewAuthorizationHandler returns a new instance of AuthorizationHandler.
@@ -62,9 +68,11 @@ func NewAuthorizationHandler(log *zap.Logger, b *AuthorizationBackend) *Authoriz
 		log:              log,
 
 		AuthorizationService: b.AuthorizationService,
+		AuthorizationRotator: b.AuthorizationRotator,
 		OrganizationService:  b.OrganizationService,
 		UserService:          b.UserService,
 		LookupService:        b.LookupService,
+		RoleService:          b.RoleService,
 	}
 
 	h.HandlerFunc("POST", "/api/v2/authorizations", h.handlePostAuthorization)
@@ -72,6 +80,9 @@ func NewAuthorizationHandler(log *zap.Logger, b *AuthorizationBackend) *Authoriz
 	h.HandlerFunc("GET", "/api/v2/authorizations/:id", h.handleGetAuthorization)
 	h.HandlerFunc("PATCH", "/api/v2/authorizations/:id", h.handleUpdateAuthorization)
 	h.HandlerFunc("DELETE", "/api/v2/authorizations/:id", h.handleDeleteAuthorization)
+	if h.AuthorizationRotator != nil {
+		h.HandlerFunc("POST", "/api/v2/authorizations/:id/rotate", h.handleRotateAuthorization)
+	}
 	return h
 }
 
@@ -86,6 +97,7 @@ type authResponse struct {
 	User        string               `json:"user"`
 	Permissions []permissionResponse `json:"permissions"`
 	Links       map[string]string    `json:"links"`
+	ExpiresAt   *time.Time           `json:"expiresAt,omitempty"`
 	CreatedAt   time.Time            `json:"createdAt"`
 	UpdatedAt   time.Time            `json:"updatedAt"`
 }
@@ -105,6 +117,7 @@ func newAuthResponse(a *platform.Authorization, org *platform.Organization, user
 			"self": fmt.Sprintf("/api/v2/authorizations/%s", a.ID),
 			"user": fmt.Sprintf("/api/v2/users/%s", a.UserID),
 		},
+		ExpiresAt: a.ExpiresAt,
 		CreatedAt: a.CreatedAt,
 		UpdatedAt: a.UpdatedAt,
 	}
@@ -119,6 +132,7 @@ func (a *authResponse) toPlatform() *platform.Authorization {
 		Description: a.Description,
 		OrgID:       a.OrgID,
 		UserID:      a.UserID,
+		ExpiresAt:   a.ExpiresAt,
 		CRUDLog: platform.CRUDLog{
 			CreatedAt: a.CreatedAt,
 			UpdatedAt: a.UpdatedAt,
@@ -212,6 +226,11 @@ func (h *AuthorizationHandler) handlePostAuthorization(w http.ResponseWriter, r
 		userID = *req.UserID
 	}
 
+	if err := h.addRolePermissions(ctx, req); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
 	auth := req.toPlatform(userID)
 
 	org, err := h.OrganizationService.FindOrganizationByID(ctx, auth.OrgID)
@@ -239,12 +258,43 @@ func (h *AuthorizationHandler) handlePostAuthorization(w http.ResponseWriter, r
 	}
 }
 
+// addRolePermissions adds the permissions of the roles of the request to its permissions.
+func (h *AuthorizationHandler) addRolePermissions(ctx context.Context, req *postAuthorizationRequest) error {
+	if len(req.RoleIDs) == 0 {
+		return nil
+	}
+	if h.RoleService == nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "creating authorizations from roles is not supported",
+		}
+	}
+
+	for _, id := range req.RoleIDs {
+		r, err := h.RoleService.FindRoleByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if r.OrgID != req.OrgID {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("role %s is not a role of org id %s", id, req.OrgID),
+			}
+		}
+		req.Permissions = append(req.Permissions, r.Permissions...)
+	}
+	return nil
+}
+
 type postAuthorizationRequest struct {
 	Status      platform.Status       `json:"status"`
 	OrgID       platform.ID           `json:"orgID"`
 	UserID      *platform.ID          `json:"userID,omitempty"`
 	Description string                `json:"description"`
 	Permissions []platform.Permission `json:"permissions"`
+	// RoleIDs are the roles the authorization is granted the permissions of.
+	RoleIDs   []platform.ID `json:"roleIDs,omitempty"`
+	ExpiresAt *time.Time    `json:"expiresAt,omitempty"`
 }
 
 func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Authorization {
@@ -254,6 +304,7 @@ func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Auth
 		Description: p.Description,
 		Permissions: p.Permissions,
 		UserID:      userID,
+		ExpiresAt:   p.ExpiresAt,
 	}
 }
 
@@ -263,6 +314,7 @@ func newPostAuthorizationRequest(a *platform.Authorization) (*postAuthorizationR
 		Description: a.Description,
 		Permissions: a.Permissions,
 		Status:      a.Status,
+		ExpiresAt:   a.ExpiresAt,
 	}
 
 	if a.UserID.Valid() {
@@ -281,7 +333,7 @@ func (p *postAuthorizationRequest) SetDefaults() {
 }
 
 func (p *postAuthorizationRequest) Validate() error {
-	if len(p.Permissions) == 0 {
+	if len(p.Permissions) == 0 && len(p.RoleIDs) == 0 {
 		return &platform.Error{
 			Code: platform.EInvalid,
 			Msg:  "authorization must include permissions",
@@ -304,6 +356,13 @@ func (p *postAuthorizationRequest) Validate() error {
 		}
 	}
 
+	if p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now()) {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "expiresAt must be in the future",
+		}
+	}
+
 	if p.Status == "" {
 		p.Status = platform.Active
 	}
@@ -618,6 +677,80 @@ func decodeDeleteAuthorizationRequest(ctx context.Context, r *http.Request) (*de
 	}, nil
 }
 
+// handleRotateAuthorization is the HTTP handler for the POST /api/v2/authorizations/:id/rotate route.
+func (h *AuthorizationHandler) handleRotateAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeRotateAuthorizationRequest(ctx, r)
+	if err != nil {
+		h.log.Info("Failed to decode request", zap.String("handler", "rotateAuthorization"), zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AuthorizationRotator.RotateAuthorization(ctx, req.ID, req.GracePeriod.Duration)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationService.FindOrganizationByID(ctx, a.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	u, err := h.UserService.FindUserByID(ctx, a.UserID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ps, err := newPermissionsResponse(ctx, a.Permissions, h.LookupService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Auth rotated", zap.String("authID", req.ID.String()), zap.String("newAuthID", a.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newAuthResponse(a, o, u, ps)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type rotateAuthorizationRequest struct {
+	ID          platform.ID       `json:"-"`
+	GracePeriod platform.Duration `json:"gracePeriod"`
+}
+
+func decodeRotateAuthorizationRequest(ctx context.Context, r *http.Request) (*rotateAuthorizationRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	req := &rotateAuthorizationRequest{}
+	if err := req.ID.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid json structure",
+				Err:  err,
+			}
+		}
+	}
+
+	return req, nil
+}
+
 func getAuthorizedUser(r *http.Request, svc platform.UserService) (*platform.User, error) {
 	ctx := r.Context()
 
@@ -634,7 +767,10 @@ type AuthorizationService struct {
 	Client *httpc.Client
 }
 
-var _ platform.AuthorizationService = (*AuthorizationService)(nil)
+var (
+	_ platform.AuthorizationService = (*AuthorizationService)(nil)
+	_ platform.AuthorizationRotator = (*AuthorizationService)(nil)
+)
 
 // FindAuthorizationByID finds the authorization against a remote influx server.
 func (s *AuthorizationService) FindAuthorizationByID(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
@@ -725,3 +861,23 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 		Delete(prefixAuthorization, id.String()).
 		Do(ctx)
 }
+
+// RotateAuthorization creates an authorization with a new token and the permissions of the authorization,
+// and expires the authorization after the grace period.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, grace time.Duration) (*platform.Authorization, error) {
+	req := rotateAuthorizationRequest{
+		ID:          id,
+		GracePeriod: platform.Duration{Duration: grace},
+	}
+
+	var res authResponse
+	err := s.Client.
+		PostJSON(req, prefixAuthorization, id.String(), "rotate").
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.toPlatform(), nil
+}
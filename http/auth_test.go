@@ -8,7 +8,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
@@ -741,6 +743,89 @@ func TestService_handlePostAuthorization(t *testing.T) {
 	}
 }
 
+func TestService_handlePostAuthorization_Roles(t *testing.T) {
+	orgID := platformtesting.MustIDBase16("020f755c3c083000")
+	dashboardsWrite := platform.Permission{
+		Action:   platform.WriteAction,
+		Resource: platform.Resource{Type: platform.DashboardsResourceType, OrgID: &orgID},
+	}
+	roles := map[platform.ID]*platform.Role{
+		1: {ID: 1, OrgID: orgID, Name: "dashboard editor", Permissions: []platform.Permission{dashboardsWrite}},
+		2: {ID: 2, OrgID: 3, Name: "other org"},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		want       []platform.Permission
+	}{
+		{
+			name:       "create an authorization from a role",
+			body:       `{"orgID": "020f755c3c083000", "roleIDs": ["0000000000000001"]}`,
+			wantStatus: http.StatusCreated,
+			want:       []platform.Permission{dashboardsWrite},
+		},
+		{
+			name:       "create an authorization from a role of another org",
+			body:       `{"orgID": "020f755c3c083000", "roleIDs": ["0000000000000002"]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "create an authorization without permissions or roles",
+			body:       `{"orgID": "020f755c3c083000"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *platform.Authorization
+			authorizationBackend := NewMockAuthorizationBackend(t)
+			authorizationBackend.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			authorizationBackend.AuthorizationService = &mock.AuthorizationService{
+				CreateAuthorizationFn: func(ctx context.Context, a *platform.Authorization) error {
+					a.ID = 10
+					created = a
+					return nil
+				},
+			}
+			authorizationBackend.UserService = &mock.UserService{
+				FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+					return &platform.User{ID: id, Name: "u1"}, nil
+				},
+			}
+			authorizationBackend.OrganizationService = &mock.OrganizationService{
+				FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+					return &platform.Organization{ID: id, Name: "o1"}, nil
+				},
+			}
+			authorizationBackend.LookupService = &mock.LookupService{
+				NameFn: func(ctx context.Context, resource platform.ResourceType, id platform.ID) (string, error) {
+					return "o1", nil
+				},
+			}
+			rs := mock.NewRoleService()
+			rs.FindRoleByIDFn = func(ctx context.Context, id platform.ID) (*platform.Role, error) {
+				return roles[id], nil
+			}
+			authorizationBackend.RoleService = rs
+			h := NewAuthorizationHandler(zaptest.NewLogger(t), authorizationBackend)
+
+			r := httptest.NewRequest("POST", "http://any.url", bytes.NewBufferString(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(context.Background(), &platform.Authorization{UserID: 4}))
+			w := httptest.NewRecorder()
+			h.handlePostAuthorization(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.want != nil && !reflect.DeepEqual(tt.want, created.Permissions) {
+				t.Errorf("expected the authorization to be granted %v, got %v", tt.want, created.Permissions)
+			}
+		})
+	}
+}
+
 func TestService_handleDeleteAuthorization(t *testing.T) {
 	type fields struct {
 		AuthorizationService platform.AuthorizationService
@@ -855,6 +940,67 @@ func TestService_handleDeleteAuthorization(t *testing.T) {
 	}
 }
 
+func TestService_handleRotateAuthorization(t *testing.T) {
+	now := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(24 * time.Hour)
+	var gotID platform.ID
+	var gotGrace time.Duration
+	rotator := &mock.AuthorizationService{
+		RotateAuthorizationFn: func(ctx context.Context, id platform.ID, grace time.Duration) (*platform.Authorization, error) {
+			gotID, gotGrace = id, grace
+			return &platform.Authorization{
+				ID:          platformtesting.MustIDBase16("020f755c3c082001"),
+				Token:       "rotated",
+				Status:      platform.Active,
+				OrgID:       platformtesting.MustIDBase16("020f755c3c083000"),
+				UserID:      platformtesting.MustIDBase16("020f755c3c081000"),
+				Permissions: []platform.Permission{{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType}}},
+				ExpiresAt:   &expiresAt,
+			}, nil
+		},
+	}
+
+	authorizationBackend := NewMockAuthorizationBackend(t)
+	authorizationBackend.HTTPErrorHandler = kithttp.ErrorHandler(0)
+	authorizationBackend.AuthorizationRotator = rotator
+	authorizationBackend.OrganizationService = &mock.OrganizationService{
+		FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+			return &platform.Organization{ID: id, Name: "o1"}, nil
+		},
+	}
+	authorizationBackend.UserService = &mock.UserService{
+		FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+			return &platform.User{ID: id, Name: "u1"}, nil
+		},
+	}
+	server := httptest.NewServer(NewAuthorizationHandler(zaptest.NewLogger(t), authorizationBackend))
+	defer server.Close()
+
+	httpClient, err := NewHTTPClient(server.URL, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := &AuthorizationService{Client: httpClient}
+
+	a, err := svc.RotateAuthorization(context.Background(), platformtesting.MustIDBase16("020f755c3c082000"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotID != platformtesting.MustIDBase16("020f755c3c082000") || gotGrace != time.Hour {
+		t.Errorf("expected the authorization to be rotated with a grace period of an hour, got %s and %s", gotID, gotGrace)
+	}
+	if a.Token != "rotated" || a.ExpiresAt == nil || !a.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("expected the rotated authorization, got %+v", a)
+	}
+
+	rotator.RotateAuthorizationFn = func(ctx context.Context, id platform.ID, grace time.Duration) (*platform.Authorization, error) {
+		return nil, &platform.Error{Code: platform.ENotFound, Msg: "authorization not found"}
+	}
+	if _, err := svc.RotateAuthorization(context.Background(), 1, time.Hour); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected the authorization not to be found, got %v", err)
+	}
+}
+
 func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.T) (platform.AuthorizationService, string, func()) {
 	t.Helper()
 	if t.Name() == "TestAuthorizationService_FindAuthorizations/find_authorization_by_token" {
@@ -873,6 +1019,9 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 	svc.IDGenerator = f.IDGenerator
 	svc.TokenGenerator = f.TokenGenerator
 	svc.TimeGenerator = f.TimeGenerator
+	if f.TimeGenerator == nil {
+		svc.TimeGenerator = platform.RealTimeGenerator{}
+	}
 
 	ctx := context.Background()
 
@@ -152,7 +152,16 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return nil, err
 	}
 
-	return h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	a, err := h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.CheckExpiry(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return a, nil
 }
 
 func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Request) (*platform.Session, error) {
@@ -27,6 +27,7 @@ type CheckBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	MonitorEvaluationService   influxdb.MonitorEvaluationService
 }
 
 // NewCheckBackend returns a new instance of CheckBackend.
@@ -41,6 +42,7 @@ func NewCheckBackend(log *zap.Logger, b *APIBackend) *CheckBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		MonitorEvaluationService:   b.MonitorEvaluationService,
 	}
 }
 
@@ -56,12 +58,14 @@ type CheckHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	MonitorEvaluationService   influxdb.MonitorEvaluationService
 }
 
 const (
 	prefixChecks          = "/api/v2/checks"
 	checksIDPath          = "/api/v2/checks/:id"
 	checksIDQueryPath     = "/api/v2/checks/:id/query"
+	checksIDEvaluatePath  = "/api/v2/checks/:id/evaluate"
 	checksIDMembersPath   = "/api/v2/checks/:id/members"
 	checksIDMembersIDPath = "/api/v2/checks/:id/members/:userID"
 	checksIDOwnersPath    = "/api/v2/checks/:id/owners"
@@ -83,6 +87,7 @@ func NewCheckHandler(log *zap.Logger, b *CheckBackend) *CheckHandler {
 		UserService:                b.UserService,
 		TaskService:                b.TaskService,
 		OrganizationService:        b.OrganizationService,
+		MonitorEvaluationService:   b.MonitorEvaluationService,
 	}
 	h.HandlerFunc("POST", prefixChecks, h.handlePostCheck)
 	h.HandlerFunc("GET", prefixChecks, h.handleGetChecks)
@@ -92,6 +97,11 @@ func NewCheckHandler(log *zap.Logger, b *CheckBackend) *CheckHandler {
 	h.HandlerFunc("PUT", checksIDPath, h.handlePutCheck)
 	h.HandlerFunc("PATCH", checksIDPath, h.handlePatchCheck)
 
+	if h.MonitorEvaluationService != nil {
+		h.HandlerFunc("POST", checksIDPath, h.handlePostCheckID)
+		h.HandlerFunc("POST", checksIDEvaluatePath, h.handlePostCheckEvaluate)
+	}
+
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
 		log:                        b.log.With(zap.String("handler", "member")),
@@ -403,6 +413,12 @@ func decodePostCheckRequest(r *http.Request) (postCheckRequest, error) {
 			Err:  err,
 		}
 	}
+	// the band is validated before the check is created, which validates the rest of the check.
+	if a, ok := chk.(*check.Anomaly); ok {
+		if err := a.ValidBand(); err != nil {
+			return postCheckRequest{}, err
+		}
+	}
 
 	var ds decodeStatus
 	if err := json.Unmarshal(b, &ds); err != nil {
@@ -717,11 +717,42 @@ func TestService_handlePostCheck(t *testing.T) {
 `,
 			},
 		},
+		{
+			name: "create an anomaly check with an invalid band",
+			fields: fields{
+				CheckService: &mock.CheckService{
+					CreateCheckFn: func(ctx context.Context, c influxdb.CheckCreate, userID influxdb.ID) error {
+						t.Fatal("the check should not be created")
+						return nil
+					},
+				},
+				OrganizationService: mock.NewOrganizationService(),
+			},
+			args: args{
+				userID: influxTesting.MustIDBase16("6f626f7274697321"),
+				check: &check.Anomaly{
+					Base: check.Base{
+						Name:                  "hello",
+						OrgID:                 influxTesting.MustIDBase16("6f626f7274697320"),
+						StatusMessageTemplate: "msg1",
+						Every:                 mustDuration("5m"),
+					},
+					Method:     "mean",
+					Window:     mustDuration("1h"),
+					Deviations: 3,
+					Level:      notification.Critical,
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			checkBackend := NewMockCheckBackend(t)
+			checkBackend.HTTPErrorHandler = kithttp.ErrorHandler(0)
 			checkBackend.CheckService = tt.fields.CheckService
 			checkBackend.OrganizationService = tt.fields.OrganizationService
 			checkBackend.TaskService = &mock.TaskService{
@@ -129,6 +129,7 @@ func ignoreMethod(ignoredMethods ...string) isValidMethodFn {
 var blacklistEndpoints = map[string]isValidMethodFn{
 	prefixSignIn:                     ignoreMethod(),
 	prefixSignOut:                    ignoreMethod(),
+	signInOAuthCallbackPath:          ignoreMethod(),
 	prefixMe:                         ignoreMethod(),
 	mePasswordPath:                   ignoreMethod(),
 	usersPasswordPath:                ignoreMethod(),
@@ -0,0 +1,278 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/rule"
+	"go.uber.org/zap"
+)
+
+// checksEvaluatePath and notificationRulesEvaluatePath share their routes with checksIDPath
+// and notificationRulesIDPath, the router doesn't allow a static segment next to the :id parameter.
+const (
+	checksEvaluatePath            = "/api/v2/checks/evaluate"
+	notificationRulesEvaluatePath = "/api/v2/notificationRules/evaluate"
+)
+
+// monitorEvaluationRange is the historical range a saved check or notification rule is evaluated over.
+type monitorEvaluationRange struct {
+	Start time.Time `json:"start"`
+	Stop  time.Time `json:"stop"`
+}
+
+// unsavedMonitorID stands for the ID and the owner of unsaved checks and notification rules,
+// which are validated like saved ones before their scripts are generated.
+const unsavedMonitorID influxdb.ID = 1
+
+// unsaved is an unsaved check or notification rule.
+type unsaved interface {
+	Valid() error
+	GetID() influxdb.ID
+	SetID(influxdb.ID)
+	GetOwnerID() influxdb.ID
+	SetOwnerID(influxdb.ID)
+}
+
+func validUnsaved(u unsaved) error {
+	if !u.GetID().Valid() {
+		u.SetID(unsavedMonitorID)
+	}
+	if !u.GetOwnerID().Valid() {
+		u.SetOwnerID(unsavedMonitorID)
+	}
+	if err := u.Valid(); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+type postCheckEvaluationRequest struct {
+	Check json.RawMessage `json:"check"`
+	monitorEvaluationRange
+}
+
+type postNotificationRuleEvaluationRequest struct {
+	Rule json.RawMessage `json:"rule"`
+	monitorEvaluationRange
+}
+
+// handlePostCheckID handles the POST requests of checksIDPath, of which only the evaluation exists.
+func (h *CheckHandler) handlePostCheckID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if httprouter.ParamsFromContext(ctx).ByName("id") != "evaluate" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EMethodNotAllowed,
+			Msg:  "method not allowed",
+		}, w)
+		return
+	}
+	h.handlePostUnsavedCheckEvaluate(w, r)
+}
+
+// handlePostUnsavedCheckEvaluate evaluates the check of the request over a historical range, without creating it.
+func (h *CheckHandler) handlePostUnsavedCheckEvaluate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req postCheckEvaluationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+	chk, err := check.UnmarshalJSON(req.Check)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := validUnsaved(chk); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.evaluateCheck(w, r, chk, req.monitorEvaluationRange)
+}
+
+// handlePostCheckEvaluate evaluates a check over a historical range.
+func (h *CheckHandler) handlePostCheckEvaluate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGetCheckRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	var req monitorEvaluationRange
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+	chk, err := h.CheckService.FindCheckByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.evaluateCheck(w, r, chk, req)
+}
+
+func (h *CheckHandler) evaluateCheck(w http.ResponseWriter, r *http.Request, chk influxdb.Check, rng monitorEvaluationRange) {
+	ctx := r.Context()
+
+	flux, err := chk.GenerateFlux()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	ev, err := evaluateMonitor(ctx, h.MonitorEvaluationService, influxdb.MonitorEvaluationRequest{
+		Flux:           flux,
+		OrganizationID: chk.GetOrgID(),
+		ResourceType:   influxdb.ChecksResourceType,
+		Start:          rng.Start,
+		Stop:           rng.Stop,
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Check evaluated", zap.String("check", chk.GetName()), zap.Int("evaluations", ev.Evaluations))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, ev); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePostUnsavedNotificationRuleEvaluate evaluates the notification rule of the request
+// over a historical range, without creating it.
+func (h *NotificationRuleHandler) handlePostUnsavedNotificationRuleEvaluate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req postNotificationRuleEvaluationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+	nr, err := rule.UnmarshalJSON(req.Rule)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := validUnsaved(nr); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.evaluateNotificationRule(w, r, nr, req.monitorEvaluationRange)
+}
+
+// handlePostNotificationRuleEvaluate evaluates a notification rule over a historical range.
+func (h *NotificationRuleHandler) handlePostNotificationRuleEvaluate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeGetNotificationRuleRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	var req monitorEvaluationRange
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+	nr, err := h.NotificationRuleStore.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.evaluateNotificationRule(w, r, nr, req)
+}
+
+func (h *NotificationRuleHandler) evaluateNotificationRule(w http.ResponseWriter, r *http.Request, nr influxdb.NotificationRule, rng monitorEvaluationRange) {
+	ctx := r.Context()
+
+	edp, err := h.NotificationEndpointService.FindNotificationEndpointByID(ctx, nr.GetEndpointID())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	// the endpoint of an unsaved rule is not checked to belong to its organization yet.
+	if edp.GetOrgID() != nr.GetOrgID() {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "the notification endpoint of the rule belongs to another organization",
+		}, w)
+		return
+	}
+	flux, err := nr.GenerateFlux(edp)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	ev, err := evaluateMonitor(ctx, h.MonitorEvaluationService, influxdb.MonitorEvaluationRequest{
+		Flux:           flux,
+		OrganizationID: nr.GetOrgID(),
+		ResourceType:   influxdb.NotificationRuleResourceType,
+		Start:          rng.Start,
+		Stop:           rng.Stop,
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Notification rule evaluated", zap.String("rule", nr.GetName()), zap.Int("evaluations", ev.Evaluations))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, ev); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// evaluateMonitor evaluates the script of req on behalf of the caller,
+// sessions get an ephemeral authorization to query with.
+func evaluateMonitor(ctx context.Context, s influxdb.MonitorEvaluationService, req influxdb.MonitorEvaluationRequest) (*influxdb.MonitorEvaluation, error) {
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "failed to get authorizer",
+			Err:  err,
+		}
+	}
+	a, err := queryAuthorization(auth, req.OrganizationID)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Err:  err,
+		}
+	}
+	return s.EvaluateMonitor(pcontext.SetAuthorizer(ctx, a), req)
+}
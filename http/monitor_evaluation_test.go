@@ -0,0 +1,257 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+	influxTesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+var (
+	evaluationStart = time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	evaluationStop  = time.Date(2019, 12, 1, 12, 0, 0, 0, time.UTC)
+)
+
+// newMockMonitorEvaluationService returns a service capturing the requests it evaluates.
+func newMockMonitorEvaluationService(reqs *[]influxdb.MonitorEvaluationRequest) *mock.MonitorEvaluationService {
+	s := mock.NewMonitorEvaluationService()
+	s.EvaluateMonitorFn = func(ctx context.Context, req influxdb.MonitorEvaluationRequest) (*influxdb.MonitorEvaluation, error) {
+		if _, err := pcontext.GetAuthorizer(ctx); err != nil {
+			return nil, err
+		}
+		*reqs = append(*reqs, req)
+		return &influxdb.MonitorEvaluation{
+			Start:       req.Start,
+			Stop:        req.Stop,
+			Every:       "1h",
+			Evaluations: 2,
+			Series:      []influxdb.MonitorSeries{},
+		}, nil
+	}
+	return s
+}
+
+func TestCheckHandler_Evaluate(t *testing.T) {
+	saved := &check.Deadman{
+		Base: check.Base{
+			ID:    influxTesting.MustIDBase16("020f755c3c082000"),
+			Name:  "moo",
+			OrgID: influxTesting.MustIDBase16("020f755c3c082001"),
+			Every: mustDuration("1h"),
+			Query: influxdb.DashboardQuery{Text: `from(bucket: "foo") |> range(start: -1h)`},
+		},
+		TimeSince: mustDuration("60s"),
+		StaleTime: mustDuration("10m"),
+		Level:     notification.Info,
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantOrgID  influxdb.ID
+	}{
+		{
+			name:       "saved check",
+			path:       "/api/v2/checks/020f755c3c082000/evaluate",
+			body:       `{"start": "2019-12-01T10:00:00Z", "stop": "2019-12-01T12:00:00Z"}`,
+			wantStatus: http.StatusOK,
+			wantOrgID:  saved.OrgID,
+		},
+		{
+			name: "unsaved check",
+			path: checksEvaluatePath,
+			body: `{
+  "check": {"type": "deadman", "name": "moo", "orgID": "020f755c3c082002", "every": "1h", "query": {"text": "from(bucket: \"foo\") |> range(start: -1h)"}, "timeSince": "60s", "staleTime": "10m", "level": "INFO"},
+  "start": "2019-12-01T10:00:00Z",
+  "stop": "2019-12-01T12:00:00Z"
+}`,
+			wantStatus: http.StatusOK,
+			wantOrgID:  influxTesting.MustIDBase16("020f755c3c082002"),
+		},
+		{
+			name:       "invalid unsaved check",
+			path:       checksEvaluatePath,
+			body:       `{"check": {"type": "deadman", "name": "moo", "orgID": "020f755c3c082002", "timeSince": "60s", "level": "INFO"}, "start": "2019-12-01T10:00:00Z", "stop": "2019-12-01T12:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unsaved check of an unknown type",
+			path:       checksEvaluatePath,
+			body:       `{"check": {"type": "foo"}, "start": "2019-12-01T10:00:00Z", "stop": "2019-12-01T12:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "post to a check",
+			path:       "/api/v2/checks/020f755c3c082000",
+			body:       `{}`,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reqs []influxdb.MonitorEvaluationRequest
+			b := NewMockCheckBackend(t)
+			b.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			b.CheckService = &mock.CheckService{
+				FindCheckByIDFn: func(ctx context.Context, id influxdb.ID) (influxdb.Check, error) {
+					return saved, nil
+				},
+			}
+			b.MonitorEvaluationService = newMockMonitorEvaluationService(&reqs)
+			h := NewCheckHandler(zaptest.NewLogger(t), b)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", tt.path, bytes.NewBufferString(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Authorization{}))
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			assertMonitorEvaluation(t, w, reqs, influxdb.ChecksResourceType, tt.wantOrgID, `_check_name: "moo"`)
+		})
+	}
+}
+
+func TestNotificationRuleHandler_Evaluate(t *testing.T) {
+	var (
+		orgID      = influxTesting.MustIDBase16("020f755c3c082001")
+		endpointID = influxTesting.MustIDBase16("020f755c3c082003")
+		saved      = &rule.HTTP{
+			Base: rule.Base{
+				ID:         influxTesting.MustIDBase16("020f755c3c082000"),
+				Name:       "foo",
+				OrgID:      orgID,
+				EndpointID: endpointID,
+				Every:      mustDuration("1h"),
+			},
+		}
+		edp = &endpoint.HTTP{
+			Base: endpoint.Base{
+				ID:    &endpointID,
+				Name:  "bar",
+				OrgID: &orgID,
+			},
+			URL:        "http://localhost:7777",
+			Method:     "POST",
+			AuthMethod: "none",
+		}
+	)
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "saved rule",
+			path:       "/api/v2/notificationRules/020f755c3c082000/evaluate",
+			body:       `{"start": "2019-12-01T10:00:00Z", "stop": "2019-12-01T12:00:00Z"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "unsaved rule",
+			path: notificationRulesEvaluatePath,
+			body: `{
+  "rule": {"type": "http", "name": "foo", "orgID": "020f755c3c082001", "endpointID": "020f755c3c082003", "every": "1h"},
+  "start": "2019-12-01T10:00:00Z",
+  "stop": "2019-12-01T12:00:00Z"
+}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "unsaved rule with the endpoint of another organization",
+			path: notificationRulesEvaluatePath,
+			body: `{
+  "rule": {"type": "http", "name": "foo", "orgID": "020f755c3c082002", "endpointID": "020f755c3c082003", "every": "1h"},
+  "start": "2019-12-01T10:00:00Z",
+  "stop": "2019-12-01T12:00:00Z"
+}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reqs []influxdb.MonitorEvaluationRequest
+			b := NewMockNotificationRuleBackend(t)
+			b.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			b.NotificationRuleStore = &mock.NotificationRuleStore{
+				FindNotificationRuleByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+					return saved, nil
+				},
+			}
+			nes := mock.NewNotificationEndpointService()
+			nes.FindNotificationEndpointByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+				return edp, nil
+			}
+			b.NotificationEndpointService = nes
+			b.MonitorEvaluationService = newMockMonitorEvaluationService(&reqs)
+			h := NewNotificationRuleHandler(zaptest.NewLogger(t), b)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", tt.path, bytes.NewBufferString(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Authorization{}))
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			assertMonitorEvaluation(t, w, reqs, influxdb.NotificationRuleResourceType, orgID, `_notification_rule_name: "foo"`)
+		})
+	}
+}
+
+func assertMonitorEvaluation(t *testing.T, w *httptest.ResponseRecorder, reqs []influxdb.MonitorEvaluationRequest, rt influxdb.ResourceType, orgID influxdb.ID, script string) {
+	t.Helper()
+
+	if len(reqs) != 1 {
+		t.Fatalf("expected a single evaluation, got %d", len(reqs))
+	}
+	req := reqs[0]
+	if req.ResourceType != rt || req.OrganizationID != orgID || !req.Start.Equal(evaluationStart) || !req.Stop.Equal(evaluationStop) {
+		t.Errorf("unexpected evaluation request %+v", req)
+	}
+	if !strings.Contains(req.Flux, script) {
+		t.Errorf("expected the script to contain %s, got:\n%s", script, req.Flux)
+	}
+
+	var got influxdb.MonitorEvaluation
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := influxdb.MonitorEvaluation{
+		Start:       evaluationStart,
+		Stop:        evaluationStop,
+		Every:       "1h",
+		Evaluations: 2,
+		Series:      []influxdb.MonitorSeries{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected evaluation. want:\n%+v\ngot:\n%+v", want, got)
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const notificationRulesIDDeliveriesPath = "/api/v2/notificationRules/:id/deliveries"
+
+type deliveryAttemptsLinks struct {
+	Self string `json:"self"`
+}
+
+type getDeliveryAttemptsResponse struct {
+	Deliveries []*influxdb.DeliveryAttempt `json:"deliveries"`
+	Links      deliveryAttemptsLinks       `json:"links"`
+}
+
+// handleGetNotificationRuleDeliveries lists the latest delivery attempts of the notifications of a notification rule.
+func (h *NotificationRuleHandler) handleGetNotificationRuleDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGetDeliveryAttemptsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	// the rule is looked up first so that only the readers of the rule get its deliveries.
+	nr, err := h.NotificationRuleStore.FindNotificationRuleByID(ctx, filter.RuleID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	filter.OrgID = nr.GetOrgID()
+
+	attempts, _, err := h.DeliveryLog.FindDeliveryAttempts(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Notification deliveries retrieved", zap.Int("deliveries", len(attempts)))
+
+	if attempts == nil {
+		attempts = []*influxdb.DeliveryAttempt{}
+	}
+	res := getDeliveryAttemptsResponse{
+		Deliveries: attempts,
+		Links: deliveryAttemptsLinks{
+			Self: fmt.Sprintf("/api/v2/notificationRules/%s/deliveries", filter.RuleID),
+		},
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodeGetDeliveryAttemptsRequest(ctx context.Context, r *http.Request) (influxdb.DeliveryAttemptFilter, error) {
+	var filter influxdb.DeliveryAttemptFilter
+	id, err := decodeGetNotificationRuleRequest(ctx, r)
+	if err != nil {
+		return filter, err
+	}
+	filter.RuleID = id
+
+	qp := r.URL.Query()
+	if limit := qp.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > influxdb.MaxDeliveryAttemptsLimit {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("limit must be between 1 and %d", influxdb.MaxDeliveryAttemptsLimit),
+			}
+		}
+		filter.Limit = n
+	}
+	if status := qp.Get("status"); status != "" {
+		switch status {
+		case influxdb.DeliveryDelivered, influxdb.DeliveryRetrying, influxdb.DeliveryFailed:
+		default:
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("status %q is invalid, it must be one of delivered, retrying or failed", status),
+			}
+		}
+		filter.Status = &status
+	}
+	return filter, nil
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification/rule"
+	influxTesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNotificationRuleHandler_Deliveries(t *testing.T) {
+	var (
+		orgID  = influxTesting.MustIDBase16("020f755c3c082001")
+		ruleID = influxTesting.MustIDBase16("020f755c3c082000")
+		saved  = &rule.HTTP{
+			Base: rule.Base{
+				ID:         ruleID,
+				Name:       "foo",
+				OrgID:      orgID,
+				EndpointID: influxTesting.MustIDBase16("020f755c3c082003"),
+				Every:      mustDuration("1h"),
+			},
+		}
+		attempt = &influxdb.DeliveryAttempt{
+			Time:        time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC),
+			RuleID:      ruleID,
+			EndpointID:  influxTesting.MustIDBase16("020f755c3c082003"),
+			Attempt:     1,
+			Status:      influxdb.DeliveryDelivered,
+			StatusCode:  204,
+			Latency:     influxdb.Duration{Duration: 20 * time.Millisecond},
+			PayloadHash: "abc",
+		}
+		failed = influxdb.DeliveryFailed
+	)
+
+	tests := []struct {
+		name       string
+		path       string
+		ruleErr    error
+		wantStatus int
+		wantFilter influxdb.DeliveryAttemptFilter
+	}{
+		{
+			name:       "deliveries",
+			path:       "/api/v2/notificationRules/020f755c3c082000/deliveries",
+			wantStatus: http.StatusOK,
+			wantFilter: influxdb.DeliveryAttemptFilter{OrgID: orgID, RuleID: ruleID},
+		},
+		{
+			name:       "failed deliveries",
+			path:       "/api/v2/notificationRules/020f755c3c082000/deliveries?status=failed&limit=10",
+			wantStatus: http.StatusOK,
+			wantFilter: influxdb.DeliveryAttemptFilter{OrgID: orgID, RuleID: ruleID, Status: &failed, Limit: 10},
+		},
+		{
+			name:       "invalid status",
+			path:       "/api/v2/notificationRules/020f755c3c082000/deliveries?status=lost",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid limit",
+			path:       "/api/v2/notificationRules/020f755c3c082000/deliveries?limit=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rule not found",
+			path: "/api/v2/notificationRules/020f755c3c082000/deliveries",
+			ruleErr: &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  "notification rule not found",
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filters []influxdb.DeliveryAttemptFilter
+			b := NewMockNotificationRuleBackend(t)
+			b.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			b.NotificationRuleStore = &mock.NotificationRuleStore{
+				FindNotificationRuleByIDF: func(ctx context.Context, id influxdb.ID) (influxdb.NotificationRule, error) {
+					if tt.ruleErr != nil {
+						return nil, tt.ruleErr
+					}
+					return saved, nil
+				},
+			}
+			dl := mock.NewDeliveryLog()
+			dl.FindDeliveryAttemptsFn = func(ctx context.Context, filter influxdb.DeliveryAttemptFilter) ([]*influxdb.DeliveryAttempt, int, error) {
+				filters = append(filters, filter)
+				return []*influxdb.DeliveryAttempt{attempt}, 1, nil
+			}
+			b.DeliveryLog = dl
+			h := NewNotificationRuleHandler(zaptest.NewLogger(t), b)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if len(filters) != 0 {
+					t.Errorf("expected no lookup of the deliveries, got %+v", filters)
+				}
+				return
+			}
+			if len(filters) != 1 || !reflect.DeepEqual(filters[0], tt.wantFilter) {
+				t.Errorf("expected the filter %+v, got %+v", tt.wantFilter, filters)
+			}
+
+			var res getDeliveryAttemptsResponse
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Deliveries) != 1 || !reflect.DeepEqual(res.Deliveries[0], attempt) {
+				t.Errorf("unexpected deliveries %+v", res.Deliveries)
+			}
+			if res.Links.Self != "/api/v2/notificationRules/020f755c3c082000/deliveries" {
+				t.Errorf("unexpected links %+v", res.Links)
+			}
+		})
+	}
+}
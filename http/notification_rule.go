@@ -32,6 +32,8 @@ type NotificationRuleBackend struct {
 	UserService                 influxdb.UserService
 	OrganizationService         influxdb.OrganizationService
 	TaskService                 influxdb.TaskService
+	MonitorEvaluationService    influxdb.MonitorEvaluationService
+	DeliveryLog                 influxdb.DeliveryLog
 }
 
 // NewNotificationRuleBackend returns a new instance of NotificationRuleBackend.
@@ -47,6 +49,8 @@ func NewNotificationRuleBackend(log *zap.Logger, b *APIBackend) *NotificationRul
 		UserService:                 b.UserService,
 		OrganizationService:         b.OrganizationService,
 		TaskService:                 b.TaskService,
+		MonitorEvaluationService:    b.MonitorEvaluationService,
+		DeliveryLog:                 b.DeliveryLog,
 	}
 }
 
@@ -63,12 +67,15 @@ type NotificationRuleHandler struct {
 	UserService                 influxdb.UserService
 	OrganizationService         influxdb.OrganizationService
 	TaskService                 influxdb.TaskService
+	MonitorEvaluationService    influxdb.MonitorEvaluationService
+	DeliveryLog                 influxdb.DeliveryLog
 }
 
 const (
 	prefixNotificationRules          = "/api/v2/notificationRules"
 	notificationRulesIDPath          = "/api/v2/notificationRules/:id"
 	notificationRulesIDQueryPath     = "/api/v2/notificationRules/:id/query"
+	notificationRulesIDEvaluatePath  = "/api/v2/notificationRules/:id/evaluate"
 	notificationRulesIDMembersPath   = "/api/v2/notificationRules/:id/members"
 	notificationRulesIDMembersIDPath = "/api/v2/notificationRules/:id/members/:userID"
 	notificationRulesIDOwnersPath    = "/api/v2/notificationRules/:id/owners"
@@ -91,6 +98,8 @@ func NewNotificationRuleHandler(log *zap.Logger, b *NotificationRuleBackend) *No
 		UserService:                 b.UserService,
 		OrganizationService:         b.OrganizationService,
 		TaskService:                 b.TaskService,
+		MonitorEvaluationService:    b.MonitorEvaluationService,
+		DeliveryLog:                 b.DeliveryLog,
 	}
 	h.HandlerFunc("POST", prefixNotificationRules, h.handlePostNotificationRule)
 	h.HandlerFunc("GET", prefixNotificationRules, h.handleGetNotificationRules)
@@ -99,6 +108,13 @@ func NewNotificationRuleHandler(log *zap.Logger, b *NotificationRuleBackend) *No
 	h.HandlerFunc("DELETE", notificationRulesIDPath, h.handleDeleteNotificationRule)
 	h.HandlerFunc("PUT", notificationRulesIDPath, h.handlePutNotificationRule)
 	h.HandlerFunc("PATCH", notificationRulesIDPath, h.handlePatchNotificationRule)
+	h.HandlerFunc("POST", notificationRulesIDPath, h.handlePostNotificationRuleID)
+	if h.MonitorEvaluationService != nil {
+		h.HandlerFunc("POST", notificationRulesIDEvaluatePath, h.handlePostNotificationRuleEvaluate)
+	}
+	if h.DeliveryLog != nil {
+		h.HandlerFunc("GET", notificationRulesIDDeliveriesPath, h.handleGetNotificationRuleDeliveries)
+	}
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/rule"
+	"go.uber.org/zap"
+)
+
+// notificationRulesPreviewPath shares its route with notificationRulesIDPath, the router
+// doesn't allow a static segment next to the :id parameter.
+const notificationRulesPreviewPath = "/api/v2/notificationRules/preview"
+
+type postNotificationRulePreviewRequest struct {
+	Rule   json.RawMessage        `json:"rule"`
+	Record map[string]interface{} `json:"record,omitempty"`
+}
+
+// handlePostNotificationRuleID handles the POST requests of notificationRulesIDPath,
+// of which only the preview and the evaluation exist.
+func (h *NotificationRuleHandler) handlePostNotificationRuleID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	switch httprouter.ParamsFromContext(ctx).ByName("id") {
+	case "preview":
+		h.handlePostNotificationRulePreview(w, r)
+	case "evaluate":
+		if h.MonitorEvaluationService != nil {
+			h.handlePostUnsavedNotificationRuleEvaluate(w, r)
+			return
+		}
+		fallthrough
+	default:
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EMethodNotAllowed,
+			Msg:  "method not allowed",
+		}, w)
+	}
+}
+
+// handlePostNotificationRulePreview renders the request an http notification rule sends
+// for a status record, without creating the rule.
+func (h *NotificationRuleHandler) handlePostNotificationRulePreview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req postNotificationRulePreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+	nr, err := rule.UnmarshalJSON(req.Rule)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+	httpRule, ok := nr.(*rule.HTTP)
+	if !ok {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "only http notification rules can be previewed",
+		}, w)
+		return
+	}
+
+	p, err := httpRule.Preview(ctx, req.Record)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Notification rule previewed", zap.String("rule", httpRule.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, p); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/notification/rule"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestNotificationRuleHandler_Preview(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		want       *rule.HTTPPreview
+	}{
+		{
+			name: "renders templates",
+			path: notificationRulesPreviewPath,
+			body: `{
+  "rule": {"type": "http", "name": "foo", "bodyTemplate": "${r._check_name} is ${r._level}", "headers": {"x-level": "${r._level}"}},
+  "record": {"_check_name": "cpu", "_level": "crit"}
+}`,
+			wantStatus: http.StatusOK,
+			want: &rule.HTTPPreview{
+				Headers: map[string]string{
+					"Content-Type": "application/json",
+					"X-Level":      "crit",
+				},
+				Body: "cpu is crit",
+			},
+		},
+		{
+			name:       "invalid template",
+			path:       notificationRulesPreviewPath,
+			body:       `{"rule": {"type": "http", "bodyTemplate": "${r._level"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not an http rule",
+			path:       notificationRulesPreviewPath,
+			body:       `{"rule": {"type": "slack", "messageTemplate": "${r._level}"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "post to a rule",
+			path:       "/api/v2/notificationRules/020f755c3c082000",
+			body:       `{}`,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewMockNotificationRuleBackend(t)
+			b.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			h := NewNotificationRuleHandler(zaptest.NewLogger(t), b)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", tt.path, bytes.NewBufferString(tt.body))
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.want == nil {
+				return
+			}
+			var got rule.HTTPPreview
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(&got, tt.want) {
+				t.Errorf("unexpected preview. want:\n%+v\ngot:\n%+v", tt.want, got)
+			}
+		})
+	}
+}
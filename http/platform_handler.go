@@ -25,6 +25,8 @@ func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
+	h.RegisterNoAuthRoute("GET", "/api/v2/signin/oauth")
+	h.RegisterNoAuthRoute("GET", "/api/v2/signin/oauth/callback")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signout")
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
@@ -53,6 +55,7 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Serve the chronograf assets for any basepath that does not start with addressable parts
 	// of the platform API.
 	if !strings.HasPrefix(r.URL.Path, "/v1") &&
+		!strings.HasPrefix(r.URL.Path, "/api/v1") &&
 		!strings.HasPrefix(r.URL.Path, "/api/v2") &&
 		!strings.HasPrefix(r.URL.Path, "/chronograf/") {
 		h.AssetHandler.ServeHTTP(w, r)
@@ -0,0 +1,336 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	http "net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/promql"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+)
+
+// maxPromQLPoints is the maximum number of steps a range query may evaluate.
+const maxPromQLPoints = 11000
+
+// PromQLBackend is all services and associated parameters required to construct
+// the PromQLHandler.
+type PromQLBackend struct {
+	log *zap.Logger
+	influxdb.HTTPErrorHandler
+
+	ProxyQueryService   query.ProxyQueryService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewPromQLBackend returns a new instance of PromQLBackend.
+func NewPromQLBackend(log *zap.Logger, b *APIBackend) *PromQLBackend {
+	return &PromQLBackend{
+		log: log,
+
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		ProxyQueryService:   b.FluxService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// PromQLHandler serves the Prometheus query API evaluating PromQL against a bucket.
+type PromQLHandler struct {
+	influxdb.HTTPErrorHandler
+	*httprouter.Router
+
+	log *zap.Logger
+	now func() time.Time
+
+	ProxyQueryService   query.ProxyQueryService
+	BucketService       influxdb.BucketService
+	OrganizationService influxdb.OrganizationService
+}
+
+const (
+	prefixPromQL     = "/api/v1"
+	promQLQueryPath  = "/api/v1/query"
+	promQLRangePath  = "/api/v1/query_range"
+	promQLBucketHelp = "please provide either bucketID or bucket, or use a token that can read a single bucket"
+)
+
+// NewPromQLHandler creates a new handler at /api/v1 to evaluate PromQL queries.
+func NewPromQLHandler(log *zap.Logger, b *PromQLBackend) *PromQLHandler {
+	h := &PromQLHandler{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Router:           NewRouter(b.HTTPErrorHandler),
+		log:              log,
+		now:              time.Now,
+
+		ProxyQueryService:   b.ProxyQueryService,
+		BucketService:       b.BucketService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", promQLQueryPath, h.handleQuery)
+	h.HandlerFunc("POST", promQLQueryPath, h.handleQuery)
+	h.HandlerFunc("GET", promQLRangePath, h.handleQueryRange)
+	h.HandlerFunc("POST", promQLRangePath, h.handleQueryRange)
+	return h
+}
+
+func (h *PromQLHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromQLHandler")
+	defer span.Finish()
+
+	if err := r.ParseForm(); err != nil {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, err)
+		return
+	}
+
+	eval := promql.Evaluation{Start: h.now()}
+	if t := r.Form.Get("time"); t != "" {
+		ts, err := parsePromQLTime(t)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, fmt.Errorf("invalid parameter 'time': %v", err))
+			return
+		}
+		eval.Start = ts
+	}
+	h.evaluate(w, r, eval)
+}
+
+func (h *PromQLHandler) handleQueryRange(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "PromQLHandler")
+	defer span.Finish()
+
+	if err := r.ParseForm(); err != nil {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, err)
+		return
+	}
+
+	var (
+		eval promql.Evaluation
+		err  error
+	)
+	if eval.Start, err = parsePromQLTime(r.Form.Get("start")); err != nil {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, fmt.Errorf("invalid parameter 'start': %v", err))
+		return
+	}
+	if eval.End, err = parsePromQLTime(r.Form.Get("end")); err != nil {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, fmt.Errorf("invalid parameter 'end': %v", err))
+		return
+	}
+	if eval.Step, err = parsePromQLDuration(r.Form.Get("step")); err != nil {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, fmt.Errorf("invalid parameter 'step': %v", err))
+		return
+	}
+	if eval.Step <= 0 {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, fmt.Errorf("zero or negative query resolution step widths are not accepted. Try a positive integer"))
+		return
+	}
+	if eval.End.Sub(eval.Start)/eval.Step > maxPromQLPoints {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, fmt.Errorf("exceeded maximum resolution of 11,000 points per timeseries. Try decreasing the query resolution (?step=XX)"))
+		return
+	}
+	h.evaluate(w, r, eval)
+}
+
+// evaluate runs the query of the request as described by eval.
+func (h *PromQLHandler) evaluate(w http.ResponseWriter, r *http.Request, eval promql.Evaluation) {
+	ctx := r.Context()
+
+	q := r.Form.Get("query")
+	parsed, err := promql.ParsePromQL(q)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, fmt.Errorf("invalid parameter 'query': %v", err))
+		return
+	}
+	expr, ok := parsed.(promql.Expr)
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, fmt.Errorf("invalid parameter 'query': %T is not an expression", parsed))
+		return
+	}
+	if n, ok := expr.(*promql.Number); ok {
+		// Numbers are answered without reading any data.
+		h.respond(w, http.StatusOK, promql.NewScalarResponse(eval.Start, n.Val))
+		return
+	}
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Op:   "http/handlePromQLQuery",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	b, err := h.findBucket(ctx, r, a)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	p, err := influxdb.NewPermissionAtID(b.ID, influxdb.ReadAction, influxdb.BucketsResourceType, b.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if !a.Allowed(*p) {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   "http/handlePromQLQuery",
+			Msg:  "insufficient permissions to read bucket",
+		}, w)
+		return
+	}
+	token, err := queryAuthorization(a, b.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// Build the query first so invalid expressions are reported as bad data.
+	eval.BucketID = b.ID.String()
+	if _, err := promql.BuildEvaluation(q, eval); err != nil {
+		h.respondError(w, http.StatusBadRequest, promql.ErrorBadData, err)
+		return
+	}
+
+	resultType := promql.VectorResult
+	if eval.IsRange() || promql.IsMatrix(expr, eval) {
+		resultType = promql.MatrixResult
+	}
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  token,
+			OrganizationID: b.OrgID,
+			Compiler: &promql.Compiler{
+				Query:    q,
+				BucketID: eval.BucketID,
+				Start:    eval.Start,
+				End:      eval.End,
+				Step:     eval.Step,
+			},
+			Source: r.Header.Get("User-Agent"),
+		},
+		Dialect: &promql.Dialect{
+			ResultType: resultType,
+		},
+	}
+
+	ctx = pcontext.SetAuthorizer(ctx, token)
+	w.Header().Set("Content-Type", "application/json")
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+		if cw.Count() == 0 {
+			// Only respond with an error IFF nothing has been written to w.
+			h.respondError(w, http.StatusUnprocessableEntity, promql.ErrorExecution, err)
+			return
+		}
+		h.log.Info("Error writing response to client",
+			zap.String("handler", "promql"),
+			zap.Error(err),
+		)
+	}
+}
+
+// findBucket returns the bucket named by the request or, when there is none,
+// the single bucket the authorizer can read.
+func (h *PromQLHandler) findBucket(ctx context.Context, r *http.Request, a influxdb.Authorizer) (*influxdb.Bucket, error) {
+	if id := r.Form.Get(BucketID); id != "" {
+		bucketID, err := influxdb.IDFromString(id)
+		if err != nil {
+			return nil, err
+		}
+		return h.BucketService.FindBucketByID(ctx, *bucketID)
+	}
+
+	if name := r.Form.Get(Bucket); name != "" {
+		filter := influxdb.BucketFilter{Name: &name}
+		if id := r.Form.Get(OrgID); id != "" {
+			orgID, err := influxdb.IDFromString(id)
+			if err != nil {
+				return nil, err
+			}
+			filter.OrganizationID = orgID
+		} else if org := r.Form.Get(Org); org != "" {
+			filter.Org = &org
+		}
+		return h.BucketService.FindBucket(ctx, filter)
+	}
+
+	auth, ok := a.(*influxdb.Authorization)
+	if !ok {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  promQLBucketHelp,
+		}
+	}
+	var bucketID *influxdb.ID
+	for _, p := range auth.Permissions {
+		if p.Action != influxdb.ReadAction || p.Resource.Type != influxdb.BucketsResourceType || p.Resource.ID == nil {
+			continue
+		}
+		if bucketID != nil && *bucketID != *p.Resource.ID {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  promQLBucketHelp,
+			}
+		}
+		bucketID = p.Resource.ID
+	}
+	if bucketID == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  promQLBucketHelp,
+		}
+	}
+	return h.BucketService.FindBucketByID(ctx, *bucketID)
+}
+
+func (h *PromQLHandler) respondError(w http.ResponseWriter, code int, typ promql.ErrorType, err error) {
+	h.respond(w, code, promql.NewErrorResponse(typ, err))
+}
+
+func (h *PromQLHandler) respond(w http.ResponseWriter, code int, resp *promql.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Info("Failed to encode PromQL response", zap.Error(err))
+	}
+}
+
+// parsePromQLTime parses a time given as RFC3339 or unix seconds.
+func parsePromQLTime(s string) (time.Time, error) {
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, frac := math.Modf(t)
+		return time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+	}
+	return t, nil
+}
+
+// parsePromQLDuration parses a duration given as seconds or a Prometheus duration.
+func parsePromQLDuration(s string) (time.Duration, error) {
+	if d, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(d * float64(time.Second)), nil
+	}
+	d, err := model.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+	}
+	return time.Duration(d), nil
+}
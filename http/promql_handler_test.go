@@ -0,0 +1,304 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	influxmock "github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/mock"
+	"github.com/influxdata/influxdb/query/promql"
+	influxtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPromQLHandler(t *testing.T) {
+	var (
+		orgID    = influxtesting.MustIDBase16("3070616e656d2076")
+		bucketID = influxtesting.MustIDBase16("0d0a657820696e74")
+		otherID  = influxtesting.MustIDBase16("2070616e656d2076")
+	)
+	readBucket := func(id influxdb.ID) influxdb.Permission {
+		p, err := influxdb.NewPermissionAtID(id, influxdb.ReadAction, influxdb.BucketsResourceType, orgID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *p
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		params     url.Values
+		auth       *influxdb.Authorization
+		status     int
+		errorType  promql.ErrorType
+		resultType promql.ResultType
+	}{
+		{
+			name:       "scalar",
+			path:       promQLQueryPath,
+			params:     url.Values{"query": {"1 + 2"}, "time": {"1572566400"}},
+			status:     http.StatusOK,
+			resultType: promql.ScalarResult,
+		},
+		{
+			name:   "bucket of token",
+			path:   promQLQueryPath,
+			params: url.Values{"query": {"up"}},
+			auth: &influxdb.Authorization{
+				OrgID:       orgID,
+				Status:      influxdb.Active,
+				Permissions: []influxdb.Permission{readBucket(bucketID)},
+			},
+			status:     http.StatusOK,
+			resultType: promql.VectorResult,
+		},
+		{
+			name:   "range query",
+			path:   promQLRangePath,
+			params: url.Values{"query": {"up"}, "bucketID": {bucketID.String()}, "start": {"1572566100"}, "end": {"1572566400"}, "step": {"1m"}},
+			auth: &influxdb.Authorization{
+				OrgID:       orgID,
+				Status:      influxdb.Active,
+				Permissions: []influxdb.Permission{readBucket(bucketID)},
+			},
+			status:     http.StatusOK,
+			resultType: promql.MatrixResult,
+		},
+		{
+			name:   "range selector",
+			path:   promQLQueryPath,
+			params: url.Values{"query": {"up[5m]"}, "bucketID": {bucketID.String()}},
+			auth: &influxdb.Authorization{
+				OrgID:       orgID,
+				Status:      influxdb.Active,
+				Permissions: []influxdb.Permission{readBucket(bucketID)},
+			},
+			status:     http.StatusOK,
+			resultType: promql.MatrixResult,
+		},
+		{
+			name:   "ambiguous bucket",
+			path:   promQLQueryPath,
+			params: url.Values{"query": {"up"}},
+			auth: &influxdb.Authorization{
+				OrgID:       orgID,
+				Status:      influxdb.Active,
+				Permissions: []influxdb.Permission{readBucket(bucketID), readBucket(otherID)},
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unreadable bucket",
+			path:   promQLQueryPath,
+			params: url.Values{"query": {"up"}, "bucketID": {bucketID.String()}},
+			auth: &influxdb.Authorization{
+				OrgID:       orgID,
+				Status:      influxdb.Active,
+				Permissions: []influxdb.Permission{readBucket(otherID)},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:      "invalid step",
+			path:      promQLRangePath,
+			params:    url.Values{"query": {"up"}, "start": {"1572566100"}, "end": {"1572566400"}, "step": {"0"}},
+			status:    http.StatusBadRequest,
+			errorType: promql.ErrorBadData,
+		},
+		{
+			name:   "invalid expression",
+			path:   promQLQueryPath,
+			params: url.Values{"query": {"rate(up)"}, "bucketID": {bucketID.String()}},
+			auth: &influxdb.Authorization{
+				OrgID:       orgID,
+				Status:      influxdb.Active,
+				Permissions: []influxdb.Permission{readBucket(bucketID)},
+			},
+			status:    http.StatusBadRequest,
+			errorType: promql.ErrorBadData,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *query.ProxyRequest
+			buckets := influxmock.NewBucketService()
+			buckets.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+				return &influxdb.Bucket{ID: id, OrgID: orgID, Name: "prometheus"}, nil
+			}
+			h := NewPromQLHandler(zaptest.NewLogger(t), &PromQLBackend{
+				log:              zaptest.NewLogger(t),
+				HTTPErrorHandler: kithttp.ErrorHandler(0),
+				BucketService:    buckets,
+				ProxyQueryService: &mock.ProxyQueryService{
+					QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+						got = req
+						return flux.Statistics{}, json.NewEncoder(w).Encode(promql.Response{
+							Status: "success",
+							Data:   &promql.Data{ResultType: req.Dialect.(*promql.Dialect).ResultType, Result: []*promql.Series{}},
+						})
+					},
+				},
+			})
+
+			r := httptest.NewRequest("GET", tt.path+"?"+tt.params.Encode(), nil)
+			if tt.auth != nil {
+				r = r.WithContext(icontext.SetAuthorizer(r.Context(), tt.auth))
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				if tt.errorType == "" {
+					return
+				}
+				var resp promql.Response
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatal(err)
+				}
+				if resp.ErrorType != tt.errorType {
+					t.Errorf("unexpected error type %q: %s", resp.ErrorType, resp.Error)
+				}
+				return
+			}
+
+			var resp struct {
+				Data struct {
+					ResultType promql.ResultType `json:"resultType"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Data.ResultType != tt.resultType {
+				t.Errorf("unexpected result type %q, want %q", resp.Data.ResultType, tt.resultType)
+			}
+			if got == nil {
+				return
+			}
+			c := got.Request.Compiler.(*promql.Compiler)
+			if c.BucketID != bucketID.String() {
+				t.Errorf("unexpected bucket %q, want %q", c.BucketID, bucketID)
+			}
+			if got.Request.OrganizationID != orgID {
+				t.Errorf("unexpected organization %v, want %v", got.Request.OrganizationID, orgID)
+			}
+		})
+	}
+}
+
+func TestParsePromQLTime(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want time.Time
+	}{
+		{s: "1572566400", want: time.Unix(1572566400, 0).UTC()},
+		{s: "1572566400.5", want: time.Unix(1572566400, int64(500*time.Millisecond)).UTC()},
+		{s: "2019-11-01T00:00:00Z", want: time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		got, err := parsePromQLTime(tt.s)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.s, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.s, got, tt.want)
+		}
+	}
+	if _, err := parsePromQLTime("yesterday"); err == nil {
+		t.Error("expected an error parsing an invalid time")
+	}
+}
+
+func TestPromQLHandler_QueryError(t *testing.T) {
+	var (
+		orgID    = influxtesting.MustIDBase16("3070616e656d2076")
+		bucketID = influxtesting.MustIDBase16("0d0a657820696e74")
+	)
+	p, err := influxdb.NewPermissionAtID(bucketID, influxdb.ReadAction, influxdb.BucketsResourceType, orgID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := &influxdb.Authorization{
+		OrgID:       orgID,
+		Status:      influxdb.Active,
+		Permissions: []influxdb.Permission{*p},
+	}
+
+	tests := []struct {
+		name    string
+		written bool
+		status  int
+	}{
+		{
+			name:   "error before the response",
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "error after the response started",
+			written: true,
+			status:  http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := influxmock.NewBucketService()
+			buckets.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+				return &influxdb.Bucket{ID: id, OrgID: orgID, Name: "prometheus"}, nil
+			}
+			h := NewPromQLHandler(zaptest.NewLogger(t), &PromQLBackend{
+				log:              zaptest.NewLogger(t),
+				HTTPErrorHandler: kithttp.ErrorHandler(0),
+				BucketService:    buckets,
+				ProxyQueryService: &mock.ProxyQueryService{
+					QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+						if tt.written {
+							if _, err := io.WriteString(w, `{"status":"success"`); err != nil {
+								return flux.Statistics{}, err
+							}
+						}
+						return flux.Statistics{}, errors.New("query failed")
+					},
+				},
+			})
+
+			params := url.Values{"query": {"up"}, "bucketID": {bucketID.String()}}
+			r := httptest.NewRequest("GET", promQLQueryPath+"?"+params.Encode(), nil)
+			r = r.WithContext(icontext.SetAuthorizer(r.Context(), auth))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.written {
+				if got := w.Body.String(); got != `{"status":"success"` {
+					t.Errorf("expected nothing to be written after the response started, got %s", got)
+				}
+				return
+			}
+			var resp promql.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.ErrorType != promql.ErrorExecution {
+				t.Errorf("unexpected error type %q: %s", resp.ErrorType, resp.Error)
+			}
+		})
+	}
+}
@@ -431,18 +431,93 @@ func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth influxdb
 		return nil, n, err
 	}
 
-	var token *influxdb.Authorization
+	token, err := queryAuthorization(auth, req.Org.ID)
+	if err != nil {
+		return pr, n, err
+	}
+
+	pr.Request.Authorization = token
+	return pr, n, nil
+}
+
+// queryAuthorization returns the authorization a query for the
+// organization runs with on behalf of auth.
+func queryAuthorization(auth influxdb.Authorizer, orgID influxdb.ID) (*influxdb.Authorization, error) {
 	switch a := auth.(type) {
 	case *influxdb.Authorization:
-		token = a
+		return a, nil
 	case *influxdb.Session:
-		token = a.EphemeralAuth(req.Org.ID)
+		return a.EphemeralAuth(orgID), nil
 	case *jsonweb.Token:
-		token = a.EphemeralAuth(req.Org.ID)
+		return a.EphemeralAuth(orgID), nil
 	default:
-		return pr, n, influxdb.ErrAuthorizerNotSupported
+		return nil, influxdb.ErrAuthorizerNotSupported
 	}
+}
 
-	pr.Request.Authorization = token
-	return pr, n, nil
+// QueryExplainRequest is a request to explain how a flux query is planned
+// and, optionally, to profile its execution.
+type QueryExplainRequest struct {
+	Query  string       `json:"query,omitempty"`
+	Extern *ast.File    `json:"extern,omitempty"`
+	AST    *ast.Package `json:"ast,omitempty"`
+
+	// Profile executes the query and reports the output of every operator.
+	Profile bool `json:"profile"`
+
+	Org *influxdb.Organization `json:"-"`
+}
+
+// Validate checks the explain request and returns an error if the request is invalid.
+func (r QueryExplainRequest) Validate() error {
+	if r.Query == "" && r.AST == nil {
+		return errors.New(`request body requires either query or AST`)
+	}
+	return nil
+}
+
+// Package returns the flux package that the request explains.
+// Query is preferred over AST.
+func (r QueryExplainRequest) Package() (*ast.Package, error) {
+	var pkg *ast.Package
+	if r.Query != "" {
+		pkg = parser.ParseSource(r.Query)
+	} else {
+		pkg = r.AST.Copy().(*ast.Package)
+	}
+	if ast.Check(pkg) > 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid AST",
+			Err:  ast.GetError(pkg),
+		}
+	}
+	if r.Extern != nil {
+		pkg.Files = append([]*ast.File{r.Extern}, pkg.Files...)
+	}
+	return pkg, nil
+}
+
+func decodeQueryExplainRequest(ctx context.Context, r *http.Request, svc influxdb.OrganizationService) (*QueryExplainRequest, error) {
+	var req QueryExplainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json",
+			Err:  err,
+		}
+	}
+	if err := req.Validate(); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	org, err := queryOrganization(ctx, r, svc)
+	if err != nil {
+		return nil, err
+	}
+	req.Org = org
+	return &req, nil
 }
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/NYTimes/gziphandler"
@@ -28,6 +29,7 @@ import (
 	kithttp "github.com/influxdata/influxdb/kit/transport/http"
 	"github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/query"
+	querycache "github.com/influxdata/influxdb/query/cache"
 	"github.com/influxdata/influxdb/query/influxql"
 	"github.com/pkg/errors"
 	prom "github.com/prometheus/client_golang/prometheus"
@@ -35,8 +37,9 @@ import (
 )
 
 const (
-	prefixQuery   = "/api/v2/query"
-	traceIDHeader = "Trace-Id"
+	prefixQuery        = "/api/v2/query"
+	prefixQueryExplain = "/api/v2/query/explain"
+	traceIDHeader      = "Trace-Id"
 )
 
 // FluxBackend is all services and associated parameters required to construct
@@ -106,6 +109,7 @@ func NewFluxHandler(log *zap.Logger, b *FluxBackend) *FluxHandler {
 	h.Handler("POST", prefixQuery, qh)
 	h.HandlerFunc("POST", "/api/v2/query/ast", h.postFluxAST)
 	h.HandlerFunc("POST", "/api/v2/query/analyze", h.postQueryAnalyze)
+	h.HandlerFunc("POST", prefixQueryExplain, h.postQueryExplain)
 	h.HandlerFunc("GET", "/api/v2/query/suggestions", h.getFluxSuggestions)
 	h.HandlerFunc("GET", "/api/v2/query/suggestions/:name", h.getFluxSuggestion)
 	return h
@@ -181,7 +185,7 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 	hd.SetHeaders(w)
 
 	cw := iocounter.Writer{Writer: w}
-	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+	if _, err := h.ProxyQueryService.Query(ctx, &cacheStatusWriter{Writer: &cw, header: w.Header()}, req); err != nil {
 		if cw.Count() == 0 {
 			// Only record the error headers IFF nothing has been written to w.
 			h.HandleHTTPError(ctx, err, w)
@@ -195,6 +199,22 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// cacheStatusWriter reports how the query result cache served a query
+// in the Query-Cache-Status and Age response headers.
+type cacheStatusWriter struct {
+	io.Writer
+	header http.Header
+}
+
+func (w *cacheStatusWriter) SetCacheStatus(s querycache.Status) {
+	if !s.Hit {
+		w.header.Set("Query-Cache-Status", "miss")
+		return
+	}
+	w.header.Set("Query-Cache-Status", "hit")
+	w.header.Set("Age", strconv.Itoa(int(s.Age.Seconds())))
+}
+
 type langRequest struct {
 	Query string `json:"query"`
 }
@@ -270,6 +290,85 @@ func (h *FluxHandler) postQueryAnalyze(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// postQueryExplain returns the logical and physical plans of a flux query.
+// When profiling is requested the query is executed, its results are
+// discarded and the output of every operator is reported.
+func (h *FluxHandler) postQueryExplain(w http.ResponseWriter, r *http.Request) {
+	const op = "http/postQueryExplain"
+	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	req, err := decodeQueryExplainRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	pkg, err := req.Package()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	e, err := query.Explain(ctx, pkg, h.Now())
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to plan query",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if req.Profile {
+		auth, err := queryAuthorization(a, req.Org.ID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		ctx = pcontext.SetAuthorizer(ctx, auth)
+
+		profiler := query.NewProfiler()
+		if err := profiler.Instrument(e.Spec); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		preq := &query.ProxyRequest{
+			Request: query.Request{
+				Authorization:  auth,
+				OrganizationID: req.Org.ID,
+				Compiler:       query.ProfileCompiler{Spec: e.Spec},
+				Source:         r.Header.Get("User-Agent"),
+			},
+			Dialect: &query.NoContentDialect{},
+		}
+		stats, err := h.ProxyQueryService.Query(ctx, ioutil.Discard, preq)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		e.Profile = profiler.Profile(stats)
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, e.Explanation); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
 // fluxParams contain flux funciton parameters as defined by the semantic graph
 type fluxParams map[string]string
 
@@ -420,6 +519,54 @@ func (s *FluxService) Query(ctx context.Context, w io.Writer, r *query.ProxyRequ
 	return flux.Statistics{}, nil
 }
 
+// Explain returns the query plans of the flux query in the request.
+// The query is also executed when the request asks for a profile.
+func (s *FluxService) Explain(ctx context.Context, orgID influxdb.ID, req *QueryExplainRequest) (*query.Explanation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, prefixQueryExplain)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	params := url.Values{}
+	params.Set(OrgID, orgID.String())
+	u.RawQuery = params.Encode()
+
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(req); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+
+	hreq, err := http.NewRequest("POST", u.String(), &body)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	SetToken(s.Token, hreq)
+	hreq.Header.Set("Content-Type", "application/json")
+	if s.Name != "" {
+		hreq.Header.Add("User-Agent", s.Name)
+	}
+	hreq = hreq.WithContext(ctx)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(hreq)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+
+	var e query.Explanation
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return &e, nil
+}
+
 func (s FluxService) Check(ctx context.Context) check.Response {
 	return QueryHealthCheck(s.Addr, s.InsecureSkipVerify)
 }
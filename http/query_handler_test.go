@@ -28,6 +28,8 @@ import (
 	kithttp "github.com/influxdata/influxdb/kit/transport/http"
 	influxmock "github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/query"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	querycache "github.com/influxdata/influxdb/query/cache"
 	"github.com/influxdata/influxdb/query/mock"
 	"go.uber.org/zap/zaptest"
 )
@@ -449,6 +451,76 @@ func TestFluxHandler_PostQuery_Errors(t *testing.T) {
 	})
 }
 
+func TestFluxHandler_PostQuery_CacheStatus(t *testing.T) {
+	orgSVC := newInMemKVSVC(t)
+	org := influxdb.Organization{Name: t.Name()}
+	if err := orgSVC.CreateOrganization(context.Background(), &org); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		status     *querycache.Status
+		wantStatus string
+		wantAge    string
+	}{
+		{
+			name: "not cached",
+		},
+		{
+			name:       "miss",
+			status:     &querycache.Status{},
+			wantStatus: "miss",
+		},
+		{
+			name:       "hit",
+			status:     &querycache.Status{Hit: true, Age: 30 * time.Second},
+			wantStatus: "hit",
+			wantAge:    "30",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &FluxBackend{
+				HTTPErrorHandler:    kithttp.ErrorHandler(0),
+				log:                 zaptest.NewLogger(t),
+				QueryEventRecorder:  noopEventRecorder{},
+				OrganizationService: orgSVC,
+				ProxyQueryService: &mock.ProxyQueryService{
+					QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+						if sw, ok := w.(querycache.StatusWriter); ok && tt.status != nil {
+							sw.SetCacheStatus(*tt.status)
+						}
+						_, err := io.WriteString(w, "#datatype,string\n")
+						return flux.Statistics{}, err
+					},
+				},
+			}
+			h := NewFluxHandler(zaptest.NewLogger(t), b)
+
+			req, err := http.NewRequest("POST", "/api/v2/query?orgID="+org.ID.String(), strings.NewReader(`from(bucket: "b") |> range(start: -1h)`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = req.WithContext(icontext.SetAuthorizer(req.Context(), &influxdb.Authorization{}))
+			req.Header.Set("Content-Type", "application/vnd.flux")
+
+			w := httptest.NewRecorder()
+			h.handleQuery(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Query-Cache-Status"); got != tt.wantStatus {
+				t.Errorf("unexpected Query-Cache-Status header; got %q, want %q", got, tt.wantStatus)
+			}
+			if got := w.Header().Get("Age"); got != tt.wantAge {
+				t.Errorf("unexpected Age header; got %q, want %q", got, tt.wantAge)
+			}
+		})
+	}
+}
+
 func TestFluxService_Query_gzip(t *testing.T) {
 	// orgService is just to mock out orgs by returning
 	// the same org every time.
@@ -685,3 +757,104 @@ func benchmarkQuery(b *testing.B, disableCompression bool) {
 
 	}
 }
+
+func TestFluxHandler_PostQueryExplain(t *testing.T) {
+	orgSVC := newInMemKVSVC(t)
+	org := influxdb.Organization{Name: t.Name()}
+	if err := orgSVC.CreateOrganization(context.Background(), &org); err != nil {
+		t.Fatal(err)
+	}
+
+	var profiled bool
+	b := &FluxBackend{
+		HTTPErrorHandler:    kithttp.ErrorHandler(0),
+		log:                 zaptest.NewLogger(t),
+		QueryEventRecorder:  noopEventRecorder{},
+		OrganizationService: orgSVC,
+		ProxyQueryService: &mock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				if _, ok := req.Request.Compiler.(query.ProfileCompiler); !ok {
+					t.Fatalf("expected a profile compiler, got %T", req.Request.Compiler)
+				}
+				if req.Request.OrganizationID != org.ID {
+					t.Fatalf("unexpected organization %s", req.Request.OrganizationID)
+				}
+				profiled = true
+				return flux.Statistics{TotalDuration: time.Second}, nil
+			},
+		},
+	}
+	h := NewFluxHandler(zaptest.NewLogger(t), b)
+
+	explain := func(t *testing.T, body string) (*query.Explanation, int) {
+		t.Helper()
+		r := httptest.NewRequest("POST", "/api/v2/query/explain?orgID="+org.ID.String(), strings.NewReader(body))
+		r = r.WithContext(icontext.SetAuthorizer(r.Context(), &influxdb.Authorization{}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			return nil, w.Code
+		}
+		var e query.Explanation
+		if err := json.Unmarshal(w.Body.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		return &e, w.Code
+	}
+
+	t.Run("explain", func(t *testing.T) {
+		e, code := explain(t, `{"query": "from(bucket: \"b\") |> range(start: -1h) |> filter(fn: (r) => r.host == \"a\")"}`)
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status %d", code)
+		}
+		want := []query.Pushdown{
+			{
+				Node:             "merged_ReadRange_filter2",
+				StorageOperation: "ReadFilter",
+				Operations: []query.PushdownOperation{
+					{ID: "influxDBFrom0", Kind: "influxDBFrom"},
+					{ID: "range1", Kind: "range"},
+					{ID: "filter2", Kind: "filter"},
+				},
+			},
+		}
+		if !cmp.Equal(want, e.Pushdowns) {
+			t.Errorf("unexpected pushdowns -want/+got:\n%s", cmp.Diff(want, e.Pushdowns))
+		}
+		if e.Profile != nil {
+			t.Error("unexpected profile when profiling was not requested")
+		}
+		if profiled {
+			t.Error("query must not be executed when profiling was not requested")
+		}
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		e, code := explain(t, `{"query": "from(bucket: \"b\") |> range(start: -1h) |> count()", "profile": true}`)
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status %d", code)
+		}
+		if !profiled {
+			t.Fatal("expected the query to be executed")
+		}
+		if e.Profile == nil {
+			t.Fatal("expected a profile")
+		}
+		if e.Profile.TotalDuration != time.Second {
+			t.Errorf("unexpected total duration %v", e.Profile.TotalDuration)
+		}
+		var kinds []string
+		for _, op := range e.Profile.Operators {
+			kinds = append(kinds, op.Kind)
+		}
+		if want := []string{"ReadRangePhysKind", "count"}; !cmp.Equal(want, kinds) {
+			t.Errorf("unexpected profiled operators -want/+got:\n%s", cmp.Diff(want, kinds))
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		if _, code := explain(t, `{"query": "from(bucket: "}`); code != http.StatusBadRequest {
+			t.Errorf("unexpected status %d", code)
+		}
+	})
+}
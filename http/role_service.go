@@ -0,0 +1,574 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixRoles = "/api/v2/roles"
+)
+
+// RoleBackend is all services and associated parameters required to construct
+// the RoleHandler.
+type RoleBackend struct {
+	influxdb.HTTPErrorHandler
+	log                 *zap.Logger
+	RoleService         influxdb.RoleService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewRoleBackend creates a backend used by the role handler.
+func NewRoleBackend(log *zap.Logger, b *APIBackend) *RoleBackend {
+	return &RoleBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		log:                 log,
+		RoleService:         b.RoleService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// RoleHandler is the handler for the role service
+type RoleHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RoleService         influxdb.RoleService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewRoleHandler creates a new RoleHandler
+func NewRoleHandler(log *zap.Logger, b *RoleBackend) *RoleHandler {
+	h := &RoleHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		RoleService:         b.RoleService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixRoles)
+	membersPath := fmt.Sprintf("%s/:id/members", prefixRoles)
+
+	h.HandlerFunc("GET", prefixRoles, h.handleGetRoles)
+	h.HandlerFunc("POST", prefixRoles, h.handlePostRole)
+	h.HandlerFunc("GET", entityPath, h.handleGetRole)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchRole)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteRole)
+	h.HandlerFunc("GET", membersPath, h.handleGetRoleMembers)
+	h.HandlerFunc("POST", membersPath, h.handlePostRoleMember)
+	h.HandlerFunc("DELETE", membersPath+"/:userID", h.handleDeleteRoleMember)
+
+	return h
+}
+
+type roleLinks struct {
+	Self    string `json:"self"`
+	Org     string `json:"org"`
+	Members string `json:"members"`
+}
+
+type roleResponse struct {
+	*influxdb.Role
+	Links roleLinks `json:"links"`
+}
+
+func newRoleResponse(r *influxdb.Role) roleResponse {
+	return roleResponse{
+		Role: r,
+		Links: roleLinks{
+			Self:    fmt.Sprintf("%s/%s", prefixRoles, r.ID),
+			Org:     fmt.Sprintf("/api/v2/orgs/%s", r.OrgID),
+			Members: fmt.Sprintf("%s/%s/members", prefixRoles, r.ID),
+		},
+	}
+}
+
+type getRolesResponse struct {
+	Roles []roleResponse        `json:"roles"`
+	Links *influxdb.PagingLinks `json:"links"`
+}
+
+func (r getRolesResponse) toInfluxDB() []*influxdb.Role {
+	rs := make([]*influxdb.Role, len(r.Roles))
+	for i := range r.Roles {
+		rs[i] = r.Roles[i].Role
+	}
+	return rs
+}
+
+func newGetRolesResponse(rs []*influxdb.Role, f influxdb.RoleFilter, opts influxdb.FindOptions) getRolesResponse {
+	resp := getRolesResponse{
+		Roles: make([]roleResponse, 0, len(rs)),
+		Links: newPagingLinks(prefixRoles, opts, f, len(rs)),
+	}
+	for _, r := range rs {
+		resp.Roles = append(resp.Roles, newRoleResponse(r))
+	}
+	return resp
+}
+
+type roleMemberLinks struct {
+	Self string `json:"self"`
+	Role string `json:"role"`
+	User string `json:"user"`
+}
+
+type roleMemberResponse struct {
+	*influxdb.RoleAssignment
+	Links roleMemberLinks `json:"links"`
+}
+
+func newRoleMemberResponse(a *influxdb.RoleAssignment) roleMemberResponse {
+	return roleMemberResponse{
+		RoleAssignment: a,
+		Links: roleMemberLinks{
+			Self: fmt.Sprintf("%s/%s/members/%s", prefixRoles, a.RoleID, a.UserID),
+			Role: fmt.Sprintf("%s/%s", prefixRoles, a.RoleID),
+			User: fmt.Sprintf("/api/v2/users/%s", a.UserID),
+		},
+	}
+}
+
+type getRoleMembersResponse struct {
+	Members []roleMemberResponse `json:"members"`
+}
+
+func (r getRoleMembersResponse) toInfluxDB() []*influxdb.RoleAssignment {
+	as := make([]*influxdb.RoleAssignment, len(r.Members))
+	for i := range r.Members {
+		as[i] = r.Members[i].RoleAssignment
+	}
+	return as
+}
+
+func newGetRoleMembersResponse(as []*influxdb.RoleAssignment) getRoleMembersResponse {
+	resp := getRoleMembersResponse{
+		Members: make([]roleMemberResponse, 0, len(as)),
+	}
+	for _, a := range as {
+		resp.Members = append(resp.Members, newRoleMemberResponse(a))
+	}
+	return resp
+}
+
+type getRolesRequest struct {
+	filter influxdb.RoleFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetRolesRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*getRolesRequest, error) {
+	opts, err := decodeFindOptions(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getRolesRequest{
+		opts: *opts,
+	}
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "orgID is invalid",
+				Err:  err,
+			}
+		}
+		req.filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		req.filter.Name = &name
+	}
+
+	return req, nil
+}
+
+func (h *RoleHandler) handleGetRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetRolesRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs, _, err := h.RoleService.FindRoles(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Roles retrieved", zap.Int("roles", len(rs)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetRolesResponse(rs, req.filter, req.opts)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func requestRoleParamID(ctx context.Context, param string) (influxdb.ID, error) {
+	urlID := httprouter.ParamsFromContext(ctx).ByName(param)
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing " + param,
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+	return *id, nil
+}
+
+func (h *RoleHandler) handleGetRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleParamID(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	role, err := h.RoleService.FindRoleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role retrieved", zap.String("role", fmt.Sprint(role)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodePostRoleRequest(r *http.Request) (*influxdb.Role, error) {
+	role := &influxdb.Role{}
+	if err := json.NewDecoder(r.Body).Decode(role); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	if err := role.Valid(); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return role, nil
+}
+
+func (h *RoleHandler) handlePostRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	role, err := decodePostRoleRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.CreateRole(ctx, role); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role created", zap.String("role", fmt.Sprint(role)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newRoleResponse(role)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *RoleHandler) handlePatchRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleParamID(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	var upd influxdb.RoleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	role, err := h.RoleService.UpdateRole(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role updated", zap.String("role", fmt.Sprint(role)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *RoleHandler) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleParamID(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.DeleteRole(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role deleted", zap.String("roleID", id.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RoleHandler) handleGetRoleMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleParamID(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	filter := influxdb.RoleAssignmentFilter{RoleID: &id}
+	if userID := r.URL.Query().Get("userID"); userID != "" {
+		uid, err := influxdb.IDFromString(userID)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "userID is invalid",
+				Err:  err,
+			}, w)
+			return
+		}
+		filter.UserID = uid
+	}
+
+	as, _, err := h.RoleService.FindRoleAssignments(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role members retrieved", zap.Int("members", len(as)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetRoleMembersResponse(as)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type postRoleMemberRequest struct {
+	UserID influxdb.ID `json:"userID"`
+}
+
+func (h *RoleHandler) handlePostRoleMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleParamID(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	var req postRoleMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+	if !req.UserID.Valid() {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "userID is invalid",
+		}, w)
+		return
+	}
+
+	a := &influxdb.RoleAssignment{RoleID: id, UserID: req.UserID}
+	if err := h.RoleService.AssignRole(ctx, a); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role assigned", zap.String("roleID", id.String()), zap.String("userID", req.UserID.String()))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newRoleMemberResponse(a)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *RoleHandler) handleDeleteRoleMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestRoleParamID(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	userID, err := requestRoleParamID(ctx, "userID")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.UnassignRole(ctx, id, userID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role unassigned", zap.String("roleID", id.String()), zap.String("userID", userID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RoleService is a role service over HTTP to the influxdb server
+type RoleService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+// FindRoleByID finds a single role by its ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	var resp roleResponse
+	err := s.Client.
+		Get(prefixRoles, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Role, nil
+}
+
+// FindRoles returns the roles matching the filter and their count.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opts ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	if filter.ID != nil {
+		r, err := s.FindRoleByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*influxdb.Role{r}, 1, nil
+	}
+
+	params := findOptionParams(opts...)
+	for k, vs := range filter.QueryParams() {
+		for _, v := range vs {
+			params = append(params, [2]string{k, v})
+		}
+	}
+
+	var resp getRolesResponse
+	err := s.Client.
+		Get(prefixRoles).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	rs := resp.toInfluxDB()
+	return rs, len(rs), nil
+}
+
+// CreateRole creates a new role and assigns it an ID.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	if err := r.Valid(); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	var resp roleResponse
+	err := s.Client.
+		PostJSON(r, prefixRoles).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	*r = *resp.Role
+	return nil
+}
+
+// UpdateRole updates a single role with a changeset.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	var resp roleResponse
+	err := s.Client.
+		PatchJSON(upd, prefixRoles, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Role, nil
+}
+
+// DeleteRole removes a role by its ID along with its assignments.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(prefixRoles, id.String()).
+		Do(ctx)
+}
+
+// FindRoleAssignments returns the assignments of the role of the filter and their count.
+// The filter must have a role ID.
+func (s *RoleService) FindRoleAssignments(ctx context.Context, filter influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+	if filter.RoleID == nil {
+		return nil, 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpFindRoleAssignments,
+			Msg:  "roleID is required",
+		}
+	}
+
+	var params [][2]string
+	if filter.UserID != nil {
+		params = append(params, [2]string{"userID", filter.UserID.String()})
+	}
+
+	var resp getRoleMembersResponse
+	err := s.Client.
+		Get(prefixRoles, filter.RoleID.String(), "members").
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	as := resp.toInfluxDB()
+	return as, len(as), nil
+}
+
+// AssignRole assigns a role to a user.
+func (s *RoleService) AssignRole(ctx context.Context, a *influxdb.RoleAssignment) error {
+	var resp roleMemberResponse
+	err := s.Client.
+		PostJSON(postRoleMemberRequest{UserID: a.UserID}, prefixRoles, a.RoleID.String(), "members").
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	*a = *resp.RoleAssignment
+	return nil
+}
+
+// UnassignRole removes the assignment of a role to a user.
+func (s *RoleService) UnassignRole(ctx context.Context, roleID, userID influxdb.ID) error {
+	return s.Client.
+		Delete(prefixRoles, roleID.String(), "members", userID.String()).
+		Do(ctx)
+}
@@ -0,0 +1,191 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	influxTesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func newMockRoleBackend(t *testing.T, rs influxdb.RoleService) *RoleBackend {
+	return &RoleBackend{
+		HTTPErrorHandler:    kithttp.ErrorHandler(0),
+		log:                 zaptest.NewLogger(t),
+		RoleService:         rs,
+		OrganizationService: mock.NewOrganizationService(),
+	}
+}
+
+func TestRoleHandler(t *testing.T) {
+	var (
+		orgID  = influxTesting.MustIDBase16("020f755c3c082001")
+		userID = influxTesting.MustIDBase16("020f755c3c082002")
+		id     = influxTesting.MustIDBase16("020f755c3c082000")
+		stored = &influxdb.Role{
+			ID:    id,
+			OrgID: orgID,
+			Name:  "dashboard editor",
+			Permissions: []influxdb.Permission{{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID},
+			}},
+		}
+		name = "dashboard editor"
+	)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		wantStatus     int
+		want           *influxdb.Role
+		wantFilter     *influxdb.RoleFilter
+		wantAssignment *influxdb.RoleAssignment
+	}{
+		{
+			name:       "create a role",
+			method:     "POST",
+			path:       prefixRoles,
+			body:       `{"orgID": "020f755c3c082001", "name": "dashboard editor", "permissions": [{"action": "write", "resource": {"type": "dashboards", "orgID": "020f755c3c082001"}}]}`,
+			wantStatus: http.StatusCreated,
+			want:       stored,
+		},
+		{
+			name:       "create a role with a permission of another org",
+			method:     "POST",
+			path:       prefixRoles,
+			body:       `{"orgID": "020f755c3c082001", "name": "dashboard editor", "permissions": [{"action": "write", "resource": {"type": "dashboards", "orgID": "020f755c3c082003"}}]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "find the roles of an org by name",
+			method:     "GET",
+			path:       prefixRoles + "?orgID=020f755c3c082001&name=dashboard%20editor",
+			wantStatus: http.StatusOK,
+			wantFilter: &influxdb.RoleFilter{OrgID: &orgID, Name: &name},
+		},
+		{
+			name:       "get a role",
+			method:     "GET",
+			path:       prefixRoles + "/020f755c3c082000",
+			wantStatus: http.StatusOK,
+			want:       stored,
+		},
+		{
+			name:       "update a role",
+			method:     "PATCH",
+			path:       prefixRoles + "/020f755c3c082000",
+			body:       `{"description": "edits dashboards"}`,
+			wantStatus: http.StatusOK,
+			want:       stored,
+		},
+		{
+			name:       "delete a role",
+			method:     "DELETE",
+			path:       prefixRoles + "/020f755c3c082000",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:           "assign a role",
+			method:         "POST",
+			path:           prefixRoles + "/020f755c3c082000/members",
+			body:           `{"userID": "020f755c3c082002"}`,
+			wantStatus:     http.StatusCreated,
+			wantAssignment: &influxdb.RoleAssignment{RoleID: id, UserID: userID, OrgID: orgID},
+		},
+		{
+			name:       "assign a role without a user",
+			method:     "POST",
+			path:       prefixRoles + "/020f755c3c082000/members",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "find the members of a role",
+			method:     "GET",
+			path:       prefixRoles + "/020f755c3c082000/members",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unassign a role",
+			method:     "DELETE",
+			path:       prefixRoles + "/020f755c3c082000/members/020f755c3c082002",
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter *influxdb.RoleFilter
+			var assignment *influxdb.RoleAssignment
+			rs := mock.NewRoleService()
+			rs.CreateRoleFn = func(ctx context.Context, r *influxdb.Role) error {
+				r.ID = id
+				return nil
+			}
+			rs.FindRoleByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+				return stored, nil
+			}
+			rs.FindRolesFn = func(ctx context.Context, f influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+				filter = &f
+				return []*influxdb.Role{stored}, 1, nil
+			}
+			rs.UpdateRoleFn = func(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+				return stored, nil
+			}
+			rs.AssignRoleFn = func(ctx context.Context, a *influxdb.RoleAssignment) error {
+				a.OrgID = orgID
+				assignment = a
+				return nil
+			}
+			rs.FindRoleAssignmentsFn = func(ctx context.Context, f influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+				if f.RoleID == nil || *f.RoleID != id {
+					t.Errorf("expected the assignments of the role to be found, got filter %+v", f)
+				}
+				return []*influxdb.RoleAssignment{{RoleID: id, UserID: userID, OrgID: orgID}}, 1, nil
+			}
+			rs.UnassignRoleFn = func(ctx context.Context, roleID, uid influxdb.ID) error {
+				if roleID != id || uid != userID {
+					t.Errorf("unexpected unassignment of role %s from user %s", roleID, uid)
+				}
+				return nil
+			}
+			h := NewRoleHandler(zaptest.NewLogger(t), newMockRoleBackend(t, rs))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.want != nil {
+				var got roleResponse
+				if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+					t.Fatal(err)
+				}
+				if !cmp.Equal(tt.want, got.Role) {
+					t.Errorf("unexpected role -want/+got:\n%s", cmp.Diff(tt.want, got.Role))
+				}
+				if want := "/api/v2/roles/020f755c3c082000"; got.Links.Self != want {
+					t.Errorf("unexpected self link %q, want %q", got.Links.Self, want)
+				}
+			}
+			if tt.wantFilter != nil && !cmp.Equal(tt.wantFilter, filter) {
+				t.Errorf("unexpected filter -want/+got:\n%s", cmp.Diff(tt.wantFilter, filter))
+			}
+			if tt.wantAssignment != nil && !cmp.Equal(tt.wantAssignment, assignment) {
+				t.Errorf("unexpected assignment -want/+got:\n%s", cmp.Diff(tt.wantAssignment, assignment))
+			}
+		})
+	}
+}
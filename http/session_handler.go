@@ -3,15 +3,20 @@ package http
 import (
 	"context"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/rand"
 	"go.uber.org/zap"
 )
 
 const (
-	prefixSignIn  = "/api/v2/signin"
-	prefixSignOut = "/api/v2/signout"
+	prefixSignIn            = "/api/v2/signin"
+	prefixSignOut           = "/api/v2/signout"
+	signInOAuthPath         = "/api/v2/signin/oauth"
+	signInOAuthCallbackPath = "/api/v2/signin/oauth/callback"
 )
 
 // SessionBackend is all services and associated parameters required to construct
@@ -23,6 +28,7 @@ type SessionBackend struct {
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
 	UserService      platform.UserService
+	OAuthService     platform.OAuthService
 }
 
 // newSessionBackend creates a new SessionBackend with associated logger.
@@ -34,6 +40,7 @@ func newSessionBackend(log *zap.Logger, b *APIBackend) *SessionBackend {
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
 		UserService:      b.UserService,
+		OAuthService:     b.OAuthService,
 	}
 }
 
@@ -46,6 +53,9 @@ type SessionHandler struct {
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
 	UserService      platform.UserService
+	OAuthService     platform.OAuthService
+
+	oauthTokens platform.TokenGenerator
 }
 
 // NewSessionHandler returns a new instance of SessionHandler.
@@ -58,10 +68,17 @@ func NewSessionHandler(log *zap.Logger, b *SessionBackend) *SessionHandler {
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
 		UserService:      b.UserService,
+		OAuthService:     b.OAuthService,
+
+		oauthTokens: rand.NewTokenGenerator(32),
 	}
 
 	h.HandlerFunc("POST", prefixSignIn, h.handleSignin)
 	h.HandlerFunc("POST", prefixSignOut, h.handleSignout)
+	if h.OAuthService != nil {
+		h.HandlerFunc("GET", signInOAuthPath, h.handleOAuthSignin)
+		h.HandlerFunc("GET", signInOAuthCallbackPath, h.handleOAuthCallback)
+	}
 	return h
 }
 
@@ -119,6 +136,118 @@ func decodeSigninRequest(ctx context.Context, r *http.Request) (*signinRequest,
 	}, nil
 }
 
+const (
+	cookieOAuthStateName = "oauth_state"
+	oauthStateLifetime   = 10 * time.Minute
+)
+
+// handleOAuthSignin is the HTTP handler for the GET /signin/oauth route.
+// It redirects the user to the identity provider, remembering the state and
+// nonce of the request in a short lived cookie to verify the callback with.
+func (h *SessionHandler) handleOAuthSignin(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	state, err := h.oauthTokens.Token()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	nonce, err := h.oauthTokens.Token()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieOAuthStateName,
+		Value:    state + "." + nonce,
+		Path:     signInOAuthPath,
+		MaxAge:   int(oauthStateLifetime.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, h.OAuthService.AuthCodeURL(state, nonce), http.StatusFound)
+}
+
+// handleOAuthCallback is the HTTP handler for the GET /signin/oauth/callback route.
+// It logs in the user the identity provider vouches for and redirects it to the UI
+// with a new session.
+func (h *SessionHandler) handleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeOAuthCallbackRequest(ctx, r)
+	// The state cookie is only ever good for a single callback.
+	http.SetCookie(w, &http.Cookie{
+		Name:   cookieOAuthStateName,
+		Path:   signInOAuthPath,
+		MaxAge: -1,
+	})
+	if err != nil {
+		h.log.Info("Invalid OAuth callback", zap.Error(err))
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	u, err := h.OAuthService.Login(ctx, req.Code, req.Nonce)
+	if err != nil {
+		h.log.Info("Failed to log in through the identity provider", zap.Error(err))
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	s, err := h.SessionService.CreateSession(ctx, u.Name)
+	if err != nil {
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	encodeCookieSession(w, s)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
+
+type oauthCallbackRequest struct {
+	Code  string
+	Nonce string
+}
+
+func decodeOAuthCallbackRequest(ctx context.Context, r *http.Request) (*oauthCallbackRequest, error) {
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "the identity provider denied the login: " + e,
+		}
+	}
+
+	c, err := r.Cookie(cookieOAuthStateName)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "missing oauth state",
+			Err:  err,
+		}
+	}
+	parts := strings.SplitN(c.Value, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[0] != q.Get("state") {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "oauth state does not match",
+		}
+	}
+
+	code := q.Get("code")
+	if code == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "missing authorization code",
+		}
+	}
+	return &oauthCallbackRequest{
+		Code:  code,
+		Nonce: parts[1],
+	}, nil
+}
+
 // handleSignout is the HTTP handler for the POST /signout route.
 func (h *SessionHandler) handleSignout(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -3,12 +3,19 @@ package http
 import (
 	"context"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/kv"
 	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/oidc"
+	"github.com/influxdata/influxdb/oidc/oidctest"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -102,3 +109,102 @@ func TestSessionHandler_handleSignin(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionHandler_handleOAuthSignin(t *testing.T) {
+	ctx := context.Background()
+	store := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := store.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	idp := oidctest.NewIdP("influxdb", "secret")
+	defer idp.Close()
+	idp.SetUser(map[string]interface{}{"sub": "1234", "preferred_username": "jdoe"})
+
+	server := httptest.NewUnstartedServer(nil)
+	config := idp.Config("http://" + server.Listener.Addr().String() + signInOAuthCallbackPath)
+	config.AutoProvision = true
+	oauthSVC, err := oidc.NewService(ctx, config, oidc.WithUserSVC(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Config.Handler = NewSessionHandler(zaptest.NewLogger(t), &SessionBackend{
+		HTTPErrorHandler: kithttp.ErrorHandler(0),
+		log:              zaptest.NewLogger(t),
+
+		SessionService: store,
+		UserService:    store,
+		OAuthService:   oauthSVC,
+	})
+	server.Start()
+	defer server.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(server.URL + signInOAuthPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected a redirect to the identity provider, got %s", resp.Status)
+	}
+	_, code, err := idp.Authorize(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = client.Get(server.URL + signInOAuthCallbackPath + "?state=forged&code=" + code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected a callback with a forged state to be unauthorized, got %s", resp.Status)
+	}
+
+	resp, err = client.Get(server.URL + signInOAuthPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	state, code, err := idp.Authorize(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = client.Get(server.URL + signInOAuthCallbackPath + "?state=" + url.QueryEscape(state) + "&code=" + url.QueryEscape(code))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/" {
+		t.Fatalf("expected a redirect to the UI, got %s to %q", resp.Status, resp.Header.Get("Location"))
+	}
+
+	var key string
+	for _, c := range resp.Cookies() {
+		if c.Name == cookieSessionName {
+			key = c.Value
+		}
+	}
+	s, err := store.FindSession(ctx, key)
+	if err != nil {
+		t.Fatalf("expected a session for the user, got %v", err)
+	}
+	u, err := store.FindUserByID(ctx, s.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "jdoe" {
+		t.Errorf("expected a session for jdoe, got one for %s", u.Name)
+	}
+}
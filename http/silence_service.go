@@ -0,0 +1,389 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pctx "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixSilences = "/api/v2/silences"
+)
+
+// SilenceBackend is all services and associated parameters required to construct
+// the SilenceHandler.
+type SilenceBackend struct {
+	influxdb.HTTPErrorHandler
+	log                 *zap.Logger
+	SilenceService      influxdb.SilenceService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewSilenceBackend creates a backend used by the silence handler.
+func NewSilenceBackend(log *zap.Logger, b *APIBackend) *SilenceBackend {
+	return &SilenceBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		log:                 log,
+		SilenceService:      b.SilenceService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// SilenceHandler is the handler for the silence service
+type SilenceHandler struct {
+	*httprouter.Router
+
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	SilenceService      influxdb.SilenceService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewSilenceHandler creates a new SilenceHandler
+func NewSilenceHandler(log *zap.Logger, b *SilenceBackend) *SilenceHandler {
+	h := &SilenceHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		SilenceService:      b.SilenceService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	entityPath := fmt.Sprintf("%s/:id", prefixSilences)
+
+	h.HandlerFunc("GET", prefixSilences, h.handleGetSilences)
+	h.HandlerFunc("POST", prefixSilences, h.handlePostSilence)
+	h.HandlerFunc("GET", entityPath, h.handleGetSilence)
+	h.HandlerFunc("PATCH", entityPath, h.handlePatchSilence)
+	h.HandlerFunc("DELETE", entityPath, h.handleDeleteSilence)
+
+	return h
+}
+
+type silenceLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+}
+
+type silenceResponse struct {
+	*influxdb.Silence
+	Links silenceLinks `json:"links"`
+}
+
+func newSilenceResponse(s *influxdb.Silence) silenceResponse {
+	return silenceResponse{
+		Silence: s,
+		Links: silenceLinks{
+			Self: fmt.Sprintf("%s/%s", prefixSilences, s.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", s.OrgID),
+		},
+	}
+}
+
+type getSilencesResponse struct {
+	Silences []silenceResponse     `json:"silences"`
+	Links    *influxdb.PagingLinks `json:"links"`
+}
+
+func (r getSilencesResponse) toInfluxDB() []*influxdb.Silence {
+	ss := make([]*influxdb.Silence, len(r.Silences))
+	for i := range r.Silences {
+		ss[i] = r.Silences[i].Silence
+	}
+	return ss
+}
+
+func newGetSilencesResponse(ss []*influxdb.Silence, f influxdb.SilenceFilter, opts influxdb.FindOptions) getSilencesResponse {
+	resp := getSilencesResponse{
+		Silences: make([]silenceResponse, 0, len(ss)),
+		Links:    newPagingLinks(prefixSilences, opts, f, len(ss)),
+	}
+	for _, s := range ss {
+		resp.Silences = append(resp.Silences, newSilenceResponse(s))
+	}
+	return resp
+}
+
+type getSilencesRequest struct {
+	filter influxdb.SilenceFilter
+	opts   influxdb.FindOptions
+}
+
+func decodeGetSilencesRequest(ctx context.Context, r *http.Request, orgSvc influxdb.OrganizationService) (*getSilencesRequest, error) {
+	opts, err := decodeFindOptions(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getSilencesRequest{
+		opts: *opts,
+	}
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "orgID is invalid",
+				Err:  err,
+			}
+		}
+		req.filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := orgSvc.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = &o.ID
+	}
+
+	if activeAt := qp.Get("activeAt"); activeAt != "" {
+		t, err := time.Parse(time.RFC3339, activeAt)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "activeAt is invalid",
+				Err:  err,
+			}
+		}
+		req.filter.ActiveAt = &t
+	}
+
+	return req, nil
+}
+
+func (h *SilenceHandler) handleGetSilences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetSilencesRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ss, _, err := h.SilenceService.FindSilences(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silences retrieved", zap.Int("silences", len(ss)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newGetSilencesResponse(ss, req.filter, req.opts)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func requestSilenceID(ctx context.Context) (influxdb.ID, error) {
+	urlID := httprouter.ParamsFromContext(ctx).ByName("id")
+	if urlID == "" {
+		return influxdb.InvalidID(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	id, err := influxdb.IDFromString(urlID)
+	if err != nil {
+		return influxdb.InvalidID(), err
+	}
+	return *id, nil
+}
+
+func (h *SilenceHandler) handleGetSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestSilenceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	s, err := h.SilenceService.FindSilenceByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence retrieved", zap.String("silence", fmt.Sprint(s)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newSilenceResponse(s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodePostSilenceRequest(r *http.Request) (*influxdb.Silence, error) {
+	s := &influxdb.Silence{}
+	if err := json.NewDecoder(r.Body).Decode(s); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	if err := s.Valid(); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return s, nil
+}
+
+func (h *SilenceHandler) handlePostSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	s, err := decodePostSilenceRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	auth, err := pctx.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	s.CreatedBy = auth.GetUserID()
+
+	if err := h.SilenceService.CreateSilence(ctx, s); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence created", zap.String("silence", fmt.Sprint(s)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newSilenceResponse(s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *SilenceHandler) handlePatchSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestSilenceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	var upd influxdb.SilenceUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	s, err := h.SilenceService.UpdateSilence(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence updated", zap.String("silence", fmt.Sprint(s)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newSilenceResponse(s)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *SilenceHandler) handleDeleteSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := requestSilenceID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.SilenceService.DeleteSilence(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Silence deleted", zap.String("silenceID", id.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SilenceService is a silence service over HTTP to the influxdb server
+type SilenceService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// FindSilenceByID finds a single silence by its ID.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var resp silenceResponse
+	err := s.Client.
+		Get(prefixSilences, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Silence, nil
+}
+
+// FindSilences returns the silences matching the filter and their count.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opts ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+	params := findOptionParams(opts...)
+	for k, vs := range filter.QueryParams() {
+		for _, v := range vs {
+			params = append(params, [2]string{k, v})
+		}
+	}
+
+	var resp getSilencesResponse
+	err := s.Client.
+		Get(prefixSilences).
+		QueryParams(params...).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	ss := resp.toInfluxDB()
+	return ss, len(ss), nil
+}
+
+// CreateSilence creates a new silence and assigns it an ID.
+func (s *SilenceService) CreateSilence(ctx context.Context, sil *influxdb.Silence) error {
+	if err := sil.Valid(); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	var resp silenceResponse
+	err := s.Client.
+		PostJSON(sil, prefixSilences).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	*sil = *resp.Silence
+	return nil
+}
+
+// UpdateSilence updates a single silence with a changeset.
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	var resp silenceResponse
+	err := s.Client.
+		PatchJSON(upd, prefixSilences, id.String()).
+		DecodeJSON(&resp).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Silence, nil
+}
+
+// DeleteSilence removes a silence by its ID.
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(prefixSilences, id.String()).
+		Do(ctx)
+}
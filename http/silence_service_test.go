@@ -0,0 +1,155 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	influxTesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap/zaptest"
+)
+
+func newMockSilenceBackend(t *testing.T, ss influxdb.SilenceService) *SilenceBackend {
+	return &SilenceBackend{
+		HTTPErrorHandler:    kithttp.ErrorHandler(0),
+		log:                 zaptest.NewLogger(t),
+		SilenceService:      ss,
+		OrganizationService: mock.NewOrganizationService(),
+	}
+}
+
+func TestSilenceHandler(t *testing.T) {
+	var (
+		orgID  = influxTesting.MustIDBase16("020f755c3c082001")
+		userID = influxTesting.MustIDBase16("020f755c3c082002")
+		id     = influxTesting.MustIDBase16("020f755c3c082000")
+		start  = time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+		stored = &influxdb.Silence{
+			ID:        id,
+			OrgID:     orgID,
+			Matchers:  []influxdb.TagRule{{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal}},
+			Start:     start,
+			End:       start.Add(time.Hour),
+			Comment:   "upgrade",
+			CreatedBy: userID,
+		}
+	)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		want       *influxdb.Silence
+		wantFilter *influxdb.SilenceFilter
+	}{
+		{
+			name:       "create a silence",
+			method:     "POST",
+			path:       prefixSilences,
+			body:       `{"orgID": "020f755c3c082001", "matchers": [{"key": "host", "value": "a", "operator": "equal"}], "start": "2019-12-01T10:00:00Z", "end": "2019-12-01T11:00:00Z", "comment": "upgrade"}`,
+			wantStatus: http.StatusCreated,
+			want:       stored,
+		},
+		{
+			name:       "create an invalid silence",
+			method:     "POST",
+			path:       prefixSilences,
+			body:       `{"orgID": "020f755c3c082001", "start": "2019-12-01T11:00:00Z", "end": "2019-12-01T10:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "find the active silences",
+			method:     "GET",
+			path:       prefixSilences + "?orgID=020f755c3c082001&activeAt=2019-12-01T10:30:00Z",
+			wantStatus: http.StatusOK,
+			wantFilter: &influxdb.SilenceFilter{OrgID: &orgID, ActiveAt: timePtr(start.Add(30 * time.Minute))},
+		},
+		{
+			name:       "find silences with an invalid time",
+			method:     "GET",
+			path:       prefixSilences + "?activeAt=now",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "get a silence",
+			method:     "GET",
+			path:       prefixSilences + "/020f755c3c082000",
+			wantStatus: http.StatusOK,
+			want:       stored,
+		},
+		{
+			name:       "update a silence",
+			method:     "PATCH",
+			path:       prefixSilences + "/020f755c3c082000",
+			body:       `{"comment": "upgrade"}`,
+			wantStatus: http.StatusOK,
+			want:       stored,
+		},
+		{
+			name:       "delete a silence",
+			method:     "DELETE",
+			path:       prefixSilences + "/020f755c3c082000",
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filter *influxdb.SilenceFilter
+			ss := mock.NewSilenceService()
+			ss.CreateSilenceFn = func(ctx context.Context, s *influxdb.Silence) error {
+				s.ID = id
+				return nil
+			}
+			ss.FindSilenceByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+				return stored, nil
+			}
+			ss.FindSilencesFn = func(ctx context.Context, f influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+				filter = &f
+				return []*influxdb.Silence{stored}, 1, nil
+			}
+			ss.UpdateSilenceFn = func(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+				return stored, nil
+			}
+			h := NewSilenceHandler(zaptest.NewLogger(t), newMockSilenceBackend(t, ss))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &influxdb.Authorization{UserID: userID}))
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+			}
+			if tt.want != nil {
+				var got silenceResponse
+				if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+					t.Fatal(err)
+				}
+				if !cmp.Equal(tt.want, got.Silence) {
+					t.Errorf("unexpected silence -want/+got:\n%s", cmp.Diff(tt.want, got.Silence))
+				}
+				if want := "/api/v2/silences/020f755c3c082000"; got.Links.Self != want {
+					t.Errorf("unexpected self link %q, want %q", got.Links.Self, want)
+				}
+			}
+			if tt.wantFilter != nil && !cmp.Equal(tt.wantFilter, filter) {
+				t.Errorf("unexpected filter -want/+got:\n%s", cmp.Diff(tt.wantFilter, filter))
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
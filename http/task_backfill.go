@@ -0,0 +1,296 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+const (
+	tasksIDBackfillPath   = "/api/v2/tasks/:id/backfill"
+	tasksIDBackfillIDPath = "/api/v2/tasks/:id/backfill/:bid"
+)
+
+type backfillResponse struct {
+	influxdb.Backfill
+	Links map[string]string `json:"links"`
+}
+
+func newBackfillResponse(b influxdb.Backfill) backfillResponse {
+	return backfillResponse{
+		Backfill: b,
+		Links: map[string]string{
+			"self": taskIDBackfillIDPath(b.TaskID, b.ID),
+			"task": taskIDPath(b.TaskID),
+		},
+	}
+}
+
+type backfillsResponse struct {
+	Backfills []backfillResponse `json:"backfills"`
+	Links     map[string]string  `json:"links"`
+}
+
+func newBackfillsResponse(bs []*influxdb.Backfill, taskID influxdb.ID) backfillsResponse {
+	res := backfillsResponse{
+		Backfills: make([]backfillResponse, 0, len(bs)),
+		Links: map[string]string{
+			"self": taskIDBackfillPath(taskID),
+			"task": taskIDPath(taskID),
+		},
+	}
+	for _, b := range bs {
+		res.Backfills = append(res.Backfills, newBackfillResponse(*b))
+	}
+	return res
+}
+
+func (h *TaskHandler) handlePostBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var bc influxdb.BackfillCreate
+	if err := json.NewDecoder(r.Body).Decode(&bc); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+	bc.TaskID = taskID
+
+	b, err := h.TaskBackfillService.CreateBackfill(ctx, bc)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newBackfillResponse(*b)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleGetBackfills(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	bs, err := h.TaskBackfillService.FindBackfills(ctx, taskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBackfillsResponse(bs, taskID)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleGetBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	id, err := decodeIDFromCtx(ctx, "bid")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.TaskBackfillService.FindBackfillByID(ctx, taskID, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBackfillResponse(*b)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleCancelBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	id, err := decodeIDFromCtx(ctx, "bid")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TaskBackfillService.CancelBackfill(ctx, taskID, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateBackfill starts executing the runs of a task over a historical range.
+func (t TaskService) CreateBackfill(ctx context.Context, bc influxdb.BackfillCreate) (*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDBackfillPath(bc.TaskID))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(bc)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var b backfillResponse
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return nil, err
+	}
+	return &b.Backfill, nil
+}
+
+// FindBackfillByID returns the progress of a single backfill.
+func (t TaskService) FindBackfillByID(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDBackfillIDPath(taskID, id))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var b backfillResponse
+	if err := json.NewDecoder(resp.Body).Decode(&b); err != nil {
+		return nil, err
+	}
+	return &b.Backfill, nil
+}
+
+// FindBackfills returns the backfills of a task.
+func (t TaskService) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var bs backfillsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bs); err != nil {
+		return nil, err
+	}
+	res := make([]*influxdb.Backfill, 0, len(bs.Backfills))
+	for i := range bs.Backfills {
+		res = append(res, &bs.Backfills[i].Backfill)
+	}
+	return res, nil
+}
+
+// CancelBackfill stops a backfill, canceling the runs it is executing.
+func (t TaskService) CancelBackfill(ctx context.Context, taskID, id influxdb.ID) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDBackfillIDPath(taskID, id))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
+
+func taskIDBackfillPath(taskID influxdb.ID) string {
+	return path.Join(prefixTasks, taskID.String(), "backfill")
+}
+
+func taskIDBackfillIDPath(taskID, id influxdb.ID) string {
+	return path.Join(prefixTasks, taskID.String(), "backfill", id.String())
+}
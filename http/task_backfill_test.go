@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestTaskBackfill_Client(t *testing.T) {
+	start := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	want := &influxdb.Backfill{
+		ID:          2,
+		TaskID:      1,
+		Start:       start,
+		Stop:        start.Add(time.Hour),
+		Concurrency: 4,
+		Status:      influxdb.BackfillStatusRunning,
+		Total:       60,
+		CreatedAt:   start.Add(24 * time.Hour),
+	}
+
+	var canceled bool
+	bs := mock.NewTaskBackfillService()
+	bs.CreateBackfillFn = func(ctx context.Context, bc influxdb.BackfillCreate) (*influxdb.Backfill, error) {
+		got := influxdb.BackfillCreate{TaskID: want.TaskID, Start: want.Start, Stop: want.Stop, Concurrency: want.Concurrency}
+		if !cmp.Equal(bc, got) {
+			t.Errorf("unexpected backfill create -want/+got:\n%s", cmp.Diff(got, bc))
+		}
+		return want, nil
+	}
+	bs.FindBackfillByIDFn = func(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+		if taskID != want.TaskID || id != want.ID {
+			return nil, influxdb.ErrBackfillNotFound
+		}
+		return want, nil
+	}
+	bs.FindBackfillsFn = func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+		return []*influxdb.Backfill{want}, nil
+	}
+	bs.CancelBackfillFn = func(ctx context.Context, taskID, id influxdb.ID) error {
+		canceled = taskID == want.TaskID && id == want.ID
+		return nil
+	}
+
+	b := NewMockTaskBackend(t)
+	b.HTTPErrorHandler = kithttp.ErrorHandler(0)
+	b.TaskBackfillService = bs
+	server := httptest.NewServer(NewTaskHandler(b.log, b))
+	defer server.Close()
+
+	s := TaskService{Addr: server.URL}
+	ctx := context.Background()
+
+	got, err := s.CreateBackfill(ctx, influxdb.BackfillCreate{TaskID: 1, Start: start, Stop: start.Add(time.Hour), Concurrency: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected backfill -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	got, err = s.FindBackfillByID(ctx, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected backfill -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	_, err = s.FindBackfillByID(ctx, 1, 3)
+	influxdbtesting.ErrorsEqual(t, err, &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "backfill not found",
+	})
+
+	all, err := s.FindBackfills(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal([]*influxdb.Backfill{want}, all) {
+		t.Errorf("unexpected backfills -want/+got:\n%s", cmp.Diff([]*influxdb.Backfill{want}, all))
+	}
+
+	if err := s.CancelBackfill(ctx, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if !canceled {
+		t.Error("expected the backfill to be canceled")
+	}
+}
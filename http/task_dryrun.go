@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+// tasksDryRunPath shares its route with tasksIDPath, the router doesn't allow
+// a static segment next to the :id parameter.
+const tasksDryRunPath = "/api/v2/tasks/dryrun"
+
+// handlePostTaskID handles the POST requests of tasksIDPath, of which only the dry run exists.
+func (h *TaskHandler) handlePostTaskID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if httprouter.ParamsFromContext(ctx).ByName("id") != "dryrun" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EMethodNotAllowed,
+			Msg:  "method not allowed",
+		}, w)
+		return
+	}
+	h.handlePostTaskDryRun(w, r)
+}
+
+func (h *TaskHandler) handlePostTaskDryRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req influxdb.TaskDryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if !req.OrganizationID.Valid() {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &req.Organization})
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		req.OrganizationID = o.ID
+	}
+
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Msg:  "failed to get authorizer",
+			Err:  err,
+		}, w)
+		return
+	}
+	// the script is queried on behalf of the caller, sessions get an ephemeral authorization.
+	a, err := queryAuthorization(auth, req.OrganizationID)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Err:  err,
+		}, w)
+		return
+	}
+	ctx = pcontext.SetAuthorizer(ctx, a)
+
+	dr, err := h.TaskDryRunService.DryRunTask(ctx, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, dr); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// DryRunTask executes a task script once without side effects,
+// returning the tables its sinks would have written.
+func (t TaskService) DryRunTask(ctx context.Context, req influxdb.TaskDryRunRequest) (*influxdb.TaskDryRun, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, tasksDryRunPath)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	hreq, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, hreq)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var dr influxdb.TaskDryRun
+	if err := json.NewDecoder(resp.Body).Decode(&dr); err != nil {
+		return nil, err
+	}
+	return &dr, nil
+}
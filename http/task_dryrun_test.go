@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestTaskDryRun_Client(t *testing.T) {
+	scheduledFor := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	want := &influxdb.TaskDryRun{
+		ScheduledFor: scheduledFor,
+		Tables: []influxdb.DryRunTable{{
+			Sink:    `to(bucket: "c", org: "o")`,
+			Columns: []influxdb.DryRunColumn{{Label: "_value", Type: "string"}},
+			Records: [][]interface{}{{"a"}},
+		}},
+		Log: []influxdb.Log{{Time: scheduledFor.Format(time.RFC3339Nano), Message: "Captured 1 tables with 1 records"}},
+	}
+
+	var got influxdb.TaskDryRunRequest
+	ds := mock.NewTaskDryRunService()
+	ds.DryRunTaskFn = func(ctx context.Context, req influxdb.TaskDryRunRequest) (*influxdb.TaskDryRun, error) {
+		auth, err := pcontext.GetAuthorizer(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := auth.(*influxdb.Authorization); !ok {
+			t.Errorf("expected the authorization of the query on the context, got %T", auth)
+		}
+		got = req
+		return want, nil
+	}
+
+	b := NewMockTaskBackend(t)
+	b.HTTPErrorHandler = kithttp.ErrorHandler(0)
+	b.TaskDryRunService = ds
+	h := NewTaskHandler(b.log, b)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := pcontext.SetAuthorizer(r.Context(), &influxdb.Session{UserID: 3, Permissions: []influxdb.Permission{}})
+		h.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	defer server.Close()
+
+	s := TaskService{Addr: server.URL}
+	req := influxdb.TaskDryRunRequest{
+		Flux:           `option task = {name: "a", every: 1h} from(bucket: "b") |> to(bucket: "c", org: "o")`,
+		OrganizationID: 2,
+		ScheduledFor:   scheduledFor,
+	}
+	dr, err := s.DryRunTask(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(req, got) {
+		t.Errorf("unexpected request -want/+got:\n%s", cmp.Diff(req, got))
+	}
+	if !cmp.Equal(want, dr) {
+		t.Errorf("unexpected dry run -want/+got:\n%s", cmp.Diff(want, dr))
+	}
+
+	if _, err := s.DryRunTask(context.Background(), influxdb.TaskDryRunRequest{OrganizationID: 2}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error for a missing script, got %v", err)
+	}
+}
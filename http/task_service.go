@@ -35,6 +35,9 @@ type TaskBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	TaskBackfillService        influxdb.TaskBackfillService
+	TaskVersionService         influxdb.TaskVersionService
+	TaskDryRunService          influxdb.TaskDryRunService
 }
 
 // NewTaskBackend returns a new instance of TaskBackend.
@@ -49,6 +52,9 @@ func NewTaskBackend(log *zap.Logger, b *APIBackend) *TaskBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		TaskBackfillService:        b.TaskBackfillService,
+		TaskVersionService:         b.TaskVersionService,
+		TaskDryRunService:          b.TaskDryRunService,
 	}
 }
 
@@ -65,6 +71,9 @@ type TaskHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	BucketService              influxdb.BucketService
+	TaskBackfillService        influxdb.TaskBackfillService
+	TaskVersionService         influxdb.TaskVersionService
+	TaskDryRunService          influxdb.TaskDryRunService
 }
 
 const (
@@ -97,6 +106,9 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		TaskBackfillService:        b.TaskBackfillService,
+		TaskVersionService:         b.TaskVersionService,
+		TaskDryRunService:          b.TaskDryRunService,
 	}
 
 	h.HandlerFunc("GET", prefixTasks, h.handleGetTasks)
@@ -139,6 +151,24 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
+	if h.TaskBackfillService != nil {
+		h.HandlerFunc("GET", tasksIDBackfillPath, h.handleGetBackfills)
+		h.HandlerFunc("POST", tasksIDBackfillPath, h.handlePostBackfill)
+		h.HandlerFunc("GET", tasksIDBackfillIDPath, h.handleGetBackfill)
+		h.HandlerFunc("DELETE", tasksIDBackfillIDPath, h.handleCancelBackfill)
+	}
+
+	if h.TaskVersionService != nil {
+		h.HandlerFunc("GET", tasksIDVersionsPath, h.handleGetTaskVersions)
+		h.HandlerFunc("GET", tasksIDVersionsIDPath, h.handleGetTaskVersion)
+		h.HandlerFunc("GET", tasksIDVersionsIDDiffPath, h.handleGetTaskVersionDiff)
+		h.HandlerFunc("POST", tasksIDVersionsIDRestorePath, h.handleRestoreTaskVersion)
+	}
+
+	if h.TaskDryRunService != nil {
+		h.HandlerFunc("POST", tasksIDPath, h.handlePostTaskID)
+	}
+
 	labelBackend := &LabelBackend{
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		log:              b.log.With(zap.String("handler", "label")),
@@ -163,13 +193,23 @@ type Task struct {
 	Flux            string                 `json:"flux"`
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
+	Location        string                 `json:"location,omitempty"`
 	Offset          string                 `json:"offset,omitempty"`
+	After           []influxdb.ID          `json:"after,omitempty"`
+	Timeout         string                 `json:"timeout,omitempty"`
+	Retry           int64                  `json:"retry,omitempty"`
+	RetryDelay      string                 `json:"retryDelay,omitempty"`
+	RetryMaxDelay   string                 `json:"retryMaxDelay,omitempty"`
+	Version         int                    `json:"version,omitempty"`
 	LatestCompleted string                 `json:"latestCompleted,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
 	CreatedAt       string                 `json:"createdAt,omitempty"`
 	UpdatedAt       string                 `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+
+	Notification        *influxdb.TaskNotification `json:"notification,omitempty"`
+	ConsecutiveFailures int                        `json:"consecutiveFailures,omitempty"`
 }
 
 type taskResponse struct {
@@ -196,6 +236,16 @@ func NewFrontEndTask(t influxdb.Task) Task {
 	if t.Offset != 0*time.Second {
 		offset = customParseDuration(t.Offset)
 	}
+	timeout, retryDelay, retryMaxDelay := "", "", ""
+	if t.Timeout != 0 {
+		timeout = customParseDuration(t.Timeout)
+	}
+	if t.RetryDelay != 0 {
+		retryDelay = customParseDuration(t.RetryDelay)
+	}
+	if t.RetryMaxDelay != 0 {
+		retryMaxDelay = customParseDuration(t.RetryMaxDelay)
+	}
 
 	return Task{
 		ID:              t.ID,
@@ -208,13 +258,23 @@ func NewFrontEndTask(t influxdb.Task) Task {
 		Flux:            t.Flux,
 		Every:           t.Every,
 		Cron:            t.Cron,
+		Location:        t.Location,
 		Offset:          offset,
+		After:           t.After,
+		Timeout:         timeout,
+		Retry:           t.Retry,
+		RetryDelay:      retryDelay,
+		RetryMaxDelay:   retryMaxDelay,
+		Version:         t.Version,
 		LatestCompleted: latestCompleted,
 		LastRunStatus:   t.LastRunStatus,
 		LastRunError:    t.LastRunError,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Metadata:        t.Metadata,
+
+		Notification:        t.Notification,
+		ConsecutiveFailures: t.ConsecutiveFailures,
 	}
 }
 
@@ -341,6 +401,9 @@ type httpRun struct {
 	StartedAt    *time.Time     `json:"startedAt,omitempty"`
 	FinishedAt   *time.Time     `json:"finishedAt,omitempty"`
 	RequestedAt  *time.Time     `json:"requestedAt,omitempty"`
+	RetryOf      influxdb.ID    `json:"retryOf,omitempty"`
+	Attempt      int            `json:"attempt,omitempty"`
+	TaskVersion  int            `json:"taskVersion,omitempty"`
 	Log          []influxdb.Log `json:"log,omitempty"`
 }
 
@@ -351,6 +414,9 @@ func newRunResponse(r influxdb.Run) runResponse {
 		Status:       r.Status,
 		Log:          r.Log,
 		ScheduledFor: &r.ScheduledFor,
+		RetryOf:      r.RetryOf,
+		Attempt:      r.Attempt,
+		TaskVersion:  r.TaskVersion,
 	}
 
 	if !r.StartedAt.IsZero() {
@@ -376,10 +442,13 @@ func newRunResponse(r influxdb.Run) runResponse {
 
 func convertRun(r httpRun) *influxdb.Run {
 	run := &influxdb.Run{
-		ID:     r.ID,
-		TaskID: r.TaskID,
-		Status: r.Status,
-		Log:    r.Log,
+		ID:          r.ID,
+		TaskID:      r.TaskID,
+		Status:      r.Status,
+		RetryOf:     r.RetryOf,
+		Attempt:     r.Attempt,
+		TaskVersion: r.TaskVersion,
+		Log:         r.Log,
 	}
 
 	if r.StartedAt != nil {
@@ -0,0 +1,345 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+const (
+	tasksIDVersionsPath          = "/api/v2/tasks/:id/versions"
+	tasksIDVersionsIDPath        = "/api/v2/tasks/:id/versions/:version"
+	tasksIDVersionsIDDiffPath    = "/api/v2/tasks/:id/versions/:version/diff"
+	tasksIDVersionsIDRestorePath = "/api/v2/tasks/:id/versions/:version/restore"
+)
+
+type taskVersionResponse struct {
+	influxdb.TaskVersion
+	Links map[string]string `json:"links"`
+}
+
+func newTaskVersionResponse(v influxdb.TaskVersion) taskVersionResponse {
+	return taskVersionResponse{
+		TaskVersion: v,
+		Links: map[string]string{
+			"self":    taskIDVersionPath(v.TaskID, v.Version),
+			"task":    taskIDPath(v.TaskID),
+			"diff":    path.Join(taskIDVersionPath(v.TaskID, v.Version), "diff"),
+			"restore": path.Join(taskIDVersionPath(v.TaskID, v.Version), "restore"),
+		},
+	}
+}
+
+type taskVersionsResponse struct {
+	Versions []taskVersionResponse `json:"versions"`
+	Links    map[string]string     `json:"links"`
+}
+
+func newTaskVersionsResponse(vs []*influxdb.TaskVersion, taskID influxdb.ID) taskVersionsResponse {
+	res := taskVersionsResponse{
+		Versions: make([]taskVersionResponse, 0, len(vs)),
+		Links: map[string]string{
+			"self": taskIDVersionsPath(taskID),
+			"task": taskIDPath(taskID),
+		},
+	}
+	for _, v := range vs {
+		res.Versions = append(res.Versions, newTaskVersionResponse(*v))
+	}
+	return res
+}
+
+// restoreTaskVersionRequest is the optional body of a request restoring a version.
+type restoreTaskVersionRequest struct {
+	Description string `json:"description,omitempty"`
+}
+
+func decodeTaskVersionFromCtx(ctx context.Context) (influxdb.ID, int, error) {
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	v, err := strconv.Atoi(params.ByName("version"))
+	if err != nil || v < 1 {
+		return 0, 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "version must be a positive integer",
+		}
+	}
+	return taskID, v, nil
+}
+
+func (h *TaskHandler) handleGetTaskVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	vs, err := h.TaskVersionService.FindTaskVersions(ctx, taskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskVersionsResponse(vs, taskID)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleGetTaskVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, version, err := decodeTaskVersionFromCtx(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	v, err := h.TaskVersionService.FindTaskVersion(ctx, taskID, version)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskVersionResponse(*v)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetTaskVersionDiff compares a version to the version given by the "from" query parameter,
+// the previous version by default.
+func (h *TaskHandler) handleGetTaskVersionDiff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, version, err := decodeTaskVersionFromCtx(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	from := version - 1
+	if qf := r.URL.Query().Get("from"); qf != "" {
+		from, err = strconv.Atoi(qf)
+		if err != nil || from < 0 {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "from must be a version number",
+			}, w)
+			return
+		}
+	}
+
+	to, err := h.TaskVersionService.FindTaskVersion(ctx, taskID, version)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// version zero is the empty script before the task was created.
+	var fromVersion *influxdb.TaskVersion
+	if from > 0 {
+		fromVersion, err = h.TaskVersionService.FindTaskVersion(ctx, taskID, from)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, influxdb.DiffTaskVersions(fromVersion, to)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleRestoreTaskVersion updates the task with the script of a version,
+// which records the restored script as a new version.
+func (h *TaskHandler) handleRestoreTaskVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, version, err := decodeTaskVersionFromCtx(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req restoreTaskVersionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "failed to decode request",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+	if req.Description == "" {
+		req.Description = fmt.Sprintf("Restored version %d", version)
+	}
+
+	v, err := h.TaskVersionService.FindTaskVersion(ctx, taskID, version)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TaskService.UpdateTask(ctx, taskID, influxdb.TaskUpdate{
+		Flux:               &v.Flux,
+		VersionDescription: req.Description,
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Err: err,
+			Msg: "failed to restore task version",
+		}, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: task.ID})
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Err: err,
+			Msg: "failed to find resource labels",
+		}, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// FindTaskVersions returns the versions of a task, the most recent first.
+func (t TaskService) FindTaskVersions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var vs taskVersionsResponse
+	if err := t.getJSON(taskIDVersionsPath(taskID), nil, &vs); err != nil {
+		return nil, err
+	}
+
+	res := make([]*influxdb.TaskVersion, 0, len(vs.Versions))
+	for i := range vs.Versions {
+		res = append(res, &vs.Versions[i].TaskVersion)
+	}
+	return res, nil
+}
+
+// FindTaskVersion returns a single version of a task.
+func (t TaskService) FindTaskVersion(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var v taskVersionResponse
+	if err := t.getJSON(taskIDVersionPath(taskID, version), nil, &v); err != nil {
+		return nil, err
+	}
+	return &v.TaskVersion, nil
+}
+
+// DiffTaskVersions returns the difference from version from to version to of a task.
+func (t TaskService) DiffTaskVersions(ctx context.Context, taskID influxdb.ID, from, to int) (*influxdb.TaskVersionDiff, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	q := url.Values{}
+	q.Set("from", strconv.Itoa(from))
+
+	var d influxdb.TaskVersionDiff
+	if err := t.getJSON(path.Join(taskIDVersionPath(taskID, to), "diff"), q, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RestoreTaskVersion updates a task with the script of one of its versions.
+func (t TaskService) RestoreTaskVersion(ctx context.Context, taskID influxdb.ID, version int, description string) (*Task, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, path.Join(taskIDVersionPath(taskID, version), "restore"))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(restoreTaskVersionRequest{Description: description})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var tr taskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, err
+	}
+	return &tr.Task, nil
+}
+
+// getJSON decodes the response of a GET request of the path into v.
+func (t TaskService) getJSON(urlPath string, query url.Values, v interface{}) error {
+	u, err := NewURL(t.Addr, urlPath)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func taskIDVersionsPath(taskID influxdb.ID) string {
+	return path.Join(prefixTasks, taskID.String(), "versions")
+}
+
+func taskIDVersionPath(taskID influxdb.ID, version int) string {
+	return path.Join(prefixTasks, taskID.String(), "versions", strconv.Itoa(version))
+}
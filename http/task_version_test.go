@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	kithttp "github.com/influxdata/influxdb/kit/transport/http"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestTaskVersion_Client(t *testing.T) {
+	createdAt := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	versions := []*influxdb.TaskVersion{
+		{
+			TaskID:      1,
+			Version:     2,
+			Flux:        "option task = {name: \"a\", every: 2h}\n\nfrom(bucket: \"b\")\n",
+			AuthorID:    3,
+			Description: "run less often",
+			CreatedAt:   createdAt.Add(time.Hour),
+		},
+		{
+			TaskID:    1,
+			Version:   1,
+			Flux:      "option task = {name: \"a\", every: 1h}\n\nfrom(bucket: \"b\")\n",
+			AuthorID:  3,
+			CreatedAt: createdAt,
+		},
+	}
+
+	vs := mock.NewTaskVersionService()
+	vs.FindTaskVersionsFn = func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+		return versions, nil
+	}
+	vs.FindTaskVersionFn = func(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+		for _, v := range versions {
+			if v.TaskID == taskID && v.Version == version {
+				return v, nil
+			}
+		}
+		return nil, influxdb.ErrTaskVersionNotFound
+	}
+
+	var restored influxdb.TaskUpdate
+	ts := &mock.TaskService{
+		UpdateTaskFn: func(ctx context.Context, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
+			restored = upd
+			return &influxdb.Task{ID: id, OrganizationID: 2, OwnerID: 3, Flux: *upd.Flux, Version: 3}, nil
+		},
+	}
+
+	b := NewMockTaskBackend(t)
+	b.HTTPErrorHandler = kithttp.ErrorHandler(0)
+	b.TaskService = ts
+	b.TaskVersionService = vs
+	server := httptest.NewServer(NewTaskHandler(b.log, b))
+	defer server.Close()
+
+	s := TaskService{Addr: server.URL}
+	ctx := context.Background()
+
+	all, err := s.FindTaskVersions(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(versions, all) {
+		t.Errorf("unexpected versions -want/+got:\n%s", cmp.Diff(versions, all))
+	}
+
+	got, err := s.FindTaskVersion(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(versions[1], got) {
+		t.Errorf("unexpected version -want/+got:\n%s", cmp.Diff(versions[1], got))
+	}
+
+	_, err = s.FindTaskVersion(ctx, 1, 4)
+	influxdbtesting.ErrorsEqual(t, err, &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "task version not found",
+	})
+
+	d, err := s.DiffTaskVersions(ctx, 1, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := influxdb.DiffTaskVersions(versions[1], versions[0]); !cmp.Equal(want, d) {
+		t.Errorf("unexpected diff -want/+got:\n%s", cmp.Diff(want, d))
+	}
+
+	task, err := s.RestoreTaskVersion(ctx, 1, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Version != 3 || *restored.Flux != versions[1].Flux || restored.VersionDescription != "Restored version 1" {
+		t.Errorf("unexpected restore of version 1, task %+v from update %+v", task, restored)
+	}
+}
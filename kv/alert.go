@@ -0,0 +1,380 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	_ influxdb.AlertService = (*Service)(nil)
+	_ influxdb.AlertTracker = (*Service)(nil)
+)
+
+func newAlertStore() *StoreBase {
+	const resource = "alert"
+
+	var decAlertEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var a influxdb.Alert
+		return key, &a, json.Unmarshal(val, &a)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, v interface{}) (Entity, error) {
+		a, ok := v.(*influxdb.Alert)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return Entity{
+			PK:   EncID(a.ID),
+			Body: a,
+		}, nil
+	}
+
+	return NewStoreBase(resource, []byte("alertsv1"), EncIDKey, EncBodyJSON, decAlertEntFn, decValToEntFn)
+}
+
+// newAlertIndexStore creates a store indexing the IDs of alerts by a unique key prefixed with the ID of their check.
+func newAlertIndexStore(resource string, bktName []byte) *StoreBase {
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, v interface{}) (Entity, error) {
+		id, ok := v.(influxdb.ID)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return Entity{PK: EncID(id)}, nil
+	}
+
+	return NewStoreBase(resource, bktName, EncUniqKey, EncIDKey, DecIndexID, decValToEntFn)
+}
+
+// newAlertOpenIndexStore indexes the alerts that are not resolved by check and series.
+func newAlertOpenIndexStore() *StoreBase {
+	return newAlertIndexStore("open alert index", []byte("alertsopenindexv1"))
+}
+
+// newAlertCheckIndexStore indexes all the alerts by check, the latest last.
+func newAlertCheckIndexStore() *StoreBase {
+	return newAlertIndexStore("check alert index", []byte("alertschecksindexv1"))
+}
+
+func alertOpenKey(checkID influxdb.ID, series string) EncodeFn {
+	return Encode(EncID(checkID), EncString(series))
+}
+
+func alertCheckKey(checkID, id influxdb.ID) EncodeFn {
+	return Encode(EncID(checkID), EncID(id))
+}
+
+// FindAlertByID finds a single alert by its ID.
+func (s *Service) FindAlertByID(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	var a *influxdb.Alert
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		a, err = s.findAlertByID(ctx, tx, id)
+		return err
+	})
+	return a, err
+}
+
+func (s *Service) findAlertByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Alert, error) {
+	body, err := s.alertStore.FindEnt(ctx, tx, Entity{PK: EncID(id)})
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Op:   influxdb.OpFindAlertByID,
+			Msg:  influxdb.ErrAlertNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	a, ok := body.(*influxdb.Alert)
+	return a, IsErrUnexpectedDecodeVal(ok)
+}
+
+// FindAlerts returns the alerts matching the filter and their count, the most recently opened first.
+// The alerts of a check are found through its index, as are the alerts that are not resolved.
+func (s *Service) FindAlerts(ctx context.Context, filter influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, int, error) {
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	as := make([]*influxdb.Alert, 0)
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var (
+			index  *StoreBase
+			prefix []byte
+		)
+		switch {
+		case filter.CheckID != nil:
+			index = s.alertCheckIndex
+			p, err := EncID(*filter.CheckID)()
+			if err != nil {
+				return err
+			}
+			prefix = p
+		case filter.Status != nil && *filter.Status != influxdb.AlertResolved:
+			index = s.alertOpenIndex
+		default:
+			return s.alertStore.Find(ctx, tx, FindOpts{
+				Descending:  true,
+				Offset:      o.Offset,
+				Limit:       o.Limit,
+				FilterEntFn: filterAlertsFn(filter),
+				CaptureFn: func(k []byte, v interface{}) error {
+					a, ok := v.(*influxdb.Alert)
+					if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+						return err
+					}
+					as = append(as, a)
+					return nil
+				},
+			})
+		}
+
+		var ids []influxdb.ID
+		err := index.Find(ctx, tx, FindOpts{
+			Prefix: prefix,
+			CaptureFn: func(k []byte, v interface{}) error {
+				id, ok := v.(influxdb.ID)
+				if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+					return err
+				}
+				ids = append(ids, id)
+				return nil
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		match := filterAlertsFn(filter)
+		for _, id := range ids {
+			a, err := s.findAlertByID(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if match(nil, a) {
+				as = append(as, a)
+			}
+		}
+		sort.Slice(as, func(i, j int) bool {
+			if !as[i].OpenedAt.Equal(as[j].OpenedAt) {
+				return as[i].OpenedAt.After(as[j].OpenedAt)
+			}
+			return as[i].ID > as[j].ID
+		})
+		as = paginateAlerts(as, o)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return as, len(as), nil
+}
+
+func paginateAlerts(as []*influxdb.Alert, o influxdb.FindOptions) []*influxdb.Alert {
+	if o.Offset >= len(as) {
+		return as[:0]
+	}
+	as = as[o.Offset:]
+	if o.Limit > 0 && o.Limit < len(as) {
+		as = as[:o.Limit]
+	}
+	return as
+}
+
+func filterAlertsFn(filter influxdb.AlertFilter) func([]byte, interface{}) bool {
+	return func(key []byte, val interface{}) bool {
+		a, ok := val.(*influxdb.Alert)
+		if !ok {
+			return false
+		}
+		if filter.OrgID != nil && a.OrgID != *filter.OrgID {
+			return false
+		}
+		if filter.CheckID != nil && a.CheckID != *filter.CheckID {
+			return false
+		}
+		if filter.Status != nil && a.Status != *filter.Status {
+			return false
+		}
+		return true
+	}
+}
+
+// AcknowledgeAlert records that the user handles the open alert.
+func (s *Service) AcknowledgeAlert(ctx context.Context, id, userID influxdb.ID, comment string) (*influxdb.Alert, error) {
+	return s.updateAlert(ctx, id, func(tx Tx, a *influxdb.Alert) error {
+		if a.Status != influxdb.AlertOpen {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Op:   influxdb.OpAcknowledgeAlert,
+				Msg:  "only open alerts can be acknowledged, the alert is " + a.Status,
+			}
+		}
+		now := s.Now()
+		a.Status = influxdb.AlertAcknowledged
+		a.AcknowledgedAt = &now
+		a.AcknowledgedBy = userID
+		a.AddEvent(influxdb.AlertEvent{
+			Time:    now,
+			Type:    influxdb.AlertEventAcknowledged,
+			UserID:  userID,
+			Comment: comment,
+		})
+		return nil
+	})
+}
+
+// ResolveAlert resolves the alert on behalf of the user.
+func (s *Service) ResolveAlert(ctx context.Context, id, userID influxdb.ID, comment string) (*influxdb.Alert, error) {
+	return s.updateAlert(ctx, id, func(tx Tx, a *influxdb.Alert) error {
+		if a.Status == influxdb.AlertResolved {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Op:   influxdb.OpResolveAlert,
+				Msg:  "the alert is already resolved",
+			}
+		}
+		return s.resolveAlert(ctx, tx, a, s.Now(), influxdb.AlertEvent{
+			UserID:  userID,
+			Comment: comment,
+		})
+	})
+}
+
+// EscalateAlert records that the notification rule escalated the alert to the endpoint.
+func (s *Service) EscalateAlert(ctx context.Context, id, ruleID, endpointID influxdb.ID, at time.Time) (*influxdb.Alert, error) {
+	return s.updateAlert(ctx, id, func(tx Tx, a *influxdb.Alert) error {
+		a.AddEvent(influxdb.AlertEvent{
+			Time:       at,
+			Type:       influxdb.AlertEventEscalated,
+			Level:      a.Level,
+			RuleID:     ruleID,
+			EndpointID: endpointID,
+		})
+		return nil
+	})
+}
+
+func (s *Service) updateAlert(ctx context.Context, id influxdb.ID, fn func(Tx, *influxdb.Alert) error) (*influxdb.Alert, error) {
+	var a *influxdb.Alert
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		var err error
+		if a, err = s.findAlertByID(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := fn(tx, a); err != nil {
+			return err
+		}
+		return s.alertStore.Put(ctx, tx, Entity{PK: EncID(a.ID), Body: a}, PutUpdate())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// resolveAlert resolves a and removes it from the index of the alerts that are not resolved, it doesn't put a.
+func (s *Service) resolveAlert(ctx context.Context, tx Tx, a *influxdb.Alert, at time.Time, e influxdb.AlertEvent) error {
+	a.Status = influxdb.AlertResolved
+	a.ResolvedAt = &at
+	a.ResolvedBy = e.UserID
+	e.Time = at
+	e.Type = influxdb.AlertEventResolved
+	a.AddEvent(e)
+	return s.alertOpenIndex.DeleteEnt(ctx, tx, Entity{UniqueKey: alertOpenKey(a.CheckID, a.SeriesKey())})
+}
+
+// TrackStatuses opens an alert for the series of the non ok statuses that have none,
+// updates the level of the alerts of the others and resolves the alerts of the ok statuses.
+// The statuses are tracked in order.
+func (s *Service) TrackStatuses(ctx context.Context, statuses []influxdb.AlertStatus) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		for _, st := range statuses {
+			if err := s.trackStatus(ctx, tx, st); err != nil {
+				return &influxdb.Error{
+					Op:  influxdb.OpTrackStatuses,
+					Err: err,
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Service) trackStatus(ctx context.Context, tx Tx, st influxdb.AlertStatus) error {
+	if !st.OrgID.Valid() || !st.CheckID.Valid() {
+		return nil
+	}
+	openKey := alertOpenKey(st.CheckID, influxdb.AlertSeriesKey(st.Tags))
+
+	var a *influxdb.Alert
+	v, err := s.alertOpenIndex.FindEnt(ctx, tx, Entity{UniqueKey: openKey})
+	switch {
+	case err == nil:
+		id, ok := v.(influxdb.ID)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return err
+		}
+		if a, err = s.findAlertByID(ctx, tx, id); err != nil {
+			return err
+		}
+	case influxdb.ErrorCode(err) != influxdb.ENotFound:
+		return err
+	}
+
+	switch {
+	case a == nil && st.Level == influxdb.AlertLevelOK:
+		return nil
+	case a == nil:
+		a = &influxdb.Alert{
+			ID:           s.IDGenerator.ID(),
+			OrgID:        st.OrgID,
+			CheckID:      st.CheckID,
+			CheckName:    st.CheckName,
+			Tags:         st.Tags,
+			Status:       influxdb.AlertOpen,
+			Level:        st.Level,
+			Message:      st.Message,
+			OpenedAt:     st.Time,
+			LastStatusAt: st.Time,
+		}
+		a.AddEvent(influxdb.AlertEvent{
+			Time:  st.Time,
+			Type:  influxdb.AlertEventOpened,
+			Level: st.Level,
+		})
+		if err := s.alertOpenIndex.Put(ctx, tx, Entity{PK: EncID(a.ID), UniqueKey: openKey}); err != nil {
+			return err
+		}
+		if err := s.alertCheckIndex.Put(ctx, tx, Entity{PK: EncID(a.ID), UniqueKey: alertCheckKey(a.CheckID, a.ID)}); err != nil {
+			return err
+		}
+		return s.alertStore.Put(ctx, tx, Entity{PK: EncID(a.ID), Body: a}, PutNew())
+	case st.Level == influxdb.AlertLevelOK:
+		a.LastStatusAt = st.Time
+		a.Level = st.Level
+		a.Message = st.Message
+		if err := s.resolveAlert(ctx, tx, a, st.Time, influxdb.AlertEvent{Level: st.Level}); err != nil {
+			return err
+		}
+	default:
+		if a.Level != st.Level {
+			a.AddEvent(influxdb.AlertEvent{
+				Time:  st.Time,
+				Type:  influxdb.AlertEventLevel,
+				Level: st.Level,
+			})
+		}
+		a.CheckName = st.CheckName
+		a.Level = st.Level
+		a.Message = st.Message
+		a.LastStatusAt = st.Time
+	}
+	return s.alertStore.Put(ctx, tx, Entity{PK: EncID(a.ID), Body: a}, PutUpdate())
+}
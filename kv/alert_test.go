@@ -0,0 +1,137 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Alerts(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	orgID, checkID, userID := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+	start := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	status := func(host, level string, at time.Duration) influxdb.AlertStatus {
+		return influxdb.AlertStatus{
+			OrgID:     orgID,
+			CheckID:   checkID,
+			CheckName: "cpu",
+			Tags:      map[string]string{"host": host},
+			Level:     level,
+			Message:   "cpu is " + level,
+			Time:      start.Add(at),
+		}
+	}
+
+	err := svc.TrackStatuses(ctx, []influxdb.AlertStatus{
+		status("a", "ok", 0),
+		status("a", "warn", time.Minute),
+		status("b", "crit", time.Minute),
+		status("a", "crit", 2*time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	open := influxdb.AlertOpen
+	alerts, n, err := svc.FindAlerts(ctx, influxdb.AlertFilter{OrgID: &orgID, Status: &open})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected an open alert per non ok series, got %d", n)
+	}
+	var a *influxdb.Alert
+	for _, al := range alerts {
+		if al.Tags["host"] == "a" {
+			a = al
+		}
+	}
+	if a == nil {
+		t.Fatal("expected an alert for host a")
+	}
+	if a.Level != "crit" || !a.OpenedAt.Equal(start.Add(time.Minute)) || !a.LastStatusAt.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("unexpected alert %+v", a)
+	}
+	if len(a.Events) != 2 || a.Events[0].Type != influxdb.AlertEventOpened || a.Events[1].Type != influxdb.AlertEventLevel {
+		t.Errorf("expected the alert to be opened then to change level, got %+v", a.Events)
+	}
+
+	ack, err := svc.AcknowledgeAlert(ctx, a.ID, userID, "on it")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack.Status != influxdb.AlertAcknowledged || ack.AcknowledgedBy != userID || ack.AcknowledgedAt == nil {
+		t.Errorf("unexpected acknowledged alert %+v", ack)
+	}
+	if _, err := svc.AcknowledgeAlert(ctx, a.ID, userID, ""); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("expected a conflict acknowledging an acknowledged alert, got %v", err)
+	}
+
+	// the acknowledged alert is still tracked and resolved once the series is ok again.
+	if err := svc.TrackStatuses(ctx, []influxdb.AlertStatus{status("a", "ok", 3*time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := svc.FindAlertByID(ctx, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != influxdb.AlertResolved || got.ResolvedAt == nil || !got.ResolvedAt.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("expected the alert to be resolved by the ok status, got %+v", got)
+	}
+
+	// a new non ok status opens a new alert.
+	if err := svc.TrackStatuses(ctx, []influxdb.AlertStatus{status("a", "warn", 4*time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	history, n, err := svc.FindAlerts(ctx, influxdb.AlertFilter{CheckID: &checkID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 || history[0].Tags["host"] != "a" || history[0].ID == a.ID || history[0].Status != influxdb.AlertOpen {
+		t.Errorf("expected the history of the check with the new alert first, got %d alerts", n)
+	}
+	if _, n, _ := svc.FindAlerts(ctx, influxdb.AlertFilter{CheckID: &checkID}, influxdb.FindOptions{Offset: 1, Limit: 1}); n != 1 {
+		t.Errorf("expected a page of 1 alert, got %d", n)
+	}
+	resolved := influxdb.AlertResolved
+	if _, n, _ := svc.FindAlerts(ctx, influxdb.AlertFilter{OrgID: &orgID, Status: &resolved}); n != 1 {
+		t.Errorf("expected 1 resolved alert, got %d", n)
+	}
+
+	var b *influxdb.Alert
+	for _, al := range history {
+		if al.Tags["host"] == "b" {
+			b = al
+		}
+	}
+	esc, err := svc.EscalateAlert(ctx, b.ID, 4, 5, start.Add(5*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !esc.EscalatedBy(4) || esc.EscalatedBy(5) {
+		t.Errorf("expected the alert to be escalated by rule 4 only, got %+v", esc.Events)
+	}
+	res, err := svc.ResolveAlert(ctx, b.ID, userID, "fixed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != influxdb.AlertResolved || res.ResolvedBy != userID {
+		t.Errorf("unexpected resolved alert %+v", res)
+	}
+	if _, err := svc.ResolveAlert(ctx, b.ID, userID, ""); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("expected a conflict resolving a resolved alert, got %v", err)
+	}
+	if _, err := svc.FindAlertByID(ctx, 42); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
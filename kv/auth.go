@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/buger/jsonparser"
 	influxdb "github.com/influxdata/influxdb"
@@ -15,7 +16,10 @@ var (
 	authIndex  = []byte("authorizationindexv1")
 )
 
-var _ influxdb.AuthorizationService = (*Service)(nil)
+var (
+	_ influxdb.AuthorizationService = (*Service)(nil)
+	_ influxdb.AuthorizationRotator = (*Service)(nil)
+)
 
 func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
 	if _, err := tx.Bucket(authBucket); err != nil {
@@ -126,7 +130,17 @@ func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string)
 			Err:  err,
 		}
 	}
-	return s.findAuthorizationByID(ctx, tx, id)
+
+	auth, err := s.findAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := auth.CheckExpiry(s.TimeGenerator.Now()); err != nil {
+		return nil, err
+	}
+
+	return auth, nil
 }
 
 func authorizationsPredicateFn(f influxdb.AuthorizationFilter) CursorPredicateFunc {
@@ -535,6 +549,68 @@ func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 	return a, nil
 }
 
+// RotateAuthorization creates an authorization with a new token and the permissions of the authorization,
+// and expires the authorization after the grace period. The new token is valid as long as the previous one was.
+func (s *Service) RotateAuthorization(ctx context.Context, id influxdb.ID, grace time.Duration) (*influxdb.Authorization, error) {
+	if grace < 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "grace period must not be negative",
+			Op:   influxdb.OpRotateAuthorization,
+		}
+	}
+
+	var a *influxdb.Authorization
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		var err error
+		a, err = s.rotateAuthorization(ctx, tx, id, grace)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (s *Service) rotateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, grace time.Duration) (*influxdb.Authorization, error) {
+	prev, err := s.findAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.TimeGenerator.Now()
+	a := &influxdb.Authorization{
+		Status:      prev.Status,
+		Description: prev.Description,
+		OrgID:       prev.OrgID,
+		UserID:      prev.UserID,
+		Permissions: prev.Permissions,
+	}
+	if prev.ExpiresAt != nil {
+		if lifetime := prev.ExpiresAt.Sub(prev.CreatedAt); lifetime > 0 {
+			expiresAt := now.Add(lifetime)
+			a.ExpiresAt = &expiresAt
+		}
+	}
+
+	if err := s.createAuthorization(ctx, tx, a); err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(grace)
+	if prev.ExpiresAt == nil || expiresAt.Before(*prev.ExpiresAt) {
+		prev.ExpiresAt = &expiresAt
+	}
+	prev.SetUpdatedAt(now)
+
+	if err := s.putAuthorization(ctx, tx, prev); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
 func authIndexBucket(tx Tx) (Bucket, error) {
 	b, err := tx.Bucket([]byte(authIndex))
 	if err != nil {
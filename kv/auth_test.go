@@ -3,9 +3,12 @@ package kv_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
 	"go.uber.org/zap/zaptest"
 )
@@ -32,6 +35,9 @@ func initAuthorizationService(s kv.Store, f influxdbtesting.AuthorizationFields,
 	svc.IDGenerator = f.IDGenerator
 	svc.TokenGenerator = f.TokenGenerator
 	svc.TimeGenerator = f.TimeGenerator
+	if f.TimeGenerator == nil {
+		svc.TimeGenerator = influxdb.RealTimeGenerator{}
+	}
 
 	ctx := context.Background()
 	if err := svc.Initialize(ctx); err != nil {
@@ -76,3 +82,68 @@ func initAuthorizationService(s kv.Store, f influxdbtesting.AuthorizationFields,
 		}
 	}
 }
+
+func TestService_RotateAuthorization(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	now := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PutUser(ctx, &influxdb.User{ID: 1, Name: "user"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PutOrganization(ctx, &influxdb.Organization{ID: 2, Name: "org"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := now.Add(24 * time.Hour)
+	prev := &influxdb.Authorization{
+		OrgID:       2,
+		UserID:      1,
+		Description: "telegraf",
+		Permissions: influxdb.OperPermissions(),
+		ExpiresAt:   &expiresAt,
+	}
+	if err := svc.CreateAuthorization(ctx, prev); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(12 * time.Hour)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+	a, err := svc.RotateAuthorization(ctx, prev.ID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID == prev.ID || a.Token == prev.Token || a.Description != prev.Description || len(a.Permissions) != len(prev.Permissions) {
+		t.Fatalf("expected a new token with the same permissions, got %+v", a)
+	}
+	if a.ExpiresAt == nil || !a.ExpiresAt.Equal(now.Add(24*time.Hour)) {
+		t.Errorf("expected the new token to be valid as long as the previous one, got %v", a.ExpiresAt)
+	}
+
+	// the previous token is valid for the grace period.
+	got, err := svc.FindAuthorizationByToken(ctx, prev.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected the previous token to expire after the grace period, got %v", got.ExpiresAt)
+	}
+
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now.Add(time.Hour)}
+	if _, err := svc.FindAuthorizationByToken(ctx, prev.Token); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected the previous token to be expired, got %v", err)
+	}
+	if _, err := svc.FindAuthorizationByToken(ctx, a.Token); err != nil {
+		t.Errorf("expected the new token to be valid, got %v", err)
+	}
+
+	if _, err := svc.RotateAuthorization(ctx, a.ID, -time.Hour); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected a negative grace period to be invalid, got %v", err)
+	}
+	if _, err := svc.RotateAuthorization(ctx, 100, time.Hour); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a missing authorization not to be found, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.DeliveryQueue = (*Service)(nil)
+
+func newDeliveryQueueStore() *StoreBase {
+	const resource = "queued delivery"
+
+	var decDeliveryEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var d influxdb.QueuedDelivery
+		return key, &d, json.Unmarshal(val, &d)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, v interface{}) (Entity, error) {
+		d, ok := v.(*influxdb.QueuedDelivery)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return Entity{
+			PK:   EncID(d.ID),
+			Body: d,
+		}, nil
+	}
+
+	return NewStoreBase(resource, []byte("notificationdeliveryqueuev1"), EncIDKey, EncBodyJSON, decDeliveryEntFn, decValToEntFn)
+}
+
+// QueueDelivery adds the delivery to the queue, or replaces it once it has an ID.
+func (s *Service) QueueDelivery(ctx context.Context, d *influxdb.QueuedDelivery) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		opt := PutUpdate()
+		if !d.ID.Valid() {
+			d.ID = s.IDGenerator.ID()
+			if d.CreatedAt.IsZero() {
+				d.CreatedAt = s.Now()
+			}
+			opt = PutNew()
+		}
+		if err := s.deliveryQueueStore.Put(ctx, tx, Entity{PK: EncID(d.ID), Body: d}, opt); err != nil {
+			return &influxdb.Error{
+				Op:  influxdb.OpQueueDelivery,
+				Err: err,
+			}
+		}
+		return nil
+	})
+}
+
+// FindDueDeliveries returns at most limit deliveries due at now, the earliest due first.
+// A limit of zero returns all the due deliveries.
+func (s *Service) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*influxdb.QueuedDelivery, error) {
+	ds := make([]*influxdb.QueuedDelivery, 0)
+	err := s.kv.View(ctx, func(tx Tx) error {
+		return s.deliveryQueueStore.Find(ctx, tx, FindOpts{
+			FilterEntFn: func(_ []byte, v interface{}) bool {
+				d, ok := v.(*influxdb.QueuedDelivery)
+				return ok && !d.NextAttemptAt.After(now)
+			},
+			CaptureFn: func(_ []byte, v interface{}) error {
+				d, ok := v.(*influxdb.QueuedDelivery)
+				if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+					return err
+				}
+				ds = append(ds, d)
+				return nil
+			},
+		})
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindDueDeliveries,
+			Err: err,
+		}
+	}
+
+	sort.SliceStable(ds, func(i, j int) bool {
+		return ds[i].NextAttemptAt.Before(ds[j].NextAttemptAt)
+	})
+	if limit > 0 && limit < len(ds) {
+		ds = ds[:limit]
+	}
+	return ds, nil
+}
+
+// DeleteQueuedDelivery removes a delivery from the queue by its ID.
+func (s *Service) DeleteQueuedDelivery(ctx context.Context, id influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		ent := Entity{PK: EncID(id)}
+		_, err := s.deliveryQueueStore.FindEnt(ctx, tx, ent)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Op:   influxdb.OpDeleteQueuedDelivery,
+				Msg:  influxdb.ErrQueuedDeliveryNotFound,
+			}
+		}
+		if err != nil {
+			return err
+		}
+		return s.deliveryQueueStore.DeleteEnt(ctx, tx, ent)
+	})
+}
@@ -0,0 +1,78 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_DeliveryQueue(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	queue := func(url string, due time.Duration) *influxdb.QueuedDelivery {
+		d := &influxdb.QueuedDelivery{
+			OrgID:         1,
+			RuleID:        2,
+			EndpointID:    3,
+			Request:       influxdb.NotificationRequest{URL: url, Body: "{}"},
+			Attempts:      1,
+			NextAttemptAt: now.Add(due),
+		}
+		if err := svc.QueueDelivery(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+		if !d.ID.Valid() || d.CreatedAt.IsZero() {
+			t.Fatalf("expected the queued delivery to get an ID and a creation time, got %+v", d)
+		}
+		return d
+	}
+	later := queue("http://later", time.Minute)
+	second := queue("http://second", -time.Second)
+	first := queue("http://first", -time.Minute)
+
+	due, err := svc.FindDueDeliveries(ctx, now, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 2 || due[0].ID != first.ID || due[1].ID != second.ID {
+		t.Fatalf("expected the due deliveries, the earliest first, got %+v", due)
+	}
+	if due, _ := svc.FindDueDeliveries(ctx, now, 1); len(due) != 1 || due[0].ID != first.ID {
+		t.Errorf("expected the limit to keep the earliest due delivery, got %+v", due)
+	}
+
+	// requeuing a delivery replaces it.
+	first.Attempts = 2
+	first.NextAttemptAt = now.Add(time.Hour)
+	if err := svc.QueueDelivery(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteQueuedDelivery(ctx, second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if due, _ := svc.FindDueDeliveries(ctx, now, 0); len(due) != 0 {
+		t.Errorf("expected no due delivery, got %+v", due)
+	}
+
+	due, err = svc.FindDueDeliveries(ctx, now.Add(2*time.Hour), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 2 || due[0].ID != later.ID || due[1].ID != first.ID || due[1].Attempts != 2 {
+		t.Errorf("unexpected due deliveries %+v", due)
+	}
+
+	if err := svc.DeleteQueuedDelivery(ctx, second.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected deleting a missing delivery to be not found, got %v", err)
+	}
+}
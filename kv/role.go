@@ -0,0 +1,526 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RoleService = (*Service)(nil)
+
+func newRoleStore() *StoreBase {
+	const resource = "role"
+
+	var decRoleEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var r influxdb.Role
+		return key, &r, json.Unmarshal(val, &r)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, v interface{}) (Entity, error) {
+		r, ok := v.(*influxdb.Role)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return Entity{
+			PK:   EncID(r.ID),
+			Body: r,
+		}, nil
+	}
+
+	return NewStoreBase(resource, []byte("rolesv1"), EncIDKey, EncBodyJSON, decRoleEntFn, decValToEntFn)
+}
+
+// newRoleAssignmentStore stores the role assignments by user, then role.
+func newRoleAssignmentStore() *StoreBase {
+	const resource = "role assignment"
+
+	var decAssignmentEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var a influxdb.RoleAssignment
+		return key, &a, json.Unmarshal(val, &a)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, v interface{}) (Entity, error) {
+		a, ok := v.(*influxdb.RoleAssignment)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return Entity{
+			PK:   roleAssignmentKey(a.UserID, a.RoleID),
+			Body: a,
+		}, nil
+	}
+
+	return NewStoreBase(resource, []byte("roleassignmentsv1"), EncIDKey, EncBodyJSON, decAssignmentEntFn, decValToEntFn)
+}
+
+func roleAssignmentKey(userID, roleID influxdb.ID) EncodeFn {
+	return Encode(EncID(userID), EncID(roleID))
+}
+
+func (s *Service) initializeRoles(ctx context.Context, tx Tx) error {
+	for _, store := range []*StoreBase{s.roleStore, s.roleAssignmentStore} {
+		if err := store.Init(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return s.migrateOrgMappingsToRoles(ctx, tx)
+}
+
+// migrateOrgMappingsToRoles assigns the built-in roles of the organizations to their owners and members.
+// The permissions of users are derived from their roles, the mappings created before roles grant them this way.
+func (s *Service) migrateOrgMappingsToRoles(ctx context.Context, tx Tx) error {
+	ms, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{ResourceType: influxdb.OrgsResourceType})
+	if err != nil {
+		return err
+	}
+	for _, m := range ms {
+		if err := s.assignBuiltInRole(ctx, tx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindRoleByID finds a single role by its ID.
+func (s *Service) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	var r *influxdb.Role
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		r, err = s.findRoleByID(ctx, tx, id)
+		return err
+	})
+	return r, err
+}
+
+func (s *Service) findRoleByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Role, error) {
+	body, err := s.roleStore.FindEnt(ctx, tx, Entity{PK: EncID(id)})
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Op:   influxdb.OpFindRoleByID,
+			Msg:  influxdb.ErrRoleNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	r, ok := body.(*influxdb.Role)
+	return r, IsErrUnexpectedDecodeVal(ok)
+}
+
+// FindRoles returns the roles matching the filter and their count.
+func (s *Service) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	var rs []*influxdb.Role
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		rs, err = s.findRoles(ctx, tx, filter, o)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return rs, len(rs), nil
+}
+
+func (s *Service) findRoles(ctx context.Context, tx Tx, filter influxdb.RoleFilter, o influxdb.FindOptions) ([]*influxdb.Role, error) {
+	rs := make([]*influxdb.Role, 0)
+	err := s.roleStore.Find(ctx, tx, FindOpts{
+		Descending:  o.Descending,
+		Offset:      o.Offset,
+		Limit:       o.Limit,
+		FilterEntFn: filterRolesFn(filter),
+		CaptureFn: func(k []byte, v interface{}) error {
+			r, ok := v.(*influxdb.Role)
+			if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+				return err
+			}
+			rs = append(rs, r)
+			return nil
+		},
+	})
+	return rs, err
+}
+
+func filterRolesFn(filter influxdb.RoleFilter) func([]byte, interface{}) bool {
+	return func(key []byte, val interface{}) bool {
+		r, ok := val.(*influxdb.Role)
+		if !ok {
+			return false
+		}
+		if filter.ID != nil && r.ID != *filter.ID {
+			return false
+		}
+		if filter.OrgID != nil && r.OrgID != *filter.OrgID {
+			return false
+		}
+		if filter.Name != nil && r.Name != *filter.Name {
+			return false
+		}
+		return true
+	}
+}
+
+// CreateRole creates a new role and assigns it an ID.
+func (s *Service) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	if err := r.Valid(); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpCreateRole,
+			Err:  err,
+		}
+	}
+	if r.BuiltIn {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpCreateRole,
+			Msg:  "built-in roles can't be created",
+		}
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, r.OrgID); err != nil {
+			return err
+		}
+		if err := s.uniqueRoleName(ctx, tx, r); err != nil {
+			return err
+		}
+
+		r.ID = s.IDGenerator.ID()
+		now := s.Now()
+		r.CreatedAt = now
+		r.UpdatedAt = now
+		return s.roleStore.Put(ctx, tx, Entity{PK: EncID(r.ID), Body: r}, PutNew())
+	})
+}
+
+func (s *Service) uniqueRoleName(ctx context.Context, tx Tx, r *influxdb.Role) error {
+	rs, err := s.findRoles(ctx, tx, influxdb.RoleFilter{OrgID: &r.OrgID, Name: &r.Name}, influxdb.FindOptions{})
+	if err != nil {
+		return err
+	}
+	for _, existing := range rs {
+		if existing.ID != r.ID {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  "role with name " + r.Name + " already exists",
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateRole updates a single role with a changeset.
+func (s *Service) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	var r *influxdb.Role
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		current, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if current.BuiltIn {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpUpdateRole,
+				Msg:  "built-in roles can't be changed",
+			}
+		}
+
+		upd.Apply(current)
+		if err := current.Valid(); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpUpdateRole,
+				Err:  err,
+			}
+		}
+		if err := s.uniqueRoleName(ctx, tx, current); err != nil {
+			return err
+		}
+		current.UpdatedAt = s.Now()
+		r = current
+		return s.roleStore.Put(ctx, tx, Entity{PK: EncID(id), Body: r}, PutUpdate())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DeleteRole removes a role by its ID along with its assignments.
+func (s *Service) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		r, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if r.BuiltIn {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpDeleteRole,
+				Msg:  "built-in roles can't be deleted",
+			}
+		}
+		return s.deleteRole(ctx, tx, id)
+	})
+}
+
+func (s *Service) deleteRole(ctx context.Context, tx Tx, id influxdb.ID) error {
+	err := s.roleAssignmentStore.Delete(ctx, tx, DeleteOpts{
+		FilterFn: func(k []byte, v interface{}) bool {
+			a, ok := v.(*influxdb.RoleAssignment)
+			return ok && a.RoleID == id
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return s.roleStore.DeleteEnt(ctx, tx, Entity{PK: EncID(id)})
+}
+
+// deleteUserRoleAssignments removes the assignments of roles to a user.
+func (s *Service) deleteUserRoleAssignments(ctx context.Context, tx Tx, userID influxdb.ID) error {
+	return s.roleAssignmentStore.Delete(ctx, tx, DeleteOpts{
+		FilterFn: func(k []byte, v interface{}) bool {
+			a, ok := v.(*influxdb.RoleAssignment)
+			return ok && a.UserID == userID
+		},
+	})
+}
+
+// deleteOrgRoles deletes the roles of an organization along with their assignments.
+func (s *Service) deleteOrgRoles(ctx context.Context, tx Tx, orgID influxdb.ID) error {
+	rs, err := s.findRoles(ctx, tx, influxdb.RoleFilter{OrgID: &orgID}, influxdb.FindOptions{})
+	if err != nil {
+		return err
+	}
+	for _, r := range rs {
+		if err := s.deleteRole(ctx, tx, r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindRoleAssignments returns the role assignments matching the filter and their count.
+func (s *Service) FindRoleAssignments(ctx context.Context, filter influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+	var as []*influxdb.RoleAssignment
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		as, err = s.findRoleAssignments(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return as, len(as), nil
+}
+
+func (s *Service) findRoleAssignments(ctx context.Context, tx Tx, filter influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, error) {
+	var prefix []byte
+	if filter.UserID != nil {
+		var err error
+		if prefix, err = EncID(*filter.UserID)(); err != nil {
+			return nil, err
+		}
+	}
+
+	as := make([]*influxdb.RoleAssignment, 0)
+	err := s.roleAssignmentStore.Find(ctx, tx, FindOpts{
+		Prefix: prefix,
+		FilterEntFn: func(k []byte, v interface{}) bool {
+			a, ok := v.(*influxdb.RoleAssignment)
+			if !ok {
+				return false
+			}
+			if filter.RoleID != nil && a.RoleID != *filter.RoleID {
+				return false
+			}
+			if filter.OrgID != nil && a.OrgID != *filter.OrgID {
+				return false
+			}
+			return true
+		},
+		CaptureFn: func(k []byte, v interface{}) error {
+			a, ok := v.(*influxdb.RoleAssignment)
+			if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+				return err
+			}
+			as = append(as, a)
+			return nil
+		},
+	})
+	return as, err
+}
+
+// AssignRole assigns a role to a user, the user is granted the permissions of the role.
+// The built-in roles are assigned to the owners and members of organizations.
+func (s *Service) AssignRole(ctx context.Context, a *influxdb.RoleAssignment) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		r, err := s.findRoleByID(ctx, tx, a.RoleID)
+		if err != nil {
+			return err
+		}
+		if r.BuiltIn {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpAssignRole,
+				Msg:  "built-in roles are assigned to the owners and members of the organization",
+			}
+		}
+		if _, err := s.findUserByID(ctx, tx, a.UserID); err != nil {
+			return err
+		}
+		ms, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+			ResourceType: influxdb.OrgsResourceType,
+			ResourceID:   r.OrgID,
+			UserID:       a.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		if len(ms) == 0 {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpAssignRole,
+				Msg:  "roles are only assigned to the owners and members of the organization of the role",
+			}
+		}
+
+		a.OrgID = r.OrgID
+		return s.roleAssignmentStore.Put(ctx, tx, Entity{PK: roleAssignmentKey(a.UserID, a.RoleID), Body: a})
+	})
+}
+
+// UnassignRole removes the assignment of a role to a user.
+func (s *Service) UnassignRole(ctx context.Context, roleID, userID influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		r, err := s.findRoleByID(ctx, tx, roleID)
+		if err != nil {
+			return err
+		}
+		if r.BuiltIn {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpUnassignRole,
+				Msg:  "built-in roles are assigned to the owners and members of the organization",
+			}
+		}
+		return s.unassignRole(ctx, tx, roleID, userID)
+	})
+}
+
+func (s *Service) unassignRole(ctx context.Context, tx Tx, roleID, userID influxdb.ID) error {
+	key := roleAssignmentKey(userID, roleID)
+	if _, err := s.roleAssignmentStore.FindEnt(ctx, tx, Entity{PK: key}); err != nil {
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Op:   influxdb.OpUnassignRole,
+				Msg:  influxdb.ErrRoleAssignmentNotFound,
+			}
+		}
+		return err
+	}
+	return s.roleAssignmentStore.DeleteEnt(ctx, tx, Entity{PK: key})
+}
+
+// builtInRole returns the built-in role of the owners or of the members of an organization, creating it if missing.
+func (s *Service) builtInRole(ctx context.Context, tx Tx, orgID influxdb.ID, ut influxdb.UserType) (*influxdb.Role, error) {
+	r, err := influxdb.NewBuiltInRole(orgID, ut)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findBuiltInRole(ctx, tx, orgID, r.Name)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	r.ID = s.RoleIDs.ID()
+	now := s.Now()
+	r.CreatedAt = now
+	r.UpdatedAt = now
+	if err := s.roleStore.Put(ctx, tx, Entity{PK: EncID(r.ID), Body: r}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// findBuiltInRole returns the built-in role of an organization with the name, or nil if it doesn't exist yet.
+func (s *Service) findBuiltInRole(ctx context.Context, tx Tx, orgID influxdb.ID, name string) (*influxdb.Role, error) {
+	rs, err := s.findRoles(ctx, tx, influxdb.RoleFilter{OrgID: &orgID, Name: &name}, influxdb.FindOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rs {
+		if r.BuiltIn {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// assignBuiltInRole assigns the built-in role of the owners or of the members of an organization
+// to the user of the organization mapping.
+func (s *Service) assignBuiltInRole(ctx context.Context, tx Tx, m *influxdb.UserResourceMapping) error {
+	r, err := s.builtInRole(ctx, tx, m.ResourceID, m.UserType)
+	if err != nil {
+		return err
+	}
+	a := &influxdb.RoleAssignment{
+		RoleID: r.ID,
+		UserID: m.UserID,
+		OrgID:  r.OrgID,
+	}
+	return s.roleAssignmentStore.Put(ctx, tx, Entity{PK: roleAssignmentKey(a.UserID, a.RoleID), Body: a})
+}
+
+// unassignBuiltInRole removes the assignment of the built-in role of the organization mapping.
+func (s *Service) unassignBuiltInRole(ctx context.Context, tx Tx, m *influxdb.UserResourceMapping) error {
+	builtIn, err := influxdb.NewBuiltInRole(m.ResourceID, m.UserType)
+	if err != nil {
+		return err
+	}
+	r, err := s.findBuiltInRole(ctx, tx, m.ResourceID, builtIn.Name)
+	if err != nil || r == nil {
+		return err
+	}
+	err = s.unassignRole(ctx, tx, r.ID, m.UserID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil
+	}
+	return err
+}
+
+// rolePermissions returns the permissions of the roles assigned to the user.
+func (s *Service) rolePermissions(ctx context.Context, tx Tx, userID influxdb.ID) ([]influxdb.Permission, error) {
+	as, err := s.findRoleAssignments(ctx, tx, influxdb.RoleAssignmentFilter{UserID: &userID})
+	if err != nil {
+		return nil, err
+	}
+
+	var ps []influxdb.Permission
+	for _, a := range as {
+		r, err := s.findRoleByID(ctx, tx, a.RoleID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.BuiltIn {
+			// the permissions of the built-in roles follow the permissions of owners and members.
+			if r, err = influxdb.NewBuiltInRole(r.OrgID, influxdb.UserType(r.Name)); err != nil {
+				return nil, err
+			}
+		}
+		ps = append(ps, r.Permissions...)
+	}
+	return ps, nil
+}
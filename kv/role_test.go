@@ -0,0 +1,242 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Roles(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	dashboardsWrite := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &org.ID},
+	}
+	r := &influxdb.Role{
+		OrgID:       org.ID,
+		Name:        "dashboard editor",
+		Permissions: []influxdb.Permission{dashboardsWrite},
+	}
+	if err := svc.CreateRole(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.ID.Valid() || r.CreatedAt.IsZero() {
+		t.Fatalf("expected the role to be assigned an ID and a creation time, got %+v", r)
+	}
+	if err := svc.CreateRole(ctx, &influxdb.Role{OrgID: org.ID, Name: r.Name, Permissions: r.Permissions}); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("expected a conflict for a role with the name of another role, got %v", err)
+	}
+	if err := svc.CreateRole(ctx, &influxdb.Role{OrgID: org.ID, Name: "empty"}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error for a role without permissions, got %v", err)
+	}
+
+	if err := svc.AssignRole(ctx, &influxdb.RoleAssignment{RoleID: r.ID, UserID: user.ID}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error assigning a role to a user outside of its organization, got %v", err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   org.ID,
+		UserID:       user.ID,
+		UserType:     influxdb.Member,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.AssignRole(ctx, &influxdb.RoleAssignment{RoleID: r.ID, UserID: user.ID}); err != nil {
+		t.Fatal(err)
+	}
+	as, n, err := svc.FindRoleAssignments(ctx, influxdb.RoleAssignmentFilter{UserID: &user.ID, RoleID: &r.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || as[0].RoleID != r.ID || as[0].OrgID != org.ID {
+		t.Errorf("expected the user to be assigned the role, got %+v", as)
+	}
+
+	sess, err := svc.CreateSession(ctx, user.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess, err = svc.FindSession(ctx, sess.Key); err != nil {
+		t.Fatal(err)
+	}
+	if !sess.Allowed(dashboardsWrite) {
+		t.Errorf("expected the session to be granted the permissions of the role")
+	}
+
+	name := "dashboard writer"
+	upd, err := svc.UpdateRole(ctx, r.ID, influxdb.RoleUpdate{Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upd.Name != name || len(upd.Permissions) != 1 {
+		t.Errorf("unexpected updated role %+v", upd)
+	}
+
+	if err := svc.UnassignRole(ctx, r.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UnassignRole(ctx, r.ID, user.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a not found error for a missing assignment, got %v", err)
+	}
+
+	if err := svc.AssignRole(ctx, &influxdb.RoleAssignment{RoleID: r.ID, UserID: user.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteRole(ctx, r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindRoleByID(ctx, r.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the role to be deleted, got %v", err)
+	}
+	if _, n, _ := svc.FindRoleAssignments(ctx, influxdb.RoleAssignmentFilter{RoleID: &r.ID}); n != 0 {
+		t.Errorf("expected the assignments of the role to be deleted, got %d", n)
+	}
+
+	r = &influxdb.Role{OrgID: org.ID, Name: "dashboard editor", Permissions: []influxdb.Permission{dashboardsWrite}}
+	if err := svc.CreateRole(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AssignRole(ctx, &influxdb.RoleAssignment{RoleID: r.ID, UserID: user.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteUser(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, n, _ := svc.FindRoleAssignments(ctx, influxdb.RoleAssignmentFilter{UserID: &user.ID}); n != 0 {
+		t.Errorf("expected the assignments of the deleted user to be deleted, got %d", n)
+	}
+}
+
+func TestService_BuiltInRoles(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &influxdb.UserResourceMapping{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   org.ID,
+		UserType:     influxdb.Member,
+		UserID:       user.ID,
+	}
+	if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+
+	name := influxdb.MemberRoleName
+	rs, _, err := svc.FindRoles(ctx, influxdb.RoleFilter{OrgID: &org.ID, Name: &name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || !rs[0].BuiltIn {
+		t.Fatalf("expected the built-in member role of the organization, got %+v", rs)
+	}
+	member := rs[0]
+	if _, n, _ := svc.FindRoleAssignments(ctx, influxdb.RoleAssignmentFilter{RoleID: &member.ID, UserID: &user.ID}); n != 1 {
+		t.Errorf("expected the member to be assigned the built-in member role, got %d assignments", n)
+	}
+
+	if _, err := svc.UpdateRole(ctx, member.ID, influxdb.RoleUpdate{Name: &name}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error updating a built-in role, got %v", err)
+	}
+	if err := svc.DeleteRole(ctx, member.ID); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error deleting a built-in role, got %v", err)
+	}
+	if err := svc.AssignRole(ctx, &influxdb.RoleAssignment{RoleID: member.ID, UserID: user.ID}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error assigning a built-in role, got %v", err)
+	}
+
+	if err := svc.DeleteUserResourceMapping(ctx, org.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, n, _ := svc.FindRoleAssignments(ctx, influxdb.RoleAssignmentFilter{UserID: &user.ID}); n != 0 {
+		t.Errorf("expected the built-in member role to be unassigned along with the mapping, got %d assignments", n)
+	}
+}
+
+func TestService_SessionPermissions_ResourceMappings(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &influxdb.Bucket{OrgID: org.ID, Name: "bucket"}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// mappings to resources other than organizations are not migrated to roles.
+	ms := []*influxdb.UserResourceMapping{
+		{
+			ResourceType: influxdb.DashboardsResourceType,
+			ResourceID:   influxdb.ID(1),
+			UserType:     influxdb.Member,
+			UserID:       user.ID,
+		},
+		{
+			ResourceType: influxdb.BucketsResourceType,
+			ResourceID:   bucket.ID,
+			UserType:     influxdb.Owner,
+			UserID:       user.ID,
+		},
+	}
+	for _, m := range ms {
+		if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sess, err := svc.CreateSession(ctx, user.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess, err = svc.FindSession(ctx, sess.Key); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range ms {
+		ps, err := m.ToPermissions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range ps {
+			if !sess.Allowed(p) {
+				t.Errorf("expected the session to be granted %s by the %s mapping", p, m.ResourceType)
+			}
+		}
+	}
+}
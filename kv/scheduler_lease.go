@@ -0,0 +1,280 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+)
+
+// Scheduler Lease Storage Schema
+// schedulerInstanceBucket:
+//   <instance>: the time the instance is alive until
+// schedulerLeaseBucket:
+//   <partition>: the lease of the partition, the partition is big endian encoded
+// schedulerClaimBucket:
+//   <taskID>: the last run of the task claimed by an instance
+
+var (
+	schedulerInstanceBucket = []byte("schedulerInstancesv1")
+	schedulerLeaseBucket    = []byte("schedulerLeasesv1")
+	schedulerClaimBucket    = []byte("schedulerClaimsv1")
+)
+
+var _ scheduler.LeaseStore = (*Service)(nil)
+
+type kvSchedulerInstance struct {
+	Instance string    `json:"instance"`
+	Expires  time.Time `json:"expires"`
+}
+
+type kvLease struct {
+	Partition int       `json:"partition"`
+	Owner     string    `json:"owner,omitempty"`
+	Expires   time.Time `json:"expires"`
+}
+
+type kvRunClaim struct {
+	ScheduledFor time.Time `json:"scheduledFor"`
+	Instance     string    `json:"instance"`
+}
+
+func (s *Service) initializeSchedulerLeases(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(schedulerInstanceBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(schedulerLeaseBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(schedulerClaimBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Heartbeat records that instance is alive until expires and returns the instances alive at now.
+// The instances that expired are removed.
+func (s *Service) Heartbeat(ctx context.Context, instance string, now, expires time.Time) ([]string, error) {
+	var instances []string
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(schedulerInstanceBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		v, err := json.Marshal(kvSchedulerInstance{Instance: instance, Expires: expires})
+		if err != nil {
+			return influxdb.ErrInternalTaskServiceError(err)
+		}
+		if err := b.Put([]byte(instance), v); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		c, err := b.Cursor()
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		var expired [][]byte
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var i kvSchedulerInstance
+			if err := json.Unmarshal(v, &i); err != nil {
+				return influxdb.ErrInternalTaskServiceError(err)
+			}
+			if !now.Before(i.Expires) {
+				expired = append(expired, k)
+				continue
+			}
+			instances = append(instances, i.Instance)
+		}
+
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return influxdb.ErrUnexpectedTaskBucketErr(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(instances)
+	return instances, nil
+}
+
+// Leases returns the leases of the partitions that were ever acquired.
+func (s *Service) Leases(ctx context.Context) ([]scheduler.Lease, error) {
+	var leases []scheduler.Lease
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(schedulerLeaseBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		c, err := b.Cursor()
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			var l kvLease
+			if err := json.Unmarshal(v, &l); err != nil {
+				return influxdb.ErrInternalTaskServiceError(err)
+			}
+			leases = append(leases, scheduler.Lease(l))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leases, nil
+}
+
+// AcquireLease makes instance the owner of partition until expires, if the lease of the partition
+// is expired at now or already owned by instance.
+func (s *Service) AcquireLease(ctx context.Context, partition int, instance string, now, expires time.Time) (scheduler.Lease, bool, error) {
+	var (
+		prev     scheduler.Lease
+		acquired bool
+	)
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		l, err := s.findLease(ctx, tx, partition)
+		if err != nil {
+			return err
+		}
+		prev = l
+
+		if l.Owner != instance && !l.Expired(now) {
+			return nil
+		}
+
+		acquired = true
+		return s.putLease(ctx, tx, scheduler.Lease{Partition: partition, Owner: instance, Expires: expires})
+	})
+	if err != nil {
+		return scheduler.Lease{}, false, err
+	}
+
+	return prev, acquired, nil
+}
+
+// ReleaseLease releases the lease of instance on partition, if it holds it.
+func (s *Service) ReleaseLease(ctx context.Context, partition int, instance string) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		l, err := s.findLease(ctx, tx, partition)
+		if err != nil {
+			return err
+		}
+		if l.Owner != instance {
+			return nil
+		}
+
+		// the expiry is kept to tell a released lease from a lease that was never acquired.
+		l.Owner = ""
+		return s.putLease(ctx, tx, l)
+	})
+}
+
+// ClaimRun claims the run of id scheduled for scheduledFor for instance, which must hold the lease of partition at now.
+func (s *Service) ClaimRun(ctx context.Context, id scheduler.ID, scheduledFor time.Time, partition int, instance string, now time.Time) (bool, error) {
+	var claimed bool
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		l, err := s.findLease(ctx, tx, partition)
+		if err != nil {
+			return err
+		}
+		if l.Owner != instance || l.Expired(now) {
+			return nil
+		}
+
+		b, err := tx.Bucket(schedulerClaimBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		key, err := influxdb.ID(id).Encode()
+		if err != nil {
+			return influxdb.ErrInvalidTaskID
+		}
+
+		v, err := b.Get(key)
+		if err != nil && !IsNotFound(err) {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		if err == nil {
+			var last kvRunClaim
+			if err := json.Unmarshal(v, &last); err != nil {
+				return influxdb.ErrInternalTaskServiceError(err)
+			}
+			if !scheduledFor.After(last.ScheduledFor) {
+				return nil
+			}
+		}
+
+		v, err = json.Marshal(kvRunClaim{ScheduledFor: scheduledFor.UTC(), Instance: instance})
+		if err != nil {
+			return influxdb.ErrInternalTaskServiceError(err)
+		}
+		if err := b.Put(key, v); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		claimed = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return claimed, nil
+}
+
+// findLease returns the lease of partition, a zero lease if it was never acquired.
+func (s *Service) findLease(ctx context.Context, tx Tx, partition int) (scheduler.Lease, error) {
+	b, err := tx.Bucket(schedulerLeaseBucket)
+	if err != nil {
+		return scheduler.Lease{}, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	v, err := b.Get(leaseKey(partition))
+	if IsNotFound(err) {
+		return scheduler.Lease{Partition: partition}, nil
+	}
+	if err != nil {
+		return scheduler.Lease{}, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	var l kvLease
+	if err := json.Unmarshal(v, &l); err != nil {
+		return scheduler.Lease{}, influxdb.ErrInternalTaskServiceError(err)
+	}
+	return scheduler.Lease(l), nil
+}
+
+func (s *Service) putLease(ctx context.Context, tx Tx, l scheduler.Lease) error {
+	b, err := tx.Bucket(schedulerLeaseBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	v, err := json.Marshal(kvLease(l))
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+	if err := b.Put(leaseKey(l.Partition), v); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
+func leaseKey(partition int) []byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], uint64(partition))
+	return k[:]
+}
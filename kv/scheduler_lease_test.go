@@ -0,0 +1,120 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_SchedulerLeases(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Minute)
+
+	t.Run("heartbeat", func(t *testing.T) {
+		if _, err := svc.Heartbeat(ctx, "a", now, expires); err != nil {
+			t.Fatal(err)
+		}
+		instances, err := svc.Heartbeat(ctx, "b", now, now.Add(time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"a", "b"}; !cmp.Equal(want, instances) {
+			t.Errorf("unexpected instances -want/+got:\n%s", cmp.Diff(want, instances))
+		}
+
+		// b expired.
+		instances, err = svc.Heartbeat(ctx, "a", now.Add(2*time.Second), expires)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"a"}; !cmp.Equal(want, instances) {
+			t.Errorf("unexpected instances -want/+got:\n%s", cmp.Diff(want, instances))
+		}
+	})
+
+	t.Run("leases", func(t *testing.T) {
+		prev, ok, err := svc.AcquireLease(ctx, 1, "a", now, expires)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || !prev.Expires.IsZero() {
+			t.Fatalf("expected to acquire a lease never acquired, got %v %+v", ok, prev)
+		}
+
+		if _, ok, err := svc.AcquireLease(ctx, 1, "b", now, expires); err != nil || ok {
+			t.Fatalf("expected not to acquire a lease held by another instance, got %v %v", ok, err)
+		}
+		if _, ok, err := svc.AcquireLease(ctx, 1, "a", now, expires.Add(time.Minute)); err != nil || !ok {
+			t.Fatalf("expected to renew a lease, got %v %v", ok, err)
+		}
+
+		// the lease of a expired.
+		prev, ok, err = svc.AcquireLease(ctx, 1, "b", expires.Add(time.Minute), expires.Add(2*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || prev.Owner != "a" {
+			t.Fatalf("expected to take over an expired lease of a, got %v %+v", ok, prev)
+		}
+
+		if err := svc.ReleaseLease(ctx, 1, "a"); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.ReleaseLease(ctx, 1, "b"); err != nil {
+			t.Fatal(err)
+		}
+		prev, ok, err = svc.AcquireLease(ctx, 1, "a", now, expires)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || prev.Owner != "" || prev.Expires.IsZero() {
+			t.Fatalf("expected to acquire a released lease, got %v %+v", ok, prev)
+		}
+
+		leases, err := svc.Leases(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []scheduler.Lease{{Partition: 1, Owner: "a", Expires: expires}}; !cmp.Equal(want, leases) {
+			t.Errorf("unexpected leases -want/+got:\n%s", cmp.Diff(want, leases))
+		}
+	})
+
+	t.Run("claims", func(t *testing.T) {
+		if _, _, err := svc.AcquireLease(ctx, 2, "a", now, expires); err != nil {
+			t.Fatal(err)
+		}
+
+		sf := now.Add(-time.Minute)
+		if ok, err := svc.ClaimRun(ctx, 10, sf, 2, "b", now); err != nil || ok {
+			t.Fatalf("expected not to claim a run without the lease, got %v %v", ok, err)
+		}
+		if ok, err := svc.ClaimRun(ctx, 10, sf, 2, "a", now); err != nil || !ok {
+			t.Fatalf("expected to claim a run, got %v %v", ok, err)
+		}
+		if ok, err := svc.ClaimRun(ctx, 10, sf, 2, "a", now); err != nil || ok {
+			t.Fatalf("expected not to claim a run twice, got %v %v", ok, err)
+		}
+		if ok, err := svc.ClaimRun(ctx, 10, sf.Add(-time.Second), 2, "a", now); err != nil || ok {
+			t.Fatalf("expected not to claim a run before the last claimed run, got %v %v", ok, err)
+		}
+		if ok, err := svc.ClaimRun(ctx, 10, sf.Add(time.Second), 2, "a", expires); err != nil || ok {
+			t.Fatalf("expected not to claim a run once the lease expired, got %v %v", ok, err)
+		}
+		if ok, err := svc.ClaimRun(ctx, 10, sf.Add(time.Second), 2, "a", now); err != nil || !ok {
+			t.Fatalf("expected to claim the next run, got %v %v", ok, err)
+		}
+	})
+}
@@ -40,6 +40,10 @@ type Service struct {
 	// bucket into the old measurement in storage.
 	OrgBucketIDs influxdb.IDGenerator
 
+	// RoleIDs generates the IDs of the built-in roles, which are created
+	// along with the mappings of users to organizations.
+	RoleIDs influxdb.IDGenerator
+
 	TokenGenerator influxdb.TokenGenerator
 	// TODO(desa:ariel): this should not be embedded
 	influxdb.TimeGenerator
@@ -50,6 +54,16 @@ type Service struct {
 	checkStore    *IndexStore
 	endpointStore *IndexStore
 	variableStore *IndexStore
+	silenceStore  *StoreBase
+
+	alertStore      *StoreBase
+	alertOpenIndex  *StoreBase
+	alertCheckIndex *StoreBase
+
+	deliveryQueueStore *StoreBase
+
+	roleStore           *StoreBase
+	roleAssignmentStore *StoreBase
 }
 
 // NewService returns an instance of a Service.
@@ -58,16 +72,24 @@ func NewService(log *zap.Logger, kv Store, configs ...ServiceConfig) *Service {
 		log:         log,
 		IDGenerator: snowflake.NewIDGenerator(),
 		// Seed the random number generator with the current time
-		OrgBucketIDs:   rand.NewOrgBucketID(time.Now().UnixNano()),
-		TokenGenerator: rand.NewTokenGenerator(64),
-		Hash:           &Bcrypt{},
-		kv:             kv,
-		audit:          noop.ResourceLogger{},
-		TimeGenerator:  influxdb.RealTimeGenerator{},
-		checkStore:     newCheckStore(),
-		endpointStore:  newEndpointStore(),
-		variableStore:  newVariableStore(),
-		indexer:        NewIndexer(log, kv),
+		OrgBucketIDs:        rand.NewOrgBucketID(time.Now().UnixNano()),
+		RoleIDs:             snowflake.NewIDGenerator(),
+		TokenGenerator:      rand.NewTokenGenerator(64),
+		Hash:                &Bcrypt{},
+		kv:                  kv,
+		audit:               noop.ResourceLogger{},
+		TimeGenerator:       influxdb.RealTimeGenerator{},
+		checkStore:          newCheckStore(),
+		endpointStore:       newEndpointStore(),
+		variableStore:       newVariableStore(),
+		silenceStore:        newSilenceStore(),
+		alertStore:          newAlertStore(),
+		alertOpenIndex:      newAlertOpenIndexStore(),
+		alertCheckIndex:     newAlertCheckIndexStore(),
+		deliveryQueueStore:  newDeliveryQueueStore(),
+		roleStore:           newRoleStore(),
+		roleAssignmentStore: newRoleAssignmentStore(),
+		indexer:             NewIndexer(log, kv),
 	}
 
 	if len(configs) > 0 {
@@ -133,6 +155,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeSchedulerLeases(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeScraperTargets(ctx, tx); err != nil {
 			return err
 		}
@@ -178,7 +204,21 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
-		return s.initializeUsers(ctx, tx)
+		if err := s.silenceStore.Init(ctx, tx); err != nil {
+			return err
+		}
+
+		for _, store := range []*StoreBase{s.alertStore, s.alertOpenIndex, s.alertCheckIndex, s.deliveryQueueStore} {
+			if err := store.Init(ctx, tx); err != nil {
+				return err
+			}
+		}
+
+		if err := s.initializeUsers(ctx, tx); err != nil {
+			return err
+		}
+
+		return s.initializeRoles(ctx, tx)
 	})
 
 }
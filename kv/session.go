@@ -103,6 +103,15 @@ func (s *Service) findSession(ctx context.Context, tx Tx, key string) (*influxdb
 
 func (s *Service) maxPermissions(ctx context.Context, tx Tx, userID influxdb.ID) ([]influxdb.Permission, error) {
 	// TODO(desa): these values should be cached so it's not so expensive to lookup each time.
+	ps, err := s.rolePermissions(ctx, tx, userID)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	// the mappings of the user grant their permissions next to the roles, the
+	// built-in roles of organizations grant the same permissions as their mappings.
 	f := influxdb.UserResourceMappingFilter{UserID: userID}
 	mappings, err := s.findUserResourceMappings(ctx, tx, f)
 	if err != nil {
@@ -110,8 +119,6 @@ func (s *Service) maxPermissions(ctx context.Context, tx Tx, userID influxdb.ID)
 			Err: err,
 		}
 	}
-
-	ps := make([]influxdb.Permission, 0, len(mappings))
 	for _, m := range mappings {
 		p, err := m.ToPermissions()
 		if err != nil {
@@ -131,7 +138,11 @@ func (s *Service) maxPermissions(ctx context.Context, tx Tx, userID influxdb.ID)
 	if err != nil {
 		return nil, err
 	}
+	now := s.TimeGenerator.Now()
 	for _, a := range as {
+		if a.CheckExpiry(now) != nil {
+			continue
+		}
 		ps = append(ps, a.Permissions...)
 	}
 
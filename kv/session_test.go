@@ -3,8 +3,10 @@ package kv_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/kv"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
 	"go.uber.org/zap/zaptest"
@@ -55,3 +57,42 @@ func initSessionService(s kv.Store, f influxdbtesting.SessionFields, t *testing.
 		}
 	}
 }
+
+func TestService_FindSession_ExpiredAuthorization(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PutUser(ctx, &influxdb.User{ID: 1, Name: "user"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PutOrganization(ctx, &influxdb.Organization{ID: 2, Name: "org"}); err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Now().Add(-time.Minute)
+	err := svc.PutAuthorization(ctx, &influxdb.Authorization{
+		ID:          3,
+		Token:       "expired",
+		OrgID:       2,
+		UserID:      1,
+		Permissions: influxdb.OperPermissions(),
+		ExpiresAt:   &expiresAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sn, err := svc.CreateSession(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sn, err = svc.FindSession(ctx, sn.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := influxdb.Permission{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType}}
+	if sn.Allowed(p) {
+		t.Error("expected the session not to be allowed the permissions of an expired token")
+	}
+}
@@ -0,0 +1,165 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SilenceService = (*Service)(nil)
+
+func newSilenceStore() *StoreBase {
+	const resource = "silence"
+
+	var decSilenceEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var s influxdb.Silence
+		return key, &s, json.Unmarshal(val, &s)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, v interface{}) (Entity, error) {
+		s, ok := v.(*influxdb.Silence)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return Entity{
+			PK:   EncID(s.ID),
+			Body: s,
+		}, nil
+	}
+
+	return NewStoreBase(resource, []byte("silencesv1"), EncIDKey, EncBodyJSON, decSilenceEntFn, decValToEntFn)
+}
+
+// FindSilenceByID finds a single silence by its ID.
+func (s *Service) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var sil *influxdb.Silence
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		sil, err = s.findSilenceByID(ctx, tx, id)
+		return err
+	})
+	return sil, err
+}
+
+func (s *Service) findSilenceByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Silence, error) {
+	body, err := s.silenceStore.FindEnt(ctx, tx, Entity{PK: EncID(id)})
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Op:   influxdb.OpFindSilenceByID,
+			Msg:  influxdb.ErrSilenceNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	sil, ok := body.(*influxdb.Silence)
+	return sil, IsErrUnexpectedDecodeVal(ok)
+}
+
+// FindSilences returns the silences matching the filter and their count.
+func (s *Service) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	sils := make([]*influxdb.Silence, 0)
+	err := s.kv.View(ctx, func(tx Tx) error {
+		return s.silenceStore.Find(ctx, tx, FindOpts{
+			Descending:  o.Descending,
+			Offset:      o.Offset,
+			Limit:       o.Limit,
+			FilterEntFn: filterSilencesFn(filter),
+			CaptureFn: func(k []byte, v interface{}) error {
+				sil, ok := v.(*influxdb.Silence)
+				if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+					return err
+				}
+				sils = append(sils, sil)
+				return nil
+			},
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return sils, len(sils), nil
+}
+
+func filterSilencesFn(filter influxdb.SilenceFilter) func([]byte, interface{}) bool {
+	return func(key []byte, val interface{}) bool {
+		sil, ok := val.(*influxdb.Silence)
+		if !ok {
+			return false
+		}
+		if filter.OrgID != nil && sil.OrgID != *filter.OrgID {
+			return false
+		}
+		if filter.ActiveAt != nil && !sil.Active(*filter.ActiveAt) {
+			return false
+		}
+		return true
+	}
+}
+
+// CreateSilence creates a new silence and assigns it an ID.
+func (s *Service) CreateSilence(ctx context.Context, sil *influxdb.Silence) error {
+	if err := sil.Valid(); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpCreateSilence,
+			Err:  err,
+		}
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, sil.OrgID); err != nil {
+			return err
+		}
+
+		sil.ID = s.IDGenerator.ID()
+		now := s.Now()
+		sil.CreatedAt = now
+		sil.UpdatedAt = now
+		return s.silenceStore.Put(ctx, tx, Entity{PK: EncID(sil.ID), Body: sil}, PutNew())
+	})
+}
+
+// UpdateSilence updates a single silence with a changeset.
+func (s *Service) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	var sil *influxdb.Silence
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		current, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(current)
+		if err := current.Valid(); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpUpdateSilence,
+				Err:  err,
+			}
+		}
+		current.UpdatedAt = s.Now()
+		sil = current
+		return s.silenceStore.Put(ctx, tx, Entity{PK: EncID(id), Body: sil}, PutUpdate())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sil, nil
+}
+
+// DeleteSilence removes a silence by its ID.
+func (s *Service) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findSilenceByID(ctx, tx, id); err != nil {
+			return err
+		}
+		return s.silenceStore.DeleteEnt(ctx, tx, Entity{PK: EncID(id)})
+	})
+}
@@ -0,0 +1,96 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_Silences(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	a := &influxdb.Silence{
+		OrgID: org.ID,
+		Matchers: []influxdb.TagRule{
+			{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal},
+		},
+		Start:   start,
+		End:     start.Add(time.Hour),
+		Comment: "deploy",
+	}
+	if err := svc.CreateSilence(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if !a.ID.Valid() || a.CreatedAt.IsZero() {
+		t.Fatalf("expected the silence to be assigned an ID and a creation time, got %+v", a)
+	}
+	b := &influxdb.Silence{OrgID: org.ID, Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour)}
+	if err := svc.CreateSilence(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.CreateSilence(ctx, &influxdb.Silence{OrgID: org.ID, Start: start, End: start}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error for a silence ending when it starts, got %v", err)
+	}
+	if err := svc.CreateSilence(ctx, &influxdb.Silence{OrgID: 10, Start: start, End: start.Add(time.Hour)}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a not found error for the silence of a missing organization, got %v", err)
+	}
+
+	got, err := svc.FindSilenceByID(ctx, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Comment != "deploy" || len(got.Matchers) != 1 || !got.End.Equal(a.End) {
+		t.Errorf("unexpected silence %+v", got)
+	}
+
+	activeAt := start.Add(30 * time.Minute)
+	sils, n, err := svc.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &org.ID, ActiveAt: &activeAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || sils[0].ID != a.ID {
+		t.Errorf("expected the silence active at %s only, got %d silences", activeAt, n)
+	}
+	if _, n, _ := svc.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &org.ID}); n != 2 {
+		t.Errorf("expected the 2 silences of the organization, got %d", n)
+	}
+
+	comment := "deploy of the api"
+	end := start.Add(90 * time.Minute)
+	upd, err := svc.UpdateSilence(ctx, a.ID, influxdb.SilenceUpdate{Comment: &comment, End: &end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upd.Comment != comment || !upd.End.Equal(end) || len(upd.Matchers) != 1 {
+		t.Errorf("unexpected updated silence %+v", upd)
+	}
+	before := start.Add(-time.Hour)
+	if _, err := svc.UpdateSilence(ctx, a.ID, influxdb.SilenceUpdate{End: &before}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error for an update ending the silence before it starts, got %v", err)
+	}
+
+	if err := svc.DeleteSilence(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FindSilenceByID(ctx, a.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a not found error for a deleted silence, got %v", err)
+	}
+	if err := svc.DeleteSilence(ctx, a.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a not found error deleting a deleted silence, got %v", err)
+	}
+}
@@ -24,6 +24,8 @@ import (
 //   <taskID>/latestCompleted: run data for the latest completed run of a task
 // taskIndexBucket
 //   <orgID>/<taskID>: index for tasks by org
+// taskVersionBucket
+//   <taskID>/<version>: revisions of the task script, see task_version.go
 
 // We may want to add a <taskName>/<taskID> index to allow us to look up tasks by task name.
 
@@ -48,14 +50,24 @@ type kvTask struct {
 	Flux            string                 `json:"flux"`
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
+	Location        string                 `json:"location,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
 	Offset          influxdb.Duration      `json:"offset,omitempty"`
+	After           []influxdb.ID          `json:"after,omitempty"`
+	Timeout         influxdb.Duration      `json:"timeout,omitempty"`
+	Retry           int64                  `json:"retry,omitempty"`
+	RetryDelay      influxdb.Duration      `json:"retryDelay,omitempty"`
+	RetryMaxDelay   influxdb.Duration      `json:"retryMaxDelay,omitempty"`
+	Version         int                    `json:"version,omitempty"`
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time              `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+
+	Notification        *influxdb.TaskNotification `json:"notification,omitempty"`
+	ConsecutiveFailures int                        `json:"consecutiveFailures,omitempty"`
 }
 
 func kvToInfluxTask(k *kvTask) *influxdb.Task {
@@ -71,14 +83,24 @@ func kvToInfluxTask(k *kvTask) *influxdb.Task {
 		Flux:            k.Flux,
 		Every:           k.Every,
 		Cron:            k.Cron,
+		Location:        k.Location,
 		LastRunStatus:   k.LastRunStatus,
 		LastRunError:    k.LastRunError,
 		Offset:          k.Offset.Duration,
+		After:           k.After,
+		Timeout:         k.Timeout.Duration,
+		Retry:           k.Retry,
+		RetryDelay:      k.RetryDelay.Duration,
+		RetryMaxDelay:   k.RetryMaxDelay.Duration,
+		Version:         k.Version,
 		LatestCompleted: k.LatestCompleted,
 		LatestScheduled: k.LatestScheduled,
 		CreatedAt:       k.CreatedAt,
 		UpdatedAt:       k.UpdatedAt,
 		Metadata:        k.Metadata,
+
+		Notification:        k.Notification,
+		ConsecutiveFailures: k.ConsecutiveFailures,
 	}
 }
 
@@ -92,6 +114,9 @@ func (s *Service) initializeTasks(ctx context.Context, tx Tx) error {
 	if _, err := tx.Bucket(taskIndexBucket); err != nil {
 		return err
 	}
+	if _, err := tx.Bucket(taskVersionBucket); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -617,6 +642,7 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		Flux:            tc.Flux,
 		Every:           opt.Every.String(),
 		Cron:            opt.Cron,
+		Location:        opt.Location,
 		CreatedAt:       createdAt,
 		LatestCompleted: createdAt,
 		LatestScheduled: createdAt,
@@ -631,6 +657,22 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 
 	}
 
+	if err := setTaskRunOptions(task, opt); err != nil {
+		return nil, err
+	}
+
+	if task.After, err = s.taskUpstreams(ctx, tx, task, opt.After); err != nil {
+		return nil, err
+	}
+
+	if task.Notification, err = s.taskNotification(ctx, tx, task, tc.Notification); err != nil {
+		return nil, err
+	}
+
+	if err := s.putTaskVersion(ctx, tx, task, opt, ""); err != nil {
+		return nil, err
+	}
+
 	taskBucket, err := tx.Bucket(taskBucket)
 	if err != nil {
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
@@ -714,6 +756,107 @@ func (s *Service) createTaskURM(ctx context.Context, tx Tx, t *influxdb.Task) er
 	})
 }
 
+// setTaskRunOptions sets the timeout and retry settings of the runs of task from opt.
+func setTaskRunOptions(task *influxdb.Task, opt options.Options) error {
+	// a single attempt is the default, the task only records retries that are enabled.
+	task.Retry = 0
+	if opt.Retry != nil && *opt.Retry > 1 {
+		task.Retry = *opt.Retry
+	}
+
+	for _, d := range []struct {
+		opt *options.Duration
+		dst *time.Duration
+	}{
+		{opt.Timeout, &task.Timeout},
+		{opt.RetryDelay, &task.RetryDelay},
+		{opt.RetryMaxDelay, &task.RetryMaxDelay},
+	} {
+		*d.dst = 0
+		if d.opt == nil {
+			continue
+		}
+		dur, err := d.opt.DurationFrom(time.Now())
+		if err != nil {
+			return influxdb.ErrTaskTimeParse(err)
+		}
+		*d.dst = dur
+	}
+	return nil
+}
+
+// taskUpstreams decodes the IDs of the upstream tasks of a task.
+// The upstream tasks must belong to the organization of the task and must not depend on it.
+func (s *Service) taskUpstreams(ctx context.Context, tx Tx, task *influxdb.Task, after []string) ([]influxdb.ID, error) {
+	if len(after) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]influxdb.ID, 0, len(after))
+	for _, a := range after {
+		var id influxdb.ID
+		if err := id.DecodeFromString(a); err != nil {
+			return nil, influxdb.ErrTaskOptionParse(err)
+		}
+		upstream, err := s.findTaskByID(ctx, tx, id)
+		if err == influxdb.ErrTaskNotFound || (err == nil && upstream.OrganizationID != task.OrganizationID) {
+			return nil, influxdb.ErrTaskUpstreamNotFound(id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	// walk the upstream tasks, none of them may lead back to the task.
+	seen := make(map[influxdb.ID]bool)
+	queue := append([]influxdb.ID{}, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == task.ID {
+			return nil, influxdb.ErrTaskDependencyCycle
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		upstream, err := s.findTaskByID(ctx, tx, id)
+		if err == influxdb.ErrTaskNotFound {
+			// a deleted upstream task does not depend on anything.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, upstream.After...)
+	}
+	return ids, nil
+}
+
+// taskNotification validates the notification of a task, whose endpoint must belong to the organization of the task.
+// A notification without endpoint is no notification.
+func (s *Service) taskNotification(ctx context.Context, tx Tx, task *influxdb.Task, n *influxdb.TaskNotification) (*influxdb.TaskNotification, error) {
+	if n == nil || !n.EndpointID.Valid() {
+		return nil, nil
+	}
+	if err := n.Valid(); err != nil {
+		return nil, err
+	}
+
+	edp, err := s.findNotificationEndpointByID(ctx, tx, n.EndpointID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound || (err == nil && edp.GetOrgID() != task.OrganizationID) {
+		return nil, influxdb.ErrTaskNotificationEndpointNotFound(n.EndpointID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cp := *n
+	return &cp, nil
+}
+
 // UpdateTask updates a single task with changeset.
 func (s *Service) UpdateTask(ctx context.Context, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
 	var t *influxdb.Task
@@ -746,6 +889,13 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		if err = upd.UpdateFlux(task.Flux); err != nil {
 			return nil, err
 		}
+		changed := task.Flux != *upd.Flux
+		if changed && task.Version == 0 {
+			// tasks created before their scripts were versioned keep their current script as a baseline version.
+			if err := s.putTaskBaselineVersion(ctx, tx, task); err != nil {
+				return nil, err
+			}
+		}
 		task.Flux = *upd.Flux
 
 		options, err := options.FromScript(*upd.Flux)
@@ -755,6 +905,7 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.Name = options.Name
 		task.Every = options.Every.String()
 		task.Cron = options.Cron
+		task.Location = options.Location
 
 		var off time.Duration
 		if options.Offset != nil {
@@ -764,6 +915,20 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 			}
 		}
 		task.Offset = off
+
+		if err := setTaskRunOptions(task, options); err != nil {
+			return nil, err
+		}
+
+		if task.After, err = s.taskUpstreams(ctx, tx, task, options.After); err != nil {
+			return nil, err
+		}
+
+		if changed {
+			if err := s.putTaskVersion(ctx, tx, task, options, upd.VersionDescription); err != nil {
+				return nil, err
+			}
+		}
 		task.UpdatedAt = updatedAt
 	}
 
@@ -789,6 +954,17 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.UpdatedAt = updatedAt
 	}
 
+	if upd.Notification != nil {
+		if task.Notification, err = s.taskNotification(ctx, tx, task, upd.Notification); err != nil {
+			return nil, err
+		}
+		task.UpdatedAt = updatedAt
+	}
+
+	if upd.ConsecutiveFailures != nil {
+		task.ConsecutiveFailures = *upd.ConsecutiveFailures
+	}
+
 	if upd.LatestCompleted != nil {
 		// make sure we only update latest completed one way
 		tlc := task.LatestCompleted
@@ -935,6 +1111,10 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
+	if err := s.deleteTaskVersions(ctx, tx, task.ID); err != nil {
+		return err
+	}
+
 	if err := s.deleteUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
 		ResourceID: task.ID,
 	}); err != nil {
@@ -1288,6 +1468,11 @@ func (s *Service) CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFo
 	return r, err
 }
 func (s *Service) createRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error) {
+	version, err := s.runTaskVersion(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
 	id := s.IDGenerator.ID()
 	t := time.Unix(scheduledFor.Unix(), 0).UTC()
 
@@ -1297,28 +1482,93 @@ func (s *Service) createRun(ctx context.Context, tx Tx, taskID influxdb.ID, sche
 		ScheduledFor: t,
 		RunAt:        runAt,
 		Status:       backend.RunScheduled.String(),
+		TaskVersion:  version,
+		Log:          []influxdb.Log{},
+	}
+
+	if err := s.putRun(ctx, tx, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// CreateRetryRun creates a run that retries the failed run at runAt.
+func (s *Service) CreateRetryRun(ctx context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error) {
+	var r *influxdb.Run
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		run, err := s.createRetryRun(ctx, tx, run, runAt)
+		if err != nil {
+			return err
+		}
+		r = run
+		return nil
+	})
+	return r, err
+}
+
+func (s *Service) createRetryRun(ctx context.Context, tx Tx, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error) {
+	version, err := s.runTaskVersion(ctx, tx, run.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	retryOf := run.RetryOf
+	if !retryOf.Valid() {
+		retryOf = run.ID
+	}
+
+	r := influxdb.Run{
+		ID:           s.IDGenerator.ID(),
+		TaskID:       run.TaskID,
+		ScheduledFor: run.ScheduledFor,
+		RunAt:        runAt,
+		RequestedAt:  run.RequestedAt,
+		Status:       backend.RunScheduled.String(),
+		RetryOf:      retryOf,
+		Attempt:      run.Attempt + 1,
+		TaskVersion:  version,
 		Log:          []influxdb.Log{},
 	}
 
+	if err := s.putRun(ctx, tx, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// runTaskVersion returns the version of the script of a task recorded by its runs.
+// The runs of a task that doesn't exist record no version.
+func (s *Service) runTaskVersion(ctx context.Context, tx Tx, taskID influxdb.ID) (int, error) {
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err == influxdb.ErrTaskNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return task.Version, nil
+}
+
+// putRun stores a run in the list of currently running runs of its task.
+func (s *Service) putRun(ctx context.Context, tx Tx, run *influxdb.Run) error {
 	b, err := tx.Bucket(taskRunBucket)
 	if err != nil {
-		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
 	runBytes, err := json.Marshal(run)
 	if err != nil {
-		return nil, influxdb.ErrInternalTaskServiceError(err)
+		return influxdb.ErrInternalTaskServiceError(err)
 	}
 
-	runKey, err := taskRunKey(taskID, run.ID)
+	runKey, err := taskRunKey(run.TaskID, run.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if err := b.Put(runKey, runBytes); err != nil {
-		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
-
-	return &run, nil
+	return nil
 }
 
 func (s *Service) CurrentlyRunning(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error) {
@@ -1455,6 +1705,10 @@ func (s *Service) startManualRun(ctx context.Context, tx Tx, taskID, runID influ
 		return nil, influxdb.ErrRunNotFound
 	}
 
+	if run.TaskVersion, err = s.runTaskVersion(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+
 	// save manual runs
 	mRunsBytes, err := json.Marshal(mRuns)
 	if err != nil {
@@ -1514,11 +1768,28 @@ func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 		return nil, err
 	}
 
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// count the failed runs since the last successful run, a failed run that is retried counts once its last attempt failed.
+	failures := task.ConsecutiveFailures
+	r.ConsecutiveFailures = failures
+	switch {
+	case r.Status == backend.RunSuccess.String():
+		failures = 0
+	case r.Status == backend.RunFail.String() && int64(r.Attempt+1) >= task.Retry:
+		failures++
+		r.ConsecutiveFailures = failures
+	}
+
 	// tell task to update latest completed
 	scheduled := r.ScheduledFor
 	_, err = s.updateTask(ctx, tx, taskID, influxdb.TaskUpdate{
-		LatestCompleted: &scheduled,
-		LastRunStatus:   &r.Status,
+		LatestCompleted:     &scheduled,
+		ConsecutiveFailures: &failures,
+		LastRunStatus:       &r.Status,
 		LastRunError: func() *string {
 			if r.Status == "failed" {
 				// prefer the second to last log message as the error message
@@ -1576,7 +1847,7 @@ func (s *Service) updateRunState(ctx context.Context, tx Tx, taskID, runID influ
 	switch state {
 	case backend.RunStarted:
 		run.StartedAt = when
-	case backend.RunSuccess, backend.RunFail, backend.RunCanceled:
+	case backend.RunSuccess, backend.RunFail, backend.RunCanceled, backend.RunBlocked:
 		run.FinishedAt = when
 	}
 
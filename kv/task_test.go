@@ -12,6 +12,7 @@ import (
 	"github.com/influxdata/influxdb"
 	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/notification/endpoint"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	"github.com/influxdata/influxdb/task/backend"
 	"github.com/influxdata/influxdb/task/servicetest"
@@ -239,6 +240,156 @@ func TestService_UpdateTask_InactiveToActive(t *testing.T) {
 	}
 }
 
+func TestService_TaskVersions(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	v1 := `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`
+	task, err := ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           v1,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	if err != nil {
+		t.Fatal("CreateTask", err)
+	}
+	if task.Version != 1 {
+		t.Fatalf("expected a new task to be at version 1, got %d", task.Version)
+	}
+
+	// changes that leave the script as is are not versioned.
+	desc := "a description"
+	if _, err := ts.Service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Description: &desc}); err != nil {
+		t.Fatal("UpdateTask", err)
+	}
+
+	v2 := `option task = {name: "a task",every: 2h} from(bucket:"test") |> range(start:-2h)`
+	task, err = ts.Service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Flux: &v2, VersionDescription: "run less often"})
+	if err != nil {
+		t.Fatal("UpdateTask", err)
+	}
+	if task.Version != 2 {
+		t.Fatalf("expected the updated task to be at version 2, got %d", task.Version)
+	}
+
+	vs, err := ts.Service.FindTaskVersions(ctx, task.ID)
+	if err != nil {
+		t.Fatal("FindTaskVersions", err)
+	}
+	if len(vs) != 2 || vs[0].Version != 2 || vs[1].Version != 1 {
+		t.Fatalf("expected versions 2 and 1, got %+v", vs)
+	}
+	if vs[0].Flux != v2 || vs[0].Description != "run less often" || vs[0].AuthorID != ts.User.ID || vs[0].Options.Every.String() != "2h" {
+		t.Fatalf("unexpected version 2: %+v", vs[0])
+	}
+
+	v, err := ts.Service.FindTaskVersion(ctx, task.ID, 1)
+	if err != nil {
+		t.Fatal("FindTaskVersion", err)
+	}
+	if v.Flux != v1 {
+		t.Fatalf("unexpected script of version 1 -got/+exp\n%s", cmp.Diff(v.Flux, v1))
+	}
+	if _, err := ts.Service.FindTaskVersion(ctx, task.ID, 3); err != influxdb.ErrTaskVersionNotFound {
+		t.Fatalf("expected version 3 not to be found, got %v", err)
+	}
+
+	run, err := ts.Service.CreateRun(ctx, task.ID, time.Unix(3600, 0), time.Unix(3600, 0))
+	if err != nil {
+		t.Fatal("CreateRun", err)
+	}
+	if run.TaskVersion != 2 {
+		t.Fatalf("expected the run to record version 2, got %d", run.TaskVersion)
+	}
+
+	if err := ts.Service.DeleteTask(ctx, task.ID); err != nil {
+		t.Fatal("DeleteTask", err)
+	}
+	if _, err := ts.Service.FindTaskVersion(ctx, task.ID, 1); err != influxdb.ErrTaskVersionNotFound {
+		t.Fatalf("expected the versions of a deleted task to be removed, got %v", err)
+	}
+}
+
+func TestService_TaskVersions_Unversioned(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	v1 := `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`
+	task, err := ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           v1,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	if err != nil {
+		t.Fatal("CreateTask", err)
+	}
+
+	// store the task as it was before its script was versioned.
+	err = ts.Store.Update(ctx, func(tx kv.Tx) error {
+		key, err := task.ID.Encode()
+		if err != nil {
+			return err
+		}
+		b, err := tx.Bucket([]byte("tasksv1"))
+		if err != nil {
+			return err
+		}
+		task.Version = 0
+		v, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+		if err := b.Put(key, v); err != nil {
+			return err
+		}
+
+		vb, err := tx.Bucket([]byte("taskVersionsv1"))
+		if err != nil {
+			return err
+		}
+		return vb.Delete(append(append(key, '/'), 0, 0, 0, 0, 0, 0, 0, 1))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vs, err := ts.Service.FindTaskVersions(ctx, task.ID); err != nil || len(vs) != 0 {
+		t.Fatalf("expected the task to have no versions, got %+v, %v", vs, err)
+	}
+
+	v2 := `option task = {name: "a task",every: 2h} from(bucket:"test") |> range(start:-2h)`
+	task, err = ts.Service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Flux: &v2})
+	if err != nil {
+		t.Fatal("UpdateTask", err)
+	}
+	if task.Version != 2 {
+		t.Fatalf("expected the updated task to be at version 2, got %d", task.Version)
+	}
+
+	vs, err := ts.Service.FindTaskVersions(ctx, task.ID)
+	if err != nil {
+		t.Fatal("FindTaskVersions", err)
+	}
+	if len(vs) != 2 || vs[0].Version != 2 || vs[1].Version != 1 {
+		t.Fatalf("expected versions 2 and 1, got %+v", vs)
+	}
+	if vs[0].Flux != v2 {
+		t.Fatalf("unexpected script of version 2 -got/+exp\n%s", cmp.Diff(vs[0].Flux, v2))
+	}
+	if vs[1].Flux != v1 || vs[1].Options.Every.String() != "1h" {
+		t.Fatalf("expected the script before the update as the baseline version, got %+v", vs[1])
+	}
+}
+
 func TestTaskRunCancellation(t *testing.T) {
 	store, close, err := NewTestBoltStore(t)
 	if err != nil {
@@ -308,3 +459,92 @@ func TestTaskRunCancellation(t *testing.T) {
 		t.Fatalf("expected task run to be cancelled")
 	}
 }
+
+func TestService_TaskNotification(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	orgID := ts.Org.ID
+	edp := &endpoint.Slack{
+		Base: endpoint.Base{Name: "slack", OrgID: &orgID, Status: influxdb.Active},
+		URL:  "http://localhost:9999/slack",
+	}
+	if err := ts.Service.CreateNotificationEndpoint(ctx, edp, ts.User.ID); err != nil {
+		t.Fatal("CreateNotificationEndpoint", err)
+	}
+
+	tc := influxdb.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h, retry: 2} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+		Notification:   &influxdb.TaskNotification{EndpointID: edp.GetID() + 1, Policy: influxdb.TaskNotifyFirst},
+	}
+	if _, err := ts.Service.CreateTask(ctx, tc); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected a task notifying a missing endpoint to be invalid, got %v", err)
+	}
+
+	tc.Notification.EndpointID = edp.GetID()
+	task, err := ts.Service.CreateTask(ctx, tc)
+	if err != nil {
+		t.Fatal("CreateTask", err)
+	}
+	if task.Notification == nil || *task.Notification != *tc.Notification {
+		t.Fatalf("unexpected notification %+v", task.Notification)
+	}
+
+	finish := func(r *influxdb.Run, rs backend.RunStatus) {
+		t.Helper()
+		if err := ts.Service.UpdateRunState(ctx, task.ID, r.ID, time.Now(), rs); err != nil {
+			t.Fatal("UpdateRunState", err)
+		}
+		if _, err := ts.Service.FinishRun(ctx, task.ID, r.ID); err != nil {
+			t.Fatal("FinishRun", err)
+		}
+	}
+	expectFailures := func(n int) {
+		t.Helper()
+		task, err := ts.Service.FindTaskByID(ctx, task.ID)
+		if err != nil {
+			t.Fatal("FindTaskByID", err)
+		}
+		if task.ConsecutiveFailures != n {
+			t.Fatalf("expected %d consecutive failures, got %d", n, task.ConsecutiveFailures)
+		}
+	}
+
+	// a failed run only counts once its last attempt failed.
+	run, err := ts.Service.CreateRun(ctx, task.ID, time.Unix(3600, 0), time.Unix(3600, 0))
+	if err != nil {
+		t.Fatal("CreateRun", err)
+	}
+	finish(run, backend.RunFail)
+	expectFailures(0)
+
+	retry, err := ts.Service.CreateRetryRun(ctx, run, time.Unix(3700, 0))
+	if err != nil {
+		t.Fatal("CreateRetryRun", err)
+	}
+	finish(retry, backend.RunFail)
+	expectFailures(1)
+
+	run, err = ts.Service.CreateRun(ctx, task.ID, time.Unix(7200, 0), time.Unix(7200, 0))
+	if err != nil {
+		t.Fatal("CreateRun", err)
+	}
+	finish(run, backend.RunSuccess)
+	expectFailures(0)
+
+	// a notification without endpoint removes the notification.
+	task, err = ts.Service.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{Notification: &influxdb.TaskNotification{}})
+	if err != nil {
+		t.Fatal("UpdateTask", err)
+	}
+	if task.Notification != nil {
+		t.Fatalf("expected the notification to be removed, got %+v", task.Notification)
+	}
+}
@@ -0,0 +1,208 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+// Task Version Storage Schema
+// taskVersionBucket:
+//   <taskID>/<version>: task version data storage, the version is big endian encoded
+
+var (
+	taskVersionBucket = []byte("taskVersionsv1")
+)
+
+var _ influxdb.TaskVersionService = (*Service)(nil)
+
+// putTaskVersion records the current script of task as its next version.
+func (s *Service) putTaskVersion(ctx context.Context, tx Tx, task *influxdb.Task, opt options.Options, description string) error {
+	task.Version++
+
+	uid, _ := icontext.GetUserID(ctx)
+	v := &influxdb.TaskVersion{
+		TaskID:      task.ID,
+		Version:     task.Version,
+		Flux:        task.Flux,
+		Options:     opt,
+		AuthorID:    uid,
+		Description: description,
+		CreatedAt:   s.clock.Now().UTC(),
+	}
+
+	b, err := tx.Bucket(taskVersionBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskVersionKey(v.TaskID, v.Version)
+	if err != nil {
+		return err
+	}
+
+	vBytes, err := json.Marshal(v)
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	if err := b.Put(key, vBytes); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
+// putTaskBaselineVersion records the current script of a task that has no versions as its first version.
+func (s *Service) putTaskBaselineVersion(ctx context.Context, tx Tx, task *influxdb.Task) error {
+	opt, err := options.FromScript(task.Flux)
+	if err != nil {
+		return influxdb.ErrTaskOptionParse(err)
+	}
+	return s.putTaskVersion(ctx, tx, task, opt, "")
+}
+
+// FindTaskVersions returns the versions of a task, the most recent first.
+func (s *Service) FindTaskVersions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	var vs []*influxdb.TaskVersion
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.findTaskByID(ctx, tx, taskID); err != nil {
+			return err
+		}
+
+		v, err := s.findTaskVersions(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		vs = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vs, nil
+}
+
+func (s *Service) findTaskVersions(ctx context.Context, tx Tx, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	b, err := tx.Bucket(taskVersionBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	prefix, err := taskVersionPrefix(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := b.Cursor(WithCursorHintPrefix(string(prefix)))
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	vs := []*influxdb.TaskVersion{}
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		tv := &influxdb.TaskVersion{}
+		if err := json.Unmarshal(v, tv); err != nil {
+			return nil, influxdb.ErrInternalTaskServiceError(err)
+		}
+		vs = append(vs, tv)
+	}
+
+	// the keys are in ascending version order.
+	for i, j := 0, len(vs)-1; i < j; i, j = i+1, j-1 {
+		vs[i], vs[j] = vs[j], vs[i]
+	}
+	return vs, nil
+}
+
+// FindTaskVersion returns a single version of a task.
+func (s *Service) FindTaskVersion(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	var tv *influxdb.TaskVersion
+	err := s.kv.View(ctx, func(tx Tx) error {
+		v, err := s.findTaskVersion(ctx, tx, taskID, version)
+		if err != nil {
+			return err
+		}
+		tv = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tv, nil
+}
+
+func (s *Service) findTaskVersion(ctx context.Context, tx Tx, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	b, err := tx.Bucket(taskVersionBucket)
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskVersionKey(taskID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, influxdb.ErrTaskVersionNotFound
+	}
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	tv := &influxdb.TaskVersion{}
+	if err := json.Unmarshal(v, tv); err != nil {
+		return nil, influxdb.ErrInternalTaskServiceError(err)
+	}
+	return tv, nil
+}
+
+// deleteTaskVersions removes all the versions of a task.
+func (s *Service) deleteTaskVersions(ctx context.Context, tx Tx, taskID influxdb.ID) error {
+	vs, err := s.findTaskVersions(ctx, tx, taskID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(taskVersionBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	for _, v := range vs {
+		key, err := taskVersionKey(taskID, v.Version)
+		if err != nil {
+			return err
+		}
+		if err := b.Delete(key); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+	}
+	return nil
+}
+
+func taskVersionPrefix(taskID influxdb.ID) ([]byte, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, influxdb.ErrInvalidTaskID
+	}
+	return []byte(string(encodedID) + "/"), nil
+}
+
+func taskVersionKey(taskID influxdb.ID, version int) ([]byte, error) {
+	prefix, err := taskVersionPrefix(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], uint64(version))
+	return append(prefix, v[:]...), nil
+}
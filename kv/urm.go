@@ -214,7 +214,7 @@ func (s *Service) createOrgDependentMappings(ctx context.Context, tx Tx, m *infl
 		// TODO(desa): add support for all other resource types.
 	}
 
-	return nil
+	return s.assignBuiltInRole(ctx, tx, m)
 }
 
 func userResourceKey(m *influxdb.UserResourceMapping) ([]byte, error) {
@@ -399,7 +399,7 @@ func (s *Service) deleteOrgDependentMappings(ctx context.Context, tx Tx, m *infl
 		// TODO(desa): add support for all other resource types.
 	}
 
-	return nil
+	return s.unassignBuiltInRole(ctx, tx, m)
 }
 
 func (s *Service) addResourceOwner(ctx context.Context, tx Tx, rt influxdb.ResourceType, id influxdb.ID) error {
@@ -375,6 +375,10 @@ func (s *Service) updateUser(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		u.Status = *upd.Status
 	}
 
+	if upd.OAuthID != nil {
+		u.OAuthID = *upd.OAuthID
+	}
+
 	if err := s.appendUserEventToLog(ctx, tx, u.ID, userUpdatedEvent); err != nil {
 		return nil, err
 	}
@@ -446,6 +450,10 @@ func (s *Service) deleteUser(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return err
 	}
 
+	if err := s.deleteUserRoleAssignments(ctx, tx, id); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.AlertService = (*AlertService)(nil)
+
+// AlertService is a mock implementation of influxdb.AlertService.
+type AlertService struct {
+	FindAlertByIDFn    func(context.Context, influxdb.ID) (*influxdb.Alert, error)
+	FindAlertsFn       func(context.Context, influxdb.AlertFilter, ...influxdb.FindOptions) ([]*influxdb.Alert, int, error)
+	AcknowledgeAlertFn func(context.Context, influxdb.ID, influxdb.ID, string) (*influxdb.Alert, error)
+	ResolveAlertFn     func(context.Context, influxdb.ID, influxdb.ID, string) (*influxdb.Alert, error)
+}
+
+// NewAlertService returns a mock AlertService where its methods will return
+// zero values.
+func NewAlertService() *AlertService {
+	return &AlertService{
+		FindAlertByIDFn: func(context.Context, influxdb.ID) (*influxdb.Alert, error) { return nil, nil },
+		FindAlertsFn: func(context.Context, influxdb.AlertFilter, ...influxdb.FindOptions) ([]*influxdb.Alert, int, error) {
+			return nil, 0, nil
+		},
+		AcknowledgeAlertFn: func(context.Context, influxdb.ID, influxdb.ID, string) (*influxdb.Alert, error) { return nil, nil },
+		ResolveAlertFn:     func(context.Context, influxdb.ID, influxdb.ID, string) (*influxdb.Alert, error) { return nil, nil },
+	}
+}
+
+// FindAlertByID finds a single alert by its ID.
+func (s *AlertService) FindAlertByID(ctx context.Context, id influxdb.ID) (*influxdb.Alert, error) {
+	return s.FindAlertByIDFn(ctx, id)
+}
+
+// FindAlerts returns the alerts matching the filter and their count.
+func (s *AlertService) FindAlerts(ctx context.Context, filter influxdb.AlertFilter, opt ...influxdb.FindOptions) ([]*influxdb.Alert, int, error) {
+	return s.FindAlertsFn(ctx, filter, opt...)
+}
+
+// AcknowledgeAlert records that the user handles the open alert.
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, id, userID influxdb.ID, comment string) (*influxdb.Alert, error) {
+	return s.AcknowledgeAlertFn(ctx, id, userID, comment)
+}
+
+// ResolveAlert resolves the alert on behalf of the user.
+func (s *AlertService) ResolveAlert(ctx context.Context, id, userID influxdb.ID, comment string) (*influxdb.Alert, error) {
+	return s.ResolveAlertFn(ctx, id, userID, comment)
+}
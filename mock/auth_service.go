@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 )
@@ -20,6 +21,9 @@ type AuthorizationService struct {
 	CreateAuthorizationFn      func(context.Context, *platform.Authorization) error
 	DeleteAuthorizationFn      func(context.Context, platform.ID) error
 	UpdateAuthorizationFn      func(context.Context, platform.ID, *platform.AuthorizationUpdate) (*platform.Authorization, error)
+
+	// Methods for an platform.AuthorizationRotator
+	RotateAuthorizationFn func(context.Context, platform.ID, time.Duration) (*platform.Authorization, error)
 }
 
 // NewAuthorizationService returns a mock AuthorizationService where its methods will return
@@ -36,6 +40,9 @@ func NewAuthorizationService() *AuthorizationService {
 		UpdateAuthorizationFn: func(context.Context, platform.ID, *platform.AuthorizationUpdate) (*platform.Authorization, error) {
 			return nil, nil
 		},
+		RotateAuthorizationFn: func(context.Context, platform.ID, time.Duration) (*platform.Authorization, error) {
+			return nil, nil
+		},
 	}
 }
 
@@ -67,3 +74,8 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id platform.ID, upd *platform.AuthorizationUpdate) (*platform.Authorization, error) {
 	return s.UpdateAuthorizationFn(ctx, id, upd)
 }
+
+// RotateAuthorization creates an authorization with a new token and the permissions of the authorization.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, grace time.Duration) (*platform.Authorization, error) {
+	return s.RotateAuthorizationFn(ctx, id, grace)
+}
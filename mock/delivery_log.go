@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.DeliveryLog = (*DeliveryLog)(nil)
+
+// DeliveryLog is a mock implementation of influxdb.DeliveryLog.
+type DeliveryLog struct {
+	FindDeliveryAttemptsFn func(context.Context, influxdb.DeliveryAttemptFilter) ([]*influxdb.DeliveryAttempt, int, error)
+}
+
+// NewDeliveryLog returns a mock DeliveryLog where its methods will return
+// zero values.
+func NewDeliveryLog() *DeliveryLog {
+	return &DeliveryLog{
+		FindDeliveryAttemptsFn: func(context.Context, influxdb.DeliveryAttemptFilter) ([]*influxdb.DeliveryAttempt, int, error) {
+			return nil, 0, nil
+		},
+	}
+}
+
+// FindDeliveryAttempts returns the delivery attempts matching the filter and their count.
+func (s *DeliveryLog) FindDeliveryAttempts(ctx context.Context, filter influxdb.DeliveryAttemptFilter) ([]*influxdb.DeliveryAttempt, int, error) {
+	return s.FindDeliveryAttemptsFn(ctx, filter)
+}
@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.MonitorEvaluationService = (*MonitorEvaluationService)(nil)
+
+// MonitorEvaluationService is a mock implementation of influxdb.MonitorEvaluationService.
+type MonitorEvaluationService struct {
+	EvaluateMonitorFn func(context.Context, influxdb.MonitorEvaluationRequest) (*influxdb.MonitorEvaluation, error)
+}
+
+// NewMonitorEvaluationService returns a mock MonitorEvaluationService where its methods will return
+// zero values.
+func NewMonitorEvaluationService() *MonitorEvaluationService {
+	return &MonitorEvaluationService{
+		EvaluateMonitorFn: func(context.Context, influxdb.MonitorEvaluationRequest) (*influxdb.MonitorEvaluation, error) {
+			return nil, nil
+		},
+	}
+}
+
+// EvaluateMonitor evaluates the script of a check or of a notification rule over a historical range.
+func (s *MonitorEvaluationService) EvaluateMonitor(ctx context.Context, req influxdb.MonitorEvaluationRequest) (*influxdb.MonitorEvaluation, error) {
+	return s.EvaluateMonitorFn(ctx, req)
+}
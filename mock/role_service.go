@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+// RoleService is a mock implementation of influxdb.RoleService.
+type RoleService struct {
+	FindRoleByIDFn        func(context.Context, influxdb.ID) (*influxdb.Role, error)
+	FindRolesFn           func(context.Context, influxdb.RoleFilter, ...influxdb.FindOptions) ([]*influxdb.Role, int, error)
+	CreateRoleFn          func(context.Context, *influxdb.Role) error
+	UpdateRoleFn          func(context.Context, influxdb.ID, influxdb.RoleUpdate) (*influxdb.Role, error)
+	DeleteRoleFn          func(context.Context, influxdb.ID) error
+	FindRoleAssignmentsFn func(context.Context, influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error)
+	AssignRoleFn          func(context.Context, *influxdb.RoleAssignment) error
+	UnassignRoleFn        func(context.Context, influxdb.ID, influxdb.ID) error
+}
+
+// NewRoleService returns a mock RoleService where its methods will return
+// zero values.
+func NewRoleService() *RoleService {
+	return &RoleService{
+		FindRoleByIDFn: func(context.Context, influxdb.ID) (*influxdb.Role, error) { return nil, nil },
+		FindRolesFn: func(context.Context, influxdb.RoleFilter, ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+			return nil, 0, nil
+		},
+		CreateRoleFn: func(context.Context, *influxdb.Role) error { return nil },
+		UpdateRoleFn: func(context.Context, influxdb.ID, influxdb.RoleUpdate) (*influxdb.Role, error) {
+			return nil, nil
+		},
+		DeleteRoleFn: func(context.Context, influxdb.ID) error { return nil },
+		FindRoleAssignmentsFn: func(context.Context, influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+			return nil, 0, nil
+		},
+		AssignRoleFn:   func(context.Context, *influxdb.RoleAssignment) error { return nil },
+		UnassignRoleFn: func(context.Context, influxdb.ID, influxdb.ID) error { return nil },
+	}
+}
+
+// FindRoleByID finds a single role by its ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	return s.FindRoleByIDFn(ctx, id)
+}
+
+// FindRoles returns the roles matching the filter and their count.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	return s.FindRolesFn(ctx, filter, opt...)
+}
+
+// CreateRole creates a new role and assigns it an ID.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	return s.CreateRoleFn(ctx, r)
+}
+
+// UpdateRole updates a single role with a changeset.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	return s.UpdateRoleFn(ctx, id, upd)
+}
+
+// DeleteRole removes a role by its ID along with its assignments.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteRoleFn(ctx, id)
+}
+
+// FindRoleAssignments returns the role assignments matching the filter and their count.
+func (s *RoleService) FindRoleAssignments(ctx context.Context, filter influxdb.RoleAssignmentFilter) ([]*influxdb.RoleAssignment, int, error) {
+	return s.FindRoleAssignmentsFn(ctx, filter)
+}
+
+// AssignRole assigns a role to a user.
+func (s *RoleService) AssignRole(ctx context.Context, a *influxdb.RoleAssignment) error {
+	return s.AssignRoleFn(ctx, a)
+}
+
+// UnassignRole removes the assignment of a role to a user.
+func (s *RoleService) UnassignRole(ctx context.Context, roleID, userID influxdb.ID) error {
+	return s.UnassignRoleFn(ctx, roleID, userID)
+}
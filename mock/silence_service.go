@@ -0,0 +1,59 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// SilenceService is a mock implementation of influxdb.SilenceService.
+type SilenceService struct {
+	FindSilenceByIDFn func(context.Context, influxdb.ID) (*influxdb.Silence, error)
+	FindSilencesFn    func(context.Context, influxdb.SilenceFilter, ...influxdb.FindOptions) ([]*influxdb.Silence, int, error)
+	CreateSilenceFn   func(context.Context, *influxdb.Silence) error
+	UpdateSilenceFn   func(context.Context, influxdb.ID, influxdb.SilenceUpdate) (*influxdb.Silence, error)
+	DeleteSilenceFn   func(context.Context, influxdb.ID) error
+}
+
+// NewSilenceService returns a mock SilenceService where its methods will return
+// zero values.
+func NewSilenceService() *SilenceService {
+	return &SilenceService{
+		FindSilenceByIDFn: func(context.Context, influxdb.ID) (*influxdb.Silence, error) { return nil, nil },
+		FindSilencesFn: func(context.Context, influxdb.SilenceFilter, ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+			return nil, 0, nil
+		},
+		CreateSilenceFn: func(context.Context, *influxdb.Silence) error { return nil },
+		UpdateSilenceFn: func(context.Context, influxdb.ID, influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+			return nil, nil
+		},
+		DeleteSilenceFn: func(context.Context, influxdb.ID) error { return nil },
+	}
+}
+
+// FindSilenceByID finds a single silence by its ID.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	return s.FindSilenceByIDFn(ctx, id)
+}
+
+// FindSilences returns the silences matching the filter and their count.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+	return s.FindSilencesFn(ctx, filter, opt...)
+}
+
+// CreateSilence creates a new silence and assigns it an ID.
+func (s *SilenceService) CreateSilence(ctx context.Context, sil *influxdb.Silence) error {
+	return s.CreateSilenceFn(ctx, sil)
+}
+
+// UpdateSilence updates a single silence with a changeset.
+func (s *SilenceService) UpdateSilence(ctx context.Context, id influxdb.ID, upd influxdb.SilenceUpdate) (*influxdb.Silence, error) {
+	return s.UpdateSilenceFn(ctx, id, upd)
+}
+
+// DeleteSilence removes a silence by its ID.
+func (s *SilenceService) DeleteSilence(ctx context.Context, id influxdb.ID) error {
+	return s.DeleteSilenceFn(ctx, id)
+}
@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskBackfillService = (*TaskBackfillService)(nil)
+
+// TaskBackfillService is a mock implementation of influxdb.TaskBackfillService.
+type TaskBackfillService struct {
+	CreateBackfillFn   func(context.Context, influxdb.BackfillCreate) (*influxdb.Backfill, error)
+	FindBackfillByIDFn func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Backfill, error)
+	FindBackfillsFn    func(context.Context, influxdb.ID) ([]*influxdb.Backfill, error)
+	CancelBackfillFn   func(context.Context, influxdb.ID, influxdb.ID) error
+}
+
+// NewTaskBackfillService returns a mock TaskBackfillService where its methods will return
+// zero values.
+func NewTaskBackfillService() *TaskBackfillService {
+	return &TaskBackfillService{
+		CreateBackfillFn: func(context.Context, influxdb.BackfillCreate) (*influxdb.Backfill, error) {
+			return nil, nil
+		},
+		FindBackfillByIDFn: func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Backfill, error) {
+			return nil, nil
+		},
+		FindBackfillsFn: func(context.Context, influxdb.ID) ([]*influxdb.Backfill, error) {
+			return nil, nil
+		},
+		CancelBackfillFn: func(context.Context, influxdb.ID, influxdb.ID) error {
+			return nil
+		},
+	}
+}
+
+// CreateBackfill starts executing the runs of a task over a historical range.
+func (s *TaskBackfillService) CreateBackfill(ctx context.Context, b influxdb.BackfillCreate) (*influxdb.Backfill, error) {
+	return s.CreateBackfillFn(ctx, b)
+}
+
+// FindBackfillByID returns the progress of a single backfill.
+func (s *TaskBackfillService) FindBackfillByID(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	return s.FindBackfillByIDFn(ctx, taskID, id)
+}
+
+// FindBackfills returns the backfills of a task.
+func (s *TaskBackfillService) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	return s.FindBackfillsFn(ctx, taskID)
+}
+
+// CancelBackfill stops a backfill, canceling the runs it is executing.
+func (s *TaskBackfillService) CancelBackfill(ctx context.Context, taskID, id influxdb.ID) error {
+	return s.CancelBackfillFn(ctx, taskID, id)
+}
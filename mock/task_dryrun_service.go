@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskDryRunService = (*TaskDryRunService)(nil)
+
+// TaskDryRunService is a mock implementation of influxdb.TaskDryRunService.
+type TaskDryRunService struct {
+	DryRunTaskFn func(context.Context, influxdb.TaskDryRunRequest) (*influxdb.TaskDryRun, error)
+}
+
+// NewTaskDryRunService returns a mock TaskDryRunService where its methods will return
+// zero values.
+func NewTaskDryRunService() *TaskDryRunService {
+	return &TaskDryRunService{
+		DryRunTaskFn: func(context.Context, influxdb.TaskDryRunRequest) (*influxdb.TaskDryRun, error) {
+			return nil, nil
+		},
+	}
+}
+
+// DryRunTask executes a task script once without side effects.
+func (s *TaskDryRunService) DryRunTask(ctx context.Context, req influxdb.TaskDryRunRequest) (*influxdb.TaskDryRun, error) {
+	return s.DryRunTaskFn(ctx, req)
+}
@@ -131,6 +131,7 @@ func (s *TaskService) ForceRun(ctx context.Context, taskID influxdb.ID, schedule
 
 type TaskControlService struct {
 	CreateRunFn        func(ctx context.Context, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error)
+	CreateRetryRunFn   func(ctx context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error)
 	CurrentlyRunningFn func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error)
 	ManualRunsFn       func(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error)
 	StartManualRunFn   func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error)
@@ -142,6 +143,9 @@ type TaskControlService struct {
 func (tcs *TaskControlService) CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error) {
 	return tcs.CreateRunFn(ctx, taskID, scheduledFor, runAt)
 }
+func (tcs *TaskControlService) CreateRetryRun(ctx context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error) {
+	return tcs.CreateRetryRunFn(ctx, run, runAt)
+}
 func (tcs *TaskControlService) CurrentlyRunning(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error) {
 	return tcs.CurrentlyRunningFn(ctx, taskID)
 }
@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskVersionService = (*TaskVersionService)(nil)
+
+// TaskVersionService is a mock implementation of influxdb.TaskVersionService.
+type TaskVersionService struct {
+	FindTaskVersionsFn func(context.Context, influxdb.ID) ([]*influxdb.TaskVersion, error)
+	FindTaskVersionFn  func(context.Context, influxdb.ID, int) (*influxdb.TaskVersion, error)
+}
+
+// NewTaskVersionService returns a mock TaskVersionService where its methods will return
+// zero values.
+func NewTaskVersionService() *TaskVersionService {
+	return &TaskVersionService{
+		FindTaskVersionsFn: func(context.Context, influxdb.ID) ([]*influxdb.TaskVersion, error) {
+			return nil, nil
+		},
+		FindTaskVersionFn: func(context.Context, influxdb.ID, int) (*influxdb.TaskVersion, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindTaskVersions returns the versions of a task, the most recent first.
+func (s *TaskVersionService) FindTaskVersions(ctx context.Context, taskID influxdb.ID) ([]*influxdb.TaskVersion, error) {
+	return s.FindTaskVersionsFn(ctx, taskID)
+}
+
+// FindTaskVersion returns a single version of a task.
+func (s *TaskVersionService) FindTaskVersion(ctx context.Context, taskID influxdb.ID, version int) (*influxdb.TaskVersion, error) {
+	return s.FindTaskVersionFn(ctx, taskID, version)
+}
@@ -0,0 +1,77 @@
+package influxdb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// MaxMonitorEvaluations is the maximum number of times a monitor evaluation executes a script.
+	MaxMonitorEvaluations = 1000
+	// MaxMonitorEvaluationPoints is the maximum number of points of all the series of a monitor evaluation.
+	MaxMonitorEvaluationPoints = 10000
+)
+
+// MonitorEvaluationRequest is a request to evaluate the script of a check or
+// of a notification rule over a historical range.
+type MonitorEvaluationRequest struct {
+	Flux           string
+	OrganizationID ID
+	ResourceType   ResourceType // ResourceType is the type of the resource whose script is evaluated
+	Start          time.Time
+	Stop           time.Time
+}
+
+// Validate returns an error if the evaluation cannot be executed.
+func (r MonitorEvaluationRequest) Validate() error {
+	switch {
+	case r.Flux == "":
+		return errors.New("missing flux")
+	case !r.OrganizationID.Valid():
+		return errors.New("missing orgID")
+	case r.ResourceType != ChecksResourceType && r.ResourceType != NotificationRuleResourceType:
+		return errors.New("only checks and notification rules can be evaluated")
+	case r.Start.IsZero() || r.Stop.IsZero():
+		return errors.New("missing start or stop")
+	case !r.Start.Before(r.Stop):
+		return errors.New("start must be before stop")
+	}
+	return nil
+}
+
+// MonitorEvaluation is the outcome of the evaluation of the script of a check or of
+// a notification rule. The script is executed for each time its task would have been
+// scheduled for in the range, and the statuses it would have written, or the
+// notifications it would have sent, are returned instead.
+type MonitorEvaluation struct {
+	Start       time.Time       `json:"start"`
+	Stop        time.Time       `json:"stop"`
+	Every       string          `json:"every"`
+	Evaluations int             `json:"evaluations"` // Evaluations is the number of times the script was executed
+	Series      []MonitorSeries `json:"series"`
+	Truncated   bool            `json:"truncated,omitempty"` // Truncated is set when points beyond MaxMonitorEvaluationPoints were dropped
+	Error       string          `json:"error,omitempty"`
+}
+
+// MonitorSeries is the timeline of the statuses or of the notifications of a series.
+type MonitorSeries struct {
+	Tags   map[string]string `json:"tags"`
+	Points []MonitorPoint    `json:"points"`
+}
+
+// MonitorPoint is a status, or a notification of a status, of a series.
+type MonitorPoint struct {
+	ScheduledFor time.Time `json:"scheduledFor"`
+	Time         time.Time `json:"time"` // Time is the time of the status, or of the status notified
+	Level        string    `json:"level"`
+	Message      string    `json:"message,omitempty"`
+}
+
+// MonitorEvaluationService evaluates the scripts of checks and notification rules
+// over historical ranges without side effects.
+type MonitorEvaluationService interface {
+	// EvaluateMonitor executes a script at the cadence of its task over the range
+	// of the request with the authorization on ctx.
+	EvaluateMonitor(ctx context.Context, req MonitorEvaluationRequest) (*MonitorEvaluation, error)
+}
@@ -29,8 +29,10 @@ type NotificationRule interface {
 	GetTaskID() ID
 	GetEndpointID() ID
 	GetLimit() *Limit
+	GetEscalation() *Escalation
 	GenerateFlux(NotificationEndpoint) (string, error)
 	MatchesTags(tags []Tag) bool
+	MatchesLevel(level string) bool
 }
 
 // NotificationRuleStore represents a service for managing notification rule.
@@ -71,6 +73,31 @@ type Limit struct {
 	Every int `json:"limitEvery,omitempty"`
 }
 
+// Escalation escalates the alerts the notification rule notifies of once they stay
+// unacknowledged for Timeout after they opened. The alerts are escalated to the secondary
+// endpoint EndpointID, or notified again to the endpoint of the rule when it is not set.
+type Escalation struct {
+	Timeout    Duration `json:"timeout"`
+	EndpointID *ID      `json:"endpointID,omitempty"`
+}
+
+// Valid returns an error if the escalation has no timeout or an invalid endpoint.
+func (e Escalation) Valid() error {
+	if e.Timeout.Duration <= 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "escalation timeout must be positive",
+		}
+	}
+	if e.EndpointID != nil && !e.EndpointID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "escalation endpoint ID is invalid",
+		}
+	}
+	return nil
+}
+
 // NotificationRuleFilter represents a set of filter that restrict the returned notification rules.
 type NotificationRuleFilter struct {
 	OrgID        *ID
@@ -0,0 +1,145 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+var _ influxdb.Check = (*Absence)(nil)
+
+// Absence is the absence check, which sets the level of the statuses of the
+// series that reported in the previous interval but not in the interval checked.
+// Unlike the deadman check, series are compared with the set of series seen
+// the interval before, so a series going missing is reported once per series.
+type Absence struct {
+	Base
+	Level notification.CheckLevel `json:"level"`
+}
+
+// Type returns the type of the check.
+func (c Absence) Type() string {
+	return "absence"
+}
+
+// Valid returns error if something is invalid.
+func (c Absence) Valid() error {
+	if err := c.Base.Valid(); err != nil {
+		return err
+	}
+	switch c.Level {
+	case notification.Info, notification.Warn, notification.Critical:
+	default:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("absence check level %s is invalid", c.Level),
+		}
+	}
+	return nil
+}
+
+// GenerateFlux returns a flux script for the Absence provided.
+func (c Absence) GenerateFlux() (string, error) {
+	p, err := c.GenerateFluxAST()
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the absence provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Absence) GenerateFluxAST() (*ast.Package, error) {
+	p := parser.ParseSource(c.Query.Text)
+	removeAggregateWindow(p)
+	replaceDurationsWithEvery(p, c.Every)
+	removeStopFromRange(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	// the query reads the previous interval as well, which is the set of
+	// series the series of the interval checked are compared with.
+	replaceRangeStart(p, durationLiteral(2*c.Every.TimeDuration()))
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor", "experimental", "influxdata/influxdb/v1")...)
+	f.Body = append(f.Body, c.generateFluxASTBody()...)
+
+	return p, nil
+}
+
+func (c Absence) generateFluxASTBody() []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("absence"))
+	statements = append(statements, c.generateLevelFn())
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	return append(statements, c.generateFluxASTChecksFunction())
+}
+
+func (c Absence) generateLevelFn() ast.Statement {
+	fn := flux.Function(flux.FunctionParams("r"), flux.Member("r", "absent"))
+
+	lvl := strings.ToLower(c.Level.String())
+
+	return flux.DefineVariable(lvl, fn)
+}
+
+func (c Absence) generateFluxASTChecksFunction() ast.Statement {
+	every := ast.DurationLiteral(*c.Every)
+	now := flux.Call(flux.Identifier("now"), flux.Object())
+	start := flux.Call(flux.Member("experimental", "subDuration"), flux.Object(flux.Property("from", now), flux.Property("d", &every)))
+
+	// the last value of each series is absent from the interval checked
+	// when it is before the start of the interval.
+	absent := flux.ObjectWith("r", flux.Property("absent", flux.LessThan(flux.Member("r", "_time"), start)))
+	return flux.ExpressionStatement(flux.Pipe(
+		flux.Identifier("data"),
+		flux.Call(flux.Member("v1", "fieldsAsCols"), flux.Object()),
+		flux.Call(flux.Identifier("max"), flux.Object(flux.Property("column", flux.String("_time")))),
+		flux.Call(flux.Identifier("map"), flux.Object(flux.Property("fn", flux.Function(flux.FunctionParams("r"), absent)))),
+		c.generateFluxASTChecksCall(),
+	))
+}
+
+func (c Absence) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	lvl := strings.ToLower(c.Level.String())
+	objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+type absenceAlias Absence
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Absence) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			absenceAlias
+			Type string `json:"type"`
+		}{
+			absenceAlias: absenceAlias(c),
+			Type:         c.Type(),
+		})
+}
@@ -0,0 +1,65 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestAbsence_GenerateFlux(t *testing.T) {
+	absence := check.Absence{
+		Base: check.Base{
+			ID:   10,
+			Name: "moo",
+			Tags: []influxdb.Tag{
+				{Key: "aaa", Value: "vaaa"},
+			},
+			Every:                 mustDuration("5m"),
+			StatusMessageTemplate: "${r.host} stopped reporting",
+			Query: influxdb.DashboardQuery{
+				Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._measurement == "cpu") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+			},
+		},
+		Level: notification.Critical,
+	}
+
+	script := `package main
+import "influxdata/influxdb/monitor"
+import "experimental"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -10m)
+	|> filter(fn: (r) =>
+		(r._measurement == "cpu"))
+
+option task = {name: "moo", every: 5m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "absence",
+	tags: {aaa: "vaaa"},
+}
+crit = (r) =>
+	(r.absent)
+messageFn = (r) =>
+	("${r.host} stopped reporting")
+
+data
+	|> v1.fieldsAsCols()
+	|> max(column: "_time")
+	|> map(fn: (r) =>
+		({r with absent: r._time < experimental.subDuration(from: now(), d: 5m)}))
+	|> monitor.check(data: check, messageFn: messageFn, crit: crit)`
+
+	s, err := absence.GenerateFlux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != script {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", script, s)
+	}
+}
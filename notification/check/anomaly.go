@@ -0,0 +1,262 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+var _ influxdb.Check = (*Anomaly)(nil)
+
+// Methods computing the band of an anomaly check.
+const (
+	// AnomalyStddev is the band of the mean ± k standard deviations.
+	AnomalyStddev = "stddev"
+	// AnomalyMAD is the band of the median ± k median absolute deviations.
+	AnomalyMAD = "mad"
+)
+
+// week is the offset of the baseline of anomaly checks comparing with last week.
+const week = 7 * 24 * time.Hour
+
+// Anomaly is the anomaly check, which sets the level of the statuses of values
+// outside of a band of deviations around the values of a baseline window.
+type Anomaly struct {
+	Base
+	// Window is the duration of the baseline the band is computed from,
+	// which ends where the values checked every interval start.
+	Window *notification.Duration `json:"window,omitempty"`
+	// Method is the method computing the band, stddev or mad.
+	Method string `json:"method"`
+	// Deviations is the number of deviations between the center and the bounds of the band.
+	Deviations float64 `json:"deviations"`
+	// LastWeek moves the baseline a week back, so values are compared with
+	// the values of the same time last week.
+	LastWeek bool                    `json:"lastWeek"`
+	Level    notification.CheckLevel `json:"level"`
+}
+
+// Type returns the type of the check.
+func (c Anomaly) Type() string {
+	return "anomaly"
+}
+
+// Valid returns error if something is invalid.
+func (c Anomaly) Valid() error {
+	if err := c.Base.Valid(); err != nil {
+		return err
+	}
+	return c.ValidBand()
+}
+
+// ValidBand returns an error if the band configuration of the check is invalid.
+func (c Anomaly) ValidBand() error {
+	if c.Window == nil || c.Window.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "anomaly check window must be a positive duration",
+		}
+	}
+	if c.Method != AnomalyStddev && c.Method != AnomalyMAD {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("anomaly check method must be %s or %s", AnomalyStddev, AnomalyMAD),
+		}
+	}
+	if c.Deviations <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "anomaly check deviations must be positive",
+		}
+	}
+	switch c.Level {
+	case notification.Info, notification.Warn, notification.Critical:
+	default:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("anomaly check level %s is invalid", c.Level),
+		}
+	}
+	return nil
+}
+
+// GenerateFlux returns a flux script for the Anomaly provided.
+func (c Anomaly) GenerateFlux() (string, error) {
+	p, err := c.GenerateFluxAST()
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the anomaly provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Anomaly) GenerateFluxAST() (*ast.Package, error) {
+	if err := c.ValidBand(); err != nil {
+		return nil, err
+	}
+
+	p := parser.ParseSource(c.Query.Text)
+	replaceDurationsWithEvery(p, c.Every)
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	fields := getFields(p)
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("expected a single field but got: %s", fields)
+	}
+
+	// the query reads the baseline as well as the values checked.
+	replaceRangeStart(p, durationLiteral(c.baselineStart()))
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor", "influxdata/influxdb/anomaly", "influxdata/influxdb/v1")...)
+	f.Body = append(f.Body, c.generateFluxASTBody(fields[0])...)
+
+	return p, nil
+}
+
+// baselineStart returns how long before now the baseline starts.
+func (c Anomaly) baselineStart() time.Duration {
+	return c.baselineStop() + c.Window.TimeDuration()
+}
+
+// baselineStop returns how long before now the baseline stops.
+func (c Anomaly) baselineStop() time.Duration {
+	if c.LastWeek {
+		return week
+	}
+	return c.Every.TimeDuration()
+}
+
+var durationUnits = []struct {
+	unit string
+	d    time.Duration
+}{
+	{"w", week},
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+	{"us", time.Microsecond},
+	{"ns", time.Nanosecond},
+}
+
+// durationLiteral returns the duration literal of d in the largest units, 1w1h rather than 169h.
+func durationLiteral(d time.Duration) *ast.DurationLiteral {
+	lit := &ast.DurationLiteral{}
+	for _, u := range durationUnits {
+		if n := d / u.d; n > 0 {
+			lit.Values = append(lit.Values, ast.Duration{Magnitude: int64(n), Unit: u.unit})
+			d -= n * u.d
+		}
+	}
+	return lit
+}
+
+// replaceRangeStart sets the start of the range of the query.
+func replaceRangeStart(pkg *ast.Package, start *ast.DurationLiteral) {
+	ast.Visit(pkg, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpression); ok {
+			if id, ok := call.Callee.(*ast.Identifier); ok && id.Name == "range" {
+				for _, args := range call.Arguments {
+					if obj, ok := args.(*ast.ObjectExpression); ok {
+						for _, prop := range obj.Properties {
+							if prop.Key.Key() == "start" {
+								prop.Value = flux.Negative(start)
+							}
+						}
+					}
+				}
+			}
+		}
+	})
+}
+
+func (c Anomaly) generateFluxASTBody(field string) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("anomaly"))
+	statements = append(statements, c.generateLevelFn(field))
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	return append(statements, c.generateFluxASTChecksFunction(field))
+}
+
+func (c Anomaly) generateLevelFn(field string) ast.Statement {
+	fnBody := flux.Or(
+		flux.LessThan(flux.Member("r", field), flux.Member("r", "_lower")),
+		flux.GreaterThan(flux.Member("r", field), flux.Member("r", "_upper")),
+	)
+	fn := flux.Function(flux.FunctionParams("r"), fnBody)
+
+	lvl := strings.ToLower(c.Level.String())
+
+	return flux.DefineVariable(lvl, fn)
+}
+
+func (c Anomaly) generateFluxASTBandCall(field string) *ast.CallExpression {
+	start := ast.DurationLiteral(*c.Every)
+	return flux.Call(flux.Member("anomaly", "band"), flux.Object(
+		flux.Property("column", flux.String(field)),
+		flux.Property("method", flux.String(c.Method)),
+		flux.Property("k", flux.Float(c.Deviations)),
+		flux.Property("start", flux.Negative(&start)),
+		flux.Property("baselineStart", flux.Negative(durationLiteral(c.baselineStart()))),
+		flux.Property("baselineStop", flux.Negative(durationLiteral(c.baselineStop()))),
+	))
+}
+
+func (c Anomaly) generateFluxASTChecksFunction(field string) ast.Statement {
+	return flux.ExpressionStatement(flux.Pipe(
+		flux.Identifier("data"),
+		flux.Call(flux.Member("v1", "fieldsAsCols"), flux.Object()),
+		c.generateFluxASTBandCall(field),
+		c.generateFluxASTChecksCall(),
+	))
+}
+
+func (c Anomaly) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	lvl := strings.ToLower(c.Level.String())
+	objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+type anomalyAlias Anomaly
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Anomaly) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			anomalyAlias
+			Type string `json:"type"`
+		}{
+			anomalyAlias: anomalyAlias(c),
+			Type:         c.Type(),
+		})
+}
@@ -0,0 +1,134 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestAnomaly_GenerateFlux(t *testing.T) {
+	base := check.Base{
+		ID:   10,
+		Name: "moo",
+		Tags: []influxdb.Tag{
+			{Key: "aaa", Value: "vaaa"},
+		},
+		Every:                 mustDuration("5m"),
+		StatusMessageTemplate: "whoa! {r.usage_user}",
+		Query: influxdb.DashboardQuery{
+			Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "usage_user") |> aggregateWindow(every: 1m, fn: mean) |> yield()`,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		anomaly check.Anomaly
+		script  string
+	}{
+		{
+			name: "stddev of the previous window",
+			anomaly: check.Anomaly{
+				Base:       base,
+				Window:     mustDuration("1h"),
+				Method:     check.AnomalyStddev,
+				Deviations: 3,
+				Level:      notification.Critical,
+			},
+			script: `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/anomaly"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -1h5m)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 5m, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 5m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa"},
+}
+crit = (r) =>
+	(r.usage_user < r._lower or r.usage_user > r._upper)
+messageFn = (r) =>
+	("whoa! {r.usage_user}")
+
+data
+	|> v1.fieldsAsCols()
+	|> anomaly.band(
+		column: "usage_user",
+		method: "stddev",
+		k: 3.0,
+		start: -5m,
+		baselineStart: -1h5m,
+		baselineStop: -5m,
+	)
+	|> monitor.check(data: check, messageFn: messageFn, crit: crit)`,
+		},
+		{
+			name: "mad of last week",
+			anomaly: check.Anomaly{
+				Base:       base,
+				Window:     mustDuration("30m"),
+				Method:     check.AnomalyMAD,
+				Deviations: 2.5,
+				LastWeek:   true,
+				Level:      notification.Warn,
+			},
+			script: `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/anomaly"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -1w30m)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+	|> aggregateWindow(every: 5m, fn: mean, createEmpty: false)
+
+option task = {name: "moo", every: 5m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "anomaly",
+	tags: {aaa: "vaaa"},
+}
+warn = (r) =>
+	(r.usage_user < r._lower or r.usage_user > r._upper)
+messageFn = (r) =>
+	("whoa! {r.usage_user}")
+
+data
+	|> v1.fieldsAsCols()
+	|> anomaly.band(
+		column: "usage_user",
+		method: "mad",
+		k: 2.5,
+		start: -5m,
+		baselineStart: -1w30m,
+		baselineStop: -1w,
+	)
+	|> monitor.check(data: check, messageFn: messageFn, warn: warn)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.anomaly.GenerateFlux()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != tt.script {
+				t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", tt.script, s)
+			}
+		})
+	}
+}
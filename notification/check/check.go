@@ -205,6 +205,9 @@ var typeToCheck = map[string](func() influxdb.Check){
 	"deadman":   func() influxdb.Check { return &Deadman{} },
 	"threshold": func() influxdb.Check { return &Threshold{} },
 	"custom":    func() influxdb.Check { return &Custom{} },
+	"anomaly":   func() influxdb.Check { return &Anomaly{} },
+	"rate":      func() influxdb.Check { return &Rate{} },
+	"absence":   func() influxdb.Check { return &Absence{} },
 }
 
 // UnmarshalJSON will convert
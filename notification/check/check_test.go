@@ -154,6 +154,112 @@ func TestValidCheck(t *testing.T) {
 				Msg:  "range threshold min can't be larger than max",
 			},
 		},
+		{
+			name: "anomaly without window",
+			src: &check.Anomaly{
+				Base:       goodBase,
+				Method:     check.AnomalyStddev,
+				Deviations: 3,
+				Level:      notification.Critical,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly check window must be a positive duration",
+			},
+		},
+		{
+			name: "anomaly with bad method",
+			src: &check.Anomaly{
+				Base:       goodBase,
+				Window:     mustDuration("1h"),
+				Method:     "mean",
+				Deviations: 3,
+				Level:      notification.Critical,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly check method must be stddev or mad",
+			},
+		},
+		{
+			name: "anomaly without deviations",
+			src: &check.Anomaly{
+				Base:   goodBase,
+				Window: mustDuration("1h"),
+				Method: check.AnomalyMAD,
+				Level:  notification.Critical,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly check deviations must be positive",
+			},
+		},
+		{
+			name: "anomaly with ok level",
+			src: &check.Anomaly{
+				Base:       goodBase,
+				Window:     mustDuration("1h"),
+				Method:     check.AnomalyMAD,
+				Deviations: 3,
+				Level:      notification.Ok,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "anomaly check level OK is invalid",
+			},
+		},
+		{
+			name: "good anomaly",
+			src: &check.Anomaly{
+				Base:       goodBase,
+				Window:     mustDuration("1h"),
+				Method:     check.AnomalyMAD,
+				Deviations: 3,
+				Level:      notification.Warn,
+			},
+		},
+		{
+			name: "rate with zero unit",
+			src: &check.Rate{
+				Base: goodBase,
+				Unit: mustDuration("0s"),
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "rate check unit must be a positive duration",
+			},
+		},
+		{
+			name: "rate with bad threshold",
+			src: &check.Rate{
+				Base: goodBase,
+				Thresholds: []check.ThresholdConfig{
+					&check.Range{Min: 200, Max: 100},
+				},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "range threshold min can't be larger than max",
+			},
+		},
+		{
+			name: "absence with ok level",
+			src: &check.Absence{
+				Base:  goodBase,
+				Level: notification.Ok,
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "absence check level OK is invalid",
+			},
+		},
+		{
+			name: "good absence",
+			src: &check.Absence{
+				Base:  goodBase,
+				Level: notification.Critical,
+			},
+		},
 	}
 	for _, c := range cases {
 		got := c.src.Valid()
@@ -262,6 +368,120 @@ func TestJSON(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "simple anomaly",
+			src: &check.Anomaly{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("5m"),
+					Query: influxdb.DashboardQuery{
+						BuilderConfig: influxdb.BuilderConfig{
+							Buckets: []string{},
+							Tags: []struct {
+								Key    string   `json:"key"`
+								Values []string `json:"values"`
+							}{},
+							Functions: []struct {
+								Name string `json:"name"`
+							}{},
+						},
+					},
+					Tags: []influxdb.Tag{
+						{
+							Key:   "k1",
+							Value: "v1",
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Window:     mustDuration("2h"),
+				Method:     check.AnomalyMAD,
+				Deviations: 2.5,
+				LastWeek:   true,
+				Level:      notification.Critical,
+			},
+		},
+		{
+			name: "simple rate",
+			src: &check.Rate{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("1m"),
+					Query: influxdb.DashboardQuery{
+						BuilderConfig: influxdb.BuilderConfig{
+							Buckets: []string{},
+							Tags: []struct {
+								Key    string   `json:"key"`
+								Values []string `json:"values"`
+							}{},
+							Functions: []struct {
+								Name string `json:"name"`
+							}{},
+						},
+					},
+					Tags: []influxdb.Tag{
+						{
+							Key:   "k1",
+							Value: "v1",
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Unit:        mustDuration("1m"),
+				NonNegative: true,
+				Thresholds: []check.ThresholdConfig{
+					&check.Greater{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Critical}, Value: 1000},
+					&check.Range{ThresholdConfigBase: check.ThresholdConfigBase{Level: notification.Warn}, Min: -10, Max: 10, Within: true},
+				},
+			},
+		},
+		{
+			name: "simple absence",
+			src: &check.Absence{
+				Base: check.Base{
+					ID:      influxTesting.MustIDBase16(id1),
+					Name:    "name1",
+					OwnerID: influxTesting.MustIDBase16(id2),
+					OrgID:   influxTesting.MustIDBase16(id3),
+					Every:   mustDuration("5m"),
+					Query: influxdb.DashboardQuery{
+						BuilderConfig: influxdb.BuilderConfig{
+							Buckets: []string{},
+							Tags: []struct {
+								Key    string   `json:"key"`
+								Values []string `json:"values"`
+							}{},
+							Functions: []struct {
+								Name string `json:"name"`
+							}{},
+						},
+					},
+					Tags: []influxdb.Tag{
+						{
+							Key:   "k1",
+							Value: "v1",
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Level: notification.Warn,
+			},
+		},
 	}
 	for _, c := range cases {
 		fn := func(t *testing.T) {
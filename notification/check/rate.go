@@ -0,0 +1,177 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+var _ influxdb.Check = (*Rate)(nil)
+
+// Rate is the rate of change check, which compares the derivative of the
+// values of a field, like the per second rate of a counter, with thresholds.
+type Rate struct {
+	Base
+	// Unit is the duration the rate of change is computed per, a second if unset.
+	Unit *notification.Duration `json:"unit,omitempty"`
+	// NonNegative drops negative rates, so counter resets are not compared with the thresholds.
+	NonNegative bool              `json:"nonNegative"`
+	Thresholds  []ThresholdConfig `json:"thresholds"`
+}
+
+// Type returns the type of the check.
+func (c Rate) Type() string {
+	return "rate"
+}
+
+// Valid returns error if something is invalid.
+func (c Rate) Valid() error {
+	if err := c.Base.Valid(); err != nil {
+		return err
+	}
+	if c.Unit != nil && c.Unit.TimeDuration() <= 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "rate check unit must be a positive duration",
+		}
+	}
+	for _, cc := range c.Thresholds {
+		if err := cc.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type rateDecode struct {
+	Base
+	Unit        *notification.Duration  `json:"unit,omitempty"`
+	NonNegative bool                    `json:"nonNegative"`
+	Thresholds  []thresholdConfigDecode `json:"thresholds"`
+}
+
+// UnmarshalJSON implement json.Unmarshaler interface.
+func (c *Rate) UnmarshalJSON(b []byte) error {
+	raw := new(rateDecode)
+	if err := json.Unmarshal(b, raw); err != nil {
+		return err
+	}
+	thresholds, err := decodeThresholdConfigs(raw.Thresholds)
+	if err != nil {
+		return err
+	}
+	c.Base = raw.Base
+	c.Unit = raw.Unit
+	c.NonNegative = raw.NonNegative
+	c.Thresholds = thresholds
+	return nil
+}
+
+// GenerateFlux returns a flux script for the Rate provided.
+func (c Rate) GenerateFlux() (string, error) {
+	p, err := c.GenerateFluxAST()
+	if err != nil {
+		return "", err
+	}
+
+	return ast.Format(p), nil
+}
+
+// GenerateFluxAST returns a flux AST for the rate provided. If there
+// are any errors in the flux that the user provided the function will return
+// an error for each error found when the script is parsed.
+func (c Rate) GenerateFluxAST() (*ast.Package, error) {
+	p := parser.ParseSource(c.Query.Text)
+	replaceDurationsWithEvery(p, c.Every)
+	removeStopFromRange(p)
+	addCreateEmptyFalseToAggregateWindow(p)
+
+	if errs := ast.GetErrors(p); len(errs) != 0 {
+		return nil, multiError(errs)
+	}
+
+	// TODO(desa): this is a hack that we had to do as a result of https://github.com/influxdata/flux/issues/1701
+	// when it is fixed we should use a separate file and not manipulate the existing one.
+	if len(p.Files) != 1 {
+		return nil, fmt.Errorf("expect a single file to be returned from query parsing got %d", len(p.Files))
+	}
+
+	fields := getFields(p)
+	if len(fields) != 1 {
+		return nil, fmt.Errorf("expected a single field but got: %s", fields)
+	}
+
+	// the query reads the previous interval as well, so the first value
+	// of the interval checked has a value to compute its rate from.
+	replaceRangeStart(p, durationLiteral(2*c.Every.TimeDuration()))
+
+	f := p.Files[0]
+	assignPipelineToData(f)
+
+	f.Imports = append(f.Imports, flux.Imports("influxdata/influxdb/monitor", "influxdata/influxdb/v1")...)
+	f.Body = append(f.Body, c.generateFluxASTBody(fields[0])...)
+
+	return p, nil
+}
+
+func (c Rate) generateFluxASTBody(field string) []ast.Statement {
+	var statements []ast.Statement
+	statements = append(statements, c.generateTaskOption())
+	statements = append(statements, c.generateFluxASTCheckDefinition("rate"))
+	for _, td := range c.Thresholds {
+		statements = append(statements, td.generateFluxASTThresholdFunction(field))
+	}
+	statements = append(statements, c.generateFluxASTMessageFunction())
+	return append(statements, c.generateFluxASTChecksFunction(field))
+}
+
+func (c Rate) generateFluxASTChecksFunction(field string) ast.Statement {
+	unit := durationLiteral(time.Second)
+	if c.Unit != nil {
+		unit = durationLiteral(c.Unit.TimeDuration())
+	}
+	return flux.ExpressionStatement(flux.Pipe(
+		flux.Identifier("data"),
+		flux.Call(flux.Member("v1", "fieldsAsCols"), flux.Object()),
+		flux.Call(flux.Identifier("derivative"), flux.Object(
+			flux.Property("unit", unit),
+			flux.Property("nonNegative", flux.Bool(c.NonNegative)),
+			flux.Property("columns", flux.Array(flux.String(field))),
+		)),
+		c.generateFluxASTChecksCall(),
+	))
+}
+
+func (c Rate) generateFluxASTChecksCall() *ast.CallExpression {
+	objectProps := append(([]*ast.Property)(nil), flux.Property("data", flux.Identifier("check")))
+	objectProps = append(objectProps, flux.Property("messageFn", flux.Identifier("messageFn")))
+
+	// This assumes that the ThresholdConfigs we've been provided do not have duplicates.
+	for _, td := range c.Thresholds {
+		lvl := strings.ToLower(td.GetLevel().String())
+		objectProps = append(objectProps, flux.Property(lvl, flux.Identifier(lvl)))
+	}
+
+	return flux.Call(flux.Member("monitor", "check"), flux.Object(objectProps...))
+}
+
+type rateAlias Rate
+
+// MarshalJSON implement json.Marshaler interface.
+func (c Rate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			rateAlias
+			Type string `json:"type"`
+		}{
+			rateAlias: rateAlias(c),
+			Type:      c.Type(),
+		})
+}
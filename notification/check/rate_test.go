@@ -0,0 +1,140 @@
+package check_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/check"
+)
+
+func TestRate_GenerateFlux(t *testing.T) {
+	base := check.Base{
+		ID:   10,
+		Name: "moo",
+		Tags: []influxdb.Tag{
+			{Key: "aaa", Value: "vaaa"},
+		},
+		Every:                 mustDuration("1m"),
+		StatusMessageTemplate: "whoa! {r.bytes_recv}",
+		Query: influxdb.DashboardQuery{
+			Text: `from(bucket: "foo") |> range(start: -1d, stop: now()) |> filter(fn: (r) => r._field == "bytes_recv") |> aggregateWindow(every: 1m, fn: last) |> yield()`,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		rate   check.Rate
+		script string
+	}{
+		{
+			name: "per second",
+			rate: check.Rate{
+				Base:        base,
+				NonNegative: true,
+				Thresholds: []check.ThresholdConfig{
+					check.Greater{
+						ThresholdConfigBase: check.ThresholdConfigBase{
+							Level: notification.Critical,
+						},
+						Value: 1000,
+					},
+				},
+			},
+			script: `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -2m)
+	|> filter(fn: (r) =>
+		(r._field == "bytes_recv"))
+	|> aggregateWindow(every: 1m, fn: last, createEmpty: false)
+
+option task = {name: "moo", every: 1m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "rate",
+	tags: {aaa: "vaaa"},
+}
+crit = (r) =>
+	(r.bytes_recv > 1000.0)
+messageFn = (r) =>
+	("whoa! {r.bytes_recv}")
+
+data
+	|> v1.fieldsAsCols()
+	|> derivative(unit: 1s, nonNegative: true, columns: ["bytes_recv"])
+	|> monitor.check(data: check, messageFn: messageFn, crit: crit)`,
+		},
+		{
+			name: "per minute",
+			rate: check.Rate{
+				Base: base,
+				Unit: mustDuration("1m"),
+				Thresholds: []check.ThresholdConfig{
+					check.Lesser{
+						ThresholdConfigBase: check.ThresholdConfigBase{
+							Level: notification.Warn,
+						},
+						Value: 0,
+					},
+					check.Greater{
+						ThresholdConfigBase: check.ThresholdConfigBase{
+							Level: notification.Critical,
+						},
+						Value: 60000,
+					},
+				},
+			},
+			script: `package main
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/v1"
+
+data = from(bucket: "foo")
+	|> range(start: -2m)
+	|> filter(fn: (r) =>
+		(r._field == "bytes_recv"))
+	|> aggregateWindow(every: 1m, fn: last, createEmpty: false)
+
+option task = {name: "moo", every: 1m}
+
+check = {
+	_check_id: "000000000000000a",
+	_check_name: "moo",
+	_type: "rate",
+	tags: {aaa: "vaaa"},
+}
+warn = (r) =>
+	(r.bytes_recv < 0.0)
+crit = (r) =>
+	(r.bytes_recv > 60000.0)
+messageFn = (r) =>
+	("whoa! {r.bytes_recv}")
+
+data
+	|> v1.fieldsAsCols()
+	|> derivative(unit: 1m, nonNegative: false, columns: ["bytes_recv"])
+	|> monitor.check(
+		data: check,
+		messageFn: messageFn,
+		warn: warn,
+		crit: crit,
+	)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.rate.GenerateFlux()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != tt.script {
+				t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", tt.script, s)
+			}
+		})
+	}
+}
@@ -59,20 +59,30 @@ func (t *Threshold) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	t.Base = tdRaws.Base
-	for _, tdRaw := range tdRaws.Thresholds {
+	thresholds, err := decodeThresholdConfigs(tdRaws.Thresholds)
+	if err != nil {
+		return err
+	}
+	t.Thresholds = thresholds
+	return nil
+}
+
+func decodeThresholdConfigs(tdRaws []thresholdConfigDecode) ([]ThresholdConfig, error) {
+	var thresholds []ThresholdConfig
+	for _, tdRaw := range tdRaws {
 		switch tdRaw.Type {
 		case "lesser":
 			td := &Lesser{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
 				Value:               tdRaw.Value,
 			}
-			t.Thresholds = append(t.Thresholds, td)
+			thresholds = append(thresholds, td)
 		case "greater":
 			td := &Greater{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
 				Value:               tdRaw.Value,
 			}
-			t.Thresholds = append(t.Thresholds, td)
+			thresholds = append(thresholds, td)
 		case "range":
 			td := &Range{
 				ThresholdConfigBase: tdRaw.ThresholdConfigBase,
@@ -80,15 +90,14 @@ func (t *Threshold) UnmarshalJSON(b []byte) error {
 				Max:                 tdRaw.Max,
 				Within:              tdRaw.Within,
 			}
-			t.Thresholds = append(t.Thresholds, td)
+			thresholds = append(thresholds, td)
 		default:
-			return &influxdb.Error{
+			return nil, &influxdb.Error{
 				Msg: fmt.Sprintf("invalid threshold type %s", tdRaw.Type),
 			}
 		}
 	}
-
-	return nil
+	return thresholds, nil
 }
 
 func multiError(errs []error) error {
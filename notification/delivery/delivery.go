@@ -0,0 +1,514 @@
+// Package delivery delivers the notifications of notification rules to their endpoints,
+// keeps a log of the delivery attempts and retries the failed deliveries.
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	influxlogger "github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+var (
+	_ influxdb.NotificationDeliverer = (*Service)(nil)
+	_ influxdb.DeliveryLog           = (*Service)(nil)
+)
+
+const (
+	// DefaultInterval is the interval the queued deliveries are retried at.
+	DefaultInterval = 10 * time.Second
+	// DefaultBaseBackoff is the time the first retry of a delivery waits for.
+	DefaultBaseBackoff = 30 * time.Second
+	// DefaultMaxBackoff is the longest time a retry of a delivery waits for.
+	DefaultMaxBackoff = time.Hour
+	// DefaultMaxAttempts is the number of attempts to deliver a notification before it is dropped.
+	DefaultMaxAttempts = 10
+	// DefaultBatchSize is the number of queued deliveries retried at once.
+	DefaultBatchSize = 100
+	// DefaultTimeout is the time an attempt is given to deliver a notification.
+	DefaultTimeout = 30 * time.Second
+)
+
+// names of the tags and fields of the delivery attempts in the _monitoring bucket.
+const (
+	ruleIDTag         = "ruleID"
+	endpointIDTag     = "endpointID"
+	statusTag         = "status"
+	attemptField      = "attempt"
+	statusCodeField   = "statusCode"
+	latencyField      = "latency"
+	payloadHashField  = "payloadHash"
+	deliveryIDField   = "deliveryID"
+	errorField        = "error"
+	sentTag           = "_sent"
+	notificationsName = "notifications"
+)
+
+// BucketFinder finds the _monitoring buckets the notifications and their delivery attempts are recorded in.
+type BucketFinder interface {
+	FindBucketByName(ctx context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error)
+}
+
+// Service delivers notifications with HTTP POST requests, an attempt succeeding on a 2xx response.
+// The notifications and their delivery attempts are written to the _monitoring bucket of their organization,
+// and the failed deliveries are queued to be retried with an exponential backoff until MaxAttempts.
+type Service struct {
+	log     *zap.Logger
+	queue   influxdb.DeliveryQueue
+	buckets BucketFinder
+	pw      storage.PointsWriter
+	qs      query.QueryService
+
+	// Client is the client the notifications are delivered with.
+	Client *http.Client
+	// Interval is the interval the queued deliveries are retried at.
+	Interval time.Duration
+	// BaseBackoff is the time the first retry of a delivery waits for, doubled by each next retry.
+	BaseBackoff time.Duration
+	// MaxBackoff is the longest time a retry of a delivery waits for.
+	MaxBackoff time.Duration
+	// MaxAttempts is the number of attempts to deliver a notification before it is dropped.
+	MaxAttempts int
+	// BatchSize is the number of queued deliveries retried at once.
+	BatchSize int
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+// NewService returns a Service queuing the failed deliveries in queue and recording the notifications
+// and their delivery attempts with pw, to be found back with qs.
+func NewService(log *zap.Logger, queue influxdb.DeliveryQueue, buckets BucketFinder, pw storage.PointsWriter, qs query.QueryService) *Service {
+	return &Service{
+		log:         log,
+		queue:       queue,
+		buckets:     buckets,
+		pw:          pw,
+		qs:          qs,
+		Client:      &http.Client{Timeout: DefaultTimeout},
+		Interval:    DefaultInterval,
+		BaseBackoff: DefaultBaseBackoff,
+		MaxBackoff:  DefaultMaxBackoff,
+		MaxAttempts: DefaultMaxAttempts,
+		BatchSize:   DefaultBatchSize,
+		Now:         time.Now,
+	}
+}
+
+// DeliverNotifications sends the notifications to their endpoints and records them and their delivery attempts
+// in the _monitoring bucket. The notifications failing to be delivered are queued to be retried.
+// All the notifications are attempted, the first error is returned.
+func (s *Service) DeliverNotifications(ctx context.Context, ns []influxdb.RuleNotification) error {
+	var firstErr error
+	points := make(map[influxdb.ID]models.Points)
+	for _, n := range ns {
+		a := s.attempt(ctx, n.RuleID, n.EndpointID, n.Request)
+		a.Attempt = 1
+		if a.Status != influxdb.DeliveryDelivered {
+			a.Status = influxdb.DeliveryRetrying
+			d := &influxdb.QueuedDelivery{
+				OrgID:         n.OrgID,
+				RuleID:        n.RuleID,
+				EndpointID:    n.EndpointID,
+				Request:       n.Request,
+				Attempts:      1,
+				NextAttemptAt: a.Time.Add(s.backoff(1)),
+				CreatedAt:     a.Time,
+			}
+			if s.MaxAttempts <= 1 {
+				a.Status = influxdb.DeliveryFailed
+			} else if err := s.queue.QueueDelivery(ctx, d); err != nil {
+				s.log.Error("Failed to queue notification delivery",
+					zap.Stringer("ruleID", n.RuleID),
+					zap.Stringer("endpointID", n.EndpointID),
+					zap.Error(err))
+				a.Status = influxdb.DeliveryFailed
+				if firstErr == nil {
+					firstErr = err
+				}
+			} else {
+				a.DeliveryID = d.ID
+			}
+		}
+
+		pts, err := notificationPoints(n, a)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		points[n.OrgID] = append(points[n.OrgID], pts...)
+	}
+
+	if err := s.write(ctx, points); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	if firstErr != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeliverNotifications,
+			Err: firstErr,
+		}
+	}
+	return nil
+}
+
+// Run retries the queued deliveries each interval until ctx is done.
+func (s *Service) Run(ctx context.Context) {
+	logger := s.log.With(
+		zap.String("service", "notification_delivery"),
+		influxlogger.DurationLiteral("interval", s.Interval),
+	)
+
+	logger.Info("Starting")
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Retry(ctx); err != nil {
+				logger.Error("Failure retrying notification deliveries", zap.Error(err))
+			}
+		case <-ctx.Done():
+			logger.Info("Stopping")
+			return
+		}
+	}
+}
+
+// Retry attempts the queued deliveries that are due. A delivery is removed from the queue once delivered
+// or after MaxAttempts, and else waits for twice as long as its previous retry, up to MaxBackoff.
+func (s *Service) Retry(ctx context.Context) error {
+	due, err := s.queue.FindDueDeliveries(ctx, s.Now().UTC(), s.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	var firstErr error
+	points := make(map[influxdb.ID]models.Points)
+	for _, d := range due {
+		a := s.attempt(ctx, d.RuleID, d.EndpointID, d.Request)
+		d.Attempts++
+		a.Attempt = d.Attempts
+		a.DeliveryID = d.ID
+
+		switch {
+		case a.Status == influxdb.DeliveryDelivered:
+			err = s.queue.DeleteQueuedDelivery(ctx, d.ID)
+		case d.Attempts >= s.MaxAttempts:
+			a.Status = influxdb.DeliveryFailed
+			err = s.queue.DeleteQueuedDelivery(ctx, d.ID)
+		default:
+			a.Status = influxdb.DeliveryRetrying
+			d.NextAttemptAt = a.Time.Add(s.backoff(d.Attempts))
+			err = s.queue.QueueDelivery(ctx, d)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		p, err := attemptPoint(a)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		points[d.OrgID] = append(points[d.OrgID], p)
+	}
+
+	if err := s.write(ctx, points); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// backoff returns the time to wait for before the retry following the attempt.
+func (s *Service) backoff(attempt int) time.Duration {
+	b := s.BaseBackoff
+	for i := 1; i < attempt && b < s.MaxBackoff; i++ {
+		b *= 2
+	}
+	if b > s.MaxBackoff {
+		b = s.MaxBackoff
+	}
+	return b
+}
+
+// attempt sends the request, the returned attempt is delivered on a 2xx response.
+func (s *Service) attempt(ctx context.Context, ruleID, endpointID influxdb.ID, r influxdb.NotificationRequest) *influxdb.DeliveryAttempt {
+	sum := sha256.Sum256([]byte(r.Body))
+	a := &influxdb.DeliveryAttempt{
+		Time:        s.Now().UTC(),
+		RuleID:      ruleID,
+		EndpointID:  endpointID,
+		Status:      influxdb.DeliveryRetrying,
+		PayloadHash: hex.EncodeToString(sum[:]),
+	}
+
+	start := time.Now()
+	code, err := s.send(ctx, r)
+	a.Latency = influxdb.Duration{Duration: time.Since(start)}
+	a.StatusCode = code
+	if err != nil {
+		a.Error = err.Error()
+		s.log.Debug("Failed to deliver notification",
+			zap.Stringer("ruleID", ruleID),
+			zap.Stringer("endpointID", endpointID),
+			zap.Error(err))
+		return a
+	}
+	a.Status = influxdb.DeliveryDelivered
+	return a
+}
+
+func (s *Service) send(ctx context.Context, r influxdb.NotificationRequest) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, r.URL, bytes.NewBufferString(r.Body))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return resp.StatusCode, nil
+}
+
+// write writes the points of each organization to its _monitoring bucket.
+func (s *Service) write(ctx context.Context, points map[influxdb.ID]models.Points) error {
+	var firstErr error
+	for orgID, pts := range points {
+		if err := s.writeOrg(ctx, orgID, pts); err != nil {
+			s.log.Error("Failed to record notification deliveries", zap.Stringer("orgID", orgID), zap.Error(err))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (s *Service) writeOrg(ctx context.Context, orgID influxdb.ID, pts models.Points) error {
+	b, err := s.buckets.FindBucketByName(ctx, orgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return err
+	}
+	exploded, err := tsdb.ExplodePoints(orgID, b.ID, pts)
+	if err != nil {
+		return err
+	}
+	return s.pw.WritePoints(ctx, exploded)
+}
+
+// notificationPoints returns the record of the notification, sent if a delivered it, and the point of a.
+func notificationPoints(n influxdb.RuleNotification, a *influxdb.DeliveryAttempt) (models.Points, error) {
+	ap, err := attemptPoint(a)
+	if err != nil {
+		return nil, err
+	}
+	if len(n.Fields) == 0 {
+		return models.Points{ap}, nil
+	}
+
+	tags := make(map[string]string, len(n.Tags)+1)
+	for k, v := range n.Tags {
+		tags[k] = v
+	}
+	tags[sentTag] = fmt.Sprint(a.Status == influxdb.DeliveryDelivered)
+	name := n.Measurement
+	if name == "" {
+		name = notificationsName
+	}
+	t := n.Time
+	if t.IsZero() {
+		t = a.Time
+	}
+	np, err := models.NewPoint(name, models.NewTags(tags), n.Fields, t)
+	if err != nil {
+		return nil, err
+	}
+	return models.Points{np, ap}, nil
+}
+
+func attemptPoint(a *influxdb.DeliveryAttempt) (models.Point, error) {
+	tags := models.NewTags(map[string]string{
+		ruleIDTag:     a.RuleID.String(),
+		endpointIDTag: a.EndpointID.String(),
+		statusTag:     a.Status,
+	})
+	deliveryID := ""
+	if a.DeliveryID.Valid() {
+		deliveryID = a.DeliveryID.String()
+	}
+	fields := map[string]interface{}{
+		attemptField:     int64(a.Attempt),
+		statusCodeField:  int64(a.StatusCode),
+		latencyField:     int64(a.Latency.Duration),
+		payloadHashField: a.PayloadHash,
+		deliveryIDField:  deliveryID,
+		errorField:       a.Error,
+	}
+	return models.NewPoint(influxdb.DeliveryMeasurement, tags, fields, a.Time)
+}
+
+// FindDeliveryAttempts returns the delivery attempts of the notifications of a notification rule
+// recorded in the last 14 days, the latest first.
+func (s *Service) FindDeliveryAttempts(ctx context.Context, filter influxdb.DeliveryAttemptFilter) ([]*influxdb.DeliveryAttempt, int, error) {
+	if filter.Limit == 0 {
+		filter.Limit = influxdb.DefaultDeliveryAttemptsLimit
+	}
+	if filter.Limit < 0 || filter.Limit > influxdb.MaxDeliveryAttemptsLimit {
+		return nil, 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpFindDeliveryAttempts,
+			Msg:  fmt.Sprintf("limit must be between 1 and %d", influxdb.MaxDeliveryAttemptsLimit),
+		}
+	}
+
+	b, err := s.buckets.FindBucketByName(ctx, filter.OrgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	statusPart := ""
+	if filter.Status != nil {
+		statusPart = fmt.Sprintf(`|> filter(fn: (r) => r.%s == %q)`, statusTag, *filter.Status)
+	}
+
+	// the _monitoring bucket keeps data for 7 days so pulling 14d's is sufficient.
+	script := fmt.Sprintf(`from(bucketID: %q)
+	  |> range(start: -14d)
+	  |> filter(fn: (r) => r._measurement == %q and r.%s == %q)
+	  %s
+	  |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
+	  |> group()
+	  |> sort(columns: ["_time"], desc: true)
+	  |> limit(n: %d)
+	`, b.ID.String(), influxdb.DeliveryMeasurement, ruleIDTag, filter.RuleID.String(), statusPart, filter.Limit)
+
+	// At this point we are behind authorization
+	// so we are faking a read only permission to the org's system bucket
+	bucketID := b.ID
+	auth := &influxdb.Authorization{
+		Status: influxdb.Active,
+		ID:     b.ID,
+		OrgID:  filter.OrgID,
+		Permissions: []influxdb.Permission{
+			{
+				Action: influxdb.ReadAction,
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: &filter.OrgID,
+					ID:    &bucketID,
+				},
+			},
+		},
+	}
+	request := &query.Request{Authorization: auth, OrganizationID: filter.OrgID, Compiler: lang.FluxCompiler{Query: script}}
+
+	itr, err := s.qs.Query(ctx, request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer itr.Release()
+
+	r := &attemptReader{log: s.log}
+	for itr.More() {
+		if err := itr.Next().Tables().Do(r.readTable); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := itr.Err(); err != nil {
+		return nil, 0, fmt.Errorf("unexpected internal error while decoding delivery attempts: %v", err)
+	}
+	return r.attempts, len(r.attempts), nil
+}
+
+// attemptReader reads the delivery attempts out of the pivoted tables of their points.
+type attemptReader struct {
+	log      *zap.Logger
+	attempts []*influxdb.DeliveryAttempt
+}
+
+func (r *attemptReader) readTable(tbl flux.Table) error {
+	return tbl.Do(r.readAttempts)
+}
+
+func (r *attemptReader) readAttempts(cr flux.ColReader) error {
+	for i := 0; i < cr.Len(); i++ {
+		var a influxdb.DeliveryAttempt
+		for j, col := range cr.Cols() {
+			switch {
+			case col.Label == "_time" && col.Type == flux.TTime:
+				if ts := cr.Times(j); ts.IsValid(i) {
+					a.Time = time.Unix(0, ts.Value(i)).UTC()
+				}
+			case col.Type == flux.TString:
+				v := cr.Strings(j).ValueString(i)
+				switch col.Label {
+				case ruleIDTag, endpointIDTag, deliveryIDField:
+					if v == "" {
+						continue
+					}
+					id, err := influxdb.IDFromString(v)
+					if err != nil {
+						r.log.Info("Failed to parse "+col.Label, zap.Error(err))
+						continue
+					}
+					switch col.Label {
+					case ruleIDTag:
+						a.RuleID = *id
+					case endpointIDTag:
+						a.EndpointID = *id
+					default:
+						a.DeliveryID = *id
+					}
+				case statusTag:
+					a.Status = v
+				case payloadHashField:
+					a.PayloadHash = v
+				case errorField:
+					a.Error = v
+				}
+			case col.Type == flux.TInt:
+				vs := cr.Ints(j)
+				if !vs.IsValid(i) {
+					continue
+				}
+				switch col.Label {
+				case attemptField:
+					a.Attempt = int(vs.Value(i))
+				case statusCodeField:
+					a.StatusCode = int(vs.Value(i))
+				case latencyField:
+					a.Latency = influxdb.Duration{Duration: time.Duration(vs.Value(i))}
+				}
+			}
+		}
+		r.attempts = append(r.attempts, &a)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package delivery_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/notification/delivery"
+	"github.com/influxdata/influxdb/query"
+	qmock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	orgID      = influxdb.ID(1)
+	ruleID     = influxdb.ID(2)
+	endpointID = influxdb.ID(3)
+	bucketID   = influxdb.ID(4)
+)
+
+// receiver is an endpoint failing its first requests.
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, string(body))
+	if rc.failures > 0 {
+		rc.failures--
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newService(t *testing.T, pw *mock.PointsWriter, qs query.QueryService) (*delivery.Service, *kv.Service) {
+	t.Helper()
+	queue := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := queue.Initialize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, id influxdb.ID, name string) (*influxdb.Bucket, error) {
+		if id != orgID || name != influxdb.MonitoringSystemBucketName {
+			t.Errorf("unexpected bucket %s of org %s", name, id)
+		}
+		return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: name}, nil
+	}
+	return delivery.NewService(zaptest.NewLogger(t), queue, buckets, pw, qs), queue
+}
+
+// tagValues returns the values of the tag of the points of the field of the measurement.
+func tagValues(pw *mock.PointsWriter, measurement, field, tag string) []string {
+	var vs []string
+	for _, p := range pw.Points {
+		tags := p.Tags()
+		if tags.GetString(models.MeasurementTagKey) == measurement && tags.GetString(models.FieldKeyTagKey) == field {
+			vs = append(vs, tags.GetString(tag))
+		}
+	}
+	return vs
+}
+
+func TestService_DeliverNotifications(t *testing.T) {
+	rc := &receiver{failures: 2}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	pw := &mock.PointsWriter{}
+	svc, queue := newService(t, pw, nil)
+	now := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	svc.Now = func() time.Time { return now }
+	svc.MaxAttempts = 3
+
+	ctx := context.Background()
+	n := influxdb.RuleNotification{
+		OrgID:      orgID,
+		RuleID:     ruleID,
+		EndpointID: endpointID,
+		Request: influxdb.NotificationRequest{
+			URL:     srv.URL + "/alerts",
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    `{"text":"cpu is crit"}`,
+		},
+		Tags:   map[string]string{"_notification_rule_id": ruleID.String()},
+		Fields: map[string]interface{}{"_message": "cpu is crit"},
+		Time:   now,
+	}
+	if err := svc.DeliverNotifications(ctx, []influxdb.RuleNotification{n}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rc.requests) != 1 || rc.requests[0].URL.Path != "/alerts" || rc.requests[0].Header.Get("Content-Type") != "application/json" || rc.bodies[0] != n.Request.Body {
+		t.Fatalf("unexpected requests %+v", rc.requests)
+	}
+	if sent := tagValues(pw, "notifications", "_message", "_sent"); len(sent) != 1 || sent[0] != "false" {
+		t.Errorf("expected the notification to be recorded as not sent, got %v", sent)
+	}
+	if st := tagValues(pw, influxdb.DeliveryMeasurement, "attempt", "status"); len(st) != 1 || st[0] != influxdb.DeliveryRetrying {
+		t.Errorf("expected the failed attempt to be recorded, got %v", st)
+	}
+
+	// nothing is retried before the backoff.
+	if err := svc.Retry(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rc.requests) != 1 {
+		t.Fatalf("expected no retry before the backoff, got %d requests", len(rc.requests))
+	}
+
+	now = now.Add(delivery.DefaultBaseBackoff)
+	if err := svc.Retry(ctx); err != nil {
+		t.Fatal(err)
+	}
+	due, err := queue.FindDueDeliveries(ctx, now.Add(2*delivery.DefaultBaseBackoff), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rc.requests) != 2 || len(due) != 1 || due[0].Attempts != 2 || !due[0].NextAttemptAt.Equal(now.Add(2*delivery.DefaultBaseBackoff)) {
+		t.Fatalf("expected the second attempt to double the backoff, got %+v", due)
+	}
+
+	now = now.Add(2 * delivery.DefaultBaseBackoff)
+	if err := svc.Retry(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rc.requests) != 3 || rc.bodies[2] != n.Request.Body {
+		t.Fatalf("expected the third attempt to deliver the notification, got %d requests", len(rc.requests))
+	}
+	if due, _ := queue.FindDueDeliveries(ctx, now.Add(time.Hour), 0); len(due) != 0 {
+		t.Errorf("expected the delivered notification to leave the queue, got %+v", due)
+	}
+	st := tagValues(pw, influxdb.DeliveryMeasurement, "attempt", "status")
+	want := []string{influxdb.DeliveryRetrying, influxdb.DeliveryRetrying, influxdb.DeliveryDelivered}
+	if strings.Join(st, ",") != strings.Join(want, ",") {
+		t.Errorf("expected the attempts %v, got %v", want, st)
+	}
+
+	// a notification is dropped after the last attempt.
+	rc.failures = 10
+	if err := svc.DeliverNotifications(ctx, []influxdb.RuleNotification{n}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		now = now.Add(delivery.DefaultMaxBackoff)
+		if err := svc.Retry(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if due, _ := queue.FindDueDeliveries(ctx, now.Add(delivery.DefaultMaxBackoff), 0); len(due) != 0 {
+		t.Errorf("expected the failed notification to leave the queue, got %+v", due)
+	}
+	st = tagValues(pw, influxdb.DeliveryMeasurement, "attempt", "status")
+	if st[len(st)-1] != influxdb.DeliveryFailed {
+		t.Errorf("expected the last attempt to fail, got %v", st)
+	}
+}
+
+func TestService_FindDeliveryAttempts(t *testing.T) {
+	start := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	var script string
+	qs := &qmock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			script = req.Compiler.(lang.FluxCompiler).Query
+			return flux.NewSliceResultIterator([]flux.Result{&tableResult{attemptsTable(t, start)}}), nil
+		},
+	}
+	svc, _ := newService(t, &mock.PointsWriter{}, qs)
+
+	failed := influxdb.DeliveryFailed
+	attempts, n, err := svc.FindDeliveryAttempts(context.Background(), influxdb.DeliveryAttemptFilter{
+		OrgID:  orgID,
+		RuleID: ruleID,
+		Status: &failed,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{bucketID.String(), `r.ruleID == "` + ruleID.String() + `"`, `r.status == "failed"`, "limit(n: 100)"} {
+		if !strings.Contains(script, s) {
+			t.Errorf("expected the query to contain %q, got %s", s, script)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("expected an attempt, got %d", n)
+	}
+	want := influxdb.DeliveryAttempt{
+		Time:        start,
+		RuleID:      ruleID,
+		EndpointID:  endpointID,
+		DeliveryID:  5,
+		Attempt:     10,
+		Status:      influxdb.DeliveryFailed,
+		StatusCode:  503,
+		Latency:     influxdb.Duration{Duration: 20 * time.Millisecond},
+		PayloadHash: "abc",
+		Error:       "unavailable",
+	}
+	if *attempts[0] != want {
+		t.Errorf("expected %+v, got %+v", want, *attempts[0])
+	}
+
+	if _, _, err := svc.FindDeliveryAttempts(context.Background(), influxdb.DeliveryAttemptFilter{OrgID: orgID, RuleID: ruleID, Limit: 1000}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an out of bounds limit to be invalid, got %v", err)
+	}
+}
+
+// attemptsTable builds the pivoted table of a failed delivery attempt.
+func attemptsTable(t *testing.T, at time.Time) flux.Table {
+	t.Helper()
+	b := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), &memory.Allocator{})
+	cols := []struct {
+		meta flux.ColMeta
+		v    values.Value
+	}{
+		{flux.ColMeta{Label: "_time", Type: flux.TTime}, values.NewTime(values.ConvertTime(at))},
+		{flux.ColMeta{Label: "ruleID", Type: flux.TString}, values.NewString(ruleID.String())},
+		{flux.ColMeta{Label: "endpointID", Type: flux.TString}, values.NewString(endpointID.String())},
+		{flux.ColMeta{Label: "status", Type: flux.TString}, values.NewString(influxdb.DeliveryFailed)},
+		{flux.ColMeta{Label: "attempt", Type: flux.TInt}, values.NewInt(10)},
+		{flux.ColMeta{Label: "statusCode", Type: flux.TInt}, values.NewInt(503)},
+		{flux.ColMeta{Label: "latency", Type: flux.TInt}, values.NewInt(int64(20 * time.Millisecond))},
+		{flux.ColMeta{Label: "payloadHash", Type: flux.TString}, values.NewString("abc")},
+		{flux.ColMeta{Label: "deliveryID", Type: flux.TString}, values.NewString(influxdb.ID(5).String())},
+		{flux.ColMeta{Label: "error", Type: flux.TString}, values.NewString("unavailable")},
+	}
+	for _, c := range cols {
+		if _, err := b.AddCol(c.meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for j, c := range cols {
+		if err := b.AppendValue(j, c.v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tbl, err := b.Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tbl
+}
+
+// tableResult is a flux.Result of a table.
+type tableResult struct {
+	table flux.Table
+}
+
+func (r *tableResult) Name() string               { return "_result" }
+func (r *tableResult) Tables() flux.TableIterator { return r }
+
+func (r *tableResult) Do(f func(flux.Table) error) error {
+	return f(r.table)
+}
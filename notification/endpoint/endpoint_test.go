@@ -136,6 +136,86 @@ func TestValidEndpoint(t *testing.T) {
 				Msg:  "invalid http username/password for basic auth",
 			},
 		},
+		{
+			name: "empty smtp host",
+			src: &endpoint.SMTP{
+				Base: goodBase,
+				Port: 25,
+				TLS:  endpoint.SMTPTLSStartTLS,
+				From: "influxdb@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp endpoint host must be provided",
+			},
+		},
+		{
+			name: "invalid smtp port",
+			src: &endpoint.SMTP{
+				Base: goodBase,
+				Host: "smtp.example.com",
+				TLS:  endpoint.SMTPTLSStartTLS,
+				From: "influxdb@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp endpoint port 0 is invalid",
+			},
+		},
+		{
+			name: "invalid smtp tls mode",
+			src: &endpoint.SMTP{
+				Base: goodBase,
+				Host: "smtp.example.com",
+				Port: 465,
+				TLS:  "ssl",
+				From: "influxdb@example.com",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `invalid smtp endpoint tls mode "ssl"`,
+			},
+		},
+		{
+			name: "invalid smtp from",
+			src: &endpoint.SMTP{
+				Base: goodBase,
+				Host: "smtp.example.com",
+				Port: 587,
+				TLS:  endpoint.SMTPTLSStartTLS,
+				From: "influxdb",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp endpoint from address is invalid: mail: missing '@' or angle-addr",
+			},
+		},
+		{
+			name: "empty smtp password",
+			src: &endpoint.SMTP{
+				Base:     goodBase,
+				Host:     "smtp.example.com",
+				Port:     587,
+				TLS:      endpoint.SMTPTLSStartTLS,
+				From:     "influxdb@example.com",
+				Username: influxdb.SecretField{Key: "username-key"},
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invalid smtp endpoint password for username",
+			},
+		},
+		{
+			name: "valid smtp",
+			src: &endpoint.SMTP{
+				Base: goodBase,
+				Host: "smtp.example.com",
+				Port: 25,
+				TLS:  endpoint.SMTPTLSNone,
+				From: "InfluxDB <influxdb@example.com>",
+			},
+			err: nil,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -226,6 +306,27 @@ func TestJSON(t *testing.T) {
 				Password:   influxdb.SecretField{Key: "password-key"},
 			},
 		},
+		{
+			name: "simple smtp",
+			src: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "name1",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				Host:     "smtp.example.com",
+				Port:     587,
+				TLS:      endpoint.SMTPTLSStartTLS,
+				Username: influxdb.SecretField{Key: "username-key"},
+				Password: influxdb.SecretField{Key: "password-key"},
+				From:     "influxdb@example.com",
+			},
+		},
 	}
 	for _, c := range cases {
 		b, err := json.Marshal(c.src)
@@ -365,6 +466,47 @@ func TestBackFill(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "smtp with username and password",
+			src: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "name1",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+				},
+				Host: "smtp.example.com",
+				Port: 465,
+				TLS:  endpoint.SMTPTLS,
+				Username: influxdb.SecretField{
+					Value: strPtr("username1"),
+				},
+				Password: influxdb.SecretField{
+					Value: strPtr("password1"),
+				},
+				From: "influxdb@example.com",
+			},
+			target: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:     influxTesting.MustIDBase16Ptr(id1),
+					Name:   "name1",
+					OrgID:  influxTesting.MustIDBase16Ptr(id3),
+					Status: influxdb.Active,
+				},
+				Host: "smtp.example.com",
+				Port: 465,
+				TLS:  endpoint.SMTPTLS,
+				Username: influxdb.SecretField{
+					Key:   id1 + "-username",
+					Value: strPtr("username1"),
+				},
+				Password: influxdb.SecretField{
+					Key:   id1 + "-password",
+					Value: strPtr("password1"),
+				},
+				From: "influxdb@example.com",
+			},
+		},
 	}
 	for _, c := range cases {
 		c.src.BackfillSecretKeys()
@@ -0,0 +1,125 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.NotificationEndpoint = &SMTP{}
+
+const (
+	smtpUsernameSuffix = "-username"
+	smtpPasswordSuffix = "-password"
+)
+
+// TLS modes of the connection to an SMTP server.
+const (
+	SMTPTLSNone     = "none"
+	SMTPTLSStartTLS = "starttls"
+	SMTPTLS         = "tls"
+)
+
+var goodSMTPTLS = map[string]bool{
+	SMTPTLSNone:     true,
+	SMTPTLSStartTLS: true,
+	SMTPTLS:         true,
+}
+
+// SMTP is the notification endpoint config of an SMTP server emails are sent through.
+type SMTP struct {
+	Base
+	// Host is the host name of the SMTP server.
+	Host string `json:"host"`
+	// Port is the port of the SMTP server.
+	Port int `json:"port"`
+	// TLS is the TLS mode of the connection, one of none, starttls or tls.
+	TLS string `json:"tls"`
+	// Username and Password authenticate to the server when the username is set.
+	Username influxdb.SecretField `json:"username,omitempty"`
+	Password influxdb.SecretField `json:"password,omitempty"`
+	// From is the address emails are sent from.
+	From string `json:"from"`
+}
+
+// BackfillSecretKeys fill back fill the secret field key during the unmarshalling
+// if value of that secret field is not nil.
+func (s *SMTP) BackfillSecretKeys() {
+	if s.Username.Key == "" && s.Username.Value != nil {
+		s.Username.Key = s.idStr() + smtpUsernameSuffix
+	}
+	if s.Password.Key == "" && s.Password.Value != nil {
+		s.Password.Key = s.idStr() + smtpPasswordSuffix
+	}
+}
+
+// SecretFields return available secret fields.
+func (s SMTP) SecretFields() []influxdb.SecretField {
+	arr := make([]influxdb.SecretField, 0)
+	if s.Username.Key != "" {
+		arr = append(arr, s.Username)
+	}
+	if s.Password.Key != "" {
+		arr = append(arr, s.Password)
+	}
+	return arr
+}
+
+// Valid returns error if some configuration is invalid
+func (s SMTP) Valid() error {
+	if err := s.Base.valid(); err != nil {
+		return err
+	}
+	if s.Host == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "smtp endpoint host must be provided",
+		}
+	}
+	if s.Port < 1 || s.Port > 65535 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("smtp endpoint port %d is invalid", s.Port),
+		}
+	}
+	if !goodSMTPTLS[s.TLS] {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid smtp endpoint tls mode %q", s.TLS),
+		}
+	}
+	if _, err := mail.ParseAddress(s.From); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("smtp endpoint from address is invalid: %s", err.Error()),
+		}
+	}
+	if s.Username.Key != "" && s.Password.Key == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid smtp endpoint password for username",
+		}
+	}
+	return nil
+}
+
+type smtpAlias SMTP
+
+// MarshalJSON implement json.Marshaler interface.
+func (s SMTP) MarshalJSON() ([]byte, error) {
+	return json.Marshal(
+		struct {
+			smtpAlias
+			Type string `json:"type"`
+		}{
+			smtpAlias: smtpAlias(s),
+			Type:      s.Type(),
+		})
+}
+
+// Type returns the type.
+func (s SMTP) Type() string {
+	return SMTPType
+}
@@ -0,0 +1,162 @@
+// Package escalation escalates the alerts of checks nobody acknowledged in time,
+// following the escalation policies of notification rules.
+package escalation
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	influxlogger "github.com/influxdata/influxdb/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultInterval is the interval the open alerts are checked for escalation at.
+const DefaultInterval = time.Minute
+
+// TaskFinder finds the tasks of notification rules, which hold whether the rules are active.
+type TaskFinder interface {
+	FindTaskByID(ctx context.Context, id influxdb.ID) (*influxdb.Task, error)
+}
+
+// Escalator escalates the open alerts matched by an active notification rule with an escalation policy
+// once they have been open for the timeout of the policy. An alert is escalated once by each rule,
+// to the secondary endpoint of the policy or else to the endpoint of the rule.
+type Escalator struct {
+	log      *zap.Logger
+	alerts   influxdb.AlertService
+	tracker  influxdb.AlertTracker
+	rules    influxdb.NotificationRuleStore
+	tasks    TaskFinder
+	notifier influxdb.AlertNotifier
+
+	// Interval is the interval the open alerts are checked at.
+	Interval time.Duration
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+// NewEscalator returns an Escalator checking the open alerts every DefaultInterval.
+func NewEscalator(log *zap.Logger, alerts influxdb.AlertService, tracker influxdb.AlertTracker, rules influxdb.NotificationRuleStore, tasks TaskFinder, notifier influxdb.AlertNotifier) *Escalator {
+	return &Escalator{
+		log:      log,
+		alerts:   alerts,
+		tracker:  tracker,
+		rules:    rules,
+		tasks:    tasks,
+		notifier: notifier,
+		Interval: DefaultInterval,
+		Now:      time.Now,
+	}
+}
+
+// Run escalates the open alerts each interval until ctx is done.
+func (e *Escalator) Run(ctx context.Context) {
+	logger := e.log.With(
+		zap.String("service", "alert_escalation"),
+		influxlogger.DurationLiteral("interval", e.Interval),
+	)
+
+	logger.Info("Starting")
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.Escalate(ctx); err != nil {
+				logger.Error("Failure escalating alerts", zap.Error(err))
+			}
+		case <-ctx.Done():
+			logger.Info("Stopping")
+			return
+		}
+	}
+}
+
+// Escalate escalates the open alerts due for escalation.
+// An alert failing to be notified is escalated again by the next call.
+func (e *Escalator) Escalate(ctx context.Context) error {
+	open := influxdb.AlertOpen
+	alerts, _, err := e.alerts.FindAlerts(ctx, influxdb.AlertFilter{Status: &open})
+	if err != nil {
+		return err
+	}
+
+	now := e.Now().UTC()
+	rulesByOrg := make(map[influxdb.ID][]influxdb.NotificationRule)
+	for _, a := range alerts {
+		rules, ok := rulesByOrg[a.OrgID]
+		if !ok {
+			if rules, err = e.escalatingRules(ctx, a.OrgID); err != nil {
+				return err
+			}
+			rulesByOrg[a.OrgID] = rules
+		}
+
+		tags := make([]influxdb.Tag, 0, len(a.Tags))
+		for k, v := range a.Tags {
+			tags = append(tags, influxdb.Tag{Key: k, Value: v})
+		}
+		unacknowledged := now.Sub(a.OpenedAt)
+		for _, r := range rules {
+			esc := r.GetEscalation()
+			if unacknowledged < esc.Timeout.Duration || a.EscalatedBy(r.GetID()) || !r.MatchesLevel(a.Level) || !r.MatchesTags(tags) {
+				continue
+			}
+			if err := e.escalate(ctx, a, r, unacknowledged, now); err != nil {
+				e.log.Error("Failed to escalate alert",
+					zap.Stringer("alertID", a.ID),
+					zap.Stringer("ruleID", r.GetID()),
+					zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Escalator) escalate(ctx context.Context, a *influxdb.Alert, r influxdb.NotificationRule, unacknowledged time.Duration, now time.Time) error {
+	endpointID := r.GetEndpointID()
+	if id := r.GetEscalation().EndpointID; id != nil {
+		endpointID = *id
+	}
+	err := e.notifier.NotifyAlert(ctx, endpointID, influxdb.AlertNotification{
+		Alert:          *a,
+		RuleID:         r.GetID(),
+		RuleName:       r.GetName(),
+		Unacknowledged: influxdb.Duration{Duration: unacknowledged.Truncate(time.Second)},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = e.tracker.EscalateAlert(ctx, a.ID, r.GetID(), endpointID, now)
+	return err
+}
+
+// escalatingRules returns the active notification rules of the organization with an escalation policy.
+func (e *Escalator) escalatingRules(ctx context.Context, orgID influxdb.ID) ([]influxdb.NotificationRule, error) {
+	rules, _, err := e.rules.FindNotificationRules(ctx, influxdb.NotificationRuleFilter{
+		OrgID: &orgID,
+		UserResourceMappingFilter: influxdb.UserResourceMappingFilter{
+			ResourceType: influxdb.NotificationRuleResourceType,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	escalating := make([]influxdb.NotificationRule, 0, len(rules))
+	for _, r := range rules {
+		if r.GetEscalation() == nil {
+			continue
+		}
+		t, err := e.tasks.FindTaskByID(ctx, r.GetTaskID())
+		if err != nil {
+			e.log.Debug("Failed to find the task of notification rule", zap.Stringer("ruleID", r.GetID()), zap.Error(err))
+			continue
+		}
+		if t.Status == influxdb.TaskStatusActive {
+			escalating = append(escalating, r)
+		}
+	}
+	return escalating, nil
+}
@@ -0,0 +1,171 @@
+package escalation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/escalation"
+	"github.com/influxdata/influxdb/notification/rule"
+	"go.uber.org/zap/zaptest"
+)
+
+// notifierFunc notifies of alerts by calling the func.
+type notifierFunc func(ctx context.Context, endpointID influxdb.ID, n influxdb.AlertNotification) error
+
+func (f notifierFunc) NotifyAlert(ctx context.Context, endpointID influxdb.ID, n influxdb.AlertNotification) error {
+	return f(ctx, endpointID, n)
+}
+
+type notified struct {
+	endpointID influxdb.ID
+	n          influxdb.AlertNotification
+}
+
+func TestEscalator_Escalate(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		orgID     = influxdb.ID(1)
+		checkID   = influxdb.ID(2)
+		endpoint  = influxdb.ID(10)
+		secondary = influxdb.ID(11)
+	)
+	start := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	err := svc.TrackStatuses(ctx, []influxdb.AlertStatus{
+		{OrgID: orgID, CheckID: checkID, CheckName: "cpu", Tags: map[string]string{"host": "a"}, Level: "crit", Time: start},
+		{OrgID: orgID, CheckID: checkID, CheckName: "cpu", Tags: map[string]string{"host": "b"}, Level: "warn", Time: start},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ruleBase := func(id influxdb.ID, taskID influxdb.ID, esc *influxdb.Escalation) rule.Base {
+		return rule.Base{
+			ID:          id,
+			Name:        "rule",
+			OrgID:       orgID,
+			EndpointID:  endpoint,
+			TaskID:      taskID,
+			StatusRules: []notification.StatusRule{{CurrentLevel: notification.Critical}},
+			Escalation:  esc,
+		}
+	}
+	rules := mock.NewNotificationRuleStore()
+	rules.FindNotificationRulesF = func(ctx context.Context, f influxdb.NotificationRuleFilter, _ ...influxdb.FindOptions) ([]influxdb.NotificationRule, int, error) {
+		if f.OrgID == nil || *f.OrgID != orgID {
+			return nil, 0, nil
+		}
+		rs := []influxdb.NotificationRule{
+			&rule.Slack{Base: ruleBase(20, 30, &influxdb.Escalation{Timeout: influxdb.Duration{Duration: 15 * time.Minute}})},
+			&rule.Slack{Base: ruleBase(21, 31, &influxdb.Escalation{Timeout: influxdb.Duration{Duration: 30 * time.Minute}, EndpointID: idPtr(secondary)})},
+			// an inactive rule, and a rule without escalation.
+			&rule.Slack{Base: ruleBase(22, 32, &influxdb.Escalation{Timeout: influxdb.Duration{Duration: time.Minute}})},
+			&rule.Slack{Base: ruleBase(23, 30, nil)},
+		}
+		return rs, len(rs), nil
+	}
+	tasks := mock.NewTaskService()
+	tasks.FindTaskByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		status := influxdb.TaskStatusActive
+		if id == 32 {
+			status = influxdb.TaskStatusInactive
+		}
+		return &influxdb.Task{ID: id, Status: status}, nil
+	}
+
+	var (
+		sent []notified
+		fail bool
+	)
+	notifier := notifierFunc(func(ctx context.Context, endpointID influxdb.ID, n influxdb.AlertNotification) error {
+		if fail {
+			return errors.New("endpoint unavailable")
+		}
+		sent = append(sent, notified{endpointID: endpointID, n: n})
+		return nil
+	})
+
+	now := start
+	esc := escalation.NewEscalator(zaptest.NewLogger(t), svc, svc, rules, tasks, notifier)
+	esc.Now = func() time.Time { return now }
+	escalate := func() {
+		t.Helper()
+		if err := esc.Escalate(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the alerts are not open for long enough.
+	now = start.Add(10 * time.Minute)
+	escalate()
+	if len(sent) != 0 {
+		t.Fatalf("expected no escalation, got %+v", sent)
+	}
+
+	// a failed notification is retried on the next escalation.
+	now = start.Add(20 * time.Minute)
+	fail = true
+	escalate()
+	fail = false
+	escalate()
+	if len(sent) != 1 || sent[0].endpointID != endpoint || sent[0].n.RuleID != 20 || sent[0].n.Alert.Tags["host"] != "a" {
+		t.Fatalf("expected the critical alert to be escalated to the endpoint of the rule, got %+v", sent)
+	}
+	if sent[0].n.Unacknowledged.Duration != 20*time.Minute {
+		t.Errorf("unexpected unacknowledged duration %s", sent[0].n.Unacknowledged)
+	}
+
+	// an alert is escalated once per rule, and to the secondary endpoint of the policy.
+	now = start.Add(40 * time.Minute)
+	escalate()
+	escalate()
+	if len(sent) != 2 || sent[1].endpointID != secondary || sent[1].n.RuleID != 21 {
+		t.Fatalf("expected the alert to be escalated to the secondary endpoint, got %+v", sent)
+	}
+	a, err := svc.FindAlertByID(ctx, sent[0].n.Alert.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.EscalatedBy(20) || !a.EscalatedBy(21) {
+		t.Errorf("expected the escalations to be recorded, got %+v", a.Events)
+	}
+
+	// acknowledged alerts are not escalated.
+	now = start.Add(2 * time.Hour)
+	if err := svc.TrackStatuses(ctx, []influxdb.AlertStatus{
+		{OrgID: orgID, CheckID: checkID, CheckName: "cpu", Tags: map[string]string{"host": "c"}, Level: "crit", Time: start},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	open := influxdb.AlertOpen
+	alerts, _, err := svc.FindAlerts(ctx, influxdb.AlertFilter{CheckID: idPtr(checkID), Status: &open})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range alerts {
+		if a.Tags["host"] == "c" {
+			if _, err := svc.AcknowledgeAlert(ctx, a.ID, 5, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	escalate()
+	if len(sent) != 2 {
+		t.Fatalf("expected no more escalation, got %+v", sent[2:])
+	}
+}
+
+func idPtr(id influxdb.ID) *influxdb.ID {
+	return &id
+}
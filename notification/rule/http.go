@@ -1,18 +1,31 @@
 package rule
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/textproto"
+	"sort"
+	"time"
 
+	fluxlang "github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/notification/endpoint"
 	"github.com/influxdata/influxdb/notification/flux"
+	"golang.org/x/net/http/httpguts"
 )
 
 // HTTP is the notification rule config of http.
 type HTTP struct {
 	Base
+	// BodyTemplate is the template of the body of the requests, see parseTemplate.
+	// The status record is sent as JSON if it is empty.
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+	// Headers are the templates of the extra headers of the requests.
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 // GenerateFlux generates a flux script for the http notification rule.
@@ -30,10 +43,14 @@ func (s *HTTP) GenerateFlux(e influxdb.NotificationEndpoint) (string, error) {
 
 // GenerateFluxAST generates a flux AST for the http notification rule.
 func (s *HTTP) GenerateFluxAST(e *endpoint.HTTP) (*ast.Package, error) {
+	body, err := s.generateFluxASTBody(e)
+	if err != nil {
+		return nil, err
+	}
 	f := flux.File(
 		s.Name,
 		s.imports(e),
-		s.generateFluxASTBody(e),
+		body,
 	)
 	return &ast.Package{Package: "main", Files: []*ast.File{f}}, nil
 }
@@ -53,7 +70,7 @@ func (s *HTTP) imports(e *endpoint.HTTP) []*ast.ImportDeclaration {
 	return flux.Imports(packages...)
 }
 
-func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) []ast.Statement {
+func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) ([]ast.Statement, error) {
 	var statements []ast.Statement
 	statements = append(statements, s.generateTaskOption())
 	statements = append(statements, s.generateHeaders(e))
@@ -61,9 +78,13 @@ func (s *HTTP) generateFluxASTBody(e *endpoint.HTTP) []ast.Statement {
 	statements = append(statements, s.generateFluxASTNotificationDefinition(e))
 	statements = append(statements, s.generateFluxASTStatuses())
 	statements = append(statements, s.generateAllStateChanges()...)
-	statements = append(statements, s.generateFluxASTNotifyPipe())
+	notify, err := s.generateFluxASTNotifyPipe()
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, notify)
 
-	return statements
+	return statements, nil
 }
 
 func (s *HTTP) generateHeaders(e *endpoint.HTTP) ast.Statement {
@@ -121,19 +142,32 @@ func (s *HTTP) generateFluxASTEndpoint(e *endpoint.HTTP) ast.Statement {
 	return flux.DefineVariable("endpoint", call)
 }
 
-func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
+func (s *HTTP) generateFluxASTNotifyPipe() (ast.Statement, error) {
+	body, err := s.generateBody()
+	if err != nil {
+		return nil, err
+	}
+	headers, err := s.generateRequestHeaders()
+	if err != nil {
+		return nil, err
+	}
 	endpointBody := flux.Call(
 		flux.Member("json", "encode"),
 		flux.Object(flux.Property("v", flux.Identifier("body"))),
 	)
-	headers := flux.Property("headers", flux.Identifier("headers"))
+	if s.BodyTemplate != "" {
+		endpointBody = flux.Call(
+			flux.Identifier("bytes"),
+			flux.Object(flux.Property("v", flux.Identifier("body"))),
+		)
+	}
 
 	endpointProps := []*ast.Property{
-		headers,
+		flux.Property("headers", headers),
 		flux.Property("data", endpointBody),
 	}
 	endpointFn := flux.FuncBlock(flux.FunctionParams("r"),
-		s.generateBody(),
+		body,
 		&ast.ReturnStatement{
 			Argument: flux.Object(endpointProps...),
 		},
@@ -146,10 +180,18 @@ func (s *HTTP) generateFluxASTNotifyPipe() ast.Statement {
 
 	call := flux.Call(flux.Member("monitor", "notify"), flux.Object(props...))
 
-	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call))
+	return flux.ExpressionStatement(flux.Pipe(flux.Identifier("all_statuses"), call)), nil
 }
 
-func (s *HTTP) generateBody() ast.Statement {
+func (s *HTTP) generateBody() (ast.Statement, error) {
+	if s.BodyTemplate != "" {
+		body, err := parseTemplate(s.BodyTemplate)
+		if err != nil {
+			return nil, err
+		}
+		return flux.DefineVariable("body", body), nil
+	}
+
 	// {r with "_version": 1}
 	props := []*ast.Property{
 		flux.Property(
@@ -158,7 +200,31 @@ func (s *HTTP) generateBody() ast.Statement {
 	}
 
 	body := flux.ObjectWith("r", props...)
-	return flux.DefineVariable("body", body)
+	return flux.DefineVariable("body", body), nil
+}
+
+// generateRequestHeaders returns the headers of a request, which are the headers
+// of the endpoint with the rendered headers of the rule.
+func (s *HTTP) generateRequestHeaders() (ast.Expression, error) {
+	if len(s.Headers) == 0 {
+		return flux.Identifier("headers"), nil
+	}
+
+	keys := make([]string, 0, len(s.Headers))
+	for k := range s.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	props := make([]*ast.Property, 0, len(keys))
+	for _, k := range keys {
+		v, err := parseTemplate(s.Headers[k])
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, flux.Dictionary(textproto.CanonicalMIMEHeaderKey(k), v))
+	}
+	return flux.ObjectWith("headers", props...), nil
 }
 
 type httpAlias HTTP
@@ -180,6 +246,36 @@ func (s HTTP) Valid() error {
 	if err := s.Base.valid(); err != nil {
 		return err
 	}
+	return s.ValidTemplates()
+}
+
+// ValidTemplates returns an error if the body or a header template can not be parsed,
+// or a header name is invalid.
+func (s HTTP) ValidTemplates() error {
+	if s.BodyTemplate != "" {
+		if _, err := parseTemplate(s.BodyTemplate); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "http body template is invalid",
+				Err:  err,
+			}
+		}
+	}
+	for k, v := range s.Headers {
+		if !httpguts.ValidHeaderFieldName(k) {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("http header name %q is invalid", k),
+			}
+		}
+		if _, err := parseTemplate(v); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("http header %q template is invalid", k),
+				Err:  err,
+			}
+		}
+	}
 	return nil
 }
 
@@ -187,3 +283,158 @@ func (s HTTP) Valid() error {
 func (s HTTP) Type() string {
 	return "http"
 }
+
+// HTTPPreview is an http request rendered by the templates of an http notification rule.
+type HTTPPreview struct {
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// Preview renders the body and headers of the request sent for the status record.
+// An example record is used if the record is empty. The flux builtins must be
+// registered to evaluate the templates.
+func (s *HTTP) Preview(ctx context.Context, record map[string]interface{}) (*HTTPPreview, error) {
+	if err := s.ValidTemplates(); err != nil {
+		return nil, err
+	}
+	if len(record) == 0 {
+		record = s.exampleRecord()
+	}
+	r, err := recordObject(record)
+	if err != nil {
+		return nil, err
+	}
+
+	var templates []*ast.StringExpression
+	var body ast.Expression = flux.Call(
+		flux.Identifier("string"),
+		flux.Object(flux.Property("v", flux.Call(
+			flux.Member("json", "encode"),
+			flux.Object(flux.Property("v", flux.ObjectWith("r", flux.Property("_version", flux.Integer(1))))),
+		))),
+	)
+	if s.BodyTemplate != "" {
+		tmpl, err := parseTemplate(s.BodyTemplate)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+		body = tmpl
+	}
+	headers, err := s.generateRequestHeaders()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range s.Headers {
+		tmpl, err := parseTemplate(v)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+	for _, tmpl := range templates {
+		for _, f := range templateFields(tmpl) {
+			if _, ok := record[f]; !ok {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("status record has no value for the template interpolation of %q", f),
+				}
+			}
+		}
+	}
+
+	f := flux.File("", flux.Imports("json"), []ast.Statement{
+		flux.DefineVariable("r", r),
+		flux.DefineVariable("headers", flux.Object(
+			flux.Dictionary("Content-Type", flux.String("application/json")),
+		)),
+		flux.DefineVariable("request_headers", headers),
+		flux.DefineVariable("request_body", body),
+	})
+	pkg := &ast.Package{Package: "main", Files: []*ast.File{f}}
+
+	scope, err := evalPreview(ctx, pkg)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to render http notification templates",
+			Err:  err,
+		}
+	}
+
+	p := &HTTPPreview{Headers: map[string]string{}}
+	if v, ok := scope.Lookup("request_body"); ok && v.Type().Nature() == semantic.String {
+		p.Body = v.Str()
+	}
+	if v, ok := scope.Lookup("request_headers"); ok && v.Type().Nature() == semantic.Object {
+		v.Object().Range(func(k string, v values.Value) {
+			if v.Type().Nature() == semantic.String {
+				p.Headers[k] = v.Str()
+			}
+		})
+	}
+	return p, nil
+}
+
+// evalPreview evaluates the formatted preview program, as the templates are formatted
+// into the script of the rule.
+func evalPreview(ctx context.Context, pkg *ast.Package) (values.Scope, error) {
+	_, scope, err := fluxlang.Eval(ctx, ast.Format(pkg))
+	return scope, err
+}
+
+// exampleRecord returns a status record notified by the rule.
+func (s *HTTP) exampleRecord() map[string]interface{} {
+	record := map[string]interface{}{
+		"_check_id":                   influxdb.ID(1).String(),
+		"_check_name":                 "Example Check",
+		"_level":                      "crit",
+		"_measurement":                "notifications",
+		"_message":                    "Example Check is crit",
+		"_notification_rule_id":       s.ID.String(),
+		"_notification_rule_name":     s.Name,
+		"_notification_endpoint_id":   s.EndpointID.String(),
+		"_notification_endpoint_name": "",
+		"_source_measurement":         "cpu",
+		"_time":                       time.Unix(0, 0).UTC(),
+		"_type":                       "threshold",
+		"_value":                      99.5,
+	}
+	for _, tr := range s.TagRules {
+		if tr.Operator == influxdb.Equal {
+			record[tr.Key] = tr.Value
+		}
+	}
+	return record
+}
+
+// recordObject returns the flux object of a status record.
+func recordObject(record map[string]interface{}) (*ast.ObjectExpression, error) {
+	keys := make([]string, 0, len(record))
+	for k := range record {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	props := make([]*ast.Property, 0, len(keys))
+	for _, k := range keys {
+		var v ast.Expression
+		switch rv := record[k].(type) {
+		case string:
+			v = flux.String(rv)
+		case float64:
+			v = flux.Float(rv)
+		case bool:
+			v = flux.Bool(rv)
+		case time.Time:
+			v = &ast.DateTimeLiteral{Value: rv}
+		default:
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("status record value of %q must be a string, number or boolean", k),
+			}
+		}
+		props = append(props, flux.Dictionary(k, v))
+	}
+	return flux.Object(props...), nil
+}
@@ -1,12 +1,16 @@
 package rule_test
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/notification"
 	"github.com/influxdata/influxdb/notification/endpoint"
 	"github.com/influxdata/influxdb/notification/rule"
+	_ "github.com/influxdata/influxdb/query/builtin"
 )
 
 func TestHTTP_GenerateFlux(t *testing.T) {
@@ -297,3 +301,216 @@ all_statuses
 		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
 	}
 }
+
+func TestHTTP_GenerateFlux_templates(t *testing.T) {
+	want := `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "http"
+import "json"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+headers = {"Content-Type": "application/json"}
+endpoint = http.endpoint(url: "http://localhost:7777")
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: endpoint(mapFn: (r) => {
+		body = "{\"text\": \"${r._check_name} is ${r._level}: ${r._message}\", \"value\": ${string(v: r._value)}}"
+
+		return {headers: {headers with "X-Check": "${r._check_name}", "X-Level": "${r._level}"}, data: bytes(v: body)}
+	}))`
+
+	s := &rule.HTTP{
+		Base: rule.Base{
+			ID:         1,
+			Name:       "foo",
+			Every:      mustDuration("1h"),
+			EndpointID: 2,
+			StatusRules: []notification.StatusRule{
+				{
+					CurrentLevel: notification.Critical,
+				},
+			},
+		},
+		BodyTemplate: `{"text": "${r._check_name} is ${r._level}: ${r._message}", "value": ${string(v: r._value)}}`,
+		Headers: map[string]string{
+			"x-level": "${r._level}",
+			"X-Check": "${r._check_name}",
+		},
+	}
+
+	id := influxdb.ID(2)
+	e := &endpoint.HTTP{
+		Base: endpoint.Base{
+			ID:   &id,
+			Name: "foo",
+		},
+		URL: "http://localhost:7777",
+	}
+
+	f, err := s.GenerateFlux(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f != want {
+		t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", want, f)
+	}
+
+	s.BodyTemplate = "${r._message"
+	if _, err := s.GenerateFlux(e); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid template error, got %v", err)
+	}
+}
+
+func TestHTTP_ValidTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		headers map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "text and interpolations",
+			body:    `{"check": "${r._check_name}", "tags": {"host": "${r["host"]}"}}`,
+			headers: map[string]string{"X-Level": `${r._level}`, "X-Value": `${string(v: r._value)}`},
+		},
+		{
+			name:    "conditional",
+			headers: map[string]string{"X-Level": `${if r._level == "crit" then "high" else "low"}`},
+			wantErr: true,
+		},
+		{
+			name:    "function call",
+			body:    `${string(v: now())}`,
+			wantErr: true,
+		},
+		{
+			name:    "other identifier",
+			body:    `${headers.Authorization}`,
+			wantErr: true,
+		},
+		{
+			name:    "nested member",
+			body:    `${r.host.name}`,
+			wantErr: true,
+		},
+		{
+			name:    "unclosed interpolation",
+			body:    "${r._message",
+			wantErr: true,
+		},
+		{
+			name:    "invalid expression",
+			body:    "${r._message +}",
+			wantErr: true,
+		},
+		{
+			name:    "statement",
+			body:    "${x = 1}",
+			wantErr: true,
+		},
+		{
+			name:    "invalid header name",
+			headers: map[string]string{"X Level": "${r._level}"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid header template",
+			headers: map[string]string{"X-Level": "${}"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := rule.HTTP{BodyTemplate: tt.body, Headers: tt.headers}
+			err := s.ValidTemplates()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected invalid error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestHTTP_Preview(t *testing.T) {
+	s := &rule.HTTP{
+		BodyTemplate: `{"text": "${r._check_name} is ${r._level}", "value": ${string(v: r._value)}, "host": "${r.host}"}`,
+		Headers: map[string]string{
+			"x-level": "${r._level}",
+		},
+	}
+
+	p, err := s.Preview(context.Background(), map[string]interface{}{
+		"_check_name": "cpu",
+		"_level":      "crit",
+		"_value":      99.5,
+		"host":        "a",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &rule.HTTPPreview{
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			"X-Level":      "crit",
+		},
+		Body: `{"text": "cpu is crit", "value": 99.5, "host": "a"}`,
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("unexpected preview. want:\n%+v\ngot:\n%+v", want, p)
+	}
+
+	t.Run("example record", func(t *testing.T) {
+		s := &rule.HTTP{
+			Base: rule.Base{
+				Name: "foo",
+				TagRules: []notification.TagRule{
+					{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal},
+				},
+			},
+		}
+		p, err := s.Preview(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, exp := range []string{`"_check_name":"Example Check"`, `"_version":1`, `"host":"a"`} {
+			if !strings.Contains(p.Body, exp) {
+				t.Errorf("expected body to contain %s, got %s", exp, p.Body)
+			}
+		}
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		s := &rule.HTTP{BodyTemplate: "${r.missing}"}
+		_, err := s.Preview(context.Background(), map[string]interface{}{"_level": "crit"})
+		if influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error, got %v", err)
+		}
+	})
+
+	t.Run("value not converted to a string", func(t *testing.T) {
+		s := &rule.HTTP{BodyTemplate: "${r._value}"}
+		_, err := s.Preview(context.Background(), map[string]interface{}{"_value": 99.5})
+		if influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected invalid error, got %v", err)
+		}
+	})
+}
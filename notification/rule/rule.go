@@ -16,6 +16,7 @@ var typeToRule = map[string](func() influxdb.NotificationRule){
 	"slack":     func() influxdb.NotificationRule { return &Slack{} },
 	"pagerduty": func() influxdb.NotificationRule { return &PagerDuty{} },
 	"http":      func() influxdb.NotificationRule { return &HTTP{} },
+	"smtp":      func() influxdb.NotificationRule { return &SMTP{} },
 }
 
 // UnmarshalJSON will convert
@@ -58,6 +59,7 @@ type Base struct {
 	TagRules    []notification.TagRule    `json:"tagRules,omitempty"`
 	StatusRules []notification.StatusRule `json:"statusRules,omitempty"`
 	*influxdb.Limit
+	Escalation *influxdb.Escalation `json:"escalation,omitempty"`
 	influxdb.CRUDLog
 }
 
@@ -111,6 +113,11 @@ func (b Base) valid() error {
 			}
 		}
 	}
+	if b.Escalation != nil {
+		if err := b.Escalation.Valid(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -367,6 +374,21 @@ func (b *Base) MatchesTags(tags []influxdb.Tag) bool {
 	return true
 }
 
+// MatchesLevel returns true if a status of the level matches a status rule of the rule,
+// regardless of the level of the previous status.
+func (b *Base) MatchesLevel(level string) bool {
+	if len(b.StatusRules) == 0 {
+		return true
+	}
+	l := notification.ParseCheckLevel(strings.ToUpper(level))
+	for _, r := range b.StatusRules {
+		if r.CurrentLevel == notification.Any || r.CurrentLevel == l {
+			return true
+		}
+	}
+	return false
+}
+
 // GetOwnerID returns the owner id.
 func (b Base) GetOwnerID() influxdb.ID {
 	return b.OwnerID
@@ -382,6 +404,11 @@ func (b *Base) GetLimit() *influxdb.Limit {
 	return b.Limit
 }
 
+// GetEscalation returns the escalation policy of the rule, nil if its alerts are not escalated.
+func (b *Base) GetEscalation() *influxdb.Escalation {
+	return b.Escalation
+}
+
 // GetName implements influxdb.Getter interface.
 func (b *Base) GetName() string {
 	return b.Name
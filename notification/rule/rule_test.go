@@ -197,6 +197,62 @@ func TestValidRule(t *testing.T) {
 				Msg:  `if limit is set, limit and limitEvery must be larger than 0`,
 			},
 		},
+		{
+			name: "smtp without to addresses",
+			src: &rule.SMTP{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Name:       "name1",
+				},
+				SubjectTemplate: "subject",
+				BodyTemplate:    "body",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp to addresses are empty",
+			},
+		},
+		{
+			name: "smtp with invalid cc address",
+			src: &rule.SMTP{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Name:       "name1",
+				},
+				To:              []string{"ops@example.com"},
+				Cc:              []string{"lead"},
+				SubjectTemplate: "subject",
+				BodyTemplate:    "body",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  `smtp address "lead" is invalid: mail: missing '@' or angle-addr`,
+			},
+		},
+		{
+			name: "smtp without body template",
+			src: &rule.SMTP{
+				Base: rule.Base{
+					ID:         influxTesting.MustIDBase16(id1),
+					OwnerID:    influxTesting.MustIDBase16(id2),
+					OrgID:      influxTesting.MustIDBase16(id3),
+					EndpointID: 1,
+					Name:       "name1",
+				},
+				To:              []string{"ops@example.com"},
+				SubjectTemplate: "subject",
+			},
+			err: &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "smtp body template is empty",
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -286,6 +342,32 @@ func TestJSON(t *testing.T) {
 				MessageTemplate: "msg1",
 			},
 		},
+		{
+			name: "smtp with recipients",
+			src: &rule.SMTP{
+				Base: rule.Base{
+					ID:          influxTesting.MustIDBase16(id1),
+					Name:        "name1",
+					OwnerID:     influxTesting.MustIDBase16(id2),
+					OrgID:       influxTesting.MustIDBase16(id3),
+					RunbookLink: "runbooklink1",
+					Every:       mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: timeGen1.Now(),
+						UpdatedAt: timeGen2.Now(),
+					},
+				},
+				To:              []string{"ops@example.com", "oncall@example.com"},
+				Cc:              []string{"lead@example.com"},
+				SubjectTemplate: "subject1",
+				BodyTemplate:    "body1",
+			},
+		},
 		{
 			name: "simple pagerDuty",
 			src: &rule.PagerDuty{
@@ -0,0 +1,181 @@
+package rule_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/notification/rule"
+)
+
+func TestSMTP_GenerateFlux(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     string
+		rule     *rule.SMTP
+		endpoint *endpoint.SMTP
+	}{
+		{
+			name: "without auth",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/smtp"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+smtp_endpoint = smtp.endpoint(
+	host: "smtp.example.com",
+	port: 25,
+	tls: "none",
+	from: "influxdb@example.com",
+)
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h, fn: (r) =>
+	(r.foo == "bar"))
+crit = statuses
+	|> filter(fn: (r) =>
+		(r._level == "crit"))
+all_statuses = crit
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: smtp_endpoint(mapFn: (r) =>
+		({
+			to: "ops@example.com, oncall@example.com",
+			cc: "",
+			subject: "${r._check_name} is ${r._level}",
+			body: "${r._message}",
+		})))`,
+			rule: &rule.SMTP{
+				To:              []string{"ops@example.com", "oncall@example.com"},
+				SubjectTemplate: "${r._check_name} is ${r._level}",
+				BodyTemplate:    "${r._message}",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 2,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					TagRules: []notification.TagRule{
+						{
+							Tag: influxdb.Tag{
+								Key:   "foo",
+								Value: "bar",
+							},
+							Operator: influxdb.Equal,
+						},
+					},
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Critical,
+						},
+					},
+				},
+			},
+			endpoint: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				Host: "smtp.example.com",
+				Port: 25,
+				TLS:  endpoint.SMTPTLSNone,
+				From: "influxdb@example.com",
+			},
+		},
+		{
+			name: "with auth and cc",
+			want: `package main
+// foo
+import "influxdata/influxdb/monitor"
+import "influxdata/influxdb/smtp"
+import "influxdata/influxdb/secrets"
+import "experimental"
+
+option task = {name: "foo", every: 1h}
+
+smtp_username = secrets.get(key: "smtp-username")
+smtp_password = secrets.get(key: "smtp-password")
+smtp_endpoint = smtp.endpoint(
+	host: "smtp.example.com",
+	port: 587,
+	tls: "starttls",
+	username: smtp_username,
+	password: smtp_password,
+	from: "InfluxDB <influxdb@example.com>",
+)
+notification = {
+	_notification_rule_id: "0000000000000001",
+	_notification_rule_name: "foo",
+	_notification_endpoint_id: "0000000000000002",
+	_notification_endpoint_name: "foo",
+}
+statuses = monitor.from(start: -2h)
+any = statuses
+	|> filter(fn: (r) =>
+		(true))
+all_statuses = any
+	|> filter(fn: (r) =>
+		(r._time > experimental.subDuration(from: now(), d: 1h)))
+
+all_statuses
+	|> monitor.notify(data: notification, endpoint: smtp_endpoint(mapFn: (r) =>
+		({
+			to: "ops@example.com",
+			cc: "lead@example.com",
+			subject: "subject",
+			body: "body",
+		})))`,
+			rule: &rule.SMTP{
+				To:              []string{"ops@example.com"},
+				Cc:              []string{"lead@example.com"},
+				SubjectTemplate: "subject",
+				BodyTemplate:    "body",
+				Base: rule.Base{
+					ID:         1,
+					EndpointID: 2,
+					Name:       "foo",
+					Every:      mustDuration("1h"),
+					StatusRules: []notification.StatusRule{
+						{
+							CurrentLevel: notification.Any,
+						},
+					},
+				},
+			},
+			endpoint: &endpoint.SMTP{
+				Base: endpoint.Base{
+					ID:   idPtr(2),
+					Name: "foo",
+				},
+				Host:     "smtp.example.com",
+				Port:     587,
+				TLS:      endpoint.SMTPTLSStartTLS,
+				Username: influxdb.SecretField{Key: "smtp-username"},
+				Password: influxdb.SecretField{Key: "smtp-password"},
+				From:     "InfluxDB <influxdb@example.com>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.rule.GenerateFlux(tt.endpoint)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if f != tt.want {
+				t.Errorf("scripts did not match. want:\n%v\n\ngot:\n%v", tt.want, f)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package rule
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+)
+
+// parseTemplate parses a template into a Flux string expression.
+// A template is text with ${expression} interpolations of the values of the status
+// record bound to r, for example "${r._check_name} is ${r._level}: ${r._message}".
+// An interpolation is a member access on r, r.name or r["name"], which may be
+// converted to a string with string(v: r.name). No other expressions are allowed.
+func parseTemplate(tmpl string) (*ast.StringExpression, error) {
+	expr := &ast.StringExpression{}
+	for len(tmpl) > 0 {
+		i := strings.Index(tmpl, "${")
+		if i < 0 {
+			expr.Parts = append(expr.Parts, textPart(tmpl))
+			break
+		}
+		if i > 0 {
+			expr.Parts = append(expr.Parts, textPart(tmpl[:i]))
+		}
+		tmpl = tmpl[i+2:]
+
+		j := interpolationEnd(tmpl)
+		if j < 0 {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "template interpolation is missing its closing }",
+			}
+		}
+		e, err := parseExpression(tmpl[:j])
+		if err != nil {
+			return nil, err
+		}
+		expr.Parts = append(expr.Parts, &ast.InterpolatedPart{Expression: e})
+		tmpl = tmpl[j+1:]
+	}
+	return expr, nil
+}
+
+// textPart returns the text part of a string expression that formats to text.
+// The formatter writes text parts as they are, so the text is escaped here.
+func textPart(text string) *ast.TextPart {
+	text = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text)
+	return &ast.TextPart{Value: text}
+}
+
+// interpolationEnd returns the index of the } closing the interpolation s starts in,
+// skipping the braces of nested objects and of string literals. -1 is returned if
+// the interpolation is not closed.
+func interpolationEnd(s string) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+func parseExpression(src string) (ast.Expression, error) {
+	invalid := func(msg string) error {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid template interpolation ${%s}: %s", src, msg),
+		}
+	}
+
+	pkg := parser.ParseSource(src)
+	if ast.Check(pkg) > 0 {
+		return nil, invalid(ast.GetError(pkg).Error())
+	}
+	body := pkg.Files[0].Body
+	if len(body) != 1 {
+		return nil, invalid("must be a single expression")
+	}
+	stmt, ok := body[0].(*ast.ExpressionStatement)
+	if !ok {
+		return nil, invalid("must be an expression")
+	}
+	if _, ok := interpolatedField(stmt.Expression); !ok {
+		return nil, invalid(`must be a value of r, such as r.name, r["name"] or string(v: r.name)`)
+	}
+	return stmt.Expression, nil
+}
+
+// interpolatedField returns the field of the status record an interpolation renders.
+// It reports false if the interpolation is not a member access on r, optionally
+// converted with string().
+func interpolatedField(e ast.Expression) (string, bool) {
+	if call, ok := e.(*ast.CallExpression); ok {
+		callee, ok := call.Callee.(*ast.Identifier)
+		if !ok || callee.Name != "string" || len(call.Arguments) != 1 {
+			return "", false
+		}
+		args, ok := call.Arguments[0].(*ast.ObjectExpression)
+		if !ok || args.With != nil || len(args.Properties) != 1 || args.Properties[0].Key.Key() != "v" {
+			return "", false
+		}
+		e = args.Properties[0].Value
+	}
+
+	switch e := e.(type) {
+	case *ast.MemberExpression:
+		if obj, ok := e.Object.(*ast.Identifier); ok && obj.Name == "r" {
+			return e.Property.Key(), true
+		}
+	case *ast.IndexExpression:
+		obj, ok := e.Array.(*ast.Identifier)
+		idx, isString := e.Index.(*ast.StringLiteral)
+		if ok && isString && obj.Name == "r" {
+			return idx.Value, true
+		}
+	}
+	return "", false
+}
+
+// templateFields returns the fields of the status record a parsed template renders.
+func templateFields(expr *ast.StringExpression) []string {
+	var fields []string
+	for _, part := range expr.Parts {
+		if p, ok := part.(*ast.InterpolatedPart); ok {
+			if f, ok := interpolatedField(p.Expression); ok {
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
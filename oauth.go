@@ -0,0 +1,19 @@
+package influxdb
+
+import "context"
+
+// OpOAuthLogin represents the operation that logs a user in through an identity provider.
+const OpOAuthLogin = "OAuthLogin"
+
+// OAuthService logs users in through the OAuth2 authorization code flow
+// of an identity provider, optionally extended with OpenID Connect.
+type OAuthService interface {
+	// AuthCodeURL returns the URL of the identity provider the user is sent to for logging in.
+	// The state is handed back along with the authorization code and the nonce is
+	// embedded in the ID token issued for it.
+	AuthCodeURL(state, nonce string) string
+	// Login exchanges an authorization code for the identity of the user and returns
+	// the matching user, provisioning it and its organization memberships and roles
+	// from the claims of the identity provider.
+	Login(ctx context.Context, code, nonce string) (*User, error)
+}
@@ -0,0 +1,95 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultUsernameClaim is the claim the name of the user is read from when none is configured.
+	DefaultUsernameClaim = "preferred_username"
+	// DefaultGroupsClaim is the claim the groups of the user are read from when none is configured.
+	DefaultGroupsClaim = "groups"
+)
+
+// DefaultScopes are the scopes requested when none are configured.
+var DefaultScopes = []string{"openid", "profile", "email"}
+
+// Config configures the identity provider users log in with.
+type Config struct {
+	// Issuer is the URL of an OpenID Connect provider. When set, the endpoints
+	// that are not configured are discovered from its provider configuration.
+	Issuer string
+
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+	JWKSURL     string
+
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the URL of the callback the identity provider sends users back to,
+	// usually https://<host>/api/v2/signin/oauth/callback.
+	RedirectURL string
+	Scopes      []string
+
+	// UsernameClaim is the claim the user name is read from. Logins without
+	// the claim fail, and an email claim is only used once it is verified.
+	UsernameClaim string
+	// GroupsClaim is the claim holding the groups of the user.
+	GroupsClaim string
+	// GroupMappings are applied on every login. They only ever add memberships
+	// and roles, leaving a group does not revoke what its mappings granted.
+	GroupMappings []GroupMapping
+
+	// AutoProvision creates users that log in for the first time.
+	// Otherwise only users linked to the identity provider are allowed to log in.
+	AutoProvision bool
+}
+
+func (c Config) usernameClaim() string {
+	if c.UsernameClaim == "" {
+		return DefaultUsernameClaim
+	}
+	return c.UsernameClaim
+}
+
+func (c Config) groupsClaim() string {
+	if c.GroupsClaim == "" {
+		return DefaultGroupsClaim
+	}
+	return c.GroupsClaim
+}
+
+func (c Config) scopes() []string {
+	if len(c.Scopes) == 0 {
+		return DefaultScopes
+	}
+	return c.Scopes
+}
+
+// GroupMapping grants the members of a group of the identity provider a role in an organization.
+// The built-in owner and member roles make the user an owner or member of the organization.
+type GroupMapping struct {
+	Group string
+	Org   string
+	Role  string
+}
+
+// ParseGroupMapping parses a group mapping of the form <group>:<org name>:<role name>.
+// The group name may itself contain colons.
+func ParseGroupMapping(s string) (GroupMapping, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 3 {
+		return GroupMapping{}, fmt.Errorf("invalid group mapping %q, expected <group>:<org>:<role>", s)
+	}
+	m := GroupMapping{
+		Group: strings.Join(parts[:len(parts)-2], ":"),
+		Org:   parts[len(parts)-2],
+		Role:  parts[len(parts)-1],
+	}
+	if m.Group == "" || m.Org == "" || m.Role == "" {
+		return GroupMapping{}, fmt.Errorf("invalid group mapping %q, expected <group>:<org>:<role>", s)
+	}
+	return m, nil
+}
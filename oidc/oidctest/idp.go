@@ -0,0 +1,259 @@
+// Package oidctest provides an in-process OpenID Connect identity provider for tests.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/influxdata/influxdb/oidc"
+)
+
+const keyID = "oidctest"
+
+// IdP is a fake identity provider. Every authorization request is granted
+// for the user whose claims were last set with SetUser.
+type IdP struct {
+	*httptest.Server
+
+	ClientID     string
+	ClientSecret string
+
+	// OmitIDToken makes the provider behave as a plain OAuth2 provider,
+	// identifying users through its user info endpoint only.
+	OmitIDToken bool
+
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	claims map[string]interface{}
+	codes  map[string]grant
+	tokens map[string]map[string]interface{}
+	n      int
+}
+
+type grant struct {
+	redirectURI string
+	nonce       string
+	claims      map[string]interface{}
+}
+
+// NewIdP starts an identity provider for the client. Close it when done.
+func NewIdP(clientID, clientSecret string) *IdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	p := &IdP{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		claims:       map[string]interface{}{},
+		codes:        map[string]grant{},
+		tokens:       map[string]map[string]interface{}{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.handleDiscovery)
+	mux.HandleFunc("/authorize", p.handleAuthorize)
+	mux.HandleFunc("/token", p.handleToken)
+	mux.HandleFunc("/keys", p.handleKeys)
+	mux.HandleFunc("/userinfo", p.handleUserInfo)
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+// Config returns the config of a service logging in with the identity provider.
+func (p *IdP) Config(redirectURL string) oidc.Config {
+	return oidc.Config{
+		Issuer:       p.URL,
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		RedirectURL:  redirectURL,
+	}
+}
+
+// SetUser sets the claims of the user the next authorization requests are granted for.
+// The issuer, audience and expiry claims are filled in by the provider,
+// claims set to nil are left out of the ID token.
+func (p *IdP) SetUser(claims map[string]interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.claims = claims
+}
+
+// Authorize follows the authorization URL as a browser would and returns
+// the state and authorization code the provider redirects back with.
+func (p *IdP) Authorize(authCodeURL string) (state, code string, err error) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authCodeURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("authorization failed with %s", resp.Status)
+	}
+
+	u, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	return u.Query().Get("state"), u.Query().Get("code"), nil
+}
+
+func (p *IdP) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 p.URL,
+		"authorization_endpoint": p.URL + "/authorize",
+		"token_endpoint":         p.URL + "/token",
+		"userinfo_endpoint":      p.URL + "/userinfo",
+		"jwks_uri":               p.URL + "/keys",
+	})
+}
+
+func (p *IdP) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != p.ClientID || q.Get("response_type") != "code" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirectURI.String() == "" {
+		http.Error(w, "invalid redirect uri", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	p.n++
+	code := fmt.Sprintf("code-%d", p.n)
+	p.codes[code] = grant{
+		redirectURI: redirectURI.String(),
+		nonce:       q.Get("nonce"),
+		claims:      p.claims,
+	}
+	p.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *IdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if id != p.ClientID || secret != p.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	g, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != g.redirectURI {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	p.mu.Lock()
+	p.n++
+	accessToken := fmt.Sprintf("token-%d", p.n)
+	p.tokens[accessToken] = g.claims
+	p.mu.Unlock()
+
+	resp := map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	}
+	if !p.OmitIDToken {
+		idToken, err := p.IDToken(g.claims, g.nonce)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+			return
+		}
+		resp["id_token"] = idToken
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// IDToken returns an ID token for the claims signed by the provider.
+func (p *IdP) IDToken(claims map[string]interface{}, nonce string) (string, error) {
+	now := time.Now()
+	c := jwt.MapClaims{
+		"iss": p.URL,
+		"aud": p.ClientID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	if nonce != "" {
+		c["nonce"] = nonce
+	}
+	for k, v := range claims {
+		if v == nil {
+			delete(c, k)
+			continue
+		}
+		c[k] = v
+	}
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+	tok.Header["kid"] = keyID
+	return tok.SignedString(p.key)
+}
+
+func (p *IdP) handleKeys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, oidc.JWKS{
+		Keys: []oidc.JWK{{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: keyID,
+			N:   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *IdP) handleUserInfo(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	p.mu.Lock()
+	claims, ok := p.tokens[token]
+	p.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, http.StatusOK, claims)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,124 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// provision finds the user with the name, creating it when auto-provisioning
+// is enabled, and grants it the roles its groups are mapped to. Only users that
+// were provisioned for the subject or linked to it log in, so that the identity
+// provider can not be used to log in as a user with a password.
+// Group mappings only ever add to what the user was granted; memberships and
+// roles granted otherwise, or by groups the user left, are left as they are.
+func (s *Service) provision(ctx context.Context, name, subject string, groups []string) (*influxdb.User, error) {
+	u, err := s.userSVC.FindUser(ctx, influxdb.UserFilter{Name: &name})
+	switch {
+	case influxdb.ErrorCode(err) == influxdb.ENotFound && s.config.AutoProvision:
+		u = &influxdb.User{Name: name, OAuthID: subject}
+		if err := s.userSVC.CreateUser(ctx, u); err != nil {
+			return nil, err
+		}
+		s.log.Info("Provisioned user", zap.String("user", name), zap.Stringer("id", u.ID))
+	case influxdb.ErrorCode(err) == influxdb.ENotFound:
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Op:   influxdb.OpOAuthLogin,
+			Msg:  fmt.Sprintf("user %s does not exist", name),
+		}
+	case err != nil:
+		return nil, err
+	case u.OAuthID != subject:
+		s.log.Info("Refused to log in a user not linked to the identity provider", zap.String("user", name), zap.String("subject", subject))
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Op:   influxdb.OpOAuthLogin,
+			Msg:  fmt.Sprintf("user %s is not linked to the identity provider", name),
+		}
+	}
+
+	if u.Status == influxdb.Inactive {
+		return nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Op:   influxdb.OpOAuthLogin,
+			Msg:  "user is inactive",
+		}
+	}
+
+	member := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		member[g] = true
+	}
+	for _, m := range s.config.GroupMappings {
+		if !member[m.Group] {
+			continue
+		}
+		if err := s.grant(ctx, u.ID, m); err != nil {
+			s.log.Warn("Failed to apply group mapping",
+				zap.String("user", name),
+				zap.String("group", m.Group),
+				zap.String("org", m.Org),
+				zap.String("role", m.Role),
+				zap.Error(err))
+		}
+	}
+	return u, nil
+}
+
+// grant grants the user the role of the mapping in its organization.
+func (s *Service) grant(ctx context.Context, userID influxdb.ID, m GroupMapping) error {
+	org, err := s.orgSVC.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &m.Org})
+	if err != nil {
+		return err
+	}
+
+	switch m.Role {
+	case influxdb.OwnerRoleName:
+		return s.addToOrg(ctx, userID, org.ID, influxdb.Owner)
+	case influxdb.MemberRoleName:
+		return s.addToOrg(ctx, userID, org.ID, influxdb.Member)
+	}
+
+	rs, _, err := s.roleSVC.FindRoles(ctx, influxdb.RoleFilter{OrgID: &org.ID, Name: &m.Role})
+	if err != nil {
+		return err
+	}
+	if len(rs) == 0 {
+		return &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  fmt.Sprintf("role %s not found in organization %s", m.Role, m.Org),
+		}
+	}
+	return s.roleSVC.AssignRole(ctx, &influxdb.RoleAssignment{RoleID: rs[0].ID, UserID: userID})
+}
+
+// addToOrg makes the user an owner or member of the organization, promoting
+// members to owners but never demoting owners.
+func (s *Service) addToOrg(ctx context.Context, userID, orgID influxdb.ID, userType influxdb.UserType) error {
+	ms, _, err := s.urmSVC.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   orgID,
+		UserID:       userID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(ms) > 0 {
+		if ms[0].UserType == influxdb.Owner || userType == influxdb.Member {
+			return nil
+		}
+		if err := s.urmSVC.DeleteUserResourceMapping(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	return s.urmSVC.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   orgID,
+		UserID:       userID,
+		UserType:     userType,
+	})
+}
@@ -0,0 +1,372 @@
+// Package oidc logs users in through the OAuth2 authorization code flow of an
+// identity provider, verifying OpenID Connect ID tokens when the provider issues them.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+type serviceOpt struct {
+	logger *zap.Logger
+	client *http.Client
+
+	userSVC influxdb.UserService
+	orgSVC  influxdb.OrganizationService
+	urmSVC  influxdb.UserResourceMappingService
+	roleSVC influxdb.RoleService
+}
+
+// ServiceSetterFn is a means of setting dependencies on the Service type.
+type ServiceSetterFn func(opt *serviceOpt)
+
+// WithLogger sets the logger for the service.
+func WithLogger(log *zap.Logger) ServiceSetterFn {
+	return func(o *serviceOpt) {
+		o.logger = log
+	}
+}
+
+// WithHTTPClient sets the client used to talk to the identity provider.
+func WithHTTPClient(client *http.Client) ServiceSetterFn {
+	return func(o *serviceOpt) {
+		o.client = client
+	}
+}
+
+// WithUserSVC sets the user service users are looked up and provisioned with.
+func WithUserSVC(userSVC influxdb.UserService) ServiceSetterFn {
+	return func(o *serviceOpt) {
+		o.userSVC = userSVC
+	}
+}
+
+// WithOrganizationSVC sets the organization service group mappings are resolved with.
+func WithOrganizationSVC(orgSVC influxdb.OrganizationService) ServiceSetterFn {
+	return func(o *serviceOpt) {
+		o.orgSVC = orgSVC
+	}
+}
+
+// WithUserResourceMappingSVC sets the service organization owners and members are added with.
+func WithUserResourceMappingSVC(urmSVC influxdb.UserResourceMappingService) ServiceSetterFn {
+	return func(o *serviceOpt) {
+		o.urmSVC = urmSVC
+	}
+}
+
+// WithRoleSVC sets the service custom roles are assigned with.
+func WithRoleSVC(roleSVC influxdb.RoleService) ServiceSetterFn {
+	return func(o *serviceOpt) {
+		o.roleSVC = roleSVC
+	}
+}
+
+// Service logs users in with an identity provider.
+type Service struct {
+	log    *zap.Logger
+	config Config
+	oauth2 *oauth2.Config
+	client *http.Client
+	keys   *keySet
+
+	userSVC influxdb.UserService
+	orgSVC  influxdb.OrganizationService
+	urmSVC  influxdb.UserResourceMappingService
+	roleSVC influxdb.RoleService
+}
+
+var _ influxdb.OAuthService = (*Service)(nil)
+
+// NewService creates a service for the identity provider of the config,
+// discovering the endpoints of the provider when an issuer is configured.
+func NewService(ctx context.Context, config Config, opts ...ServiceSetterFn) (*Service, error) {
+	opt := &serviceOpt{
+		logger: zap.NewNop(),
+		client: http.DefaultClient,
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	if config.ClientID == "" {
+		return nil, fmt.Errorf("a client id is required")
+	}
+	if opt.userSVC == nil {
+		return nil, fmt.Errorf("a user service is required")
+	}
+	if len(config.GroupMappings) > 0 && (opt.orgSVC == nil || opt.urmSVC == nil || opt.roleSVC == nil) {
+		return nil, fmt.Errorf("organization, user resource mapping and role services are required to map groups")
+	}
+	if config.Issuer != "" {
+		if err := discover(ctx, opt.client, &config); err != nil {
+			return nil, err
+		}
+	}
+	if config.AuthURL == "" || config.TokenURL == "" {
+		return nil, fmt.Errorf("the authorization and token endpoints of the identity provider are required")
+	}
+
+	return &Service{
+		log:    opt.logger,
+		config: config,
+		oauth2: &oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			RedirectURL:  config.RedirectURL,
+			Scopes:       config.scopes(),
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  config.AuthURL,
+				TokenURL: config.TokenURL,
+			},
+		},
+		client:  opt.client,
+		keys:    &keySet{url: config.JWKSURL, client: opt.client},
+		userSVC: opt.userSVC,
+		orgSVC:  opt.orgSVC,
+		urmSVC:  opt.urmSVC,
+		roleSVC: opt.roleSVC,
+	}, nil
+}
+
+type providerConfig struct {
+	Issuer      string `json:"issuer"`
+	AuthURL     string `json:"authorization_endpoint"`
+	TokenURL    string `json:"token_endpoint"`
+	UserInfoURL string `json:"userinfo_endpoint"`
+	JWKSURL     string `json:"jwks_uri"`
+}
+
+// discover fills in the endpoints missing from the config with those
+// published in the provider configuration of the issuer.
+func discover(ctx context.Context, client *http.Client, config *Config) error {
+	url := strings.TrimSuffix(config.Issuer, "/") + discoveryPath
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to discover the identity provider: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to discover the identity provider: %s returned %s", url, resp.Status)
+	}
+
+	var pc providerConfig
+	if err := json.NewDecoder(resp.Body).Decode(&pc); err != nil {
+		return fmt.Errorf("unable to decode the provider configuration: %v", err)
+	}
+	if pc.Issuer != config.Issuer {
+		return fmt.Errorf("the provider configuration is for issuer %q, expected %q", pc.Issuer, config.Issuer)
+	}
+
+	set := func(v *string, discovered string) {
+		if *v == "" {
+			*v = discovered
+		}
+	}
+	set(&config.AuthURL, pc.AuthURL)
+	set(&config.TokenURL, pc.TokenURL)
+	set(&config.UserInfoURL, pc.UserInfoURL)
+	set(&config.JWKSURL, pc.JWKSURL)
+	return nil
+}
+
+// AuthCodeURL returns the URL of the identity provider the user is sent to for logging in.
+func (s *Service) AuthCodeURL(state, nonce string) string {
+	var opts []oauth2.AuthCodeOption
+	if nonce != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("nonce", nonce))
+	}
+	return s.oauth2.AuthCodeURL(state, opts...)
+}
+
+// Login exchanges the authorization code for the claims of the user and
+// returns the user they identify, provisioning it as configured.
+func (s *Service) Login(ctx context.Context, code, nonce string) (*influxdb.User, error) {
+	claims, err := s.claims(ctx, code, nonce)
+	if err != nil {
+		s.log.Info("Failed to verify the identity of the user", zap.Error(err))
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Op:   influxdb.OpOAuthLogin,
+			Msg:  "unable to verify the identity of the user",
+			Err:  err,
+		}
+	}
+
+	name, err := username(claims, s.config.usernameClaim())
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Op:   influxdb.OpOAuthLogin,
+			Msg:  err.Error(),
+		}
+	}
+	subject, ok := claims["sub"].(string)
+	if !ok || subject == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnauthorized,
+			Op:   influxdb.OpOAuthLogin,
+			Msg:  "the identity provider did not return the subject of the user",
+		}
+	}
+	return s.provision(ctx, name, subject, groups(claims, s.config.groupsClaim()))
+}
+
+// claims exchanges the authorization code for a token and returns the claims
+// of its ID token, completed with those of the user info endpoint.
+func (s *Service) claims(ctx context.Context, code, nonce string) (map[string]interface{}, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, s.client)
+	tok, err := s.oauth2.Exchange(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	if raw, ok := tok.Extra("id_token").(string); ok && raw != "" {
+		if claims, err = s.verifyIDToken(ctx, raw, nonce); err != nil {
+			return nil, err
+		}
+	} else if s.config.UserInfoURL == "" {
+		return nil, fmt.Errorf("the identity provider did not issue an ID token and has no user info endpoint")
+	}
+
+	if s.config.UserInfoURL != "" {
+		info, err := s.userInfo(ctx, tok)
+		if err != nil {
+			return nil, err
+		}
+		if sub, ok := claims["sub"]; ok && info["sub"] != sub {
+			return nil, fmt.Errorf("the user info is for another subject than the ID token")
+		}
+		for k, v := range info {
+			if _, ok := claims[k]; !ok {
+				claims[k] = v
+			}
+		}
+	}
+	return claims, nil
+}
+
+func (s *Service) verifyIDToken(ctx context.Context, raw, nonce string) (map[string]interface{}, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA:
+			kid, _ := t.Header["kid"].(string)
+			return s.keys.key(ctx, kid)
+		case *jwt.SigningMethodHMAC:
+			if s.config.ClientSecret == "" {
+				return nil, fmt.Errorf("a client secret is required to verify %v signatures", t.Header["alg"])
+			}
+			return []byte(s.config.ClientSecret), nil
+		}
+		return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %v", err)
+	}
+	// the expiry is only verified when present, an ID token must have one.
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("the ID token has no expiry")
+	}
+
+	if s.config.Issuer != "" && claims["iss"] != s.config.Issuer {
+		return nil, fmt.Errorf("the ID token was issued by %v, expected %s", claims["iss"], s.config.Issuer)
+	}
+	if !audience(claims["aud"], s.config.ClientID) {
+		return nil, fmt.Errorf("the ID token was not issued for client %s", s.config.ClientID)
+	}
+	if nonce != "" && claims["nonce"] != nonce {
+		return nil, fmt.Errorf("the nonce of the ID token does not match")
+	}
+	return claims, nil
+}
+
+func (s *Service) userInfo(ctx context.Context, tok *oauth2.Token) (map[string]interface{}, error) {
+	req, err := http.NewRequest("GET", s.config.UserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.oauth2.Client(ctx, tok).Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the user info endpoint returned %s", resp.Status)
+	}
+
+	info := map[string]interface{}{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("unable to decode the user info: %v", err)
+	}
+	return info, nil
+}
+
+// audience reports whether the aud claim, either a string or a list of strings, contains the client.
+func audience(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// username returns the user name of the claim. An email is only used
+// as the user name once the identity provider verified it.
+func username(claims map[string]interface{}, claim string) (string, error) {
+	name, ok := claims[claim].(string)
+	if !ok || name == "" {
+		return "", fmt.Errorf("the identity provider did not return the %s claim", claim)
+	}
+	if claim == "email" {
+		// some providers return the verification as a string.
+		switch claims["email_verified"] {
+		case true, "true":
+		default:
+			return "", fmt.Errorf("the identity provider did not verify the email %s", name)
+		}
+	}
+	return name, nil
+}
+
+// groups returns the groups of the claim, either a list of strings or a comma separated string.
+func groups(claims map[string]interface{}, claim string) []string {
+	var gs []string
+	switch v := claims[claim].(type) {
+	case string:
+		for _, g := range strings.Split(v, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				gs = append(gs, g)
+			}
+		}
+	case []interface{}:
+		for _, g := range v {
+			if g, ok := g.(string); ok {
+				gs = append(gs, g)
+			}
+		}
+	}
+	return gs
+}
@@ -0,0 +1,347 @@
+package oidc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/oidc"
+	"github.com/influxdata/influxdb/oidc/oidctest"
+	"go.uber.org/zap/zaptest"
+)
+
+func newService(t *testing.T, idp *oidctest.IdP, update func(*oidc.Config)) (*oidc.Service, *kv.Service) {
+	t.Helper()
+
+	ctx := context.Background()
+	store := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := store.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	config := idp.Config("http://localhost:9999/api/v2/signin/oauth/callback")
+	if update != nil {
+		update(&config)
+	}
+	svc, err := oidc.NewService(ctx, config,
+		oidc.WithLogger(zaptest.NewLogger(t)),
+		oidc.WithUserSVC(store),
+		oidc.WithOrganizationSVC(store),
+		oidc.WithUserResourceMappingSVC(store),
+		oidc.WithRoleSVC(store),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, store
+}
+
+func login(t *testing.T, idp *oidctest.IdP, svc *oidc.Service, nonce string) (*influxdb.User, error) {
+	t.Helper()
+
+	state, code, err := idp.Authorize(svc.AuthCodeURL("state", nonce))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != "state" {
+		t.Fatalf("expected the state to be handed back, got %q", state)
+	}
+	return svc.Login(context.Background(), code, nonce)
+}
+
+func TestService_Login(t *testing.T) {
+	idp := oidctest.NewIdP("influxdb", "secret")
+	defer idp.Close()
+
+	svc, store := newService(t, idp, func(c *oidc.Config) {
+		c.AutoProvision = true
+	})
+	idp.SetUser(map[string]interface{}{
+		"sub":                "1234",
+		"preferred_username": "jdoe",
+	})
+
+	u, err := login(t, idp, svc, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "jdoe" || !u.ID.Valid() {
+		t.Fatalf("expected user jdoe to be provisioned, got %+v", u)
+	}
+
+	again, err := login(t, idp, svc, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != u.ID {
+		t.Errorf("expected the provisioned user on the second login, got %+v", again)
+	}
+	if _, n, _ := store.FindUsers(context.Background(), influxdb.UserFilter{}); n != 1 {
+		t.Errorf("expected a single user, got %d", n)
+	}
+}
+
+func TestService_Login_WithoutAutoProvision(t *testing.T) {
+	idp := oidctest.NewIdP("influxdb", "secret")
+	defer idp.Close()
+
+	svc, store := newService(t, idp, nil)
+	idp.SetUser(map[string]interface{}{"sub": "1234", "preferred_username": "jdoe"})
+
+	if _, err := login(t, idp, svc, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Fatalf("expected an unknown user to be unauthorized, got %v", err)
+	}
+
+	// users with the name are not logged in until they are linked to the subject.
+	ctx := context.Background()
+	existing := &influxdb.User{Name: "jdoe"}
+	if err := store.CreateUser(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := login(t, idp, svc, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Fatalf("expected a user not linked to the identity provider to be unauthorized, got %v", err)
+	}
+
+	other := "5678"
+	if _, err := store.UpdateUser(ctx, existing.ID, influxdb.UserUpdate{OAuthID: &other}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := login(t, idp, svc, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Fatalf("expected a user linked to another subject to be unauthorized, got %v", err)
+	}
+
+	subject := "1234"
+	if _, err := store.UpdateUser(ctx, existing.ID, influxdb.UserUpdate{OAuthID: &subject}); err != nil {
+		t.Fatal(err)
+	}
+	u, err := login(t, idp, svc, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != existing.ID {
+		t.Errorf("expected the linked user, got %+v", u)
+	}
+}
+
+func TestService_Login_ExistingUser(t *testing.T) {
+	idp := oidctest.NewIdP("influxdb", "secret")
+	defer idp.Close()
+
+	svc, store := newService(t, idp, func(c *oidc.Config) {
+		c.AutoProvision = true
+	})
+	admin := &influxdb.User{Name: "admin"}
+	if err := store.CreateUser(context.Background(), admin); err != nil {
+		t.Fatal(err)
+	}
+
+	idp.SetUser(map[string]interface{}{"sub": "1234", "preferred_username": "admin"})
+	if _, err := login(t, idp, svc, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected a user created otherwise not to be logged in, got %v", err)
+	}
+}
+
+func TestService_Login_UsernameClaim(t *testing.T) {
+	idp := oidctest.NewIdP("influxdb", "secret")
+	defer idp.Close()
+
+	svc, _ := newService(t, idp, func(c *oidc.Config) {
+		c.AutoProvision = true
+	})
+	idp.SetUser(map[string]interface{}{"sub": "1234", "email": "jdoe@example.com", "email_verified": true})
+	if _, err := login(t, idp, svc, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected a login without the user name claim to be unauthorized, got %v", err)
+	}
+
+	idp.SetUser(map[string]interface{}{"preferred_username": "jdoe"})
+	if _, err := login(t, idp, svc, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected a login without a subject to be unauthorized, got %v", err)
+	}
+
+	svc, _ = newService(t, idp, func(c *oidc.Config) {
+		c.AutoProvision = true
+		c.UsernameClaim = "email"
+	})
+	idp.SetUser(map[string]interface{}{"sub": "1234", "email": "jdoe@example.com", "email_verified": false})
+	if _, err := login(t, idp, svc, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected a login with an unverified email to be unauthorized, got %v", err)
+	}
+
+	idp.SetUser(map[string]interface{}{"sub": "1234", "email": "jdoe@example.com", "email_verified": true})
+	u, err := login(t, idp, svc, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "jdoe@example.com" {
+		t.Errorf("expected the user to be named by its verified email, got %+v", u)
+	}
+}
+
+func TestService_Login_UserInfo(t *testing.T) {
+	idp := oidctest.NewIdP("influxdb", "secret")
+	defer idp.Close()
+	idp.OmitIDToken = true
+
+	svc, _ := newService(t, idp, func(c *oidc.Config) {
+		c.AutoProvision = true
+	})
+	idp.SetUser(map[string]interface{}{"sub": "1234", "preferred_username": "jdoe"})
+
+	u, err := login(t, idp, svc, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name != "jdoe" {
+		t.Errorf("expected the user to be identified through the user info endpoint, got %+v", u)
+	}
+}
+
+func TestService_Login_InvalidIDToken(t *testing.T) {
+	idp := oidctest.NewIdP("influxdb", "secret")
+	defer idp.Close()
+
+	svc, _ := newService(t, idp, func(c *oidc.Config) {
+		c.AutoProvision = true
+	})
+	idp.SetUser(map[string]interface{}{"sub": "1234", "preferred_username": "jdoe"})
+
+	state, code, err := idp.Authorize(svc.AuthCodeURL("state", "nonce"))
+	if err != nil || state != "state" {
+		t.Fatalf("unexpected authorization: %q %v", state, err)
+	}
+	if _, err := svc.Login(context.Background(), code, "other nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected a mismatched nonce to be unauthorized, got %v", err)
+	}
+	if _, err := svc.Login(context.Background(), code, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected a used authorization code to be unauthorized, got %v", err)
+	}
+
+	// Tokens signed by another provider do not verify with the keys of the issuer.
+	other := oidctest.NewIdP("influxdb", "secret")
+	defer other.Close()
+	svc, _ = newService(t, idp, func(c *oidc.Config) {
+		c.AutoProvision = true
+		c.AuthURL = other.URL + "/authorize"
+		c.TokenURL = other.URL + "/token"
+	})
+	other.SetUser(map[string]interface{}{"sub": "1234", "preferred_username": "jdoe"})
+	if _, err := login(t, other, svc, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected an ID token of another provider to be unauthorized, got %v", err)
+	}
+
+	svc, _ = newService(t, idp, func(c *oidc.Config) {
+		c.AutoProvision = true
+	})
+	idp.SetUser(map[string]interface{}{"sub": "1234", "preferred_username": "jdoe", "exp": nil})
+	if _, err := login(t, idp, svc, "nonce"); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected an ID token without expiry to be unauthorized, got %v", err)
+	}
+}
+
+func TestService_Login_GroupMappings(t *testing.T) {
+	idp := oidctest.NewIdP("influxdb", "secret")
+	defer idp.Close()
+
+	svc, store := newService(t, idp, func(c *oidc.Config) {
+		c.AutoProvision = true
+		c.GroupMappings = []oidc.GroupMapping{
+			{Group: "admins", Org: "acme", Role: influxdb.OwnerRoleName},
+			{Group: "engineers", Org: "acme", Role: influxdb.MemberRoleName},
+			{Group: "engineers", Org: "acme", Role: "dashboard editor"},
+			{Group: "sales", Org: "acme", Role: influxdb.MemberRoleName},
+		}
+	})
+
+	ctx := context.Background()
+	org := &influxdb.Organization{Name: "acme"}
+	if err := store.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	editor := &influxdb.Role{
+		OrgID: org.ID,
+		Name:  "dashboard editor",
+		Permissions: []influxdb.Permission{{
+			Action:   influxdb.WriteAction,
+			Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &org.ID},
+		}},
+	}
+	if err := store.CreateRole(ctx, editor); err != nil {
+		t.Fatal(err)
+	}
+
+	idp.SetUser(map[string]interface{}{
+		"sub":                "1234",
+		"preferred_username": "jdoe",
+		"groups":             []string{"engineers"},
+	})
+	u, err := login(t, idp, svc, "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orgFilter := influxdb.UserResourceMappingFilter{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   org.ID,
+		UserID:       u.ID,
+	}
+	ms, _, err := store.FindUserResourceMappings(ctx, orgFilter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].UserType != influxdb.Member {
+		t.Fatalf("expected the engineer to be a member of the organization, got %+v", ms)
+	}
+	if _, n, _ := store.FindRoleAssignments(ctx, influxdb.RoleAssignmentFilter{RoleID: &editor.ID, UserID: &u.ID}); n != 1 {
+		t.Errorf("expected the engineer to be assigned the dashboard editor role, got %d assignments", n)
+	}
+
+	idp.SetUser(map[string]interface{}{
+		"sub":                "1234",
+		"preferred_username": "jdoe",
+		"groups":             []string{"engineers", "admins"},
+	})
+	if _, err := login(t, idp, svc, "nonce"); err != nil {
+		t.Fatal(err)
+	}
+	if ms, _, _ = store.FindUserResourceMappings(ctx, orgFilter); len(ms) != 1 || ms[0].UserType != influxdb.Owner {
+		t.Fatalf("expected the admin to be promoted to owner of the organization, got %+v", ms)
+	}
+
+	idp.SetUser(map[string]interface{}{
+		"sub":                "1234",
+		"preferred_username": "jdoe",
+		"groups":             "sales",
+	})
+	if _, err := login(t, idp, svc, "nonce"); err != nil {
+		t.Fatal(err)
+	}
+	if ms, _, _ = store.FindUserResourceMappings(ctx, orgFilter); len(ms) != 1 || ms[0].UserType != influxdb.Owner {
+		t.Errorf("expected group mappings never to demote an owner, got %+v", ms)
+	}
+}
+
+func TestParseGroupMapping(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    oidc.GroupMapping
+		wantErr bool
+	}{
+		{in: "admins:acme:owner", want: oidc.GroupMapping{Group: "admins", Org: "acme", Role: "owner"}},
+		{in: "cn=admins:acme:dashboard editor", want: oidc.GroupMapping{Group: "cn=admins", Org: "acme", Role: "dashboard editor"}},
+		{in: "urn:groups:admins:acme:member", want: oidc.GroupMapping{Group: "urn:groups:admins", Org: "acme", Role: "member"}},
+		{in: "admins:acme", wantErr: true},
+		{in: "admins::owner", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := oidc.ParseGroupMapping(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseGroupMapping(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseGroupMapping(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
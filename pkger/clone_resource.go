@@ -128,6 +128,32 @@ func checkToObject(ch influxdb.Check, name string) Object {
 			thresholds = append(thresholds, convertThreshold(th))
 		}
 		k.Spec[fieldCheckThresholds] = thresholds
+	case *icheck.Anomaly:
+		k.Type = KindCheckAnomaly
+		assignBase(cT.Base)
+		assignNonZeroFluxDurs(k.Spec, map[string]*notification.Duration{
+			fieldCheckWindow: cT.Window,
+		})
+		k.Spec[fieldCheckMethod] = cT.Method
+		k.Spec[fieldCheckDeviations] = cT.Deviations
+		k.Spec[fieldLevel] = cT.Level.String()
+		assignNonZeroBools(k.Spec, map[string]bool{fieldCheckLastWeek: cT.LastWeek})
+	case *icheck.Rate:
+		k.Type = KindCheckRate
+		assignBase(cT.Base)
+		assignNonZeroFluxDurs(k.Spec, map[string]*notification.Duration{
+			fieldCheckUnit: cT.Unit,
+		})
+		assignNonZeroBools(k.Spec, map[string]bool{fieldCheckNonNegative: cT.NonNegative})
+		var thresholds []Resource
+		for _, th := range cT.Thresholds {
+			thresholds = append(thresholds, convertThreshold(th))
+		}
+		k.Spec[fieldCheckThresholds] = thresholds
+	case *icheck.Absence:
+		k.Type = KindCheckAbsence
+		assignBase(cT.Base)
+		k.Spec[fieldLevel] = cT.Level.String()
 	}
 	return k
 }
@@ -454,6 +480,16 @@ func endpointKind(e influxdb.NotificationEndpoint, name string) Object {
 		assignNonZeroSecrets(k.Spec, map[string]influxdb.SecretField{
 			fieldNotificationEndpointToken: actual.Token,
 		})
+	case *endpoint.SMTP:
+		k.Type = KindNotificationEndpointSMTP
+		k.Spec[fieldNotificationEndpointHost] = actual.Host
+		k.Spec[fieldNotificationEndpointPort] = actual.Port
+		k.Spec[fieldNotificationEndpointTLS] = actual.TLS
+		k.Spec[fieldNotificationEndpointFrom] = actual.From
+		assignNonZeroSecrets(k.Spec, map[string]influxdb.SecretField{
+			fieldNotificationEndpointPassword: actual.Password,
+			fieldNotificationEndpointUsername: actual.Username,
+		})
 	}
 
 	return k
@@ -518,6 +554,14 @@ func ruleToObject(iRule influxdb.NotificationRule, endpointName, name string) Ob
 		assignBase(t.Base)
 		k.Spec[fieldNotificationRuleMessageTemplate] = t.MessageTemplate
 		assignNonZeroStrings(k.Spec, map[string]string{fieldNotificationRuleChannel: t.Channel})
+	case *rule.SMTP:
+		assignBase(t.Base)
+		k.Spec[fieldNotificationRuleTo] = t.To
+		if len(t.Cc) > 0 {
+			k.Spec[fieldNotificationRuleCc] = t.Cc
+		}
+		k.Spec[fieldNotificationRuleSubjectTemplate] = t.SubjectTemplate
+		k.Spec[fieldNotificationRuleBodyTemplate] = t.BodyTemplate
 	}
 
 	return k
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/mail"
 	"net/url"
 	"reflect"
 	"sort"
@@ -23,7 +24,10 @@ const (
 	KindUnknown                       Kind = ""
 	KindBucket                        Kind = "Bucket"
 	KindCheck                         Kind = "Check"
+	KindCheckAbsence                  Kind = "CheckAbsence"
+	KindCheckAnomaly                  Kind = "CheckAnomaly"
 	KindCheckDeadman                  Kind = "CheckDeadman"
+	KindCheckRate                     Kind = "CheckRate"
 	KindCheckThreshold                Kind = "CheckThreshold"
 	KindDashboard                     Kind = "Dashboard"
 	KindLabel                         Kind = "Label"
@@ -31,6 +35,7 @@ const (
 	KindNotificationEndpointHTTP      Kind = "NotificationEndpointHTTP"
 	KindNotificationEndpointPagerDuty Kind = "NotificationEndpointPagerDuty"
 	KindNotificationEndpointSlack     Kind = "NotificationEndpointSlack"
+	KindNotificationEndpointSMTP      Kind = "NotificationEndpointSMTP"
 	KindNotificationRule              Kind = "NotificationRule"
 	KindPackage                       Kind = "Package"
 	KindTask                          Kind = "Task"
@@ -41,7 +46,10 @@ const (
 var kinds = map[Kind]bool{
 	KindBucket:                        true,
 	KindCheck:                         true,
+	KindCheckAbsence:                  true,
+	KindCheckAnomaly:                  true,
 	KindCheckDeadman:                  true,
+	KindCheckRate:                     true,
 	KindCheckThreshold:                true,
 	KindDashboard:                     true,
 	KindLabel:                         true,
@@ -49,6 +57,7 @@ var kinds = map[Kind]bool{
 	KindNotificationEndpointHTTP:      true,
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
+	KindNotificationEndpointSMTP:      true,
 	KindNotificationRule:              true,
 	KindTask:                          true,
 	KindTelegraf:                      true,
@@ -58,13 +67,17 @@ var kinds = map[Kind]bool{
 var kindsUniqByName = map[Kind]bool{
 	KindBucket:                        true,
 	KindCheck:                         true,
+	KindCheckAbsence:                  true,
+	KindCheckAnomaly:                  true,
 	KindCheckDeadman:                  true,
+	KindCheckRate:                     true,
 	KindCheckThreshold:                true,
 	KindLabel:                         true,
 	KindNotificationEndpoint:          true,
 	KindNotificationEndpointHTTP:      true,
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
+	KindNotificationEndpointSMTP:      true,
 	KindVariable:                      true,
 }
 
@@ -98,7 +111,7 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 	switch k {
 	case KindBucket:
 		return influxdb.BucketsResourceType
-	case KindCheck, KindCheckDeadman, KindCheckThreshold:
+	case KindCheck, KindCheckAbsence, KindCheckAnomaly, KindCheckDeadman, KindCheckRate, KindCheckThreshold:
 		return influxdb.ChecksResourceType
 	case KindDashboard:
 		return influxdb.DashboardsResourceType
@@ -107,7 +120,8 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 	case KindNotificationEndpoint,
 		KindNotificationEndpointHTTP,
 		KindNotificationEndpointPagerDuty,
-		KindNotificationEndpointSlack:
+		KindNotificationEndpointSlack,
+		KindNotificationEndpointSMTP:
 		return influxdb.NotificationEndpointResourceType
 	case KindNotificationRule:
 		return influxdb.NotificationRuleResourceType
@@ -409,6 +423,12 @@ type DiffNotificationRule struct {
 	Status          influxdb.Status     `json:"status"`
 	StatusRules     []SummaryStatusRule `json:"statusRules"`
 	TagRules        []SummaryTagRule    `json:"tagRules"`
+
+	// These 4 fields are the recipients and templates of the emails of smtp rules.
+	To              []string `json:"to,omitempty"`
+	Cc              []string `json:"cc,omitempty"`
+	SubjectTemplate string   `json:"subjectTemplate,omitempty"`
+	BodyTemplate    string   `json:"bodyTemplate,omitempty"`
 }
 
 func newDiffNotificationRule(r *notificationRule, iEndpoint influxdb.NotificationEndpoint) DiffNotificationRule {
@@ -422,6 +442,11 @@ func newDiffNotificationRule(r *notificationRule, iEndpoint influxdb.Notificatio
 		Status:          r.Status(),
 		StatusRules:     toSummaryStatusRules(r.statusRules),
 		TagRules:        toSummaryTagRules(r.tagRules),
+
+		To:              r.to,
+		Cc:              r.cc,
+		SubjectTemplate: r.subjTemplate,
+		BodyTemplate:    r.bodyTemplate,
 	}
 	if iEndpoint != nil {
 		sum.EndpointID = SafeID(iEndpoint.GetID())
@@ -695,6 +720,12 @@ type (
 		Status            influxdb.Status     `json:"status"`
 		StatusRules       []SummaryStatusRule `json:"statusRules"`
 		TagRules          []SummaryTagRule    `json:"tagRules"`
+
+		// These 4 fields are the recipients and templates of the emails of smtp rules.
+		To              []string `json:"to,omitempty"`
+		Cc              []string `json:"cc,omitempty"`
+		SubjectTemplate string   `json:"subjectTemplate,omitempty"`
+		BodyTemplate    string   `json:"bodyTemplate,omitempty"`
 	}
 
 	SummaryStatusRule struct {
@@ -924,16 +955,25 @@ type checkKind int
 const (
 	checkKindDeadman checkKind = iota + 1
 	checkKindThreshold
+	checkKindAnomaly
+	checkKindRate
+	checkKindAbsence
 )
 
 const (
 	fieldCheckAllValues             = "allValues"
+	fieldCheckDeviations            = "deviations"
+	fieldCheckLastWeek              = "lastWeek"
+	fieldCheckMethod                = "method"
+	fieldCheckNonNegative           = "nonNegative"
 	fieldCheckReportZero            = "reportZero"
 	fieldCheckStaleTime             = "staleTime"
 	fieldCheckStatusMessageTemplate = "statusMessageTemplate"
 	fieldCheckTags                  = "tags"
 	fieldCheckThresholds            = "thresholds"
 	fieldCheckTimeSince             = "timeSince"
+	fieldCheckUnit                  = "unit"
+	fieldCheckWindow                = "window"
 )
 
 type check struct {
@@ -954,6 +994,14 @@ type check struct {
 	timeSince     time.Duration
 	thresholds    []threshold
 
+	window     time.Duration
+	method     string
+	deviations float64
+	lastWeek   bool
+
+	unit        time.Duration
+	nonNegative bool
+
 	labels sortedLabels
 
 	existing influxdb.Check
@@ -1023,6 +1071,27 @@ func (c *check) summarize() SummaryCheck {
 			StaleTime:  toNotificationDuration(c.staleTime),
 			TimeSince:  toNotificationDuration(c.timeSince),
 		}
+	case checkKindAnomaly:
+		sum.Check = &icheck.Anomaly{
+			Base:       base,
+			Window:     toNotificationDuration(c.window),
+			Method:     c.method,
+			Deviations: c.deviations,
+			LastWeek:   c.lastWeek,
+			Level:      notification.ParseCheckLevel(strings.ToUpper(c.level)),
+		}
+	case checkKindRate:
+		sum.Check = &icheck.Rate{
+			Base:        base,
+			Unit:        toNotificationDuration(c.unit),
+			NonNegative: c.nonNegative,
+			Thresholds:  toInfluxThresholds(c.thresholds...),
+		}
+	case checkKindAbsence:
+		sum.Check = &icheck.Absence{
+			Base:  base,
+			Level: notification.ParseCheckLevel(strings.ToUpper(c.level)),
+		}
 	}
 	return sum
 }
@@ -1055,7 +1124,7 @@ func (c *check) valid() []validationErr {
 	}
 
 	switch c.kind {
-	case checkKindThreshold:
+	case checkKindThreshold, checkKindRate:
 		if len(c.thresholds) == 0 {
 			vErrs = append(vErrs, validationErr{
 				Field: fieldCheckThresholds,
@@ -1068,10 +1137,49 @@ func (c *check) valid() []validationErr {
 				vErrs = append(vErrs, fail)
 			}
 		}
+		if c.unit < 0 {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckUnit,
+				Msg:   "duration value must be positive",
+			})
+		}
+	case checkKindAnomaly:
+		if c.window <= 0 {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckWindow,
+				Msg:   "duration value must be provided",
+			})
+		}
+		if c.method != icheck.AnomalyStddev && c.method != icheck.AnomalyMAD {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckMethod,
+				Msg:   fmt.Sprintf("must be 1 in [%s, %s]; got=%q", icheck.AnomalyStddev, icheck.AnomalyMAD, c.method),
+			})
+		}
+		if c.deviations <= 0 {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldCheckDeviations,
+				Msg:   "must be a positive number",
+			})
+		}
+		vErrs = append(vErrs, c.validLevel()...)
+	case checkKindAbsence:
+		vErrs = append(vErrs, c.validLevel()...)
 	}
 	return vErrs
 }
 
+func (c *check) validLevel() []validationErr {
+	switch notification.ParseCheckLevel(strings.ToUpper(c.level)) {
+	case notification.Info, notification.Warn, notification.Critical:
+		return nil
+	}
+	return []validationErr{{
+		Field: fieldLevel,
+		Msg:   fmt.Sprintf("must be 1 in [CRIT, WARN, INFO]; got=%q", c.level),
+	}}
+}
+
 type mapperChecks []*check
 
 func (c mapperChecks) Association(i int) labelAssociater {
@@ -1326,6 +1434,7 @@ const (
 	notificationKindHTTP notificationKind = iota + 1
 	notificationKindPagerDuty
 	notificationKindSlack
+	notificationKindSMTP
 )
 
 const (
@@ -1335,9 +1444,13 @@ const (
 )
 
 const (
+	fieldNotificationEndpointFrom       = "from"
+	fieldNotificationEndpointHost       = "host"
 	fieldNotificationEndpointHTTPMethod = "method"
 	fieldNotificationEndpointPassword   = "password"
+	fieldNotificationEndpointPort       = "port"
 	fieldNotificationEndpointRoutingKey = "routingKey"
+	fieldNotificationEndpointTLS        = "tls"
 	fieldNotificationEndpointToken      = "token"
 	fieldNotificationEndpointURL        = "url"
 	fieldNotificationEndpointUsername   = "username"
@@ -1349,10 +1462,14 @@ type notificationEndpoint struct {
 	OrgID       influxdb.ID
 	name        *references
 	description string
+	from        string
+	host        string
 	method      string
 	password    *references
+	port        int
 	routingKey  *references
 	status      string
+	tls         string
 	token       *references
 	httpType    string
 	url         string
@@ -1441,10 +1558,36 @@ func (n *notificationEndpoint) summarize() SummaryNotificationEndpoint {
 			URL:   n.url,
 			Token: n.token.SecretField(),
 		}
+	case notificationKindSMTP:
+		sum.NotificationEndpoint = &endpoint.SMTP{
+			Base:     base,
+			Host:     n.host,
+			Port:     n.smtpPort(),
+			TLS:      n.smtpTLS(),
+			Username: n.username.SecretField(),
+			Password: n.password.SecretField(),
+			From:     n.from,
+		}
 	}
 	return sum
 }
 
+// smtpPort returns the port of an smtp endpoint, defaulting to the SMTP port.
+func (n *notificationEndpoint) smtpPort() int {
+	if n.port == 0 {
+		return 25
+	}
+	return n.port
+}
+
+// smtpTLS returns the tls mode of an smtp endpoint, defaulting to starttls.
+func (n *notificationEndpoint) smtpTLS() string {
+	if n.tls == "" {
+		return endpoint.SMTPTLSStartTLS
+	}
+	return n.tls
+}
+
 var validEndpointHTTPMethods = map[string]bool{
 	"DELETE":  true,
 	"GET":     true,
@@ -1457,11 +1600,13 @@ var validEndpointHTTPMethods = map[string]bool{
 
 func (n *notificationEndpoint) valid() []validationErr {
 	var failures []validationErr
-	if _, err := url.Parse(n.url); err != nil || n.url == "" {
-		failures = append(failures, validationErr{
-			Field: fieldNotificationEndpointURL,
-			Msg:   "must be valid url",
-		})
+	if n.kind != notificationKindSMTP {
+		if _, err := url.Parse(n.url); err != nil || n.url == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointURL,
+				Msg:   "must be valid url",
+			})
+		}
 	}
 
 	status := influxdb.Status(n.status)
@@ -1522,6 +1667,45 @@ func (n *notificationEndpoint) valid() []validationErr {
 				),
 			})
 		}
+	case notificationKindSMTP:
+		if n.host == "" {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointHost,
+				Msg:   "must provide non empty string",
+			})
+		}
+		if port := n.smtpPort(); port < 1 || port > 65535 {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPort,
+				Msg:   fmt.Sprintf("must be a valid port; got=%d", port),
+			})
+		}
+		switch tls := n.smtpTLS(); tls {
+		case endpoint.SMTPTLSNone, endpoint.SMTPTLSStartTLS, endpoint.SMTPTLS:
+		default:
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointTLS,
+				Msg: fmt.Sprintf(
+					"invalid tls mode provided %q; valid mode is 1 in [%s, %s, %s]",
+					tls,
+					endpoint.SMTPTLSNone,
+					endpoint.SMTPTLSStartTLS,
+					endpoint.SMTPTLS,
+				),
+			})
+		}
+		if _, err := mail.ParseAddress(n.from); err != nil {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointFrom,
+				Msg:   "must be a valid email address",
+			})
+		}
+		if n.username.hasValue() && !n.password.hasValue() {
+			failures = append(failures, validationErr{
+				Field: fieldNotificationEndpointPassword,
+				Msg:   "must provide non empty string",
+			})
+		}
 	}
 	return failures
 }
@@ -1537,13 +1721,17 @@ func (n mapperNotificationEndpoints) Len() int {
 }
 
 const (
+	fieldNotificationRuleBodyTemplate    = "bodyTemplate"
+	fieldNotificationRuleCc              = "cc"
 	fieldNotificationRuleChannel         = "channel"
 	fieldNotificationRuleCurrentLevel    = "currentLevel"
 	fieldNotificationRuleEndpointName    = "endpointName"
 	fieldNotificationRuleMessageTemplate = "messageTemplate"
 	fieldNotificationRulePreviousLevel   = "previousLevel"
 	fieldNotificationRuleStatusRules     = "statusRules"
+	fieldNotificationRuleSubjectTemplate = "subjectTemplate"
 	fieldNotificationRuleTagRules        = "tagRules"
+	fieldNotificationRuleTo              = "to"
 )
 
 type notificationRule struct {
@@ -1560,6 +1748,11 @@ type notificationRule struct {
 	statusRules []struct{ curLvl, prevLvl string }
 	tagRules    []struct{ k, v, op string }
 
+	to           []string
+	cc           []string
+	subjTemplate string
+	bodyTemplate string
+
 	endpointID   influxdb.ID
 	endpointName *references
 	endpointType string
@@ -1609,6 +1802,11 @@ func (r *notificationRule) summarize() SummaryNotificationRule {
 		Status:            r.Status(),
 		StatusRules:       toSummaryStatusRules(r.statusRules),
 		TagRules:          toSummaryTagRules(r.tagRules),
+
+		To:              r.to,
+		Cc:              r.cc,
+		SubjectTemplate: r.subjTemplate,
+		BodyTemplate:    r.bodyTemplate,
 	}
 }
 
@@ -1657,6 +1855,14 @@ func (r *notificationRule) toInfluxRule() influxdb.NotificationRule {
 			Channel:         r.channel,
 			MessageTemplate: r.msgTemplate,
 		}
+	case "smtp":
+		return &rule.SMTP{
+			Base:            base,
+			To:              r.to,
+			Cc:              r.cc,
+			SubjectTemplate: r.subjTemplate,
+			BodyTemplate:    r.bodyTemplate,
+		}
 	}
 	return nil
 }
@@ -2439,8 +2645,8 @@ type color struct {
 }
 
 // TODO:
-//  - verify templates are desired
-//  - template colors so references can be shared
+//   - verify templates are desired
+//   - template colors so references can be shared
 type colors []*color
 
 func (c colors) influxViewColors() []influxdb.ViewColor {
@@ -2474,8 +2680,9 @@ func (c colors) strings() []string {
 }
 
 // TODO: looks like much of these are actually getting defaults in
-//  the UI. looking at sytem charts, seeign lots of failures for missing
-//  color types or no colors at all.
+//
+//	the UI. looking at sytem charts, seeign lots of failures for missing
+//	color types or no colors at all.
 func (c colors) hasTypes(types ...string) []validationErr {
 	tMap := make(map[string]bool)
 	for _, cc := range c {
@@ -701,6 +701,9 @@ func (p *Pkg) graphChecks() *parseErr {
 	}{
 		{kind: KindCheckThreshold, checkKind: checkKindThreshold},
 		{kind: KindCheckDeadman, checkKind: checkKindDeadman},
+		{kind: KindCheckAnomaly, checkKind: checkKindAnomaly},
+		{kind: KindCheckRate, checkKind: checkKindRate},
+		{kind: KindCheckAbsence, checkKind: checkKindAbsence},
 	}
 	var pErr parseErr
 	for _, checkKind := range checkKinds {
@@ -726,6 +729,12 @@ func (p *Pkg) graphChecks() *parseErr {
 				status:        normStr(o.Spec.stringShort(fieldStatus)),
 				statusMessage: o.Spec.stringShort(fieldCheckStatusMessageTemplate),
 				timeSince:     o.Spec.durationShort(fieldCheckTimeSince),
+				window:        o.Spec.durationShort(fieldCheckWindow),
+				method:        normStr(o.Spec.stringShort(fieldCheckMethod)),
+				deviations:    o.Spec.float64Short(fieldCheckDeviations),
+				lastWeek:      o.Spec.boolShort(fieldCheckLastWeek),
+				unit:          o.Spec.durationShort(fieldCheckUnit),
+				nonNegative:   o.Spec.boolShort(fieldCheckNonNegative),
 			}
 			for _, tagRes := range o.Spec.slcResource(fieldCheckTags) {
 				ch.tags = append(ch.tags, struct{ k, v string }{
@@ -820,6 +829,10 @@ func (p *Pkg) graphNotificationEndpoints() *parseErr {
 			kind:             KindNotificationEndpointSlack,
 			notificationKind: notificationKindSlack,
 		},
+		{
+			kind:             KindNotificationEndpointSMTP,
+			notificationKind: notificationKindSMTP,
+		},
 	}
 
 	var pErr parseErr
@@ -837,11 +850,15 @@ func (p *Pkg) graphNotificationEndpoints() *parseErr {
 				kind:        nk.notificationKind,
 				name:        nameRef,
 				description: o.Spec.stringShort(fieldDescription),
+				from:        o.Spec.stringShort(fieldNotificationEndpointFrom),
+				host:        o.Spec.stringShort(fieldNotificationEndpointHost),
 				method:      strings.TrimSpace(strings.ToUpper(o.Spec.stringShort(fieldNotificationEndpointHTTPMethod))),
 				httpType:    normStr(o.Spec.stringShort(fieldType)),
 				password:    o.Spec.references(fieldNotificationEndpointPassword),
+				port:        o.Spec.intShort(fieldNotificationEndpointPort),
 				routingKey:  o.Spec.references(fieldNotificationEndpointRoutingKey),
 				status:      normStr(o.Spec.stringShort(fieldStatus)),
+				tls:         normStr(o.Spec.stringShort(fieldNotificationEndpointTLS)),
 				token:       o.Spec.references(fieldNotificationEndpointToken),
 				url:         o.Spec.stringShort(fieldNotificationEndpointURL),
 				username:    o.Spec.references(fieldNotificationEndpointUsername),
@@ -880,6 +897,10 @@ func (p *Pkg) graphNotificationRules() *parseErr {
 			msgTemplate:  o.Spec.stringShort(fieldNotificationRuleMessageTemplate),
 			offset:       o.Spec.durationShort(fieldOffset),
 			status:       normStr(o.Spec.stringShort(fieldStatus)),
+			to:           o.Spec.slcStr(fieldNotificationRuleTo),
+			cc:           o.Spec.slcStr(fieldNotificationRuleCc),
+			subjTemplate: o.Spec.stringShort(fieldNotificationRuleSubjectTemplate),
+			bodyTemplate: o.Spec.stringShort(fieldNotificationRuleBodyTemplate),
 		}
 
 		for _, sRule := range o.Spec.slcResource(fieldNotificationRuleStatusRules) {
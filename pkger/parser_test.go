@@ -413,6 +413,90 @@ spec:
 			})
 		})
 
+		t.Run("anomaly check", func(t *testing.T) {
+			testfileRunner(t, "testdata/checks_anomaly", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Checks, 1)
+
+				check := sum.Checks[0]
+				anomalyCheck, ok := check.Check.(*icheck.Anomaly)
+				require.Truef(t, ok, "got: %#v", check)
+
+				expectedBase := icheck.Base{
+					Name:                  "anomaly_check",
+					Description:           "anomaly desc",
+					Every:                 mustDuration(t, 5*time.Minute),
+					Offset:                mustDuration(t, 10*time.Second),
+					StatusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }",
+				}
+				expectedBase.Query.Text = "from(bucket: \"rucket_1\")\n  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)\n  |> filter(fn: (r) => r._measurement == \"cpu\")\n  |> filter(fn: (r) => r._field == \"usage_idle\")\n  |> aggregateWindow(every: 1m, fn: mean)\n  |> yield(name: \"mean\")"
+				assert.Equal(t, expectedBase, anomalyCheck.Base)
+				assert.Equal(t, mustDuration(t, time.Hour), anomalyCheck.Window)
+				assert.Equal(t, icheck.AnomalyMAD, anomalyCheck.Method)
+				assert.Equal(t, 2.5, anomalyCheck.Deviations)
+				assert.True(t, anomalyCheck.LastWeek)
+				assert.Equal(t, notification.Warn, anomalyCheck.Level)
+				assert.Len(t, check.LabelAssociations, 1)
+
+				containsLabelMappings(t, sum.LabelMappings, labelMapping{
+					labelName: "label_1",
+					resName:   "anomaly_check",
+					resType:   influxdb.ChecksResourceType,
+				})
+			})
+		})
+
+		t.Run("rate check", func(t *testing.T) {
+			testfileRunner(t, "testdata/checks_rate", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Checks, 1)
+
+				check := sum.Checks[0]
+				rateCheck, ok := check.Check.(*icheck.Rate)
+				require.Truef(t, ok, "got: %#v", check)
+				assert.Equal(t, "rate_check", rateCheck.Name)
+				assert.Equal(t, "rate desc", rateCheck.Description)
+				assert.Equal(t, mustDuration(t, time.Minute), rateCheck.Unit)
+				assert.True(t, rateCheck.NonNegative)
+				expectedThresholds := []icheck.ThresholdConfig{
+					icheck.Greater{
+						ThresholdConfigBase: icheck.ThresholdConfigBase{Level: notification.Critical},
+						Value:               1000.0,
+					},
+				}
+				assert.Equal(t, expectedThresholds, rateCheck.Thresholds)
+				assert.Len(t, check.LabelAssociations, 1)
+
+				containsLabelMappings(t, sum.LabelMappings, labelMapping{
+					labelName: "label_1",
+					resName:   "rate_check",
+					resType:   influxdb.ChecksResourceType,
+				})
+			})
+		})
+
+		t.Run("absence check", func(t *testing.T) {
+			testfileRunner(t, "testdata/checks_absence", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.Checks, 1)
+
+				check := sum.Checks[0]
+				absenceCheck, ok := check.Check.(*icheck.Absence)
+				require.Truef(t, ok, "got: %#v", check)
+				assert.Equal(t, "absence_check", absenceCheck.Name)
+				assert.Equal(t, "absence desc", absenceCheck.Description)
+				assert.Equal(t, mustDuration(t, 5*time.Minute), absenceCheck.Every)
+				assert.Equal(t, notification.Critical, absenceCheck.Level)
+				assert.Len(t, check.LabelAssociations, 1)
+
+				containsLabelMappings(t, sum.LabelMappings, labelMapping{
+					labelName: "label_1",
+					resName:   "absence_check",
+					resType:   influxdb.ChecksResourceType,
+				})
+			})
+		})
+
 		t.Run("handles bad config", func(t *testing.T) {
 			tests := []struct {
 				kind   Kind
@@ -711,6 +795,26 @@ spec:
       name: label_1
     - kind: Label
       name: label_1
+`,
+					},
+				},
+				{
+					kind: KindCheckAnomaly,
+					resErr: testPkgResourceError{
+						name:           "invalid band",
+						validationErrs: 4,
+						valFields:      []string{fieldCheckWindow, fieldCheckMethod, fieldCheckDeviations, fieldLevel},
+						pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: CheckAnomaly
+metadata:
+  name: check_2
+spec:
+  every: 5m
+  level: ok
+  method: mean
+  query:  >
+    from(bucket: "rucket_1") |> yield(name: "mean")
+  statusMessageTemplate: "Check: ${ r._check_name } is: ${ r._level }"
 `,
 					},
 				},
@@ -2395,6 +2499,38 @@ spec:
 			})
 		})
 
+		t.Run("smtp endpoint", func(t *testing.T) {
+			testfileRunner(t, "testdata/notification_endpoint_smtp", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+				require.Len(t, sum.NotificationEndpoints, 1)
+				require.Len(t, sum.LabelMappings, 1)
+
+				expected := &endpoint.SMTP{
+					Base: endpoint.Base{
+						Name:        "smtp_notification_endpoint",
+						Description: "smtp desc",
+						Status:      influxdb.TaskStatusActive,
+					},
+					Host:     "smtp.example.com",
+					Port:     587,
+					TLS:      "starttls",
+					Username: influxdb.SecretField{Value: strPtr("secret username")},
+					Password: influxdb.SecretField{Value: strPtr("secret password")},
+					From:     "influxdb@example.com",
+				}
+				actual := sum.NotificationEndpoints[0]
+				assert.Equal(t, expected, actual.NotificationEndpoint)
+				require.Len(t, actual.LabelAssociations, 1)
+				assert.Equal(t, "label_1", actual.LabelAssociations[0].Name)
+
+				containsLabelMappings(t, sum.LabelMappings, labelMapping{
+					labelName: "label_1",
+					resName:   "smtp_notification_endpoint",
+					resType:   influxdb.NotificationEndpointResourceType,
+				})
+			})
+		})
+
 		t.Run("handles bad config", func(t *testing.T) {
 			tests := []struct {
 				kind   Kind
@@ -2604,6 +2740,38 @@ metadata:
   name: slack_notification_endpoint
 spec:
   url: https://hooks.slack.com/services/bip/piddy/boppidy
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointSMTP,
+					resErr: testPkgResourceError{
+						name:           "missing smtp host and from",
+						validationErrs: 2,
+						valFields:      []string{fieldNotificationEndpointHost, fieldNotificationEndpointFrom},
+						pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointSMTP
+metadata:
+  name: smtp_notification_endpoint
+spec:
+  port: 25
+`,
+					},
+				},
+				{
+					kind: KindNotificationEndpointSMTP,
+					resErr: testPkgResourceError{
+						name:           "invalid smtp tls mode",
+						validationErrs: 1,
+						valFields:      []string{fieldNotificationEndpointTLS},
+						pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: NotificationEndpointSMTP
+metadata:
+  name: smtp_notification_endpoint
+spec:
+  host: smtp.example.com
+  tls: ssl
+  from: influxdb@example.com
 `,
 					},
 				},
@@ -247,15 +247,19 @@ func (s *Service) CreatePkg(ctx context.Context, setters ...CreatePkgSetFn) (*Pk
 	var kindPriorities = map[Kind]int{
 		KindLabel:                         1,
 		KindBucket:                        2,
-		KindCheckDeadman:                  3,
-		KindCheckThreshold:                4,
-		KindNotificationEndpointHTTP:      5,
-		KindNotificationEndpointPagerDuty: 6,
-		KindNotificationEndpointSlack:     7,
-		KindNotificationRule:              8,
-		KindVariable:                      9,
-		KindTelegraf:                      10,
-		KindDashboard:                     11,
+		KindCheckAbsence:                  3,
+		KindCheckAnomaly:                  4,
+		KindCheckDeadman:                  5,
+		KindCheckRate:                     6,
+		KindCheckThreshold:                7,
+		KindNotificationEndpointHTTP:      8,
+		KindNotificationEndpointPagerDuty: 9,
+		KindNotificationEndpointSlack:     10,
+		KindNotificationEndpointSMTP:      11,
+		KindNotificationRule:              12,
+		KindVariable:                      13,
+		KindTelegraf:                      14,
+		KindDashboard:                     15,
 	}
 
 	sort.Slice(pkg.Objects, func(i, j int) bool {
@@ -530,7 +534,10 @@ func (s *Service) resourceCloneToKind(ctx context.Context, r ResourceToClone, cF
 		}
 		newKind = bucketToObject(*bkt, r.Name)
 	case r.Kind.is(KindCheck),
+		r.Kind.is(KindCheckAbsence),
+		r.Kind.is(KindCheckAnomaly),
 		r.Kind.is(KindCheckDeadman),
+		r.Kind.is(KindCheckRate),
 		r.Kind.is(KindCheckThreshold):
 		ch, err := s.checkSVC.FindCheckByID(ctx, r.ID)
 		if err != nil {
@@ -552,7 +559,8 @@ func (s *Service) resourceCloneToKind(ctx context.Context, r ResourceToClone, cF
 	case r.Kind.is(KindNotificationEndpoint),
 		r.Kind.is(KindNotificationEndpointHTTP),
 		r.Kind.is(KindNotificationEndpointPagerDuty),
-		r.Kind.is(KindNotificationEndpointSlack):
+		r.Kind.is(KindNotificationEndpointSlack),
+		r.Kind.is(KindNotificationEndpointSMTP):
 		e, err := s.endpointSVC.FindNotificationEndpointByID(ctx, r.ID)
 		if err != nil {
 			return nil, err
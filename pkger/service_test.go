@@ -1530,6 +1530,39 @@ func TestService(t *testing.T) {
 							Level:      notification.Critical,
 						},
 					},
+					{
+						name: "anomaly",
+						expected: &icheck.Anomaly{
+							Base:       newThresholdBase(2),
+							Window:     mustDuration(t, time.Hour),
+							Method:     icheck.AnomalyMAD,
+							Deviations: 2.5,
+							LastWeek:   true,
+							Level:      notification.Warn,
+						},
+					},
+					{
+						name:    "rate",
+						newName: "new name",
+						expected: &icheck.Rate{
+							Base:        newThresholdBase(3),
+							Unit:        mustDuration(t, time.Minute),
+							NonNegative: true,
+							Thresholds: []icheck.ThresholdConfig{
+								icheck.Greater{
+									ThresholdConfigBase: icheck.ThresholdConfigBase{Level: notification.Critical},
+									Value:               1000,
+								},
+							},
+						},
+					},
+					{
+						name: "absence",
+						expected: &icheck.Absence{
+							Base:  newThresholdBase(4),
+							Level: notification.Critical,
+						},
+					},
 				}
 
 				for _, tt := range tests {
@@ -1566,6 +1599,16 @@ func TestService(t *testing.T) {
 							expectedName = tt.newName
 						}
 						assert.Equal(t, expectedName, actual.GetName())
+						assert.IsType(t, tt.expected, actual)
+						if expected, ok := tt.expected.(*icheck.Anomaly); ok {
+							anomaly, ok := actual.(*icheck.Anomaly)
+							require.Truef(t, ok, "got: %#v", actual)
+							assert.Equal(t, expected.Window, anomaly.Window)
+							assert.Equal(t, expected.Method, anomaly.Method)
+							assert.Equal(t, expected.Deviations, anomaly.Deviations)
+							assert.Equal(t, expected.LastWeek, anomaly.LastWeek)
+							assert.Equal(t, expected.Level, anomaly.Level)
+						}
 					}
 					t.Run(tt.name, fn)
 				}
@@ -2033,6 +2076,22 @@ func TestService(t *testing.T) {
 							URL:        "http://example.com",
 						},
 					},
+					{
+						name: "smtp",
+						expected: &endpoint.SMTP{
+							Base: endpoint.Base{
+								Name:        "pd-endpoint",
+								Description: "desc",
+								Status:      influxdb.TaskStatusActive,
+							},
+							Host:     "smtp.example.com",
+							Port:     465,
+							TLS:      "tls",
+							Username: influxdb.SecretField{Key: "username"},
+							Password: influxdb.SecretField{Key: "password"},
+							From:     "influxdb@example.com",
+						},
+					},
 				}
 
 				for _, tt := range tests {
@@ -2155,6 +2214,28 @@ func TestService(t *testing.T) {
 							Base: newRuleBase(13),
 						},
 					},
+					{
+						name: "smtp",
+						endpoint: &endpoint.SMTP{
+							Base: endpoint.Base{
+								ID:          newTestIDPtr(13),
+								Name:        "endpoint_0",
+								Description: "desc",
+								Status:      influxdb.TaskStatusActive,
+							},
+							Host: "smtp.example.com",
+							Port: 25,
+							TLS:  "none",
+							From: "influxdb@example.com",
+						},
+						rule: &rule.SMTP{
+							Base:            newRuleBase(13),
+							To:              []string{"ops@example.com", "oncall@example.com"},
+							Cc:              []string{"lead@example.com"},
+							SubjectTemplate: "SUBJECT",
+							BodyTemplate:    "BODY",
+						},
+					},
 				}
 
 				for _, tt := range tests {
@@ -2232,6 +2313,12 @@ func TestService(t *testing.T) {
 						case *rule.Slack:
 							baseEqual(t, p.Base)
 							assert.Equal(t, p.MessageTemplate, actualRule.MessageTemplate)
+						case *rule.SMTP:
+							baseEqual(t, p.Base)
+							assert.Equal(t, p.To, actualRule.To)
+							assert.Equal(t, p.Cc, actualRule.Cc)
+							assert.Equal(t, p.SubjectTemplate, actualRule.SubjectTemplate)
+							assert.Equal(t, p.BodyTemplate, actualRule.BodyTemplate)
 						}
 
 						require.Len(t, pkg.Summary().NotificationEndpoints, 1)
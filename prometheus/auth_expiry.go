@@ -0,0 +1,72 @@
+package prometheus
+
+import (
+	"context"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultExpiryWindow is the default window of the tokens counted as expiring.
+const DefaultExpiryWindow = 7 * 24 * time.Hour
+
+type authorizationExpiryCollector struct {
+	svc    platform.AuthorizationService
+	window time.Duration
+
+	expiringDesc *prometheus.Desc
+	expiredDesc  *prometheus.Desc
+}
+
+// NewAuthorizationExpiryCollector returns a collector which exports the number of active tokens
+// expiring within the window and of expired tokens.
+func NewAuthorizationExpiryCollector(svc platform.AuthorizationService, window time.Duration) prometheus.Collector {
+	return &authorizationExpiryCollector{
+		svc:    svc,
+		window: window,
+		expiringDesc: prometheus.NewDesc(
+			"influxdb_tokens_expiring_total",
+			"Number of active tokens expiring within the window",
+			nil, prometheus.Labels{
+				"window": window.String(),
+			},
+		),
+		expiredDesc: prometheus.NewDesc(
+			"influxdb_tokens_expired_total",
+			"Number of expired tokens on the server",
+			nil, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *authorizationExpiryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.expiringDesc
+	ch <- c.expiredDesc
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *authorizationExpiryCollector) Collect(ch chan<- prometheus.Metric) {
+	as, _, err := c.svc.FindAuthorizations(context.Background(), platform.AuthorizationFilter{})
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.expiringDesc, err)
+		ch <- prometheus.NewInvalidMetric(c.expiredDesc, err)
+		return
+	}
+
+	now := time.Now()
+	var expiring, expired int
+	for _, a := range as {
+		switch {
+		case a.ExpiresAt == nil:
+		case a.CheckExpiry(now) != nil:
+			expired++
+		case a.IsActive() && a.ExpiresAt.Before(now.Add(c.window)):
+			expiring++
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.expiringDesc, prometheus.GaugeValue, float64(expiring))
+	ch <- prometheus.MustNewConstMetric(c.expiredDesc, prometheus.GaugeValue, float64(expired))
+}
@@ -0,0 +1,45 @@
+package prometheus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/prom"
+	"github.com/influxdata/influxdb/kit/prom/promtest"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/prometheus"
+	"go.uber.org/zap"
+)
+
+func TestAuthorizationExpiryCollector(t *testing.T) {
+	at := func(d time.Duration) *time.Time {
+		t := time.Now().Add(d)
+		return &t
+	}
+	svc := mock.NewAuthorizationService()
+	svc.FindAuthorizationsFn = func(ctx context.Context, filter platform.AuthorizationFilter, opts ...platform.FindOptions) ([]*platform.Authorization, int, error) {
+		as := []*platform.Authorization{
+			{ID: 1, Status: platform.Active},
+			{ID: 2, Status: platform.Active, ExpiresAt: at(time.Hour)},
+			{ID: 3, Status: platform.Active, ExpiresAt: at(30 * 24 * time.Hour)},
+			{ID: 4, Status: platform.Inactive, ExpiresAt: at(time.Hour)},
+			{ID: 5, Status: platform.Active, ExpiresAt: at(-time.Hour)},
+		}
+		return as, len(as), nil
+	}
+
+	reg := prom.NewRegistry(zap.NewNop())
+	reg.MustRegister(prometheus.NewAuthorizationExpiryCollector(svc, prometheus.DefaultExpiryWindow))
+	mfs := promtest.MustGather(t, reg)
+
+	m := promtest.MustFindMetric(t, mfs, "influxdb_tokens_expiring_total", map[string]string{"window": "168h0m0s"})
+	if got := m.GetGauge().GetValue(); got != 1 {
+		t.Errorf("exp 1 expiring token, got %v", got)
+	}
+	m = promtest.MustFindMetric(t, mfs, "influxdb_tokens_expired_total", nil)
+	if got := m.GetGauge().GetValue(); got != 1 {
+		t.Errorf("exp 1 expired token, got %v", got)
+	}
+}
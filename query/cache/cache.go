@@ -0,0 +1,411 @@
+// Package cache implements a cache of query results in front of a
+// query.ProxyQueryService.
+//
+// Results are keyed by organization, the normalized Flux AST, the encoding
+// dialect and the now time of the query bucketed to a refresh interval.
+// Every query that is served through the cache is executed with its now time
+// truncated to the refresh interval, so identical queries that arrive within
+// the same interval read the same time ranges and share a single result.
+//
+// Cached results are invalidated when points are written to, data is
+// deleted from, or the retention period changes of any of the buckets a
+// query reads, see PointsWriter, DeleteService and BucketService.
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	"github.com/influxdata/influxdb/kit/check"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+// DefaultInterval is the default refresh interval of the cache.
+const DefaultInterval = 10 * time.Second
+
+// Config configures the query result cache.
+type Config struct {
+	// MaxBytes is the maximum total size of the cached results.
+	// Results larger than a tenth of MaxBytes are never cached.
+	MaxBytes int64
+
+	// Interval is the refresh interval that the now time of cached
+	// queries is truncated to. It defaults to DefaultInterval.
+	Interval time.Duration
+}
+
+// Status reports how a query was served by the cache.
+type Status struct {
+	// Hit is true if the result was served from the cache.
+	Hit bool
+	// Age is the time since the cached result was computed.
+	Age time.Duration
+}
+
+// StatusWriter is implemented by writers that report the cache status
+// of a query to the client, e.g. as response headers. SetCacheStatus is
+// called before anything is written for queries that can be cached.
+type StatusWriter interface {
+	io.Writer
+	SetCacheStatus(s Status)
+}
+
+// ProxyQueryService is a query.ProxyQueryService that caches query results.
+type ProxyQueryService struct {
+	proxyQueryService query.ProxyQueryService
+	bucketService     influxdb.BucketService
+	log               *zap.Logger
+
+	interval      time.Duration
+	maxBytes      int64
+	maxEntryBytes int64
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	size    int64
+	// buckets indexes the keys of the cached results that read a bucket.
+	buckets map[influxdb.ID]map[string]struct{}
+	// generations is incremented every time a bucket is invalidated, so
+	// that results computed while a bucket was written to are not cached.
+	generations map[influxdb.ID]uint64
+
+	metrics *metrics
+}
+
+type entry struct {
+	key     string
+	buckets []influxdb.ID
+	orgID   influxdb.ID
+	result  []byte
+	stats   flux.Statistics
+	created time.Time
+}
+
+// NewProxyQueryService returns a ProxyQueryService that caches the results of s.
+// The bucket service is used to find the IDs of the buckets read by a query.
+func NewProxyQueryService(log *zap.Logger, c Config, s query.ProxyQueryService, bucketService influxdb.BucketService) *ProxyQueryService {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &ProxyQueryService{
+		proxyQueryService: s,
+		bucketService:     bucketService,
+		log:               log,
+		interval:          interval,
+		maxBytes:          c.MaxBytes,
+		maxEntryBytes:     c.MaxBytes / 10,
+		lru:               list.New(),
+		entries:           make(map[string]*list.Element),
+		buckets:           make(map[influxdb.ID]map[string]struct{}),
+		generations:       make(map[influxdb.ID]uint64),
+		metrics:           newMetrics(),
+	}
+}
+
+// SetProxyQueryService sets the query service the cached results are computed with.
+// It lets the cache be created before the query service, so that the writes and
+// deletes of the queries can invalidate it. It must be set before queries are served.
+func (s *ProxyQueryService) SetProxyQueryService(qs query.ProxyQueryService) {
+	s.proxyQueryService = qs
+}
+
+// Query serves the query from the cache or executes it and caches its result.
+func (s *ProxyQueryService) Query(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	cq, ok := s.prepare(req)
+	if !ok {
+		s.metrics.requests.WithLabelValues(labelBypass).Inc()
+		return s.proxyQueryService.Query(ctx, w, req)
+	}
+
+	if e := s.lookup(cq.key); e != nil {
+		if err := s.authorize(ctx, e); err == nil {
+			s.metrics.requests.WithLabelValues(labelHit).Inc()
+			span.LogKV("cache", labelHit)
+			setStatus(w, Status{Hit: true, Age: time.Since(e.created)})
+			_, err := w.Write(e.result)
+			return e.stats, err
+		}
+		// The cached result reads buckets that the request is not allowed
+		// to read. Let the query service report the error.
+		s.metrics.requests.WithLabelValues(labelBypass).Inc()
+		return s.proxyQueryService.Query(ctx, w, cq.req)
+	}
+
+	s.metrics.requests.WithLabelValues(labelMiss).Inc()
+	span.LogKV("cache", labelMiss)
+	setStatus(w, Status{})
+
+	buckets, err := s.findBuckets(ctx, req.Request.OrganizationID, cq.reads)
+	if err != nil {
+		// The buckets of the query could not be found, so writes to them
+		// could not invalidate the result. Execute the query without caching it.
+		return s.proxyQueryService.Query(ctx, w, cq.req)
+	}
+	generations := s.bucketGenerations(buckets)
+
+	rw := &recordingWriter{w: w, max: s.maxEntryBytes}
+	stats, err := s.proxyQueryService.Query(ctx, rw, cq.req)
+	if err != nil || rw.overflow {
+		return stats, err
+	}
+	s.store(&entry{
+		key:     cq.key,
+		buckets: buckets,
+		orgID:   req.Request.OrganizationID,
+		result:  rw.buf.Bytes(),
+		stats:   stats,
+		created: time.Now(),
+	}, generations)
+	return stats, nil
+}
+
+// Check returns the status of the underlying query service.
+func (s *ProxyQueryService) Check(ctx context.Context) check.Response {
+	return s.proxyQueryService.Check(ctx)
+}
+
+// InvalidateBucket removes the cached results of the queries that read the bucket.
+func (s *ProxyQueryService) InvalidateBucket(bucketID influxdb.ID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generations[bucketID]++
+	keys := s.buckets[bucketID]
+	for key := range keys {
+		if elem, ok := s.entries[key]; ok {
+			s.remove(elem)
+		}
+	}
+	if len(keys) > 0 {
+		s.metrics.invalidations.Add(float64(len(keys)))
+	}
+}
+
+// cacheableQuery is a query that may be served from the cache.
+type cacheableQuery struct {
+	key string
+	// req is the request with its now time truncated to the refresh interval.
+	req *query.ProxyRequest
+	// reads lists the buckets read by the query.
+	reads bucketReads
+}
+
+// prepare determines if the request can be cached and computes its key.
+func (s *ProxyQueryService) prepare(req *query.ProxyRequest) (cacheableQuery, bool) {
+	if s.maxBytes <= 0 || req.Request.OrganizationID == 0 {
+		return cacheableQuery{}, false
+	}
+	if _, ok := req.Dialect.(*query.NoContentDialect); ok {
+		return cacheableQuery{}, false
+	}
+
+	var (
+		files    []*ast.File
+		now      time.Time
+		compiler flux.Compiler
+	)
+	switch c := req.Request.Compiler.(type) {
+	case lang.FluxCompiler:
+		pkg := parser.ParseSource(c.Query)
+		if ast.Check(pkg) > 0 {
+			// Let the query service report the errors.
+			return cacheableQuery{}, false
+		}
+		if c.Extern != nil {
+			files = append(files, c.Extern)
+		}
+		files = append(files, pkg.Files...)
+		now = c.Now
+		c.Now = s.truncate(now)
+		compiler = c
+	case lang.ASTCompiler:
+		if c.AST == nil {
+			return cacheableQuery{}, false
+		}
+		files = c.AST.Files
+		now = c.Now
+		c.Now = s.truncate(now)
+		compiler = c
+	default:
+		return cacheableQuery{}, false
+	}
+
+	reads, ok := readsOf(files)
+	if !ok {
+		return cacheableQuery{}, false
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%d\n%T%+v\n", req.Request.OrganizationID, compiler.CompilerType(), s.truncate(now).UnixNano(), req.Dialect, req.Dialect)
+	for _, f := range files {
+		// Formatting the files normalizes whitespace and comments.
+		io.WriteString(h, ast.Format(f))
+		io.WriteString(h, "\n")
+	}
+
+	r := *req
+	r.Request.Compiler = compiler
+	return cacheableQuery{
+		key:   hex.EncodeToString(h.Sum(nil)),
+		req:   &r,
+		reads: reads,
+	}, true
+}
+
+func (s *ProxyQueryService) truncate(now time.Time) time.Time {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return now.Truncate(s.interval)
+}
+
+// findBuckets returns the IDs of the buckets read by a query.
+func (s *ProxyQueryService) findBuckets(ctx context.Context, orgID influxdb.ID, reads bucketReads) ([]influxdb.ID, error) {
+	ids := append([]influxdb.ID(nil), reads.ids...)
+	for _, name := range reads.names {
+		name := name
+		b, err := s.bucketService.FindBucket(ctx, influxdb.BucketFilter{
+			OrganizationID: &orgID,
+			Name:           &name,
+		})
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, b.ID)
+	}
+	return ids, nil
+}
+
+// authorize returns an error if the authorizer of ctx is not allowed
+// to read all of the buckets of the cached result.
+func (s *ProxyQueryService) authorize(ctx context.Context, e *entry) error {
+	for _, id := range e.buckets {
+		p, err := influxdb.NewPermissionAtID(id, influxdb.ReadAction, influxdb.BucketsResourceType, e.orgID)
+		if err != nil {
+			return err
+		}
+		if err := authorizer.IsAllowed(ctx, *p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ProxyQueryService) bucketGenerations(buckets []influxdb.ID) []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	generations := make([]uint64, len(buckets))
+	for i, id := range buckets {
+		generations[i] = s.generations[id]
+	}
+	return generations
+}
+
+func (s *ProxyQueryService) lookup(key string) *entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	s.lru.MoveToFront(elem)
+	return elem.Value.(*entry)
+}
+
+// store adds the entry to the cache unless one of its buckets was
+// invalidated since the generations were read.
+func (s *ProxyQueryService) store(e *entry, generations []uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, id := range e.buckets {
+		if s.generations[id] != generations[i] {
+			return
+		}
+	}
+	if elem, ok := s.entries[e.key]; ok {
+		s.remove(elem)
+	}
+
+	s.entries[e.key] = s.lru.PushFront(e)
+	s.size += int64(len(e.result))
+	for _, id := range e.buckets {
+		keys, ok := s.buckets[id]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.buckets[id] = keys
+		}
+		keys[e.key] = struct{}{}
+	}
+
+	for s.size > s.maxBytes {
+		s.remove(s.lru.Back())
+		s.metrics.evictions.Inc()
+	}
+	s.metrics.entries.Set(float64(len(s.entries)))
+	s.metrics.size.Set(float64(s.size))
+}
+
+// remove removes the cached result from the cache. The lock must be held.
+func (s *ProxyQueryService) remove(elem *list.Element) {
+	e := s.lru.Remove(elem).(*entry)
+	delete(s.entries, e.key)
+	s.size -= int64(len(e.result))
+	for _, id := range e.buckets {
+		if keys, ok := s.buckets[id]; ok {
+			delete(keys, e.key)
+			if len(keys) == 0 {
+				delete(s.buckets, id)
+			}
+		}
+	}
+	s.metrics.entries.Set(float64(len(s.entries)))
+	s.metrics.size.Set(float64(s.size))
+}
+
+func setStatus(w io.Writer, s Status) {
+	if sw, ok := w.(StatusWriter); ok {
+		sw.SetCacheStatus(s)
+	}
+}
+
+// recordingWriter writes to w while keeping a copy of everything written,
+// as long as it does not grow larger than max.
+type recordingWriter struct {
+	w        io.Writer
+	buf      bytes.Buffer
+	max      int64
+	overflow bool
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	if !w.overflow {
+		if int64(w.buf.Len()+len(p)) > w.max {
+			w.overflow = true
+			w.buf = bytes.Buffer{}
+		} else {
+			w.buf.Write(p)
+		}
+	}
+	return w.w.Write(p)
+}
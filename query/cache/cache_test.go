@@ -0,0 +1,377 @@
+package cache_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/cache"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	orgID    influxdb.ID = 0x1
+	bucketID influxdb.ID = 0x2
+	otherID  influxdb.ID = 0x3
+)
+
+var now = time.Date(2019, 11, 1, 12, 0, 3, 0, time.UTC)
+
+type fixture struct {
+	svc     *cache.ProxyQueryService
+	queries []*query.ProxyRequest
+	err     error
+	result  string
+}
+
+func newFixture(t *testing.T, maxBytes int64) *fixture {
+	t.Helper()
+	f := &fixture{result: "result"}
+	qs := &querymock.ProxyQueryService{
+		QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+			f.queries = append(f.queries, req)
+			if _, err := io.WriteString(w, f.result); err != nil {
+				return flux.Statistics{}, err
+			}
+			return flux.Statistics{TotalDuration: time.Second}, f.err
+		},
+	}
+	bs := mock.NewBucketService()
+	bs.FindBucketFn = func(ctx context.Context, filter influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		switch *filter.Name {
+		case "telegraf":
+			return &influxdb.Bucket{ID: bucketID, OrgID: orgID, Name: "telegraf"}, nil
+		case "other":
+			return &influxdb.Bucket{ID: otherID, OrgID: orgID, Name: "other"}, nil
+		}
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+	}
+	f.svc = cache.NewProxyQueryService(zaptest.NewLogger(t), cache.Config{
+		MaxBytes: maxBytes,
+		Interval: 10 * time.Second,
+	}, qs, bs)
+	return f
+}
+
+// statusWriter records the cache status reported by the service.
+type statusWriter struct {
+	bytes.Buffer
+	status *cache.Status
+}
+
+func (w *statusWriter) SetCacheStatus(s cache.Status) {
+	w.status = &s
+}
+
+func (f *fixture) query(t *testing.T, ctx context.Context, q string, now time.Time) *statusWriter {
+	t.Helper()
+	return f.request(t, ctx, lang.FluxCompiler{Query: q, Now: now})
+}
+
+func (f *fixture) request(t *testing.T, ctx context.Context, c flux.Compiler) *statusWriter {
+	t.Helper()
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			OrganizationID: orgID,
+			Compiler:       c,
+		},
+		Dialect: &csv.Dialect{ResultEncoderConfig: csv.DefaultEncoderConfig()},
+	}
+	w := &statusWriter{}
+	if _, err := f.svc.Query(ctx, w, req); err != nil && f.err == nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func authorizedContext(buckets ...influxdb.ID) context.Context {
+	a := &influxdb.Authorization{
+		OrgID:  orgID,
+		Status: influxdb.Active,
+	}
+	for _, id := range buckets {
+		p, _ := influxdb.NewPermissionAtID(id, influxdb.ReadAction, influxdb.BucketsResourceType, orgID)
+		a.Permissions = append(a.Permissions, *p)
+	}
+	return pcontext.SetAuthorizer(context.Background(), a)
+}
+
+func writePoints(t *testing.T, w *cache.PointsWriter, bucket influxdb.ID) {
+	t.Helper()
+	p, err := models.NewPoint(tsdb.EncodeNameString(orgID, bucket), nil, models.Fields{"v": 1.0}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WritePoints(context.Background(), []models.Point{p}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const telegrafQuery = `from(bucket: "telegraf") |> range(start: -1h) |> filter(fn: (r) => r._measurement == "cpu")`
+
+func TestProxyQueryService_Hit(t *testing.T) {
+	f := newFixture(t, 1<<20)
+	ctx := authorizedContext(bucketID)
+
+	w := f.query(t, ctx, telegrafQuery, now)
+	if w.status == nil || w.status.Hit {
+		t.Fatalf("expected a miss, got %+v", w.status)
+	}
+	if got, want := f.queries[0].Request.Compiler.(lang.FluxCompiler).Now, now.Truncate(10*time.Second); !got.Equal(want) {
+		t.Errorf("unexpected now passed to the query service; got %v, want %v", got, want)
+	}
+
+	// Whitespace and comments are not part of the key.
+	q := "// cpu usage\n" + strings.Replace(telegrafQuery, "|>", "\n  |>", -1)
+	w = f.query(t, ctx, q, now.Add(5*time.Second))
+	if w.status == nil || !w.status.Hit {
+		t.Fatalf("expected a hit, got %+v", w.status)
+	}
+	if got := w.String(); got != "result" {
+		t.Errorf("unexpected cached result %q", got)
+	}
+	if len(f.queries) != 1 {
+		t.Errorf("expected the query to be executed once, got %d", len(f.queries))
+	}
+
+	// The next refresh interval misses.
+	w = f.query(t, ctx, telegrafQuery, now.Add(10*time.Second))
+	if w.status == nil || w.status.Hit {
+		t.Fatalf("expected a miss in the next interval, got %+v", w.status)
+	}
+	if len(f.queries) != 2 {
+		t.Errorf("expected the query to be executed twice, got %d", len(f.queries))
+	}
+}
+
+func TestProxyQueryService_PureImports(t *testing.T) {
+	f := newFixture(t, 1<<20)
+	ctx := authorizedContext(bucketID)
+	q := "import \"strings\"\n" + telegrafQuery
+
+	f.query(t, ctx, q, now)
+	if w := f.query(t, ctx, q, now); w.status == nil || !w.status.Hit {
+		t.Fatalf("expected a query importing a pure package to hit, got %+v", w.status)
+	}
+}
+
+func TestProxyQueryService_Bypass(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+	}{
+		{name: "to", q: `from(bucket: "telegraf") |> range(start: -1h) |> to(bucket: "other")`},
+		{name: "buckets", q: `buckets()`},
+		{name: "dynamic bucket", q: `b = "telegraf" from(bucket: b) |> range(start: -1h)`},
+		{name: "http", q: "import \"http\"\nhttp.post(url: \"http://localhost\")"},
+		{name: "sql", q: "import \"sql\"\nsql.from(driverName: \"postgres\", dataSourceName: \"\", query: \"\")"},
+		{name: "renamed v1", q: "import db \"influxdata/influxdb/v1\"\ndb.databases()"},
+		{name: "slack", q: "import \"slack\"\nfrom(bucket: \"telegraf\") |> range(start: -1h)"},
+		{name: "monitor", q: "import \"influxdata/influxdb/monitor\"\nfrom(bucket: \"telegraf\") |> range(start: -1h)"},
+		{name: "smtp", q: "import \"influxdata/influxdb/smtp\"\nfrom(bucket: \"telegraf\") |> range(start: -1h)"},
+		{name: "unknown bucket", q: `from(bucket: "missing") |> range(start: -1h)`},
+		{name: "syntax error", q: `from(bucket: "telegraf") |>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, 1<<20)
+			ctx := authorizedContext(bucketID, otherID)
+			f.query(t, ctx, tt.q, now)
+			w := f.query(t, ctx, tt.q, now)
+			if w.status != nil && w.status.Hit {
+				t.Errorf("expected the query not to be cached")
+			}
+			if len(f.queries) != 2 {
+				t.Errorf("expected the query to be executed twice, got %d", len(f.queries))
+			}
+		})
+	}
+}
+
+func TestProxyQueryService_Disabled(t *testing.T) {
+	f := newFixture(t, 0)
+	ctx := authorizedContext(bucketID)
+	f.query(t, ctx, telegrafQuery, now)
+	if w := f.query(t, ctx, telegrafQuery, now); w.status != nil {
+		t.Errorf("expected no cache status, got %+v", w.status)
+	}
+	if len(f.queries) != 2 {
+		t.Errorf("expected the query to be executed twice, got %d", len(f.queries))
+	}
+}
+
+func TestProxyQueryService_Invalidate(t *testing.T) {
+	f := newFixture(t, 1<<20)
+	ctx := authorizedContext(bucketID, otherID)
+	pw := &cache.PointsWriter{PointsWriter: &mock.PointsWriter{}, Cache: f.svc}
+
+	f.query(t, ctx, telegrafQuery, now)
+
+	// Writes to other buckets keep the cached result.
+	writePoints(t, pw, otherID)
+	if w := f.query(t, ctx, telegrafQuery, now); !w.status.Hit {
+		t.Fatal("expected a hit after writing to another bucket")
+	}
+
+	writePoints(t, pw, bucketID)
+	if w := f.query(t, ctx, telegrafQuery, now); w.status.Hit {
+		t.Fatal("expected a miss after writing to the bucket")
+	}
+	if w := f.query(t, ctx, telegrafQuery, now); !w.status.Hit {
+		t.Fatal("expected the result to be cached again")
+	}
+}
+
+func TestProxyQueryService_InvalidateDeletes(t *testing.T) {
+	f := newFixture(t, 1<<20)
+	ctx := authorizedContext(bucketID, otherID)
+	ds := &cache.DeleteService{DeleteService: mock.NewDeleteService(), Cache: f.svc}
+	bs := &cache.BucketService{BucketService: mock.NewBucketService(), Cache: f.svc}
+	rd := &cache.Deleter{Deleter: deleterFunc(func(context.Context, influxdb.ID, influxdb.ID, int64, int64) error { return nil }), Cache: f.svc}
+
+	f.query(t, ctx, telegrafQuery, now)
+	if err := ds.DeleteBucketRangePredicate(context.Background(), orgID, otherID, 0, now.UnixNano(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if w := f.query(t, ctx, telegrafQuery, now); !w.status.Hit {
+		t.Fatal("expected a hit after deleting from another bucket")
+	}
+
+	if err := ds.DeleteBucketRangePredicate(context.Background(), orgID, bucketID, 0, now.UnixNano(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if w := f.query(t, ctx, telegrafQuery, now); w.status.Hit {
+		t.Fatal("expected a miss after deleting from the bucket")
+	}
+
+	// the retention enforcer deletes expired data.
+	if err := rd.DeleteBucketRange(context.Background(), orgID, bucketID, 0, now.UnixNano()); err != nil {
+		t.Fatal(err)
+	}
+	if w := f.query(t, ctx, telegrafQuery, now); w.status.Hit {
+		t.Fatal("expected a miss after the expired data of the bucket was deleted")
+	}
+
+	name := "renamed"
+	if _, err := bs.UpdateBucket(context.Background(), bucketID, influxdb.BucketUpdate{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	if w := f.query(t, ctx, telegrafQuery, now); !w.status.Hit {
+		t.Fatal("expected a hit after an update that keeps the retention period")
+	}
+
+	retention := time.Hour
+	if _, err := bs.UpdateBucket(context.Background(), bucketID, influxdb.BucketUpdate{RetentionPeriod: &retention}); err != nil {
+		t.Fatal(err)
+	}
+	if w := f.query(t, ctx, telegrafQuery, now); w.status.Hit {
+		t.Fatal("expected a miss after changing the retention period of the bucket")
+	}
+
+	if err := bs.DeleteBucket(context.Background(), bucketID); err != nil {
+		t.Fatal(err)
+	}
+	if w := f.query(t, ctx, telegrafQuery, now); w.status.Hit {
+		t.Fatal("expected a miss after deleting the bucket")
+	}
+}
+
+// deleterFunc is a storage.Deleter calling the func.
+type deleterFunc func(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64) error
+
+func (f deleterFunc) DeleteBucketRange(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64) error {
+	return f(ctx, orgID, bucketID, min, max)
+}
+
+func TestProxyQueryService_BucketID(t *testing.T) {
+	f := newFixture(t, 1<<20)
+	ctx := authorizedContext(bucketID)
+	q := `from(bucketID: "` + bucketID.String() + `") |> range(start: -1h)`
+
+	f.query(t, ctx, q, now)
+	if w := f.query(t, ctx, q, now); !w.status.Hit {
+		t.Fatal("expected a hit")
+	}
+	f.svc.InvalidateBucket(bucketID)
+	if w := f.query(t, ctx, q, now); w.status.Hit {
+		t.Fatal("expected a miss after the bucket was invalidated")
+	}
+}
+
+func TestProxyQueryService_Unauthorized(t *testing.T) {
+	f := newFixture(t, 1<<20)
+	f.query(t, authorizedContext(bucketID), telegrafQuery, now)
+
+	// A request that may not read the bucket never gets the cached result.
+	w := f.query(t, authorizedContext(otherID), telegrafQuery, now)
+	if w.status != nil && w.status.Hit {
+		t.Fatal("expected the cached result not to be served")
+	}
+	if len(f.queries) != 2 {
+		t.Errorf("expected the query to be executed twice, got %d", len(f.queries))
+	}
+}
+
+func TestProxyQueryService_Errors(t *testing.T) {
+	f := newFixture(t, 1<<20)
+	f.err = errors.New("expected error")
+	ctx := authorizedContext(bucketID)
+
+	f.query(t, ctx, telegrafQuery, now)
+	if w := f.query(t, ctx, telegrafQuery, now); w.status.Hit {
+		t.Fatal("expected failed queries not to be cached")
+	}
+}
+
+func TestProxyQueryService_Eviction(t *testing.T) {
+	// The cache holds up to ten results of two bytes
+	// and results of more than two bytes are never cached.
+	f := newFixture(t, 20)
+	f.result = "ok"
+	ctx := authorizedContext(bucketID)
+
+	queries := make([]string, 11)
+	for i := range queries {
+		queries[i] = fmt.Sprintf(`from(bucket: "telegraf") |> range(start: -%dh)`, i+1)
+		f.query(t, ctx, queries[i], now)
+	}
+	if w := f.query(t, ctx, queries[10], now); !w.status.Hit {
+		t.Error("expected the most recent result to be cached")
+	}
+	if w := f.query(t, ctx, queries[0], now); w.status.Hit {
+		t.Error("expected the least recently used result to be evicted")
+	}
+
+	f.result = "too large"
+	q := `from(bucket: "telegraf") |> range(start: -1d)`
+	f.query(t, ctx, q, now)
+	if w := f.query(t, ctx, q, now); w.status.Hit {
+		t.Error("expected large results not to be cached")
+	}
+}
+
+func TestProxyQueryService_Compilers(t *testing.T) {
+	f := newFixture(t, 1<<20)
+	ctx := authorizedContext(bucketID)
+
+	f.request(t, ctx, repl.Compiler{})
+	if w := f.request(t, ctx, repl.Compiler{}); w.status != nil {
+		t.Errorf("expected repl queries to bypass the cache, got %+v", w.status)
+	}
+}
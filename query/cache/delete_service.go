@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/storage"
+)
+
+// DeleteService invalidates the cached results that read the buckets
+// that data is deleted from.
+type DeleteService struct {
+	influxdb.DeleteService
+	Cache *ProxyQueryService
+}
+
+// DeleteBucketRangePredicate deletes the data and invalidates the cached results of the bucket.
+func (s *DeleteService) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
+	err := s.DeleteService.DeleteBucketRangePredicate(ctx, orgID, bucketID, min, max, pred)
+
+	// Invalidate the bucket even if the delete failed,
+	// some of the data may have been deleted.
+	s.Cache.InvalidateBucket(bucketID)
+	return err
+}
+
+// Deleter invalidates the cached results that read the buckets whose
+// data is deleted, such as the expired data the retention enforcer deletes.
+type Deleter struct {
+	storage.Deleter
+	Cache *ProxyQueryService
+}
+
+// DeleteBucketRange deletes the data and invalidates the cached results of the bucket.
+func (d *Deleter) DeleteBucketRange(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64) error {
+	err := d.Deleter.DeleteBucketRange(ctx, orgID, bucketID, min, max)
+	d.Cache.InvalidateBucket(bucketID)
+	return err
+}
+
+// BucketService invalidates the cached results that read the buckets
+// that are deleted or whose retention period changes.
+type BucketService struct {
+	influxdb.BucketService
+	Cache *ProxyQueryService
+}
+
+// UpdateBucket updates the bucket and invalidates its cached results
+// when its retention period changes.
+func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+	b, err := s.BucketService.UpdateBucket(ctx, id, upd)
+	if err == nil && upd.RetentionPeriod != nil {
+		s.Cache.InvalidateBucket(id)
+	}
+	return b, err
+}
+
+// DeleteBucket deletes the bucket and invalidates its cached results.
+func (s *BucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error {
+	err := s.BucketService.DeleteBucket(ctx, id)
+	s.Cache.InvalidateBucket(id)
+	return err
+}
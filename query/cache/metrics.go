@@ -0,0 +1,73 @@
+package cache
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	labelHit    = "hit"
+	labelMiss   = "miss"
+	labelBypass = "bypass"
+)
+
+// metrics holds the metrics of the query result cache.
+type metrics struct {
+	requests      *prometheus.CounterVec
+	evictions     prometheus.Counter
+	invalidations prometheus.Counter
+	entries       prometheus.Gauge
+	size          prometheus.Gauge
+}
+
+func newMetrics() *metrics {
+	const (
+		namespace = "query"
+		subsystem = "cache"
+	)
+
+	return &metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "requests_total",
+			Help:      "Count of the query requests by cache result: hit, miss or bypass for queries that can not be cached",
+		}, []string{"result"}),
+
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "evictions_total",
+			Help:      "Count of the cached results evicted to stay within the cache size",
+		}),
+
+		invalidations: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "invalidations_total",
+			Help:      "Count of the cached results removed by writes to the buckets they read",
+		}),
+
+		entries: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "entries",
+			Help:      "Number of cached results",
+		}),
+
+		size: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "size_bytes",
+			Help:      "Total size of the cached results",
+		}),
+	}
+}
+
+// PrometheusCollectors returns the metrics of the cache.
+func (s *ProxyQueryService) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		s.metrics.requests,
+		s.metrics.evictions,
+		s.metrics.invalidations,
+		s.metrics.entries,
+		s.metrics.size,
+	}
+}
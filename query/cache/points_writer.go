@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+// PointsWriter invalidates the cached results that read the buckets
+// that points are written to.
+type PointsWriter struct {
+	storage.PointsWriter
+	Cache *ProxyQueryService
+}
+
+// WritePoints writes the points and invalidates the cached results of their buckets.
+func (w *PointsWriter) WritePoints(ctx context.Context, points []models.Point) error {
+	err := w.PointsWriter.WritePoints(ctx, points)
+
+	// Invalidate the buckets even if the write failed,
+	// some of the points may have been written.
+	var last influxdb.ID
+	seen := make(map[influxdb.ID]bool)
+	for _, p := range points {
+		name := p.Name()
+		if len(name) < influxdb.IDLength {
+			continue
+		}
+		_, bucketID := tsdb.DecodeNameSlice(name)
+		if bucketID == last || seen[bucketID] {
+			continue
+		}
+		last, seen[bucketID] = bucketID, true
+		w.Cache.InvalidateBucket(bucketID)
+	}
+	return err
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"path"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+)
+
+// cacheableImports are the packages known to neither have side effects nor
+// read data outside of the storage engine. Queries importing any other
+// package are not cached, as a cache hit would skip their side effects and
+// their results can not be invalidated by writes.
+var cacheableImports = map[string]bool{
+	"date":                        true,
+	"math":                        true,
+	"regexp":                      true,
+	"strings":                     true,
+	"influxdata/influxdb/anomaly": true,
+	"influxdata/influxdb/schema":  true,
+	"influxdata/influxdb/v1":      true,
+}
+
+// uncacheableFunctions are the functions of the universe and
+// influxdata/influxdb/v1 packages with side effects or results
+// that do not depend on the data of a bucket.
+var uncacheableFunctions = map[string]bool{
+	"buckets":                          true,
+	"systemTime":                       true,
+	"to":                               true,
+	"influxdata/influxdb/v1.databases": true,
+}
+
+// bucketReads are the buckets read by a query, by name or by ID.
+type bucketReads struct {
+	names []string
+	ids   []influxdb.ID
+}
+
+// readsOf returns the buckets read by the files of a query. Every call
+// that has a bucket or bucketID argument is assumed to read the bucket.
+// It returns false if the query can not be cached, either because it
+// uses a function whose result can not be invalidated or because it
+// reads a bucket that is not known until the query is evaluated.
+func readsOf(files []*ast.File) (bucketReads, bool) {
+	v := &readsVisitor{
+		imports: make(map[string]string),
+		ok:      true,
+	}
+	for _, f := range files {
+		for _, imp := range f.Imports {
+			p := imp.Path.Value
+			if !cacheableImports[p] {
+				return bucketReads{}, false
+			}
+			name := path.Base(p)
+			if imp.As != nil && imp.As.Name != "" {
+				name = imp.As.Name
+			}
+			v.imports[name] = p
+		}
+	}
+	for _, f := range files {
+		ast.Walk(v, f)
+		if !v.ok {
+			return bucketReads{}, false
+		}
+	}
+	return v.reads, true
+}
+
+type readsVisitor struct {
+	// imports maps the names of the imported packages to their paths.
+	imports map[string]string
+	reads   bucketReads
+	ok      bool
+}
+
+func (v *readsVisitor) Visit(node ast.Node) ast.Visitor {
+	if !v.ok {
+		return nil
+	}
+	call, ok := node.(*ast.CallExpression)
+	if !ok {
+		return v
+	}
+	if uncacheableFunctions[v.functionName(call.Callee)] {
+		v.ok = false
+		return nil
+	}
+	for _, arg := range call.Arguments {
+		obj, ok := arg.(*ast.ObjectExpression)
+		if !ok {
+			continue
+		}
+		for _, p := range obj.Properties {
+			if p.Key == nil {
+				continue
+			}
+			switch p.Key.Key() {
+			case "bucket":
+				lit, ok := p.Value.(*ast.StringLiteral)
+				if !ok {
+					v.ok = false
+					return nil
+				}
+				v.reads.names = append(v.reads.names, lit.Value)
+			case "bucketID":
+				lit, ok := p.Value.(*ast.StringLiteral)
+				if !ok {
+					v.ok = false
+					return nil
+				}
+				id, err := influxdb.IDFromString(lit.Value)
+				if err != nil {
+					v.ok = false
+					return nil
+				}
+				v.reads.ids = append(v.reads.ids, *id)
+			}
+		}
+	}
+	return v
+}
+
+func (v *readsVisitor) Done(node ast.Node) {}
+
+// functionName returns the name of the called function qualified by
+// the path of its package, or just its name for universe functions.
+func (v *readsVisitor) functionName(callee ast.Expression) string {
+	switch c := callee.(type) {
+	case *ast.Identifier:
+		return c.Name
+	case *ast.MemberExpression:
+		obj, ok := c.Object.(*ast.Identifier)
+		if !ok || c.Property == nil {
+			return ""
+		}
+		if p, ok := v.imports[obj.Name]; ok {
+			return p + "." + c.Property.Key()
+		}
+	}
+	return ""
+}
@@ -64,6 +64,7 @@ type Controller struct {
 	log *zap.Logger
 
 	dependencies []flux.Dependency
+	scanLimits   query.ScanLimitsConfig
 }
 
 type Config struct {
@@ -99,6 +100,11 @@ type Config struct {
 	MetricLabelKeys []string
 
 	ExecutorDependencies []flux.Dependency
+
+	// ScanLimits limits the number of values and series a single query is
+	// allowed to read from storage. The limits depend on the organization
+	// of the query and on whether it was issued by a user, a task or a check.
+	ScanLimits query.ScanLimitsConfig
 }
 
 // complete will fill in the defaults, validate the configuration, and
@@ -136,6 +142,19 @@ func (c *Config) validate(isComplete bool) error {
 	if c.QueueSize <= 0 {
 		return errors.New("QueueSize must be positive")
 	}
+	for _, l := range []query.ScanLimits{c.ScanLimits.Query, c.ScanLimits.Task, c.ScanLimits.Check} {
+		if l.MaxScannedValues < 0 || l.MaxSeries < 0 {
+			return errors.New("ScanLimits must not be negative")
+		}
+	}
+	for _, l := range c.ScanLimits.Orgs {
+		if err := l.Kind.Valid(); err != nil {
+			return err
+		}
+		if l.MaxScannedValues < 0 || l.MaxSeries < 0 {
+			return fmt.Errorf("ScanLimits for organization %s must not be negative", l.OrgID)
+		}
+	}
 	return nil
 }
 
@@ -182,6 +201,7 @@ func New(config Config) (*Controller, error) {
 		metrics:      newControllerMetrics(c.MetricLabelKeys),
 		labelKeys:    c.MetricLabelKeys,
 		dependencies: c.ExecutorDependencies,
+		scanLimits:   c.ScanLimits,
 	}
 	ctrl.wg.Add(c.ConcurrencyQuota)
 	for i := 0; i < c.ConcurrencyQuota; i++ {
@@ -206,6 +226,11 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 	for _, dep := range c.dependencies {
 		ctx = dep.Inject(ctx)
 	}
+	// Storage reads count the data they read against the scan limits of the query.
+	kind := query.KindFromContext(ctx)
+	if limits := c.scanLimits.Limits(req.OrganizationID, kind); !limits.IsZero() {
+		ctx = query.ContextWithScanLimiter(ctx, query.NewScanLimiter(kind, limits))
+	}
 	q, err := c.query(ctx, req.Compiler)
 	if err != nil {
 		return q, err
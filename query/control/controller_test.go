@@ -20,6 +20,7 @@ import (
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/stdlib/universe"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/query"
 	_ "github.com/influxdata/influxdb/query/builtin"
 	"github.com/influxdata/influxdb/query/control"
@@ -1254,6 +1255,110 @@ func TestController_ReserveMemoryWithoutExceedingMax(t *testing.T) {
 	}
 }
 
+func TestController_ScanLimits(t *testing.T) {
+	orgID := platform.ID(1)
+	otherOrgID := platform.ID(2)
+
+	c := config
+	c.ScanLimits = query.ScanLimitsConfig{
+		Query: query.ScanLimits{MaxScannedValues: 100, MaxSeries: 10},
+		Check: query.ScanLimits{MaxSeries: 5},
+		Orgs: []query.OrgScanLimits{
+			{OrgID: orgID, Kind: query.KindTask, ScanLimits: query.ScanLimits{MaxScannedValues: 1000}},
+		},
+	}
+	ctrl, err := control.New(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	tests := []struct {
+		name  string
+		kind  query.Kind
+		orgID platform.ID
+		want  *query.ScanLimits
+	}{
+		{
+			name:  "query",
+			orgID: otherOrgID,
+			want:  &query.ScanLimits{MaxScannedValues: 100, MaxSeries: 10},
+		},
+		{
+			name:  "check",
+			kind:  query.KindCheck,
+			orgID: otherOrgID,
+			want:  &query.ScanLimits{MaxSeries: 5},
+		},
+		{
+			name:  "unlimited task",
+			kind:  query.KindTask,
+			orgID: otherOrgID,
+		},
+		{
+			name:  "org task",
+			kind:  query.KindTask,
+			orgID: orgID,
+			want:  &query.ScanLimits{MaxScannedValues: 1000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var limiter *query.ScanLimiter
+			compiler := &mock.Compiler{
+				CompileFn: func(ctx context.Context) (flux.Program, error) {
+					limiter = query.ScanLimiterFromContext(ctx)
+					return mockCompiler.Compile(ctx)
+				},
+			}
+
+			ctx := context.Background()
+			if tt.kind != "" {
+				ctx = query.ContextWithKind(ctx, tt.kind)
+			}
+			req := makeRequest(compiler)
+			req.OrganizationID = tt.orgID
+			q, err := ctrl.Query(ctx, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			consumeResults(t, q)
+
+			if tt.want == nil {
+				if limiter != nil {
+					t.Fatalf("expected query to be unlimited, got limits %+v", limiter.Limits())
+				}
+				return
+			}
+			if limiter == nil {
+				t.Fatal("expected query to have scan limits")
+			}
+			if got := limiter.Limits(); got != *tt.want {
+				t.Errorf("unexpected scan limits; got %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestController_InvalidScanLimits(t *testing.T) {
+	c := config
+	c.ScanLimits = query.ScanLimitsConfig{
+		Orgs: []query.OrgScanLimits{
+			{OrgID: 1, Kind: "dashboard"},
+		},
+	}
+	if _, err := control.New(c); err == nil {
+		t.Fatal("expected an error for an unknown kind of query")
+	}
+
+	c.ScanLimits = query.ScanLimitsConfig{
+		Task: query.ScanLimits{MaxSeries: -1},
+	}
+	if _, err := control.New(c); err == nil {
+		t.Fatal("expected an error for a negative limit")
+	}
+}
+
 func consumeResults(tb testing.TB, q flux.Query) {
 	tb.Helper()
 	for res := range q.Results() {
@@ -0,0 +1,333 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/values"
+)
+
+// StorageReadSpec is implemented by physical procedure specs that are
+// evaluated by the storage engine as a single read request. Specs that
+// implement it are reported as pushdowns by Explain.
+type StorageReadSpec interface {
+	plan.PhysicalProcedureSpec
+
+	// StorageOperation returns the name of the storage read that evaluates
+	// the spec, e.g. ReadFilter or ReadGroup.
+	StorageOperation() string
+}
+
+// Explanation describes how a query was planned and, when profiled, how
+// each of the resulting operators behaved during execution.
+type Explanation struct {
+	// Logical is the logical plan after the logical rules have been applied.
+	Logical []*PlanNode `json:"logical"`
+	// Physical is the physical plan after the physical rules have been applied.
+	Physical []*PlanNode `json:"physical"`
+	// Pushdowns lists the chains of operations that were pushed into a storage read.
+	Pushdowns []Pushdown `json:"pushdowns"`
+	// Profile is only set when the query was executed with profiling enabled.
+	Profile *Profile `json:"profile,omitempty"`
+}
+
+// PlanNode is a node in a query plan. A plan is represented as a forest
+// rooted at the query results, with every node pointing at its inputs.
+type PlanNode struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+
+	// StorageOperation is the storage read that evaluates this node.
+	// It is only set for physical nodes that were pushed down.
+	StorageOperation string `json:"storageOperation,omitempty"`
+
+	Predecessors []*PlanNode `json:"predecessors,omitempty"`
+}
+
+// Pushdown describes a chain of logical operations that the physical
+// planner replaced with a single storage read.
+type Pushdown struct {
+	// Node is the ID of the physical node that performs the storage read.
+	Node string `json:"node"`
+	// StorageOperation is the storage read that evaluates the chain.
+	StorageOperation string `json:"storageOperation"`
+	// Operations lists the logical operations that were pushed down,
+	// ordered from the source to the last operation in the chain.
+	Operations []PushdownOperation `json:"operations"`
+}
+
+// PushdownOperation is a logical operation that was absorbed by a storage read.
+type PushdownOperation struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+}
+
+// ExplainedPlan is the result of planning a query for an explanation.
+type ExplainedPlan struct {
+	*Explanation
+
+	// Spec is the physical plan that the explanation was produced from.
+	// It can be instrumented and executed to profile the query.
+	Spec *plan.Spec
+}
+
+// Explain evaluates the flux package and plans the resulting query
+// without executing it. The returned plan reports the logical and
+// physical plans and which operations were pushed down to storage.
+func Explain(ctx context.Context, pkg *ast.Package, now time.Time) (*ExplainedPlan, error) {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	spec, err := specFromAST(ctx, pkg, now)
+	if err != nil {
+		return nil, err
+	}
+	return ExplainSpec(spec)
+}
+
+// ExplainSpec plans the flux spec and reports the logical and physical plans.
+func ExplainSpec(spec *flux.Spec) (*ExplainedPlan, error) {
+	lp := plan.NewLogicalPlanner()
+	initial, err := lp.CreateInitialPlan(spec)
+	if err != nil {
+		return nil, err
+	}
+	logical, err := lp.Plan(initial)
+	if err != nil {
+		return nil, err
+	}
+
+	// The physical planner rewrites the plan in place, so the logical
+	// plan must be recorded before physical planning starts.
+	e := &Explanation{
+		Logical: planTree(logical),
+	}
+	logicalNodes := planNodes(logical)
+
+	physical, err := plan.NewPhysicalPlanner().Plan(logical)
+	if err != nil {
+		return nil, err
+	}
+	e.Physical = planTree(physical)
+	e.Pushdowns = findPushdowns(physical, logicalNodes)
+
+	return &ExplainedPlan{
+		Explanation: e,
+		Spec:        physical,
+	}, nil
+}
+
+// specFromAST evaluates the package and converts the table objects it
+// yields into a flux spec.
+func specFromAST(ctx context.Context, pkg *ast.Package, now time.Time) (*flux.Spec, error) {
+	sideEffects, scope, err := flux.EvalAST(ctx, pkg, flux.SetNowOption(now))
+	if err != nil {
+		return nil, err
+	}
+	nowOpt, ok := scope.Lookup(flux.NowOption)
+	if !ok {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  fmt.Sprintf("%q option not set", flux.NowOption),
+		}
+	}
+	nowTime, err := nowOpt.Function().Call(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return specFromSideEffects(sideEffects, nowTime.Time().Time())
+}
+
+func specFromSideEffects(ses []interpreter.SideEffect, now time.Time) (*flux.Spec, error) {
+	ider := &tableObjectIDer{
+		lookup: make(map[*flux.TableObject]flux.OperationID),
+	}
+	spec := &flux.Spec{Now: now}
+	visited := make(map[*flux.TableObject]bool)
+	for _, se := range ses {
+		if to, ok := se.Value.(*flux.TableObject); ok && !visited[to] {
+			buildSpec(to, ider, spec, visited)
+		}
+	}
+	if len(spec.Operations) == 0 {
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  "this Flux script returns no streaming data",
+		}
+	}
+	return spec, nil
+}
+
+func buildSpec(t *flux.TableObject, ider *tableObjectIDer, spec *flux.Spec, visited map[*flux.TableObject]bool) {
+	t.Parents.Range(func(i int, v values.Value) {
+		if p := v.(*flux.TableObject); !visited[p] {
+			buildSpec(p, ider, spec, visited)
+		}
+	})
+	id := ider.ID(t)
+	t.Parents.Range(func(i int, v values.Value) {
+		spec.Edges = append(spec.Edges, flux.Edge{
+			Parent: ider.ID(v.(*flux.TableObject)),
+			Child:  id,
+		})
+	})
+	visited[t] = true
+	spec.Operations = append(spec.Operations, t.Operation(ider))
+}
+
+// tableObjectIDer assigns operation IDs to table objects in the same
+// way the flux compiler does, so explained node IDs match the IDs
+// reported by a regular query.
+type tableObjectIDer struct {
+	next   int
+	lookup map[*flux.TableObject]flux.OperationID
+}
+
+func (i *tableObjectIDer) ID(t *flux.TableObject) flux.OperationID {
+	if id, ok := i.lookup[t]; ok {
+		return id
+	}
+	id := flux.OperationID(fmt.Sprintf("%s%d", t.Kind, i.next))
+	i.next++
+	i.lookup[t] = id
+	return id
+}
+
+// planTree converts the plan into trees of PlanNodes rooted at the plan roots.
+func planTree(p *plan.Spec) []*PlanNode {
+	converted := make(map[plan.Node]*PlanNode)
+	var convert func(n plan.Node) *PlanNode
+	convert = func(n plan.Node) *PlanNode {
+		if pn, ok := converted[n]; ok {
+			return pn
+		}
+		pn := &PlanNode{
+			ID:   string(n.ID()),
+			Kind: string(n.Kind()),
+		}
+		if s, ok := n.ProcedureSpec().(StorageReadSpec); ok {
+			pn.StorageOperation = s.StorageOperation()
+		}
+		converted[n] = pn
+		for _, pred := range n.Predecessors() {
+			pn.Predecessors = append(pn.Predecessors, convert(pred))
+		}
+		return pn
+	}
+
+	roots := make([]*PlanNode, 0, len(p.Roots))
+	for root := range p.Roots {
+		roots = append(roots, convert(root))
+	}
+	// Roots are stored in a map, so sort them to keep the output stable.
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i].ID < roots[j].ID
+	})
+	return roots
+}
+
+// logicalNode is the part of a logical plan node that is needed to
+// reconstruct which operations were pushed down once the physical
+// planner has rewritten the plan.
+type logicalNode struct {
+	id           plan.NodeID
+	kind         plan.ProcedureKind
+	predecessors []plan.NodeID
+}
+
+func planNodes(p *plan.Spec) map[plan.NodeID]logicalNode {
+	nodes := make(map[plan.NodeID]logicalNode)
+	_ = p.BottomUpWalk(func(n plan.Node) error {
+		ln := logicalNode{
+			id:   n.ID(),
+			kind: n.Kind(),
+		}
+		for _, pred := range n.Predecessors() {
+			ln.predecessors = append(ln.predecessors, pred.ID())
+		}
+		nodes[n.ID()] = ln
+		return nil
+	})
+	return nodes
+}
+
+// findPushdowns reports the logical operations absorbed by each storage
+// read in the physical plan. Nodes that are not rewritten by the physical
+// planner keep their logical ID, so the operations absorbed by a storage
+// read are the logical ancestors of its successor that no longer exist
+// in the physical plan.
+func findPushdowns(physical *plan.Spec, logical map[plan.NodeID]logicalNode) []Pushdown {
+	remaining := make(map[plan.NodeID]bool)
+	_ = physical.BottomUpWalk(func(n plan.Node) error {
+		remaining[n.ID()] = true
+		return nil
+	})
+
+	var pushdowns []Pushdown
+	_ = physical.BottomUpWalk(func(n plan.Node) error {
+		s, ok := n.ProcedureSpec().(StorageReadSpec)
+		if !ok {
+			return nil
+		}
+		pd := Pushdown{
+			Node:             string(n.ID()),
+			StorageOperation: s.StorageOperation(),
+		}
+		for _, succ := range n.Successors() {
+			ln, ok := logical[succ.ID()]
+			if !ok {
+				continue
+			}
+			idx := predecessorIndex(succ, n)
+			if idx < 0 || idx >= len(ln.predecessors) {
+				continue
+			}
+			pd.Operations = absorbedOperations(ln.predecessors[idx], logical, remaining)
+			break
+		}
+		pushdowns = append(pushdowns, pd)
+		return nil
+	})
+	return pushdowns
+}
+
+func predecessorIndex(n, pred plan.Node) int {
+	for i, p := range n.Predecessors() {
+		if p == pred {
+			return i
+		}
+	}
+	return -1
+}
+
+// absorbedOperations walks up the logical plan from id and returns the
+// chain of operations that were removed by the physical planner.
+func absorbedOperations(id plan.NodeID, logical map[plan.NodeID]logicalNode, remaining map[plan.NodeID]bool) []PushdownOperation {
+	var ops []PushdownOperation
+	for {
+		ln, ok := logical[id]
+		if !ok || remaining[id] {
+			break
+		}
+		ops = append(ops, PushdownOperation{
+			ID:   string(ln.id),
+			Kind: string(ln.kind),
+		})
+		if len(ln.predecessors) != 1 {
+			break
+		}
+		id = ln.predecessors[0]
+	}
+	// The walk started at the end of the chain, report it from the source.
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
@@ -0,0 +1,187 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb/query"
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+func TestExplain_Pushdowns(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []query.Pushdown
+	}{
+		{
+			name:   "range",
+			script: `from(bucket: "b") |> range(start: -1h)`,
+			want: []query.Pushdown{
+				{
+					Node:             "ReadRange",
+					StorageOperation: "ReadFilter",
+					Operations: []query.PushdownOperation{
+						{ID: "influxDBFrom0", Kind: "influxDBFrom"},
+						{ID: "range1", Kind: "range"},
+					},
+				},
+			},
+		},
+		{
+			name: "filter and group",
+			script: `from(bucket: "b")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> group(columns: ["host"])
+	|> sum()`,
+			want: []query.Pushdown{
+				{
+					Node:             "ReadGroup",
+					StorageOperation: "ReadGroup",
+					Operations: []query.PushdownOperation{
+						{ID: "influxDBFrom0", Kind: "influxDBFrom"},
+						{ID: "range1", Kind: "range"},
+						{ID: "filter2", Kind: "filter"},
+						{ID: "group3", Kind: "group"},
+					},
+				},
+			},
+		},
+		{
+			name: "partial filter",
+			script: `from(bucket: "b")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._value + 1.0 > 0.0)`,
+			want: []query.Pushdown{
+				{
+					Node:             "ReadRange",
+					StorageOperation: "ReadFilter",
+					Operations: []query.PushdownOperation{
+						{ID: "influxDBFrom0", Kind: "influxDBFrom"},
+						{ID: "range1", Kind: "range"},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parser.ParseSource(tt.script)
+			e, err := query.Explain(context.Background(), pkg, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tt.want, e.Pushdowns) {
+				t.Errorf("unexpected pushdowns -want/+got:\n%s", cmp.Diff(tt.want, e.Pushdowns))
+			}
+			if len(e.Logical) != 1 || len(e.Physical) != 1 {
+				t.Fatalf("expected a single result, got %d logical and %d physical", len(e.Logical), len(e.Physical))
+			}
+		})
+	}
+}
+
+func TestExplain_StorageOperation(t *testing.T) {
+	pkg := parser.ParseSource(`from(bucket: "b") |> range(start: -1h) |> count()`)
+	e, err := query.Explain(context.Background(), pkg, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ops []string
+	var walk func(n *query.PlanNode)
+	walk = func(n *query.PlanNode) {
+		if n.StorageOperation != "" {
+			ops = append(ops, n.ID+":"+n.StorageOperation)
+		}
+		for _, pred := range n.Predecessors {
+			walk(pred)
+		}
+	}
+	for _, root := range e.Physical {
+		walk(root)
+	}
+	if want := []string{"ReadRange:ReadFilter"}; !cmp.Equal(want, ops) {
+		t.Errorf("unexpected storage operations -want/+got:\n%s", cmp.Diff(want, ops))
+	}
+	for _, root := range e.Logical {
+		walk(root)
+	}
+	if len(ops) != 1 {
+		t.Errorf("logical plan must not report storage operations, got %v", ops)
+	}
+}
+
+func TestProfiler(t *testing.T) {
+	script := `import "csv"
+
+data = "
+#datatype,string,long,dateTime:RFC3339,double,string
+#group,false,false,false,false,true
+#default,_result,,,,
+,result,table,_time,_value,host
+,,0,2019-01-01T00:00:00Z,1.0,a
+,,0,2019-01-01T00:00:10Z,2.0,a
+,,1,2019-01-01T00:00:00Z,3.0,b
+"
+
+csv.from(csv: data) |> filter(fn: (r) => r._value > 1.0)`
+
+	e, err := query.Explain(context.Background(), parser.ParseSource(script), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiler := query.NewProfiler()
+	if err := profiler.Instrument(e.Spec); err != nil {
+		t.Fatal(err)
+	}
+
+	prog, err := query.ProfileCompiler{Spec: e.Spec}.Compile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := prog.Start(context.Background(), &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows int
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				rows += cr.Len()
+				return nil
+			})
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 2 {
+		t.Fatalf("instrumented query returned %d rows, want 2", rows)
+	}
+
+	p := profiler.Profile(q.Statistics())
+	got := make(map[string][2]int64)
+	for _, op := range p.Operators {
+		got[op.Kind] = [2]int64{op.Tables, op.Rows}
+		if op.Tables > 0 && op.Bytes == 0 {
+			t.Errorf("operator %s produced tables without any bytes", op.Node)
+		}
+	}
+	want := map[string][2]int64{
+		"fromCSV": {2, 3},
+		"filter":  {2, 2},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected operator output -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
@@ -31,17 +31,21 @@ type varRefCursor struct {
 }
 
 // createVarRefCursor creates a new cursor from a variable reference using the sources
-// in the transpilerState.
-func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error) {
+// in the transpilerState. The cursor reads the points within the time range.
+func createVarRefCursor(t *transpilerState, ref *influxql.VarRef, tr influxql.TimeRange) (cursor, error) {
 	if len(t.stmt.Sources) != 1 {
 		// TODO(jsternberg): Support multiple sources.
 		return nil, errors.New("unimplemented: only one source is allowed")
 	}
 
-	// Only support a direct measurement. Subqueries are not supported yet.
-	mm, ok := t.stmt.Sources[0].(*influxql.Measurement)
-	if !ok {
-		return nil, errors.New("unimplemented: source must be a measurement")
+	var mm *influxql.Measurement
+	switch source := t.stmt.Sources[0].(type) {
+	case *influxql.Measurement:
+		mm = source
+	case *influxql.SubQuery:
+		return createSubQueryCursor(t, source, ref, tr)
+	default:
+		return nil, errors.New("unimplemented: source must be a measurement or a subquery")
 	}
 
 	// Create the from spec and add it to the list of operations.
@@ -50,20 +54,6 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 		return nil, err
 	}
 
-	valuer := influxql.NowValuer{Now: t.config.Now}
-	_, tr, err := influxql.ConditionExpr(t.stmt.Condition, &valuer)
-	if err != nil {
-		return nil, err
-	}
-
-	// If the maximum is not set and we have a windowing function, then
-	// the end time will be set to now.
-	if tr.Max.IsZero() {
-		if window, err := t.stmt.GroupByInterval(); err == nil && window > 0 {
-			tr.Max = t.config.Now
-		}
-	}
-
 	range_ := &ast.PipeExpression{
 		Argument: from,
 		Call: &ast.CallExpression{
@@ -86,7 +76,7 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 								Name: "stop",
 							},
 							Value: &ast.DateTimeLiteral{
-								Value: tr.MaxTime().UTC(),
+								Value: rangeStop(tr).UTC(),
 							},
 						},
 					},
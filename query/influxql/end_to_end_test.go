@@ -50,97 +50,77 @@ func init() {
 }
 
 var skipTests = map[string]string{
-	"hardcoded_literal_1":      "transpiler count query is off by 1 https://github.com/influxdata/influxdb/issues/10744",
-	"hardcoded_literal_3":      "transpiler count query is off by 1 https://github.com/influxdata/influxdb/issues/10744",
-	"fuzz_join_within_cursor":  "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
-	"derivative_count":         "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_first":         "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_last":          "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_max":           "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_mean":          "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_median":        "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_min":           "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_mode":          "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_percentile_10": "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_percentile_50": "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_percentile_90": "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"derivative_sum":           "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"regex_measurement_0":      "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
-	"regex_measurement_1":      "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
-	"regex_measurement_2":      "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
-	"regex_measurement_3":      "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
-	"regex_measurement_4":      "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
-	"regex_measurement_5":      "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
-	"regex_tag_0":              "Transpiler: Returns results in wrong sort order for regex filter on tags https://github.com/influxdata/influxdb/issues/10739",
-	"regex_tag_1":              "Transpiler: Returns results in wrong sort order for regex filter on tags https://github.com/influxdata/influxdb/issues/10739",
-	"regex_tag_2":              "Transpiler: Returns results in wrong sort order for regex filter on tags https://github.com/influxdata/influxdb/issues/10739",
-	"regex_tag_3":              "Transpiler: Returns results in wrong sort order for regex filter on tags https://github.com/influxdata/influxdb/issues/10739",
-	"explicit_type_0":          "Transpiler should remove _start column https://github.com/influxdata/influxdb/issues/10742",
-	"explicit_type_1":          "Transpiler should remove _start column https://github.com/influxdata/influxdb/issues/10742",
-	"fills_0":                  "need fill/Interpolate function https://github.com/influxdata/flux/issues/436",
-	"random_math_0":            "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
-	"selector_0":               "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"selector_1":               "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"selector_2":               "Transpiler: first function uses different series than influxQL https://github.com/influxdata/influxdb/issues/10737",
-	"selector_6":               "Transpiler: first function uses different series than influxQL https://github.com/influxdata/influxdb/issues/10737",
-	"selector_7":               "Transpiler: first function uses different series than influxQL https://github.com/influxdata/influxdb/issues/10737",
-	"series_agg_0":             "Transpiler: Implement difference https://github.com/influxdata/influxdb/issues/10736",
-	"series_agg_1":             "Transpiler: Implement stddev https://github.com/influxdata/influxdb/issues/10735",
-	"series_agg_2":             "Transpiler: Implement spread https://github.com/influxdata/influxdb/issues/10734",
-	"series_agg_3":             "Transpiler: Implement elapsed https://github.com/influxdata/influxdb/issues/10733",
-	"series_agg_4":             "Transpiler: Implement cumulative_sum https://github.com/influxdata/influxdb/issues/10732",
-	"series_agg_5":             "add derivative support to the transpiler https://github.com/influxdata/influxdb/issues/10759",
-	"series_agg_6":             "Transpiler: Implement non_negative_derivative https://github.com/influxdata/influxdb/issues/10731",
-	"series_agg_7":             "Transpiler should remove _start column  https://github.com/influxdata/influxdb/issues/10742",
-	"series_agg_8":             "Transpiler should remove _start column  https://github.com/influxdata/influxdb/issues/10742",
-	"series_agg_9":             "Transpiler should remove _start column  https://github.com/influxdata/influxdb/issues/10742",
-	"Subquery_0":               "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"Subquery_1":               "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"Subquery_2":               "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"Subquery_3":               "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"Subquery_4":               "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"Subquery_5":               "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"NestedSubquery_0":         "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"NestedSubquery_1":         "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"NestedSubquery_2":         "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"NestedSubquery_3":         "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"SimulatedHTTP_0":          "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"SimulatedHTTP_1":          "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"SimulatedHTTP_2":          "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"SimulatedHTTP_3":          "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"SimulatedHTTP_4":          "Implement subqueries in the transpiler https://github.com/influxdata/influxdb/issues/10660",
-	"SelectorMath_0":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_1":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_2":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_3":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_4":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_5":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_6":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_7":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_8":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_9":           "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_10":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_11":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_12":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_13":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_14":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_15":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_16":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_17":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_18":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_19":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_20":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_21":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_22":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_23":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_24":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_25":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_26":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_27":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_28":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_29":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_30":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
-	"SelectorMath_31":          "Transpiler: unimplemented functions: top and bottom https://github.com/influxdata/influxdb/issues/10738",
+	"hardcoded_literal_1":     "transpiler count query is off by 1 https://github.com/influxdata/influxdb/issues/10744",
+	"hardcoded_literal_3":     "transpiler count query is off by 1 https://github.com/influxdata/influxdb/issues/10744",
+	"fuzz_join_within_cursor": "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"derivative_mode":         "Transpiler: Implement mode",
+	"regex_measurement_0":     "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
+	"regex_measurement_1":     "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
+	"regex_measurement_2":     "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
+	"regex_measurement_3":     "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
+	"regex_measurement_4":     "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
+	"regex_measurement_5":     "Transpiler: regex on measurements not evaluated https://github.com/influxdata/influxdb/issues/10740",
+	"regex_tag_0":             "Transpiler: Returns results in wrong sort order for regex filter on tags https://github.com/influxdata/influxdb/issues/10739",
+	"regex_tag_1":             "Transpiler: Returns results in wrong sort order for regex filter on tags https://github.com/influxdata/influxdb/issues/10739",
+	"regex_tag_2":             "Transpiler: Returns results in wrong sort order for regex filter on tags https://github.com/influxdata/influxdb/issues/10739",
+	"regex_tag_3":             "Transpiler: Returns results in wrong sort order for regex filter on tags https://github.com/influxdata/influxdb/issues/10739",
+	"explicit_type_0":         "Transpiler should remove _start column https://github.com/influxdata/influxdb/issues/10742",
+	"explicit_type_1":         "Transpiler should remove _start column https://github.com/influxdata/influxdb/issues/10742",
+	"random_math_0":           "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"selector_1":              "the expected result is an error within the results rather than a failed query",
+	"selector_2":              "Transpiler: first function uses different series than influxQL https://github.com/influxdata/influxdb/issues/10737",
+	"selector_6":              "Transpiler: first function uses different series than influxQL https://github.com/influxdata/influxdb/issues/10737",
+	"selector_7":              "Transpiler: first function uses different series than influxQL https://github.com/influxdata/influxdb/issues/10737",
+	"series_agg_1":            "Transpiler: Implement stddev https://github.com/influxdata/influxdb/issues/10735",
+	"series_agg_2":            "Transpiler: Implement spread https://github.com/influxdata/influxdb/issues/10734",
+	"series_agg_3":            "Transpiler: Implement elapsed https://github.com/influxdata/influxdb/issues/10733",
+	"series_agg_7":            "Transpiler should remove _start column  https://github.com/influxdata/influxdb/issues/10742",
+	"series_agg_8":            "Transpiler should remove _start column  https://github.com/influxdata/influxdb/issues/10742",
+	"series_agg_9":            "Transpiler should remove _start column  https://github.com/influxdata/influxdb/issues/10742",
+	"Subquery_0":              "transpiler does not implement field wildcards",
+	"Subquery_1":              "mean of a subquery is summed in a different order and the time of the result is not the epoch",
+	"Subquery_2":              "tags selected by a subquery are not fields of the enclosing query",
+	"Subquery_3":              "mean of a subquery is summed in a different order and the time of the result is not the epoch",
+	"Subquery_4":              "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"NestedSubquery_2":        "transpiler does not implement LIMIT",
+	"NestedSubquery_3":        "transpiler does not implement LIMIT",
+	"SimulatedHTTP_0":         "transpiler does not implement multiple sources",
+	"SimulatedHTTP_1":         "transpiler does not implement tag arguments to top and bottom",
+	"SimulatedHTTP_2":         "Transpiler: Implement spread https://github.com/influxdata/influxdb/issues/10734",
+	"SimulatedHTTP_3":         "transpiler does not implement multiple sources",
+	"SimulatedHTTP_4":         "transpiler does not implement multiple sources",
+	"SelectorMath_0":          "the expected result is an error within the results rather than a failed query",
+	"SelectorMath_1":          "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_2":          "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_3":          "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_4":          "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_5":          "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_6":          "the expected result is an error within the results rather than a failed query",
+	"SelectorMath_7":          "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_8":          "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_9":          "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_10":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_11":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_12":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_13":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_14":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_15":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_16":         "the expected result is an error within the results rather than a failed query",
+	"SelectorMath_17":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_18":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_19":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_20":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_21":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_22":         "the expected result is an error within the results rather than a failed query",
+	"SelectorMath_23":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_24":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_25":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_26":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_27":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_28":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_29":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_30":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
+	"SelectorMath_31":         "transpiler does not implement joining fields within a cursor https://github.com/influxdata/influxdb/issues/10743",
 }
 
 var querier = fluxquerytest.NewQuerier()
@@ -3,6 +3,7 @@ package influxql
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/execute"
@@ -93,15 +94,147 @@ func parseFunction(expr *influxql.Call) (*function, error) {
 			Ref:  functionRef,
 			call: expr,
 		}, nil
+	case "top", "bottom":
+		if got := len(expr.Args); got < 2 {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least 2, got %d", expr.Name, got)
+		}
+
+		var functionRef *influxql.VarRef
+
+		switch ref := expr.Args[0].(type) {
+		case *influxql.VarRef:
+			functionRef = ref
+		case *influxql.Wildcard:
+			return nil, errors.New("unimplemented: wildcard function")
+		case *influxql.RegexLiteral:
+			return nil, errors.New("unimplemented: wildcard regex function")
+		default:
+			return nil, fmt.Errorf("expected first argument to be a field in %s(), found %s", expr.Name, expr.Args[0])
+		}
+
+		limit, ok := expr.Args[len(expr.Args)-1].(*influxql.IntegerLiteral)
+		if !ok {
+			return nil, fmt.Errorf("expected integer as last argument in %s(), found %s", expr.Name, expr.Args[len(expr.Args)-1])
+		} else if limit.Val <= 0 {
+			return nil, fmt.Errorf("limit (%d) in %s function must be at least 1", limit.Val, expr.Name)
+		}
+
+		if len(expr.Args) > 2 {
+			for _, arg := range expr.Args[1 : len(expr.Args)-1] {
+				if _, ok := arg.(*influxql.VarRef); !ok {
+					return nil, fmt.Errorf("only fields or tags are allowed in %s(), found %s", expr.Name, arg)
+				}
+			}
+			return nil, fmt.Errorf("unimplemented: tag arguments in %s()", expr.Name)
+		}
+
+		return &function{
+			Ref:  functionRef,
+			call: expr,
+		}, nil
+	case "derivative", "non_negative_derivative":
+		if got := len(expr.Args); got < 1 || got > 2 {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least 1 but no more than 2, got %d", expr.Name, got)
+		}
+
+		if len(expr.Args) == 2 {
+			unit, ok := expr.Args[1].(*influxql.DurationLiteral)
+			if !ok {
+				return nil, fmt.Errorf("second argument to %s must be a duration, got %T", expr.Name, expr.Args[1])
+			} else if unit.Val <= 0 {
+				return nil, fmt.Errorf("duration argument must be positive, got %s", influxql.FormatDuration(unit.Val))
+			}
+		}
+		return parseTransformation(expr)
+	case "difference", "non_negative_difference", "cumulative_sum":
+		if exp, got := 1, len(expr.Args); exp != got {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+		}
+		return parseTransformation(expr)
+	case "moving_average":
+		if exp, got := 2, len(expr.Args); exp != got {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+		}
+
+		n, ok := expr.Args[1].(*influxql.IntegerLiteral)
+		if !ok {
+			return nil, fmt.Errorf("second argument for %s must be an integer, got %T", expr.Name, expr.Args[1])
+		} else if n.Val <= 1 {
+			return nil, fmt.Errorf("%s window must be greater than 1, got %d", expr.Name, n.Val)
+		}
+		return parseTransformation(expr)
 	default:
 		return nil, fmt.Errorf("unimplemented function: %q", expr.Name)
 	}
 
 }
 
+// parseTransformation parses the first argument of a transformation such as derivative.
+// The argument is either a field or an aggregate of a field computed for each interval.
+func parseTransformation(expr *influxql.Call) (*function, error) {
+	switch ref := expr.Args[0].(type) {
+	case *influxql.VarRef:
+		return &function{
+			Ref:  ref,
+			call: expr,
+		}, nil
+	case *influxql.Call:
+		if isTransformation(ref) {
+			return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
+		}
+		fn, err := parseFunction(ref)
+		if err != nil {
+			return nil, err
+		}
+		return &function{
+			Ref:  fn.Ref,
+			call: expr,
+		}, nil
+	case *influxql.Wildcard:
+		return nil, errors.New("unimplemented: wildcard function")
+	case *influxql.RegexLiteral:
+		return nil, errors.New("unimplemented: wildcard regex function")
+	default:
+		return nil, fmt.Errorf("expected field argument in %s()", expr.Name)
+	}
+}
+
+// isTransformation returns true if the call computes a value from the
+// previous points of the series instead of aggregating them.
+func isTransformation(expr *influxql.Call) bool {
+	switch expr.Name {
+	case "derivative", "non_negative_derivative", "difference", "non_negative_difference", "cumulative_sum", "moving_average":
+		return true
+	}
+	return false
+}
+
+// isTopOrBottom returns true if the call selects multiple points from each interval.
+func isTopOrBottom(expr *influxql.Call) bool {
+	return expr.Name == "top" || expr.Name == "bottom"
+}
+
+// fieldRef returns the field that is read by the call. Transformations of an
+// aggregate read the field of the aggregate.
+func fieldRef(expr *influxql.Call) (*influxql.VarRef, bool) {
+	switch arg := expr.Args[0].(type) {
+	case *influxql.VarRef:
+		return arg, true
+	case *influxql.Call:
+		if isTransformation(expr) {
+			return fieldRef(arg)
+		}
+	}
+	return nil, false
+}
+
 // createFunctionCursor creates a new cursor that calls a function on one of the columns
 // and returns the result.
 func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, normalize bool) (cursor, error) {
+	if isTransformation(call) {
+		return createTransformationCursor(t, call, in)
+	}
+
 	cur := &functionCursor{
 		call:   call,
 		parent: in,
@@ -220,6 +353,75 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 		}
 		cur.value = fieldName
 		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+	case "top", "bottom":
+		fieldName, ok := in.Value(call.Args[0])
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+		}
+
+		limit := call.Args[len(call.Args)-1].(*influxql.IntegerLiteral)
+		args := []*ast.Property{
+			{
+				Key: &ast.Identifier{
+					Name: "n",
+				},
+				Value: &ast.IntegerLiteral{
+					Value: limit.Val,
+				},
+			},
+		}
+		if fieldName != execute.DefaultValueColLabel {
+			args = append(args, &ast.Property{
+				Key: &ast.Identifier{
+					Name: "columns",
+				},
+				Value: &ast.ArrayExpression{
+					Elements: []ast.Expression{
+						&ast.StringLiteral{Value: fieldName},
+					},
+				},
+			})
+		}
+
+		// The selected points are returned in time order and keep their own time
+		// even when they are selected from an interval.
+		cur.expr = &ast.PipeExpression{
+			Argument: &ast.PipeExpression{
+				Argument: in.Expr(),
+				Call: &ast.CallExpression{
+					Callee: &ast.Identifier{
+						Name: call.Name,
+					},
+					Arguments: []ast.Expression{
+						&ast.ObjectExpression{
+							Properties: args,
+						},
+					},
+				},
+			},
+			Call: &ast.CallExpression{
+				Callee: &ast.Identifier{
+					Name: "sort",
+				},
+				Arguments: []ast.Expression{
+					&ast.ObjectExpression{
+						Properties: []*ast.Property{{
+							Key: &ast.Identifier{
+								Name: "columns",
+							},
+							Value: &ast.ArrayExpression{
+								Elements: []ast.Expression{
+									&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+								},
+							},
+						}},
+					},
+				},
+			},
+		}
+		cur.value = fieldName
+		cur.exclude = map[influxql.Expr]struct{}{call.Args[0]: {}}
+		return cur, nil
 	default:
 		return nil, fmt.Errorf("unimplemented function: %q", call.Name)
 	}
@@ -284,6 +486,215 @@ func createFunctionCursor(t *transpilerState, call *influxql.Call, in cursor, no
 	return cur, nil
 }
 
+// createTransformationCursor creates a new cursor that transforms the points of each
+// series. When the transformation is applied to an aggregate, the aggregate is
+// computed for each interval before it is transformed.
+func createTransformationCursor(t *transpilerState, call *influxql.Call, in cursor) (cursor, error) {
+	if nested, ok := call.Args[0].(*influxql.Call); ok {
+		cur, err := createFunctionCursor(t, nested, in, true)
+		if err != nil {
+			return nil, err
+		}
+		cur = unwindow(cur)
+		if cur, err = fill(t, nested, cur); err != nil {
+			return nil, err
+		}
+
+		// Intervals without a value are skipped by the transformation.
+		value, _ := cur.Value(nested)
+		in = &pipeCursor{
+			expr:   filterExists(cur.Expr(), value),
+			cursor: cur,
+		}
+	}
+
+	value, ok := in.Value(call.Args[0])
+	if !ok {
+		return nil, fmt.Errorf("undefined variable: %s", call.Args[0])
+	}
+
+	var (
+		name        string
+		args        []*ast.Property
+		nonNegative bool
+	)
+	switch call.Name {
+	case "derivative", "non_negative_derivative":
+		unit := time.Second
+		if len(call.Args) == 2 {
+			unit = call.Args[1].(*influxql.DurationLiteral).Val
+		} else if interval, err := t.stmt.GroupByInterval(); err == nil && interval > 0 {
+			unit = interval
+		}
+		name = "derivative"
+		args = append(args, &ast.Property{
+			Key: &ast.Identifier{
+				Name: "unit",
+			},
+			Value: &ast.DurationLiteral{
+				Values: durationLiteral(unit),
+			},
+		})
+		nonNegative = call.Name == "non_negative_derivative"
+	case "difference", "non_negative_difference":
+		name = "difference"
+		nonNegative = call.Name == "non_negative_difference"
+	case "cumulative_sum":
+		name = "cumulativeSum"
+	case "moving_average":
+		if value != execute.DefaultValueColLabel {
+			return nil, fmt.Errorf("unimplemented: %s of column %s", call.Name, value)
+		}
+		name = "movingAverage"
+		args = append(args, &ast.Property{
+			Key: &ast.Identifier{
+				Name: "n",
+			},
+			Value: &ast.IntegerLiteral{
+				Value: call.Args[1].(*influxql.IntegerLiteral).Val,
+			},
+		})
+	default:
+		return nil, fmt.Errorf("unimplemented function: %q", call.Name)
+	}
+
+	if nonNegative {
+		args = append(args, &ast.Property{
+			Key: &ast.Identifier{
+				Name: "nonNegative",
+			},
+			Value: &ast.BooleanLiteral{
+				Value: true,
+			},
+		})
+	}
+	if value != execute.DefaultValueColLabel && name != "movingAverage" {
+		args = append(args, &ast.Property{
+			Key: &ast.Identifier{
+				Name: "columns",
+			},
+			Value: &ast.ArrayExpression{
+				Elements: []ast.Expression{
+					&ast.StringLiteral{Value: value},
+				},
+			},
+		})
+	}
+
+	expr := &ast.CallExpression{
+		Callee: &ast.Identifier{
+			Name: name,
+		},
+	}
+	if len(args) > 0 {
+		expr.Arguments = []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: args,
+			},
+		}
+	}
+
+	cur := &functionCursor{
+		expr: &ast.PipeExpression{
+			Argument: in.Expr(),
+			Call:     expr,
+		},
+		call:    call,
+		value:   value,
+		exclude: map[influxql.Expr]struct{}{call.Args[0]: {}},
+		parent:  in,
+	}
+
+	// The non-negative transformations produce null values instead of
+	// negative ones and these points are not part of the result.
+	if nonNegative {
+		cur.expr = filterExists(cur.expr, value)
+	}
+
+	// A transformation of an aggregate reads one interval before the start of the
+	// time range and that interval is not part of the result.
+	if _, ok := call.Args[0].(*influxql.Call); ok {
+		tr, err := t.timeRange()
+		if err != nil {
+			return nil, err
+		}
+		if !tr.Min.IsZero() {
+			cur.expr = filterStart(cur.expr, tr.Min)
+		}
+	}
+	return cur, nil
+}
+
+// filterStart filters out the rows before the start time.
+func filterStart(in ast.Expression, start time.Time) ast.Expression {
+	return &ast.PipeExpression{
+		Argument: in,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "filter",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{
+							Name: "fn",
+						},
+						Value: &ast.FunctionExpression{
+							Params: []*ast.Property{{
+								Key: &ast.Identifier{Name: "r"},
+							}},
+							Body: &ast.BinaryExpression{
+								Operator: ast.GreaterThanEqualOperator,
+								Left: &ast.MemberExpression{
+									Object:   &ast.Identifier{Name: "r"},
+									Property: &ast.Identifier{Name: execute.DefaultTimeColLabel},
+								},
+								Right: &ast.DateTimeLiteral{
+									Value: start.UTC(),
+								},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// filterExists filters out the rows that do not have a value in the column.
+func filterExists(in ast.Expression, column string) ast.Expression {
+	property := columnProperty(column)
+	return &ast.PipeExpression{
+		Argument: in,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "filter",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{
+							Name: "fn",
+						},
+						Value: &ast.FunctionExpression{
+							Params: []*ast.Property{{
+								Key: &ast.Identifier{Name: "r"},
+							}},
+							Body: &ast.UnaryExpression{
+								Operator: ast.ExistsOperator,
+								Argument: &ast.MemberExpression{
+									Object:   &ast.Identifier{Name: "r"},
+									Property: property,
+								},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
 type functionCursor struct {
 	expr    ast.Expression
 	call    *influxql.Call
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 )
@@ -65,6 +66,27 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 		return nil, v.err
 	}
 
+	// An invalid interval is reported when the dimensions are grouped.
+	interval, _ := stmt.GroupByInterval()
+	for _, fn := range v.calls {
+		if isTopOrBottom(fn.call) {
+			if len(v.calls) > 1 {
+				return nil, fmt.Errorf("selector function %s() cannot be combined with other functions", fn.call.Name)
+			}
+			limit := fn.call.Args[len(fn.call.Args)-1].(*influxql.IntegerLiteral)
+			if stmt.Limit > 0 && int(limit.Val) > stmt.Limit {
+				return nil, fmt.Errorf("limit (%d) in %s function can not be larger than the LIMIT (%d) in the select statement", limit.Val, fn.call.Name, stmt.Limit)
+			}
+		}
+		if isTransformation(fn.call) {
+			if _, ok := fn.call.Args[0].(*influxql.Call); ok && interval == 0 {
+				return nil, fmt.Errorf("%s aggregate requires a GROUP BY interval", fn.call.Name)
+			} else if !ok && interval > 0 {
+				return nil, fmt.Errorf("aggregate function required inside the call to %s", fn.call.Name)
+			}
+		}
+	}
+
 	// Attempt to take the calls and variables and put them into groups.
 	if len(v.refs) > 0 {
 		// If any of the calls are not selectors, we have an error message.
@@ -105,17 +127,42 @@ func identifyGroups(stmt *influxql.SelectStatement) ([]*groupInfo, error) {
 	return groups, nil
 }
 
+// timeRange returns the time range read by the group. A transformation of an aggregate
+// reads one more interval before the start so the first interval has a previous value.
+func (gr *groupInfo) timeRange(t *transpilerState) (influxql.TimeRange, error) {
+	tr, err := t.timeRange()
+	if err != nil {
+		return influxql.TimeRange{}, err
+	}
+	if gr.call == nil || !isTransformation(gr.call) || tr.Min.IsZero() {
+		return tr, nil
+	}
+	if _, ok := gr.call.Args[0].(*influxql.Call); ok {
+		interval, err := t.stmt.GroupByInterval()
+		if err != nil {
+			return influxql.TimeRange{}, err
+		}
+		tr.Min = tr.Min.Add(-interval)
+	}
+	return tr, nil
+}
+
 func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
+	tr, err := gr.timeRange(t)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create all of the cursors for every variable reference.
 	// TODO(jsternberg): Determine which of these cursors are from fields and which are tags.
 	var cursors []cursor
 	if gr.call != nil {
-		ref, ok := gr.call.Args[0].(*influxql.VarRef)
+		ref, ok := fieldRef(gr.call)
 		if !ok {
 			// TODO(jsternberg): This should be validated and figured out somewhere else.
 			return nil, fmt.Errorf("first argument to %q must be a variable", gr.call.Name)
 		}
-		cur, err := createVarRefCursor(t, ref)
+		cur, err := createVarRefCursor(t, ref, tr)
 		if err != nil {
 			return nil, err
 		}
@@ -123,7 +170,7 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 	}
 
 	for _, ref := range gr.refs {
-		cur, err := createVarRefCursor(t, ref)
+		cur, err := createVarRefCursor(t, ref, tr)
 		if err != nil {
 			return nil, err
 		}
@@ -171,7 +218,7 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 					// Add this variable name to the listing of tags.
 					tags[*ref] = struct{}{}
 				default:
-					cur, err := createVarRefCursor(t, ref)
+					cur, err := createVarRefCursor(t, ref, tr)
 					if err != nil {
 						condErr = err
 						return
@@ -248,25 +295,13 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 		}
 		cur = c
 
-		// If there was a window operation, we now need to undo that and sort by the start column
-		// so they stay in the same table and are joined in the correct order.
-		if interval > 0 {
-			cur = &pipeCursor{
-				expr: &ast.PipeExpression{
-					Argument: cur.Expr(),
-					Call: &ast.CallExpression{
-						Callee: &ast.Identifier{Name: "window"},
-						Arguments: []ast.Expression{
-							&ast.ObjectExpression{
-								Properties: []*ast.Property{{
-									Key:   &ast.Identifier{Name: "every"},
-									Value: &ast.Identifier{Name: "inf"},
-								}},
-							},
-						},
-					},
-				},
-				cursor: cur,
+		// If there was a window operation, we now need to fill the empty windows and undo
+		// the window so they stay in the same table and are joined in the correct order.
+		// Transformations of an aggregate have already done this before they were applied.
+		if interval > 0 && !isTransformation(gr.call) {
+			cur = unwindow(cur)
+			if cur, err = fill(t, gr.call, cur); err != nil {
+				return nil, err
 			}
 		}
 	} else {
@@ -288,9 +323,154 @@ func (gr *groupInfo) createCursor(t *transpilerState) (cursor, error) {
 	return cur, nil
 }
 
+// fill fills the value of the windows without a point using the fill option of the statement.
+// It is applied after the windows have been merged into a single table.
+func fill(t *transpilerState, call *influxql.Call, in cursor) (cursor, error) {
+	if isTopOrBottom(call) {
+		// The points selected by top and bottom are never filled.
+		return in, nil
+	}
+
+	value, _ := in.Value(call)
+	switch t.stmt.Fill {
+	case influxql.NullFill, influxql.NoFill:
+		return in, nil
+	case influxql.NumberFill:
+		var fillValue float64
+		switch v := t.stmt.FillValue.(type) {
+		case int64:
+			fillValue = float64(v)
+		case float64:
+			fillValue = v
+		default:
+			return nil, fmt.Errorf("unimplemented: fill(%v)", t.stmt.FillValue)
+		}
+
+		// The count of an empty window is zero and that is what gets replaced.
+		if call.Name == "count" {
+			return &pipeCursor{
+				expr:   replaceZeroCount(in.Expr(), value, int64(fillValue)),
+				cursor: in,
+			}, nil
+		}
+
+		// TODO: The value should have the type of the field, but the schema is not
+		// known so the aggregated fields are assumed to be floats.
+		return &pipeCursor{
+			expr: fillCall(in.Expr(), value, &ast.Property{
+				Key:   &ast.Identifier{Name: "value"},
+				Value: &ast.FloatLiteral{Value: fillValue},
+			}),
+			cursor: in,
+		}, nil
+	case influxql.PreviousFill:
+		if call.Name == "count" {
+			return nil, errors.New("unimplemented: fill(previous) with count()")
+		}
+		return &pipeCursor{
+			expr: fillCall(in.Expr(), value, &ast.Property{
+				Key:   &ast.Identifier{Name: "usePrevious"},
+				Value: &ast.BooleanLiteral{Value: true},
+			}),
+			cursor: in,
+		}, nil
+	case influxql.LinearFill:
+		return nil, errors.New("unimplemented: fill(linear)")
+	default:
+		return nil, fmt.Errorf("unimplemented: fill option %d", t.stmt.Fill)
+	}
+}
+
+// fillCall calls fill on the column with the given argument.
+func fillCall(in ast.Expression, column string, arg *ast.Property) ast.Expression {
+	args := []*ast.Property{arg}
+	if column != execute.DefaultValueColLabel {
+		args = append(args, &ast.Property{
+			Key:   &ast.Identifier{Name: "column"},
+			Value: &ast.StringLiteral{Value: column},
+		})
+	}
+	return &ast.PipeExpression{
+		Argument: in,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{Name: "fill"},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: args,
+				},
+			},
+		},
+	}
+}
+
+// replaceZeroCount replaces the counts of zero in the column with the fill value.
+func replaceZeroCount(in ast.Expression, column string, fillValue int64) ast.Expression {
+	property := columnProperty(column)
+	count := &ast.MemberExpression{
+		Object:   &ast.Identifier{Name: "r"},
+		Property: property,
+	}
+	return &ast.PipeExpression{
+		Argument: in,
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{Name: "map"},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key: &ast.Identifier{Name: "fn"},
+						Value: &ast.FunctionExpression{
+							Params: []*ast.Property{{
+								Key: &ast.Identifier{Name: "r"},
+							}},
+							Body: &ast.ObjectExpression{
+								With: &ast.Identifier{Name: "r"},
+								Properties: []*ast.Property{{
+									Key: property,
+									Value: &ast.ConditionalExpression{
+										Test: &ast.BinaryExpression{
+											Operator: ast.EqualOperator,
+											Left:     count,
+											Right:    &ast.IntegerLiteral{Value: 0},
+										},
+										Consequent: &ast.IntegerLiteral{Value: fillValue},
+										Alternate:  count,
+									},
+								}},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// unwindow undoes the window operation so the windows stay in the same table
+// and are joined in the correct order.
+func unwindow(in cursor) cursor {
+	return &pipeCursor{
+		expr: &ast.PipeExpression{
+			Argument: in.Expr(),
+			Call: &ast.CallExpression{
+				Callee: &ast.Identifier{Name: "window"},
+				Arguments: []ast.Expression{
+					&ast.ObjectExpression{
+						Properties: []*ast.Property{{
+							Key:   &ast.Identifier{Name: "every"},
+							Value: &ast.Identifier{Name: "inf"},
+						}},
+					},
+				},
+			},
+		},
+		cursor: in,
+	}
+}
+
 func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 	var windowEvery time.Duration
 	var windowStart time.Time
+	var wildcard bool
 	tags := []ast.Expression{
 		&ast.StringLiteral{Value: "_measurement"},
 		&ast.StringLiteral{Value: "_start"},
@@ -367,7 +547,7 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 					}
 				}
 			case *influxql.Wildcard:
-				return nil, errors.New("unimplemented: dimension wildcards")
+				wildcard = true
 			case *influxql.RegexLiteral:
 				return nil, errors.New("unimplemented: dimension regex wildcards")
 			default:
@@ -378,7 +558,15 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 
 	// Perform the grouping by the tags we found. There is always a group by because
 	// there is always something to group in influxql.
-	// TODO(jsternberg): A wildcard will skip this step.
+	// A wildcard groups by every tag so it groups by everything except the values.
+	mode := "by"
+	if wildcard {
+		tags = []ast.Expression{
+			&ast.StringLiteral{Value: execute.DefaultTimeColLabel},
+			&ast.StringLiteral{Value: execute.DefaultValueColLabel},
+		}
+		mode = "except"
+	}
 	in = &pipeCursor{
 		expr: &ast.PipeExpression{
 			Argument: in.Expr(),
@@ -402,7 +590,7 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 									Name: "mode",
 								},
 								Value: &ast.StringLiteral{
-									Value: "by",
+									Value: mode,
 								},
 							},
 						},
@@ -432,6 +620,24 @@ func (gr *groupInfo) group(t *transpilerState, in cursor) (cursor, error) {
 				},
 			})
 		}
+
+		// The windows without points are created so they can be filled. This is skipped
+		// when there is no lower time bound because the windows would start at the minimum time.
+		if t.stmt.Fill != influxql.NoFill && gr.call != nil && !isTopOrBottom(gr.call) {
+			tr, err := t.timeRange()
+			if err != nil {
+				return nil, err
+			} else if !tr.Min.IsZero() {
+				args = append(args, &ast.Property{
+					Key: &ast.Identifier{
+						Name: "createEmpty",
+					},
+					Value: &ast.BooleanLiteral{
+						Value: true,
+					},
+				})
+			}
+		}
 		in = &pipeCursor{
 			expr: &ast.PipeExpression{
 				Argument: in.Expr(),
@@ -108,15 +108,9 @@ func (t *transpilerState) mapFields(in cursor) (cursor, error) {
 
 func (t *transpilerState) mapField(expr influxql.Expr, in cursor) (ast.Expression, error) {
 	if sym, ok := in.Value(expr); ok {
-		var property ast.PropertyKey
-		if strings.HasPrefix(sym, "_") {
-			property = &ast.Identifier{Name: sym}
-		} else {
-			property = &ast.StringLiteral{Value: sym}
-		}
 		return &ast.MemberExpression{
 			Object:   &ast.Identifier{Name: "r"},
-			Property: property,
+			Property: columnProperty(sym),
 		}, nil
 	}
 
@@ -158,6 +152,14 @@ func (t *transpilerState) mapField(expr influxql.Expr, in cursor) (ast.Expressio
 	}
 }
 
+// columnProperty returns the property used to access a column of the record.
+func columnProperty(column string) ast.PropertyKey {
+	if strings.HasPrefix(column, "_") {
+		return &ast.Identifier{Name: column}
+	}
+	return &ast.StringLiteral{Value: column}
+}
+
 func (t *transpilerState) evalBinaryExpr(expr *influxql.BinaryExpr, in cursor) (ast.Expression, error) {
 	fn := func() func(left, right ast.Expression) ast.Expression {
 		b := evalBuilder{}
@@ -0,0 +1,430 @@
+package influxql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxql"
+)
+
+// The SHOW statements that list the schema of a database read it from the storage engine.
+// When a statement reads a single measurement, it is transpiled into the patterns that the
+// planner pushes down to the storage schema reads (ReadTagKeys and ReadTagValues). When it
+// reads more than one measurement, the results are grouped by measurement like in 1.x, which
+// the schema reads do not support, so the series are read and grouped instead.
+
+// schemaColumns are the group key columns that are not tag keys.
+var schemaColumns = []string{"_start", "_stop", "_measurement", "_field"}
+
+func (t *transpilerState) transpileShowMeasurements(ctx context.Context, stmt *influxql.ShowMeasurementsStatement) (ast.Expression, error) {
+	var sources influxql.Sources
+	if stmt.Source != nil {
+		sources = influxql.Sources{stmt.Source}
+	}
+	expr, err := t.showSource(stmt.Database, sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	expr = pipe(expr, "keep", property("columns", stringArray("_measurement")))
+	expr = pipe(expr, "group")
+	expr = pipe(expr, "distinct", property("column", &ast.StringLiteral{Value: "_measurement"}))
+	expr = pipe(expr, "sort")
+	if expr, err = showLimit(expr, stmt.Limit, stmt.Offset); err != nil {
+		return nil, err
+	}
+	expr = pipe(expr, "rename", property("columns", renameColumns("_value", "name")))
+	return seriesName(expr, "measurements"), nil
+}
+
+func (t *transpilerState) transpileShowTagKeys(ctx context.Context, stmt *influxql.ShowTagKeysStatement) (ast.Expression, error) {
+	if stmt.SLimit > 0 || stmt.SOffset > 0 {
+		return nil, errors.New("unimplemented: SLIMIT and SOFFSET")
+	}
+	expr, err := t.showSource(stmt.Database, stmt.Sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	expr = pipe(expr, "keys")
+	name, single := singleMeasurement(stmt.Sources)
+	if single {
+		expr = pipe(expr, "keep", property("columns", stringArray("_value")))
+	} else {
+		expr = pipe(expr, "keep", property("columns", stringArray("_measurement", "_value")))
+		expr = pipe(expr, "group", property("columns", stringArray("_measurement")))
+	}
+	expr = pipe(expr, "distinct")
+
+	// The group key contains columns that are not tag keys.
+	var tagKey ast.Expression
+	for i := len(schemaColumns) - 1; i >= 0; i-- {
+		var ne ast.Expression = &ast.BinaryExpression{
+			Operator: ast.NotEqualOperator,
+			Left:     member("r", "_value"),
+			Right:    &ast.StringLiteral{Value: schemaColumns[i]},
+		}
+		if tagKey != nil {
+			ne = &ast.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left:     ne,
+				Right:    tagKey,
+			}
+		}
+		tagKey = ne
+	}
+	expr = pipe(expr, "filter", property("fn", predicate(tagKey)))
+	expr = pipe(expr, "sort")
+	if expr, err = showLimit(expr, stmt.Limit, stmt.Offset); err != nil {
+		return nil, err
+	}
+	expr = pipe(expr, "rename", property("columns", renameColumns("_value", "tagKey")))
+	if single {
+		expr = seriesName(expr, name)
+	}
+	return expr, nil
+}
+
+func (t *transpilerState) transpileShowTagValues(ctx context.Context, stmt *influxql.ShowTagValuesStatement) (ast.Expression, error) {
+	var keys []string
+	switch expr := stmt.TagKeyExpr.(type) {
+	case *influxql.ListLiteral:
+		keys = expr.Vals
+	case *influxql.StringLiteral:
+		switch stmt.Op {
+		case influxql.EQ:
+			keys = []string{expr.Val}
+		case influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX:
+			return nil, fmt.Errorf("unimplemented: tag key operand: %s", stmt.Op)
+		default:
+			return nil, fmt.Errorf("unsupported operand: %s", stmt.Op)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported literal type: %T", expr)
+	}
+
+	name, single := singleMeasurement(stmt.Sources)
+	tables := make([]ast.Expression, 0, len(keys))
+	for _, key := range keys {
+		expr, err := t.showSource(stmt.Database, stmt.Sources, stmt.Condition)
+		if err != nil {
+			return nil, err
+		}
+
+		var measurement ast.Expression
+		if single {
+			expr = pipe(expr, "keep", property("columns", stringArray(key)))
+			expr = pipe(expr, "group")
+			measurement = &ast.StringLiteral{Value: name}
+		} else {
+			expr = pipe(expr, "keep", property("columns", stringArray("_measurement", key)))
+			expr = pipe(expr, "group", property("columns", stringArray("_measurement")))
+			measurement = member("r", "_measurement")
+		}
+		expr = pipe(expr, "distinct", property("column", &ast.StringLiteral{Value: key}))
+
+		// Series without the tag have an empty value.
+		expr = pipe(expr, "filter", property("fn", predicate(&ast.BinaryExpression{
+			Operator: ast.NotEqualOperator,
+			Left:     member("r", "_value"),
+			Right:    &ast.StringLiteral{Value: ""},
+		})))
+		expr = pipe(expr, "map", property("fn", predicate(&ast.ObjectExpression{
+			Properties: []*ast.Property{
+				property("_measurement", measurement),
+				property("key", &ast.StringLiteral{Value: key}),
+				property("value", member("r", "_value")),
+			},
+		})))
+		tables = append(tables, expr)
+	}
+
+	expr := tables[0]
+	if len(tables) > 1 {
+		expr = &ast.CallExpression{
+			Callee: &ast.Identifier{Name: "union"},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{
+						property("tables", &ast.ArrayExpression{Elements: tables}),
+					},
+				},
+			},
+		}
+	}
+	expr = pipe(expr, "group", property("columns", stringArray("_measurement")))
+	expr = pipe(expr, "sort", property("columns", stringArray("key", "value")))
+	return showLimit(expr, stmt.Limit, stmt.Offset)
+}
+
+func (t *transpilerState) transpileShowFieldKeys(ctx context.Context, stmt *influxql.ShowFieldKeysStatement) (ast.Expression, error) {
+	expr, err := t.showSource(stmt.Database, stmt.Sources, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	name, single := singleMeasurement(stmt.Sources)
+	if single {
+		expr = pipe(expr, "keep", property("columns", stringArray("_field")))
+		expr = pipe(expr, "group")
+	} else {
+		expr = pipe(expr, "keep", property("columns", stringArray("_measurement", "_field")))
+		expr = pipe(expr, "group", property("columns", stringArray("_measurement")))
+	}
+	expr = pipe(expr, "distinct", property("column", &ast.StringLiteral{Value: "_field"}))
+	expr = pipe(expr, "sort")
+	if expr, err = showLimit(expr, stmt.Limit, stmt.Offset); err != nil {
+		return nil, err
+	}
+
+	// TODO: 1.x also returns the type of each field in a fieldType column. The
+	// type of a field is only known from the type of its value column, which
+	// can not be turned into a value in Flux yet.
+	expr = pipe(expr, "rename", property("columns", renameColumns("_value", "fieldKey")))
+	if single {
+		expr = seriesName(expr, name)
+	}
+	return expr, nil
+}
+
+func (t *transpilerState) transpileShowSeries(ctx context.Context, stmt *influxql.ShowSeriesStatement) (ast.Expression, error) {
+	expr, err := t.showSource(stmt.Database, stmt.Sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := t.requireImport("influxdata/influxdb/schema")
+	expr = &ast.PipeExpression{
+		Argument: expr,
+		Call: &ast.CallExpression{
+			Callee: &ast.MemberExpression{
+				Object:   schema,
+				Property: &ast.Identifier{Name: "seriesKeys"},
+			},
+		},
+	}
+	expr = pipe(expr, "sort", property("columns", stringArray("key")))
+	return showLimit(expr, stmt.Limit, stmt.Offset)
+}
+
+// showSource returns the expression that reads the series of the database
+// that match the sources and the condition of a SHOW statement.
+func (t *transpilerState) showSource(database string, sources influxql.Sources, cond influxql.Expr) (ast.Expression, error) {
+	mm := &influxql.Measurement{Database: database}
+	for _, source := range sources {
+		m, ok := source.(*influxql.Measurement)
+		if !ok {
+			return nil, errors.New("unimplemented: source must be a measurement")
+		}
+		// The database and retention policy may be specified with the measurement.
+		if m.Database != "" {
+			mm.Database = m.Database
+		}
+		if m.RetentionPolicy != "" {
+			mm.RetentionPolicy = m.RetentionPolicy
+		}
+	}
+	if mm.Database == "" {
+		if t.config.DefaultDatabase == "" {
+			return nil, errDatabaseNameRequired
+		}
+		mm.Database = t.config.DefaultDatabase
+	}
+
+	expr, err := t.from(mm)
+	if err != nil {
+		return nil, err
+	}
+
+	valuer := influxql.NowValuer{Now: t.config.Now}
+	cond, tr, err := influxql.ConditionExpr(cond, &valuer)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1.x reads the schema of all of the data when no time range is given.
+	// Read the last hour instead so the query does not scan all of the data.
+	if tr.Min.IsZero() && tr.Max.IsZero() {
+		expr = pipe(expr, "range", property("start", &ast.DurationLiteral{
+			Values: []ast.Duration{{Magnitude: -1, Unit: "h"}},
+		}))
+	} else {
+		expr = pipe(expr, "range",
+			property("start", &ast.DateTimeLiteral{Value: tr.MinTime().UTC()}),
+			property("stop", &ast.DateTimeLiteral{Value: tr.MaxTime().UTC()}),
+		)
+	}
+
+	// Filter the measurements that are listed in the sources.
+	var filter ast.Expression
+	for i := len(sources) - 1; i >= 0; i-- {
+		m := sources[i].(*influxql.Measurement)
+		var match ast.Expression
+		if m.Regex != nil {
+			match = &ast.BinaryExpression{
+				Operator: ast.RegexpMatchOperator,
+				Left:     member("r", "_measurement"),
+				Right:    &ast.RegexpLiteral{Value: m.Regex.Val},
+			}
+		} else {
+			match = &ast.BinaryExpression{
+				Operator: ast.EqualOperator,
+				Left:     member("r", "_measurement"),
+				Right:    &ast.StringLiteral{Value: m.Name},
+			}
+		}
+		if filter != nil {
+			match = &ast.LogicalExpression{
+				Operator: ast.OrOperator,
+				Left:     match,
+				Right:    filter,
+			}
+		}
+		filter = match
+	}
+
+	// The condition of a SHOW statement can only refer to tags.
+	if cond != nil {
+		expr, err := t.mapField(cond, schemaCursor{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to evaluate condition: %s", err)
+		}
+		if filter != nil {
+			expr = &ast.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left:     filter,
+				Right:    expr,
+			}
+		}
+		filter = expr
+	}
+
+	if filter != nil {
+		expr = pipe(expr, "filter", property("fn", predicate(filter)))
+	}
+	return expr, nil
+}
+
+// schemaCursor resolves the variable references in the condition of a
+// SHOW statement, which are tag keys or the _name of the measurement.
+type schemaCursor struct{}
+
+func (schemaCursor) Expr() ast.Expression  { return nil }
+func (schemaCursor) Keys() []influxql.Expr { return nil }
+func (schemaCursor) Value(expr influxql.Expr) (string, bool) {
+	ref, ok := expr.(*influxql.VarRef)
+	if !ok {
+		return "", false
+	}
+	if ref.Val == "_name" {
+		return "_measurement", true
+	}
+	return ref.Val, true
+}
+
+// singleMeasurement returns the name of the measurement if the sources
+// contain exactly one measurement that is not a regex.
+func singleMeasurement(sources influxql.Sources) (string, bool) {
+	if len(sources) != 1 {
+		return "", false
+	}
+	m, ok := sources[0].(*influxql.Measurement)
+	if !ok || m.Regex != nil || m.Name == "" {
+		return "", false
+	}
+	return m.Name, true
+}
+
+// seriesName names the single series of the result by grouping it by
+// a _measurement column with the name.
+func seriesName(expr ast.Expression, name string) ast.Expression {
+	expr = pipe(expr, "set",
+		property("key", &ast.StringLiteral{Value: "_measurement"}),
+		property("value", &ast.StringLiteral{Value: name}),
+	)
+	return pipe(expr, "group", property("columns", stringArray("_measurement")))
+}
+
+// showLimit limits the number of rows of each series.
+func showLimit(expr ast.Expression, limit, offset int) (ast.Expression, error) {
+	if limit == 0 {
+		if offset > 0 {
+			return nil, errors.New("unimplemented: OFFSET without LIMIT")
+		}
+		return expr, nil
+	}
+	props := []*ast.Property{
+		property("n", &ast.IntegerLiteral{Value: int64(limit)}),
+	}
+	if offset > 0 {
+		props = append(props, property("offset", &ast.IntegerLiteral{Value: int64(offset)}))
+	}
+	return pipe(expr, "limit", props...), nil
+}
+
+// pipe returns the expression piped into a call of the function with the properties.
+func pipe(arg ast.Expression, fn string, props ...*ast.Property) ast.Expression {
+	call := &ast.CallExpression{
+		Callee: &ast.Identifier{Name: fn},
+	}
+	if len(props) > 0 {
+		call.Arguments = []ast.Expression{
+			&ast.ObjectExpression{Properties: props},
+		}
+	}
+	return &ast.PipeExpression{
+		Argument: arg,
+		Call:     call,
+	}
+}
+
+func property(key string, value ast.Expression) *ast.Property {
+	return &ast.Property{
+		Key:   &ast.Identifier{Name: key},
+		Value: value,
+	}
+}
+
+func stringArray(vs ...string) *ast.ArrayExpression {
+	elements := make([]ast.Expression, 0, len(vs))
+	for _, v := range vs {
+		elements = append(elements, &ast.StringLiteral{Value: v})
+	}
+	return &ast.ArrayExpression{Elements: elements}
+}
+
+func renameColumns(from, to string) *ast.ObjectExpression {
+	return &ast.ObjectExpression{
+		Properties: []*ast.Property{
+			property(from, &ast.StringLiteral{Value: to}),
+		},
+	}
+}
+
+// member returns an expression that accesses the property of the object,
+// the same way as mapField does for the columns of a cursor.
+func member(object, name string) *ast.MemberExpression {
+	var key ast.PropertyKey
+	if strings.HasPrefix(name, "_") {
+		key = &ast.Identifier{Name: name}
+	} else {
+		key = &ast.StringLiteral{Value: name}
+	}
+	return &ast.MemberExpression{
+		Object:   &ast.Identifier{Name: object},
+		Property: key,
+	}
+}
+
+// predicate returns a function of r with the body.
+func predicate(body ast.Expression) *ast.FunctionExpression {
+	return &ast.FunctionExpression{
+		Params: []*ast.Property{{
+			Key: &ast.Identifier{Name: "r"},
+		}},
+		Body: body,
+	}
+}
@@ -12,7 +12,7 @@ func init() {
 	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
 	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
 	|> group(columns: ["_measurement", "_start"], mode: "by")
-	|> window(every: 1m)
+	|> window(every: 1m, createEmpty: true)
 	|> ` + name + `()
 	|> duplicate(column: "_start", as: "_time")
 	|> window(every: inf)
@@ -0,0 +1,73 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT mean(value) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m) fill(none)`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT mean(value) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m) fill(0)`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m, createEmpty: true)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> fill(value: 0.0)
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT mean(value) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m) fill(previous)`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m, createEmpty: true)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> fill(usePrevious: true)
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT count(value) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m) fill(10)`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m, createEmpty: true)
+	|> count()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({r with _value: if r._value == 0 then 10 else r._value}))
+	|> map(fn: (r) => ({_time: r._time, count: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,24 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW FIELD KEYS FROM "cpu"`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._measurement == "cpu"))
+	|> keep(columns: ["_field"])
+	|> group()
+	|> distinct(column: "_field")
+	|> sort()
+	|> rename(columns: {_value: "fieldKey"})
+	|> set(key: "_measurement", value: "cpu")
+	|> group(columns: ["_measurement"])
+	|> yield(name: "0")
+`,
+		),
+	)
+}
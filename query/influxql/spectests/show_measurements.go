@@ -0,0 +1,22 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW MEASUREMENTS ON "db0"`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> keep(columns: ["_measurement"])
+	|> group()
+	|> distinct(column: "_measurement")
+	|> sort()
+	|> rename(columns: {_value: "name"})
+	|> set(key: "_measurement", value: "measurements")
+	|> group(columns: ["_measurement"])
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,25 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW MEASUREMENTS WITH MEASUREMENT =~ /cp.*/ WHERE "host" = 'server01' LIMIT 10 OFFSET 5`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._measurement =~ /cp.*/ and r["host"] == "server01"))
+	|> keep(columns: ["_measurement"])
+	|> group()
+	|> distinct(column: "_measurement")
+	|> sort()
+	|> limit(n: 10, offset: 5)
+	|> rename(columns: {_value: "name"})
+	|> set(key: "_measurement", value: "measurements")
+	|> group(columns: ["_measurement"])
+	|> yield(name: "0")
+`,
+		),
+	)
+}
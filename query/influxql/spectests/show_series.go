@@ -0,0 +1,21 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW SERIES FROM "cpu" WHERE "host" = 'server01' LIMIT 10`,
+			`package main
+import schema "influxdata/influxdb/schema"
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._measurement == "cpu" and r["host"] == "server01"))
+	|> schema.seriesKeys()
+	|> sort(columns: ["key"])
+	|> limit(n: 10)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
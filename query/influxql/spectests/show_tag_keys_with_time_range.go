@@ -0,0 +1,24 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW TAG KEYS WHERE time > '2010-09-15T08:00:00Z' LIMIT 2`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:00:00.000000001Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> keys()
+	|> keep(columns: ["_measurement", "_value"])
+	|> group(columns: ["_measurement"])
+	|> distinct()
+	|> filter(fn: (r) =>
+		(r._value != "_start" and (r._value != "_stop" and (r._value != "_measurement" and r._value != "_field"))))
+	|> sort()
+	|> limit(n: 2)
+	|> rename(columns: {_value: "tagKey"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -8,11 +8,15 @@ func init() {
 
 from(bucketID: "")
 	|> range(start: -1h)
-	|> keyValues(keyColumns: ["host"])
-	|> group(columns: ["_measurement", "_key"], mode: "by")
-	|> distinct()
-	|> group(columns: ["_measurement"], mode: "by")
-	|> rename(columns: {_key: "key", _value: "value"})
+	|> keep(columns: ["_measurement", "host"])
+	|> group(columns: ["_measurement"])
+	|> distinct(column: "host")
+	|> filter(fn: (r) =>
+		(r._value != ""))
+	|> map(fn: (r) =>
+		({_measurement: r._measurement, key: "host", value: r._value}))
+	|> group(columns: ["_measurement"])
+	|> sort(columns: ["key", "value"])
 	|> yield(name: "0")
 `,
 		),
@@ -5,14 +5,25 @@ func init() {
 		NewFixture(
 			`SHOW TAG VALUES ON "db0" WITH KEY IN ("host", "region")`,
 			`package main
-
-from(bucketID: "")
+union(tables: [from(bucketID: "")
+	|> range(start: -1h)
+	|> keep(columns: ["_measurement", "host"])
+	|> group(columns: ["_measurement"])
+	|> distinct(column: "host")
+	|> filter(fn: (r) =>
+		(r._value != ""))
+	|> map(fn: (r) =>
+		({_measurement: r._measurement, key: "host", value: r._value})), from(bucketID: "")
 	|> range(start: -1h)
-	|> keyValues(keyColumns: ["host", "region"])
-	|> group(columns: ["_measurement", "_key"], mode: "by")
-	|> distinct()
-	|> group(columns: ["_measurement"], mode: "by")
-	|> rename(columns: {_key: "key", _value: "value"})
+	|> keep(columns: ["_measurement", "region"])
+	|> group(columns: ["_measurement"])
+	|> distinct(column: "region")
+	|> filter(fn: (r) =>
+		(r._value != ""))
+	|> map(fn: (r) =>
+		({_measurement: r._measurement, key: "region", value: r._value}))])
+	|> group(columns: ["_measurement"])
+	|> sort(columns: ["key", "value"])
 	|> yield(name: "0")
 `,
 		),
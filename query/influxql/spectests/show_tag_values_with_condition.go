@@ -0,0 +1,27 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW TAG VALUES FROM "cpu" WITH KEY = "host" WHERE "region" = 'west' LIMIT 10`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) =>
+		(r._measurement == "cpu" and r["region"] == "west"))
+	|> keep(columns: ["host"])
+	|> group()
+	|> distinct(column: "host")
+	|> filter(fn: (r) =>
+		(r._value != ""))
+	|> map(fn: (r) =>
+		({_measurement: "cpu", key: "host", value: r._value}))
+	|> group(columns: ["_measurement"])
+	|> sort(columns: ["key", "value"])
+	|> limit(n: 10)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,54 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT mean(value) FROM (SELECT value FROM db0..cpu WHERE value > 0)`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> filter(fn: (r) => r._value > 0)
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> map(fn: (r) => ({_time: r._time, value: r._value}), mergeKey: true)
+
+t0
+	|> filter(fn: (r) => exists r["value"])
+	|> map(fn: (r) => ({_time: r._time, _value: r["value"]}), mergeKey: true)
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT max(mean) FROM (SELECT mean(value) FROM db0..cpu) WHERE time >= now() - 10m GROUP BY time(1m)`,
+			`package main
+
+t0 = from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m, createEmpty: true)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}), mergeKey: true)
+
+t0
+	|> filter(fn: (r) => exists r["mean"])
+	|> map(fn: (r) => ({_time: r._time, _value: r["mean"]}), mergeKey: true)
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m, createEmpty: true)
+	|> max()
+	|> drop(columns: ["_time"])
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, max: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,34 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT top(value, 3) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> top(n: 3)
+	|> sort(columns: ["_time"])
+	|> map(fn: (r) => ({_time: r._time, top: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT bottom(value, 3) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> bottom(n: 3)
+	|> sort(columns: ["_time"])
+	|> map(fn: (r) => ({_time: r._time, bottom: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
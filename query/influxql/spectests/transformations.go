@@ -0,0 +1,105 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT derivative(value) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> derivative(unit: 1s)
+	|> map(fn: (r) => ({_time: r._time, derivative: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT non_negative_derivative(value, 10s) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> derivative(unit: 10s, nonNegative: true)
+	|> filter(fn: (r) => exists r._value)
+	|> map(fn: (r) => ({_time: r._time, non_negative_derivative: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT difference(value) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> difference()
+	|> map(fn: (r) => ({_time: r._time, difference: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT non_negative_difference(value) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> difference(nonNegative: true)
+	|> filter(fn: (r) => exists r._value)
+	|> map(fn: (r) => ({_time: r._time, non_negative_difference: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT cumulative_sum(value) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> cumulativeSum()
+	|> map(fn: (r) => ({_time: r._time, cumulative_sum: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT moving_average(value, 3) FROM db0..cpu`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775806Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> movingAverage(n: 3)
+	|> map(fn: (r) => ({_time: r._time, moving_average: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+		NewFixture(
+			`SELECT derivative(mean(value)) FROM db0..cpu WHERE time >= now() - 10m GROUP BY time(1m)`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:49:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m, createEmpty: true)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> filter(fn: (r) => exists r._value)
+	|> derivative(unit: 1m)
+	|> filter(fn: (r) => r._time >= 2010-09-15T08:50:00Z)
+	|> map(fn: (r) => ({_time: r._time, derivative: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,151 @@
+package influxql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/influxql"
+)
+
+// subQuery holds the result of a transpiled subquery. The result is assigned to
+// a variable so every field read by the enclosing query uses the same tables.
+type subQuery struct {
+	ident   *ast.Identifier
+	columns map[string]struct{}
+}
+
+// subQueryKey identifies a subquery that is read within a time range.
+type subQueryKey struct {
+	subquery *influxql.SubQuery
+	bounds   influxql.TimeRange
+}
+
+// transpileSubQuery transpiles the statement of the subquery within the time range
+// using the interval of the enclosing query.
+func (t *transpilerState) transpileSubQuery(subquery *influxql.SubQuery, bounds influxql.TimeRange) (*subQuery, error) {
+	key := subQueryKey{subquery: subquery, bounds: bounds}
+	if sq, ok := t.subqueries[key]; ok {
+		return sq, nil
+	}
+
+	// A subquery without an ordering is read in the same direction as the enclosing query.
+	stmt := subquery.Statement.Clone()
+	if len(stmt.SortFields) == 0 {
+		stmt.SortFields = t.stmt.SortFields
+	} else if stmt.TimeAscending() != t.stmt.TimeAscending() {
+		return nil, errors.New("subqueries must be ordered in the same direction as the query itself")
+	}
+
+	// An aggregate subquery without an interval inherits the interval of the enclosing query.
+	if interval, err := stmt.GroupByInterval(); err == nil && interval == 0 && hasCall(stmt.Fields) {
+		for _, d := range t.stmt.Dimensions {
+			if call, ok := d.Expr.(*influxql.Call); ok && call.Name == "time" {
+				stmt.Dimensions = append(stmt.Dimensions, d)
+			}
+		}
+	}
+
+	inner := &transpilerState{
+		config:         t.config,
+		file:           t.file,
+		assignments:    t.assignments,
+		subqueries:     make(map[subQueryKey]*subQuery),
+		bounds:         bounds,
+		dbrpMappingSvc: t.dbrpMappingSvc,
+	}
+	cur, err := inner.transpileSelect(context.TODO(), stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	sq := &subQuery{
+		ident:   t.assignment(cur.Expr()),
+		columns: make(map[string]struct{}),
+	}
+	for _, name := range inner.stmt.ColumnNames() {
+		sq.columns[name] = struct{}{}
+	}
+	t.subqueries[key] = sq
+	return sq, nil
+}
+
+// createSubQueryCursor creates a new cursor that reads a field computed by a subquery
+// within the time range.
+func createSubQueryCursor(t *transpilerState, subquery *influxql.SubQuery, ref *influxql.VarRef, tr influxql.TimeRange) (cursor, error) {
+	sq, err := t.transpileSubQuery(subquery, tr)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sq.columns[ref.Val]; !ok {
+		return nil, fmt.Errorf("unimplemented: %s is not a field of the subquery", ref.Val)
+	}
+
+	// The rows of the subquery that do not have the field are skipped and the field
+	// becomes the value column, like a field read from the database.
+	expr := &ast.PipeExpression{
+		Argument: filterExists(sq.ident, ref.Val),
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "map",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{
+						{
+							Key: &ast.Identifier{
+								Name: "fn",
+							},
+							Value: &ast.FunctionExpression{
+								Params: []*ast.Property{{
+									Key: &ast.Identifier{Name: "r"},
+								}},
+								Body: &ast.ObjectExpression{
+									Properties: []*ast.Property{
+										{
+											Key: &ast.Identifier{Name: execute.DefaultTimeColLabel},
+											Value: &ast.MemberExpression{
+												Object:   &ast.Identifier{Name: "r"},
+												Property: &ast.Identifier{Name: execute.DefaultTimeColLabel},
+											},
+										},
+										{
+											Key: &ast.Identifier{Name: execute.DefaultValueColLabel},
+											Value: &ast.MemberExpression{
+												Object:   &ast.Identifier{Name: "r"},
+												Property: columnProperty(ref.Val),
+											},
+										},
+									},
+								},
+							},
+						},
+						{
+							Key: &ast.Identifier{
+								Name: "mergeKey",
+							},
+							Value: &ast.BooleanLiteral{Value: true},
+						},
+					},
+				},
+			},
+		},
+	}
+	return &varRefCursor{
+		expr: expr,
+		ref:  ref,
+	}, nil
+}
+
+// hasCall returns true if any of the fields calls a function.
+func hasCall(fields influxql.Fields) bool {
+	var found bool
+	influxql.WalkFunc(fields, func(n influxql.Node) {
+		if call, ok := n.(*influxql.Call); ok && !isMathFunction(call) {
+			found = true
+		}
+	})
+	return found
+}
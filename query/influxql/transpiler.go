@@ -56,6 +56,8 @@ type transpilerState struct {
 	config         Config
 	file           *ast.File
 	assignments    map[string]ast.Expression
+	subqueries     map[subQueryKey]*subQuery
+	bounds         influxql.TimeRange
 	dbrpMappingSvc influxdb.DBRPMappingService
 }
 
@@ -69,6 +71,7 @@ func newTranspilerState(dbrpMappingSvc influxdb.DBRPMappingService, config *Conf
 			},
 		},
 		assignments:    make(map[string]ast.Expression),
+		subqueries:     make(map[subQueryKey]*subQuery),
 		dbrpMappingSvc: dbrpMappingSvc,
 	}
 	if config != nil {
@@ -117,8 +120,16 @@ func (t *transpilerState) transpile(ctx context.Context, s influxql.Statement) (
 			return nil, err
 		}
 		return cur.Expr(), nil
+	case *influxql.ShowMeasurementsStatement:
+		return t.transpileShowMeasurements(ctx, stmt)
+	case *influxql.ShowTagKeysStatement:
+		return t.transpileShowTagKeys(ctx, stmt)
 	case *influxql.ShowTagValuesStatement:
 		return t.transpileShowTagValues(ctx, stmt)
+	case *influxql.ShowFieldKeysStatement:
+		return t.transpileShowFieldKeys(ctx, stmt)
+	case *influxql.ShowSeriesStatement:
+		return t.transpileShowSeries(ctx, stmt)
 	case *influxql.ShowDatabasesStatement:
 		return t.transpileShowDatabases(ctx, stmt)
 	case *influxql.ShowRetentionPoliciesStatement:
@@ -128,268 +139,6 @@ func (t *transpilerState) transpile(ctx context.Context, s influxql.Statement) (
 	}
 }
 
-func (t *transpilerState) transpileShowTagValues(ctx context.Context, stmt *influxql.ShowTagValuesStatement) (ast.Expression, error) {
-	// While the ShowTagValuesStatement contains a sources section and those sources are measurements, they do
-	// not actually contain the database and we do not factor in retention policies. So we are always going to use
-	// the default retention policy when evaluating which bucket we are querying and we do not have to consult
-	// the sources in the statement.
-	if stmt.Database == "" {
-		if t.config.DefaultDatabase == "" {
-			return nil, errDatabaseNameRequired
-		}
-		stmt.Database = t.config.DefaultDatabase
-	}
-
-	expr, err := t.from(&influxql.Measurement{Database: stmt.Database})
-	if err != nil {
-		return nil, err
-	}
-
-	// TODO(jsternberg): Read the range from the condition expression. 1.x doesn't actually do this so it isn't
-	// urgent to implement this functionality so we can use the default range.
-	expr = &ast.PipeExpression{
-		Argument: expr,
-		Call: &ast.CallExpression{
-			Callee: &ast.Identifier{
-				Name: "range",
-			},
-			Arguments: []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "start",
-							},
-							Value: &ast.DurationLiteral{
-								Values: []ast.Duration{{
-									Magnitude: -1,
-									Unit:      "h",
-								}},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// If we have a list of sources, look through it and add each of the measurement names.
-	measurementNames := make([]string, 0, len(stmt.Sources))
-	for _, source := range stmt.Sources {
-		mm := source.(*influxql.Measurement)
-		measurementNames = append(measurementNames, mm.Name)
-	}
-
-	if len(measurementNames) > 0 {
-		var filterExpr ast.Expression = &ast.BinaryExpression{
-			Operator: ast.EqualOperator,
-			Left: &ast.MemberExpression{
-				Object:   &ast.Identifier{Name: "r"},
-				Property: &ast.Identifier{Name: "_measurement"},
-			},
-			Right: &ast.StringLiteral{
-				Value: measurementNames[len(measurementNames)-1],
-			},
-		}
-		for i := len(measurementNames) - 2; i >= 0; i-- {
-			filterExpr = &ast.LogicalExpression{
-				Operator: ast.OrOperator,
-				Left: &ast.BinaryExpression{
-					Operator: ast.EqualOperator,
-					Left: &ast.MemberExpression{
-						Object:   &ast.Identifier{Name: "r"},
-						Property: &ast.Identifier{Name: "_measurement"},
-					},
-					Right: &ast.StringLiteral{
-						Value: measurementNames[i],
-					},
-				},
-				Right: filterExpr,
-			}
-		}
-		expr = &ast.PipeExpression{
-			Argument: expr,
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "filter",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{Name: "fn"},
-								Value: &ast.FunctionExpression{
-									Params: []*ast.Property{
-										{
-											Key: &ast.Identifier{Name: "r"},
-										},
-									},
-									Body: filterExpr,
-								},
-							},
-						},
-					},
-				},
-			},
-		}
-	}
-
-	// TODO(jsternberg): Add the condition filter for the where clause.
-
-	// Create the key values op spec from the
-	var keyColumns []ast.Expression
-	switch expr := stmt.TagKeyExpr.(type) {
-	case *influxql.ListLiteral:
-		keyColumns = make([]ast.Expression, 0, len(expr.Vals))
-		for _, name := range expr.Vals {
-			keyColumns = append(keyColumns, &ast.StringLiteral{
-				Value: name,
-			})
-		}
-	case *influxql.StringLiteral:
-		switch stmt.Op {
-		case influxql.EQ:
-			keyColumns = []ast.Expression{
-				&ast.StringLiteral{
-					Value: expr.Val,
-				},
-			}
-		case influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX:
-			return nil, fmt.Errorf("unimplemented: tag key operand: %s", stmt.Op)
-		default:
-			return nil, fmt.Errorf("unsupported operand: %s", stmt.Op)
-		}
-	default:
-		return nil, fmt.Errorf("unsupported literal type: %T", expr)
-	}
-	expr = &ast.PipeExpression{
-		Argument: expr,
-		Call: &ast.CallExpression{
-			Callee: &ast.Identifier{
-				Name: "keyValues",
-			},
-			Arguments: []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "keyColumns",
-							},
-							Value: &ast.ArrayExpression{
-								Elements: keyColumns,
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// Group by the measurement and key, find distinct values, then group by the measurement
-	// to join all of the different keys together. Finish by renaming the columns. This is static.
-	return &ast.PipeExpression{
-		Argument: &ast.PipeExpression{
-			Argument: &ast.PipeExpression{
-				Argument: &ast.PipeExpression{
-					Argument: expr,
-					Call: &ast.CallExpression{
-						Callee: &ast.Identifier{Name: "group"},
-						Arguments: []ast.Expression{
-							&ast.ObjectExpression{
-								Properties: []*ast.Property{
-									{
-										Key: &ast.Identifier{
-											Name: "columns",
-										},
-										Value: &ast.ArrayExpression{
-											Elements: []ast.Expression{
-												&ast.StringLiteral{Value: "_measurement"},
-												&ast.StringLiteral{Value: "_key"},
-											},
-										},
-									},
-									{
-										Key: &ast.Identifier{
-											Name: "mode",
-										},
-										Value: &ast.StringLiteral{
-											Value: "by",
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-				Call: &ast.CallExpression{
-					Callee: &ast.Identifier{Name: "distinct"},
-				},
-			},
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{Name: "group"},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{
-									Name: "columns",
-								},
-								Value: &ast.ArrayExpression{
-									Elements: []ast.Expression{
-										&ast.StringLiteral{Value: "_measurement"},
-									},
-								},
-							},
-							{
-								Key: &ast.Identifier{
-									Name: "mode",
-								},
-								Value: &ast.StringLiteral{
-									Value: "by",
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-		Call: &ast.CallExpression{
-			Callee: &ast.Identifier{Name: "rename"},
-			Arguments: []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "columns",
-							},
-							Value: &ast.ObjectExpression{
-								Properties: []*ast.Property{
-									{
-										Key: &ast.Identifier{
-											Name: "_key",
-										},
-										Value: &ast.StringLiteral{
-											Value: "key",
-										},
-									},
-									{
-										Key: &ast.Identifier{
-											Name: "_value",
-										},
-										Value: &ast.StringLiteral{
-											Value: "value",
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-		},
-	}, nil
-}
-
 func (t *transpilerState) transpileShowDatabases(ctx context.Context, stmt *influxql.ShowDatabasesStatement) (ast.Expression, error) {
 	v1 := t.requireImport("influxdata/influxdb/v1")
 	return &ast.PipeExpression{
@@ -649,6 +398,34 @@ func (t *transpilerState) transpileSelect(ctx context.Context, stmt *influxql.Se
 	return cur, nil
 }
 
+// timeRange returns the time range of the current statement. The time range of
+// a subquery is limited by the time range of the query that encloses it.
+func (t *transpilerState) timeRange() (influxql.TimeRange, error) {
+	valuer := influxql.NowValuer{Now: t.config.Now}
+	_, tr, err := influxql.ConditionExpr(t.stmt.Condition, &valuer)
+	if err != nil {
+		return influxql.TimeRange{}, err
+	}
+	tr = tr.Intersect(t.bounds)
+
+	// If the maximum is not set and we have a windowing function, then
+	// the range will end at now.
+	if tr.Max.IsZero() {
+		if window, err := t.stmt.GroupByInterval(); err == nil && window > 0 {
+			tr.Max = t.config.Now.Add(-1)
+		}
+	}
+	return tr, nil
+}
+
+// rangeStop returns the exclusive stop of a range that includes the maximum time.
+func rangeStop(tr influxql.TimeRange) time.Time {
+	if tr.Max.IsZero() {
+		return tr.MaxTime()
+	}
+	return tr.Max.Add(1)
+}
+
 func (t *transpilerState) mapType(ref *influxql.VarRef) influxql.DataType {
 	// TODO(jsternberg): Actually evaluate the type against the schema.
 	return influxql.Tag
@@ -0,0 +1,207 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/influxdata/influxdb"
+)
+
+// Kind identifies what issued a query. Each kind of query has its own scan limits.
+type Kind string
+
+const (
+	// KindQuery is a query issued directly by a user.
+	KindQuery Kind = "query"
+	// KindTask is a query issued by a task run.
+	KindTask Kind = "task"
+	// KindCheck is a query issued by the task of a check or a notification rule.
+	KindCheck Kind = "check"
+)
+
+// Valid returns an error if k is not a known kind of query.
+func (k Kind) Valid() error {
+	switch k {
+	case KindQuery, KindTask, KindCheck:
+		return nil
+	}
+	return &influxdb.Error{
+		Code: influxdb.EInvalid,
+		Msg:  fmt.Sprintf("unknown query kind %q, expected one of query, task or check", string(k)),
+	}
+}
+
+// limitName returns the name of the limit as it is configured for k.
+func (k Kind) limitName(limit string) string {
+	if k == KindQuery || k == "" {
+		return "query-" + limit
+	}
+	return string(k) + "-query-" + limit
+}
+
+type kindContextKey struct{}
+
+// ContextWithKind returns a new context that marks the queries issued with it as kind.
+func ContextWithKind(ctx context.Context, kind Kind) context.Context {
+	return context.WithValue(ctx, kindContextKey{}, kind)
+}
+
+// KindFromContext returns the kind of query set on the context.
+// Queries are assumed to be issued by a user when no kind was set.
+func KindFromContext(ctx context.Context) Kind {
+	if kind, ok := ctx.Value(kindContextKey{}).(Kind); ok {
+		return kind
+	}
+	return KindQuery
+}
+
+// ScanLimits bound how much data a single query may read from storage.
+// A limit of zero disables that limit.
+type ScanLimits struct {
+	// MaxScannedValues is the maximum number of values a query may scan.
+	MaxScannedValues int `json:"maxScannedValues"`
+	// MaxSeries is the maximum number of series a query may read.
+	MaxSeries int `json:"maxSeries"`
+}
+
+// IsZero returns true if none of the limits are enabled.
+func (l ScanLimits) IsZero() bool {
+	return l.MaxScannedValues <= 0 && l.MaxSeries <= 0
+}
+
+// OrgScanLimits overrides the scan limits of one kind of query for an organization.
+type OrgScanLimits struct {
+	OrgID influxdb.ID
+	Kind  Kind
+	ScanLimits
+}
+
+// ParseOrgScanLimits parses scan limits for an organization from a string
+// of the form <org-id>:<kind>:<max-scanned-values>:<max-series>.
+func ParseOrgScanLimits(s string) (OrgScanLimits, error) {
+	invalid := func(msg string, err error) error {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid organization scan limits %q: %s", s, msg),
+			Err:  err,
+		}
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return OrgScanLimits{}, invalid("expected <org-id>:<kind>:<max-scanned-values>:<max-series>", nil)
+	}
+
+	var l OrgScanLimits
+	if err := l.OrgID.DecodeFromString(parts[0]); err != nil {
+		return OrgScanLimits{}, invalid("invalid organization id", err)
+	}
+	l.Kind = Kind(parts[1])
+	if err := l.Kind.Valid(); err != nil {
+		return OrgScanLimits{}, invalid("invalid kind", err)
+	}
+	var err error
+	if l.MaxScannedValues, err = strconv.Atoi(parts[2]); err != nil || l.MaxScannedValues < 0 {
+		return OrgScanLimits{}, invalid("max scanned values must be a non-negative integer", err)
+	}
+	if l.MaxSeries, err = strconv.Atoi(parts[3]); err != nil || l.MaxSeries < 0 {
+		return OrgScanLimits{}, invalid("max series must be a non-negative integer", err)
+	}
+	return l, nil
+}
+
+// ScanLimitsConfig configures the scan limits of every kind of query.
+type ScanLimitsConfig struct {
+	// Query, Task and Check are the default limits for each kind of query.
+	Query ScanLimits
+	Task  ScanLimits
+	Check ScanLimits
+
+	// Orgs overrides the default limits for specific organizations.
+	Orgs []OrgScanLimits
+}
+
+// Limits returns the scan limits of a query of the given kind issued for orgID.
+func (c ScanLimitsConfig) Limits(orgID influxdb.ID, kind Kind) ScanLimits {
+	for _, l := range c.Orgs {
+		if l.OrgID == orgID && l.Kind == kind {
+			return l.ScanLimits
+		}
+	}
+	switch kind {
+	case KindTask:
+		return c.Task
+	case KindCheck:
+		return c.Check
+	default:
+		return c.Query
+	}
+}
+
+// ScanLimiter tracks the values and series a single query reads from storage.
+// It is safe for concurrent use by all of the storage reads of the query.
+type ScanLimiter struct {
+	kind   Kind
+	limits ScanLimits
+
+	values int64
+	series int64
+}
+
+// NewScanLimiter returns a ScanLimiter that enforces limits on a query of the given kind.
+func NewScanLimiter(kind Kind, limits ScanLimits) *ScanLimiter {
+	return &ScanLimiter{
+		kind:   kind,
+		limits: limits,
+	}
+}
+
+// AddScannedValues records that n more values were scanned and returns
+// an error if the query has scanned more values than it is allowed to.
+func (l *ScanLimiter) AddScannedValues(n int) error {
+	values := atomic.AddInt64(&l.values, int64(n))
+	if max := l.limits.MaxScannedValues; max > 0 && values > int64(max) {
+		return l.exceeded(l.kind.limitName("max-scanned-values"), fmt.Sprintf("scanned more than %d values", max))
+	}
+	return nil
+}
+
+// AddSeries records that n more series were read and returns an error if
+// the query has read more series than it is allowed to.
+func (l *ScanLimiter) AddSeries(n int) error {
+	series := atomic.AddInt64(&l.series, int64(n))
+	if max := l.limits.MaxSeries; max > 0 && series > int64(max) {
+		return l.exceeded(l.kind.limitName("max-series"), fmt.Sprintf("read more than %d series", max))
+	}
+	return nil
+}
+
+// Limits returns the limits enforced by the ScanLimiter.
+func (l *ScanLimiter) Limits() ScanLimits {
+	return l.limits
+}
+
+func (l *ScanLimiter) exceeded(limit, msg string) error {
+	return &influxdb.Error{
+		Code: influxdb.ETooLarge,
+		Op:   "query/ScanLimiter",
+		Msg:  fmt.Sprintf("query %s and was aborted by the %s limit", msg, limit),
+	}
+}
+
+type scanLimiterContextKey struct{}
+
+// ContextWithScanLimiter returns a new context with the scan limiter of a query.
+func ContextWithScanLimiter(ctx context.Context, l *ScanLimiter) context.Context {
+	return context.WithValue(ctx, scanLimiterContextKey{}, l)
+}
+
+// ScanLimiterFromContext returns the scan limiter of the query,
+// or nil if the query is not limited.
+func ScanLimiterFromContext(ctx context.Context) *ScanLimiter {
+	l, _ := ctx.Value(scanLimiterContextKey{}).(*ScanLimiter)
+	return l
+}
@@ -0,0 +1,145 @@
+package query_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestParseOrgScanLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    query.OrgScanLimits
+		wantErr bool
+	}{
+		{
+			name: "task",
+			s:    "0000000000000001:task:1000:10",
+			want: query.OrgScanLimits{
+				OrgID:      1,
+				Kind:       query.KindTask,
+				ScanLimits: query.ScanLimits{MaxScannedValues: 1000, MaxSeries: 10},
+			},
+		},
+		{
+			name: "unlimited series",
+			s:    "0000000000000002:query:5:0",
+			want: query.OrgScanLimits{
+				OrgID:      2,
+				Kind:       query.KindQuery,
+				ScanLimits: query.ScanLimits{MaxScannedValues: 5},
+			},
+		},
+		{
+			name:    "missing limit",
+			s:       "0000000000000001:task:1000",
+			wantErr: true,
+		},
+		{
+			name:    "invalid org",
+			s:       "org:task:1000:10",
+			wantErr: true,
+		},
+		{
+			name:    "invalid kind",
+			s:       "0000000000000001:dashboard:1000:10",
+			wantErr: true,
+		},
+		{
+			name:    "negative limit",
+			s:       "0000000000000001:check:-1:10",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := query.ParseOrgScanLimits(tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				if code := influxdb.ErrorCode(err); code != influxdb.EInvalid {
+					t.Errorf("unexpected error code %q", code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("unexpected limits; got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanLimitsConfig_Limits(t *testing.T) {
+	c := query.ScanLimitsConfig{
+		Query: query.ScanLimits{MaxScannedValues: 1},
+		Task:  query.ScanLimits{MaxScannedValues: 2},
+		Check: query.ScanLimits{MaxScannedValues: 3},
+		Orgs: []query.OrgScanLimits{
+			{OrgID: 10, Kind: query.KindCheck, ScanLimits: query.ScanLimits{MaxSeries: 4}},
+		},
+	}
+	tests := []struct {
+		orgID influxdb.ID
+		kind  query.Kind
+		want  query.ScanLimits
+	}{
+		{orgID: 1, kind: query.KindQuery, want: query.ScanLimits{MaxScannedValues: 1}},
+		{orgID: 1, kind: query.KindTask, want: query.ScanLimits{MaxScannedValues: 2}},
+		{orgID: 1, kind: query.KindCheck, want: query.ScanLimits{MaxScannedValues: 3}},
+		{orgID: 10, kind: query.KindQuery, want: query.ScanLimits{MaxScannedValues: 1}},
+		{orgID: 10, kind: query.KindCheck, want: query.ScanLimits{MaxSeries: 4}},
+	}
+	for _, tt := range tests {
+		if got := c.Limits(tt.orgID, tt.kind); got != tt.want {
+			t.Errorf("unexpected limits for org %s and kind %s; got %+v, want %+v", tt.orgID, tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestScanLimiter(t *testing.T) {
+	l := query.NewScanLimiter(query.KindCheck, query.ScanLimits{MaxScannedValues: 10, MaxSeries: 2})
+
+	if err := l.AddScannedValues(10); err != nil {
+		t.Fatalf("unexpected error at the limit: %v", err)
+	}
+	err := l.AddScannedValues(1)
+	if err == nil {
+		t.Fatal("expected an error once the limit is exceeded")
+	}
+	if code := influxdb.ErrorCode(err); code != influxdb.ETooLarge {
+		t.Errorf("unexpected error code %q", code)
+	}
+	if !strings.Contains(err.Error(), "check-query-max-scanned-values") {
+		t.Errorf("expected error to name the limit, got %q", err)
+	}
+
+	if err := l.AddSeries(2); err != nil {
+		t.Fatalf("unexpected error at the limit: %v", err)
+	}
+	if err := l.AddSeries(1); err == nil || !strings.Contains(err.Error(), "check-query-max-series") {
+		t.Errorf("expected error naming the series limit, got %v", err)
+	}
+
+	unlimited := query.NewScanLimiter(query.KindQuery, query.ScanLimits{})
+	if err := unlimited.AddScannedValues(1 << 30); err != nil {
+		t.Errorf("unexpected error without limits: %v", err)
+	}
+}
+
+func TestKindFromContext(t *testing.T) {
+	if got := query.KindFromContext(context.Background()); got != query.KindQuery {
+		t.Errorf("unexpected default kind %q", got)
+	}
+	ctx := query.ContextWithKind(context.Background(), query.KindTask)
+	if got := query.KindFromContext(ctx); got != query.KindTask {
+		t.Errorf("unexpected kind %q", got)
+	}
+}
@@ -0,0 +1,333 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+const (
+	// ProfileCompilerType is the compiler type of a ProfileCompiler.
+	ProfileCompilerType = "profile"
+
+	// CursorStatsMetadataKey is the flux.Metadata key that storage sources
+	// use to report the cursor statistics of their dataset.
+	CursorStatsMetadataKey = "influxdb/cursor-stats"
+
+	profileKind = "influxdb/profile"
+)
+
+func init() {
+	execute.RegisterTransformation(profileKind, createProfileTransformation)
+}
+
+// SourceCursorStats are the cursor statistics collected by a single
+// storage source while it read its tables.
+type SourceCursorStats struct {
+	// DatasetID is the ID of the dataset produced by the source.
+	DatasetID execute.DatasetID
+	cursors.CursorStats
+}
+
+// Profile reports how a query behaved during execution.
+type Profile struct {
+	Operators []*OperatorProfile `json:"operators"`
+
+	TotalDuration   time.Duration `json:"totalDuration"`
+	CompileDuration time.Duration `json:"compileDuration"`
+	QueueDuration   time.Duration `json:"queueDuration"`
+	ExecuteDuration time.Duration `json:"executeDuration"`
+	MaxAllocated    int64         `json:"maxAllocated"`
+	TotalAllocated  int64         `json:"totalAllocated"`
+}
+
+// OperatorProfile reports the output of a single operator in the physical plan.
+type OperatorProfile struct {
+	Node             string `json:"node"`
+	Kind             string `json:"kind"`
+	StorageOperation string `json:"storageOperation,omitempty"`
+
+	// Tables and Rows are the number of tables and rows the operator produced.
+	Tables int64 `json:"tables"`
+	Rows   int64 `json:"rows"`
+	// Bytes is the size of the column buffers the operator produced.
+	Bytes int64 `json:"bytes"`
+	// Duration is the time from the start of execution until the
+	// operator finished producing tables.
+	Duration time.Duration `json:"duration"`
+
+	// ScannedValues and ScannedBytes are the storage cursor statistics.
+	// They are only reported for operators pushed down to storage.
+	ScannedValues int `json:"scannedValues,omitempty"`
+	ScannedBytes  int `json:"scannedBytes,omitempty"`
+}
+
+// Profiler collects the output of every operator in a physical plan.
+// A Profiler is only valid for a single execution.
+type Profiler struct {
+	mu        sync.Mutex
+	start     time.Time
+	operators []*OperatorProfile
+	byNode    map[plan.NodeID]*OperatorProfile
+}
+
+// NewProfiler returns a new Profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{
+		byNode: make(map[plan.NodeID]*OperatorProfile),
+	}
+}
+
+// Instrument inserts a profiling operator after every operator of the plan
+// that has successors. The profiling operators pass tables through
+// unchanged while recording what flows through them.
+func (p *Profiler) Instrument(ps *plan.Spec) error {
+	var nodes []plan.Node
+	if err := ps.TopDownWalk(func(n plan.Node) error {
+		if _, ok := n.ProcedureSpec().(plan.YieldProcedureSpec); ok {
+			return nil
+		}
+		if len(n.Successors()) == 0 {
+			return nil
+		}
+		nodes = append(nodes, n)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Insert the profiling nodes in the order the plan is read,
+	// from the sources down to the results.
+	for i := len(nodes) - 1; i >= 0; i-- {
+		n := nodes[i]
+		op := &OperatorProfile{
+			Node: string(n.ID()),
+			Kind: string(n.Kind()),
+		}
+		if s, ok := n.ProcedureSpec().(StorageReadSpec); ok {
+			op.StorageOperation = s.StorageOperation()
+		}
+		p.operators = append(p.operators, op)
+		p.byNode[n.ID()] = op
+
+		pn := plan.CreatePhysicalNode(plan.NodeID("profile_"+string(n.ID())), &profileProcedureSpec{
+			profiler: p,
+			op:       op,
+		})
+		pn.TriggerSpec = plan.NarrowTransformationTriggerSpec{}
+		pn.SetBounds(n.Bounds())
+
+		succs := append([]plan.Node(nil), n.Successors()...)
+		n.ClearSuccessors()
+		n.AddSuccessors(pn)
+		pn.AddPredecessors(n)
+		for _, succ := range succs {
+			preds := append([]plan.Node(nil), succ.Predecessors()...)
+			succ.ClearPredecessors()
+			for _, pred := range preds {
+				if pred == n {
+					pred = pn
+				}
+				succ.AddPredecessors(pred)
+			}
+			pn.AddSuccessors(succ)
+		}
+	}
+	return ps.CheckIntegrity()
+}
+
+// Profile returns the profile of the executed query. The statistics
+// are those returned by the query once it has finished.
+func (p *Profiler) Profile(stats flux.Statistics) *Profile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cursorStats := make(map[execute.DatasetID]cursors.CursorStats)
+	for _, v := range stats.Metadata[CursorStatsMetadataKey] {
+		if s, ok := v.(SourceCursorStats); ok {
+			cs := cursorStats[s.DatasetID]
+			cs.Add(s.CursorStats)
+			cursorStats[s.DatasetID] = cs
+		}
+	}
+	for id, op := range p.byNode {
+		if cs, ok := cursorStats[execute.DatasetIDFromNodeID(id)]; ok {
+			op.ScannedValues = cs.ScannedValues
+			op.ScannedBytes = cs.ScannedBytes
+		}
+	}
+
+	return &Profile{
+		Operators:       p.operators,
+		TotalDuration:   stats.TotalDuration,
+		CompileDuration: stats.CompileDuration,
+		QueueDuration:   stats.QueueDuration,
+		ExecuteDuration: stats.ExecuteDuration,
+		MaxAllocated:    stats.MaxAllocated,
+		TotalAllocated:  stats.TotalAllocated,
+	}
+}
+
+// begin marks the start of execution. Profiling transformations
+// are created right before the executor starts the sources.
+func (p *Profiler) begin() {
+	p.mu.Lock()
+	if p.start.IsZero() {
+		p.start = time.Now()
+	}
+	p.mu.Unlock()
+}
+
+func (p *Profiler) record(op *OperatorProfile, rows, bytes int64) {
+	p.mu.Lock()
+	op.Tables++
+	op.Rows += rows
+	op.Bytes += bytes
+	p.mu.Unlock()
+}
+
+func (p *Profiler) finish(op *OperatorProfile) {
+	p.mu.Lock()
+	op.Duration = time.Since(p.start)
+	p.mu.Unlock()
+}
+
+// ProfileCompiler is a flux.Compiler for a plan that has already been
+// planned and instrumented by a Profiler.
+type ProfileCompiler struct {
+	Spec *plan.Spec
+}
+
+// Compile returns a program that executes the instrumented plan.
+func (c ProfileCompiler) Compile(ctx context.Context) (flux.Program, error) {
+	if c.Spec == nil {
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  "profile compiler requires a plan",
+		}
+	}
+	return &lang.Program{PlanSpec: c.Spec}, nil
+}
+
+// CompilerType returns the ProfileCompilerType.
+func (ProfileCompiler) CompilerType() flux.CompilerType {
+	return ProfileCompilerType
+}
+
+type profileProcedureSpec struct {
+	plan.DefaultCost
+
+	profiler *Profiler
+	op       *OperatorProfile
+}
+
+func (s *profileProcedureSpec) Kind() plan.ProcedureKind {
+	return profileKind
+}
+
+func (s *profileProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+// TriggerSpec implements plan.TriggerAwareProcedureSpec.
+func (s *profileProcedureSpec) TriggerSpec() plan.TriggerSpec {
+	return plan.NarrowTransformationTriggerSpec{}
+}
+
+func createProfileTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*profileProcedureSpec)
+	if !ok {
+		return nil, nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  fmt.Sprintf("invalid spec type %T", spec),
+		}
+	}
+	s.profiler.begin()
+	d := execute.NewPassthroughDataset(id)
+	t := &profileTransformation{
+		d:        d,
+		profiler: s.profiler,
+		op:       s.op,
+	}
+	return t, d, nil
+}
+
+// profileTransformation buffers every table it receives so that it
+// can be measured before passing it on unchanged.
+type profileTransformation struct {
+	d        *execute.PassthroughDataset
+	profiler *Profiler
+	op       *OperatorProfile
+}
+
+func (t *profileTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *profileTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	buf, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	var rows, bytes int64
+	for i, n := 0, buf.BufferN(); i < n; i++ {
+		cr := buf.Buffer(i)
+		rows += int64(cr.Len())
+		bytes += colReaderBytes(cr)
+	}
+	t.profiler.record(t.op, rows, bytes)
+	return t.d.Process(buf)
+}
+
+func (t *profileTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *profileTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *profileTransformation) Finish(id execute.DatasetID, err error) {
+	t.profiler.finish(t.op)
+	t.d.Finish(err)
+}
+
+// colReaderBytes returns the size of the buffers backing the columns of cr.
+func colReaderBytes(cr flux.ColReader) int64 {
+	var n int64
+	for j, c := range cr.Cols() {
+		var arr array.Interface
+		switch c.Type {
+		case flux.TBool:
+			arr = cr.Bools(j)
+		case flux.TInt:
+			arr = cr.Ints(j)
+		case flux.TUInt:
+			arr = cr.UInts(j)
+		case flux.TFloat:
+			arr = cr.Floats(j)
+		case flux.TString:
+			arr = cr.Strings(j)
+		case flux.TTime:
+			arr = cr.Times(j)
+		default:
+			continue
+		}
+		for _, b := range arr.Data().Buffers() {
+			if b != nil {
+				n += int64(b.Len())
+			}
+		}
+	}
+	return n
+}
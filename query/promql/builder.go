@@ -0,0 +1,630 @@
+package promql
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+const (
+	// DefaultBucket is the bucket queried when an evaluation does not name one.
+	DefaultBucket = "prometheus"
+	// DefaultLookback is how far back an instant vector selector looks for the
+	// latest sample of a series.
+	DefaultLookback = 5 * time.Minute
+
+	metricNameLabel = "__name__"
+	fieldColumn     = "_field"
+	sideColumn      = "_side"
+)
+
+// Evaluation describes where and when a PromQL expression is evaluated.
+//
+// A zero Step evaluates an instant query at Start, or relative to now when Start is zero.
+// A positive Step evaluates a range query at every Step from Start through End.
+type Evaluation struct {
+	Bucket   string
+	BucketID string
+	Start    time.Time
+	End      time.Time
+	Step     time.Duration
+}
+
+// IsRange reports whether the evaluation is a range query.
+func (e Evaluation) IsRange() bool {
+	return e.Step > 0
+}
+
+// Validate returns an error if the evaluation cannot be built.
+func (e Evaluation) Validate() error {
+	if e.Step < 0 {
+		return fmt.Errorf("zero or negative query resolution step widths are not accepted")
+	}
+	if e.IsRange() {
+		if e.Start.IsZero() || e.End.IsZero() {
+			return fmt.Errorf("range queries require a start and an end")
+		}
+		if e.End.Before(e.Start) {
+			return fmt.Errorf("end timestamp must not be before start time")
+		}
+	}
+	return nil
+}
+
+// last returns the final step of the evaluation.
+func (e Evaluation) last() time.Time {
+	if !e.IsRange() {
+		return e.Start
+	}
+	steps := e.End.Sub(e.Start) / e.Step
+	return e.Start.Add(steps * e.Step)
+}
+
+// IsMatrix reports whether expr evaluated as described by eval results in a range vector.
+func IsMatrix(expr Expr, eval Evaluation) bool {
+	s, ok := expr.(*Selector)
+	return ok && s.Range > 0 && !eval.IsRange()
+}
+
+// BuildEvaluation parses promql and builds a flux query specification
+// evaluating it as described by eval.
+func BuildEvaluation(promql string, eval Evaluation, opts ...Option) (*flux.Spec, error) {
+	parsed, err := ParsePromQL(promql, opts...)
+	if err != nil {
+		return nil, err
+	}
+	expr, ok := parsed.(Expr)
+	if !ok {
+		return nil, fmt.Errorf("unable to build as %T is not a QueryBuilder", parsed)
+	}
+	return buildSpec(expr, eval)
+}
+
+// buildSpec builds the specification of expr.
+//
+// Every vector is built into the same shape: one table per series grouped by
+// its labels, the metric name in _field and one row per evaluation step.
+func buildSpec(expr Expr, eval Evaluation) (*flux.Spec, error) {
+	if err := eval.Validate(); err != nil {
+		return nil, err
+	}
+	b := &specBuilder{
+		eval: eval,
+		spec: &flux.Spec{},
+		ids:  make(map[flux.OperationKind]int),
+	}
+
+	switch e := expr.(type) {
+	case *Selector:
+		if e.Range > 0 {
+			if eval.IsRange() {
+				return nil, fmt.Errorf(`invalid expression type "range vector" for range query, must be Number or instant vector`)
+			}
+			if err := b.matrix(e); err != nil {
+				return nil, err
+			}
+			return b.spec, nil
+		}
+	case *AggregateExpr, *BinaryExpr, *Call:
+	default:
+		return expr.QuerySpec()
+	}
+
+	if _, err := b.vector(expr); err != nil {
+		return nil, err
+	}
+	return b.spec, nil
+}
+
+type specBuilder struct {
+	eval Evaluation
+	spec *flux.Spec
+	ids  map[flux.OperationKind]int
+}
+
+// add appends an operation as the child of parents and returns its ID.
+func (b *specBuilder) add(spec flux.OperationSpec, parents ...flux.OperationID) flux.OperationID {
+	kind := spec.Kind()
+	id := flux.OperationID(kind)
+	if n := b.ids[kind]; n > 0 {
+		id = flux.OperationID(fmt.Sprintf("%s%d", kind, n))
+	}
+	b.ids[kind]++
+
+	b.spec.Operations = append(b.spec.Operations, &flux.Operation{
+		ID:   id,
+		Spec: spec,
+	})
+	for _, parent := range parents {
+		b.spec.Edges = append(b.spec.Edges, flux.Edge{
+			Parent: parent,
+			Child:  id,
+		})
+	}
+	return id
+}
+
+// vector builds an expression that must evaluate to an instant vector.
+func (b *specBuilder) vector(expr Expr) (flux.OperationID, error) {
+	switch e := expr.(type) {
+	case *Selector:
+		if e.Range > 0 {
+			return "", fmt.Errorf("expected type instant vector, got range vector")
+		}
+		return b.sample(e, DefaultLookback, &universe.LastOpSpec{
+			SelectorConfig: execute.DefaultSelectorConfig,
+		})
+	case *AggregateExpr:
+		return b.aggregate(e)
+	case *BinaryExpr:
+		return b.binary(e)
+	case *Call:
+		return b.call(e)
+	case *Number:
+		return "", fmt.Errorf("expected type instant vector, got scalar")
+	default:
+		return "", fmt.Errorf("unable to build %T as a vector", expr)
+	}
+}
+
+// selection reads the series matching s between lookback before the first step
+// and the last step.
+//
+// Prometheus selects samples from (t - lookback, t] while range selects from
+// [start, stop) so both bounds are shifted by a nanosecond. Range queries read
+// another lookback past the last step as windows overlapping the stop of the
+// range would otherwise be truncated to end at the last step.
+func (b *specBuilder) selection(s *Selector, lookback time.Duration) (flux.OperationID, error) {
+	where, err := NewWhereOperation(s.Name, s.LabelMatchers)
+	if err != nil {
+		return "", err
+	}
+
+	from := &influxdb.FromOpSpec{
+		Bucket:   b.eval.Bucket,
+		BucketID: b.eval.BucketID,
+	}
+	if from.Bucket == "" && from.BucketID == "" {
+		from.Bucket = DefaultBucket
+	}
+	rng := &universe.RangeOpSpec{
+		TimeColumn:  execute.DefaultTimeColLabel,
+		StartColumn: execute.DefaultStartColLabel,
+		StopColumn:  execute.DefaultStopColLabel,
+	}
+	if b.eval.Start.IsZero() {
+		rng.Start = flux.Time{IsRelative: true, Relative: -s.Offset - lookback + time.Nanosecond}
+		rng.Stop = flux.Time{IsRelative: true, Relative: -s.Offset + time.Nanosecond}
+	} else {
+		rng.Start = flux.Time{Absolute: b.eval.Start.Add(-s.Offset - lookback + time.Nanosecond)}
+		stop := b.eval.last().Add(-s.Offset + time.Nanosecond)
+		if b.eval.IsRange() {
+			stop = stop.Add(lookback)
+		}
+		rng.Stop = flux.Time{Absolute: stop}
+	}
+
+	id := b.add(from)
+	id = b.add(rng, id)
+	return b.add(where.Spec, id), nil
+}
+
+// matrix builds a range vector of the raw samples selected by s.
+func (b *specBuilder) matrix(s *Selector) error {
+	id, err := b.selection(s, s.Range)
+	if err != nil {
+		return err
+	}
+	b.add(&universe.DropOpSpec{
+		Columns: []string{execute.DefaultStartColLabel, execute.DefaultStopColLabel, "_measurement"},
+	}, id)
+	return nil
+}
+
+// sample builds an instant vector reducing the samples in the lookback of
+// every step with reduce.
+func (b *specBuilder) sample(s *Selector, lookback time.Duration, reduce flux.OperationSpec) (flux.OperationID, error) {
+	id, err := b.selection(s, lookback)
+	if err != nil {
+		return "", err
+	}
+
+	if b.eval.IsRange() {
+		// Align the window stops with the steps shifted by the offset.
+		step := b.eval.Step.Nanoseconds()
+		offset := (b.eval.Start.Add(-s.Offset+time.Nanosecond).UnixNano()%step + step) % step
+		id = b.add(&universe.WindowOpSpec{
+			Every:       values.ConvertDuration(b.eval.Step),
+			Period:      values.ConvertDuration(lookback),
+			Offset:      values.ConvertDuration(time.Duration(offset)),
+			TimeColumn:  execute.DefaultTimeColLabel,
+			StartColumn: execute.DefaultStartColLabel,
+			StopColumn:  execute.DefaultStopColLabel,
+		}, id)
+	}
+
+	id = b.add(reduce, id)
+	// The stop of each window is the step it is evaluated at.
+	id = b.add(&universe.DuplicateOpSpec{
+		Column: execute.DefaultStopColLabel,
+		As:     execute.DefaultTimeColLabel,
+	}, id)
+	id = b.add(&universe.ShiftOpSpec{
+		Shift:   values.ConvertDuration(s.Offset - time.Nanosecond),
+		Columns: []string{execute.DefaultTimeColLabel},
+	}, id)
+	id = b.add(&universe.DropOpSpec{
+		Columns: []string{execute.DefaultStartColLabel, execute.DefaultStopColLabel, "_measurement"},
+	}, id)
+
+	if b.eval.IsRange() {
+		// Windows partially overlapping the bounds of the range do not end at a step.
+		id = b.add(&universe.FilterOpSpec{
+			Fn: rowFunction(&semantic.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left: &semantic.BinaryExpression{
+					Operator: ast.GreaterThanEqualOperator,
+					Left:     column(execute.DefaultTimeColLabel),
+					Right:    &semantic.DateTimeLiteral{Value: b.eval.Start},
+				},
+				Right: &semantic.BinaryExpression{
+					Operator: ast.LessThanEqualOperator,
+					Left:     column(execute.DefaultTimeColLabel),
+					Right:    &semantic.DateTimeLiteral{Value: b.eval.last()},
+				},
+			}),
+		}, id)
+	}
+	return id, nil
+}
+
+// aggregate builds an aggregation over the series of every step.
+func (b *specBuilder) aggregate(a *AggregateExpr) (flux.OperationID, error) {
+	var param float64
+	switch a.Op.Kind {
+	case CountValuesKind:
+		return "", fmt.Errorf("count_values is not supported")
+	case TopKind, BottomKind, QuantileKind:
+		n, ok := a.Op.Arg.(*Number)
+		if !ok {
+			return "", fmt.Errorf("expected a number as the aggregation parameter")
+		}
+		param = n.Val
+	case UnknownOpKind:
+		return "", fmt.Errorf("unknown aggregation operator")
+	}
+
+	id, err := b.vector(a.Expr)
+	if err != nil {
+		return "", err
+	}
+
+	var labels []string
+	without := false
+	if a.Aggregate != nil {
+		without = a.Aggregate.Without
+		for _, l := range a.Aggregate.Labels {
+			labels = append(labels, l.Name)
+		}
+	}
+
+	switch a.Op.Kind {
+	case TopKind, BottomKind:
+		// topk and bottomk keep the selected series as they are.
+		if without {
+			id = b.add(&universe.GroupOpSpec{
+				Mode:    "except",
+				Columns: append(labels, fieldColumn, execute.DefaultValueColLabel),
+			}, id)
+		} else {
+			id = b.add(&universe.GroupOpSpec{
+				Mode:    "by",
+				Columns: append(labels, execute.DefaultTimeColLabel),
+			}, id)
+		}
+		id = b.add(&universe.SortOpSpec{
+			Columns: []string{execute.DefaultValueColLabel},
+			Desc:    a.Op.Kind == TopKind,
+		}, id)
+		id = b.add(&universe.LimitOpSpec{N: int64(param)}, id)
+		return b.regroup(id), nil
+	}
+
+	if without {
+		id = b.add(&universe.DropOpSpec{
+			Columns: append(labels, fieldColumn),
+		}, id)
+		id = b.add(&universe.GroupOpSpec{
+			Mode:    "except",
+			Columns: []string{execute.DefaultValueColLabel},
+		}, id)
+	} else {
+		id = b.add(&universe.KeepOpSpec{
+			Columns: append(labels, execute.DefaultTimeColLabel, execute.DefaultValueColLabel),
+		}, id)
+		id = b.add(&universe.GroupOpSpec{
+			Mode:    "by",
+			Columns: append(labels, execute.DefaultTimeColLabel),
+		}, id)
+	}
+
+	switch a.Op.Kind {
+	case SumKind:
+		id = b.add(&universe.SumOpSpec{AggregateConfig: execute.DefaultAggregateConfig}, id)
+	case CountKind:
+		// Counting as the sum of ones keeps the values floats.
+		id = b.add(mapValue(&semantic.FloatLiteral{Value: 1}), id)
+		id = b.add(&universe.SumOpSpec{AggregateConfig: execute.DefaultAggregateConfig}, id)
+	case AvgKind:
+		id = b.add(&universe.MeanOpSpec{AggregateConfig: execute.DefaultAggregateConfig}, id)
+	case MinKind:
+		id = b.add(&universe.MinOpSpec{SelectorConfig: execute.DefaultSelectorConfig}, id)
+	case MaxKind:
+		id = b.add(&universe.MaxOpSpec{SelectorConfig: execute.DefaultSelectorConfig}, id)
+	case StdevKind, StdVarKind:
+		id = b.add(&universe.StddevOpSpec{
+			Mode:            "population",
+			AggregateConfig: execute.DefaultAggregateConfig,
+		}, id)
+		if a.Op.Kind == StdVarKind {
+			id = b.add(mapValue(&semantic.BinaryExpression{
+				Operator: ast.MultiplicationOperator,
+				Left:     column(execute.DefaultValueColLabel),
+				Right:    column(execute.DefaultValueColLabel),
+			}), id)
+		}
+	case QuantileKind:
+		id = b.add(&universe.QuantileOpSpec{
+			Quantile:        param,
+			Method:          "exact_mean",
+			AggregateConfig: execute.DefaultAggregateConfig,
+		}, id)
+	}
+	return b.regroup(id), nil
+}
+
+// call builds a function call.
+func (b *specBuilder) call(c *Call) (flux.OperationID, error) {
+	switch c.Func {
+	case "rate", "irate", "increase":
+		if len(c.Args) != 1 {
+			return "", fmt.Errorf("expected 1 argument(s) in call to %q, got %d", c.Func, len(c.Args))
+		}
+		s, ok := c.Args[0].(*Selector)
+		if !ok || s.Range == 0 {
+			return "", fmt.Errorf("expected type range vector in call to function %q", c.Func)
+		}
+
+		var (
+			reduce flux.OperationSpec
+			err    error
+		)
+		switch c.Func {
+		case "rate":
+			reduce, err = internalOpSpec("extrapolatedRate", map[string]interface{}{"isCounter": true, "isRate": true})
+		case "increase":
+			reduce, err = internalOpSpec("extrapolatedRate", map[string]interface{}{"isCounter": true, "isRate": false})
+		case "irate":
+			reduce, err = internalOpSpec("instantRate", map[string]interface{}{"isRate": true})
+		}
+		if err != nil {
+			return "", err
+		}
+
+		id, err := b.sample(s, s.Range, reduce)
+		if err != nil {
+			return "", err
+		}
+		return b.add(&universe.DropOpSpec{Columns: []string{fieldColumn}}, id), nil
+	case "histogram_quantile":
+		if len(c.Args) != 2 {
+			return "", fmt.Errorf("expected 2 argument(s) in call to %q, got %d", c.Func, len(c.Args))
+		}
+		q, ok := c.Args[0].(*Number)
+		if !ok {
+			return "", fmt.Errorf("expected type scalar in call to function %q", c.Func)
+		}
+		id, err := b.vector(c.Args[1])
+		if err != nil {
+			return "", err
+		}
+		quantile, err := internalOpSpec("promHistogramQuantile", map[string]interface{}{
+			"quantile":         q.Val,
+			"countColumn":      execute.DefaultValueColLabel,
+			"upperBoundColumn": "le",
+			"valueColumn":      execute.DefaultValueColLabel,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		id = b.add(&universe.GroupOpSpec{
+			Mode:    "except",
+			Columns: []string{"le", execute.DefaultValueColLabel},
+		}, id)
+		id = b.add(quantile, id)
+		id = b.add(&universe.DropOpSpec{Columns: []string{fieldColumn}}, id)
+		return b.regroup(id), nil
+	default:
+		return "", fmt.Errorf("unknown function with name %q", c.Func)
+	}
+}
+
+// binary builds an arithmetic operation or a comparison filter.
+func (b *specBuilder) binary(e *BinaryExpr) (flux.OperationID, error) {
+	op, err := astOperator(e.Op)
+	if err != nil {
+		return "", err
+	}
+
+	ln, lok := e.LHS.(*Number)
+	rn, rok := e.RHS.(*Number)
+	switch {
+	case lok && rok:
+		return "", fmt.Errorf("unable to build a query for the scalar expression")
+	case lok || rok:
+		// Vector and scalar operations apply to every sample of the vector.
+		var (
+			vector Expr
+			left   semantic.Expression = column(execute.DefaultValueColLabel)
+			right  semantic.Expression = column(execute.DefaultValueColLabel)
+		)
+		if lok {
+			vector = e.RHS
+			left = &semantic.FloatLiteral{Value: ln.Val}
+		} else {
+			vector = e.LHS
+			right = &semantic.FloatLiteral{Value: rn.Val}
+		}
+		id, err := b.vector(vector)
+		if err != nil {
+			return "", err
+		}
+		expr := &semantic.BinaryExpression{Operator: op, Left: left, Right: right}
+		if e.Op.IsComparison() {
+			return b.add(&universe.FilterOpSpec{Fn: rowFunction(expr)}, id), nil
+		}
+		id = b.add(&universe.DropOpSpec{Columns: []string{fieldColumn}}, id)
+		return b.add(mapValue(expr), id), nil
+	}
+
+	// Vector to vector operations match the samples of series with
+	// the same labels by pivoting both sides into a single row.
+	lhs, err := b.vector(e.LHS)
+	if err != nil {
+		return "", err
+	}
+	rhs, err := b.vector(e.RHS)
+	if err != nil {
+		return "", err
+	}
+	lhs = b.side(lhs, "lhs")
+	rhs = b.side(rhs, "rhs")
+
+	id := b.add(&universe.UnionOpSpec{}, lhs, rhs)
+	id = b.add(&universe.GroupOpSpec{
+		Mode:    "except",
+		Columns: []string{execute.DefaultTimeColLabel, execute.DefaultValueColLabel, sideColumn},
+	}, id)
+	id = b.add(&universe.PivotOpSpec{
+		RowKey:      []string{execute.DefaultTimeColLabel},
+		ColumnKey:   []string{sideColumn},
+		ValueColumn: execute.DefaultValueColLabel,
+	}, id)
+	id = b.add(&universe.FilterOpSpec{
+		Fn: rowFunction(&semantic.LogicalExpression{
+			Operator: ast.AndOperator,
+			Left:     &semantic.UnaryExpression{Operator: ast.ExistsOperator, Argument: column("lhs")},
+			Right:    &semantic.UnaryExpression{Operator: ast.ExistsOperator, Argument: column("rhs")},
+		}),
+	}, id)
+
+	expr := &semantic.BinaryExpression{Operator: op, Left: column("lhs"), Right: column("rhs")}
+	if e.Op.IsComparison() {
+		id = b.add(&universe.FilterOpSpec{Fn: rowFunction(expr)}, id)
+		id = b.add(mapValue(column("lhs")), id)
+	} else {
+		id = b.add(mapValue(expr), id)
+	}
+	return b.add(&universe.DropOpSpec{Columns: []string{"lhs", "rhs"}}, id), nil
+}
+
+// side marks the samples of one side of a vector to vector operation.
+func (b *specBuilder) side(id flux.OperationID, name string) flux.OperationID {
+	id = b.add(&universe.DropOpSpec{Columns: []string{fieldColumn}}, id)
+	return b.add(&universe.SetOpSpec{Key: sideColumn, Value: name}, id)
+}
+
+// regroup groups the samples of every step back into one table per series.
+func (b *specBuilder) regroup(id flux.OperationID) flux.OperationID {
+	return b.add(&universe.GroupOpSpec{
+		Mode:    "except",
+		Columns: []string{execute.DefaultTimeColLabel, execute.DefaultValueColLabel},
+	}, id)
+}
+
+// internalOpSpec creates the spec of an operation registered by the flux
+// internal/promql package which cannot be imported directly.
+func internalOpSpec(kind flux.OperationKind, spec map[string]interface{}) (flux.OperationSpec, error) {
+	newSpec := flux.OperationSpecNewFn(kind)
+	if newSpec == nil {
+		return nil, fmt.Errorf("operation %q is not registered", kind)
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	s := newSpec()
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var binaryOperators = map[BinaryOpKind]ast.OperatorKind{
+	OpAdd:          ast.AdditionOperator,
+	OpSub:          ast.SubtractionOperator,
+	OpMul:          ast.MultiplicationOperator,
+	OpDiv:          ast.DivisionOperator,
+	OpMod:          ast.ModuloOperator,
+	OpPow:          ast.PowerOperator,
+	OpEqual:        ast.EqualOperator,
+	OpNotEqual:     ast.NotEqualOperator,
+	OpGreater:      ast.GreaterThanOperator,
+	OpLess:         ast.LessThanOperator,
+	OpGreaterEqual: ast.GreaterThanEqualOperator,
+	OpLessEqual:    ast.LessThanEqualOperator,
+}
+
+func astOperator(op BinaryOpKind) (ast.OperatorKind, error) {
+	kind, ok := binaryOperators[op]
+	if !ok {
+		return 0, fmt.Errorf("unknown binary operator %d", op)
+	}
+	return kind, nil
+}
+
+// column references a column of the row r.
+func column(name string) *semantic.MemberExpression {
+	return &semantic.MemberExpression{
+		Object:   &semantic.IdentifierExpression{Name: "r"},
+		Property: name,
+	}
+}
+
+// rowFunction creates the function (r) => body.
+func rowFunction(body semantic.Expression) interpreter.ResolvedFunction {
+	return interpreter.ResolvedFunction{
+		Scope: values.NewScope(),
+		Fn: &semantic.FunctionExpression{
+			Block: &semantic.FunctionBlock{
+				Parameters: &semantic.FunctionParameters{
+					List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
+				},
+				Body: body,
+			},
+		},
+	}
+}
+
+// mapValue replaces the _value of every row with value.
+func mapValue(value semantic.Expression) *universe.MapOpSpec {
+	return &universe.MapOpSpec{
+		Fn: rowFunction(&semantic.ObjectExpression{
+			With: &semantic.IdentifierExpression{Name: "r"},
+			Properties: []*semantic.Property{{
+				Key:   &semantic.Identifier{Name: execute.DefaultValueColLabel},
+				Value: value,
+			}},
+		}),
+	}
+}
@@ -0,0 +1,74 @@
+package promql
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/plan"
+)
+
+const CompilerType = "promql"
+
+// AddCompilerMappings adds the promql specific compiler mappings.
+func AddCompilerMappings(mappings flux.CompilerMappings) error {
+	return mappings.Add(CompilerType, func() flux.Compiler {
+		return new(Compiler)
+	})
+}
+
+// Compiler builds a PromQL expression into a Flux program.
+type Compiler struct {
+	Query    string        `json:"query"`
+	Bucket   string        `json:"bucket,omitempty"`
+	BucketID string        `json:"bucketID,omitempty"`
+	Start    time.Time     `json:"start,omitempty"`
+	End      time.Time     `json:"end,omitempty"`
+	Step     time.Duration `json:"step,omitempty"`
+	Now      *time.Time    `json:"now,omitempty"`
+}
+
+var _ flux.Compiler = &Compiler{}
+
+// Evaluation returns the evaluation of the query.
+// An instant query without a start is evaluated now.
+func (c *Compiler) Evaluation() Evaluation {
+	eval := Evaluation{
+		Bucket:   c.Bucket,
+		BucketID: c.BucketID,
+		Start:    c.Start,
+		End:      c.End,
+		Step:     c.Step,
+	}
+	if eval.Start.IsZero() && !eval.IsRange() {
+		if c.Now != nil {
+			eval.Start = *c.Now
+		} else {
+			eval.Start = time.Now()
+		}
+	}
+	return eval
+}
+
+// Compile builds the query specification and plans it into a Program.
+func (c *Compiler) Compile(ctx context.Context) (flux.Program, error) {
+	eval := c.Evaluation()
+	spec, err := BuildEvaluation(c.Query, eval)
+	if err != nil {
+		return nil, err
+	}
+	spec.Now = eval.Start
+
+	ps, err := plan.PlannerBuilder{}.Build().Plan(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &lang.Program{
+		PlanSpec: ps,
+	}, nil
+}
+
+func (c *Compiler) CompilerType() flux.CompilerType {
+	return CompilerType
+}
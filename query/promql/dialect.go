@@ -0,0 +1,35 @@
+package promql
+
+import (
+	"net/http"
+
+	"github.com/influxdata/flux"
+)
+
+const DialectType = "promql"
+
+// AddDialectMappings adds the promql specific dialect mappings.
+func AddDialectMappings(mappings flux.DialectMappings) error {
+	return mappings.Add(DialectType, func() flux.Dialect {
+		return new(Dialect)
+	})
+}
+
+// Dialect describes the output format of PromQL queries.
+type Dialect struct {
+	ResultType ResultType // ResultType is the Prometheus type of the results; defaults to vector.
+}
+
+func (d *Dialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+}
+
+func (d *Dialect) Encoder() flux.MultiResultEncoder {
+	return &MultiResultEncoder{
+		ResultType: d.ResultType,
+	}
+}
+
+func (d *Dialect) DialectType() flux.DialectType {
+	return DialectType
+}
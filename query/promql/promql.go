@@ -48,17 +48,17 @@ var g = &grammar{
 									},
 									&ruleRefExpr{
 										pos:  position{line: 13, col: 32, offset: 331},
-										name: "AggregateExpression",
-									},
-									&ruleRefExpr{
-										pos:  position{line: 13, col: 54, offset: 353},
-										name: "VectorSelector",
+										name: "Expression",
 									},
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 13, col: 71, offset: 370},
+							pos:  position{line: 13, col: 45, offset: 344},
+							name: "__",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 13, col: 48, offset: 347},
 							name: "EOF",
 						},
 					},
@@ -67,39 +67,39 @@ var g = &grammar{
 		},
 		{
 			name: "SourceChar",
-			pos:  position{line: 17, col: 1, offset: 403},
+			pos:  position{line: 17, col: 1, offset: 380},
 			expr: &anyMatcher{
-				line: 17, col: 14, offset: 416,
+				line: 17, col: 14, offset: 393,
 			},
 		},
 		{
 			name: "Comment",
-			pos:  position{line: 19, col: 1, offset: 419},
+			pos:  position{line: 19, col: 1, offset: 396},
 			expr: &actionExpr{
-				pos: position{line: 19, col: 11, offset: 429},
+				pos: position{line: 19, col: 11, offset: 406},
 				run: (*parser).callonComment1,
 				expr: &seqExpr{
-					pos: position{line: 19, col: 11, offset: 429},
+					pos: position{line: 19, col: 11, offset: 406},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 19, col: 11, offset: 429},
+							pos:        position{line: 19, col: 11, offset: 406},
 							val:        "#",
 							ignoreCase: false,
 						},
 						&zeroOrMoreExpr{
-							pos: position{line: 19, col: 15, offset: 433},
+							pos: position{line: 19, col: 15, offset: 410},
 							expr: &seqExpr{
-								pos: position{line: 19, col: 17, offset: 435},
+								pos: position{line: 19, col: 17, offset: 412},
 								exprs: []interface{}{
 									&notExpr{
-										pos: position{line: 19, col: 17, offset: 435},
+										pos: position{line: 19, col: 17, offset: 412},
 										expr: &ruleRefExpr{
-											pos:  position{line: 19, col: 18, offset: 436},
+											pos:  position{line: 19, col: 18, offset: 413},
 											name: "EOL",
 										},
 									},
 									&ruleRefExpr{
-										pos:  position{line: 19, col: 22, offset: 440},
+										pos:  position{line: 19, col: 22, offset: 417},
 										name: "SourceChar",
 									},
 								},
@@ -111,15 +111,15 @@ var g = &grammar{
 		},
 		{
 			name: "Identifier",
-			pos:  position{line: 23, col: 1, offset: 500},
+			pos:  position{line: 23, col: 1, offset: 477},
 			expr: &actionExpr{
-				pos: position{line: 23, col: 14, offset: 513},
+				pos: position{line: 23, col: 14, offset: 490},
 				run: (*parser).callonIdentifier1,
 				expr: &labeledExpr{
-					pos:   position{line: 23, col: 14, offset: 513},
+					pos:   position{line: 23, col: 14, offset: 490},
 					label: "ident",
 					expr: &ruleRefExpr{
-						pos:  position{line: 23, col: 20, offset: 519},
+						pos:  position{line: 23, col: 20, offset: 496},
 						name: "IdentifierName",
 					},
 				},
@@ -127,21 +127,21 @@ var g = &grammar{
 		},
 		{
 			name: "IdentifierName",
-			pos:  position{line: 30, col: 1, offset: 692},
+			pos:  position{line: 30, col: 1, offset: 669},
 			expr: &actionExpr{
-				pos: position{line: 30, col: 18, offset: 709},
+				pos: position{line: 30, col: 18, offset: 686},
 				run: (*parser).callonIdentifierName1,
 				expr: &seqExpr{
-					pos: position{line: 30, col: 18, offset: 709},
+					pos: position{line: 30, col: 18, offset: 686},
 					exprs: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 30, col: 18, offset: 709},
+							pos:  position{line: 30, col: 18, offset: 686},
 							name: "IdentifierStart",
 						},
 						&zeroOrMoreExpr{
-							pos: position{line: 30, col: 34, offset: 725},
+							pos: position{line: 30, col: 34, offset: 702},
 							expr: &ruleRefExpr{
-								pos:  position{line: 30, col: 34, offset: 725},
+								pos:  position{line: 30, col: 34, offset: 702},
 								name: "IdentifierPart",
 							},
 						},
@@ -151,9 +151,9 @@ var g = &grammar{
 		},
 		{
 			name: "IdentifierStart",
-			pos:  position{line: 33, col: 1, offset: 776},
+			pos:  position{line: 33, col: 1, offset: 753},
 			expr: &charClassMatcher{
-				pos:        position{line: 33, col: 19, offset: 794},
+				pos:        position{line: 33, col: 19, offset: 771},
 				val:        "[\\pL_]",
 				chars:      []rune{'_'},
 				classes:    []*unicode.RangeTable{rangeTable("L")},
@@ -163,16 +163,16 @@ var g = &grammar{
 		},
 		{
 			name: "IdentifierPart",
-			pos:  position{line: 34, col: 1, offset: 801},
+			pos:  position{line: 34, col: 1, offset: 778},
 			expr: &choiceExpr{
-				pos: position{line: 34, col: 18, offset: 818},
+				pos: position{line: 34, col: 18, offset: 795},
 				alternatives: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 34, col: 18, offset: 818},
+						pos:  position{line: 34, col: 18, offset: 795},
 						name: "IdentifierStart",
 					},
 					&charClassMatcher{
-						pos:        position{line: 34, col: 36, offset: 836},
+						pos:        position{line: 34, col: 36, offset: 813},
 						val:        "[\\p{Nd}]",
 						classes:    []*unicode.RangeTable{rangeTable("Nd")},
 						ignoreCase: false,
@@ -183,74 +183,74 @@ var g = &grammar{
 		},
 		{
 			name: "StringLiteral",
-			pos:  position{line: 36, col: 1, offset: 846},
+			pos:  position{line: 36, col: 1, offset: 823},
 			expr: &choiceExpr{
-				pos: position{line: 36, col: 17, offset: 862},
+				pos: position{line: 36, col: 17, offset: 839},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 36, col: 17, offset: 862},
+						pos: position{line: 36, col: 17, offset: 839},
 						run: (*parser).callonStringLiteral2,
 						expr: &choiceExpr{
-							pos: position{line: 36, col: 19, offset: 864},
+							pos: position{line: 36, col: 19, offset: 841},
 							alternatives: []interface{}{
 								&seqExpr{
-									pos: position{line: 36, col: 19, offset: 864},
+									pos: position{line: 36, col: 19, offset: 841},
 									exprs: []interface{}{
 										&litMatcher{
-											pos:        position{line: 36, col: 19, offset: 864},
+											pos:        position{line: 36, col: 19, offset: 841},
 											val:        "\"",
 											ignoreCase: false,
 										},
 										&zeroOrMoreExpr{
-											pos: position{line: 36, col: 23, offset: 868},
+											pos: position{line: 36, col: 23, offset: 845},
 											expr: &ruleRefExpr{
-												pos:  position{line: 36, col: 23, offset: 868},
+												pos:  position{line: 36, col: 23, offset: 845},
 												name: "DoubleStringChar",
 											},
 										},
 										&litMatcher{
-											pos:        position{line: 36, col: 41, offset: 886},
+											pos:        position{line: 36, col: 41, offset: 863},
 											val:        "\"",
 											ignoreCase: false,
 										},
 									},
 								},
 								&seqExpr{
-									pos: position{line: 36, col: 47, offset: 892},
+									pos: position{line: 36, col: 47, offset: 869},
 									exprs: []interface{}{
 										&litMatcher{
-											pos:        position{line: 36, col: 47, offset: 892},
+											pos:        position{line: 36, col: 47, offset: 869},
 											val:        "'",
 											ignoreCase: false,
 										},
 										&ruleRefExpr{
-											pos:  position{line: 36, col: 51, offset: 896},
+											pos:  position{line: 36, col: 51, offset: 873},
 											name: "SingleStringChar",
 										},
 										&litMatcher{
-											pos:        position{line: 36, col: 68, offset: 913},
+											pos:        position{line: 36, col: 68, offset: 890},
 											val:        "'",
 											ignoreCase: false,
 										},
 									},
 								},
 								&seqExpr{
-									pos: position{line: 36, col: 74, offset: 919},
+									pos: position{line: 36, col: 74, offset: 896},
 									exprs: []interface{}{
 										&litMatcher{
-											pos:        position{line: 36, col: 74, offset: 919},
+											pos:        position{line: 36, col: 74, offset: 896},
 											val:        "`",
 											ignoreCase: false,
 										},
 										&zeroOrMoreExpr{
-											pos: position{line: 36, col: 78, offset: 923},
+											pos: position{line: 36, col: 78, offset: 900},
 											expr: &ruleRefExpr{
-												pos:  position{line: 36, col: 78, offset: 923},
+												pos:  position{line: 36, col: 78, offset: 900},
 												name: "RawStringChar",
 											},
 										},
 										&litMatcher{
-											pos:        position{line: 36, col: 93, offset: 938},
+											pos:        position{line: 36, col: 93, offset: 915},
 											val:        "`",
 											ignoreCase: false,
 										},
@@ -260,35 +260,35 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 42, col: 5, offset: 1084},
+						pos: position{line: 42, col: 5, offset: 1061},
 						run: (*parser).callonStringLiteral18,
 						expr: &choiceExpr{
-							pos: position{line: 42, col: 7, offset: 1086},
+							pos: position{line: 42, col: 7, offset: 1063},
 							alternatives: []interface{}{
 								&seqExpr{
-									pos: position{line: 42, col: 9, offset: 1088},
+									pos: position{line: 42, col: 9, offset: 1065},
 									exprs: []interface{}{
 										&litMatcher{
-											pos:        position{line: 42, col: 9, offset: 1088},
+											pos:        position{line: 42, col: 9, offset: 1065},
 											val:        "\"",
 											ignoreCase: false,
 										},
 										&zeroOrMoreExpr{
-											pos: position{line: 42, col: 13, offset: 1092},
+											pos: position{line: 42, col: 13, offset: 1069},
 											expr: &ruleRefExpr{
-												pos:  position{line: 42, col: 13, offset: 1092},
+												pos:  position{line: 42, col: 13, offset: 1069},
 												name: "DoubleStringChar",
 											},
 										},
 										&choiceExpr{
-											pos: position{line: 42, col: 33, offset: 1112},
+											pos: position{line: 42, col: 33, offset: 1089},
 											alternatives: []interface{}{
 												&ruleRefExpr{
-													pos:  position{line: 42, col: 33, offset: 1112},
+													pos:  position{line: 42, col: 33, offset: 1089},
 													name: "EOL",
 												},
 												&ruleRefExpr{
-													pos:  position{line: 42, col: 39, offset: 1118},
+													pos:  position{line: 42, col: 39, offset: 1095},
 													name: "EOF",
 												},
 											},
@@ -296,29 +296,29 @@ var g = &grammar{
 									},
 								},
 								&seqExpr{
-									pos: position{line: 42, col: 51, offset: 1130},
+									pos: position{line: 42, col: 51, offset: 1107},
 									exprs: []interface{}{
 										&litMatcher{
-											pos:        position{line: 42, col: 51, offset: 1130},
+											pos:        position{line: 42, col: 51, offset: 1107},
 											val:        "'",
 											ignoreCase: false,
 										},
 										&zeroOrOneExpr{
-											pos: position{line: 42, col: 55, offset: 1134},
+											pos: position{line: 42, col: 55, offset: 1111},
 											expr: &ruleRefExpr{
-												pos:  position{line: 42, col: 55, offset: 1134},
+												pos:  position{line: 42, col: 55, offset: 1111},
 												name: "SingleStringChar",
 											},
 										},
 										&choiceExpr{
-											pos: position{line: 42, col: 75, offset: 1154},
+											pos: position{line: 42, col: 75, offset: 1131},
 											alternatives: []interface{}{
 												&ruleRefExpr{
-													pos:  position{line: 42, col: 75, offset: 1154},
+													pos:  position{line: 42, col: 75, offset: 1131},
 													name: "EOL",
 												},
 												&ruleRefExpr{
-													pos:  position{line: 42, col: 81, offset: 1160},
+													pos:  position{line: 42, col: 81, offset: 1137},
 													name: "EOF",
 												},
 											},
@@ -326,22 +326,22 @@ var g = &grammar{
 									},
 								},
 								&seqExpr{
-									pos: position{line: 42, col: 91, offset: 1170},
+									pos: position{line: 42, col: 91, offset: 1147},
 									exprs: []interface{}{
 										&litMatcher{
-											pos:        position{line: 42, col: 91, offset: 1170},
+											pos:        position{line: 42, col: 91, offset: 1147},
 											val:        "`",
 											ignoreCase: false,
 										},
 										&zeroOrMoreExpr{
-											pos: position{line: 42, col: 95, offset: 1174},
+											pos: position{line: 42, col: 95, offset: 1151},
 											expr: &ruleRefExpr{
-												pos:  position{line: 42, col: 95, offset: 1174},
+												pos:  position{line: 42, col: 95, offset: 1151},
 												name: "RawStringChar",
 											},
 										},
 										&ruleRefExpr{
-											pos:  position{line: 42, col: 110, offset: 1189},
+											pos:  position{line: 42, col: 110, offset: 1166},
 											name: "EOF",
 										},
 									},
@@ -354,51 +354,51 @@ var g = &grammar{
 		},
 		{
 			name: "DoubleStringChar",
-			pos:  position{line: 46, col: 1, offset: 1260},
+			pos:  position{line: 46, col: 1, offset: 1237},
 			expr: &choiceExpr{
-				pos: position{line: 46, col: 20, offset: 1279},
+				pos: position{line: 46, col: 20, offset: 1256},
 				alternatives: []interface{}{
 					&seqExpr{
-						pos: position{line: 46, col: 20, offset: 1279},
+						pos: position{line: 46, col: 20, offset: 1256},
 						exprs: []interface{}{
 							&notExpr{
-								pos: position{line: 46, col: 20, offset: 1279},
+								pos: position{line: 46, col: 20, offset: 1256},
 								expr: &choiceExpr{
-									pos: position{line: 46, col: 23, offset: 1282},
+									pos: position{line: 46, col: 23, offset: 1259},
 									alternatives: []interface{}{
 										&litMatcher{
-											pos:        position{line: 46, col: 23, offset: 1282},
+											pos:        position{line: 46, col: 23, offset: 1259},
 											val:        "\"",
 											ignoreCase: false,
 										},
 										&litMatcher{
-											pos:        position{line: 46, col: 29, offset: 1288},
+											pos:        position{line: 46, col: 29, offset: 1265},
 											val:        "\\",
 											ignoreCase: false,
 										},
 										&ruleRefExpr{
-											pos:  position{line: 46, col: 36, offset: 1295},
+											pos:  position{line: 46, col: 36, offset: 1272},
 											name: "EOL",
 										},
 									},
 								},
 							},
 							&ruleRefExpr{
-								pos:  position{line: 46, col: 42, offset: 1301},
+								pos:  position{line: 46, col: 42, offset: 1278},
 								name: "SourceChar",
 							},
 						},
 					},
 					&seqExpr{
-						pos: position{line: 46, col: 55, offset: 1314},
+						pos: position{line: 46, col: 55, offset: 1291},
 						exprs: []interface{}{
 							&litMatcher{
-								pos:        position{line: 46, col: 55, offset: 1314},
+								pos:        position{line: 46, col: 55, offset: 1291},
 								val:        "\\",
 								ignoreCase: false,
 							},
 							&ruleRefExpr{
-								pos:  position{line: 46, col: 60, offset: 1319},
+								pos:  position{line: 46, col: 60, offset: 1296},
 								name: "DoubleStringEscape",
 							},
 						},
@@ -408,51 +408,51 @@ var g = &grammar{
 		},
 		{
 			name: "SingleStringChar",
-			pos:  position{line: 47, col: 1, offset: 1338},
+			pos:  position{line: 47, col: 1, offset: 1315},
 			expr: &choiceExpr{
-				pos: position{line: 47, col: 20, offset: 1357},
+				pos: position{line: 47, col: 20, offset: 1334},
 				alternatives: []interface{}{
 					&seqExpr{
-						pos: position{line: 47, col: 20, offset: 1357},
+						pos: position{line: 47, col: 20, offset: 1334},
 						exprs: []interface{}{
 							&notExpr{
-								pos: position{line: 47, col: 20, offset: 1357},
+								pos: position{line: 47, col: 20, offset: 1334},
 								expr: &choiceExpr{
-									pos: position{line: 47, col: 23, offset: 1360},
+									pos: position{line: 47, col: 23, offset: 1337},
 									alternatives: []interface{}{
 										&litMatcher{
-											pos:        position{line: 47, col: 23, offset: 1360},
+											pos:        position{line: 47, col: 23, offset: 1337},
 											val:        "'",
 											ignoreCase: false,
 										},
 										&litMatcher{
-											pos:        position{line: 47, col: 29, offset: 1366},
+											pos:        position{line: 47, col: 29, offset: 1343},
 											val:        "\\",
 											ignoreCase: false,
 										},
 										&ruleRefExpr{
-											pos:  position{line: 47, col: 36, offset: 1373},
+											pos:  position{line: 47, col: 36, offset: 1350},
 											name: "EOL",
 										},
 									},
 								},
 							},
 							&ruleRefExpr{
-								pos:  position{line: 47, col: 42, offset: 1379},
+								pos:  position{line: 47, col: 42, offset: 1356},
 								name: "SourceChar",
 							},
 						},
 					},
 					&seqExpr{
-						pos: position{line: 47, col: 55, offset: 1392},
+						pos: position{line: 47, col: 55, offset: 1369},
 						exprs: []interface{}{
 							&litMatcher{
-								pos:        position{line: 47, col: 55, offset: 1392},
+								pos:        position{line: 47, col: 55, offset: 1369},
 								val:        "\\",
 								ignoreCase: false,
 							},
 							&ruleRefExpr{
-								pos:  position{line: 47, col: 60, offset: 1397},
+								pos:  position{line: 47, col: 60, offset: 1374},
 								name: "SingleStringEscape",
 							},
 						},
@@ -462,20 +462,20 @@ var g = &grammar{
 		},
 		{
 			name: "RawStringChar",
-			pos:  position{line: 48, col: 1, offset: 1416},
+			pos:  position{line: 48, col: 1, offset: 1393},
 			expr: &seqExpr{
-				pos: position{line: 48, col: 17, offset: 1432},
+				pos: position{line: 48, col: 17, offset: 1409},
 				exprs: []interface{}{
 					&notExpr{
-						pos: position{line: 48, col: 17, offset: 1432},
+						pos: position{line: 48, col: 17, offset: 1409},
 						expr: &litMatcher{
-							pos:        position{line: 48, col: 18, offset: 1433},
+							pos:        position{line: 48, col: 18, offset: 1410},
 							val:        "`",
 							ignoreCase: false,
 						},
 					},
 					&ruleRefExpr{
-						pos:  position{line: 48, col: 22, offset: 1437},
+						pos:  position{line: 48, col: 22, offset: 1414},
 						name: "SourceChar",
 					},
 				},
@@ -483,40 +483,40 @@ var g = &grammar{
 		},
 		{
 			name: "DoubleStringEscape",
-			pos:  position{line: 50, col: 1, offset: 1449},
+			pos:  position{line: 50, col: 1, offset: 1426},
 			expr: &choiceExpr{
-				pos: position{line: 50, col: 22, offset: 1470},
+				pos: position{line: 50, col: 22, offset: 1447},
 				alternatives: []interface{}{
 					&choiceExpr{
-						pos: position{line: 50, col: 24, offset: 1472},
+						pos: position{line: 50, col: 24, offset: 1449},
 						alternatives: []interface{}{
 							&litMatcher{
-								pos:        position{line: 50, col: 24, offset: 1472},
+								pos:        position{line: 50, col: 24, offset: 1449},
 								val:        "\"",
 								ignoreCase: false,
 							},
 							&ruleRefExpr{
-								pos:  position{line: 50, col: 30, offset: 1478},
+								pos:  position{line: 50, col: 30, offset: 1455},
 								name: "CommonEscapeSequence",
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 51, col: 7, offset: 1507},
+						pos: position{line: 51, col: 7, offset: 1484},
 						run: (*parser).callonDoubleStringEscape5,
 						expr: &choiceExpr{
-							pos: position{line: 51, col: 9, offset: 1509},
+							pos: position{line: 51, col: 9, offset: 1486},
 							alternatives: []interface{}{
 								&ruleRefExpr{
-									pos:  position{line: 51, col: 9, offset: 1509},
+									pos:  position{line: 51, col: 9, offset: 1486},
 									name: "SourceChar",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 51, col: 22, offset: 1522},
+									pos:  position{line: 51, col: 22, offset: 1499},
 									name: "EOL",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 51, col: 28, offset: 1528},
+									pos:  position{line: 51, col: 28, offset: 1505},
 									name: "EOF",
 								},
 							},
@@ -527,40 +527,40 @@ var g = &grammar{
 		},
 		{
 			name: "SingleStringEscape",
-			pos:  position{line: 54, col: 1, offset: 1593},
+			pos:  position{line: 54, col: 1, offset: 1570},
 			expr: &choiceExpr{
-				pos: position{line: 54, col: 22, offset: 1614},
+				pos: position{line: 54, col: 22, offset: 1591},
 				alternatives: []interface{}{
 					&choiceExpr{
-						pos: position{line: 54, col: 24, offset: 1616},
+						pos: position{line: 54, col: 24, offset: 1593},
 						alternatives: []interface{}{
 							&litMatcher{
-								pos:        position{line: 54, col: 24, offset: 1616},
+								pos:        position{line: 54, col: 24, offset: 1593},
 								val:        "'",
 								ignoreCase: false,
 							},
 							&ruleRefExpr{
-								pos:  position{line: 54, col: 30, offset: 1622},
+								pos:  position{line: 54, col: 30, offset: 1599},
 								name: "CommonEscapeSequence",
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 55, col: 7, offset: 1651},
+						pos: position{line: 55, col: 7, offset: 1628},
 						run: (*parser).callonSingleStringEscape5,
 						expr: &choiceExpr{
-							pos: position{line: 55, col: 9, offset: 1653},
+							pos: position{line: 55, col: 9, offset: 1630},
 							alternatives: []interface{}{
 								&ruleRefExpr{
-									pos:  position{line: 55, col: 9, offset: 1653},
+									pos:  position{line: 55, col: 9, offset: 1630},
 									name: "SourceChar",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 55, col: 22, offset: 1666},
+									pos:  position{line: 55, col: 22, offset: 1643},
 									name: "EOL",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 55, col: 28, offset: 1672},
+									pos:  position{line: 55, col: 28, offset: 1649},
 									name: "EOF",
 								},
 							},
@@ -571,28 +571,28 @@ var g = &grammar{
 		},
 		{
 			name: "CommonEscapeSequence",
-			pos:  position{line: 59, col: 1, offset: 1738},
+			pos:  position{line: 59, col: 1, offset: 1715},
 			expr: &choiceExpr{
-				pos: position{line: 59, col: 24, offset: 1761},
+				pos: position{line: 59, col: 24, offset: 1738},
 				alternatives: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 59, col: 24, offset: 1761},
+						pos:  position{line: 59, col: 24, offset: 1738},
 						name: "SingleCharEscape",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 59, col: 43, offset: 1780},
+						pos:  position{line: 59, col: 43, offset: 1757},
 						name: "OctalEscape",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 59, col: 57, offset: 1794},
+						pos:  position{line: 59, col: 57, offset: 1771},
 						name: "HexEscape",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 59, col: 69, offset: 1806},
+						pos:  position{line: 59, col: 69, offset: 1783},
 						name: "LongUnicodeEscape",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 59, col: 89, offset: 1826},
+						pos:  position{line: 59, col: 89, offset: 1803},
 						name: "ShortUnicodeEscape",
 					},
 				},
@@ -600,47 +600,47 @@ var g = &grammar{
 		},
 		{
 			name: "SingleCharEscape",
-			pos:  position{line: 60, col: 1, offset: 1845},
+			pos:  position{line: 60, col: 1, offset: 1822},
 			expr: &choiceExpr{
-				pos: position{line: 60, col: 20, offset: 1864},
+				pos: position{line: 60, col: 20, offset: 1841},
 				alternatives: []interface{}{
 					&litMatcher{
-						pos:        position{line: 60, col: 20, offset: 1864},
+						pos:        position{line: 60, col: 20, offset: 1841},
 						val:        "a",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 60, col: 26, offset: 1870},
+						pos:        position{line: 60, col: 26, offset: 1847},
 						val:        "b",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 60, col: 32, offset: 1876},
+						pos:        position{line: 60, col: 32, offset: 1853},
 						val:        "n",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 60, col: 38, offset: 1882},
+						pos:        position{line: 60, col: 38, offset: 1859},
 						val:        "f",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 60, col: 44, offset: 1888},
+						pos:        position{line: 60, col: 44, offset: 1865},
 						val:        "r",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 60, col: 50, offset: 1894},
+						pos:        position{line: 60, col: 50, offset: 1871},
 						val:        "t",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 60, col: 56, offset: 1900},
+						pos:        position{line: 60, col: 56, offset: 1877},
 						val:        "v",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 60, col: 62, offset: 1906},
+						pos:        position{line: 60, col: 62, offset: 1883},
 						val:        "\\",
 						ignoreCase: false,
 					},
@@ -649,50 +649,50 @@ var g = &grammar{
 		},
 		{
 			name: "OctalEscape",
-			pos:  position{line: 61, col: 1, offset: 1911},
+			pos:  position{line: 61, col: 1, offset: 1888},
 			expr: &choiceExpr{
-				pos: position{line: 61, col: 15, offset: 1925},
+				pos: position{line: 61, col: 15, offset: 1902},
 				alternatives: []interface{}{
 					&seqExpr{
-						pos: position{line: 61, col: 15, offset: 1925},
+						pos: position{line: 61, col: 15, offset: 1902},
 						exprs: []interface{}{
 							&ruleRefExpr{
-								pos:  position{line: 61, col: 15, offset: 1925},
+								pos:  position{line: 61, col: 15, offset: 1902},
 								name: "OctalDigit",
 							},
 							&ruleRefExpr{
-								pos:  position{line: 61, col: 26, offset: 1936},
+								pos:  position{line: 61, col: 26, offset: 1913},
 								name: "OctalDigit",
 							},
 							&ruleRefExpr{
-								pos:  position{line: 61, col: 37, offset: 1947},
+								pos:  position{line: 61, col: 37, offset: 1924},
 								name: "OctalDigit",
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 62, col: 7, offset: 1964},
+						pos: position{line: 62, col: 7, offset: 1941},
 						run: (*parser).callonOctalEscape6,
 						expr: &seqExpr{
-							pos: position{line: 62, col: 7, offset: 1964},
+							pos: position{line: 62, col: 7, offset: 1941},
 							exprs: []interface{}{
 								&ruleRefExpr{
-									pos:  position{line: 62, col: 7, offset: 1964},
+									pos:  position{line: 62, col: 7, offset: 1941},
 									name: "OctalDigit",
 								},
 								&choiceExpr{
-									pos: position{line: 62, col: 20, offset: 1977},
+									pos: position{line: 62, col: 20, offset: 1954},
 									alternatives: []interface{}{
 										&ruleRefExpr{
-											pos:  position{line: 62, col: 20, offset: 1977},
+											pos:  position{line: 62, col: 20, offset: 1954},
 											name: "SourceChar",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 62, col: 33, offset: 1990},
+											pos:  position{line: 62, col: 33, offset: 1967},
 											name: "EOL",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 62, col: 39, offset: 1996},
+											pos:  position{line: 62, col: 39, offset: 1973},
 											name: "EOF",
 										},
 									},
@@ -705,52 +705,52 @@ var g = &grammar{
 		},
 		{
 			name: "HexEscape",
-			pos:  position{line: 65, col: 1, offset: 2057},
+			pos:  position{line: 65, col: 1, offset: 2034},
 			expr: &choiceExpr{
-				pos: position{line: 65, col: 13, offset: 2069},
+				pos: position{line: 65, col: 13, offset: 2046},
 				alternatives: []interface{}{
 					&seqExpr{
-						pos: position{line: 65, col: 13, offset: 2069},
+						pos: position{line: 65, col: 13, offset: 2046},
 						exprs: []interface{}{
 							&litMatcher{
-								pos:        position{line: 65, col: 13, offset: 2069},
+								pos:        position{line: 65, col: 13, offset: 2046},
 								val:        "x",
 								ignoreCase: false,
 							},
 							&ruleRefExpr{
-								pos:  position{line: 65, col: 17, offset: 2073},
+								pos:  position{line: 65, col: 17, offset: 2050},
 								name: "HexDigit",
 							},
 							&ruleRefExpr{
-								pos:  position{line: 65, col: 26, offset: 2082},
+								pos:  position{line: 65, col: 26, offset: 2059},
 								name: "HexDigit",
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 66, col: 7, offset: 2097},
+						pos: position{line: 66, col: 7, offset: 2074},
 						run: (*parser).callonHexEscape6,
 						expr: &seqExpr{
-							pos: position{line: 66, col: 7, offset: 2097},
+							pos: position{line: 66, col: 7, offset: 2074},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 66, col: 7, offset: 2097},
+									pos:        position{line: 66, col: 7, offset: 2074},
 									val:        "x",
 									ignoreCase: false,
 								},
 								&choiceExpr{
-									pos: position{line: 66, col: 13, offset: 2103},
+									pos: position{line: 66, col: 13, offset: 2080},
 									alternatives: []interface{}{
 										&ruleRefExpr{
-											pos:  position{line: 66, col: 13, offset: 2103},
+											pos:  position{line: 66, col: 13, offset: 2080},
 											name: "SourceChar",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 66, col: 26, offset: 2116},
+											pos:  position{line: 66, col: 26, offset: 2093},
 											name: "EOL",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 66, col: 32, offset: 2122},
+											pos:  position{line: 66, col: 32, offset: 2099},
 											name: "EOF",
 										},
 									},
@@ -763,80 +763,80 @@ var g = &grammar{
 		},
 		{
 			name: "LongUnicodeEscape",
-			pos:  position{line: 69, col: 1, offset: 2189},
+			pos:  position{line: 69, col: 1, offset: 2166},
 			expr: &choiceExpr{
-				pos: position{line: 70, col: 5, offset: 2214},
+				pos: position{line: 70, col: 5, offset: 2191},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 70, col: 5, offset: 2214},
+						pos: position{line: 70, col: 5, offset: 2191},
 						run: (*parser).callonLongUnicodeEscape2,
 						expr: &seqExpr{
-							pos: position{line: 70, col: 5, offset: 2214},
+							pos: position{line: 70, col: 5, offset: 2191},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 70, col: 5, offset: 2214},
+									pos:        position{line: 70, col: 5, offset: 2191},
 									val:        "U",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 70, col: 9, offset: 2218},
+									pos:  position{line: 70, col: 9, offset: 2195},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 70, col: 18, offset: 2227},
+									pos:  position{line: 70, col: 18, offset: 2204},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 70, col: 27, offset: 2236},
+									pos:  position{line: 70, col: 27, offset: 2213},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 70, col: 36, offset: 2245},
+									pos:  position{line: 70, col: 36, offset: 2222},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 70, col: 45, offset: 2254},
+									pos:  position{line: 70, col: 45, offset: 2231},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 70, col: 54, offset: 2263},
+									pos:  position{line: 70, col: 54, offset: 2240},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 70, col: 63, offset: 2272},
+									pos:  position{line: 70, col: 63, offset: 2249},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 70, col: 72, offset: 2281},
+									pos:  position{line: 70, col: 72, offset: 2258},
 									name: "HexDigit",
 								},
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 73, col: 7, offset: 2383},
+						pos: position{line: 73, col: 7, offset: 2360},
 						run: (*parser).callonLongUnicodeEscape13,
 						expr: &seqExpr{
-							pos: position{line: 73, col: 7, offset: 2383},
+							pos: position{line: 73, col: 7, offset: 2360},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 73, col: 7, offset: 2383},
+									pos:        position{line: 73, col: 7, offset: 2360},
 									val:        "U",
 									ignoreCase: false,
 								},
 								&choiceExpr{
-									pos: position{line: 73, col: 13, offset: 2389},
+									pos: position{line: 73, col: 13, offset: 2366},
 									alternatives: []interface{}{
 										&ruleRefExpr{
-											pos:  position{line: 73, col: 13, offset: 2389},
+											pos:  position{line: 73, col: 13, offset: 2366},
 											name: "SourceChar",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 73, col: 26, offset: 2402},
+											pos:  position{line: 73, col: 26, offset: 2379},
 											name: "EOL",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 73, col: 32, offset: 2408},
+											pos:  position{line: 73, col: 32, offset: 2385},
 											name: "EOF",
 										},
 									},
@@ -849,64 +849,64 @@ var g = &grammar{
 		},
 		{
 			name: "ShortUnicodeEscape",
-			pos:  position{line: 76, col: 1, offset: 2471},
+			pos:  position{line: 76, col: 1, offset: 2448},
 			expr: &choiceExpr{
-				pos: position{line: 77, col: 5, offset: 2497},
+				pos: position{line: 77, col: 5, offset: 2474},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 77, col: 5, offset: 2497},
+						pos: position{line: 77, col: 5, offset: 2474},
 						run: (*parser).callonShortUnicodeEscape2,
 						expr: &seqExpr{
-							pos: position{line: 77, col: 5, offset: 2497},
+							pos: position{line: 77, col: 5, offset: 2474},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 77, col: 5, offset: 2497},
+									pos:        position{line: 77, col: 5, offset: 2474},
 									val:        "u",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 77, col: 9, offset: 2501},
+									pos:  position{line: 77, col: 9, offset: 2478},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 77, col: 18, offset: 2510},
+									pos:  position{line: 77, col: 18, offset: 2487},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 77, col: 27, offset: 2519},
+									pos:  position{line: 77, col: 27, offset: 2496},
 									name: "HexDigit",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 77, col: 36, offset: 2528},
+									pos:  position{line: 77, col: 36, offset: 2505},
 									name: "HexDigit",
 								},
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 80, col: 7, offset: 2630},
+						pos: position{line: 80, col: 7, offset: 2607},
 						run: (*parser).callonShortUnicodeEscape9,
 						expr: &seqExpr{
-							pos: position{line: 80, col: 7, offset: 2630},
+							pos: position{line: 80, col: 7, offset: 2607},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 80, col: 7, offset: 2630},
+									pos:        position{line: 80, col: 7, offset: 2607},
 									val:        "u",
 									ignoreCase: false,
 								},
 								&choiceExpr{
-									pos: position{line: 80, col: 13, offset: 2636},
+									pos: position{line: 80, col: 13, offset: 2613},
 									alternatives: []interface{}{
 										&ruleRefExpr{
-											pos:  position{line: 80, col: 13, offset: 2636},
+											pos:  position{line: 80, col: 13, offset: 2613},
 											name: "SourceChar",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 80, col: 26, offset: 2649},
+											pos:  position{line: 80, col: 26, offset: 2626},
 											name: "EOL",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 80, col: 32, offset: 2655},
+											pos:  position{line: 80, col: 32, offset: 2632},
 											name: "EOF",
 										},
 									},
@@ -919,9 +919,9 @@ var g = &grammar{
 		},
 		{
 			name: "OctalDigit",
-			pos:  position{line: 84, col: 1, offset: 2719},
+			pos:  position{line: 84, col: 1, offset: 2696},
 			expr: &charClassMatcher{
-				pos:        position{line: 84, col: 14, offset: 2732},
+				pos:        position{line: 84, col: 14, offset: 2709},
 				val:        "[0-7]",
 				ranges:     []rune{'0', '7'},
 				ignoreCase: false,
@@ -930,9 +930,9 @@ var g = &grammar{
 		},
 		{
 			name: "DecimalDigit",
-			pos:  position{line: 85, col: 1, offset: 2738},
+			pos:  position{line: 85, col: 1, offset: 2715},
 			expr: &charClassMatcher{
-				pos:        position{line: 85, col: 16, offset: 2753},
+				pos:        position{line: 85, col: 16, offset: 2730},
 				val:        "[0-9]",
 				ranges:     []rune{'0', '9'},
 				ignoreCase: false,
@@ -941,9 +941,9 @@ var g = &grammar{
 		},
 		{
 			name: "HexDigit",
-			pos:  position{line: 86, col: 1, offset: 2759},
+			pos:  position{line: 86, col: 1, offset: 2736},
 			expr: &charClassMatcher{
-				pos:        position{line: 86, col: 12, offset: 2770},
+				pos:        position{line: 86, col: 12, offset: 2747},
 				val:        "[0-9a-f]i",
 				ranges:     []rune{'0', '9', 'a', 'f'},
 				ignoreCase: true,
@@ -952,44 +952,44 @@ var g = &grammar{
 		},
 		{
 			name: "CharClassMatcher",
-			pos:  position{line: 88, col: 1, offset: 2781},
+			pos:  position{line: 88, col: 1, offset: 2758},
 			expr: &choiceExpr{
-				pos: position{line: 88, col: 20, offset: 2800},
+				pos: position{line: 88, col: 20, offset: 2777},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 88, col: 20, offset: 2800},
+						pos: position{line: 88, col: 20, offset: 2777},
 						run: (*parser).callonCharClassMatcher2,
 						expr: &seqExpr{
-							pos: position{line: 88, col: 20, offset: 2800},
+							pos: position{line: 88, col: 20, offset: 2777},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 88, col: 20, offset: 2800},
+									pos:        position{line: 88, col: 20, offset: 2777},
 									val:        "[",
 									ignoreCase: false,
 								},
 								&zeroOrMoreExpr{
-									pos: position{line: 88, col: 24, offset: 2804},
+									pos: position{line: 88, col: 24, offset: 2781},
 									expr: &choiceExpr{
-										pos: position{line: 88, col: 26, offset: 2806},
+										pos: position{line: 88, col: 26, offset: 2783},
 										alternatives: []interface{}{
 											&ruleRefExpr{
-												pos:  position{line: 88, col: 26, offset: 2806},
+												pos:  position{line: 88, col: 26, offset: 2783},
 												name: "ClassCharRange",
 											},
 											&ruleRefExpr{
-												pos:  position{line: 88, col: 43, offset: 2823},
+												pos:  position{line: 88, col: 43, offset: 2800},
 												name: "ClassChar",
 											},
 											&seqExpr{
-												pos: position{line: 88, col: 55, offset: 2835},
+												pos: position{line: 88, col: 55, offset: 2812},
 												exprs: []interface{}{
 													&litMatcher{
-														pos:        position{line: 88, col: 55, offset: 2835},
+														pos:        position{line: 88, col: 55, offset: 2812},
 														val:        "\\",
 														ignoreCase: false,
 													},
 													&ruleRefExpr{
-														pos:  position{line: 88, col: 60, offset: 2840},
+														pos:  position{line: 88, col: 60, offset: 2817},
 														name: "UnicodeClassEscape",
 													},
 												},
@@ -998,14 +998,14 @@ var g = &grammar{
 									},
 								},
 								&litMatcher{
-									pos:        position{line: 88, col: 82, offset: 2862},
+									pos:        position{line: 88, col: 82, offset: 2839},
 									val:        "]",
 									ignoreCase: false,
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 88, col: 86, offset: 2866},
+									pos: position{line: 88, col: 86, offset: 2843},
 									expr: &litMatcher{
-										pos:        position{line: 88, col: 86, offset: 2866},
+										pos:        position{line: 88, col: 86, offset: 2843},
 										val:        "i",
 										ignoreCase: false,
 									},
@@ -1014,44 +1014,44 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 90, col: 5, offset: 2908},
+						pos: position{line: 90, col: 5, offset: 2885},
 						run: (*parser).callonCharClassMatcher15,
 						expr: &seqExpr{
-							pos: position{line: 90, col: 5, offset: 2908},
+							pos: position{line: 90, col: 5, offset: 2885},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 90, col: 5, offset: 2908},
+									pos:        position{line: 90, col: 5, offset: 2885},
 									val:        "[",
 									ignoreCase: false,
 								},
 								&zeroOrMoreExpr{
-									pos: position{line: 90, col: 9, offset: 2912},
+									pos: position{line: 90, col: 9, offset: 2889},
 									expr: &seqExpr{
-										pos: position{line: 90, col: 11, offset: 2914},
+										pos: position{line: 90, col: 11, offset: 2891},
 										exprs: []interface{}{
 											&notExpr{
-												pos: position{line: 90, col: 11, offset: 2914},
+												pos: position{line: 90, col: 11, offset: 2891},
 												expr: &ruleRefExpr{
-													pos:  position{line: 90, col: 14, offset: 2917},
+													pos:  position{line: 90, col: 14, offset: 2894},
 													name: "EOL",
 												},
 											},
 											&ruleRefExpr{
-												pos:  position{line: 90, col: 20, offset: 2923},
+												pos:  position{line: 90, col: 20, offset: 2900},
 												name: "SourceChar",
 											},
 										},
 									},
 								},
 								&choiceExpr{
-									pos: position{line: 90, col: 36, offset: 2939},
+									pos: position{line: 90, col: 36, offset: 2916},
 									alternatives: []interface{}{
 										&ruleRefExpr{
-											pos:  position{line: 90, col: 36, offset: 2939},
+											pos:  position{line: 90, col: 36, offset: 2916},
 											name: "EOL",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 90, col: 42, offset: 2945},
+											pos:  position{line: 90, col: 42, offset: 2922},
 											name: "EOF",
 										},
 									},
@@ -1064,21 +1064,21 @@ var g = &grammar{
 		},
 		{
 			name: "ClassCharRange",
-			pos:  position{line: 94, col: 1, offset: 3017},
+			pos:  position{line: 94, col: 1, offset: 2994},
 			expr: &seqExpr{
-				pos: position{line: 94, col: 18, offset: 3034},
+				pos: position{line: 94, col: 18, offset: 3011},
 				exprs: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 94, col: 18, offset: 3034},
+						pos:  position{line: 94, col: 18, offset: 3011},
 						name: "ClassChar",
 					},
 					&litMatcher{
-						pos:        position{line: 94, col: 28, offset: 3044},
+						pos:        position{line: 94, col: 28, offset: 3021},
 						val:        "-",
 						ignoreCase: false,
 					},
 					&ruleRefExpr{
-						pos:  position{line: 94, col: 32, offset: 3048},
+						pos:  position{line: 94, col: 32, offset: 3025},
 						name: "ClassChar",
 					},
 				},
@@ -1086,51 +1086,51 @@ var g = &grammar{
 		},
 		{
 			name: "ClassChar",
-			pos:  position{line: 95, col: 1, offset: 3058},
+			pos:  position{line: 95, col: 1, offset: 3035},
 			expr: &choiceExpr{
-				pos: position{line: 95, col: 13, offset: 3070},
+				pos: position{line: 95, col: 13, offset: 3047},
 				alternatives: []interface{}{
 					&seqExpr{
-						pos: position{line: 95, col: 13, offset: 3070},
+						pos: position{line: 95, col: 13, offset: 3047},
 						exprs: []interface{}{
 							&notExpr{
-								pos: position{line: 95, col: 13, offset: 3070},
+								pos: position{line: 95, col: 13, offset: 3047},
 								expr: &choiceExpr{
-									pos: position{line: 95, col: 16, offset: 3073},
+									pos: position{line: 95, col: 16, offset: 3050},
 									alternatives: []interface{}{
 										&litMatcher{
-											pos:        position{line: 95, col: 16, offset: 3073},
+											pos:        position{line: 95, col: 16, offset: 3050},
 											val:        "]",
 											ignoreCase: false,
 										},
 										&litMatcher{
-											pos:        position{line: 95, col: 22, offset: 3079},
+											pos:        position{line: 95, col: 22, offset: 3056},
 											val:        "\\",
 											ignoreCase: false,
 										},
 										&ruleRefExpr{
-											pos:  position{line: 95, col: 29, offset: 3086},
+											pos:  position{line: 95, col: 29, offset: 3063},
 											name: "EOL",
 										},
 									},
 								},
 							},
 							&ruleRefExpr{
-								pos:  position{line: 95, col: 35, offset: 3092},
+								pos:  position{line: 95, col: 35, offset: 3069},
 								name: "SourceChar",
 							},
 						},
 					},
 					&seqExpr{
-						pos: position{line: 95, col: 48, offset: 3105},
+						pos: position{line: 95, col: 48, offset: 3082},
 						exprs: []interface{}{
 							&litMatcher{
-								pos:        position{line: 95, col: 48, offset: 3105},
+								pos:        position{line: 95, col: 48, offset: 3082},
 								val:        "\\",
 								ignoreCase: false,
 							},
 							&ruleRefExpr{
-								pos:  position{line: 95, col: 53, offset: 3110},
+								pos:  position{line: 95, col: 53, offset: 3087},
 								name: "CharClassEscape",
 							},
 						},
@@ -1140,51 +1140,51 @@ var g = &grammar{
 		},
 		{
 			name: "CharClassEscape",
-			pos:  position{line: 96, col: 1, offset: 3126},
+			pos:  position{line: 96, col: 1, offset: 3103},
 			expr: &choiceExpr{
-				pos: position{line: 96, col: 19, offset: 3144},
+				pos: position{line: 96, col: 19, offset: 3121},
 				alternatives: []interface{}{
 					&choiceExpr{
-						pos: position{line: 96, col: 21, offset: 3146},
+						pos: position{line: 96, col: 21, offset: 3123},
 						alternatives: []interface{}{
 							&litMatcher{
-								pos:        position{line: 96, col: 21, offset: 3146},
+								pos:        position{line: 96, col: 21, offset: 3123},
 								val:        "]",
 								ignoreCase: false,
 							},
 							&ruleRefExpr{
-								pos:  position{line: 96, col: 27, offset: 3152},
+								pos:  position{line: 96, col: 27, offset: 3129},
 								name: "CommonEscapeSequence",
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 97, col: 7, offset: 3181},
+						pos: position{line: 97, col: 7, offset: 3158},
 						run: (*parser).callonCharClassEscape5,
 						expr: &seqExpr{
-							pos: position{line: 97, col: 7, offset: 3181},
+							pos: position{line: 97, col: 7, offset: 3158},
 							exprs: []interface{}{
 								&notExpr{
-									pos: position{line: 97, col: 7, offset: 3181},
+									pos: position{line: 97, col: 7, offset: 3158},
 									expr: &litMatcher{
-										pos:        position{line: 97, col: 8, offset: 3182},
+										pos:        position{line: 97, col: 8, offset: 3159},
 										val:        "p",
 										ignoreCase: false,
 									},
 								},
 								&choiceExpr{
-									pos: position{line: 97, col: 14, offset: 3188},
+									pos: position{line: 97, col: 14, offset: 3165},
 									alternatives: []interface{}{
 										&ruleRefExpr{
-											pos:  position{line: 97, col: 14, offset: 3188},
+											pos:  position{line: 97, col: 14, offset: 3165},
 											name: "SourceChar",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 97, col: 27, offset: 3201},
+											pos:  position{line: 97, col: 27, offset: 3178},
 											name: "EOL",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 97, col: 33, offset: 3207},
+											pos:  position{line: 97, col: 33, offset: 3184},
 											name: "EOF",
 										},
 									},
@@ -1197,49 +1197,49 @@ var g = &grammar{
 		},
 		{
 			name: "UnicodeClassEscape",
-			pos:  position{line: 101, col: 1, offset: 3273},
+			pos:  position{line: 101, col: 1, offset: 3250},
 			expr: &seqExpr{
-				pos: position{line: 101, col: 22, offset: 3294},
+				pos: position{line: 101, col: 22, offset: 3271},
 				exprs: []interface{}{
 					&litMatcher{
-						pos:        position{line: 101, col: 22, offset: 3294},
+						pos:        position{line: 101, col: 22, offset: 3271},
 						val:        "p",
 						ignoreCase: false,
 					},
 					&choiceExpr{
-						pos: position{line: 102, col: 7, offset: 3307},
+						pos: position{line: 102, col: 7, offset: 3284},
 						alternatives: []interface{}{
 							&ruleRefExpr{
-								pos:  position{line: 102, col: 7, offset: 3307},
+								pos:  position{line: 102, col: 7, offset: 3284},
 								name: "SingleCharUnicodeClass",
 							},
 							&actionExpr{
-								pos: position{line: 103, col: 7, offset: 3336},
+								pos: position{line: 103, col: 7, offset: 3313},
 								run: (*parser).callonUnicodeClassEscape5,
 								expr: &seqExpr{
-									pos: position{line: 103, col: 7, offset: 3336},
+									pos: position{line: 103, col: 7, offset: 3313},
 									exprs: []interface{}{
 										&notExpr{
-											pos: position{line: 103, col: 7, offset: 3336},
+											pos: position{line: 103, col: 7, offset: 3313},
 											expr: &litMatcher{
-												pos:        position{line: 103, col: 8, offset: 3337},
+												pos:        position{line: 103, col: 8, offset: 3314},
 												val:        "{",
 												ignoreCase: false,
 											},
 										},
 										&choiceExpr{
-											pos: position{line: 103, col: 14, offset: 3343},
+											pos: position{line: 103, col: 14, offset: 3320},
 											alternatives: []interface{}{
 												&ruleRefExpr{
-													pos:  position{line: 103, col: 14, offset: 3343},
+													pos:  position{line: 103, col: 14, offset: 3320},
 													name: "SourceChar",
 												},
 												&ruleRefExpr{
-													pos:  position{line: 103, col: 27, offset: 3356},
+													pos:  position{line: 103, col: 27, offset: 3333},
 													name: "EOL",
 												},
 												&ruleRefExpr{
-													pos:  position{line: 103, col: 33, offset: 3362},
+													pos:  position{line: 103, col: 33, offset: 3339},
 													name: "EOF",
 												},
 											},
@@ -1248,26 +1248,26 @@ var g = &grammar{
 								},
 							},
 							&actionExpr{
-								pos: position{line: 104, col: 7, offset: 3433},
+								pos: position{line: 104, col: 7, offset: 3410},
 								run: (*parser).callonUnicodeClassEscape13,
 								expr: &seqExpr{
-									pos: position{line: 104, col: 7, offset: 3433},
+									pos: position{line: 104, col: 7, offset: 3410},
 									exprs: []interface{}{
 										&litMatcher{
-											pos:        position{line: 104, col: 7, offset: 3433},
+											pos:        position{line: 104, col: 7, offset: 3410},
 											val:        "{",
 											ignoreCase: false,
 										},
 										&labeledExpr{
-											pos:   position{line: 104, col: 11, offset: 3437},
+											pos:   position{line: 104, col: 11, offset: 3414},
 											label: "ident",
 											expr: &ruleRefExpr{
-												pos:  position{line: 104, col: 17, offset: 3443},
+												pos:  position{line: 104, col: 17, offset: 3420},
 												name: "IdentifierName",
 											},
 										},
 										&litMatcher{
-											pos:        position{line: 104, col: 32, offset: 3458},
+											pos:        position{line: 104, col: 32, offset: 3435},
 											val:        "}",
 											ignoreCase: false,
 										},
@@ -1275,34 +1275,34 @@ var g = &grammar{
 								},
 							},
 							&actionExpr{
-								pos: position{line: 110, col: 7, offset: 3622},
+								pos: position{line: 110, col: 7, offset: 3599},
 								run: (*parser).callonUnicodeClassEscape19,
 								expr: &seqExpr{
-									pos: position{line: 110, col: 7, offset: 3622},
+									pos: position{line: 110, col: 7, offset: 3599},
 									exprs: []interface{}{
 										&litMatcher{
-											pos:        position{line: 110, col: 7, offset: 3622},
+											pos:        position{line: 110, col: 7, offset: 3599},
 											val:        "{",
 											ignoreCase: false,
 										},
 										&ruleRefExpr{
-											pos:  position{line: 110, col: 11, offset: 3626},
+											pos:  position{line: 110, col: 11, offset: 3603},
 											name: "IdentifierName",
 										},
 										&choiceExpr{
-											pos: position{line: 110, col: 28, offset: 3643},
+											pos: position{line: 110, col: 28, offset: 3620},
 											alternatives: []interface{}{
 												&litMatcher{
-													pos:        position{line: 110, col: 28, offset: 3643},
+													pos:        position{line: 110, col: 28, offset: 3620},
 													val:        "]",
 													ignoreCase: false,
 												},
 												&ruleRefExpr{
-													pos:  position{line: 110, col: 34, offset: 3649},
+													pos:  position{line: 110, col: 34, offset: 3626},
 													name: "EOL",
 												},
 												&ruleRefExpr{
-													pos:  position{line: 110, col: 40, offset: 3655},
+													pos:  position{line: 110, col: 40, offset: 3632},
 													name: "EOF",
 												},
 											},
@@ -1317,9 +1317,9 @@ var g = &grammar{
 		},
 		{
 			name: "SingleCharUnicodeClass",
-			pos:  position{line: 115, col: 1, offset: 3735},
+			pos:  position{line: 115, col: 1, offset: 3712},
 			expr: &charClassMatcher{
-				pos:        position{line: 115, col: 26, offset: 3760},
+				pos:        position{line: 115, col: 26, offset: 3737},
 				val:        "[LMNCPZS]",
 				chars:      []rune{'L', 'M', 'N', 'C', 'P', 'Z', 'S'},
 				ignoreCase: false,
@@ -1328,39 +1328,39 @@ var g = &grammar{
 		},
 		{
 			name: "Number",
-			pos:  position{line: 118, col: 1, offset: 3772},
+			pos:  position{line: 118, col: 1, offset: 3749},
 			expr: &actionExpr{
-				pos: position{line: 118, col: 10, offset: 3781},
+				pos: position{line: 118, col: 10, offset: 3758},
 				run: (*parser).callonNumber1,
 				expr: &seqExpr{
-					pos: position{line: 118, col: 10, offset: 3781},
+					pos: position{line: 118, col: 10, offset: 3758},
 					exprs: []interface{}{
 						&zeroOrOneExpr{
-							pos: position{line: 118, col: 10, offset: 3781},
+							pos: position{line: 118, col: 10, offset: 3758},
 							expr: &litMatcher{
-								pos:        position{line: 118, col: 10, offset: 3781},
+								pos:        position{line: 118, col: 10, offset: 3758},
 								val:        "-",
 								ignoreCase: false,
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 118, col: 15, offset: 3786},
+							pos:  position{line: 118, col: 15, offset: 3763},
 							name: "Integer",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 118, col: 23, offset: 3794},
+							pos: position{line: 118, col: 23, offset: 3771},
 							expr: &seqExpr{
-								pos: position{line: 118, col: 25, offset: 3796},
+								pos: position{line: 118, col: 25, offset: 3773},
 								exprs: []interface{}{
 									&litMatcher{
-										pos:        position{line: 118, col: 25, offset: 3796},
+										pos:        position{line: 118, col: 25, offset: 3773},
 										val:        ".",
 										ignoreCase: false,
 									},
 									&oneOrMoreExpr{
-										pos: position{line: 118, col: 29, offset: 3800},
+										pos: position{line: 118, col: 29, offset: 3777},
 										expr: &ruleRefExpr{
-											pos:  position{line: 118, col: 29, offset: 3800},
+											pos:  position{line: 118, col: 29, offset: 3777},
 											name: "Digit",
 										},
 									},
@@ -1373,29 +1373,29 @@ var g = &grammar{
 		},
 		{
 			name: "Integer",
-			pos:  position{line: 122, col: 1, offset: 3852},
+			pos:  position{line: 122, col: 1, offset: 3829},
 			expr: &choiceExpr{
-				pos: position{line: 122, col: 11, offset: 3862},
+				pos: position{line: 122, col: 11, offset: 3839},
 				alternatives: []interface{}{
 					&litMatcher{
-						pos:        position{line: 122, col: 11, offset: 3862},
+						pos:        position{line: 122, col: 11, offset: 3839},
 						val:        "0",
 						ignoreCase: false,
 					},
 					&actionExpr{
-						pos: position{line: 122, col: 17, offset: 3868},
+						pos: position{line: 122, col: 17, offset: 3845},
 						run: (*parser).callonInteger3,
 						expr: &seqExpr{
-							pos: position{line: 122, col: 17, offset: 3868},
+							pos: position{line: 122, col: 17, offset: 3845},
 							exprs: []interface{}{
 								&ruleRefExpr{
-									pos:  position{line: 122, col: 17, offset: 3868},
+									pos:  position{line: 122, col: 17, offset: 3845},
 									name: "NonZeroDigit",
 								},
 								&zeroOrMoreExpr{
-									pos: position{line: 122, col: 30, offset: 3881},
+									pos: position{line: 122, col: 30, offset: 3858},
 									expr: &ruleRefExpr{
-										pos:  position{line: 122, col: 30, offset: 3881},
+										pos:  position{line: 122, col: 30, offset: 3858},
 										name: "Digit",
 									},
 								},
@@ -1407,9 +1407,9 @@ var g = &grammar{
 		},
 		{
 			name: "NonZeroDigit",
-			pos:  position{line: 126, col: 1, offset: 3945},
+			pos:  position{line: 126, col: 1, offset: 3922},
 			expr: &charClassMatcher{
-				pos:        position{line: 126, col: 16, offset: 3960},
+				pos:        position{line: 126, col: 16, offset: 3937},
 				val:        "[1-9]",
 				ranges:     []rune{'1', '9'},
 				ignoreCase: false,
@@ -1418,9 +1418,9 @@ var g = &grammar{
 		},
 		{
 			name: "Digit",
-			pos:  position{line: 127, col: 1, offset: 3966},
+			pos:  position{line: 127, col: 1, offset: 3943},
 			expr: &charClassMatcher{
-				pos:        position{line: 127, col: 9, offset: 3974},
+				pos:        position{line: 127, col: 9, offset: 3951},
 				val:        "[0-9]",
 				ranges:     []rune{'0', '9'},
 				ignoreCase: false,
@@ -1429,31 +1429,31 @@ var g = &grammar{
 		},
 		{
 			name: "LabelBlock",
-			pos:  position{line: 129, col: 1, offset: 3981},
+			pos:  position{line: 129, col: 1, offset: 3958},
 			expr: &choiceExpr{
-				pos: position{line: 129, col: 14, offset: 3994},
+				pos: position{line: 129, col: 14, offset: 3971},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 129, col: 14, offset: 3994},
+						pos: position{line: 129, col: 14, offset: 3971},
 						run: (*parser).callonLabelBlock2,
 						expr: &seqExpr{
-							pos: position{line: 129, col: 14, offset: 3994},
+							pos: position{line: 129, col: 14, offset: 3971},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 129, col: 14, offset: 3994},
+									pos:        position{line: 129, col: 14, offset: 3971},
 									val:        "{",
 									ignoreCase: false,
 								},
 								&labeledExpr{
-									pos:   position{line: 129, col: 18, offset: 3998},
+									pos:   position{line: 129, col: 18, offset: 3975},
 									label: "block",
 									expr: &ruleRefExpr{
-										pos:  position{line: 129, col: 24, offset: 4004},
+										pos:  position{line: 129, col: 24, offset: 3981},
 										name: "LabelMatches",
 									},
 								},
 								&litMatcher{
-									pos:        position{line: 129, col: 37, offset: 4017},
+									pos:        position{line: 129, col: 37, offset: 3994},
 									val:        "}",
 									ignoreCase: false,
 								},
@@ -1461,22 +1461,22 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 131, col: 5, offset: 4049},
+						pos: position{line: 131, col: 5, offset: 4026},
 						run: (*parser).callonLabelBlock8,
 						expr: &seqExpr{
-							pos: position{line: 131, col: 5, offset: 4049},
+							pos: position{line: 131, col: 5, offset: 4026},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 131, col: 5, offset: 4049},
+									pos:        position{line: 131, col: 5, offset: 4026},
 									val:        "{",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 131, col: 9, offset: 4053},
+									pos:  position{line: 131, col: 9, offset: 4030},
 									name: "LabelMatches",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 131, col: 22, offset: 4066},
+									pos:  position{line: 131, col: 22, offset: 4043},
 									name: "EOF",
 								},
 							},
@@ -1487,12 +1487,12 @@ var g = &grammar{
 		},
 		{
 			name: "NanoSecondUnits",
-			pos:  position{line: 135, col: 1, offset: 4131},
+			pos:  position{line: 135, col: 1, offset: 4108},
 			expr: &actionExpr{
-				pos: position{line: 135, col: 19, offset: 4149},
+				pos: position{line: 135, col: 19, offset: 4126},
 				run: (*parser).callonNanoSecondUnits1,
 				expr: &litMatcher{
-					pos:        position{line: 135, col: 19, offset: 4149},
+					pos:        position{line: 135, col: 19, offset: 4126},
 					val:        "ns",
 					ignoreCase: false,
 				},
@@ -1500,25 +1500,25 @@ var g = &grammar{
 		},
 		{
 			name: "MicroSecondUnits",
-			pos:  position{line: 140, col: 1, offset: 4254},
+			pos:  position{line: 140, col: 1, offset: 4231},
 			expr: &actionExpr{
-				pos: position{line: 140, col: 20, offset: 4273},
+				pos: position{line: 140, col: 20, offset: 4250},
 				run: (*parser).callonMicroSecondUnits1,
 				expr: &choiceExpr{
-					pos: position{line: 140, col: 21, offset: 4274},
+					pos: position{line: 140, col: 21, offset: 4251},
 					alternatives: []interface{}{
 						&litMatcher{
-							pos:        position{line: 140, col: 21, offset: 4274},
+							pos:        position{line: 140, col: 21, offset: 4251},
 							val:        "us",
 							ignoreCase: false,
 						},
 						&litMatcher{
-							pos:        position{line: 140, col: 28, offset: 4281},
+							pos:        position{line: 140, col: 28, offset: 4258},
 							val:        "µs",
 							ignoreCase: false,
 						},
 						&litMatcher{
-							pos:        position{line: 140, col: 35, offset: 4289},
+							pos:        position{line: 140, col: 35, offset: 4266},
 							val:        "μs",
 							ignoreCase: false,
 						},
@@ -1528,12 +1528,12 @@ var g = &grammar{
 		},
 		{
 			name: "MilliSecondUnits",
-			pos:  position{line: 145, col: 1, offset: 4398},
+			pos:  position{line: 145, col: 1, offset: 4375},
 			expr: &actionExpr{
-				pos: position{line: 145, col: 20, offset: 4417},
+				pos: position{line: 145, col: 20, offset: 4394},
 				run: (*parser).callonMilliSecondUnits1,
 				expr: &litMatcher{
-					pos:        position{line: 145, col: 20, offset: 4417},
+					pos:        position{line: 145, col: 20, offset: 4394},
 					val:        "ms",
 					ignoreCase: false,
 				},
@@ -1541,12 +1541,12 @@ var g = &grammar{
 		},
 		{
 			name: "SecondUnits",
-			pos:  position{line: 150, col: 1, offset: 4524},
+			pos:  position{line: 150, col: 1, offset: 4501},
 			expr: &actionExpr{
-				pos: position{line: 150, col: 15, offset: 4538},
+				pos: position{line: 150, col: 15, offset: 4515},
 				run: (*parser).callonSecondUnits1,
 				expr: &litMatcher{
-					pos:        position{line: 150, col: 15, offset: 4538},
+					pos:        position{line: 150, col: 15, offset: 4515},
 					val:        "s",
 					ignoreCase: false,
 				},
@@ -1554,12 +1554,12 @@ var g = &grammar{
 		},
 		{
 			name: "MinuteUnits",
-			pos:  position{line: 154, col: 1, offset: 4575},
+			pos:  position{line: 154, col: 1, offset: 4552},
 			expr: &actionExpr{
-				pos: position{line: 154, col: 15, offset: 4589},
+				pos: position{line: 154, col: 15, offset: 4566},
 				run: (*parser).callonMinuteUnits1,
 				expr: &litMatcher{
-					pos:        position{line: 154, col: 15, offset: 4589},
+					pos:        position{line: 154, col: 15, offset: 4566},
 					val:        "m",
 					ignoreCase: false,
 				},
@@ -1567,12 +1567,12 @@ var g = &grammar{
 		},
 		{
 			name: "HourUnits",
-			pos:  position{line: 158, col: 1, offset: 4626},
+			pos:  position{line: 158, col: 1, offset: 4603},
 			expr: &actionExpr{
-				pos: position{line: 158, col: 13, offset: 4638},
+				pos: position{line: 158, col: 13, offset: 4615},
 				run: (*parser).callonHourUnits1,
 				expr: &litMatcher{
-					pos:        position{line: 158, col: 13, offset: 4638},
+					pos:        position{line: 158, col: 13, offset: 4615},
 					val:        "h",
 					ignoreCase: false,
 				},
@@ -1580,12 +1580,12 @@ var g = &grammar{
 		},
 		{
 			name: "DayUnits",
-			pos:  position{line: 162, col: 1, offset: 4673},
+			pos:  position{line: 162, col: 1, offset: 4650},
 			expr: &actionExpr{
-				pos: position{line: 162, col: 12, offset: 4684},
+				pos: position{line: 162, col: 12, offset: 4661},
 				run: (*parser).callonDayUnits1,
 				expr: &litMatcher{
-					pos:        position{line: 162, col: 12, offset: 4684},
+					pos:        position{line: 162, col: 12, offset: 4661},
 					val:        "d",
 					ignoreCase: false,
 				},
@@ -1593,12 +1593,12 @@ var g = &grammar{
 		},
 		{
 			name: "WeekUnits",
-			pos:  position{line: 168, col: 1, offset: 4892},
+			pos:  position{line: 168, col: 1, offset: 4869},
 			expr: &actionExpr{
-				pos: position{line: 168, col: 13, offset: 4904},
+				pos: position{line: 168, col: 13, offset: 4881},
 				run: (*parser).callonWeekUnits1,
 				expr: &litMatcher{
-					pos:        position{line: 168, col: 13, offset: 4904},
+					pos:        position{line: 168, col: 13, offset: 4881},
 					val:        "w",
 					ignoreCase: false,
 				},
@@ -1606,12 +1606,12 @@ var g = &grammar{
 		},
 		{
 			name: "YearUnits",
-			pos:  position{line: 174, col: 1, offset: 5115},
+			pos:  position{line: 174, col: 1, offset: 5092},
 			expr: &actionExpr{
-				pos: position{line: 174, col: 13, offset: 5127},
+				pos: position{line: 174, col: 13, offset: 5104},
 				run: (*parser).callonYearUnits1,
 				expr: &litMatcher{
-					pos:        position{line: 174, col: 13, offset: 5127},
+					pos:        position{line: 174, col: 13, offset: 5104},
 					val:        "y",
 					ignoreCase: false,
 				},
@@ -1619,44 +1619,44 @@ var g = &grammar{
 		},
 		{
 			name: "DurationUnits",
-			pos:  position{line: 180, col: 1, offset: 5324},
+			pos:  position{line: 180, col: 1, offset: 5301},
 			expr: &choiceExpr{
-				pos: position{line: 180, col: 18, offset: 5341},
+				pos: position{line: 180, col: 18, offset: 5318},
 				alternatives: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 180, col: 18, offset: 5341},
+						pos:  position{line: 180, col: 18, offset: 5318},
 						name: "NanoSecondUnits",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 180, col: 36, offset: 5359},
+						pos:  position{line: 180, col: 36, offset: 5336},
 						name: "MicroSecondUnits",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 180, col: 55, offset: 5378},
+						pos:  position{line: 180, col: 55, offset: 5355},
 						name: "MilliSecondUnits",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 180, col: 74, offset: 5397},
+						pos:  position{line: 180, col: 74, offset: 5374},
 						name: "SecondUnits",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 180, col: 88, offset: 5411},
+						pos:  position{line: 180, col: 88, offset: 5388},
 						name: "MinuteUnits",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 180, col: 102, offset: 5425},
+						pos:  position{line: 180, col: 102, offset: 5402},
 						name: "HourUnits",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 180, col: 114, offset: 5437},
+						pos:  position{line: 180, col: 114, offset: 5414},
 						name: "DayUnits",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 180, col: 125, offset: 5448},
+						pos:  position{line: 180, col: 125, offset: 5425},
 						name: "WeekUnits",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 180, col: 137, offset: 5460},
+						pos:  position{line: 180, col: 137, offset: 5437},
 						name: "YearUnits",
 					},
 				},
@@ -1664,26 +1664,26 @@ var g = &grammar{
 		},
 		{
 			name: "Duration",
-			pos:  position{line: 182, col: 1, offset: 5472},
+			pos:  position{line: 182, col: 1, offset: 5449},
 			expr: &actionExpr{
-				pos: position{line: 182, col: 12, offset: 5483},
+				pos: position{line: 182, col: 12, offset: 5460},
 				run: (*parser).callonDuration1,
 				expr: &seqExpr{
-					pos: position{line: 182, col: 12, offset: 5483},
+					pos: position{line: 182, col: 12, offset: 5460},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 182, col: 12, offset: 5483},
+							pos:   position{line: 182, col: 12, offset: 5460},
 							label: "dur",
 							expr: &ruleRefExpr{
-								pos:  position{line: 182, col: 16, offset: 5487},
+								pos:  position{line: 182, col: 16, offset: 5464},
 								name: "Integer",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 182, col: 24, offset: 5495},
+							pos:   position{line: 182, col: 24, offset: 5472},
 							label: "units",
 							expr: &ruleRefExpr{
-								pos:  position{line: 182, col: 30, offset: 5501},
+								pos:  position{line: 182, col: 30, offset: 5478},
 								name: "DurationUnits",
 							},
 						},
@@ -1692,127 +1692,169 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "Operators",
-			pos:  position{line: 188, col: 1, offset: 5650},
+			name: "ComparisonOperators",
+			pos:  position{line: 188, col: 1, offset: 5627},
 			expr: &choiceExpr{
-				pos: position{line: 188, col: 13, offset: 5662},
+				pos: position{line: 188, col: 23, offset: 5649},
 				alternatives: []interface{}{
-					&litMatcher{
-						pos:        position{line: 188, col: 13, offset: 5662},
-						val:        "-",
-						ignoreCase: false,
-					},
-					&litMatcher{
-						pos:        position{line: 188, col: 19, offset: 5668},
-						val:        "+",
-						ignoreCase: false,
-					},
-					&litMatcher{
-						pos:        position{line: 188, col: 25, offset: 5674},
-						val:        "*",
-						ignoreCase: false,
-					},
-					&litMatcher{
-						pos:        position{line: 188, col: 31, offset: 5680},
-						val:        "%",
-						ignoreCase: false,
-					},
-					&litMatcher{
-						pos:        position{line: 188, col: 37, offset: 5686},
-						val:        "/",
-						ignoreCase: false,
+					&actionExpr{
+						pos: position{line: 188, col: 23, offset: 5649},
+						run: (*parser).callonComparisonOperators2,
+						expr: &litMatcher{
+							pos:        position{line: 188, col: 23, offset: 5649},
+							val:        "==",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 43, offset: 5692},
-						val:        "==",
-						ignoreCase: false,
+					&actionExpr{
+						pos: position{line: 190, col: 5, offset: 5684},
+						run: (*parser).callonComparisonOperators4,
+						expr: &litMatcher{
+							pos:        position{line: 190, col: 5, offset: 5684},
+							val:        "!=",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 50, offset: 5699},
-						val:        "!=",
-						ignoreCase: false,
+					&actionExpr{
+						pos: position{line: 192, col: 5, offset: 5722},
+						run: (*parser).callonComparisonOperators6,
+						expr: &litMatcher{
+							pos:        position{line: 192, col: 5, offset: 5722},
+							val:        ">=",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 57, offset: 5706},
-						val:        "<=",
-						ignoreCase: false,
+					&actionExpr{
+						pos: position{line: 194, col: 5, offset: 5764},
+						run: (*parser).callonComparisonOperators8,
+						expr: &litMatcher{
+							pos:        position{line: 194, col: 5, offset: 5764},
+							val:        "<=",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 64, offset: 5713},
-						val:        "<",
-						ignoreCase: false,
+					&actionExpr{
+						pos: position{line: 196, col: 5, offset: 5803},
+						run: (*parser).callonComparisonOperators10,
+						expr: &litMatcher{
+							pos:        position{line: 196, col: 5, offset: 5803},
+							val:        ">",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 70, offset: 5719},
-						val:        ">=",
-						ignoreCase: false,
+					&actionExpr{
+						pos: position{line: 198, col: 5, offset: 5839},
+						run: (*parser).callonComparisonOperators12,
+						expr: &litMatcher{
+							pos:        position{line: 198, col: 5, offset: 5839},
+							val:        "<",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 77, offset: 5726},
-						val:        ">",
-						ignoreCase: false,
+				},
+			},
+		},
+		{
+			name: "AdditiveOperators",
+			pos:  position{line: 202, col: 1, offset: 5871},
+			expr: &choiceExpr{
+				pos: position{line: 202, col: 21, offset: 5891},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 202, col: 21, offset: 5891},
+						run: (*parser).callonAdditiveOperators2,
+						expr: &litMatcher{
+							pos:        position{line: 202, col: 21, offset: 5891},
+							val:        "+",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 83, offset: 5732},
-						val:        "=~",
-						ignoreCase: false,
+					&actionExpr{
+						pos: position{line: 204, col: 5, offset: 5923},
+						run: (*parser).callonAdditiveOperators4,
+						expr: &litMatcher{
+							pos:        position{line: 204, col: 5, offset: 5923},
+							val:        "-",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 90, offset: 5739},
-						val:        "!~",
-						ignoreCase: false,
+				},
+			},
+		},
+		{
+			name: "MultiplicativeOperators",
+			pos:  position{line: 208, col: 1, offset: 5954},
+			expr: &choiceExpr{
+				pos: position{line: 208, col: 27, offset: 5980},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 208, col: 27, offset: 5980},
+						run: (*parser).callonMultiplicativeOperators2,
+						expr: &litMatcher{
+							pos:        position{line: 208, col: 27, offset: 5980},
+							val:        "*",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 97, offset: 5746},
-						val:        "^",
-						ignoreCase: false,
+					&actionExpr{
+						pos: position{line: 210, col: 5, offset: 6012},
+						run: (*parser).callonMultiplicativeOperators4,
+						expr: &litMatcher{
+							pos:        position{line: 210, col: 5, offset: 6012},
+							val:        "/",
+							ignoreCase: false,
+						},
 					},
-					&litMatcher{
-						pos:        position{line: 188, col: 103, offset: 5752},
-						val:        "=",
-						ignoreCase: false,
+					&actionExpr{
+						pos: position{line: 212, col: 5, offset: 6044},
+						run: (*parser).callonMultiplicativeOperators6,
+						expr: &litMatcher{
+							pos:        position{line: 212, col: 5, offset: 6044},
+							val:        "%",
+							ignoreCase: false,
+						},
 					},
 				},
 			},
 		},
 		{
 			name: "LabelOperators",
-			pos:  position{line: 190, col: 1, offset: 5757},
+			pos:  position{line: 216, col: 1, offset: 6075},
 			expr: &choiceExpr{
-				pos: position{line: 190, col: 19, offset: 5775},
+				pos: position{line: 216, col: 19, offset: 6093},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 190, col: 19, offset: 5775},
+						pos: position{line: 216, col: 19, offset: 6093},
 						run: (*parser).callonLabelOperators2,
 						expr: &litMatcher{
-							pos:        position{line: 190, col: 19, offset: 5775},
+							pos:        position{line: 216, col: 19, offset: 6093},
 							val:        "!=",
 							ignoreCase: false,
 						},
 					},
 					&actionExpr{
-						pos: position{line: 192, col: 5, offset: 5811},
+						pos: position{line: 218, col: 5, offset: 6129},
 						run: (*parser).callonLabelOperators4,
 						expr: &litMatcher{
-							pos:        position{line: 192, col: 5, offset: 5811},
+							pos:        position{line: 218, col: 5, offset: 6129},
 							val:        "=~",
 							ignoreCase: false,
 						},
 					},
 					&actionExpr{
-						pos: position{line: 194, col: 5, offset: 5849},
+						pos: position{line: 220, col: 5, offset: 6167},
 						run: (*parser).callonLabelOperators6,
 						expr: &litMatcher{
-							pos:        position{line: 194, col: 5, offset: 5849},
+							pos:        position{line: 220, col: 5, offset: 6167},
 							val:        "!~",
 							ignoreCase: false,
 						},
 					},
 					&actionExpr{
-						pos: position{line: 196, col: 5, offset: 5889},
+						pos: position{line: 222, col: 5, offset: 6207},
 						run: (*parser).callonLabelOperators8,
 						expr: &litMatcher{
-							pos:        position{line: 196, col: 5, offset: 5889},
+							pos:        position{line: 222, col: 5, offset: 6207},
 							val:        "=",
 							ignoreCase: false,
 						},
@@ -1822,57 +1864,57 @@ var g = &grammar{
 		},
 		{
 			name: "Label",
-			pos:  position{line: 200, col: 1, offset: 5920},
+			pos:  position{line: 226, col: 1, offset: 6238},
 			expr: &ruleRefExpr{
-				pos:  position{line: 200, col: 9, offset: 5928},
+				pos:  position{line: 226, col: 9, offset: 6246},
 				name: "Identifier",
 			},
 		},
 		{
 			name: "LabelMatch",
-			pos:  position{line: 201, col: 1, offset: 5939},
+			pos:  position{line: 227, col: 1, offset: 6257},
 			expr: &actionExpr{
-				pos: position{line: 201, col: 14, offset: 5952},
+				pos: position{line: 227, col: 14, offset: 6270},
 				run: (*parser).callonLabelMatch1,
 				expr: &seqExpr{
-					pos: position{line: 201, col: 14, offset: 5952},
+					pos: position{line: 227, col: 14, offset: 6270},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 201, col: 14, offset: 5952},
+							pos:   position{line: 227, col: 14, offset: 6270},
 							label: "label",
 							expr: &ruleRefExpr{
-								pos:  position{line: 201, col: 20, offset: 5958},
+								pos:  position{line: 227, col: 20, offset: 6276},
 								name: "Label",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 201, col: 26, offset: 5964},
+							pos:  position{line: 227, col: 26, offset: 6282},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 201, col: 29, offset: 5967},
+							pos:   position{line: 227, col: 29, offset: 6285},
 							label: "op",
 							expr: &ruleRefExpr{
-								pos:  position{line: 201, col: 32, offset: 5970},
+								pos:  position{line: 227, col: 32, offset: 6288},
 								name: "LabelOperators",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 201, col: 47, offset: 5985},
+							pos:  position{line: 227, col: 47, offset: 6303},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 201, col: 50, offset: 5988},
+							pos:   position{line: 227, col: 50, offset: 6306},
 							label: "match",
 							expr: &choiceExpr{
-								pos: position{line: 201, col: 58, offset: 5996},
+								pos: position{line: 227, col: 58, offset: 6314},
 								alternatives: []interface{}{
 									&ruleRefExpr{
-										pos:  position{line: 201, col: 58, offset: 5996},
+										pos:  position{line: 227, col: 58, offset: 6314},
 										name: "StringLiteral",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 201, col: 74, offset: 6012},
+										pos:  position{line: 227, col: 74, offset: 6330},
 										name: "Number",
 									},
 								},
@@ -1884,32 +1926,32 @@ var g = &grammar{
 		},
 		{
 			name: "LabelMatches",
-			pos:  position{line: 204, col: 1, offset: 6102},
+			pos:  position{line: 230, col: 1, offset: 6420},
 			expr: &actionExpr{
-				pos: position{line: 204, col: 16, offset: 6117},
+				pos: position{line: 230, col: 16, offset: 6435},
 				run: (*parser).callonLabelMatches1,
 				expr: &seqExpr{
-					pos: position{line: 204, col: 16, offset: 6117},
+					pos: position{line: 230, col: 16, offset: 6435},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 204, col: 16, offset: 6117},
+							pos:   position{line: 230, col: 16, offset: 6435},
 							label: "first",
 							expr: &ruleRefExpr{
-								pos:  position{line: 204, col: 22, offset: 6123},
+								pos:  position{line: 230, col: 22, offset: 6441},
 								name: "LabelMatch",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 204, col: 33, offset: 6134},
+							pos:  position{line: 230, col: 33, offset: 6452},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 204, col: 36, offset: 6137},
+							pos:   position{line: 230, col: 36, offset: 6455},
 							label: "rest",
 							expr: &zeroOrMoreExpr{
-								pos: position{line: 204, col: 41, offset: 6142},
+								pos: position{line: 230, col: 41, offset: 6460},
 								expr: &ruleRefExpr{
-									pos:  position{line: 204, col: 41, offset: 6142},
+									pos:  position{line: 230, col: 41, offset: 6460},
 									name: "LabelMatchesRest",
 								},
 							},
@@ -1920,27 +1962,27 @@ var g = &grammar{
 		},
 		{
 			name: "LabelMatchesRest",
-			pos:  position{line: 208, col: 1, offset: 6221},
+			pos:  position{line: 234, col: 1, offset: 6539},
 			expr: &actionExpr{
-				pos: position{line: 208, col: 21, offset: 6241},
+				pos: position{line: 234, col: 21, offset: 6559},
 				run: (*parser).callonLabelMatchesRest1,
 				expr: &seqExpr{
-					pos: position{line: 208, col: 21, offset: 6241},
+					pos: position{line: 234, col: 21, offset: 6559},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 208, col: 21, offset: 6241},
+							pos:        position{line: 234, col: 21, offset: 6559},
 							val:        ",",
 							ignoreCase: false,
 						},
 						&ruleRefExpr{
-							pos:  position{line: 208, col: 25, offset: 6245},
+							pos:  position{line: 234, col: 25, offset: 6563},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 208, col: 28, offset: 6248},
+							pos:   position{line: 234, col: 28, offset: 6566},
 							label: "match",
 							expr: &ruleRefExpr{
-								pos:  position{line: 208, col: 34, offset: 6254},
+								pos:  position{line: 234, col: 34, offset: 6572},
 								name: "LabelMatch",
 							},
 						},
@@ -1950,27 +1992,27 @@ var g = &grammar{
 		},
 		{
 			name: "LabelList",
-			pos:  position{line: 212, col: 1, offset: 6292},
+			pos:  position{line: 238, col: 1, offset: 6610},
 			expr: &choiceExpr{
-				pos: position{line: 212, col: 13, offset: 6304},
+				pos: position{line: 238, col: 13, offset: 6622},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 212, col: 13, offset: 6304},
+						pos: position{line: 238, col: 13, offset: 6622},
 						run: (*parser).callonLabelList2,
 						expr: &seqExpr{
-							pos: position{line: 212, col: 14, offset: 6305},
+							pos: position{line: 238, col: 14, offset: 6623},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 212, col: 14, offset: 6305},
+									pos:        position{line: 238, col: 14, offset: 6623},
 									val:        "(",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 212, col: 18, offset: 6309},
+									pos:  position{line: 238, col: 18, offset: 6627},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 212, col: 21, offset: 6312},
+									pos:        position{line: 238, col: 21, offset: 6630},
 									val:        ")",
 									ignoreCase: false,
 								},
@@ -1978,49 +2020,49 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 214, col: 6, offset: 6344},
+						pos: position{line: 240, col: 6, offset: 6662},
 						run: (*parser).callonLabelList7,
 						expr: &seqExpr{
-							pos: position{line: 214, col: 6, offset: 6344},
+							pos: position{line: 240, col: 6, offset: 6662},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 214, col: 6, offset: 6344},
+									pos:        position{line: 240, col: 6, offset: 6662},
 									val:        "(",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 214, col: 10, offset: 6348},
+									pos:  position{line: 240, col: 10, offset: 6666},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 214, col: 13, offset: 6351},
+									pos:   position{line: 240, col: 13, offset: 6669},
 									label: "label",
 									expr: &ruleRefExpr{
-										pos:  position{line: 214, col: 19, offset: 6357},
+										pos:  position{line: 240, col: 19, offset: 6675},
 										name: "Label",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 214, col: 25, offset: 6363},
+									pos:  position{line: 240, col: 25, offset: 6681},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 214, col: 28, offset: 6366},
+									pos:   position{line: 240, col: 28, offset: 6684},
 									label: "rest",
 									expr: &zeroOrMoreExpr{
-										pos: position{line: 214, col: 33, offset: 6371},
+										pos: position{line: 240, col: 33, offset: 6689},
 										expr: &ruleRefExpr{
-											pos:  position{line: 214, col: 33, offset: 6371},
+											pos:  position{line: 240, col: 33, offset: 6689},
 											name: "LabelListRest",
 										},
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 214, col: 48, offset: 6386},
+									pos:  position{line: 240, col: 48, offset: 6704},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 214, col: 51, offset: 6389},
+									pos:        position{line: 240, col: 51, offset: 6707},
 									val:        ")",
 									ignoreCase: false,
 								},
@@ -2032,27 +2074,27 @@ var g = &grammar{
 		},
 		{
 			name: "LabelListRest",
-			pos:  position{line: 218, col: 1, offset: 6455},
+			pos:  position{line: 244, col: 1, offset: 6773},
 			expr: &actionExpr{
-				pos: position{line: 218, col: 18, offset: 6472},
+				pos: position{line: 244, col: 18, offset: 6790},
 				run: (*parser).callonLabelListRest1,
 				expr: &seqExpr{
-					pos: position{line: 218, col: 18, offset: 6472},
+					pos: position{line: 244, col: 18, offset: 6790},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 218, col: 18, offset: 6472},
+							pos:        position{line: 244, col: 18, offset: 6790},
 							val:        ",",
 							ignoreCase: false,
 						},
 						&ruleRefExpr{
-							pos:  position{line: 218, col: 22, offset: 6476},
+							pos:  position{line: 244, col: 22, offset: 6794},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 218, col: 25, offset: 6479},
+							pos:   position{line: 244, col: 25, offset: 6797},
 							label: "label",
 							expr: &ruleRefExpr{
-								pos:  position{line: 218, col: 31, offset: 6485},
+								pos:  position{line: 244, col: 31, offset: 6803},
 								name: "Label",
 							},
 						},
@@ -2062,62 +2104,62 @@ var g = &grammar{
 		},
 		{
 			name: "VectorSelector",
-			pos:  position{line: 222, col: 1, offset: 6518},
+			pos:  position{line: 248, col: 1, offset: 6836},
 			expr: &actionExpr{
-				pos: position{line: 222, col: 18, offset: 6535},
+				pos: position{line: 248, col: 18, offset: 6853},
 				run: (*parser).callonVectorSelector1,
 				expr: &seqExpr{
-					pos: position{line: 222, col: 18, offset: 6535},
+					pos: position{line: 248, col: 18, offset: 6853},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 222, col: 18, offset: 6535},
+							pos:   position{line: 248, col: 18, offset: 6853},
 							label: "metric",
 							expr: &ruleRefExpr{
-								pos:  position{line: 222, col: 25, offset: 6542},
+								pos:  position{line: 248, col: 25, offset: 6860},
 								name: "Identifier",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 222, col: 36, offset: 6553},
+							pos:  position{line: 248, col: 36, offset: 6871},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 222, col: 40, offset: 6557},
+							pos:   position{line: 248, col: 40, offset: 6875},
 							label: "block",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 222, col: 46, offset: 6563},
+								pos: position{line: 248, col: 46, offset: 6881},
 								expr: &ruleRefExpr{
-									pos:  position{line: 222, col: 46, offset: 6563},
+									pos:  position{line: 248, col: 46, offset: 6881},
 									name: "LabelBlock",
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 222, col: 58, offset: 6575},
+							pos:  position{line: 248, col: 58, offset: 6893},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 222, col: 61, offset: 6578},
+							pos:   position{line: 248, col: 61, offset: 6896},
 							label: "rng",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 222, col: 65, offset: 6582},
+								pos: position{line: 248, col: 65, offset: 6900},
 								expr: &ruleRefExpr{
-									pos:  position{line: 222, col: 65, offset: 6582},
+									pos:  position{line: 248, col: 65, offset: 6900},
 									name: "Range",
 								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 222, col: 72, offset: 6589},
+							pos:  position{line: 248, col: 72, offset: 6907},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 222, col: 75, offset: 6592},
+							pos:   position{line: 248, col: 75, offset: 6910},
 							label: "offset",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 222, col: 82, offset: 6599},
+								pos: position{line: 248, col: 82, offset: 6917},
 								expr: &ruleRefExpr{
-									pos:  position{line: 222, col: 82, offset: 6599},
+									pos:  position{line: 248, col: 82, offset: 6917},
 									name: "Offset",
 								},
 							},
@@ -2128,36 +2170,36 @@ var g = &grammar{
 		},
 		{
 			name: "Range",
-			pos:  position{line: 226, col: 1, offset: 6677},
+			pos:  position{line: 252, col: 1, offset: 6995},
 			expr: &actionExpr{
-				pos: position{line: 226, col: 9, offset: 6685},
+				pos: position{line: 252, col: 9, offset: 7003},
 				run: (*parser).callonRange1,
 				expr: &seqExpr{
-					pos: position{line: 226, col: 9, offset: 6685},
+					pos: position{line: 252, col: 9, offset: 7003},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 226, col: 9, offset: 6685},
+							pos:        position{line: 252, col: 9, offset: 7003},
 							val:        "[",
 							ignoreCase: false,
 						},
 						&ruleRefExpr{
-							pos:  position{line: 226, col: 13, offset: 6689},
+							pos:  position{line: 252, col: 13, offset: 7007},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 226, col: 16, offset: 6692},
+							pos:   position{line: 252, col: 16, offset: 7010},
 							label: "dur",
 							expr: &ruleRefExpr{
-								pos:  position{line: 226, col: 20, offset: 6696},
+								pos:  position{line: 252, col: 20, offset: 7014},
 								name: "Duration",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 226, col: 29, offset: 6705},
+							pos:  position{line: 252, col: 29, offset: 7023},
 							name: "__",
 						},
 						&litMatcher{
-							pos:        position{line: 226, col: 32, offset: 6708},
+							pos:        position{line: 252, col: 32, offset: 7026},
 							val:        "]",
 							ignoreCase: false,
 						},
@@ -2167,27 +2209,27 @@ var g = &grammar{
 		},
 		{
 			name: "Offset",
-			pos:  position{line: 230, col: 1, offset: 6737},
+			pos:  position{line: 256, col: 1, offset: 7055},
 			expr: &actionExpr{
-				pos: position{line: 230, col: 10, offset: 6746},
+				pos: position{line: 256, col: 10, offset: 7064},
 				run: (*parser).callonOffset1,
 				expr: &seqExpr{
-					pos: position{line: 230, col: 10, offset: 6746},
+					pos: position{line: 256, col: 10, offset: 7064},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 230, col: 10, offset: 6746},
+							pos:        position{line: 256, col: 10, offset: 7064},
 							val:        "offset",
 							ignoreCase: true,
 						},
 						&ruleRefExpr{
-							pos:  position{line: 230, col: 20, offset: 6756},
+							pos:  position{line: 256, col: 20, offset: 7074},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 230, col: 23, offset: 6759},
+							pos:   position{line: 256, col: 23, offset: 7077},
 							label: "dur",
 							expr: &ruleRefExpr{
-								pos:  position{line: 230, col: 27, offset: 6763},
+								pos:  position{line: 256, col: 27, offset: 7081},
 								name: "Duration",
 							},
 						},
@@ -2197,12 +2239,12 @@ var g = &grammar{
 		},
 		{
 			name: "CountValueOperator",
-			pos:  position{line: 234, col: 1, offset: 6797},
+			pos:  position{line: 260, col: 1, offset: 7115},
 			expr: &actionExpr{
-				pos: position{line: 234, col: 22, offset: 6818},
+				pos: position{line: 260, col: 22, offset: 7136},
 				run: (*parser).callonCountValueOperator1,
 				expr: &litMatcher{
-					pos:        position{line: 234, col: 22, offset: 6818},
+					pos:        position{line: 260, col: 22, offset: 7136},
 					val:        "count_values",
 					ignoreCase: true,
 				},
@@ -2210,28 +2252,28 @@ var g = &grammar{
 		},
 		{
 			name: "BinaryAggregateOperators",
-			pos:  position{line: 240, col: 1, offset: 6903},
+			pos:  position{line: 266, col: 1, offset: 7221},
 			expr: &actionExpr{
-				pos: position{line: 240, col: 29, offset: 6931},
+				pos: position{line: 266, col: 29, offset: 7249},
 				run: (*parser).callonBinaryAggregateOperators1,
 				expr: &labeledExpr{
-					pos:   position{line: 240, col: 29, offset: 6931},
+					pos:   position{line: 266, col: 29, offset: 7249},
 					label: "op",
 					expr: &choiceExpr{
-						pos: position{line: 240, col: 33, offset: 6935},
+						pos: position{line: 266, col: 33, offset: 7253},
 						alternatives: []interface{}{
 							&litMatcher{
-								pos:        position{line: 240, col: 33, offset: 6935},
+								pos:        position{line: 266, col: 33, offset: 7253},
 								val:        "topk",
 								ignoreCase: true,
 							},
 							&litMatcher{
-								pos:        position{line: 240, col: 43, offset: 6945},
+								pos:        position{line: 266, col: 43, offset: 7263},
 								val:        "bottomk",
 								ignoreCase: true,
 							},
 							&litMatcher{
-								pos:        position{line: 240, col: 56, offset: 6958},
+								pos:        position{line: 266, col: 56, offset: 7276},
 								val:        "quantile",
 								ignoreCase: true,
 							},
@@ -2242,48 +2284,48 @@ var g = &grammar{
 		},
 		{
 			name: "UnaryAggregateOperators",
-			pos:  position{line: 246, col: 1, offset: 7060},
+			pos:  position{line: 272, col: 1, offset: 7378},
 			expr: &actionExpr{
-				pos: position{line: 246, col: 27, offset: 7086},
+				pos: position{line: 272, col: 27, offset: 7404},
 				run: (*parser).callonUnaryAggregateOperators1,
 				expr: &labeledExpr{
-					pos:   position{line: 246, col: 27, offset: 7086},
+					pos:   position{line: 272, col: 27, offset: 7404},
 					label: "op",
 					expr: &choiceExpr{
-						pos: position{line: 246, col: 31, offset: 7090},
+						pos: position{line: 272, col: 31, offset: 7408},
 						alternatives: []interface{}{
 							&litMatcher{
-								pos:        position{line: 246, col: 31, offset: 7090},
+								pos:        position{line: 272, col: 31, offset: 7408},
 								val:        "sum",
 								ignoreCase: true,
 							},
 							&litMatcher{
-								pos:        position{line: 246, col: 40, offset: 7099},
+								pos:        position{line: 272, col: 40, offset: 7417},
 								val:        "min",
 								ignoreCase: true,
 							},
 							&litMatcher{
-								pos:        position{line: 246, col: 49, offset: 7108},
+								pos:        position{line: 272, col: 49, offset: 7426},
 								val:        "max",
 								ignoreCase: true,
 							},
 							&litMatcher{
-								pos:        position{line: 246, col: 58, offset: 7117},
+								pos:        position{line: 272, col: 58, offset: 7435},
 								val:        "avg",
 								ignoreCase: true,
 							},
 							&litMatcher{
-								pos:        position{line: 246, col: 67, offset: 7126},
+								pos:        position{line: 272, col: 67, offset: 7444},
 								val:        "stddev",
 								ignoreCase: true,
 							},
 							&litMatcher{
-								pos:        position{line: 246, col: 79, offset: 7138},
+								pos:        position{line: 272, col: 79, offset: 7456},
 								val:        "stdvar",
 								ignoreCase: true,
 							},
 							&litMatcher{
-								pos:        position{line: 246, col: 91, offset: 7150},
+								pos:        position{line: 272, col: 91, offset: 7468},
 								val:        "count",
 								ignoreCase: true,
 							},
@@ -2294,20 +2336,20 @@ var g = &grammar{
 		},
 		{
 			name: "AggregateOperators",
-			pos:  position{line: 252, col: 1, offset: 7249},
+			pos:  position{line: 278, col: 1, offset: 7567},
 			expr: &choiceExpr{
-				pos: position{line: 252, col: 22, offset: 7270},
+				pos: position{line: 278, col: 22, offset: 7588},
 				alternatives: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 252, col: 22, offset: 7270},
+						pos:  position{line: 278, col: 22, offset: 7588},
 						name: "CountValueOperator",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 252, col: 43, offset: 7291},
+						pos:  position{line: 278, col: 43, offset: 7609},
 						name: "BinaryAggregateOperators",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 252, col: 70, offset: 7318},
+						pos:  position{line: 278, col: 70, offset: 7636},
 						name: "UnaryAggregateOperators",
 					},
 				},
@@ -2315,41 +2357,41 @@ var g = &grammar{
 		},
 		{
 			name: "AggregateBy",
-			pos:  position{line: 254, col: 1, offset: 7343},
+			pos:  position{line: 280, col: 1, offset: 7661},
 			expr: &actionExpr{
-				pos: position{line: 254, col: 15, offset: 7357},
+				pos: position{line: 280, col: 15, offset: 7675},
 				run: (*parser).callonAggregateBy1,
 				expr: &seqExpr{
-					pos: position{line: 254, col: 15, offset: 7357},
+					pos: position{line: 280, col: 15, offset: 7675},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 254, col: 15, offset: 7357},
+							pos:        position{line: 280, col: 15, offset: 7675},
 							val:        "by",
 							ignoreCase: true,
 						},
 						&ruleRefExpr{
-							pos:  position{line: 254, col: 21, offset: 7363},
+							pos:  position{line: 280, col: 21, offset: 7681},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 254, col: 24, offset: 7366},
+							pos:   position{line: 280, col: 24, offset: 7684},
 							label: "labels",
 							expr: &ruleRefExpr{
-								pos:  position{line: 254, col: 31, offset: 7373},
+								pos:  position{line: 280, col: 31, offset: 7691},
 								name: "LabelList",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 254, col: 41, offset: 7383},
+							pos:  position{line: 280, col: 41, offset: 7701},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 254, col: 44, offset: 7386},
+							pos:   position{line: 280, col: 44, offset: 7704},
 							label: "keep",
 							expr: &zeroOrOneExpr{
-								pos: position{line: 254, col: 49, offset: 7391},
+								pos: position{line: 280, col: 49, offset: 7709},
 								expr: &litMatcher{
-									pos:        position{line: 254, col: 49, offset: 7391},
+									pos:        position{line: 280, col: 49, offset: 7709},
 									val:        "keep_common",
 									ignoreCase: true,
 								},
@@ -2361,27 +2403,27 @@ var g = &grammar{
 		},
 		{
 			name: "AggregateWithout",
-			pos:  position{line: 261, col: 1, offset: 7504},
+			pos:  position{line: 287, col: 1, offset: 7822},
 			expr: &actionExpr{
-				pos: position{line: 261, col: 20, offset: 7523},
+				pos: position{line: 287, col: 20, offset: 7841},
 				run: (*parser).callonAggregateWithout1,
 				expr: &seqExpr{
-					pos: position{line: 261, col: 20, offset: 7523},
+					pos: position{line: 287, col: 20, offset: 7841},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 261, col: 20, offset: 7523},
+							pos:        position{line: 287, col: 20, offset: 7841},
 							val:        "without",
 							ignoreCase: true,
 						},
 						&ruleRefExpr{
-							pos:  position{line: 261, col: 31, offset: 7534},
+							pos:  position{line: 287, col: 31, offset: 7852},
 							name: "__",
 						},
 						&labeledExpr{
-							pos:   position{line: 261, col: 34, offset: 7537},
+							pos:   position{line: 287, col: 34, offset: 7855},
 							label: "labels",
 							expr: &ruleRefExpr{
-								pos:  position{line: 261, col: 41, offset: 7544},
+								pos:  position{line: 287, col: 41, offset: 7862},
 								name: "LabelList",
 							},
 						},
@@ -2391,16 +2433,16 @@ var g = &grammar{
 		},
 		{
 			name: "AggregateGroup",
-			pos:  position{line: 268, col: 1, offset: 7656},
+			pos:  position{line: 294, col: 1, offset: 7974},
 			expr: &choiceExpr{
-				pos: position{line: 268, col: 18, offset: 7673},
+				pos: position{line: 294, col: 18, offset: 7991},
 				alternatives: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 268, col: 18, offset: 7673},
+						pos:  position{line: 294, col: 18, offset: 7991},
 						name: "AggregateBy",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 268, col: 32, offset: 7687},
+						pos:  position{line: 294, col: 32, offset: 8005},
 						name: "AggregateWithout",
 					},
 				},
@@ -2408,86 +2450,86 @@ var g = &grammar{
 		},
 		{
 			name: "AggregateExpression",
-			pos:  position{line: 270, col: 1, offset: 7705},
+			pos:  position{line: 296, col: 1, offset: 8023},
 			expr: &choiceExpr{
-				pos: position{line: 271, col: 1, offset: 7727},
+				pos: position{line: 297, col: 1, offset: 8045},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 271, col: 1, offset: 7727},
+						pos: position{line: 297, col: 1, offset: 8045},
 						run: (*parser).callonAggregateExpression2,
 						expr: &seqExpr{
-							pos: position{line: 271, col: 1, offset: 7727},
+							pos: position{line: 297, col: 1, offset: 8045},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 271, col: 1, offset: 7727},
+									pos:   position{line: 297, col: 1, offset: 8045},
 									label: "op",
 									expr: &ruleRefExpr{
-										pos:  position{line: 271, col: 4, offset: 7730},
+										pos:  position{line: 297, col: 4, offset: 8048},
 										name: "CountValueOperator",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 271, col: 24, offset: 7750},
+									pos:  position{line: 297, col: 24, offset: 8068},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 271, col: 27, offset: 7753},
+									pos:        position{line: 297, col: 27, offset: 8071},
 									val:        "(",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 271, col: 31, offset: 7757},
+									pos:  position{line: 297, col: 31, offset: 8075},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 271, col: 34, offset: 7760},
+									pos:   position{line: 297, col: 34, offset: 8078},
 									label: "param",
 									expr: &ruleRefExpr{
-										pos:  position{line: 271, col: 40, offset: 7766},
+										pos:  position{line: 297, col: 40, offset: 8084},
 										name: "StringLiteral",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 271, col: 54, offset: 7780},
+									pos:  position{line: 297, col: 54, offset: 8098},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 271, col: 57, offset: 7783},
+									pos:        position{line: 297, col: 57, offset: 8101},
 									val:        ",",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 271, col: 61, offset: 7787},
+									pos:  position{line: 297, col: 61, offset: 8105},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 271, col: 64, offset: 7790},
+									pos:   position{line: 297, col: 64, offset: 8108},
 									label: "vector",
 									expr: &ruleRefExpr{
-										pos:  position{line: 271, col: 71, offset: 7797},
-										name: "VectorSelector",
+										pos:  position{line: 297, col: 71, offset: 8115},
+										name: "Expression",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 271, col: 86, offset: 7812},
+									pos:  position{line: 297, col: 82, offset: 8126},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 271, col: 89, offset: 7815},
+									pos:        position{line: 297, col: 85, offset: 8129},
 									val:        ")",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 271, col: 93, offset: 7819},
+									pos:  position{line: 297, col: 89, offset: 8133},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 271, col: 96, offset: 7822},
+									pos:   position{line: 297, col: 92, offset: 8136},
 									label: "group",
 									expr: &zeroOrOneExpr{
-										pos: position{line: 271, col: 102, offset: 7828},
+										pos: position{line: 297, col: 98, offset: 8142},
 										expr: &ruleRefExpr{
-											pos:  position{line: 271, col: 102, offset: 7828},
+											pos:  position{line: 297, col: 98, offset: 8142},
 											name: "AggregateGroup",
 										},
 									},
@@ -2496,82 +2538,82 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 277, col: 1, offset: 7976},
+						pos: position{line: 303, col: 1, offset: 8285},
 						run: (*parser).callonAggregateExpression22,
 						expr: &seqExpr{
-							pos: position{line: 277, col: 1, offset: 7976},
+							pos: position{line: 303, col: 1, offset: 8285},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 277, col: 1, offset: 7976},
+									pos:   position{line: 303, col: 1, offset: 8285},
 									label: "op",
 									expr: &ruleRefExpr{
-										pos:  position{line: 277, col: 4, offset: 7979},
+										pos:  position{line: 303, col: 4, offset: 8288},
 										name: "CountValueOperator",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 277, col: 24, offset: 7999},
+									pos:  position{line: 303, col: 24, offset: 8308},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 277, col: 27, offset: 8002},
+									pos:   position{line: 303, col: 27, offset: 8311},
 									label: "group",
 									expr: &zeroOrOneExpr{
-										pos: position{line: 277, col: 33, offset: 8008},
+										pos: position{line: 303, col: 33, offset: 8317},
 										expr: &ruleRefExpr{
-											pos:  position{line: 277, col: 33, offset: 8008},
+											pos:  position{line: 303, col: 33, offset: 8317},
 											name: "AggregateGroup",
 										},
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 277, col: 49, offset: 8024},
+									pos:  position{line: 303, col: 49, offset: 8333},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 277, col: 52, offset: 8027},
+									pos:        position{line: 303, col: 52, offset: 8336},
 									val:        "(",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 277, col: 56, offset: 8031},
+									pos:  position{line: 303, col: 56, offset: 8340},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 277, col: 59, offset: 8034},
+									pos:   position{line: 303, col: 59, offset: 8343},
 									label: "param",
 									expr: &ruleRefExpr{
-										pos:  position{line: 277, col: 65, offset: 8040},
+										pos:  position{line: 303, col: 65, offset: 8349},
 										name: "StringLiteral",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 277, col: 79, offset: 8054},
+									pos:  position{line: 303, col: 79, offset: 8363},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 277, col: 82, offset: 8057},
+									pos:        position{line: 303, col: 82, offset: 8366},
 									val:        ",",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 277, col: 86, offset: 8061},
+									pos:  position{line: 303, col: 86, offset: 8370},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 277, col: 89, offset: 8064},
+									pos:   position{line: 303, col: 89, offset: 8373},
 									label: "vector",
 									expr: &ruleRefExpr{
-										pos:  position{line: 277, col: 96, offset: 8071},
-										name: "VectorSelector",
+										pos:  position{line: 303, col: 96, offset: 8380},
+										name: "Expression",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 277, col: 111, offset: 8086},
+									pos:  position{line: 303, col: 107, offset: 8391},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 277, col: 114, offset: 8089},
+									pos:        position{line: 303, col: 110, offset: 8394},
 									val:        ")",
 									ignoreCase: false,
 								},
@@ -2579,81 +2621,81 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 283, col: 1, offset: 8225},
+						pos: position{line: 309, col: 1, offset: 8525},
 						run: (*parser).callonAggregateExpression42,
 						expr: &seqExpr{
-							pos: position{line: 283, col: 1, offset: 8225},
+							pos: position{line: 309, col: 1, offset: 8525},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 283, col: 1, offset: 8225},
+									pos:   position{line: 309, col: 1, offset: 8525},
 									label: "op",
 									expr: &ruleRefExpr{
-										pos:  position{line: 283, col: 4, offset: 8228},
+										pos:  position{line: 309, col: 4, offset: 8528},
 										name: "BinaryAggregateOperators",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 283, col: 30, offset: 8254},
+									pos:  position{line: 309, col: 30, offset: 8554},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 283, col: 33, offset: 8257},
+									pos:        position{line: 309, col: 33, offset: 8557},
 									val:        "(",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 283, col: 37, offset: 8261},
+									pos:  position{line: 309, col: 37, offset: 8561},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 283, col: 41, offset: 8265},
+									pos:   position{line: 309, col: 41, offset: 8565},
 									label: "param",
 									expr: &ruleRefExpr{
-										pos:  position{line: 283, col: 47, offset: 8271},
+										pos:  position{line: 309, col: 47, offset: 8571},
 										name: "Number",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 283, col: 54, offset: 8278},
+									pos:  position{line: 309, col: 54, offset: 8578},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 283, col: 57, offset: 8281},
+									pos:        position{line: 309, col: 57, offset: 8581},
 									val:        ",",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 283, col: 61, offset: 8285},
+									pos:  position{line: 309, col: 61, offset: 8585},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 283, col: 64, offset: 8288},
+									pos:   position{line: 309, col: 64, offset: 8588},
 									label: "vector",
 									expr: &ruleRefExpr{
-										pos:  position{line: 283, col: 71, offset: 8295},
-										name: "VectorSelector",
+										pos:  position{line: 309, col: 71, offset: 8595},
+										name: "Expression",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 283, col: 86, offset: 8310},
+									pos:  position{line: 309, col: 82, offset: 8606},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 283, col: 89, offset: 8313},
+									pos:        position{line: 309, col: 85, offset: 8609},
 									val:        ")",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 283, col: 93, offset: 8317},
+									pos:  position{line: 309, col: 89, offset: 8613},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 283, col: 96, offset: 8320},
+									pos:   position{line: 309, col: 92, offset: 8616},
 									label: "group",
 									expr: &zeroOrOneExpr{
-										pos: position{line: 283, col: 102, offset: 8326},
+										pos: position{line: 309, col: 98, offset: 8622},
 										expr: &ruleRefExpr{
-											pos:  position{line: 283, col: 102, offset: 8326},
+											pos:  position{line: 309, col: 98, offset: 8622},
 											name: "AggregateGroup",
 										},
 									},
@@ -2662,82 +2704,82 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 289, col: 1, offset: 8467},
+						pos: position{line: 315, col: 1, offset: 8758},
 						run: (*parser).callonAggregateExpression62,
 						expr: &seqExpr{
-							pos: position{line: 289, col: 1, offset: 8467},
+							pos: position{line: 315, col: 1, offset: 8758},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 289, col: 1, offset: 8467},
+									pos:   position{line: 315, col: 1, offset: 8758},
 									label: "op",
 									expr: &ruleRefExpr{
-										pos:  position{line: 289, col: 4, offset: 8470},
+										pos:  position{line: 315, col: 4, offset: 8761},
 										name: "BinaryAggregateOperators",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 289, col: 30, offset: 8496},
+									pos:  position{line: 315, col: 30, offset: 8787},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 289, col: 33, offset: 8499},
+									pos:   position{line: 315, col: 33, offset: 8790},
 									label: "group",
 									expr: &zeroOrOneExpr{
-										pos: position{line: 289, col: 39, offset: 8505},
+										pos: position{line: 315, col: 39, offset: 8796},
 										expr: &ruleRefExpr{
-											pos:  position{line: 289, col: 39, offset: 8505},
+											pos:  position{line: 315, col: 39, offset: 8796},
 											name: "AggregateGroup",
 										},
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 289, col: 55, offset: 8521},
+									pos:  position{line: 315, col: 55, offset: 8812},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 289, col: 58, offset: 8524},
+									pos:        position{line: 315, col: 58, offset: 8815},
 									val:        "(",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 289, col: 62, offset: 8528},
+									pos:  position{line: 315, col: 62, offset: 8819},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 289, col: 66, offset: 8532},
+									pos:   position{line: 315, col: 66, offset: 8823},
 									label: "param",
 									expr: &ruleRefExpr{
-										pos:  position{line: 289, col: 72, offset: 8538},
+										pos:  position{line: 315, col: 72, offset: 8829},
 										name: "Number",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 289, col: 79, offset: 8545},
+									pos:  position{line: 315, col: 79, offset: 8836},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 289, col: 82, offset: 8548},
+									pos:        position{line: 315, col: 82, offset: 8839},
 									val:        ",",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 289, col: 86, offset: 8552},
+									pos:  position{line: 315, col: 86, offset: 8843},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 289, col: 89, offset: 8555},
+									pos:   position{line: 315, col: 89, offset: 8846},
 									label: "vector",
 									expr: &ruleRefExpr{
-										pos:  position{line: 289, col: 96, offset: 8562},
-										name: "VectorSelector",
+										pos:  position{line: 315, col: 96, offset: 8853},
+										name: "Expression",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 289, col: 111, offset: 8577},
+									pos:  position{line: 315, col: 107, offset: 8864},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 289, col: 114, offset: 8580},
+									pos:        position{line: 315, col: 110, offset: 8867},
 									val:        ")",
 									ignoreCase: false,
 								},
@@ -2745,60 +2787,60 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 295, col: 1, offset: 8709},
+						pos: position{line: 321, col: 1, offset: 8991},
 						run: (*parser).callonAggregateExpression82,
 						expr: &seqExpr{
-							pos: position{line: 295, col: 1, offset: 8709},
+							pos: position{line: 321, col: 1, offset: 8991},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 295, col: 1, offset: 8709},
+									pos:   position{line: 321, col: 1, offset: 8991},
 									label: "op",
 									expr: &ruleRefExpr{
-										pos:  position{line: 295, col: 4, offset: 8712},
+										pos:  position{line: 321, col: 4, offset: 8994},
 										name: "UnaryAggregateOperators",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 295, col: 29, offset: 8737},
+									pos:  position{line: 321, col: 29, offset: 9019},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 295, col: 32, offset: 8740},
+									pos:        position{line: 321, col: 32, offset: 9022},
 									val:        "(",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 295, col: 36, offset: 8744},
+									pos:  position{line: 321, col: 36, offset: 9026},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 295, col: 39, offset: 8747},
+									pos:   position{line: 321, col: 39, offset: 9029},
 									label: "vector",
 									expr: &ruleRefExpr{
-										pos:  position{line: 295, col: 46, offset: 8754},
-										name: "VectorSelector",
+										pos:  position{line: 321, col: 46, offset: 9036},
+										name: "Expression",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 295, col: 61, offset: 8769},
+									pos:  position{line: 321, col: 57, offset: 9047},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 295, col: 64, offset: 8772},
+									pos:        position{line: 321, col: 60, offset: 9050},
 									val:        ")",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 295, col: 68, offset: 8776},
+									pos:  position{line: 321, col: 64, offset: 9054},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 295, col: 71, offset: 8779},
+									pos:   position{line: 321, col: 67, offset: 9057},
 									label: "group",
 									expr: &zeroOrOneExpr{
-										pos: position{line: 295, col: 77, offset: 8785},
+										pos: position{line: 321, col: 73, offset: 9063},
 										expr: &ruleRefExpr{
-											pos:  position{line: 295, col: 77, offset: 8785},
+											pos:  position{line: 321, col: 73, offset: 9063},
 											name: "AggregateGroup",
 										},
 									},
@@ -2807,88 +2849,458 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 299, col: 1, offset: 8878},
+						pos: position{line: 325, col: 1, offset: 9151},
 						run: (*parser).callonAggregateExpression97,
 						expr: &seqExpr{
-							pos: position{line: 299, col: 1, offset: 8878},
+							pos: position{line: 325, col: 1, offset: 9151},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 299, col: 1, offset: 8878},
+									pos:   position{line: 325, col: 1, offset: 9151},
 									label: "op",
 									expr: &ruleRefExpr{
-										pos:  position{line: 299, col: 4, offset: 8881},
+										pos:  position{line: 325, col: 4, offset: 9154},
 										name: "UnaryAggregateOperators",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 299, col: 29, offset: 8906},
+									pos:  position{line: 325, col: 29, offset: 9179},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 299, col: 32, offset: 8909},
+									pos:   position{line: 325, col: 32, offset: 9182},
 									label: "group",
 									expr: &zeroOrOneExpr{
-										pos: position{line: 299, col: 38, offset: 8915},
+										pos: position{line: 325, col: 38, offset: 9188},
 										expr: &ruleRefExpr{
-											pos:  position{line: 299, col: 38, offset: 8915},
+											pos:  position{line: 325, col: 38, offset: 9188},
 											name: "AggregateGroup",
 										},
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 299, col: 54, offset: 8931},
+									pos:  position{line: 325, col: 54, offset: 9204},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 299, col: 57, offset: 8934},
+									pos:        position{line: 325, col: 57, offset: 9207},
 									val:        "(",
 									ignoreCase: false,
 								},
 								&ruleRefExpr{
-									pos:  position{line: 299, col: 61, offset: 8938},
+									pos:  position{line: 325, col: 61, offset: 9211},
 									name: "__",
 								},
 								&labeledExpr{
-									pos:   position{line: 299, col: 64, offset: 8941},
+									pos:   position{line: 325, col: 64, offset: 9214},
 									label: "vector",
 									expr: &ruleRefExpr{
-										pos:  position{line: 299, col: 71, offset: 8948},
-										name: "VectorSelector",
+										pos:  position{line: 325, col: 71, offset: 9221},
+										name: "Expression",
+									},
+								},
+								&ruleRefExpr{
+									pos:  position{line: 325, col: 82, offset: 9232},
+									name: "__",
+								},
+								&litMatcher{
+									pos:        position{line: 325, col: 85, offset: 9235},
+									val:        ")",
+									ignoreCase: false,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Expression",
+			pos:  position{line: 329, col: 1, offset: 9310},
+			expr: &ruleRefExpr{
+				pos:  position{line: 329, col: 14, offset: 9323},
+				name: "ComparisonExpression",
+			},
+		},
+		{
+			name: "ComparisonExpression",
+			pos:  position{line: 331, col: 1, offset: 9345},
+			expr: &actionExpr{
+				pos: position{line: 331, col: 24, offset: 9368},
+				run: (*parser).callonComparisonExpression1,
+				expr: &seqExpr{
+					pos: position{line: 331, col: 24, offset: 9368},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 331, col: 24, offset: 9368},
+							label: "head",
+							expr: &ruleRefExpr{
+								pos:  position{line: 331, col: 29, offset: 9373},
+								name: "AdditiveExpression",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 331, col: 48, offset: 9392},
+							label: "tail",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 331, col: 53, offset: 9397},
+								expr: &seqExpr{
+									pos: position{line: 331, col: 55, offset: 9399},
+									exprs: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 331, col: 55, offset: 9399},
+											name: "__",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 331, col: 58, offset: 9402},
+											name: "ComparisonOperators",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 331, col: 78, offset: 9422},
+											name: "__",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 331, col: 81, offset: 9425},
+											name: "AdditiveExpression",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "AdditiveExpression",
+			pos:  position{line: 335, col: 1, offset: 9490},
+			expr: &actionExpr{
+				pos: position{line: 335, col: 22, offset: 9511},
+				run: (*parser).callonAdditiveExpression1,
+				expr: &seqExpr{
+					pos: position{line: 335, col: 22, offset: 9511},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 335, col: 22, offset: 9511},
+							label: "head",
+							expr: &ruleRefExpr{
+								pos:  position{line: 335, col: 27, offset: 9516},
+								name: "MultiplicativeExpression",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 335, col: 52, offset: 9541},
+							label: "tail",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 335, col: 57, offset: 9546},
+								expr: &seqExpr{
+									pos: position{line: 335, col: 59, offset: 9548},
+									exprs: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 335, col: 59, offset: 9548},
+											name: "__",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 335, col: 62, offset: 9551},
+											name: "AdditiveOperators",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 335, col: 80, offset: 9569},
+											name: "__",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 335, col: 83, offset: 9572},
+											name: "MultiplicativeExpression",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MultiplicativeExpression",
+			pos:  position{line: 339, col: 1, offset: 9643},
+			expr: &actionExpr{
+				pos: position{line: 339, col: 28, offset: 9670},
+				run: (*parser).callonMultiplicativeExpression1,
+				expr: &seqExpr{
+					pos: position{line: 339, col: 28, offset: 9670},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 339, col: 28, offset: 9670},
+							label: "head",
+							expr: &ruleRefExpr{
+								pos:  position{line: 339, col: 33, offset: 9675},
+								name: "PowerExpression",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 339, col: 49, offset: 9691},
+							label: "tail",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 339, col: 54, offset: 9696},
+								expr: &seqExpr{
+									pos: position{line: 339, col: 56, offset: 9698},
+									exprs: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 339, col: 56, offset: 9698},
+											name: "__",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 339, col: 59, offset: 9701},
+											name: "MultiplicativeOperators",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 339, col: 83, offset: 9725},
+											name: "__",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 339, col: 86, offset: 9728},
+											name: "PowerExpression",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "PowerExpression",
+			pos:  position{line: 344, col: 1, offset: 9834},
+			expr: &choiceExpr{
+				pos: position{line: 344, col: 19, offset: 9852},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 344, col: 19, offset: 9852},
+						run: (*parser).callonPowerExpression2,
+						expr: &seqExpr{
+							pos: position{line: 344, col: 19, offset: 9852},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 344, col: 19, offset: 9852},
+									label: "base",
+									expr: &ruleRefExpr{
+										pos:  position{line: 344, col: 24, offset: 9857},
+										name: "OperandExpression",
+									},
+								},
+								&ruleRefExpr{
+									pos:  position{line: 344, col: 42, offset: 9875},
+									name: "__",
+								},
+								&litMatcher{
+									pos:        position{line: 344, col: 45, offset: 9878},
+									val:        "^",
+									ignoreCase: false,
+								},
+								&ruleRefExpr{
+									pos:  position{line: 344, col: 49, offset: 9882},
+									name: "__",
+								},
+								&labeledExpr{
+									pos:   position{line: 344, col: 52, offset: 9885},
+									label: "exp",
+									expr: &ruleRefExpr{
+										pos:  position{line: 344, col: 56, offset: 9889},
+										name: "PowerExpression",
+									},
+								},
+							},
+						},
+					},
+					&ruleRefExpr{
+						pos:  position{line: 346, col: 5, offset: 9954},
+						name: "OperandExpression",
+					},
+				},
+			},
+		},
+		{
+			name: "OperandExpression",
+			pos:  position{line: 348, col: 1, offset: 9973},
+			expr: &choiceExpr{
+				pos: position{line: 348, col: 21, offset: 9993},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 348, col: 21, offset: 9993},
+						run: (*parser).callonOperandExpression2,
+						expr: &seqExpr{
+							pos: position{line: 348, col: 21, offset: 9993},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 348, col: 21, offset: 9993},
+									val:        "(",
+									ignoreCase: false,
+								},
+								&ruleRefExpr{
+									pos:  position{line: 348, col: 25, offset: 9997},
+									name: "__",
+								},
+								&labeledExpr{
+									pos:   position{line: 348, col: 28, offset: 10000},
+									label: "expr",
+									expr: &ruleRefExpr{
+										pos:  position{line: 348, col: 33, offset: 10005},
+										name: "Expression",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 299, col: 86, offset: 8963},
+									pos:  position{line: 348, col: 44, offset: 10016},
 									name: "__",
 								},
 								&litMatcher{
-									pos:        position{line: 299, col: 89, offset: 8966},
+									pos:        position{line: 348, col: 47, offset: 10019},
 									val:        ")",
 									ignoreCase: false,
 								},
 							},
 						},
 					},
+					&ruleRefExpr{
+						pos:  position{line: 350, col: 5, offset: 10050},
+						name: "AggregateExpression",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 350, col: 27, offset: 10072},
+						name: "FunctionCall",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 350, col: 42, offset: 10087},
+						name: "Number",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 350, col: 51, offset: 10096},
+						name: "VectorSelector",
+					},
+				},
+			},
+		},
+		{
+			name: "FunctionCall",
+			pos:  position{line: 352, col: 1, offset: 10112},
+			expr: &actionExpr{
+				pos: position{line: 352, col: 16, offset: 10127},
+				run: (*parser).callonFunctionCall1,
+				expr: &seqExpr{
+					pos: position{line: 352, col: 16, offset: 10127},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 352, col: 16, offset: 10127},
+							label: "fn",
+							expr: &ruleRefExpr{
+								pos:  position{line: 352, col: 19, offset: 10130},
+								name: "Identifier",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 352, col: 30, offset: 10141},
+							name: "__",
+						},
+						&litMatcher{
+							pos:        position{line: 352, col: 33, offset: 10144},
+							val:        "(",
+							ignoreCase: false,
+						},
+						&ruleRefExpr{
+							pos:  position{line: 352, col: 37, offset: 10148},
+							name: "__",
+						},
+						&labeledExpr{
+							pos:   position{line: 352, col: 40, offset: 10151},
+							label: "args",
+							expr: &zeroOrOneExpr{
+								pos: position{line: 352, col: 45, offset: 10156},
+								expr: &ruleRefExpr{
+									pos:  position{line: 352, col: 45, offset: 10156},
+									name: "FunctionArgs",
+								},
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 352, col: 59, offset: 10170},
+							name: "__",
+						},
+						&litMatcher{
+							pos:        position{line: 352, col: 62, offset: 10173},
+							val:        ")",
+							ignoreCase: false,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "FunctionArgs",
+			pos:  position{line: 356, col: 1, offset: 10225},
+			expr: &actionExpr{
+				pos: position{line: 356, col: 16, offset: 10240},
+				run: (*parser).callonFunctionArgs1,
+				expr: &seqExpr{
+					pos: position{line: 356, col: 16, offset: 10240},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 356, col: 16, offset: 10240},
+							label: "first",
+							expr: &ruleRefExpr{
+								pos:  position{line: 356, col: 22, offset: 10246},
+								name: "Expression",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 356, col: 33, offset: 10257},
+							label: "rest",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 356, col: 38, offset: 10262},
+								expr: &seqExpr{
+									pos: position{line: 356, col: 40, offset: 10264},
+									exprs: []interface{}{
+										&ruleRefExpr{
+											pos:  position{line: 356, col: 40, offset: 10264},
+											name: "__",
+										},
+										&litMatcher{
+											pos:        position{line: 356, col: 43, offset: 10267},
+											val:        ",",
+											ignoreCase: false,
+										},
+										&ruleRefExpr{
+											pos:  position{line: 356, col: 47, offset: 10271},
+											name: "__",
+										},
+										&ruleRefExpr{
+											pos:  position{line: 356, col: 50, offset: 10274},
+											name: "Expression",
+										},
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		{
 			name: "__",
-			pos:  position{line: 303, col: 1, offset: 9046},
+			pos:  position{line: 360, col: 1, offset: 10329},
 			expr: &zeroOrMoreExpr{
-				pos: position{line: 303, col: 6, offset: 9051},
+				pos: position{line: 360, col: 6, offset: 10334},
 				expr: &choiceExpr{
-					pos: position{line: 303, col: 8, offset: 9053},
+					pos: position{line: 360, col: 8, offset: 10336},
 					alternatives: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 303, col: 8, offset: 9053},
+							pos:  position{line: 360, col: 8, offset: 10336},
 							name: "Whitespace",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 303, col: 21, offset: 9066},
+							pos:  position{line: 360, col: 21, offset: 10349},
 							name: "EOL",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 303, col: 27, offset: 9072},
+							pos:  position{line: 360, col: 27, offset: 10355},
 							name: "Comment",
 						},
 					},
@@ -2897,20 +3309,20 @@ var g = &grammar{
 		},
 		{
 			name: "_",
-			pos:  position{line: 304, col: 1, offset: 9083},
+			pos:  position{line: 361, col: 1, offset: 10366},
 			expr: &zeroOrMoreExpr{
-				pos: position{line: 304, col: 5, offset: 9087},
+				pos: position{line: 361, col: 5, offset: 10370},
 				expr: &ruleRefExpr{
-					pos:  position{line: 304, col: 5, offset: 9087},
+					pos:  position{line: 361, col: 5, offset: 10370},
 					name: "Whitespace",
 				},
 			},
 		},
 		{
 			name: "Whitespace",
-			pos:  position{line: 306, col: 1, offset: 9100},
+			pos:  position{line: 363, col: 1, offset: 10383},
 			expr: &charClassMatcher{
-				pos:        position{line: 306, col: 14, offset: 9113},
+				pos:        position{line: 363, col: 14, offset: 10396},
 				val:        "[ \\t\\r]",
 				chars:      []rune{' ', '\t', '\r'},
 				ignoreCase: false,
@@ -2919,62 +3331,62 @@ var g = &grammar{
 		},
 		{
 			name: "EOL",
-			pos:  position{line: 307, col: 1, offset: 9121},
+			pos:  position{line: 364, col: 1, offset: 10404},
 			expr: &litMatcher{
-				pos:        position{line: 307, col: 7, offset: 9127},
+				pos:        position{line: 364, col: 7, offset: 10410},
 				val:        "\n",
 				ignoreCase: false,
 			},
 		},
 		{
 			name: "EOS",
-			pos:  position{line: 308, col: 1, offset: 9132},
+			pos:  position{line: 365, col: 1, offset: 10415},
 			expr: &choiceExpr{
-				pos: position{line: 308, col: 7, offset: 9138},
+				pos: position{line: 365, col: 7, offset: 10421},
 				alternatives: []interface{}{
 					&seqExpr{
-						pos: position{line: 308, col: 7, offset: 9138},
+						pos: position{line: 365, col: 7, offset: 10421},
 						exprs: []interface{}{
 							&ruleRefExpr{
-								pos:  position{line: 308, col: 7, offset: 9138},
+								pos:  position{line: 365, col: 7, offset: 10421},
 								name: "__",
 							},
 							&litMatcher{
-								pos:        position{line: 308, col: 10, offset: 9141},
+								pos:        position{line: 365, col: 10, offset: 10424},
 								val:        ";",
 								ignoreCase: false,
 							},
 						},
 					},
 					&seqExpr{
-						pos: position{line: 308, col: 16, offset: 9147},
+						pos: position{line: 365, col: 16, offset: 10430},
 						exprs: []interface{}{
 							&ruleRefExpr{
-								pos:  position{line: 308, col: 16, offset: 9147},
+								pos:  position{line: 365, col: 16, offset: 10430},
 								name: "_",
 							},
 							&zeroOrOneExpr{
-								pos: position{line: 308, col: 18, offset: 9149},
+								pos: position{line: 365, col: 18, offset: 10432},
 								expr: &ruleRefExpr{
-									pos:  position{line: 308, col: 18, offset: 9149},
+									pos:  position{line: 365, col: 18, offset: 10432},
 									name: "SingleLineComment",
 								},
 							},
 							&ruleRefExpr{
-								pos:  position{line: 308, col: 37, offset: 9168},
+								pos:  position{line: 365, col: 37, offset: 10451},
 								name: "EOL",
 							},
 						},
 					},
 					&seqExpr{
-						pos: position{line: 308, col: 43, offset: 9174},
+						pos: position{line: 365, col: 43, offset: 10457},
 						exprs: []interface{}{
 							&ruleRefExpr{
-								pos:  position{line: 308, col: 43, offset: 9174},
+								pos:  position{line: 365, col: 43, offset: 10457},
 								name: "__",
 							},
 							&ruleRefExpr{
-								pos:  position{line: 308, col: 46, offset: 9177},
+								pos:  position{line: 365, col: 46, offset: 10460},
 								name: "EOF",
 							},
 						},
@@ -2984,11 +3396,11 @@ var g = &grammar{
 		},
 		{
 			name: "EOF",
-			pos:  position{line: 310, col: 1, offset: 9182},
+			pos:  position{line: 367, col: 1, offset: 10465},
 			expr: &notExpr{
-				pos: position{line: 310, col: 7, offset: 9188},
+				pos: position{line: 367, col: 7, offset: 10471},
 				expr: &anyMatcher{
-					line: 310, col: 8, offset: 9189,
+					line: 367, col: 8, offset: 10472,
 				},
 			},
 		},
@@ -3360,6 +3772,116 @@ func (p *parser) callonDuration1() (interface{}, error) {
 	return p.cur.onDuration1(stack["dur"], stack["units"])
 }
 
+func (c *current) onComparisonOperators2() (interface{}, error) {
+	return OpEqual, nil
+}
+
+func (p *parser) callonComparisonOperators2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onComparisonOperators2()
+}
+
+func (c *current) onComparisonOperators4() (interface{}, error) {
+	return OpNotEqual, nil
+}
+
+func (p *parser) callonComparisonOperators4() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onComparisonOperators4()
+}
+
+func (c *current) onComparisonOperators6() (interface{}, error) {
+	return OpGreaterEqual, nil
+}
+
+func (p *parser) callonComparisonOperators6() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onComparisonOperators6()
+}
+
+func (c *current) onComparisonOperators8() (interface{}, error) {
+	return OpLessEqual, nil
+}
+
+func (p *parser) callonComparisonOperators8() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onComparisonOperators8()
+}
+
+func (c *current) onComparisonOperators10() (interface{}, error) {
+	return OpGreater, nil
+}
+
+func (p *parser) callonComparisonOperators10() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onComparisonOperators10()
+}
+
+func (c *current) onComparisonOperators12() (interface{}, error) {
+	return OpLess, nil
+}
+
+func (p *parser) callonComparisonOperators12() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onComparisonOperators12()
+}
+
+func (c *current) onAdditiveOperators2() (interface{}, error) {
+	return OpAdd, nil
+}
+
+func (p *parser) callonAdditiveOperators2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onAdditiveOperators2()
+}
+
+func (c *current) onAdditiveOperators4() (interface{}, error) {
+	return OpSub, nil
+}
+
+func (p *parser) callonAdditiveOperators4() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onAdditiveOperators4()
+}
+
+func (c *current) onMultiplicativeOperators2() (interface{}, error) {
+	return OpMul, nil
+}
+
+func (p *parser) callonMultiplicativeOperators2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMultiplicativeOperators2()
+}
+
+func (c *current) onMultiplicativeOperators4() (interface{}, error) {
+	return OpDiv, nil
+}
+
+func (p *parser) callonMultiplicativeOperators4() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMultiplicativeOperators4()
+}
+
+func (c *current) onMultiplicativeOperators6() (interface{}, error) {
+	return OpMod, nil
+}
+
+func (p *parser) callonMultiplicativeOperators6() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMultiplicativeOperators6()
+}
+
 func (c *current) onLabelOperators2() (interface{}, error) {
 	return NotEqual, nil
 }
@@ -3555,7 +4077,7 @@ func (p *parser) callonAggregateWithout1() (interface{}, error) {
 func (c *current) onAggregateExpression2(op, param, vector, group interface{}) (interface{}, error) {
 	oper := op.(*Operator)
 	oper.Arg = param.(*StringLiteral)
-	return NewAggregateExpr(oper, vector.(*Selector), group)
+	return NewAggregateExpr(oper, vector.(Expr), group)
 }
 
 func (p *parser) callonAggregateExpression2() (interface{}, error) {
@@ -3567,7 +4089,7 @@ func (p *parser) callonAggregateExpression2() (interface{}, error) {
 func (c *current) onAggregateExpression22(op, group, param, vector interface{}) (interface{}, error) {
 	oper := op.(*Operator)
 	oper.Arg = param.(*StringLiteral)
-	return NewAggregateExpr(oper, vector.(*Selector), group)
+	return NewAggregateExpr(oper, vector.(Expr), group)
 }
 
 func (p *parser) callonAggregateExpression22() (interface{}, error) {
@@ -3579,7 +4101,7 @@ func (p *parser) callonAggregateExpression22() (interface{}, error) {
 func (c *current) onAggregateExpression42(op, param, vector, group interface{}) (interface{}, error) {
 	oper := op.(*Operator)
 	oper.Arg = param.(*Number)
-	return NewAggregateExpr(oper, vector.(*Selector), group)
+	return NewAggregateExpr(oper, vector.(Expr), group)
 }
 
 func (p *parser) callonAggregateExpression42() (interface{}, error) {
@@ -3591,7 +4113,7 @@ func (p *parser) callonAggregateExpression42() (interface{}, error) {
 func (c *current) onAggregateExpression62(op, group, param, vector interface{}) (interface{}, error) {
 	oper := op.(*Operator)
 	oper.Arg = param.(*Number)
-	return NewAggregateExpr(oper, vector.(*Selector), group)
+	return NewAggregateExpr(oper, vector.(Expr), group)
 }
 
 func (p *parser) callonAggregateExpression62() (interface{}, error) {
@@ -3601,7 +4123,7 @@ func (p *parser) callonAggregateExpression62() (interface{}, error) {
 }
 
 func (c *current) onAggregateExpression82(op, vector, group interface{}) (interface{}, error) {
-	return NewAggregateExpr(op.(*Operator), vector.(*Selector), group)
+	return NewAggregateExpr(op.(*Operator), vector.(Expr), group)
 }
 
 func (p *parser) callonAggregateExpression82() (interface{}, error) {
@@ -3611,7 +4133,7 @@ func (p *parser) callonAggregateExpression82() (interface{}, error) {
 }
 
 func (c *current) onAggregateExpression97(op, group, vector interface{}) (interface{}, error) {
-	return NewAggregateExpr(op.(*Operator), vector.(*Selector), group)
+	return NewAggregateExpr(op.(*Operator), vector.(Expr), group)
 }
 
 func (p *parser) callonAggregateExpression97() (interface{}, error) {
@@ -3620,6 +4142,76 @@ func (p *parser) callonAggregateExpression97() (interface{}, error) {
 	return p.cur.onAggregateExpression97(stack["op"], stack["group"], stack["vector"])
 }
 
+func (c *current) onComparisonExpression1(head, tail interface{}) (interface{}, error) {
+	return NewBinaryExprs(head, tail)
+}
+
+func (p *parser) callonComparisonExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onComparisonExpression1(stack["head"], stack["tail"])
+}
+
+func (c *current) onAdditiveExpression1(head, tail interface{}) (interface{}, error) {
+	return NewBinaryExprs(head, tail)
+}
+
+func (p *parser) callonAdditiveExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onAdditiveExpression1(stack["head"], stack["tail"])
+}
+
+func (c *current) onMultiplicativeExpression1(head, tail interface{}) (interface{}, error) {
+	return NewBinaryExprs(head, tail)
+}
+
+func (p *parser) callonMultiplicativeExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMultiplicativeExpression1(stack["head"], stack["tail"])
+}
+
+func (c *current) onPowerExpression2(base, exp interface{}) (interface{}, error) {
+	return NewBinaryExpr(OpPow, base, exp)
+}
+
+func (p *parser) callonPowerExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onPowerExpression2(stack["base"], stack["exp"])
+}
+
+func (c *current) onOperandExpression2(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonOperandExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onOperandExpression2(stack["expr"])
+}
+
+func (c *current) onFunctionCall1(fn, args interface{}) (interface{}, error) {
+	return NewCall(fn.(*Identifier), args)
+}
+
+func (p *parser) callonFunctionCall1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionCall1(stack["fn"], stack["args"])
+}
+
+func (c *current) onFunctionArgs1(first, rest interface{}) (interface{}, error) {
+	return NewExprList(first, rest)
+}
+
+func (p *parser) callonFunctionArgs1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionArgs1(stack["first"], stack["rest"])
+}
+
 var (
 	// errNoRule is returned when the grammar to parse has no rule.
 	errNoRule = errors.New("grammar has no rule")
@@ -3678,18 +4270,17 @@ func Entrypoint(ruleName string) Option {
 //
 // Example usage:
 //
-//     input := "input"
-//     stats := Stats{}
-//     _, err := Parse("input-file", []byte(input), Statistics(&stats, "no match"))
-//     if err != nil {
-//         log.Panicln(err)
-//     }
-//     b, err := json.MarshalIndent(stats.ChoiceAltCnt, "", "  ")
-//     if err != nil {
-//         log.Panicln(err)
-//     }
-//     fmt.Println(string(b))
-//
+//	input := "input"
+//	stats := Stats{}
+//	_, err := Parse("input-file", []byte(input), Statistics(&stats, "no match"))
+//	if err != nil {
+//	    log.Panicln(err)
+//	}
+//	b, err := json.MarshalIndent(stats.ChoiceAltCnt, "", "  ")
+//	if err != nil {
+//	    log.Panicln(err)
+//	}
+//	fmt.Println(string(b))
 func Statistics(stats *Stats, choiceNoMatch string) Option {
 	return func(p *parser) Option {
 		oldStats := p.Stats
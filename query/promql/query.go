@@ -2,8 +2,6 @@
 package promql
 
 import (
-	"fmt"
-
 	"github.com/influxdata/flux"
 )
 
@@ -15,14 +13,7 @@ func ParsePromQL(promql string, opts ...Option) (interface{}, error) {
 	return f, nil
 }
 
+// Build parses promql and builds a flux query specification evaluating it now.
 func Build(promql string, opts ...Option) (*flux.Spec, error) {
-	parsed, err := ParsePromQL(promql, opts...)
-	if err != nil {
-		return nil, err
-	}
-	builder, ok := parsed.(QueryBuilder)
-	if !ok {
-		return nil, fmt.Errorf("unable to build as %t is not a QueryBuilder", parsed)
-	}
-	return builder.QuerySpec()
+	return BuildEvaluation(promql, Evaluation{}, opts...)
 }
@@ -8,10 +8,14 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/semantic/semantictest"
 	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
+	_ "github.com/influxdata/influxdb/query/builtin"
 	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 )
 
@@ -182,7 +186,7 @@ func TestParsePromQL(t *testing.T) {
 				Op: &Operator{
 					Kind: MinKind,
 				},
-				Selector: &Selector{
+				Expr: &Selector{
 					Name: "some_metric",
 				},
 				Aggregate: &Aggregate{
@@ -202,7 +206,7 @@ func TestParsePromQL(t *testing.T) {
 				Op: &Operator{
 					Kind: CountKind,
 				},
-				Selector: &Selector{
+				Expr: &Selector{
 					Name: "some_metric",
 				},
 				Aggregate: &Aggregate{
@@ -222,7 +226,7 @@ func TestParsePromQL(t *testing.T) {
 				Op: &Operator{
 					Kind: AvgKind,
 				},
-				Selector: &Selector{
+				Expr: &Selector{
 					Name: "some_metric",
 				},
 				Aggregate: &Aggregate{
@@ -242,7 +246,7 @@ func TestParsePromQL(t *testing.T) {
 				Op: &Operator{
 					Kind: SumKind,
 				},
-				Selector: &Selector{
+				Expr: &Selector{
 					Name: "some_metric",
 				},
 				Aggregate: &Aggregate{
@@ -265,7 +269,7 @@ func TestParsePromQL(t *testing.T) {
 				Op: &Operator{
 					Kind: SumKind,
 				},
-				Selector: &Selector{
+				Expr: &Selector{
 					Name: "some_metric",
 				},
 				Aggregate: &Aggregate{
@@ -285,7 +289,7 @@ func TestParsePromQL(t *testing.T) {
 				Op: &Operator{
 					Kind: SumKind,
 				},
-				Selector: &Selector{
+				Expr: &Selector{
 					Name: "some_metric",
 				},
 				Aggregate: &Aggregate{
@@ -308,7 +312,7 @@ func TestParsePromQL(t *testing.T) {
 						String: "version",
 					},
 				},
-				Selector: &Selector{
+				Expr: &Selector{
 					Name: "build_version",
 				},
 			},
@@ -320,7 +324,7 @@ func TestParsePromQL(t *testing.T) {
 				Op: &Operator{
 					Kind: SumKind,
 				},
-				Selector: &Selector{
+				Expr: &Selector{
 					Name:  "node_cpu",
 					Range: 170 * time.Hour,
 					LabelMatchers: []*LabelMatcher{
@@ -335,6 +339,141 @@ func TestParsePromQL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "rate of a range",
+			promql: `rate(http_requests_total{job="api"}[5m])`,
+			want: &Call{
+				Func: "rate",
+				Args: []Expr{
+					&Selector{
+						Name:  "http_requests_total",
+						Range: 5 * time.Minute,
+						LabelMatchers: []*LabelMatcher{
+							{
+								Name: "job",
+								Kind: Equal,
+								Value: &StringLiteral{
+									String: "api",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "sum of rates by label",
+			promql: `sum by (job) (rate(http_requests_total[5m]))`,
+			want: &AggregateExpr{
+				Op: &Operator{
+					Kind: SumKind,
+				},
+				Expr: &Call{
+					Func: "rate",
+					Args: []Expr{
+						&Selector{
+							Name:  "http_requests_total",
+							Range: 5 * time.Minute,
+						},
+					},
+				},
+				Aggregate: &Aggregate{
+					By: true,
+					Labels: []*Identifier{
+						{
+							Name: "job",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "histogram quantile",
+			promql: `histogram_quantile(0.9, rate(request_duration_bucket[10m]))`,
+			want: &Call{
+				Func: "histogram_quantile",
+				Args: []Expr{
+					&Number{
+						Val: 0.9,
+					},
+					&Call{
+						Func: "rate",
+						Args: []Expr{
+							&Selector{
+								Name:  "request_duration_bucket",
+								Range: 10 * time.Minute,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "binary operator precedence",
+			promql: `a + b * c`,
+			want: &BinaryExpr{
+				Op:  OpAdd,
+				LHS: &Selector{Name: "a"},
+				RHS: &BinaryExpr{
+					Op:  OpMul,
+					LHS: &Selector{Name: "b"},
+					RHS: &Selector{Name: "c"},
+				},
+			},
+		},
+		{
+			name:   "parenthesized binary operators are left associative",
+			promql: `(a - b) - c`,
+			want: &BinaryExpr{
+				Op: OpSub,
+				LHS: &BinaryExpr{
+					Op:  OpSub,
+					LHS: &Selector{Name: "a"},
+					RHS: &Selector{Name: "b"},
+				},
+				RHS: &Selector{Name: "c"},
+			},
+		},
+		{
+			name:   "power is right associative",
+			promql: `a ^ 2 ^ 3`,
+			want: &BinaryExpr{
+				Op:  OpPow,
+				LHS: &Selector{Name: "a"},
+				RHS: &Number{Val: 8},
+			},
+		},
+		{
+			name:   "comparison with a scalar",
+			promql: `up{job="api"} == 0`,
+			want: &BinaryExpr{
+				Op: OpEqual,
+				LHS: &Selector{
+					Name: "up",
+					LabelMatchers: []*LabelMatcher{
+						{
+							Name: "job",
+							Kind: Equal,
+							Value: &StringLiteral{
+								String: "api",
+							},
+						},
+					},
+				},
+				RHS: &Number{Val: 0},
+			},
+		},
+		{
+			name:   "scalar arithmetic is folded",
+			promql: `1 + 2 * 3`,
+			want:   &Number{Val: 7},
+		},
+		{
+			name:    "scalar comparison",
+			promql:  `1 > 2`,
+			wantErr: true,
+			want:    "",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -350,6 +489,45 @@ func TestParsePromQL(t *testing.T) {
 	}
 }
 
+// where returns the filter of node_cpu with the label matchers given
+// as pairs of label names and values, e.g. where("mode", "user").
+func where(labels ...string) interpreter.ResolvedFunction {
+	equal := func(property, value string) semantic.Expression {
+		return &semantic.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left: &semantic.MemberExpression{
+				Object: &semantic.IdentifierExpression{
+					Name: "r",
+				},
+				Property: property,
+			},
+			Right: &semantic.StringLiteral{
+				Value: value,
+			},
+		}
+	}
+
+	body := equal("_field", "node_cpu")
+	for i := 0; i+1 < len(labels); i += 2 {
+		body = &semantic.LogicalExpression{
+			Operator: ast.AndOperator,
+			Left:     body,
+			Right:    equal(labels[i], labels[i+1]),
+		}
+	}
+	return interpreter.ResolvedFunction{
+		Scope: nil,
+		Fn: &semantic.FunctionExpression{
+			Block: &semantic.FunctionBlock{
+				Parameters: &semantic.FunctionParameters{
+					List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
+				},
+				Body: body,
+			},
+		},
+	}
+}
+
 func TestBuild(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -364,230 +542,140 @@ func TestBuild(t *testing.T) {
 			want: &flux.Spec{
 				Operations: []*flux.Operation{
 					{
-						ID:   flux.OperationID("from"),
+						ID:   "influxDBFrom",
 						Spec: &influxdb.FromOpSpec{Bucket: "prometheus"},
 					},
 					{
-						ID: "where",
-						Spec: &universe.FilterOpSpec{
-							Fn: interpreter.ResolvedFunction{
-								Scope: nil,
-								Fn: &semantic.FunctionExpression{
-									Block: &semantic.FunctionBlock{
-										Parameters: &semantic.FunctionParameters{
-											List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
-										},
-										Body: &semantic.LogicalExpression{
-											Operator: ast.AndOperator,
-											Left: &semantic.LogicalExpression{
-												Operator: ast.AndOperator,
-												Left: &semantic.BinaryExpression{
-													Operator: ast.EqualOperator,
-													Left: &semantic.MemberExpression{
-														Object: &semantic.IdentifierExpression{
-															Name: "r",
-														},
-														Property: "_metric",
-													},
-													Right: &semantic.StringLiteral{
-														Value: "node_cpu",
-													},
-												},
-												Right: &semantic.BinaryExpression{
-													Operator: ast.EqualOperator,
-													Left: &semantic.MemberExpression{
-														Object: &semantic.IdentifierExpression{
-															Name: "r",
-														},
-														Property: "mode",
-													},
-													Right: &semantic.StringLiteral{
-														Value: "user",
-													},
-												},
-											},
-											Right: &semantic.BinaryExpression{
-												Operator: ast.EqualOperator,
-												Left: &semantic.MemberExpression{
-													Object: &semantic.IdentifierExpression{
-														Name: "r",
-													},
-													Property: "cpu",
-												},
-												Right: &semantic.StringLiteral{
-													Value: "cpu2",
-												},
-											},
-										},
-									},
-								},
-							},
+						ID: "range",
+						Spec: &universe.RangeOpSpec{
+							Start:       flux.Time{IsRelative: true, Relative: -5*time.Minute + time.Nanosecond},
+							Stop:        flux.Time{IsRelative: true, Relative: time.Nanosecond},
+							TimeColumn:  "_time",
+							StartColumn: "_start",
+							StopColumn:  "_stop",
 						},
 					},
 					{
-						ID: flux.OperationID("count"), Spec: &universe.CountOpSpec{},
+						ID:   "filter",
+						Spec: &universe.FilterOpSpec{Fn: where("mode", "user", "cpu", "cpu2")},
 					},
-				},
-				Edges: []flux.Edge{
 					{
-						Parent: flux.OperationID("from"),
-						Child:  flux.OperationID("where"),
+						ID:   "last",
+						Spec: &universe.LastOpSpec{SelectorConfig: execute.SelectorConfig{Column: "_value"}},
 					},
 					{
-						Parent: flux.OperationID("where"),
-						Child:  flux.OperationID("count"),
+						ID:   "duplicate",
+						Spec: &universe.DuplicateOpSpec{Column: "_stop", As: "_time"},
 					},
-				},
-			},
-		},
-		{
-			name:   "range of time but no aggregates",
-			promql: `node_cpu{mode="user"}[2m] offset 5m`,
-			want: &flux.Spec{
-				Operations: []*flux.Operation{
 					{
-						ID:   flux.OperationID("from"),
-						Spec: &influxdb.FromOpSpec{Bucket: "prometheus"},
+						ID: "timeShift",
+						Spec: &universe.ShiftOpSpec{
+							Shift:   values.ConvertDuration(-time.Nanosecond),
+							Columns: []string{"_time"},
+						},
 					},
 					{
-						ID: flux.OperationID("range"),
-						Spec: &universe.RangeOpSpec{
-							Start: flux.Time{Relative: -time.Minute * 7},
-						},
+						ID:   "drop",
+						Spec: &universe.DropOpSpec{Columns: []string{"_start", "_stop", "_measurement"}},
+					},
+					{
+						ID:   "keep",
+						Spec: &universe.KeepOpSpec{Columns: []string{"_time", "_value"}},
 					},
 					{
-						ID: "where",
-						Spec: &universe.FilterOpSpec{
+						ID:   "group",
+						Spec: &universe.GroupOpSpec{Mode: "by", Columns: []string{"_time"}},
+					},
+					{
+						ID: "map",
+						Spec: &universe.MapOpSpec{
 							Fn: interpreter.ResolvedFunction{
-								Scope: nil,
 								Fn: &semantic.FunctionExpression{
 									Block: &semantic.FunctionBlock{
 										Parameters: &semantic.FunctionParameters{
 											List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
 										},
-										Body: &semantic.LogicalExpression{
-											Operator: ast.AndOperator,
-											Left: &semantic.BinaryExpression{
-												Operator: ast.EqualOperator,
-												Left: &semantic.MemberExpression{
-													Object: &semantic.IdentifierExpression{
-														Name: "r",
-													},
-													Property: "_metric",
-												},
-												Right: &semantic.StringLiteral{
-													Value: "node_cpu",
-												},
-											},
-											Right: &semantic.BinaryExpression{
-												Operator: ast.EqualOperator,
-												Left: &semantic.MemberExpression{
-													Object: &semantic.IdentifierExpression{
-														Name: "r",
-													},
-													Property: "mode",
-												},
-												Right: &semantic.StringLiteral{
-													Value: "user",
-												},
-											},
+										Body: &semantic.ObjectExpression{
+											With: &semantic.IdentifierExpression{Name: "r"},
+											Properties: []*semantic.Property{{
+												Key:   &semantic.Identifier{Name: "_value"},
+												Value: &semantic.FloatLiteral{Value: 1},
+											}},
 										},
 									},
 								},
 							},
 						},
 					},
-				},
-				Edges: []flux.Edge{
 					{
-						Parent: flux.OperationID("from"),
-						Child:  flux.OperationID("range"),
+						ID:   "sum",
+						Spec: &universe.SumOpSpec{AggregateConfig: execute.AggregateConfig{Columns: []string{"_value"}}},
 					},
 					{
-						Parent: flux.OperationID("range"),
-						Child:  flux.OperationID("where"),
+						ID:   "group1",
+						Spec: &universe.GroupOpSpec{Mode: "except", Columns: []string{"_time", "_value"}},
 					},
 				},
+				Edges: []flux.Edge{
+					{Parent: "influxDBFrom", Child: "range"},
+					{Parent: "range", Child: "filter"},
+					{Parent: "filter", Child: "last"},
+					{Parent: "last", Child: "duplicate"},
+					{Parent: "duplicate", Child: "timeShift"},
+					{Parent: "timeShift", Child: "drop"},
+					{Parent: "drop", Child: "keep"},
+					{Parent: "keep", Child: "group"},
+					{Parent: "group", Child: "map"},
+					{Parent: "map", Child: "sum"},
+					{Parent: "sum", Child: "group1"},
+				},
 			},
 		},
-
 		{
-			name:   "sum over a range",
-			promql: `sum(node_cpu{_measurement="m0"}[170h])`,
+			name:   "range of time but no aggregates",
+			promql: `node_cpu{mode="user"}[2m] offset 5m`,
 			want: &flux.Spec{
 				Operations: []*flux.Operation{
 					{
-						ID:   flux.OperationID("from"),
+						ID:   "influxDBFrom",
 						Spec: &influxdb.FromOpSpec{Bucket: "prometheus"},
 					},
 					{
-						ID: flux.OperationID("range"),
+						ID: "range",
 						Spec: &universe.RangeOpSpec{
-							Start: flux.Time{Relative: -170 * time.Hour},
+							Start:       flux.Time{IsRelative: true, Relative: -7*time.Minute + time.Nanosecond},
+							Stop:        flux.Time{IsRelative: true, Relative: -5*time.Minute + time.Nanosecond},
+							TimeColumn:  "_time",
+							StartColumn: "_start",
+							StopColumn:  "_stop",
 						},
 					},
 					{
-						ID: "where",
-						Spec: &universe.FilterOpSpec{
-							Fn: interpreter.ResolvedFunction{
-								Scope: nil,
-								Fn: &semantic.FunctionExpression{
-									Block: &semantic.FunctionBlock{
-										Parameters: &semantic.FunctionParameters{
-											List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
-										},
-										Body: &semantic.LogicalExpression{
-											Operator: ast.AndOperator,
-											Left: &semantic.BinaryExpression{
-												Operator: ast.EqualOperator,
-												Left: &semantic.MemberExpression{
-													Object: &semantic.IdentifierExpression{
-														Name: "r",
-													},
-													Property: "_metric",
-												},
-												Right: &semantic.StringLiteral{
-													Value: "node_cpu",
-												},
-											},
-											Right: &semantic.BinaryExpression{
-												Operator: ast.EqualOperator,
-												Left: &semantic.MemberExpression{
-													Object: &semantic.IdentifierExpression{
-														Name: "r",
-													},
-													Property: "_measurement",
-												},
-												Right: &semantic.StringLiteral{
-													Value: "m0",
-												},
-											},
-										},
-									},
-								},
-							},
-						},
+						ID:   "filter",
+						Spec: &universe.FilterOpSpec{Fn: where("mode", "user")},
 					},
 					{
-						ID: flux.OperationID("sum"), Spec: &universe.SumOpSpec{},
+						ID:   "drop",
+						Spec: &universe.DropOpSpec{Columns: []string{"_start", "_stop", "_measurement"}},
 					},
 				},
 				Edges: []flux.Edge{
-					{
-						Parent: flux.OperationID("from"),
-						Child:  flux.OperationID("range"),
-					},
-					{
-						Parent: flux.OperationID("range"),
-						Child:  flux.OperationID("where"),
-					},
-					{
-						Parent: flux.OperationID("where"),
-						Child:  flux.OperationID("sum"),
-					},
+					{Parent: "influxDBFrom", Child: "range"},
+					{Parent: "range", Child: "filter"},
+					{Parent: "filter", Child: "drop"},
 				},
 			},
 		},
+		{
+			name:    "sum over a range",
+			promql:  `sum(node_cpu{_measurement="m0"}[170h])`,
+			wantErr: true,
+		},
+		{
+			name:    "comment",
+			promql:  `# node_cpu`,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -597,10 +685,147 @@ func TestBuild(t *testing.T) {
 				t.Errorf("Build() %s error = %v, wantErr %v", tt.promql, err, tt.wantErr)
 				return
 			}
-			opts := append(semantictest.CmpOptions, []cmp.Option{cmp.AllowUnexported(flux.Spec{}), cmpopts.IgnoreUnexported(flux.Spec{})}...)
+			opts := append(semantictest.CmpOptions, []cmp.Option{
+				cmp.AllowUnexported(flux.Spec{}, values.Duration{}),
+				cmpopts.IgnoreUnexported(flux.Spec{}),
+				cmpopts.IgnoreFields(interpreter.ResolvedFunction{}, "Scope"),
+			}...)
 			if !cmp.Equal(tt.want, got, opts...) {
 				t.Errorf("Build() = %s -want/+got\n%s", tt.promql, cmp.Diff(tt.want, got, opts...))
 			}
 		})
 	}
 }
+
+func TestBuildEvaluation(t *testing.T) {
+	start := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	instant := Evaluation{Bucket: "telegraf", Start: start}
+	rng := Evaluation{Bucket: "telegraf", Start: start, End: start.Add(time.Hour), Step: time.Minute}
+
+	tests := []struct {
+		name    string
+		promql  string
+		eval    Evaluation
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "instant vector",
+			promql: `node_cpu`,
+			eval:   instant,
+			want: []string{
+				"influxDBFrom->range", "range->filter", "filter->last", "last->duplicate",
+				"duplicate->timeShift", "timeShift->drop",
+			},
+		},
+		{
+			name:   "instant vector over a range",
+			promql: `node_cpu`,
+			eval:   rng,
+			want: []string{
+				"influxDBFrom->range", "range->filter", "filter->window", "window->last", "last->duplicate",
+				"duplicate->timeShift", "timeShift->drop", "drop->filter1",
+			},
+		},
+		{
+			name:   "sum of rates without labels",
+			promql: `sum without (cpu) (rate(node_cpu[5m]))`,
+			eval:   rng,
+			want: []string{
+				"influxDBFrom->range", "range->filter", "filter->window", "window->extrapolatedRate",
+				"extrapolatedRate->duplicate", "duplicate->timeShift", "timeShift->drop", "drop->filter1",
+				"filter1->drop1", "drop1->drop2", "drop2->group", "group->sum", "sum->group1",
+			},
+		},
+		{
+			name:   "top series",
+			promql: `topk by (host) (3, irate(node_cpu[1m]))`,
+			eval:   instant,
+			want: []string{
+				"influxDBFrom->range", "range->filter", "filter->instantRate", "instantRate->duplicate",
+				"duplicate->timeShift", "timeShift->drop", "drop->drop1", "drop1->group", "group->sort",
+				"sort->limit", "limit->group1",
+			},
+		},
+		{
+			name:   "histogram quantile",
+			promql: `histogram_quantile(0.99, latency_bucket)`,
+			eval:   instant,
+			want: []string{
+				"influxDBFrom->range", "range->filter", "filter->last", "last->duplicate",
+				"duplicate->timeShift", "timeShift->drop", "drop->group", "group->promHistogramQuantile",
+				"promHistogramQuantile->drop1", "drop1->group1",
+			},
+		},
+		{
+			name:   "vector and scalar",
+			promql: `node_memory_free / 1024`,
+			eval:   instant,
+			want: []string{
+				"influxDBFrom->range", "range->filter", "filter->last", "last->duplicate",
+				"duplicate->timeShift", "timeShift->drop", "drop->drop1", "drop1->map",
+			},
+		},
+		{
+			name:   "vector and vector",
+			promql: `node_memory_free > node_memory_total`,
+			eval:   instant,
+			want: []string{
+				"influxDBFrom->range", "range->filter", "filter->last", "last->duplicate",
+				"duplicate->timeShift", "timeShift->drop",
+				"influxDBFrom1->range1", "range1->filter1", "filter1->last1", "last1->duplicate1",
+				"duplicate1->timeShift1", "timeShift1->drop1",
+				"drop->drop2", "drop2->set", "drop1->drop3", "drop3->set1",
+				"set->union", "set1->union", "union->group", "group->pivot", "pivot->filter2",
+				"filter2->filter3", "filter3->map", "map->drop4",
+			},
+		},
+		{
+			name:    "range vector in a range query",
+			promql:  `node_cpu[5m]`,
+			eval:    rng,
+			wantErr: true,
+		},
+		{
+			name:    "rate of an instant vector",
+			promql:  `rate(node_cpu)`,
+			eval:    instant,
+			wantErr: true,
+		},
+		{
+			name:    "unknown function",
+			promql:  `holt_winters(node_cpu[5m], 0.1, 0.1)`,
+			eval:    instant,
+			wantErr: true,
+		},
+		{
+			name:    "count values",
+			promql:  `count_values("version", build_info)`,
+			eval:    instant,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := BuildEvaluation(tt.promql, tt.eval)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildEvaluation() %s error = %v, wantErr %v", tt.promql, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, e := range spec.Edges {
+				got = append(got, string(e.Parent)+"->"+string(e.Child))
+			}
+			if !cmp.Equal(tt.want, got) {
+				t.Errorf("BuildEvaluation() = %s -want/+got\n%s", tt.promql, cmp.Diff(tt.want, got))
+			}
+
+			if _, err := (plan.PlannerBuilder{}).Build().Plan(spec); err != nil {
+				t.Errorf("unable to plan %s: %v", tt.promql, err)
+			}
+		})
+	}
+}
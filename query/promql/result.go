@@ -0,0 +1,235 @@
+package promql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/iocounter"
+)
+
+// ResultType is the type of the data of a Prometheus query response.
+type ResultType string
+
+// Possible ResultTypes.
+const (
+	VectorResult ResultType = "vector"
+	MatrixResult ResultType = "matrix"
+	ScalarResult ResultType = "scalar"
+)
+
+// ErrorType classifies the error of a Prometheus query response.
+type ErrorType string
+
+// Possible ErrorTypes.
+const (
+	ErrorBadData   ErrorType = "bad_data"
+	ErrorExecution ErrorType = "execution"
+)
+
+// Response is a response of the Prometheus HTTP API.
+type Response struct {
+	Status    string    `json:"status"`
+	Data      *Data     `json:"data,omitempty"`
+	ErrorType ErrorType `json:"errorType,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// NewErrorResponse returns the response of a failed query.
+func NewErrorResponse(typ ErrorType, err error) *Response {
+	return &Response{
+		Status:    "error",
+		ErrorType: typ,
+		Error:     err.Error(),
+	}
+}
+
+// NewScalarResponse returns the response of a query evaluating to a number at t.
+func NewScalarResponse(t time.Time, v float64) *Response {
+	return &Response{
+		Status: "success",
+		Data: &Data{
+			ResultType: ScalarResult,
+			Result:     Sample{Time: t, Value: v},
+		},
+	}
+}
+
+// Data is the result of a successful query.
+type Data struct {
+	ResultType ResultType  `json:"resultType"`
+	Result     interface{} `json:"result"`
+}
+
+// Series is a labeled series of a vector or matrix result.
+type Series struct {
+	Metric map[string]string `json:"metric"`
+	Value  *Sample           `json:"value,omitempty"`
+	Values []Sample          `json:"values,omitempty"`
+}
+
+// Sample is a value at a point in time.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// MarshalJSON encodes the sample as a pair of unix seconds and the formatted value.
+func (s Sample) MarshalJSON() ([]byte, error) {
+	var v string
+	switch {
+	case math.IsNaN(s.Value):
+		v = "NaN"
+	case math.IsInf(s.Value, 1):
+		v = "+Inf"
+	case math.IsInf(s.Value, -1):
+		v = "-Inf"
+	default:
+		v = strconv.FormatFloat(s.Value, 'f', -1, 64)
+	}
+	ts := strconv.FormatFloat(float64(s.Time.UnixNano())/1e9, 'f', -1, 64)
+	return json.Marshal([]interface{}{json.Number(ts), v})
+}
+
+// MultiResultEncoder encodes results as a Prometheus HTTP API query response.
+type MultiResultEncoder struct {
+	ResultType ResultType
+}
+
+// Encode writes the results as a vector or matrix.
+// Expectations/Assumptions:
+//  1. The string columns of the group key are the labels of a series. The metric
+//     name is in the _field column.
+//  2. Tables with the same labels are samples of the same series.
+//  3. Nothing is written when the results fail so the caller may respond with an error.
+func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	series := make(map[string]*Series)
+	for results.More() {
+		res := results.Next()
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			return appendSamples(series, tbl)
+		}); err != nil {
+			results.Release()
+			return 0, err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return 0, err
+	}
+
+	keys := make([]string, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	typ := e.ResultType
+	if typ == "" {
+		typ = VectorResult
+	}
+	result := make([]*Series, 0, len(keys))
+	for _, k := range keys {
+		s := series[k]
+		sort.SliceStable(s.Values, func(i, j int) bool {
+			return s.Values[i].Time.Before(s.Values[j].Time)
+		})
+		if typ == VectorResult {
+			s.Value = &s.Values[len(s.Values)-1]
+			s.Values = nil
+		}
+		result = append(result, s)
+	}
+
+	wc := &iocounter.Writer{Writer: w}
+	err := json.NewEncoder(wc).Encode(Response{
+		Status: "success",
+		Data: &Data{
+			ResultType: typ,
+			Result:     result,
+		},
+	})
+	return wc.Count(), err
+}
+
+func appendSamples(series map[string]*Series, tbl flux.Table) error {
+	metric := make(map[string]string)
+	for j, c := range tbl.Key().Cols() {
+		if c.Type != flux.TString {
+			continue
+		}
+		name := c.Label
+		if name == fieldColumn {
+			name = metricNameLabel
+		}
+		metric[name] = tbl.Key().ValueString(j)
+	}
+
+	labels := make([]string, 0, len(metric))
+	for k, v := range metric {
+		labels = append(labels, k+"="+strconv.Quote(v))
+	}
+	sort.Strings(labels)
+	id := strings.Join(labels, ",")
+
+	timeIdx := execute.ColIdx(execute.DefaultTimeColLabel, tbl.Cols())
+	valueIdx := execute.ColIdx(execute.DefaultValueColLabel, tbl.Cols())
+	if timeIdx < 0 || valueIdx < 0 {
+		// Tables without samples do not contribute a series.
+		return tbl.Do(func(flux.ColReader) error { return nil })
+	}
+
+	var samples []Sample
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		times := cr.Times(timeIdx)
+		for i := 0; i < cr.Len(); i++ {
+			if !times.IsValid(i) {
+				continue
+			}
+			s := Sample{Time: execute.Time(times.Value(i)).Time()}
+			switch c := tbl.Cols()[valueIdx]; c.Type {
+			case flux.TFloat:
+				vs := cr.Floats(valueIdx)
+				if !vs.IsValid(i) {
+					continue
+				}
+				s.Value = vs.Value(i)
+			case flux.TInt:
+				vs := cr.Ints(valueIdx)
+				if !vs.IsValid(i) {
+					continue
+				}
+				s.Value = float64(vs.Value(i))
+			case flux.TUInt:
+				vs := cr.UInts(valueIdx)
+				if !vs.IsValid(i) {
+					continue
+				}
+				s.Value = float64(vs.Value(i))
+			default:
+				return fmt.Errorf("unsupported value type: %s", c.Type)
+			}
+			samples = append(samples, s)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+
+	s, ok := series[id]
+	if !ok {
+		s = &Series{Metric: metric}
+		series[id] = s
+	}
+	s.Values = append(s.Values, samples...)
+	return nil
+}
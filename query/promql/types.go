@@ -1,17 +1,18 @@
 package promql
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
-	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/stdlib/universe"
-	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
 )
 
 type ArgKind int
@@ -29,6 +30,11 @@ type QueryBuilder interface {
 	QuerySpec() (*flux.Spec, error)
 }
 
+// Expr is a PromQL expression that evaluates to a number or a vector.
+type Expr interface {
+	QueryBuilder
+}
+
 type Arg interface {
 	Type() ArgKind
 	Value() interface{}
@@ -92,6 +98,10 @@ func (n *Number) Value() interface{} {
 	return n.Val
 }
 
+func (n *Number) QuerySpec() (*flux.Spec, error) {
+	return nil, fmt.Errorf("unable to build a query for the scalar %v", n.Val)
+}
+
 func NewNumber(val string) (*Number, error) {
 	num, err := strconv.ParseFloat(val, 64)
 	if err != nil {
@@ -143,72 +153,18 @@ type Selector struct {
 }
 
 func (s *Selector) QuerySpec() (*flux.Spec, error) {
-	parent := "from"
-	ops := []*flux.Operation{
-		{
-			ID: "from", // TODO: Change this to a UUID
-			Spec: &influxdb.FromOpSpec{
-				Bucket: "prometheus",
-			},
-		},
-	}
-	edges := []flux.Edge{}
-
-	rng, err := NewRangeOp(s.Range, s.Offset)
-	if err != nil {
-		return nil, err
-	}
-
-	if rng != nil {
-		ops = append(ops, rng)
-		edge := flux.Edge{
-			Parent: flux.OperationID(parent),
-			Child:  "range",
-		}
-		parent = "range"
-		edges = append(edges, edge)
-	}
-
-	where, err := NewWhereOperation(s.Name, s.LabelMatchers)
-	if err != nil {
-		return nil, err
-	}
-
-	ops = append(ops, where)
-	edge := flux.Edge{
-		Parent: flux.OperationID(parent),
-		Child:  "where",
-	}
-	parent = "where"
-	edges = append(edges, edge)
-
-	return &flux.Spec{
-		Operations: ops,
-		Edges:      edges,
-	}, nil
-}
-
-func NewRangeOp(rng, offset time.Duration) (*flux.Operation, error) {
-	if rng == 0 && offset == 0 {
-		return nil, nil
-	}
-	return &flux.Operation{
-		ID: "range", // TODO: Change this to a UUID
-		Spec: &universe.RangeOpSpec{
-			Start: flux.Time{
-				Relative: -rng - offset,
-			},
-		},
-	}, nil
+	return buildSpec(s, Evaluation{})
 }
 
 var operatorLookup = map[MatchKind]ast.OperatorKind{
 	Equal:        ast.EqualOperator,
 	NotEqual:     ast.NotEqualOperator,
-	RegexMatch:   ast.EqualOperator,
-	RegexNoMatch: ast.NotEqualOperator,
+	RegexMatch:   ast.RegexpMatchOperator,
+	RegexNoMatch: ast.NotRegexpMatchOperator,
 }
 
+// NewWhereOperation filters the series of metricName matching all labels.
+// Metric names are stored in the _field column and labels as tags.
 func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operation, error) {
 	var node semantic.Expression = &semantic.BinaryExpression{
 		Operator: ast.EqualOperator,
@@ -216,7 +172,7 @@ func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operati
 			Object: &semantic.IdentifierExpression{
 				Name: "r",
 			},
-			Property: "_metric",
+			Property: fieldColumn,
 		},
 		Right: &semantic.StringLiteral{
 			Value: metricName,
@@ -227,18 +183,35 @@ func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operati
 		if !ok {
 			return nil, fmt.Errorf("unknown label match kind %d", label.Kind)
 		}
+		name := label.Name
+		if name == metricNameLabel {
+			name = fieldColumn
+		}
 		ref := &semantic.MemberExpression{
 			Object: &semantic.IdentifierExpression{
 				Name: "r",
 			},
-			Property: label.Name,
+			Property: name,
 		}
 		var value semantic.Expression
-		if label.Value.Type() == StringKind {
-			value = &semantic.StringLiteral{
-				Value: label.Value.Value().(string),
+		switch label.Value.Type() {
+		case StringKind:
+			v := label.Value.Value().(string)
+			if label.Kind == RegexMatch || label.Kind == RegexNoMatch {
+				// Prometheus regular expressions are fully anchored.
+				re, err := regexp.Compile("^(?:" + v + ")$")
+				if err != nil {
+					return nil, fmt.Errorf("invalid regular expression for label %q: %v", label.Name, err)
+				}
+				value = &semantic.RegexpLiteral{
+					Value: re,
+				}
+			} else {
+				value = &semantic.StringLiteral{
+					Value: v,
+				}
 			}
-		} else if label.Value.Type() == NumberKind {
+		case NumberKind:
 			value = &semantic.FloatLiteral{
 				Value: label.Value.Value().(float64),
 			}
@@ -257,17 +230,7 @@ func NewWhereOperation(metricName string, labels []*LabelMatcher) (*flux.Operati
 	return &flux.Operation{
 		ID: "where", // TODO: Change this to a UUID
 		Spec: &universe.FilterOpSpec{
-			Fn: interpreter.ResolvedFunction{
-				Scope: nil,
-				Fn: &semantic.FunctionExpression{
-					Block: &semantic.FunctionBlock{
-						Parameters: &semantic.FunctionParameters{
-							List: []*semantic.FunctionParameter{{Key: &semantic.Identifier{Name: "r"}}},
-						},
-						Body: node,
-					},
-				},
-			},
+			Fn: rowFunction(node),
 		},
 	}, nil
 }
@@ -306,23 +269,6 @@ type Aggregate struct {
 	Labels  []*Identifier `json:"labels,omitempty"`
 }
 
-func (a *Aggregate) QuerySpec() (*flux.Operation, error) {
-	if a.Without {
-		return nil, fmt.Errorf("unable to merge using `without`")
-	}
-	keys := make([]string, len(a.Labels))
-	for i := range a.Labels {
-		keys[i] = a.Labels[i].Name
-	}
-	return &flux.Operation{
-		ID: "merge",
-		Spec: &universe.GroupOpSpec{
-			Columns: keys,
-			Mode:    "by",
-		},
-	}, nil
-}
-
 type OperatorKind int
 
 const (
@@ -375,108 +321,169 @@ type Operator struct {
 	Arg  Arg          `json:"arg,omitempty"`
 }
 
-func (o *Operator) QuerySpec() (*flux.Operation, error) {
-	switch o.Kind {
-	case CountValuesKind, BottomKind, QuantileKind, StdVarKind:
-		return nil, fmt.Errorf("unable to run %d yet", o.Kind)
-	case CountKind:
-		return &flux.Operation{
-			ID:   "count",
-			Spec: &universe.CountOpSpec{},
-		}, nil
-	//case TopKind:
-	//	return &flux.Operation{
-	//		ID:   "top",
-	//		Spec: &universe.TopOpSpec{}, // TODO: Top doesn't have arg yet
-	//	}, nil
-	case SumKind:
-		return &flux.Operation{
-			ID:   "sum",
-			Spec: &universe.SumOpSpec{},
-		}, nil
-	//case MinKind:
-	//	return &flux.Operation{
-	//		ID:   "min",
-	//		Spec: &universe.MinOpSpec{},
-	//	}, nil
-	//case MaxKind:
-	//	return &flux.Operation{
-	//		ID:   "max",
-	//		Spec: &universe.MaxOpSpec{},
-	//	}, nil
-	//case AvgKind:
-	//	return &flux.Operation{
-	//		ID:   "mean",
-	//		Spec: &universe.MeanOpSpec{},
-	//	}, nil
-	//case StdevKind:
-	//	return &flux.Operation{
-	//		ID:   "stddev",
-	//		Spec: &universe.StddevOpSpec{},
-	//	}, nil
-	default:
-		return nil, fmt.Errorf("unknown Op kind %d", o.Kind)
-	}
-}
-
 type AggregateExpr struct {
 	Op        *Operator  `json:"op,omitempty"`
-	Selector  *Selector  `json:"selector,omitempty"`
+	Expr      Expr       `json:"expr,omitempty"`
 	Aggregate *Aggregate `json:"aggregate,omitempty"`
 }
 
 func (a *AggregateExpr) QuerySpec() (*flux.Spec, error) {
-	spec, err := a.Selector.QuerySpec()
-	if err != nil {
-		return nil, err
+	return buildSpec(a, Evaluation{})
+}
+
+func NewAggregateExpr(op *Operator, expr Expr, group interface{}) (*AggregateExpr, error) {
+	agg := &AggregateExpr{
+		Op:   op,
+		Expr: expr,
+	}
+	if group != nil {
+		agg.Aggregate = group.(*Aggregate)
+	}
+	return agg, nil
+}
+
+// BinaryOpKind is an enum for binary operators.
+type BinaryOpKind int
+
+// Possible BinaryOpKinds.
+const (
+	OpAdd BinaryOpKind = iota
+	OpSub
+	OpMul
+	OpDiv
+	OpMod
+	OpPow
+	OpEqual
+	OpNotEqual
+	OpGreater
+	OpLess
+	OpGreaterEqual
+	OpLessEqual
+)
+
+// IsComparison reports whether the operator filters rather than computes values.
+func (k BinaryOpKind) IsComparison() bool {
+	return k >= OpEqual
+}
+
+func (k BinaryOpKind) String() string {
+	switch k {
+	case OpAdd:
+		return "+"
+	case OpSub:
+		return "-"
+	case OpMul:
+		return "*"
+	case OpDiv:
+		return "/"
+	case OpMod:
+		return "%"
+	case OpPow:
+		return "^"
+	case OpEqual:
+		return "=="
+	case OpNotEqual:
+		return "!="
+	case OpGreater:
+		return ">"
+	case OpLess:
+		return "<"
+	case OpGreaterEqual:
+		return ">="
+	case OpLessEqual:
+		return "<="
+	default:
+		return "unknown"
+	}
+}
+
+// BinaryExpr applies an arithmetic or comparison operator to two expressions.
+// Only one-to-one vector matching on all labels is supported.
+type BinaryExpr struct {
+	Op  BinaryOpKind `json:"op"`
+	LHS Expr         `json:"lhs,omitempty"`
+	RHS Expr         `json:"rhs,omitempty"`
+}
+
+func (b *BinaryExpr) QuerySpec() (*flux.Spec, error) {
+	return buildSpec(b, Evaluation{})
+}
+
+// NewBinaryExpr creates a binary expression folding operations between two numbers.
+func NewBinaryExpr(op BinaryOpKind, lhs, rhs interface{}) (Expr, error) {
+	l, r := lhs.(Expr), rhs.(Expr)
+	ln, lok := l.(*Number)
+	rn, rok := r.(*Number)
+	if !lok || !rok {
+		return &BinaryExpr{Op: op, LHS: l, RHS: r}, nil
+	}
+	if op.IsComparison() {
+		return nil, errors.New("comparisons between scalars must use BOOL modifier")
 	}
+	return &Number{Val: applyArithmetic(op, ln.Val, rn.Val)}, nil
+}
 
-	if a.Aggregate != nil {
-		agg, err := a.Aggregate.QuerySpec()
+// NewBinaryExprs folds a chain of left associative binary operations.
+func NewBinaryExprs(head, tail interface{}) (Expr, error) {
+	expr := head.(Expr)
+	for _, t := range toIfaceSlice(tail) {
+		// Each element of the chain is [whitespace, operator, whitespace, operand].
+		parts := toIfaceSlice(t)
+		next, err := NewBinaryExpr(parts[1].(BinaryOpKind), expr, parts[3])
 		if err != nil {
 			return nil, err
 		}
-
-		parent := flux.OperationID("from")
-		if len(spec.Edges) > 0 {
-			tail := spec.Edges[len(spec.Edges)-1]
-			parent = tail.Child
-		}
-
-		spec.Operations = append(spec.Operations, agg)
-		spec.Edges = append(spec.Edges, flux.Edge{
-			Parent: parent,
-			Child:  agg.ID,
-		})
+		expr = next
 	}
+	return expr, nil
+}
 
-	op, err := a.Op.QuerySpec()
-	if err != nil {
-		return nil, err
+func applyArithmetic(op BinaryOpKind, l, r float64) float64 {
+	switch op {
+	case OpAdd:
+		return l + r
+	case OpSub:
+		return l - r
+	case OpMul:
+		return l * r
+	case OpDiv:
+		return l / r
+	case OpMod:
+		return math.Mod(l, r)
+	case OpPow:
+		return math.Pow(l, r)
+	default:
+		return math.NaN()
 	}
+}
+
+// Call is a call of a PromQL function such as rate or histogram_quantile.
+type Call struct {
+	Func string `json:"func,omitempty"`
+	Args []Expr `json:"args,omitempty"`
+}
+
+func (c *Call) QuerySpec() (*flux.Spec, error) {
+	return buildSpec(c, Evaluation{})
+}
 
-	parent := flux.OperationID("from")
-	if len(spec.Edges) > 0 {
-		tail := spec.Edges[len(spec.Edges)-1]
-		parent = tail.Child
+func NewCall(fn *Identifier, args interface{}) (*Call, error) {
+	call := &Call{
+		Func: fn.Name,
 	}
-	spec.Operations = append(spec.Operations, op)
-	spec.Edges = append(spec.Edges, flux.Edge{
-		Parent: parent,
-		Child:  op.ID,
-	})
-	return spec, nil
-}
-
-func NewAggregateExpr(op *Operator, selector *Selector, group interface{}) (*AggregateExpr, error) {
-	expr := &AggregateExpr{
-		Op:       op,
-		Selector: selector,
+	if args != nil {
+		call.Args = args.([]Expr)
 	}
-	if group != nil {
-		expr.Aggregate = group.(*Aggregate)
+	return call, nil
+}
+
+func NewExprList(first, rest interface{}) ([]Expr, error) {
+	exprs := []Expr{first.(Expr)}
+	for _, r := range toIfaceSlice(rest) {
+		// Each element is [whitespace, ",", whitespace, expression].
+		exprs = append(exprs, toIfaceSlice(r)[3].(Expr))
 	}
-	return expr, nil
+	return exprs, nil
 }
 
 type Comment struct {
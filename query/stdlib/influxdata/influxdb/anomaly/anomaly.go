@@ -0,0 +1,30 @@
+// Package anomaly implements the influxdata/influxdb/anomaly Flux package,
+// which detects values outside of the band of values of a baseline.
+package anomaly
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/parser"
+)
+
+// PackagePath is the import path of the Flux package.
+const PackagePath = "influxdata/influxdb/anomaly"
+
+// source declares the builtin values of the package that are implemented in Go.
+const source = `package anomaly
+
+// band adds the _center, _lower and _upper columns of the band of k deviations
+// around the values of column in [baselineStart, baselineStop) to the rows at
+// or after start. The other rows are dropped, and the tables with too few
+// baseline values to compute the band are left empty.
+// The deviation is the sample standard deviation around the mean for the stddev
+// method, and the median absolute deviation around the median for the mad method.
+builtin band
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Path = PackagePath
+	pkg.Files[0].Name = "anomaly.flux"
+	flux.RegisterPackage(pkg)
+}
@@ -0,0 +1,342 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// BandKind is the kind of the band transformation.
+const BandKind = "band"
+
+// Methods computing the deviation of a band.
+const (
+	MethodStddev = "stddev"
+	MethodMAD    = "mad"
+)
+
+// Columns the band transformation adds to its output rows.
+const (
+	CenterColumn = "_center"
+	LowerColumn  = "_lower"
+	UpperColumn  = "_upper"
+)
+
+// madScale scales the median absolute deviation to estimate the standard
+// deviation of normally distributed values, so k means the same for both methods.
+const madScale = 1.4826
+
+type BandOpSpec struct {
+	Column        string    `json:"column"`
+	Method        string    `json:"method"`
+	K             float64   `json:"k"`
+	Start         flux.Time `json:"start"`
+	BaselineStart flux.Time `json:"baselineStart"`
+	BaselineStop  flux.Time `json:"baselineStop"`
+}
+
+func init() {
+	bandSignature := flux.FunctionSignature(
+		map[string]semantic.PolyType{
+			"column":        semantic.String,
+			"method":        semantic.String,
+			"k":             semantic.Float,
+			"start":         semantic.Tvar(1),
+			"baselineStart": semantic.Tvar(2),
+			"baselineStop":  semantic.Tvar(3),
+		},
+		[]string{"start", "baselineStart", "baselineStop"},
+	)
+
+	flux.RegisterPackageValue(PackagePath, BandKind, flux.FunctionValue(BandKind, createBandOpSpec, bandSignature))
+	flux.RegisterOpSpec(BandKind, newBandOp)
+	plan.RegisterProcedureSpec(BandKind, newBandProcedure, BandKind)
+	execute.RegisterTransformation(BandKind, createBandTransformation)
+}
+
+func createBandOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	spec := &BandOpSpec{
+		Column: execute.DefaultValueColLabel,
+		Method: MethodStddev,
+		K:      3,
+	}
+	if col, ok, err := args.GetString("column"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Column = col
+	}
+	if method, ok, err := args.GetString("method"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Method = method
+	}
+	if spec.Method != MethodStddev && spec.Method != MethodMAD {
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  fmt.Sprintf("band method must be %s or %s, got %q", MethodStddev, MethodMAD, spec.Method),
+		}
+	}
+	if k, ok, err := args.GetFloat("k"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.K = k
+	}
+	if spec.K <= 0 {
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  "band k must be positive",
+		}
+	}
+
+	var err error
+	if spec.Start, err = args.GetRequiredTime("start"); err != nil {
+		return nil, err
+	}
+	if spec.BaselineStart, err = args.GetRequiredTime("baselineStart"); err != nil {
+		return nil, err
+	}
+	if spec.BaselineStop, err = args.GetRequiredTime("baselineStop"); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func newBandOp() flux.OperationSpec {
+	return new(BandOpSpec)
+}
+
+func (s *BandOpSpec) Kind() flux.OperationKind {
+	return BandKind
+}
+
+type BandProcedureSpec struct {
+	plan.DefaultCost
+	Column        string
+	Method        string
+	K             float64
+	Start         execute.Time
+	BaselineStart execute.Time
+	BaselineStop  execute.Time
+}
+
+func newBandProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*BandOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+	now := pa.Now()
+	return &BandProcedureSpec{
+		Column:        spec.Column,
+		Method:        spec.Method,
+		K:             spec.K,
+		Start:         values.ConvertTime(spec.Start.Time(now)),
+		BaselineStart: values.ConvertTime(spec.BaselineStart.Time(now)),
+		BaselineStop:  values.ConvertTime(spec.BaselineStop.Time(now)),
+	}, nil
+}
+
+func (s *BandProcedureSpec) Kind() plan.ProcedureKind {
+	return BandKind
+}
+
+func (s *BandProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createBandTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*BandProcedureSpec)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := NewBandTransformation(d, cache, s)
+	return t, d, nil
+}
+
+// bandTransformation computes the band of each table from its baseline rows,
+// and adds the band to the rows it is evaluated for.
+type bandTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+	spec  *BandProcedureSpec
+}
+
+func NewBandTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *BandProcedureSpec) *bandTransformation {
+	return &bandTransformation{
+		d:     d,
+		cache: cache,
+		spec:  spec,
+	}
+}
+
+func (t *bandTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *bandTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	cols := tbl.Cols()
+	valueIdx := execute.ColIdx(t.spec.Column, cols)
+	if valueIdx < 0 {
+		return &flux.Error{
+			Code: codes.FailedPrecondition,
+			Msg:  fmt.Sprintf("band column %q does not exist", t.spec.Column),
+		}
+	}
+	switch typ := cols[valueIdx].Type; typ {
+	case flux.TFloat, flux.TInt, flux.TUInt:
+	default:
+		return &flux.Error{
+			Code: codes.FailedPrecondition,
+			Msg:  fmt.Sprintf("band column %q has type %v, a numeric type is required", t.spec.Column, typ),
+		}
+	}
+	timeIdx := execute.ColIdx(execute.DefaultTimeColLabel, cols)
+	if timeIdx < 0 {
+		return &flux.Error{
+			Code: codes.FailedPrecondition,
+			Msg:  fmt.Sprintf("band requires the %q column", execute.DefaultTimeColLabel),
+		}
+	}
+
+	builder, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return fmt.Errorf("found duplicate table with key: %v", tbl.Key())
+	}
+	if err := execute.AddTableCols(tbl, builder); err != nil {
+		return err
+	}
+	bandIdx := make([]int, 0, 3)
+	for _, label := range []string{CenterColumn, LowerColumn, UpperColumn} {
+		j, err := builder.AddCol(flux.ColMeta{Label: label, Type: flux.TFloat})
+		if err != nil {
+			return err
+		}
+		bandIdx = append(bandIdx, j)
+	}
+
+	var (
+		baseline []float64
+		n        int
+	)
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		times := cr.Times(timeIdx)
+		for i := 0; i < cr.Len(); i++ {
+			if times.IsNull(i) {
+				continue
+			}
+			ts := execute.Time(times.Value(i))
+			switch {
+			case ts >= t.spec.Start:
+				for j := range cols {
+					if err := builder.AppendValue(j, execute.ValueForRow(cr, i, j)); err != nil {
+						return err
+					}
+				}
+				n++
+			case ts >= t.spec.BaselineStart && ts < t.spec.BaselineStop:
+				if v, ok := floatValue(cr, valueIdx, i); ok {
+					baseline = append(baseline, v)
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	center, deviation, ok := t.deviation(baseline)
+	if !ok {
+		builder.ClearData()
+		return nil
+	}
+	band := []float64{center, center - t.spec.K*deviation, center + t.spec.K*deviation}
+	for i := 0; i < n; i++ {
+		for k, j := range bandIdx {
+			if err := builder.AppendFloat(j, band[k]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deviation returns the center and deviation of the baseline values. It returns
+// false if there are too few values to compute them.
+func (t *bandTransformation) deviation(vs []float64) (float64, float64, bool) {
+	switch t.spec.Method {
+	case MethodMAD:
+		if len(vs) == 0 {
+			return 0, 0, false
+		}
+		m := median(vs)
+		deviations := make([]float64, len(vs))
+		for i, v := range vs {
+			deviations[i] = math.Abs(v - m)
+		}
+		return m, madScale * median(deviations), true
+	default:
+		if len(vs) < 2 {
+			return 0, 0, false
+		}
+		var sum float64
+		for _, v := range vs {
+			sum += v
+		}
+		mean := sum / float64(len(vs))
+		var ss float64
+		for _, v := range vs {
+			ss += (v - mean) * (v - mean)
+		}
+		return mean, math.Sqrt(ss / float64(len(vs)-1)), true
+	}
+}
+
+// median returns the median of vs, which it sorts.
+func median(vs []float64) float64 {
+	sort.Float64s(vs)
+	n := len(vs)
+	if n%2 == 1 {
+		return vs[n/2]
+	}
+	return (vs[n/2-1] + vs[n/2]) / 2
+}
+
+func floatValue(cr flux.ColReader, j, i int) (float64, bool) {
+	switch col := cr.Cols()[j]; col.Type {
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		return vs.Value(i), vs.IsValid(i)
+	case flux.TInt:
+		vs := cr.Ints(j)
+		return float64(vs.Value(i)), vs.IsValid(i)
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		return float64(vs.Value(i)), vs.IsValid(i)
+	}
+	return 0, false
+}
+
+func (t *bandTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *bandTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *bandTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
@@ -0,0 +1,213 @@
+package anomaly_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/anomaly"
+)
+
+func TestBand_Process(t *testing.T) {
+	series := func(host string, vs ...interface{}) *executetest.Table {
+		tbl := &executetest.Table{
+			KeyCols: []string{"host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "host", Type: flux.TString},
+				{Label: "usage", Type: flux.TFloat},
+			},
+		}
+		for i, v := range vs {
+			tbl.Data = append(tbl.Data, []interface{}{execute.Time(i + 1), host, v})
+		}
+		return tbl
+	}
+	want := func(host string, rows [][2]interface{}, center, lower, upper float64) *executetest.Table {
+		tbl := &executetest.Table{
+			KeyCols:   []string{"host"},
+			KeyValues: []interface{}{host},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "host", Type: flux.TString},
+				{Label: "usage", Type: flux.TFloat},
+				{Label: "_center", Type: flux.TFloat},
+				{Label: "_lower", Type: flux.TFloat},
+				{Label: "_upper", Type: flux.TFloat},
+			},
+		}
+		for _, r := range rows {
+			tbl.Data = append(tbl.Data, []interface{}{r[0], host, r[1], center, lower, upper})
+		}
+		return tbl
+	}
+
+	// baseline rows are at times [1, 5), rows at or after 6 are evaluated and row 5 is dropped.
+	spec := func(method string) *anomaly.BandProcedureSpec {
+		return &anomaly.BandProcedureSpec{
+			Column:        "usage",
+			Method:        method,
+			K:             2,
+			Start:         execute.Time(6),
+			BaselineStart: execute.Time(1),
+			BaselineStop:  execute.Time(5),
+		}
+	}
+
+	// mad is the scaled median absolute deviation of the mad case. It is a variable so
+	// that the band is computed at float64 precision, as the transformation computes it.
+	mad := 1.4826
+
+	testCases := []struct {
+		name string
+		spec *anomaly.BandProcedureSpec
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "stddev",
+			spec: spec(anomaly.MethodStddev),
+			data: []flux.Table{
+				series("a", 2.0, 4.0, 4.0, 6.0, 100.0, 10.0),
+				series("b", 1.0, 1.0, 1.0, 1.0, 1.0, 1.0, 0.5),
+			},
+			want: []*executetest.Table{
+				// mean 4, sample stddev sqrt(8/3)
+				want("a", [][2]interface{}{{execute.Time(6), 10.0}}, 4, 4-2*1.632993161855452, 4+2*1.632993161855452),
+				want("b", [][2]interface{}{{execute.Time(6), 1.0}, {execute.Time(7), 0.5}}, 1, 1, 1),
+			},
+		},
+		{
+			name: "mad",
+			spec: spec(anomaly.MethodMAD),
+			data: []flux.Table{
+				series("a", 1.0, 2.0, 3.0, 100.0, 0.0, 50.0),
+			},
+			want: []*executetest.Table{
+				// median 2.5, absolute deviations 0.5, 0.5, 1.5, 97.5 with median 1
+				want("a", [][2]interface{}{{execute.Time(6), 50.0}}, 2.5, 2.5-2*mad, 2.5+2*mad),
+			},
+		},
+		{
+			name: "too few baseline values",
+			spec: spec(anomaly.MethodStddev),
+			data: []flux.Table{
+				&executetest.Table{
+					KeyCols: []string{"host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "host", Type: flux.TString},
+						{Label: "usage", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), "a", 1.0},
+						{execute.Time(6), "a", 5.0},
+					},
+				},
+			},
+			want: []*executetest.Table{want("a", nil, 0, 0, 0)},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					return anomaly.NewBandTransformation(d, c, tc.spec)
+				},
+			)
+		})
+	}
+}
+
+func TestBand_Compile(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		args    string
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			args: `start: -1m, baselineStart: -1h, baselineStop: -1m`,
+		},
+		{
+			name:    "invalid method",
+			args:    `method: "mean", start: -1m, baselineStart: -1h, baselineStop: -1m`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid k",
+			args:    `k: 0.0, start: -1m, baselineStart: -1h, baselineStop: -1m`,
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now().UTC()
+			c := lang.FluxCompiler{
+				Now: now,
+				Query: `import "csv"
+import "influxdata/influxdb/anomaly"
+
+csv.from(csv: "#datatype,string,long,dateTime:RFC3339,string,double
+#group,false,false,false,true,false
+#default,_result,,,,
+,result,table,_time,host,_value
+,,0,` + now.Add(-30*time.Minute).Format(time.RFC3339) + `,a,1.0
+,,0,` + now.Add(-20*time.Minute).Format(time.RFC3339) + `,a,3.0
+,,0,` + now.Add(-30*time.Second).Format(time.RFC3339) + `,a,10.0
+")
+	|> anomaly.band(` + tc.args + `)`,
+			}
+			program, err := c.Compile(context.Background())
+			if err != nil {
+				if !tc.wantErr {
+					t.Fatal(err)
+				}
+				return
+			}
+			q, err := program.Start(context.Background(), &memory.Allocator{})
+			if err != nil {
+				if !tc.wantErr {
+					t.Fatal(err)
+				}
+				return
+			}
+			defer q.Done()
+			if tc.wantErr {
+				t.Fatal("expected error")
+			}
+
+			var upper []float64
+			for res := range q.Results() {
+				if err := res.Tables().Do(func(tbl flux.Table) error {
+					j := execute.ColIdx(anomaly.UpperColumn, tbl.Cols())
+					return tbl.Do(func(cr flux.ColReader) error {
+						for i := 0; i < cr.Len(); i++ {
+							upper = append(upper, cr.Floats(j).Value(i))
+						}
+						return nil
+					})
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := q.Err(); err != nil {
+				t.Fatal(err)
+			}
+			// mean 2 and stddev sqrt(2), with the default k of 3
+			if len(upper) != 1 || upper[0] != 2+3*1.4142135623730951 {
+				t.Errorf("unexpected upper bounds %v", upper)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
 )
 
 const (
@@ -33,6 +34,11 @@ func (s *ReadGroupPhysSpec) Kind() plan.ProcedureKind {
 	return ReadGroupPhysKind
 }
 
+// StorageOperation implements query.StorageReadSpec.
+func (s *ReadGroupPhysSpec) StorageOperation() string {
+	return "ReadGroup"
+}
+
 func (s *ReadGroupPhysSpec) Copy() plan.ProcedureSpec {
 	ns := new(ReadGroupPhysSpec)
 	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
@@ -63,6 +69,11 @@ type ReadRangePhysSpec struct {
 func (s *ReadRangePhysSpec) Kind() plan.ProcedureKind {
 	return ReadRangePhysKind
 }
+
+// StorageOperation implements query.StorageReadSpec.
+func (s *ReadRangePhysSpec) StorageOperation() string {
+	return "ReadFilter"
+}
 func (s *ReadRangePhysSpec) Copy() plan.ProcedureSpec {
 	ns := new(ReadRangePhysSpec)
 
@@ -125,6 +136,11 @@ func (s *ReadTagKeysPhysSpec) Kind() plan.ProcedureKind {
 	return ReadTagKeysPhysKind
 }
 
+// StorageOperation implements query.StorageReadSpec.
+func (s *ReadTagKeysPhysSpec) StorageOperation() string {
+	return "ReadTagKeys"
+}
+
 func (s *ReadTagKeysPhysSpec) Copy() plan.ProcedureSpec {
 	ns := new(ReadTagKeysPhysSpec)
 	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
@@ -140,9 +156,21 @@ func (s *ReadTagValuesPhysSpec) Kind() plan.ProcedureKind {
 	return ReadTagValuesPhysKind
 }
 
+// StorageOperation implements query.StorageReadSpec.
+func (s *ReadTagValuesPhysSpec) StorageOperation() string {
+	return "ReadTagValues"
+}
+
 func (s *ReadTagValuesPhysSpec) Copy() plan.ProcedureSpec {
 	ns := new(ReadTagValuesPhysSpec)
 	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
 	ns.TagKey = s.TagKey
 	return ns
 }
+
+var (
+	_ query.StorageReadSpec = (*ReadRangePhysSpec)(nil)
+	_ query.StorageReadSpec = (*ReadGroupPhysSpec)(nil)
+	_ query.StorageReadSpec = (*ReadTagKeysPhysSpec)(nil)
+	_ query.StorageReadSpec = (*ReadTagValuesPhysSpec)(nil)
+)
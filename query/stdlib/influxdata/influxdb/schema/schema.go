@@ -0,0 +1,26 @@
+// Package schema implements the influxdata/influxdb/schema Flux package,
+// which exposes the schema of the data stored in buckets.
+package schema
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/parser"
+)
+
+// PackagePath is the import path of the Flux package.
+const PackagePath = "influxdata/influxdb/schema"
+
+// source declares the builtin values of the package that are implemented in Go.
+const source = `package schema
+
+// seriesKeys returns the distinct 1.x series keys of the input tables
+// in a single table with a key column.
+builtin seriesKeys
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Path = PackagePath
+	pkg.Files[0].Name = "schema.flux"
+	flux.RegisterPackage(pkg)
+}
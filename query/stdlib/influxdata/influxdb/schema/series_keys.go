@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/influxdb/models"
+)
+
+// SeriesKeysKind is the kind of the seriesKeys transformation, which
+// lists the 1.x series keys of its input tables.
+const SeriesKeysKind = "seriesKeys"
+
+// seriesKeysColumn is the column the series keys are written to.
+const seriesKeysColumn = "key"
+
+type SeriesKeysOpSpec struct{}
+
+func init() {
+	seriesKeysSignature := flux.FunctionSignature(nil, nil)
+
+	flux.RegisterPackageValue(PackagePath, SeriesKeysKind, flux.FunctionValue(SeriesKeysKind, createSeriesKeysOpSpec, seriesKeysSignature))
+	flux.RegisterOpSpec(SeriesKeysKind, newSeriesKeysOp)
+	plan.RegisterProcedureSpec(SeriesKeysKind, newSeriesKeysProcedure, SeriesKeysKind)
+	execute.RegisterTransformation(SeriesKeysKind, createSeriesKeysTransformation)
+}
+
+func createSeriesKeysOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+	return new(SeriesKeysOpSpec), nil
+}
+
+func newSeriesKeysOp() flux.OperationSpec {
+	return new(SeriesKeysOpSpec)
+}
+
+func (s *SeriesKeysOpSpec) Kind() flux.OperationKind {
+	return SeriesKeysKind
+}
+
+type SeriesKeysProcedureSpec struct {
+	plan.DefaultCost
+}
+
+func newSeriesKeysProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	if _, ok := qs.(*SeriesKeysOpSpec); !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+	return &SeriesKeysProcedureSpec{}, nil
+}
+
+func (s *SeriesKeysProcedureSpec) Kind() plan.ProcedureKind {
+	return SeriesKeysKind
+}
+
+func (s *SeriesKeysProcedureSpec) Copy() plan.ProcedureSpec {
+	return new(SeriesKeysProcedureSpec)
+}
+
+func createSeriesKeysTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	if _, ok := spec.(*SeriesKeysProcedureSpec); !ok {
+		return nil, nil, fmt.Errorf("invalid spec type %T", spec)
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	t := NewSeriesKeysTransformation(d, cache)
+	return t, d, nil
+}
+
+// seriesKeysTransformation writes the distinct series keys of its input
+// tables to a single table with a key column. The series key of a table
+// is made of the measurement and the tags in its group key, formatted
+// the way 1.x formats them in the output of SHOW SERIES.
+type seriesKeysTransformation struct {
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+	seen  map[string]bool
+}
+
+func NewSeriesKeysTransformation(d execute.Dataset, cache execute.TableBuilderCache) *seriesKeysTransformation {
+	return &seriesKeysTransformation{
+		d:     d,
+		cache: cache,
+		seen:  make(map[string]bool),
+	}
+}
+
+func (t *seriesKeysTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *seriesKeysTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	builder, created := t.cache.TableBuilder(execute.NewGroupKey(nil, nil))
+	if created {
+		if _, err := builder.AddCol(flux.ColMeta{Label: seriesKeysColumn, Type: flux.TString}); err != nil {
+			return err
+		}
+	}
+
+	if key, ok := seriesKey(tbl.Key()); ok && !t.seen[key] {
+		t.seen[key] = true
+		if err := builder.AppendString(0, key); err != nil {
+			return err
+		}
+	}
+
+	// The table must be consumed even though only its group key is used.
+	return tbl.Do(func(flux.ColReader) error {
+		return nil
+	})
+}
+
+// seriesKey returns the series key of a group key. It returns false if
+// the group key does not have a measurement.
+func seriesKey(key flux.GroupKey) (string, bool) {
+	var (
+		name string
+		tags models.Tags
+	)
+	for j, c := range key.Cols() {
+		if c.Type != flux.TString {
+			continue
+		}
+		switch c.Label {
+		case "_measurement":
+			name = key.ValueString(j)
+		case "_field", execute.DefaultStartColLabel, execute.DefaultStopColLabel:
+		default:
+			tags = append(tags, models.NewTag([]byte(c.Label), []byte(key.ValueString(j))))
+		}
+	}
+	if name == "" {
+		return "", false
+	}
+	sort.Sort(tags)
+	return string(models.MakeKey([]byte(name), tags)), true
+}
+
+func (t *seriesKeysTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *seriesKeysTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *seriesKeysTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
@@ -0,0 +1,139 @@
+package schema_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/schema"
+)
+
+func TestSeriesKeys_Process(t *testing.T) {
+	series := func(m, host, region, field string, v float64) *executetest.Table {
+		tbl := &executetest.Table{
+			KeyCols: []string{"_start", "_stop", "_measurement", "_field", "host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_start", Type: flux.TTime},
+				{Label: "_stop", Type: flux.TTime},
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(0), execute.Time(10), execute.Time(1), v, m, field, host},
+			},
+		}
+		if region != "" {
+			tbl.KeyCols = append(tbl.KeyCols, "region")
+			tbl.ColMeta = append(tbl.ColMeta, flux.ColMeta{Label: "region", Type: flux.TString})
+			tbl.Data[0] = append(tbl.Data[0], region)
+		}
+		return tbl
+	}
+
+	testCases := []struct {
+		name string
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "distinct series",
+			data: []flux.Table{
+				series("cpu", "a", "west", "usage_user", 1),
+				series("cpu", "a", "west", "usage_system", 2),
+				series("cpu", "b", "", "usage_user", 3),
+				series("mem", "a", "", "free", 4),
+			},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "key", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{"cpu,host=a,region=west"},
+					{"cpu,host=b"},
+					{"mem,host=a"},
+				},
+			}},
+		},
+		{
+			name: "escaped tags",
+			data: []flux.Table{
+				series("disk io", "a=b", "", "reads", 1),
+			},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "key", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{`disk\ io,host=a\=b`},
+				},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.ProcessTestHelper(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
+					return schema.NewSeriesKeysTransformation(d, c)
+				},
+			)
+		})
+	}
+}
+
+func TestSeriesKeys_Compile(t *testing.T) {
+	c := lang.FluxCompiler{
+		Query: `import "csv"
+import "influxdata/influxdb/schema"
+
+csv.from(csv: "#datatype,string,long,string,string,double
+#group,false,false,true,true,false
+#default,_result,,,,
+,result,table,_measurement,host,_value
+,,0,cpu,a,1.0
+")
+	|> group(columns: ["_measurement", "host"])
+	|> schema.seriesKeys()`,
+	}
+	program, err := c.Compile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := program.Start(context.Background(), &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Done()
+
+	var keys []string
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				for i := 0; i < cr.Len(); i++ {
+					keys = append(keys, cr.Strings(0).ValueString(i))
+				}
+				return nil
+			})
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "cpu,host=a" {
+		t.Errorf("unexpected series keys %v", keys)
+	}
+}
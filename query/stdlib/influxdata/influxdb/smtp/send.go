@@ -0,0 +1,283 @@
+package smtp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// TLS modes of the connection to an SMTP server.
+const (
+	TLSNone     = "none"
+	TLSStartTLS = "starttls"
+	TLS         = "tls"
+)
+
+// SendKind is the name of the function sending emails.
+const SendKind = "send"
+
+// DefaultTimeout is the time an email is given to be sent when the context has no deadline.
+const DefaultTimeout = 30 * time.Second
+
+func init() {
+	flux.RegisterPackageValue(PackagePath, SendKind, values.NewFunction(
+		SendKind,
+		semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+			Parameters: map[string]semantic.PolyType{
+				"host":     semantic.String,
+				"port":     semantic.Int,
+				"tls":      semantic.String,
+				"username": semantic.String,
+				"password": semantic.String,
+				"from":     semantic.String,
+				"to":       semantic.String,
+				"cc":       semantic.String,
+				"subject":  semantic.String,
+				"body":     semantic.String,
+			},
+			Required: []string{"host", "port", "from", "to", "subject", "body"},
+			Return:   semantic.Bool,
+		}),
+		func(ctx context.Context, args values.Object) (values.Value, error) {
+			m, err := newMessage(interpreter.NewArguments(args))
+			if err != nil {
+				return nil, err
+			}
+			sent, err := m.send(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return values.NewBool(sent), nil
+		},
+		true, // send has side-effects
+	))
+}
+
+// message is an email and the SMTP server it is sent through.
+type message struct {
+	host     string
+	port     int64
+	tls      string
+	username string
+	password string
+
+	from    *mail.Address
+	to      []*mail.Address
+	cc      []*mail.Address
+	subject string
+	body    string
+}
+
+func newMessage(args interpreter.Arguments) (*message, error) {
+	var (
+		m   message
+		err error
+	)
+	if m.host, err = args.GetRequiredString("host"); err != nil {
+		return nil, err
+	}
+	if m.port, err = args.GetRequiredInt("port"); err != nil {
+		return nil, err
+	}
+	if m.port < 1 || m.port > 65535 {
+		return nil, invalidf("smtp port %d is out of range", m.port)
+	}
+
+	strs := []struct {
+		name string
+		v    *string
+		def  string
+	}{
+		{name: "tls", v: &m.tls, def: TLSStartTLS},
+		{name: "username", v: &m.username},
+		{name: "password", v: &m.password},
+		{name: "subject", v: &m.subject},
+		{name: "body", v: &m.body},
+	}
+	for _, s := range strs {
+		v, ok, err := args.GetString(s.name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			v = s.def
+		}
+		*s.v = v
+	}
+	switch m.tls {
+	case TLSNone, TLSStartTLS, TLS:
+	default:
+		return nil, invalidf("invalid smtp tls mode %q; valid modes are %s, %s and %s", m.tls, TLSNone, TLSStartTLS, TLS)
+	}
+
+	from, err := args.GetRequiredString("from")
+	if err != nil {
+		return nil, err
+	}
+	if m.from, err = mail.ParseAddress(from); err != nil {
+		return nil, invalidf("invalid smtp from address %q: %v", from, err)
+	}
+	if m.to, err = addresses(args, "to"); err != nil {
+		return nil, err
+	}
+	if len(m.to) == 0 {
+		return nil, invalidf("smtp email must have at least one to address")
+	}
+	if m.cc, err = addresses(args, "cc"); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func invalidf(format string, a ...interface{}) error {
+	return &flux.Error{
+		Code: codes.Invalid,
+		Msg:  fmt.Sprintf(format, a...),
+	}
+}
+
+// addresses parses the comma separated addresses of the argument name.
+func addresses(args interpreter.Arguments, name string) ([]*mail.Address, error) {
+	v, ok, err := args.GetString(name)
+	if err != nil || !ok || strings.TrimSpace(v) == "" {
+		return nil, err
+	}
+	as, err := mail.ParseAddressList(v)
+	if err != nil {
+		return nil, invalidf("invalid smtp %s addresses %q: %v", name, v, err)
+	}
+	return as, nil
+}
+
+// send sends m, and returns true once the server accepted it.
+// An error holding the reply of the server is returned if it rejected m,
+// and an error is returned if the server can not be reached.
+func (m *message) send(ctx context.Context) (bool, error) {
+	addr := net.JoinHostPort(m.host, strconv.FormatInt(m.port, 10))
+	validator, err := flux.GetDependencies(ctx).URLValidator()
+	if err != nil {
+		return false, err
+	}
+	if err := validator.Validate(&url.URL{Scheme: "smtp", Host: addr}); err != nil {
+		return false, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DefaultTimeout)
+	}
+	dialer := &net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return false, err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if m.tls == TLS {
+		conn = tls.Client(conn, &tls.Config{ServerName: m.host})
+	}
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return false, err
+	}
+	defer c.Close()
+
+	if err := m.deliver(c); err != nil {
+		if err, ok := err.(*textproto.Error); ok {
+			return false, &flux.Error{
+				Code: codes.Invalid,
+				Msg:  fmt.Sprintf("smtp server %s rejected the email: %d %s", addr, err.Code, err.Msg),
+			}
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// deliver sends m through the connection of c.
+func (m *message) deliver(c *smtp.Client) error {
+	if m.tls == TLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", m.host)
+		}
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(m.from.Address); err != nil {
+		return err
+	}
+	for _, a := range append(m.to, m.cc...) {
+		if err := c.Rcpt(a.Address); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.bytes()); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// bytes returns the headers and body of m.
+func (m *message) bytes() []byte {
+	var b strings.Builder
+	header := func(k, v string) {
+		b.WriteString(k)
+		b.WriteString(": ")
+		b.WriteString(v)
+		b.WriteString("\r\n")
+	}
+	header("From", m.from.String())
+	header("To", join(m.to))
+	if len(m.cc) > 0 {
+		header("Cc", join(m.cc))
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(m.subject), " ")))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/plain; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+	b.WriteString(m.body)
+	return []byte(b.String())
+}
+
+func join(as []*mail.Address) string {
+	strs := make([]string, 0, len(as))
+	for _, a := range as {
+		strs = append(strs, a.String())
+	}
+	return strings.Join(strs, ", ")
+}
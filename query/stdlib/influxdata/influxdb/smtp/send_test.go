@@ -0,0 +1,235 @@
+package smtp_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/semantic"
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+// mail is an email received by server.
+type mail struct {
+	auth string
+	from string
+	rcpt []string
+	data string
+}
+
+// server is an in-process SMTP server accepting emails without TLS.
+type server struct {
+	ln     net.Listener
+	mails  chan mail
+	reject string
+}
+
+func newServer(t *testing.T) *server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{ln: ln, mails: make(chan mail, 1)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *server) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *server) serve(conn net.Conn) {
+	defer conn.Close()
+	c := textproto.NewConn(conn)
+	c.PrintfLine("220 localhost ESMTP")
+
+	var m mail
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		arg := strings.TrimSpace(strings.TrimPrefix(line, strings.SplitN(line, " ", 2)[0]))
+		switch cmd {
+		case "EHLO":
+			c.PrintfLine("250-localhost")
+			c.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			b, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(arg, "PLAIN "))
+			m.auth = strings.Replace(strings.TrimPrefix(string(b), "\x00"), "\x00", ":", 1)
+			c.PrintfLine("235 authenticated")
+		case "MAIL":
+			m.from = arg
+			c.PrintfLine("250 ok")
+		case "RCPT":
+			if s.reject != "" && strings.Contains(arg, s.reject) {
+				c.PrintfLine("550 no such user")
+				continue
+			}
+			m.rcpt = append(m.rcpt, arg)
+			c.PrintfLine("250 ok")
+		case "DATA":
+			c.PrintfLine("354 go ahead")
+			b, err := c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			m.data = string(b)
+			c.PrintfLine("250 queued")
+			s.mails <- m
+		case "QUIT":
+			c.PrintfLine("221 bye")
+			return
+		default:
+			c.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func send(t *testing.T, script string) (bool, error) {
+	t.Helper()
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	_, scope, err := flux.Eval(ctx, `import "influxdata/influxdb/smtp"`+"\n"+script)
+	if err != nil {
+		return false, err
+	}
+	v, ok := scope.Lookup("sent")
+	if !ok {
+		t.Fatal("sent is not defined")
+	}
+	return v.Bool(), nil
+}
+
+func TestSend(t *testing.T) {
+	s := newServer(t)
+	defer s.ln.Close()
+
+	sent, err := send(t, fmt.Sprintf(`sent = smtp.send(
+    host: "localhost",
+    port: %d,
+    tls: "none",
+    username: "user",
+    password: "pass",
+    from: "InfluxDB <influxdb@example.com>",
+    to: "ops@example.com, oncall@example.com",
+    cc: "lead@example.com",
+    subject: "cpu is crit",
+    body: "cpu usage is 99%%",
+)`, s.port()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sent {
+		t.Fatal("expected the email to be sent")
+	}
+
+	m := <-s.mails
+	if m.auth != "user:pass" {
+		t.Errorf("unexpected auth %q", m.auth)
+	}
+	if m.from != "FROM:<influxdb@example.com>" {
+		t.Errorf("unexpected from %q", m.from)
+	}
+	if exp := []string{"TO:<ops@example.com>", "TO:<oncall@example.com>", "TO:<lead@example.com>"}; strings.Join(m.rcpt, ",") != strings.Join(exp, ",") {
+		t.Errorf("unexpected recipients %v", m.rcpt)
+	}
+	for _, exp := range []string{
+		"From: \"InfluxDB\" <influxdb@example.com>\n",
+		"To: <ops@example.com>, <oncall@example.com>\n",
+		"Cc: <lead@example.com>\n",
+		"Subject: cpu is crit\n",
+		"\n\ncpu usage is 99%",
+	} {
+		if !strings.Contains(m.data, exp) {
+			t.Errorf("expected email to contain %q, got:\n%s", exp, m.data)
+		}
+	}
+}
+
+func TestSend_Rejected(t *testing.T) {
+	s := newServer(t)
+	defer s.ln.Close()
+	s.reject = "nobody@"
+
+	_, err := send(t, fmt.Sprintf(`sent = smtp.send(host: "localhost", port: %d, tls: "none", from: "influxdb@example.com", to: "nobody@example.com", subject: "s", body: "b")`, s.port()))
+	if err == nil {
+		t.Fatal("expected the email to be rejected")
+	}
+	if !strings.Contains(err.Error(), "550 no such user") {
+		t.Fatalf("expected the reply of the server in the error, got %v", err)
+	}
+}
+
+func TestSend_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		script string
+	}{
+		{
+			name:   "tls mode",
+			script: `sent = smtp.send(host: "localhost", port: 25, tls: "ssl", from: "a@example.com", to: "b@example.com", subject: "s", body: "b")`,
+		},
+		{
+			name:   "port",
+			script: `sent = smtp.send(host: "localhost", port: 0, from: "a@example.com", to: "b@example.com", subject: "s", body: "b")`,
+		},
+		{
+			name:   "from",
+			script: `sent = smtp.send(host: "localhost", port: 25, from: "not an address", to: "b@example.com", subject: "s", body: "b")`,
+		},
+		{
+			name:   "no recipients",
+			script: `sent = smtp.send(host: "localhost", port: 25, from: "a@example.com", to: " ", subject: "s", body: "b")`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := send(t, tc.script); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestSend_STARTTLSUnsupported(t *testing.T) {
+	s := newServer(t)
+	defer s.ln.Close()
+
+	if _, err := send(t, fmt.Sprintf(`sent = smtp.send(host: "localhost", port: %d, from: "a@example.com", to: "b@example.com", subject: "s", body: "b")`, s.port())); err == nil {
+		t.Fatal("expected error when the server does not support STARTTLS")
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	_, scope, err := flux.Eval(ctx, `import "influxdata/influxdb/smtp"
+e = smtp.endpoint(host: "localhost", from: "influxdb@example.com")
+notify = e(mapFn: (r) => ({to: "ops@example.com", cc: "", subject: r._level, body: r._message}))
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, ok := scope.Lookup("notify")
+	if !ok {
+		t.Fatal("notify is not defined")
+	}
+	if v.Type().Nature() != semantic.Function {
+		t.Fatalf("expected the endpoint to return a function, got %v", v.Type())
+	}
+}
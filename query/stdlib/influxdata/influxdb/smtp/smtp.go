@@ -0,0 +1,50 @@
+// Package smtp implements the influxdata/influxdb/smtp Flux package,
+// which sends the notifications of SMTP notification rules as emails.
+package smtp
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/parser"
+)
+
+// PackagePath is the import path of the Flux package.
+const PackagePath = "influxdata/influxdb/smtp"
+
+// source declares the builtin values of the package that are implemented in Go,
+// and the endpoint monitor.notify sends the notifications through.
+const source = `package smtp
+
+// send sends an email with the subject and body to the comma separated to and cc addresses,
+// and returns true if the SMTP server accepted it. send fails with the reply of the server if it rejected the email.
+// tls is one of "none", "starttls" or "tls".
+builtin send
+
+// endpoint creates the endpoint of an SMTP server.
+// The returned factory function accepts a mapFn parameter, which must return
+// an object with to, cc, subject and body fields as defined by send.
+endpoint = (host, port=25, tls="starttls", username="", password="", from) =>
+    (mapFn) =>
+        (tables=<-) => tables
+            |> map(fn: (r) => {
+                obj = mapFn(r: r)
+                return {r with _sent: string(v: send(
+                    host: host,
+                    port: port,
+                    tls: tls,
+                    username: username,
+                    password: password,
+                    from: from,
+                    to: obj.to,
+                    cc: obj.cc,
+                    subject: obj.subject,
+                    body: obj.body,
+                ))}
+            })
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Path = PackagePath
+	pkg.Files[0].Name = "smtp.flux"
+	flux.RegisterPackage(pkg)
+}
@@ -67,6 +67,10 @@ func (s *Source) Metadata() flux.Metadata {
 	return flux.Metadata{
 		"influxdb/scanned-bytes":  []interface{}{s.stats.ScannedBytes},
 		"influxdb/scanned-values": []interface{}{s.stats.ScannedValues},
+		query.CursorStatsMetadataKey: []interface{}{query.SourceCursorStats{
+			DatasetID:   s.id,
+			CursorStats: s.stats,
+		}},
 	}
 }
 
@@ -4,6 +4,9 @@ package stdlib
 import (
 	_ "github.com/influxdata/influxdb/query/stdlib/experimental"
 	_ "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+	_ "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/anomaly"
+	_ "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/schema"
+	_ "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/smtp"
 	_ "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/v1"
 	_ "github.com/influxdata/influxdb/query/stdlib/testing"
 )
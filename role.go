@@ -0,0 +1,186 @@
+package influxdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ErrRoleNotFound is the error msg for a missing role.
+const ErrRoleNotFound = "role not found"
+
+// ErrRoleAssignmentNotFound is the error msg for a missing role assignment.
+const ErrRoleAssignmentNotFound = "role assignment not found"
+
+// names of the built-in roles of the owners and members of an organization.
+const (
+	OwnerRoleName  = "owner"
+	MemberRoleName = "member"
+)
+
+// ops for role error.
+const (
+	OpFindRoleByID        = "FindRoleByID"
+	OpFindRoles           = "FindRoles"
+	OpCreateRole          = "CreateRole"
+	OpUpdateRole          = "UpdateRole"
+	OpDeleteRole          = "DeleteRole"
+	OpFindRoleAssignments = "FindRoleAssignments"
+	OpAssignRole          = "AssignRole"
+	OpUnassignRole        = "UnassignRole"
+)
+
+// RoleService describes a service for managing roles and their assignments to users.
+type RoleService interface {
+	// FindRoleByID finds a single role by its ID.
+	FindRoleByID(ctx context.Context, id ID) (*Role, error)
+
+	// FindRoles returns the roles matching the filter and their count.
+	FindRoles(ctx context.Context, filter RoleFilter, opt ...FindOptions) ([]*Role, int, error)
+
+	// CreateRole creates a new role and assigns it an ID.
+	CreateRole(ctx context.Context, r *Role) error
+
+	// UpdateRole updates a single role with a changeset.
+	UpdateRole(ctx context.Context, id ID, upd RoleUpdate) (*Role, error)
+
+	// DeleteRole removes a role by its ID along with its assignments.
+	DeleteRole(ctx context.Context, id ID) error
+
+	// FindRoleAssignments returns the role assignments matching the filter and their count.
+	FindRoleAssignments(ctx context.Context, filter RoleAssignmentFilter) ([]*RoleAssignment, int, error)
+
+	// AssignRole assigns a role to a user, the user is granted the permissions of the role.
+	// The user must be an owner or a member of the organization of the role.
+	AssignRole(ctx context.Context, a *RoleAssignment) error
+
+	// UnassignRole removes the assignment of a role to a user.
+	UnassignRole(ctx context.Context, roleID, userID ID) error
+}
+
+// Role is a named set of permissions within an organization that is assigned to users.
+// The sessions of a user and the tokens created from roles are granted their permissions.
+type Role struct {
+	ID          ID           `json:"id,omitempty"`
+	OrgID       ID           `json:"orgID,omitempty"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+	// BuiltIn roles are the owner and member roles every organization has,
+	// they are assigned to the owners and members of the organization and can't be changed.
+	BuiltIn bool `json:"builtIn,omitempty"`
+	CRUDLog
+}
+
+// Valid returns an error if the role contains invalid data.
+func (r *Role) Valid() error {
+	if !r.OrgID.Valid() {
+		return errors.New("missing orgID")
+	}
+	if r.Name == "" {
+		return errors.New("missing name")
+	}
+	if len(r.Permissions) == 0 {
+		return errors.New("role must include permissions")
+	}
+	for _, p := range r.Permissions {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+		if !r.scoped(p) {
+			return fmt.Errorf("permission %s is not for org id %s", p, r.OrgID)
+		}
+	}
+	return nil
+}
+
+// scoped returns whether p only grants access to resources of the organization of the role.
+func (r *Role) scoped(p Permission) bool {
+	if p.Resource.OrgID != nil {
+		return *p.Resource.OrgID == r.OrgID
+	}
+	return p.Resource.Type == OrgsResourceType && p.Resource.ID != nil && *p.Resource.ID == r.OrgID
+}
+
+// NewBuiltInRole returns the built-in role of the owners or of the members of an organization.
+func NewBuiltInRole(orgID ID, ut UserType) (*Role, error) {
+	switch ut {
+	case Owner:
+		return &Role{
+			OrgID:       orgID,
+			Name:        OwnerRoleName,
+			Description: "Owners of the organization",
+			Permissions: OwnerPermissions(orgID),
+			BuiltIn:     true,
+		}, nil
+	case Member:
+		return &Role{
+			OrgID:       orgID,
+			Name:        MemberRoleName,
+			Description: "Members of the organization",
+			Permissions: MemberPermissions(orgID),
+			BuiltIn:     true,
+		}, nil
+	default:
+		return nil, ErrInvalidUserType
+	}
+}
+
+// RoleFilter represents a set of filters that restrict the returned roles.
+type RoleFilter struct {
+	ID    *ID
+	OrgID *ID
+	Name  *string
+}
+
+// QueryParams implements PagingFilter.
+//
+// It converts RoleFilter fields to url query params.
+func (f RoleFilter) QueryParams() map[string][]string {
+	qp := url.Values{}
+	if f.ID != nil {
+		qp.Add("id", f.ID.String())
+	}
+	if f.OrgID != nil {
+		qp.Add("orgID", f.OrgID.String())
+	}
+	if f.Name != nil {
+		qp.Add("name", *f.Name)
+	}
+	return qp
+}
+
+// RoleUpdate describes a set of changes that can be applied to a role.
+type RoleUpdate struct {
+	Name        *string       `json:"name,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Permissions *[]Permission `json:"permissions,omitempty"`
+}
+
+// Apply applies the changes of the update to r.
+func (u RoleUpdate) Apply(r *Role) {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.Permissions != nil {
+		r.Permissions = *u.Permissions
+	}
+}
+
+// RoleAssignment assigns a role to a user of the organization of the role.
+type RoleAssignment struct {
+	RoleID ID `json:"roleID"`
+	UserID ID `json:"userID"`
+	OrgID  ID `json:"orgID,omitempty"`
+}
+
+// RoleAssignmentFilter represents a set of filters that restrict the returned role assignments.
+type RoleAssignmentFilter struct {
+	RoleID *ID
+	UserID *ID
+	OrgID  *ID
+}
@@ -0,0 +1,90 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestRole_Valid(t *testing.T) {
+	orgID, otherID := influxdb.ID(1), influxdb.ID(2)
+
+	tests := []struct {
+		name    string
+		role    influxdb.Role
+		wantErr bool
+	}{
+		{
+			name: "permissions of the org",
+			role: influxdb.Role{
+				OrgID: orgID,
+				Name:  "dashboard editor",
+				Permissions: []influxdb.Permission{
+					{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID}},
+					{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.OrgsResourceType, ID: &orgID}},
+				},
+			},
+		},
+		{
+			name: "missing name",
+			role: influxdb.Role{
+				OrgID:       orgID,
+				Permissions: influxdb.MemberPermissions(orgID),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "missing permissions",
+			role:    influxdb.Role{OrgID: orgID, Name: "empty"},
+			wantErr: true,
+		},
+		{
+			name: "permission of another org",
+			role: influxdb.Role{
+				OrgID: orgID,
+				Name:  "bucket writer",
+				Permissions: []influxdb.Permission{
+					{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &otherID}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "global permission",
+			role: influxdb.Role{
+				OrgID: orgID,
+				Name:  "bucket writer",
+				Permissions: []influxdb.Permission{
+					{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.role.Valid(); (err != nil) != tt.wantErr {
+				t.Errorf("Valid() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewBuiltInRole(t *testing.T) {
+	orgID := influxdb.ID(1)
+	for _, ut := range []influxdb.UserType{influxdb.Owner, influxdb.Member} {
+		r, err := influxdb.NewBuiltInRole(orgID, ut)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.BuiltIn || r.Name != string(ut) {
+			t.Errorf("expected the built-in %s role, got %+v", ut, r)
+		}
+		if err := r.Valid(); err != nil {
+			t.Errorf("expected the built-in %s role to be valid, got %v", ut, err)
+		}
+	}
+	if _, err := influxdb.NewBuiltInRole(orgID, influxdb.UserType("guest")); err == nil {
+		t.Error("expected an unknown user type to have no built-in role")
+	}
+}
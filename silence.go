@@ -0,0 +1,217 @@
+package influxdb
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+// ErrSilenceNotFound is the error msg for a missing silence.
+const ErrSilenceNotFound = "silence not found"
+
+// ops for silence error.
+const (
+	OpFindSilenceByID = "FindSilenceByID"
+	OpFindSilences    = "FindSilences"
+	OpCreateSilence   = "CreateSilence"
+	OpUpdateSilence   = "UpdateSilence"
+	OpDeleteSilence   = "DeleteSilence"
+)
+
+// SilenceService describes a service for managing silences.
+type SilenceService interface {
+	// FindSilenceByID finds a single silence by its ID.
+	FindSilenceByID(ctx context.Context, id ID) (*Silence, error)
+
+	// FindSilences returns the silences matching the filter and their count.
+	FindSilences(ctx context.Context, filter SilenceFilter, opt ...FindOptions) ([]*Silence, int, error)
+
+	// CreateSilence creates a new silence and assigns it an ID.
+	CreateSilence(ctx context.Context, s *Silence) error
+
+	// UpdateSilence updates a single silence with a changeset.
+	UpdateSilence(ctx context.Context, id ID, upd SilenceUpdate) (*Silence, error)
+
+	// DeleteSilence removes a silence by its ID.
+	DeleteSilence(ctx context.Context, id ID) error
+}
+
+// Silence suppresses the notifications of the statuses it matches while it is active,
+// the statuses themselves are still written. A silence is active from Start to End,
+// a recurring maintenance window is active again every Repeat after, until Until.
+type Silence struct {
+	ID    ID `json:"id,omitempty"`
+	OrgID ID `json:"orgID,omitempty"`
+	// Matchers match the tags and the columns of statuses, such as _check_name and _level.
+	// A silence without matchers matches all the statuses of the organization.
+	Matchers  []TagRule         `json:"matchers"`
+	Start     time.Time         `json:"start"`
+	End       time.Time         `json:"end"`
+	Repeat    *options.Duration `json:"repeat,omitempty"`
+	Until     *time.Time        `json:"until,omitempty"`
+	Comment   string            `json:"comment,omitempty"`
+	CreatedBy ID                `json:"createdBy,omitempty"`
+	CRUDLog
+}
+
+// Valid returns an error if the silence contains invalid data.
+func (s *Silence) Valid() error {
+	if !s.OrgID.Valid() {
+		return errors.New("missing orgID")
+	}
+	if s.Start.IsZero() || s.End.IsZero() {
+		return errors.New("missing start or end")
+	}
+	if !s.Start.Before(s.End) {
+		return errors.New("start must be before end")
+	}
+	if s.Repeat != nil {
+		d, err := s.Repeat.DurationFrom(s.Start)
+		if err != nil {
+			return err
+		}
+		if d <= 0 {
+			return errors.New("repeat must be positive")
+		}
+	}
+	if s.Until != nil && !s.Start.Before(*s.Until) {
+		return errors.New("start must be before until")
+	}
+	for _, m := range s.Matchers {
+		if err := m.Valid(); err != nil {
+			return err
+		}
+		if m.Operator == RegexEqual || m.Operator == NotRegexEqual {
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Active returns whether the silence is active at t.
+func (s *Silence) Active(t time.Time) bool {
+	if t.Before(s.Start) {
+		return false
+	}
+	if s.Repeat == nil {
+		return t.Before(s.End)
+	}
+	if s.Until != nil && !t.Before(*s.Until) {
+		return false
+	}
+
+	length := s.End.Sub(s.Start)
+	if fixedDuration(s.Repeat) {
+		repeat, err := s.Repeat.DurationFrom(s.Start)
+		if err != nil || repeat <= 0 {
+			return false
+		}
+		return t.Sub(s.Start)%repeat < length
+	}
+	// months and years don't have a fixed length, the windows are stepped through.
+	for start := s.Start; !start.After(t); {
+		if t.Before(start.Add(length)) {
+			return true
+		}
+		next, err := addCalendar(s.Repeat, start)
+		if err != nil || !next.After(start) {
+			return false
+		}
+		start = next
+	}
+	return false
+}
+
+// fixedDuration returns whether the length of d doesn't depend on the time it is added to.
+func fixedDuration(d *options.Duration) bool {
+	for _, v := range d.Node.Values {
+		if v.Unit == "mo" || v.Unit == "y" {
+			return false
+		}
+	}
+	return true
+}
+
+// addCalendar adds d to t, adding its months and years to the date of t
+// rather than approximating their lengths.
+func addCalendar(d *options.Duration, t time.Time) (time.Time, error) {
+	rest := &ast.DurationLiteral{}
+	for _, v := range d.Node.Values {
+		switch v.Unit {
+		case "y":
+			t = t.AddDate(int(v.Magnitude), 0, 0)
+		case "mo":
+			t = t.AddDate(0, int(v.Magnitude), 0)
+		default:
+			rest.Values = append(rest.Values, v)
+		}
+	}
+	if len(rest.Values) == 0 {
+		return t, nil
+	}
+	r, err := ast.DurationFrom(rest, t)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(r), nil
+}
+
+// SilenceFilter represents a set of filters that restrict the returned silences.
+type SilenceFilter struct {
+	OrgID *ID
+	// ActiveAt restricts the silences to the ones active at its time.
+	ActiveAt *time.Time
+}
+
+// QueryParams implements PagingFilter.
+//
+// It converts SilenceFilter fields to url query params.
+func (f SilenceFilter) QueryParams() map[string][]string {
+	qp := url.Values{}
+	if f.OrgID != nil {
+		qp.Add("orgID", f.OrgID.String())
+	}
+	if f.ActiveAt != nil {
+		qp.Add("activeAt", f.ActiveAt.Format(time.RFC3339))
+	}
+	return qp
+}
+
+// SilenceUpdate describes a set of changes that can be applied to a silence.
+type SilenceUpdate struct {
+	Matchers *[]TagRule        `json:"matchers,omitempty"`
+	Start    *time.Time        `json:"start,omitempty"`
+	End      *time.Time        `json:"end,omitempty"`
+	Repeat   *options.Duration `json:"repeat,omitempty"`
+	Until    *time.Time        `json:"until,omitempty"`
+	Comment  *string           `json:"comment,omitempty"`
+}
+
+// Apply applies the changes of the update to s.
+func (u SilenceUpdate) Apply(s *Silence) {
+	if u.Matchers != nil {
+		s.Matchers = *u.Matchers
+	}
+	if u.Start != nil {
+		s.Start = *u.Start
+	}
+	if u.End != nil {
+		s.End = *u.End
+	}
+	if u.Repeat != nil {
+		s.Repeat = u.Repeat
+	}
+	if u.Until != nil {
+		s.Until = u.Until
+	}
+	if u.Comment != nil {
+		s.Comment = *u.Comment
+	}
+}
@@ -0,0 +1,116 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+func TestSilence_Active(t *testing.T) {
+	start := time.Date(2019, 12, 2, 22, 0, 0, 0, time.UTC) // a monday
+	until := start.Add(21 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		silence influxdb.Silence
+		at      time.Time
+		want    bool
+	}{
+		{
+			name:    "before start",
+			silence: influxdb.Silence{Start: start, End: start.Add(time.Hour)},
+			at:      start.Add(-time.Second),
+		},
+		{
+			name:    "at start",
+			silence: influxdb.Silence{Start: start, End: start.Add(time.Hour)},
+			at:      start,
+			want:    true,
+		},
+		{
+			name:    "at end",
+			silence: influxdb.Silence{Start: start, End: start.Add(time.Hour)},
+			at:      start.Add(time.Hour),
+		},
+		{
+			name:    "weekly window a week later",
+			silence: influxdb.Silence{Start: start, End: start.Add(2 * time.Hour), Repeat: options.MustParseDuration("1w")},
+			at:      start.Add(7*24*time.Hour + time.Hour),
+			want:    true,
+		},
+		{
+			name:    "weekly window between windows",
+			silence: influxdb.Silence{Start: start, End: start.Add(2 * time.Hour), Repeat: options.MustParseDuration("1w")},
+			at:      start.Add(3 * 24 * time.Hour),
+		},
+		{
+			name:    "weekly window after until",
+			silence: influxdb.Silence{Start: start, End: start.Add(2 * time.Hour), Repeat: options.MustParseDuration("1w"), Until: &until},
+			at:      start.Add(28*24*time.Hour + time.Hour),
+		},
+		{
+			name:    "monthly window",
+			silence: influxdb.Silence{Start: start, End: start.Add(2 * time.Hour), Repeat: options.MustParseDuration("1mo")},
+			at:      time.Date(2020, 3, 2, 23, 0, 0, 0, time.UTC),
+			want:    true,
+		},
+		{
+			name:    "monthly window the day after",
+			silence: influxdb.Silence{Start: start, End: start.Add(2 * time.Hour), Repeat: options.MustParseDuration("1mo")},
+			at:      time.Date(2020, 3, 3, 23, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.silence.Active(tt.at); got != tt.want {
+				t.Errorf("expected active %v at %s, got %v", tt.want, tt.at, got)
+			}
+		})
+	}
+}
+
+func TestSilence_Valid(t *testing.T) {
+	start := time.Date(2019, 12, 2, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		silence influxdb.Silence
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			silence: influxdb.Silence{OrgID: 1, Start: start, End: start.Add(time.Hour)},
+		},
+		{
+			name:    "missing org",
+			silence: influxdb.Silence{Start: start, End: start.Add(time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "missing end",
+			silence: influxdb.Silence{OrgID: 1, Start: start},
+			wantErr: true,
+		},
+		{
+			name:    "negative repeat",
+			silence: influxdb.Silence{OrgID: 1, Start: start, End: start.Add(time.Hour), Repeat: options.MustParseDuration("-1d")},
+			wantErr: true,
+		},
+		{
+			name: "invalid regex matcher",
+			silence: influxdb.Silence{OrgID: 1, Start: start, End: start.Add(time.Hour), Matchers: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "host", Value: "("}, Operator: influxdb.RegexEqual},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.silence.Valid(); (err != nil) != tt.wantErr {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -114,6 +114,17 @@ func WithRetentionEnforcer(finder BucketFinder) Option {
 	}
 }
 
+// WithRetentionEnforcerDeleter wraps the deleter the retention enforcer deletes
+// expired data with, for example to act on the deletes. It must be called after
+// WithRetentionEnforcer.
+func WithRetentionEnforcerDeleter(wrap func(Deleter) Deleter) Option {
+	return func(e *Engine) {
+		if r, ok := e.retentionEnforcer.(*retentionEnforcer); ok {
+			r.Engine = wrap(r.Engine)
+		}
+	}
+}
+
 // WithRetentionEnforcerLimiter sets a limiter used to control when the
 // retention enforcer can proceed. If this option is not used then the default
 // limiter (or the absence of one) is a no-op, and no limitations will be put
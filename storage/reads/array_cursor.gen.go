@@ -104,6 +104,16 @@ func (c *floatMultiShardArrayCursor) reset(cur cursors.FloatArrayCursor, itrs cu
 	c.itrs = itrs
 	c.err = nil
 	c.count = 0
+	c.scanned = 0
+}
+
+// abort closes the remaining shards of the cursor and sets err as
+// the error of the cursor. Subsequent calls to Next return no values.
+func (c *floatMultiShardArrayCursor) abort(err error) {
+	c.FloatArrayCursor.Close()
+	c.FloatArrayCursor = FloatEmptyArrayCursor
+	c.itrs = nil
+	c.err = err
 }
 
 func (c *floatMultiShardArrayCursor) Err() error { return c.err }
@@ -115,6 +125,10 @@ func (c *floatMultiShardArrayCursor) Stats() cursors.CursorStats {
 func (c *floatMultiShardArrayCursor) Next() *cursors.FloatArray {
 	for {
 		a := c.FloatArrayCursor.Next()
+		if err := c.scan(c.FloatArrayCursor); err != nil {
+			c.abort(err)
+			return &cursors.FloatArray{}
+		}
 		if a.Len() == 0 {
 			if c.nextArrayCursor() {
 				continue
@@ -162,6 +176,7 @@ func (c *floatMultiShardArrayCursor) nextArrayCursor() bool {
 			}
 		}
 		c.FloatArrayCursor = next
+		c.scanned = 0
 	} else {
 		c.FloatArrayCursor = FloatEmptyArrayCursor
 	}
@@ -333,6 +348,16 @@ func (c *integerMultiShardArrayCursor) reset(cur cursors.IntegerArrayCursor, itr
 	c.itrs = itrs
 	c.err = nil
 	c.count = 0
+	c.scanned = 0
+}
+
+// abort closes the remaining shards of the cursor and sets err as
+// the error of the cursor. Subsequent calls to Next return no values.
+func (c *integerMultiShardArrayCursor) abort(err error) {
+	c.IntegerArrayCursor.Close()
+	c.IntegerArrayCursor = IntegerEmptyArrayCursor
+	c.itrs = nil
+	c.err = err
 }
 
 func (c *integerMultiShardArrayCursor) Err() error { return c.err }
@@ -344,6 +369,10 @@ func (c *integerMultiShardArrayCursor) Stats() cursors.CursorStats {
 func (c *integerMultiShardArrayCursor) Next() *cursors.IntegerArray {
 	for {
 		a := c.IntegerArrayCursor.Next()
+		if err := c.scan(c.IntegerArrayCursor); err != nil {
+			c.abort(err)
+			return &cursors.IntegerArray{}
+		}
 		if a.Len() == 0 {
 			if c.nextArrayCursor() {
 				continue
@@ -391,6 +420,7 @@ func (c *integerMultiShardArrayCursor) nextArrayCursor() bool {
 			}
 		}
 		c.IntegerArrayCursor = next
+		c.scanned = 0
 	} else {
 		c.IntegerArrayCursor = IntegerEmptyArrayCursor
 	}
@@ -562,6 +592,16 @@ func (c *unsignedMultiShardArrayCursor) reset(cur cursors.UnsignedArrayCursor, i
 	c.itrs = itrs
 	c.err = nil
 	c.count = 0
+	c.scanned = 0
+}
+
+// abort closes the remaining shards of the cursor and sets err as
+// the error of the cursor. Subsequent calls to Next return no values.
+func (c *unsignedMultiShardArrayCursor) abort(err error) {
+	c.UnsignedArrayCursor.Close()
+	c.UnsignedArrayCursor = UnsignedEmptyArrayCursor
+	c.itrs = nil
+	c.err = err
 }
 
 func (c *unsignedMultiShardArrayCursor) Err() error { return c.err }
@@ -573,6 +613,10 @@ func (c *unsignedMultiShardArrayCursor) Stats() cursors.CursorStats {
 func (c *unsignedMultiShardArrayCursor) Next() *cursors.UnsignedArray {
 	for {
 		a := c.UnsignedArrayCursor.Next()
+		if err := c.scan(c.UnsignedArrayCursor); err != nil {
+			c.abort(err)
+			return &cursors.UnsignedArray{}
+		}
 		if a.Len() == 0 {
 			if c.nextArrayCursor() {
 				continue
@@ -620,6 +664,7 @@ func (c *unsignedMultiShardArrayCursor) nextArrayCursor() bool {
 			}
 		}
 		c.UnsignedArrayCursor = next
+		c.scanned = 0
 	} else {
 		c.UnsignedArrayCursor = UnsignedEmptyArrayCursor
 	}
@@ -791,6 +836,16 @@ func (c *stringMultiShardArrayCursor) reset(cur cursors.StringArrayCursor, itrs
 	c.itrs = itrs
 	c.err = nil
 	c.count = 0
+	c.scanned = 0
+}
+
+// abort closes the remaining shards of the cursor and sets err as
+// the error of the cursor. Subsequent calls to Next return no values.
+func (c *stringMultiShardArrayCursor) abort(err error) {
+	c.StringArrayCursor.Close()
+	c.StringArrayCursor = StringEmptyArrayCursor
+	c.itrs = nil
+	c.err = err
 }
 
 func (c *stringMultiShardArrayCursor) Err() error { return c.err }
@@ -802,6 +857,10 @@ func (c *stringMultiShardArrayCursor) Stats() cursors.CursorStats {
 func (c *stringMultiShardArrayCursor) Next() *cursors.StringArray {
 	for {
 		a := c.StringArrayCursor.Next()
+		if err := c.scan(c.StringArrayCursor); err != nil {
+			c.abort(err)
+			return &cursors.StringArray{}
+		}
 		if a.Len() == 0 {
 			if c.nextArrayCursor() {
 				continue
@@ -849,6 +908,7 @@ func (c *stringMultiShardArrayCursor) nextArrayCursor() bool {
 			}
 		}
 		c.StringArrayCursor = next
+		c.scanned = 0
 	} else {
 		c.StringArrayCursor = StringEmptyArrayCursor
 	}
@@ -980,6 +1040,16 @@ func (c *booleanMultiShardArrayCursor) reset(cur cursors.BooleanArrayCursor, itr
 	c.itrs = itrs
 	c.err = nil
 	c.count = 0
+	c.scanned = 0
+}
+
+// abort closes the remaining shards of the cursor and sets err as
+// the error of the cursor. Subsequent calls to Next return no values.
+func (c *booleanMultiShardArrayCursor) abort(err error) {
+	c.BooleanArrayCursor.Close()
+	c.BooleanArrayCursor = BooleanEmptyArrayCursor
+	c.itrs = nil
+	c.err = err
 }
 
 func (c *booleanMultiShardArrayCursor) Err() error { return c.err }
@@ -991,6 +1061,10 @@ func (c *booleanMultiShardArrayCursor) Stats() cursors.CursorStats {
 func (c *booleanMultiShardArrayCursor) Next() *cursors.BooleanArray {
 	for {
 		a := c.BooleanArrayCursor.Next()
+		if err := c.scan(c.BooleanArrayCursor); err != nil {
+			c.abort(err)
+			return &cursors.BooleanArray{}
+		}
 		if a.Len() == 0 {
 			if c.nextArrayCursor() {
 				continue
@@ -1038,6 +1112,7 @@ func (c *booleanMultiShardArrayCursor) nextArrayCursor() bool {
 			}
 		}
 		c.BooleanArrayCursor = next
+		c.scanned = 0
 	} else {
 		c.BooleanArrayCursor = BooleanEmptyArrayCursor
 	}
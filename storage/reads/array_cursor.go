@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage/reads/datatypes"
 	"github.com/influxdata/influxdb/tsdb/cursors"
 )
@@ -70,12 +71,40 @@ type cursorContext struct {
 	limit int64
 	count int64
 	err   error
+
+	// limiter enforces the scan limits of the query, if it has any.
+	// scanned is the number of values of the current shard cursor
+	// that have already been counted against the limits.
+	limiter *query.ScanLimiter
+	scanned int
+}
+
+// scan counts the values scanned by cur since the last call against
+// the scan limits of the query.
+func (c *cursorContext) scan(cur cursors.Cursor) error {
+	if c.limiter == nil {
+		return nil
+	}
+	n := cur.Stats().ScannedValues
+	if n <= c.scanned {
+		return nil
+	}
+	delta := n - c.scanned
+	c.scanned = n
+	return c.limiter.AddScannedValues(delta)
+}
+
+// limitedCursor is a cursor that can be aborted once a query exceeds its scan limits.
+type limitedCursor interface {
+	cursors.Cursor
+	abort(err error)
 }
 
 type multiShardArrayCursors struct {
-	ctx   context.Context
-	limit int64
-	req   cursors.CursorRequest
+	ctx     context.Context
+	limit   int64
+	req     cursors.CursorRequest
+	limiter *query.ScanLimiter
 
 	cursors struct {
 		i integerMultiShardArrayCursor
@@ -99,12 +128,14 @@ func newMultiShardArrayCursors(ctx context.Context, start, end int64, asc bool,
 			StartTime: start,
 			EndTime:   end,
 		},
+		limiter: query.ScanLimiterFromContext(ctx),
 	}
 
 	cc := cursorContext{
-		ctx:   ctx,
-		limit: limit,
-		req:   &m.req,
+		ctx:     ctx,
+		limit:   limit,
+		req:     &m.req,
+		limiter: m.limiter,
 	}
 
 	m.cursors.i.cursorContext = cc
@@ -137,25 +168,33 @@ func (m *multiShardArrayCursors) createCursor(row SeriesRow) cursors.Cursor {
 		return nil
 	}
 
+	var lc limitedCursor
 	switch c := cur.(type) {
 	case cursors.IntegerArrayCursor:
 		m.cursors.i.reset(c, row.Query, cond)
-		return &m.cursors.i
+		lc = &m.cursors.i
 	case cursors.FloatArrayCursor:
 		m.cursors.f.reset(c, row.Query, cond)
-		return &m.cursors.f
+		lc = &m.cursors.f
 	case cursors.UnsignedArrayCursor:
 		m.cursors.u.reset(c, row.Query, cond)
-		return &m.cursors.u
+		lc = &m.cursors.u
 	case cursors.StringArrayCursor:
 		m.cursors.s.reset(c, row.Query, cond)
-		return &m.cursors.s
+		lc = &m.cursors.s
 	case cursors.BooleanArrayCursor:
 		m.cursors.b.reset(c, row.Query, cond)
-		return &m.cursors.b
+		lc = &m.cursors.b
 	default:
 		panic(fmt.Sprintf("unreachable: %T", cur))
 	}
+
+	if m.limiter != nil {
+		if err := m.limiter.AddSeries(1); err != nil {
+			lc.abort(err)
+		}
+	}
+	return lc
 }
 
 func (m *multiShardArrayCursors) newAggregateCursor(ctx context.Context, agg *datatypes.Aggregate, cursor cursors.Cursor) cursors.Cursor {
@@ -20,6 +20,7 @@ type storageTable interface {
 	flux.Table
 	Close()
 	Cancel()
+	Err() error
 	Statistics() cursors.CursorStats
 }
 
@@ -208,6 +209,12 @@ READ:
 			}
 		}
 
+		// The table may have stopped reading because the query
+		// exceeded its scan limits.
+		if err := table.Err(); err != nil {
+			return err
+		}
+
 		stats := table.Statistics()
 		fi.stats.ScannedValues += stats.ScannedValues
 		fi.stats.ScannedBytes += stats.ScannedBytes
@@ -352,6 +359,10 @@ READ:
 			break READ
 		}
 
+		if err := table.Err(); err != nil {
+			return err
+		}
+
 		stats := table.Statistics()
 		gi.stats.ScannedValues += stats.ScannedValues
 		gi.stats.ScannedBytes += stats.ScannedBytes
@@ -0,0 +1,118 @@
+package reads_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/data/gen"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/storage/reads"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+)
+
+// drainResultSet reads every cursor of rs and returns the number of series
+// and values that were read, along with the first cursor error.
+func drainResultSet(rs reads.ResultSet) (series, values int, err error) {
+	defer rs.Close()
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			continue
+		}
+		series++
+		fc := cur.(cursors.FloatArrayCursor)
+		for {
+			a := fc.Next()
+			if a.Len() == 0 {
+				break
+			}
+			values += a.Len()
+		}
+		err = cur.Err()
+		cur.Close()
+		if err != nil {
+			return series, values, err
+		}
+	}
+	return series, values, rs.Err()
+}
+
+func TestNewFilteredResultSet_ScanLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		gens       func() gen.SeriesGenerator
+		limits     query.ScanLimits
+		kind       query.Kind
+		wantSeries int
+		wantValues int
+		wantErr    string
+	}{
+		{
+			name: "no limits",
+			gens: func() gen.SeriesGenerator {
+				return makeTypedSeries("m0", "t", "f0", 1.0, 2000, 3)
+			},
+			wantSeries: 3,
+			wantValues: 6000,
+		},
+		{
+			name: "within limits",
+			gens: func() gen.SeriesGenerator {
+				return makeTypedSeries("m0", "t", "f0", 1.0, 2000, 1)
+			},
+			limits:     query.ScanLimits{MaxScannedValues: 2000, MaxSeries: 1},
+			kind:       query.KindQuery,
+			wantSeries: 1,
+			wantValues: 2000,
+		},
+		{
+			name: "max scanned values",
+			gens: func() gen.SeriesGenerator {
+				return makeTypedSeries("m0", "t", "f0", 1.0, 5000, 1)
+			},
+			limits:     query.ScanLimits{MaxScannedValues: 2500},
+			kind:       query.KindQuery,
+			wantSeries: 1,
+			wantValues: 2000,
+			wantErr:    "query-max-scanned-values",
+		},
+		{
+			name: "max series",
+			gens: func() gen.SeriesGenerator {
+				return makeTypedSeries("m0", "t", "f0", 1.0, 10, 5)
+			},
+			limits:     query.ScanLimits{MaxSeries: 2},
+			kind:       query.KindTask,
+			wantSeries: 3,
+			wantValues: 20,
+			wantErr:    "task-query-max-series",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if !tt.limits.IsZero() {
+				ctx = query.ContextWithScanLimiter(ctx, query.NewScanLimiter(tt.kind, tt.limits))
+			}
+
+			cur := newSeriesGeneratorSeriesCursor(tt.gens())
+			rs := reads.NewFilteredResultSet(ctx, &datatypes.ReadFilterRequest{}, cur)
+			series, values, err := drainResultSet(rs)
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error naming %q, got %v", tt.wantErr, err)
+			}
+			if series != tt.wantSeries {
+				t.Errorf("unexpected number of series; got %d, want %d", series, tt.wantSeries)
+			}
+			if values != tt.wantValues {
+				t.Errorf("unexpected number of values; got %d, want %d", values, tt.wantValues)
+			}
+		})
+	}
+}
@@ -81,6 +81,7 @@ func (t *floatTable) advance() bool {
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		t.err = t.cur.Err()
 		return false
 	}
 
@@ -150,6 +151,10 @@ RETRY:
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		if err := t.cur.Err(); err != nil {
+			t.err = err
+			return false
+		}
 		if t.advanceCursor() {
 			goto RETRY
 		}
@@ -266,6 +271,7 @@ func (t *integerTable) advance() bool {
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		t.err = t.cur.Err()
 		return false
 	}
 
@@ -335,6 +341,10 @@ RETRY:
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		if err := t.cur.Err(); err != nil {
+			t.err = err
+			return false
+		}
 		if t.advanceCursor() {
 			goto RETRY
 		}
@@ -451,6 +461,7 @@ func (t *unsignedTable) advance() bool {
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		t.err = t.cur.Err()
 		return false
 	}
 
@@ -520,6 +531,10 @@ RETRY:
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		if err := t.cur.Err(); err != nil {
+			t.err = err
+			return false
+		}
 		if t.advanceCursor() {
 			goto RETRY
 		}
@@ -636,6 +651,7 @@ func (t *stringTable) advance() bool {
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		t.err = t.cur.Err()
 		return false
 	}
 
@@ -705,6 +721,10 @@ RETRY:
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		if err := t.cur.Err(); err != nil {
+			t.err = err
+			return false
+		}
 		if t.advanceCursor() {
 			goto RETRY
 		}
@@ -821,6 +841,7 @@ func (t *booleanTable) advance() bool {
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		t.err = t.cur.Err()
 		return false
 	}
 
@@ -890,6 +911,10 @@ RETRY:
 	a := t.cur.Next()
 	l := a.Len()
 	if l == 0 {
+		if err := t.cur.Err(); err != nil {
+			t.err = err
+			return false
+		}
 		if t.advanceCursor() {
 			goto RETRY
 		}
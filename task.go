@@ -56,7 +56,14 @@ type Task struct {
 	Flux            string                 `json:"flux"`
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
+	Location        string                 `json:"location,omitempty"`
 	Offset          time.Duration          `json:"offset,omitempty"`
+	After           []ID                   `json:"after,omitempty"`
+	Timeout         time.Duration          `json:"timeout,omitempty"`
+	Retry           int64                  `json:"retry,omitempty"`
+	RetryDelay      time.Duration          `json:"retryDelay,omitempty"`
+	RetryMaxDelay   time.Duration          `json:"retryMaxDelay,omitempty"`
+	Version         int                    `json:"version,omitempty"` // Version is the number of the current version of the Flux script
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
@@ -64,6 +71,11 @@ type Task struct {
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time              `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+
+	// Notification is the notification endpoint the task notifies of its failed runs, nil if it notifies none.
+	Notification *TaskNotification `json:"notification,omitempty"`
+	// ConsecutiveFailures is the number of runs that failed since the last successful run.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
 }
 
 // EffectiveCron returns the effective cron string of the options.
@@ -81,6 +93,15 @@ func (t *Task) EffectiveCron() string {
 	return ""
 }
 
+// EffectiveLocation returns the location the cron schedule of the task is evaluated in.
+// It is UTC when the location option was not specified.
+func (t *Task) EffectiveLocation() (*time.Location, error) {
+	if t.Location == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(t.Location)
+}
+
 // Run is a record createId when a run of a task is scheduled.
 type Run struct {
 	ID           ID        `json:"id,omitempty"`
@@ -91,7 +112,14 @@ type Run struct {
 	StartedAt    time.Time `json:"startedAt,omitempty"`   // StartedAt is the time the executor begins running the task
 	FinishedAt   time.Time `json:"finishedAt,omitempty"`  // FinishedAt is the time the executor finishes running the task
 	RequestedAt  time.Time `json:"requestedAt,omitempty"` // RequestedAt is the time the coordinator told the scheduler to schedule the task
+	RetryOf      ID        `json:"retryOf,omitempty"`     // RetryOf is the ID of the run that failed and is retried by this run
+	Attempt      int       `json:"attempt,omitempty"`     // Attempt is the number of the automatic retry, zero for the original run
+	TaskVersion  int       `json:"taskVersion,omitempty"` // TaskVersion is the version of the task script the run executed
 	Log          []Log     `json:"log,omitempty"`
+
+	// ConsecutiveFailures is the number of consecutive failed runs of the task recorded when the run finished,
+	// including this run if it failed, or the number of failed runs it recovered from if it succeeded.
+	ConsecutiveFailures int `json:"-"`
 }
 
 // Log represents a link to a log resource
@@ -154,6 +182,9 @@ type TaskCreate struct {
 	Organization   string                 `json:"org,omitempty"`
 	OwnerID        ID                     `json:"-"`
 	Metadata       map[string]interface{} `json:"-"` // not to be set through a web request but rather used by a http service using tasks backend.
+
+	// Notification is the notification endpoint the task notifies of its failed runs.
+	Notification *TaskNotification `json:"notification,omitempty"`
 }
 
 func (t TaskCreate) Validate() error {
@@ -165,6 +196,9 @@ func (t TaskCreate) Validate() error {
 	case t.Status != "" && t.Status != TaskStatusActive && t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", t.Status)
 	}
+	if t.Notification != nil {
+		return t.Notification.Valid()
+	}
 	return nil
 }
 
@@ -174,6 +208,12 @@ type TaskUpdate struct {
 	Status      *string `json:"status,omitempty"`
 	Description *string `json:"description,omitempty"`
 
+	// VersionDescription describes the change of the Flux script, it is recorded with the new version of the task.
+	VersionDescription string `json:"versionDescription,omitempty"`
+
+	// Notification replaces the notification of the task, a notification without endpoint removes it.
+	Notification *TaskNotification `json:"notification,omitempty"`
+
 	// LatestCompleted us to set latest completed on startup to skip task catchup
 	LatestCompleted *time.Time             `json:"-"`
 	LatestScheduled *time.Time             `json:"-"`
@@ -181,6 +221,9 @@ type TaskUpdate struct {
 	LastRunError    *string                `json:"-"`
 	Metadata        map[string]interface{} `json:"-"` // not to be set through a web request but rather used by a http service using tasks backend.
 
+	// ConsecutiveFailures sets the number of runs that failed since the last successful run.
+	ConsecutiveFailures *int `json:"-"`
+
 	// Options gets unmarshalled from json as if it was flat, with the same level as Flux and Status.
 	Options options.Options // when we unmarshal this gets unmarshalled from flat key-values
 }
@@ -193,9 +236,16 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		Name        string  `json:"name,omitempty"`
 		Description *string `json:"description,omitempty"`
 
+		VersionDescription string `json:"versionDescription,omitempty"`
+
+		Notification *TaskNotification `json:"notification,omitempty"`
+
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
 
+		// Location is the IANA time zone name the Cron schedule is evaluated in.
+		Location string `json:"location,omitempty"`
+
 		// Every represents a fixed period to repeat execution.
 		// It gets marshalled from a string duration, i.e.: "10s" is 10 seconds
 		Every options.Duration `json:"every,omitempty"`
@@ -207,6 +257,9 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		// After is the IDs of the upstream tasks, an empty list removes them.
+		After *[]string `json:"after,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, &jo); err != nil {
@@ -214,7 +267,10 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	}
 	t.Options.Name = jo.Name
 	t.Description = jo.Description
+	t.VersionDescription = jo.VersionDescription
+	t.Notification = jo.Notification
 	t.Options.Cron = jo.Cron
+	t.Options.Location = jo.Location
 	t.Options.Every = jo.Every
 	if jo.Offset != nil {
 		offset := *jo.Offset
@@ -222,6 +278,9 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	}
 	t.Options.Concurrency = jo.Concurrency
 	t.Options.Retry = jo.Retry
+	if jo.After != nil {
+		t.Options.After = append([]string{}, *jo.After...)
+	}
 	t.Flux = jo.Flux
 	t.Status = jo.Status
 	return nil
@@ -234,9 +293,16 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 		Name        string  `json:"name,omitempty"`
 		Description *string `json:"description,omitempty"`
 
+		VersionDescription string `json:"versionDescription,omitempty"`
+
+		Notification *TaskNotification `json:"notification,omitempty"`
+
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
 
+		// Location is the IANA time zone name the Cron schedule is evaluated in.
+		Location string `json:"location,omitempty"`
+
 		// Every represents a fixed period to repeat execution.
 		Every options.Duration `json:"every,omitempty"`
 
@@ -246,17 +312,26 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		After *[]string `json:"after,omitempty"`
 	}{}
 	jo.Name = t.Options.Name
 	jo.Cron = t.Options.Cron
+	jo.Location = t.Options.Location
 	jo.Every = t.Options.Every
 	jo.Description = t.Description
+	jo.VersionDescription = t.VersionDescription
+	jo.Notification = t.Notification
 	if t.Options.Offset != nil {
 		offset := *t.Options.Offset
 		jo.Offset = &offset
 	}
 	jo.Concurrency = t.Options.Concurrency
 	jo.Retry = t.Options.Retry
+	if t.Options.After != nil {
+		after := t.Options.After
+		jo.After = &after
+	}
 	jo.Flux = t.Flux
 	jo.Status = t.Status
 	return json.Marshal(jo)
@@ -274,11 +349,14 @@ func (t TaskUpdate) Validate() error {
 		if _, err := time.ParseDuration(t.Options.Offset.String()); err != nil {
 			return fmt.Errorf("offset: %s, %s is invalid", t.Options.Offset.String(), err)
 		}
-	case t.Flux == nil && t.Status == nil && t.Options.IsZero():
+	case t.Flux == nil && t.Status == nil && t.Notification == nil && t.Options.IsZero():
 		return errors.New("cannot update task without content")
 	case t.Status != nil && *t.Status != TaskStatusActive && *t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", *t.Status)
 	}
+	if t.Notification != nil && t.Notification.EndpointID.Valid() {
+		return t.Notification.Valid()
+	}
 	return nil
 }
 
@@ -317,17 +395,22 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) (err error) {
 	if !t.Options.Every.IsZero() && t.Options.Cron != "" {
 		return errors.New("cannot specify both cron and every")
 	}
-	op := make(map[string]ast.Expression, 4)
+	op := make(map[string]ast.Expression, 6)
 
 	if t.Options.Name != "" {
 		op["name"] = &ast.StringLiteral{Value: t.Options.Name}
 	}
 	if !t.Options.Every.IsZero() {
 		op["every"] = &t.Options.Every.Node
+		// the location only applies to cron schedules.
+		toDelete["location"] = struct{}{}
 	}
 	if t.Options.Cron != "" {
 		op["cron"] = &ast.StringLiteral{Value: t.Options.Cron}
 	}
+	if t.Options.Location != "" {
+		op["location"] = &ast.StringLiteral{Value: t.Options.Location}
+	}
 	if t.Options.Offset != nil {
 		if !t.Options.Offset.IsZero() {
 			op["offset"] = &t.Options.Offset.Node
@@ -335,6 +418,17 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) (err error) {
 			toDelete["offset"] = struct{}{}
 		}
 	}
+	if t.Options.After != nil {
+		if len(t.Options.After) > 0 {
+			after := &ast.ArrayExpression{Elements: make([]ast.Expression, 0, len(t.Options.After))}
+			for _, id := range t.Options.After {
+				after.Elements = append(after.Elements, &ast.StringLiteral{Value: id})
+			}
+			op["after"] = after
+		} else {
+			toDelete["after"] = struct{}{}
+		}
+	}
 	if len(op) > 0 || len(toDelete) > 0 {
 		editFunc := func(opt *ast.OptionStatement) (ast.Expression, error) {
 			a, ok := opt.Assignment.(*ast.VariableAssignment)
@@ -345,12 +439,17 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) (err error) {
 			if !ok {
 				return nil, fmt.Errorf("value is is %s, not an object expression", a.Init.Type())
 			}
+			// remove the keys that are deleted
+			props := obj.Properties[:0]
+			for _, p := range obj.Properties {
+				if _, ok := toDelete[p.Key.Key()]; !ok {
+					props = append(props, p)
+				}
+			}
+			obj.Properties = props
 			// modify in the keys and values that already are in the ast
-			for i, p := range obj.Properties {
+			for _, p := range obj.Properties {
 				k := p.Key.Key()
-				if _, ok := toDelete[k]; ok {
-					obj.Properties = append(obj.Properties[:i], obj.Properties[i+1:]...)
-				}
 				switch k {
 				case "name":
 					if name, ok := op["name"]; ok && t.Options.Name != "" {
@@ -380,6 +479,16 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) (err error) {
 						p.Key = &ast.Identifier{Name: "every"}
 						p.Value = every.Copy().(*ast.DurationLiteral)
 					}
+				case "location":
+					if location, ok := op["location"]; ok {
+						delete(op, "location")
+						p.Value = location
+					}
+				case "after":
+					if after, ok := op["after"]; ok {
+						delete(op, "after")
+						p.Value = after
+					}
 				}
 			}
 			// add in new keys and values to the ast
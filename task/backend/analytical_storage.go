@@ -22,6 +22,9 @@ const (
 	finishedAtField   = "finishedAt"
 	requestedAtField  = "requestedAt"
 	logField          = "logs"
+	retryOfField      = "retryOf"
+	attemptField      = "attempt"
+	taskVersionField  = "taskVersion"
 
 	taskIDTag = "taskID"
 	statusTag = "status"
@@ -399,6 +402,23 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 					continue
 				}
 				r.FinishedAt = finished.UTC()
+			case retryOfField:
+				if cr.Strings(j).ValueString(i) != "" {
+					id, err := influxdb.IDFromString(cr.Strings(j).ValueString(i))
+					if err != nil {
+						re.log.Info("Failed to parse retryOf", zap.Error(err))
+						continue
+					}
+					r.RetryOf = *id
+				}
+			case attemptField:
+				if vs := cr.Ints(j); vs.IsValid(i) {
+					r.Attempt = int(vs.Value(i))
+				}
+			case taskVersionField:
+				if vs := cr.Ints(j); vs.IsValid(i) {
+					r.TaskVersion = int(vs.Value(i))
+				}
 			case logField:
 				logBytes := bytes.TrimSpace(cr.Strings(j).Value(i))
 				if len(logBytes) != 0 {
@@ -0,0 +1,315 @@
+// Package backfill executes the runs of tasks over historical ranges.
+package backfill
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/snowflake"
+	"github.com/influxdata/influxdb/task/backend/executor"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.TaskBackfillService = (*Service)(nil)
+var _ Executor = (*executor.Executor)(nil)
+
+// Retention is how long a finished backfill can be found after it finished.
+const Retention = 24 * time.Hour
+
+// Executor is an abstraction of the task executor with only the functions needed by a backfill.
+type Executor interface {
+	PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error)
+}
+
+// TaskFinder finds the task of a backfill.
+type TaskFinder interface {
+	FindTaskByID(ctx context.Context, id influxdb.ID) (*influxdb.Task, error)
+}
+
+// Service executes backfills using the task executor.
+// Backfills are kept in memory, a backfill does not resume after a restart.
+type Service struct {
+	log *zap.Logger
+	ts  TaskFinder
+	ex  Executor
+
+	IDGenerator influxdb.IDGenerator
+	now         func() time.Time
+
+	mu        sync.Mutex
+	backfills map[influxdb.ID]*backfill
+	wg        sync.WaitGroup
+}
+
+// backfill is the state of an executing backfill.
+type backfill struct {
+	influxdb.Backfill
+	cancel context.CancelFunc
+}
+
+// NewService returns a Service executing the runs of the tasks found by ts with ex.
+func NewService(log *zap.Logger, ts TaskFinder, ex Executor) *Service {
+	return &Service{
+		log:         log,
+		ts:          ts,
+		ex:          ex,
+		IDGenerator: snowflake.NewIDGenerator(),
+		now: func() time.Time {
+			return time.Now().UTC()
+		},
+		backfills: make(map[influxdb.ID]*backfill),
+	}
+}
+
+// CreateBackfill starts executing the runs of a task for every schedule point within the range.
+func (s *Service) CreateBackfill(ctx context.Context, bc influxdb.BackfillCreate) (*influxdb.Backfill, error) {
+	if err := bc.Validate(); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	if bc.Concurrency == 0 {
+		bc.Concurrency = influxdb.DefaultBackfillConcurrency
+	}
+
+	t, err := s.ts.FindTaskByID(ctx, bc.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	points, err := SchedulePoints(t, bc.Start, bc.Stop, influxdb.MaxBackfillRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	// The runs must outlive the request creating the backfill.
+	bctx, cancel := context.WithCancel(context.Background())
+	b := &backfill{
+		Backfill: influxdb.Backfill{
+			ID:          s.IDGenerator.ID(),
+			TaskID:      t.ID,
+			Start:       bc.Start.UTC(),
+			Stop:        bc.Stop.UTC(),
+			Concurrency: bc.Concurrency,
+			Status:      influxdb.BackfillStatusRunning,
+			Total:       len(points),
+			CreatedAt:   s.now(),
+		},
+		cancel: cancel,
+	}
+
+	s.mu.Lock()
+	s.prune()
+	s.backfills[b.ID] = b
+	res := b.Backfill
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(bctx, b, points)
+	}()
+	return &res, nil
+}
+
+// execute runs the task at each point with at most the concurrency of the backfill at once.
+func (s *Service) execute(ctx context.Context, b *backfill, points []time.Time) {
+	log := s.log.With(zap.String("taskID", b.TaskID.String()), zap.String("backfillID", b.ID.String()))
+	log.Info("Starting backfill", zap.Int("runs", len(points)))
+
+	limit := make(chan struct{}, b.Concurrency)
+	var wg sync.WaitGroup
+	for _, sf := range points {
+		select {
+		case limit <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		// Backfill runs are due as soon as they are dispatched, not at their schedule point.
+		p, err := s.ex.PromisedExecute(ctx, scheduler.ID(b.TaskID), sf, s.now())
+		if err != nil {
+			log.Info("Failed to execute backfill run", zap.Time("scheduledFor", sf), zap.Error(err))
+			s.finishRun(b, err)
+			<-limit
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-limit }()
+			s.finishRun(b, final(ctx, p).Error())
+		}()
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case ctx.Err() != nil:
+		b.Status = influxdb.BackfillStatusCanceled
+	case b.Failed > 0:
+		b.Status = influxdb.BackfillStatusFailed
+	default:
+		b.Status = influxdb.BackfillStatusSuccess
+	}
+	b.FinishedAt = s.now()
+	b.cancel()
+	log.Info("Finished backfill", zap.String("status", b.Status), zap.Int("succeeded", b.Succeeded), zap.Int("failed", b.Failed))
+}
+
+// final waits for the last attempt of the run of p, following its retries, and returns its promise.
+// Once ctx is canceled, the attempt it waits for is canceled.
+func final(ctx context.Context, p executor.Promise) executor.Promise {
+	for {
+		select {
+		case <-p.Done():
+		case <-ctx.Done():
+			p.Cancel(context.Background())
+			<-p.Done()
+		}
+		rp, ok := p.(executor.RetriedPromise)
+		if !ok {
+			return p
+		}
+		r := rp.Retry()
+		if r == nil {
+			return p
+		}
+		p = r
+	}
+}
+
+func (s *Service) finishRun(b *backfill, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		b.Failed++
+	} else {
+		b.Succeeded++
+	}
+}
+
+// FindBackfillByID returns the progress of a backfill.
+func (s *Service) FindBackfillByID(ctx context.Context, taskID, id influxdb.ID) (*influxdb.Backfill, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.backfills[id]
+	if !ok || b.TaskID != taskID {
+		return nil, influxdb.ErrBackfillNotFound
+	}
+	res := b.Backfill
+	return &res, nil
+}
+
+// FindBackfills returns the backfills of a task, the most recent first.
+func (s *Service) FindBackfills(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Backfill, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+
+	bs := []*influxdb.Backfill{}
+	for _, b := range s.backfills {
+		if b.TaskID != taskID {
+			continue
+		}
+		res := b.Backfill
+		bs = append(bs, &res)
+	}
+	sort.Slice(bs, func(i, j int) bool {
+		return bs[i].CreatedAt.After(bs[j].CreatedAt)
+	})
+	return bs, nil
+}
+
+// CancelBackfill stops dispatching the runs of a backfill and cancels the runs it is executing.
+func (s *Service) CancelBackfill(ctx context.Context, taskID, id influxdb.ID) error {
+	s.mu.Lock()
+	b, ok := s.backfills[id]
+	s.mu.Unlock()
+	if !ok || b.TaskID != taskID {
+		return influxdb.ErrBackfillNotFound
+	}
+	b.cancel()
+	return nil
+}
+
+// Close cancels all backfills and waits for their runs to finish.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	for _, b := range s.backfills {
+		b.cancel()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+// prune forgets the backfills that finished longer than Retention ago.
+// The caller must hold s.mu.
+func (s *Service) prune() {
+	now := s.now()
+	for id, b := range s.backfills {
+		if b.Done() && now.Sub(b.FinishedAt) > Retention {
+			delete(s.backfills, id)
+		}
+	}
+}
+
+// SchedulePoints returns the times within [start, stop) the task is scheduled for
+// according to its cron or every option.
+// Like the scheduler, the points are the now times of the runs; the offset of the
+// task only delays when its live runs execute.
+// An error is returned if there are more than limit points.
+func SchedulePoints(t *influxdb.Task, start, stop time.Time, limit int) ([]time.Time, error) {
+	effCron := t.EffectiveCron()
+	if effCron == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "task has no cron or every option",
+		}
+	}
+
+	// The schedule returns the points after the last scheduled time, so start just before
+	// the range. Every schedules are aligned to their period.
+	loc, err := t.EffectiveLocation()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	sch, ts, err := scheduler.NewScheduleInLocation(effCron, loc, start.Add(-time.Second))
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	var points []time.Time
+	for {
+		ts, err = sch.Next(ts)
+		if err != nil {
+			return nil, err
+		}
+		if !ts.Before(stop) {
+			return points, nil
+		}
+		if ts.Before(start) {
+			continue
+		}
+		if len(points) == limit {
+			return nil, influxdb.ErrBackfillTooLarge
+		}
+		points = append(points, ts)
+	}
+}
@@ -0,0 +1,349 @@
+package backfill_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/task/backend/backfill"
+	"github.com/influxdata/influxdb/task/backend/executor"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSchedulePoints(t *testing.T) {
+	start := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		task        influxdb.Task
+		start, stop time.Time
+		limit       int
+		want        []time.Time
+		wantErr     bool
+	}{
+		{
+			name:  "every",
+			task:  influxdb.Task{Every: "1h"},
+			start: start,
+			stop:  start.Add(3 * time.Hour),
+			limit: 10,
+			want:  []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour)},
+		},
+		{
+			name:  "every unaligned range",
+			task:  influxdb.Task{Every: "1h"},
+			start: start.Add(30 * time.Minute),
+			stop:  start.Add(150 * time.Minute),
+			limit: 10,
+			want:  []time.Time{start.Add(time.Hour), start.Add(2 * time.Hour)},
+		},
+		{
+			name:  "cron",
+			task:  influxdb.Task{Cron: "0 12 * * *"},
+			start: start,
+			stop:  start.Add(72 * time.Hour),
+			limit: 10,
+			want:  []time.Time{start.Add(12 * time.Hour), start.Add(36 * time.Hour), start.Add(60 * time.Hour)},
+		},
+		{
+			name:  "offset",
+			task:  influxdb.Task{Every: "1h", Offset: 10 * time.Minute},
+			start: start,
+			stop:  start.Add(2 * time.Hour),
+			limit: 10,
+			want:  []time.Time{start, start.Add(time.Hour)},
+		},
+		{
+			name:  "empty range",
+			task:  influxdb.Task{Every: "1h"},
+			start: start.Add(time.Minute),
+			stop:  start.Add(59 * time.Minute),
+			limit: 10,
+		},
+		{
+			name:    "too many points",
+			task:    influxdb.Task{Every: "1m"},
+			start:   start,
+			stop:    start.Add(time.Hour),
+			limit:   10,
+			wantErr: true,
+		},
+		{
+			name:    "no schedule",
+			task:    influxdb.Task{},
+			start:   start,
+			stop:    start.Add(time.Hour),
+			limit:   10,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backfill.SchedulePoints(&tt.task, tt.start, tt.stop, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !cmp.Equal(tt.want, got) {
+				t.Errorf("unexpected points -want/+got:\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+// promise is a run finishing when finish is called.
+type promise struct {
+	done  chan struct{}
+	err   error
+	retry *promise
+}
+
+func newPromise() *promise {
+	return &promise{done: make(chan struct{})}
+}
+
+func (p *promise) ID() influxdb.ID            { return 1 }
+func (p *promise) Cancel(ctx context.Context) {}
+func (p *promise) Done() <-chan struct{}      { return p.done }
+func (p *promise) Error() error               { <-p.done; return p.err }
+
+func (p *promise) Retry() executor.Promise {
+	<-p.done
+	if p.retry == nil {
+		return nil
+	}
+	return p.retry
+}
+
+func (p *promise) finish(err error) {
+	p.err = err
+	close(p.done)
+}
+
+func newTaskFinder(task *influxdb.Task) backfill.TaskFinder {
+	ts := mock.NewTaskService()
+	ts.FindTaskByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Task, error) {
+		if id != task.ID {
+			return nil, influxdb.ErrTaskNotFound
+		}
+		return task, nil
+	}
+	return ts
+}
+
+// executorFunc executes runs by calling the func.
+type executorFunc func(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error)
+
+func (f executorFunc) PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error) {
+	return f(ctx, id, scheduledFor, runAt)
+}
+
+func waitDone(t *testing.T, s *backfill.Service, b *influxdb.Backfill) *influxdb.Backfill {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		got, err := s.FindBackfillByID(context.Background(), b.TaskID, b.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Done() {
+			return got
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("backfill did not finish")
+	return nil
+}
+
+func TestService_CreateBackfill(t *testing.T) {
+	task := &influxdb.Task{ID: 1, Every: "1h"}
+	start := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	var (
+		mu             sync.Mutex
+		scheduled      []time.Time
+		active, maxAct int
+	)
+	ex := executorFunc(func(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error) {
+		mu.Lock()
+		scheduled = append(scheduled, scheduledFor)
+		active++
+		if active > maxAct {
+			maxAct = active
+		}
+		mu.Unlock()
+
+		p := newPromise()
+		go func() {
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			active--
+			mu.Unlock()
+			if scheduledFor.Hour() == 3 {
+				p.finish(errors.New("run failed"))
+				return
+			}
+			p.finish(nil)
+		}()
+		return p, nil
+	})
+
+	s := backfill.NewService(zaptest.NewLogger(t), newTaskFinder(task), ex)
+	defer s.Close()
+
+	b, err := s.CreateBackfill(context.Background(), influxdb.BackfillCreate{
+		TaskID:      task.ID,
+		Start:       start,
+		Stop:        start.Add(12 * time.Hour),
+		Concurrency: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Total != 12 || b.Status != influxdb.BackfillStatusRunning {
+		t.Fatalf("unexpected backfill: %+v", b)
+	}
+
+	got := waitDone(t, s, b)
+	if got.Status != influxdb.BackfillStatusFailed || got.Succeeded != 11 || got.Failed != 1 {
+		t.Errorf("unexpected backfill progress: %+v", got)
+	}
+	if got.FinishedAt.IsZero() {
+		t.Error("expected the finish time to be set")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(scheduled) != 12 || !scheduled[0].Equal(start) {
+		t.Errorf("unexpected scheduled runs: %v", scheduled)
+	}
+	if maxAct > 3 {
+		t.Errorf("expected at most 3 concurrent runs, got %d", maxAct)
+	}
+
+	bs, err := s.FindBackfills(context.Background(), task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bs) != 1 || bs[0].ID != b.ID {
+		t.Errorf("unexpected backfills: %+v", bs)
+	}
+}
+
+func TestService_CreateBackfill_Retry(t *testing.T) {
+	task := &influxdb.Task{ID: 1, Every: "1h"}
+	start := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	// The first attempt of every run fails, and its retry succeeds after the backfill ran all its first attempts.
+	ex := executorFunc(func(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error) {
+		p, r := newPromise(), newPromise()
+		p.retry = r
+		p.finish(errors.New("run failed"))
+		go func() {
+			select {
+			case <-time.After(20 * time.Millisecond):
+				r.finish(nil)
+			case <-ctx.Done():
+				r.finish(ctx.Err())
+			}
+		}()
+		return p, nil
+	})
+
+	s := backfill.NewService(zaptest.NewLogger(t), newTaskFinder(task), ex)
+	defer s.Close()
+
+	b, err := s.CreateBackfill(context.Background(), influxdb.BackfillCreate{
+		TaskID:      task.ID,
+		Start:       start,
+		Stop:        start.Add(4 * time.Hour),
+		Concurrency: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := waitDone(t, s, b)
+	if got.Status != influxdb.BackfillStatusSuccess || got.Succeeded != 4 || got.Failed != 0 {
+		t.Errorf("expected the outcome of the retries to be counted, got %+v", got)
+	}
+}
+
+func TestService_CancelBackfill(t *testing.T) {
+	task := &influxdb.Task{ID: 1, Every: "1h"}
+	start := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	var (
+		mu      sync.Mutex
+		started int
+	)
+	ex := executorFunc(func(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error) {
+		mu.Lock()
+		started++
+		mu.Unlock()
+
+		// Runs only finish when they are canceled.
+		p := newPromise()
+		go func() {
+			<-ctx.Done()
+			p.finish(ctx.Err())
+		}()
+		return p, nil
+	})
+
+	s := backfill.NewService(zaptest.NewLogger(t), newTaskFinder(task), ex)
+	defer s.Close()
+
+	b, err := s.CreateBackfill(context.Background(), influxdb.BackfillCreate{
+		TaskID:      task.ID,
+		Start:       start,
+		Stop:        start.Add(100 * time.Hour),
+		Concurrency: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.CancelBackfill(context.Background(), task.ID, b.ID); err != nil {
+		t.Fatal(err)
+	}
+	got := waitDone(t, s, b)
+	if got.Status != influxdb.BackfillStatusCanceled {
+		t.Errorf("unexpected status %q", got.Status)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if started > 2 {
+		t.Errorf("expected no runs to start after canceling, got %d", started)
+	}
+
+	if err := s.CancelBackfill(context.Background(), 2, b.ID); err != influxdb.ErrBackfillNotFound {
+		t.Errorf("expected backfill not found for another task, got %v", err)
+	}
+}
+
+func TestService_CreateBackfill_Invalid(t *testing.T) {
+	task := &influxdb.Task{ID: 1, Every: "1m"}
+	start := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	ex := executorFunc(func(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (executor.Promise, error) {
+		t.Fatal("unexpected run")
+		return nil, nil
+	})
+	s := backfill.NewService(zaptest.NewLogger(t), newTaskFinder(task), ex)
+	defer s.Close()
+
+	for _, bc := range []influxdb.BackfillCreate{
+		{TaskID: task.ID, Start: start, Stop: start},
+		{TaskID: task.ID, Start: start, Stop: start.Add(time.Hour), Concurrency: influxdb.MaxBackfillConcurrency + 1},
+		{TaskID: task.ID, Start: start, Stop: start.AddDate(1, 0, 0)},
+		{TaskID: 2, Start: start, Stop: start.Add(time.Hour)},
+	} {
+		if _, err := s.CreateBackfill(context.Background(), bc); err == nil {
+			t.Errorf("expected an error creating %+v", bc)
+		}
+	}
+}
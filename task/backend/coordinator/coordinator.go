@@ -15,6 +15,7 @@ import (
 
 var _ middleware.Coordinator = (*Coordinator)(nil)
 var _ Executor = (*executor.Executor)(nil)
+var _ scheduler.Upstreamer = SchedulableTask{}
 
 // DefaultLimit is the maximum number of tasks that a given taskd server can own
 const DefaultLimit = 1000
@@ -62,6 +63,18 @@ func (t SchedulableTask) LastScheduled() time.Time {
 	return t.lsc
 }
 
+// Upstreams returns the IDs of the tasks the runs of the Task wait on
+func (t SchedulableTask) Upstreams() []scheduler.ID {
+	if len(t.Task.After) == 0 {
+		return nil
+	}
+	ids := make([]scheduler.ID, 0, len(t.Task.After))
+	for _, id := range t.Task.After {
+		ids = append(ids, scheduler.ID(id))
+	}
+	return ids
+}
+
 func WithLimitOpt(i int) CoordinatorOption {
 	return func(c *Coordinator) {
 		c.limit = i
@@ -82,9 +95,13 @@ func NewSchedulableTask(task *influxdb.Task) (SchedulableTask, error) {
 		ts = task.LatestScheduled
 	}
 
+	loc, err := task.EffectiveLocation()
+	if err != nil {
+		return SchedulableTask{}, err
+	}
+
 	var sch scheduler.Schedule
-	var err error
-	sch, ts, err = scheduler.NewSchedule(effCron, ts)
+	sch, ts, err = scheduler.NewScheduleInLocation(effCron, loc, ts)
 	if err != nil {
 		return SchedulableTask{}, err
 	}
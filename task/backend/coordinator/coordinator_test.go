@@ -116,6 +116,24 @@ func TestNewSchedulableTask(t *testing.T) {
 		t.Fatalf("expected SchedulableTask's LatestScheduled to equal %s but it was %s", now.Truncate(time.Second), schedulableT.LastScheduled())
 	}
 
+	created := time.Date(2019, 3, 9, 12, 0, 0, 0, time.UTC)
+	taskThree := &influxdb.Task{ID: one, CreatedAt: created, Cron: "0 9 * * *", Location: "America/New_York", LatestCompleted: created}
+	schedulableT, err = NewSchedulableTask(taskThree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := schedulableT.Schedule().Next(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2019, 3, 9, 14, 0, 0, 0, time.UTC); !next.Equal(exp) {
+		t.Fatalf("expected the cron to be evaluated in the task's location, next is %s but want %s", next.UTC(), exp)
+	}
+
+	taskThree.Location = "Not/AZone"
+	if _, err := NewSchedulableTask(taskThree); err == nil {
+		t.Fatal("expected an error for an unknown location")
+	}
 }
 
 func Test_Coordinator_Scheduler_Methods(t *testing.T) {
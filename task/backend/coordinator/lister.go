@@ -0,0 +1,61 @@
+package coordinator
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap"
+)
+
+var _ scheduler.SchedulableLister = (*TaskLister)(nil)
+
+// TaskFinder is the part of the task service the TaskLister lists tasks with.
+type TaskFinder interface {
+	FindTasks(context.Context, influxdb.TaskFilter) ([]*influxdb.Task, int, error)
+}
+
+// TaskLister lists the active tasks of a task service as SchedulableTasks,
+// the tasks an instance of a LeasedScheduler can be given.
+type TaskLister struct {
+	log *zap.Logger
+	ts  TaskFinder
+}
+
+// NewTaskLister returns a TaskLister listing the tasks of ts.
+func NewTaskLister(log *zap.Logger, ts TaskFinder) *TaskLister {
+	return &TaskLister{
+		log: log,
+		ts:  ts,
+	}
+}
+
+// ListSchedulables returns the active tasks, the tasks that can't be scheduled are skipped.
+func (l *TaskLister) ListSchedulables(ctx context.Context) ([]scheduler.Schedulable, error) {
+	var schs []scheduler.Schedulable
+	filter := influxdb.TaskFilter{Limit: influxdb.TaskMaxPageSize}
+	for {
+		tasks, _, err := l.ts.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(tasks) == 0 {
+			return schs, nil
+		}
+
+		for _, task := range tasks {
+			if task.Status != string(backend.TaskActive) {
+				continue
+			}
+
+			t, err := NewSchedulableTask(task)
+			if err != nil {
+				l.log.Info("Skipping task that can't be scheduled", zap.String("taskID", task.ID.String()), zap.Error(err))
+				continue
+			}
+			schs = append(schs, t)
+		}
+		filter.After = &tasks[len(tasks)-1].ID
+	}
+}
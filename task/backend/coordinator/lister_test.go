@@ -0,0 +1,56 @@
+package coordinator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap/zaptest"
+)
+
+type taskFinderFunc func(context.Context, influxdb.TaskFilter) ([]*influxdb.Task, int, error)
+
+func (f taskFinderFunc) FindTasks(ctx context.Context, filter influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
+	return f(ctx, filter)
+}
+
+func TestTaskLister(t *testing.T) {
+	createdAt := time.Date(2019, 11, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []*influxdb.Task{
+		{ID: 1, Every: "1m", Status: "active", CreatedAt: createdAt},
+		{ID: 2, Every: "1m", Status: "inactive", CreatedAt: createdAt},
+		{ID: 3, Status: "active", CreatedAt: createdAt},
+		{ID: 4, Cron: "0 * * * *", Status: "active", CreatedAt: createdAt},
+	}
+
+	// pages of two tasks.
+	l := NewTaskLister(zaptest.NewLogger(t), taskFinderFunc(func(ctx context.Context, filter influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
+		var page []*influxdb.Task
+		for _, task := range tasks {
+			if filter.After != nil && task.ID <= *filter.After {
+				continue
+			}
+			if len(page) == 2 {
+				break
+			}
+			page = append(page, task)
+		}
+		return page, len(page), nil
+	}))
+
+	schs, err := l.ListSchedulables(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []scheduler.ID
+	for _, sch := range schs {
+		ids = append(ids, sch.ID())
+	}
+	if want := []scheduler.ID{1, 4}; !cmp.Equal(want, ids) {
+		t.Errorf("unexpected schedulables -want/+got:\n%s", cmp.Diff(want, ids))
+	}
+}
@@ -0,0 +1,123 @@
+package executor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// SetAlertTracker sets the tracker the statuses of the runs of checks open and resolve alerts with.
+func (e *Executor) SetAlertTracker(t influxdb.AlertTracker) {
+	e.alerts = t
+}
+
+// trackAlerts hands the statuses of a successful run of a check to the alert tracker.
+// Failing to track them doesn't fail the run, the next statuses of the series catch the alerts up.
+func (e *Executor) trackAlerts(ctx context.Context, task *influxdb.Task, c *statusCapture) {
+	if c == nil || len(c.statuses) == 0 {
+		return
+	}
+	if err := e.alerts.TrackStatuses(ctx, c.list()); err != nil {
+		e.log.Error("Failed to track the alerts of the statuses of a check", zap.String("taskID", task.ID.String()), zap.Error(err))
+	}
+}
+
+// statusCapture collects the last status of each series of the checks of a run.
+type statusCapture struct {
+	orgID    influxdb.ID
+	statuses map[string]influxdb.AlertStatus
+}
+
+func newStatusCapture(orgID influxdb.ID) *statusCapture {
+	return &statusCapture{
+		orgID:    orgID,
+		statuses: make(map[string]influxdb.AlertStatus),
+	}
+}
+
+// capture drains the tables of res, keeping the statuses of checks.
+func (c *statusCapture) capture(res flux.Result) error {
+	return res.Tables().Do(c.captureTable)
+}
+
+// captureTable drains tbl, keeping its statuses.
+// Tables of notifications, or without the ID of a check, are drained only.
+func (c *statusCapture) captureTable(tbl flux.Table) error {
+	cols := tbl.Cols()
+	checkIdx := colIdx(cols, "_check_id")
+	if checkIdx < 0 || colIdx(cols, "_notification_rule_id") >= 0 {
+		return tbl.Do(func(flux.ColReader) error {
+			return nil
+		})
+	}
+
+	// the series of an alert is identified by the tags of its statuses, which are not prefixed by an underscore.
+	tags := make(map[string]string)
+	for j, col := range tbl.Key().Cols() {
+		if col.Type == flux.TString && !strings.HasPrefix(col.Label, "_") {
+			tags[col.Label] = tbl.Key().ValueString(j)
+		}
+	}
+	nameIdx := colIdx(cols, "_check_name")
+	levelIdx := colIdx(cols, "_level")
+	messageIdx := colIdx(cols, "_message")
+	timeIdx := colIdx(cols, "_time")
+	return tbl.Do(func(cr flux.ColReader) error {
+		for r := 0; r < cr.Len(); r++ {
+			s, ok := value(cr, checkIdx, r).(string)
+			if !ok {
+				continue
+			}
+			checkID, err := influxdb.IDFromString(s)
+			if err != nil {
+				continue
+			}
+			st := influxdb.AlertStatus{
+				OrgID:   c.orgID,
+				CheckID: *checkID,
+				Tags:    tags,
+			}
+			if s, ok := value(cr, nameIdx, r).(string); ok {
+				st.CheckName = s
+			}
+			if s, ok := value(cr, levelIdx, r).(string); ok {
+				st.Level = s
+			}
+			if s, ok := value(cr, messageIdx, r).(string); ok {
+				st.Message = s
+			}
+			switch v := value(cr, timeIdx, r).(type) {
+			case int64:
+				st.Time = time.Unix(0, v).UTC()
+			case time.Time:
+				st.Time = v
+			}
+			if st.Level == "" {
+				continue
+			}
+
+			key := checkID.String() + "," + influxdb.AlertSeriesKey(tags)
+			if prev, ok := c.statuses[key]; !ok || !st.Time.Before(prev.Time) {
+				c.statuses[key] = st
+			}
+		}
+		return nil
+	})
+}
+
+// list returns the statuses captured, the oldest first.
+func (c *statusCapture) list() []influxdb.AlertStatus {
+	statuses := make([]influxdb.AlertStatus, 0, len(c.statuses))
+	for _, st := range c.statuses {
+		statuses = append(statuses, st)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Time.Before(statuses[j].Time)
+	})
+	return statuses
+}
@@ -0,0 +1,147 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap/zaptest"
+)
+
+// alertTrackerFunc tracks the statuses by calling the func.
+type alertTrackerFunc func(ctx context.Context, statuses []influxdb.AlertStatus) error
+
+func (f alertTrackerFunc) TrackStatuses(ctx context.Context, statuses []influxdb.AlertStatus) error {
+	return f(ctx, statuses)
+}
+
+func (f alertTrackerFunc) EscalateAlert(ctx context.Context, id, ruleID, endpointID influxdb.ID, at time.Time) (*influxdb.Alert, error) {
+	return nil, nil
+}
+
+// statusTable builds a table of statuses of the check for the host, one per level.
+func statusTable(t *testing.T, keyLabel, checkID, host string, start time.Time, levels ...string) flux.Table {
+	t.Helper()
+	keyCols := []flux.ColMeta{
+		{Label: keyLabel, Type: flux.TString},
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "host", Type: flux.TString},
+	}
+	gk := execute.NewGroupKey(keyCols, []values.Value{
+		values.NewString(checkID),
+		values.NewString("statuses"),
+		values.NewString(host),
+	})
+	b := execute.NewColListTableBuilder(gk, &memory.Allocator{})
+	if err := execute.AddTableKeyCols(gk, b); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []flux.ColMeta{
+		{Label: "_check_id", Type: flux.TString},
+		{Label: "_check_name", Type: flux.TString},
+		{Label: "_level", Type: flux.TString},
+		{Label: "_message", Type: flux.TString},
+		{Label: "_time", Type: flux.TTime},
+	} {
+		if execute.ColIdx(c.Label, b.Cols()) >= 0 {
+			continue
+		}
+		if _, err := b.AddCol(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, level := range levels {
+		for j, c := range b.Cols() {
+			var err error
+			switch c.Label {
+			case "_check_id":
+				err = b.AppendString(j, checkID)
+			case "_measurement":
+				err = b.AppendString(j, "statuses")
+			case "host":
+				err = b.AppendString(j, host)
+			case "_check_name":
+				err = b.AppendString(j, "cpu")
+			case "_level":
+				err = b.AppendString(j, level)
+			case "_message":
+				err = b.AppendString(j, "cpu is "+level)
+			case "_time":
+				err = b.AppendTime(j, values.ConvertTime(start.Add(time.Duration(i)*time.Minute)))
+			default:
+				err = b.AppendString(j, "")
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tbl, err := b.Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tbl
+}
+
+func TestStatusCapture(t *testing.T) {
+	start := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	c := newStatusCapture(1)
+	err := c.capture(&tableResult{name: "statuses", tables: tables{
+		statusTable(t, "_check_id", "0000000000000002", "a", start, "ok", "warn", "crit"),
+		statusTable(t, "_check_id", "0000000000000002", "b", start.Add(time.Hour), "ok"),
+		// notifications are not statuses.
+		statusTable(t, "_notification_rule_id", "0000000000000002", "c", start, "crit"),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tracked []influxdb.AlertStatus
+	e := &Executor{
+		log: zaptest.NewLogger(t),
+		alerts: alertTrackerFunc(func(ctx context.Context, statuses []influxdb.AlertStatus) error {
+			tracked = statuses
+			return nil
+		}),
+	}
+	e.trackAlerts(context.Background(), &influxdb.Task{ID: 3}, c)
+
+	want := []influxdb.AlertStatus{
+		{
+			OrgID:     1,
+			CheckID:   2,
+			CheckName: "cpu",
+			Tags:      map[string]string{"host": "a"},
+			Level:     "crit",
+			Message:   "cpu is crit",
+			Time:      start.Add(2 * time.Minute),
+		},
+		{
+			OrgID:     1,
+			CheckID:   2,
+			CheckName: "cpu",
+			Tags:      map[string]string{"host": "b"},
+			Level:     "ok",
+			Message:   "cpu is ok",
+			Time:      start.Add(time.Hour),
+		},
+	}
+	if diff := cmp.Diff(want, tracked); diff != "" {
+		t.Errorf("unexpected tracked statuses (-want +got):\n%s", diff)
+	}
+}
+
+// tableResult is a flux.Result of tables.
+type tableResult struct {
+	name   string
+	tables tables
+}
+
+func (r *tableResult) Name() string               { return r.name }
+func (r *tableResult) Tables() flux.TableIterator { return r.tables }
@@ -0,0 +1,273 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// deliveryOptions override the option of the monitor package logging the notifications to the _monitoring bucket,
+// the deliverer records them once it attempted to deliver them.
+const deliveryOptions = `option %[1]s.log = (tables=<-) => tables
+`
+
+// deliveryEndpoint is an endpoint package which notifications are delivered by the notification deliverer.
+type deliveryEndpoint struct {
+	path string
+	// capture replaces the endpoint function of the package. It builds the request the endpoint would send
+	// into the _delivery columns of the notifications instead of sending it, and marks them unsent.
+	// It is formatted with the import names of the endpoint package and of the json package.
+	capture string
+}
+
+var deliveryEndpoints = []deliveryEndpoint{
+	{
+		path: "slack",
+		capture: `_deliver_slack = (url=%[1]s.defaultURL, token="") => (mapFn) => (tables=<-) => tables
+    |> map(fn: (r) => {
+        obj = mapFn(r: r)
+        return {r with
+            _sent: "false",
+            _delivery_url: url,
+            _delivery_headers: string(v: %[2]s.encode(v: {"Authorization": "Bearer " + token, "Content-Type": "application/json"})),
+            _delivery_body: string(v: %[2]s.encode(v: {
+                username: if exists obj.username then obj.username else "",
+                channel: if exists obj.channel then obj.channel else "",
+                workspace: if exists obj.workspace then obj.workspace else "",
+                attachments: [{color: %[1]s.validateColorString(color: obj.color), text: string(v: obj.text), mrkdwn_in: ["text"]}],
+                as_user: false,
+                icon_emoji: if exists obj.iconEmoji then obj.iconEmoji else "",
+            })),
+        }
+    })
+`,
+	},
+	{
+		path: "pagerduty",
+		capture: `_deliver_pagerduty = (url=%[1]s.defaultURL) => (mapFn) => (tables=<-) => tables
+    |> %[1]s.dedupKey()
+    |> map(fn: (r) => {
+        obj = mapFn(r: r)
+        return {r with
+            _sent: "false",
+            _delivery_url: url,
+            _delivery_headers: string(v: %[2]s.encode(v: {"Accept": "application/vnd.pagerduty+json;version=2", "Content-Type": "application/json"})),
+            _delivery_body: string(v: %[2]s.encode(v: {
+                payload: {
+                    summary: obj.summary,
+                    timestamp: obj.timestamp,
+                    source: obj.source,
+                    severity: obj.severity,
+                    component: obj.component,
+                    group: obj.group,
+                    class: obj.class,
+                },
+                routing_key: obj.routingKey,
+                dedup_key: r._pagerdutyDedupKey,
+                event_action: obj.eventAction,
+                client: obj.client,
+                client_url: obj.clientURL,
+            })),
+        }
+    })
+`,
+	},
+	{
+		path: "http",
+		capture: `_deliver_http = (url) => (mapFn) => (tables=<-) => tables
+    |> map(fn: (r) => {
+        obj = mapFn(r: r)
+        return {r with
+            _sent: "false",
+            _delivery_url: url,
+            _delivery_headers: string(v: %[2]s.encode(v: obj.headers)),
+            _delivery_body: string(v: obj.data),
+        }
+    })
+`,
+	},
+}
+
+// columns of the requests of the notifications captured for the notification deliverer.
+const (
+	deliveryURLColumn     = "_delivery_url"
+	deliveryHeadersColumn = "_delivery_headers"
+	deliveryBodyColumn    = "_delivery_body"
+)
+
+// deliveryIgnoredColumns are the columns of notifications that are not recorded with them.
+var deliveryIgnoredColumns = map[string]bool{
+	"_measurement":        true,
+	"_start":              true,
+	"_stop":               true,
+	"_time":               true,
+	"_sent":               true,
+	"_pagerdutyDedupKey":  true,
+	deliveryURLColumn:     true,
+	deliveryHeadersColumn: true,
+	deliveryBodyColumn:    true,
+}
+
+// SetNotificationDeliverer sets the deliverer the notifications of the runs of notification rules are sent with.
+// Without a deliverer, the notifications are sent by the flux packages of their endpoints.
+func (e *Executor) SetNotificationDeliverer(d influxdb.NotificationDeliverer) {
+	e.deliverer = d
+}
+
+// captureDeliveries rewrites the notifications of pkg to Slack, PagerDuty and HTTP endpoints to be captured
+// for the notification deliverer rather than sent. It returns nil when pkg sends no such notification.
+func (e *Executor) captureDeliveries(orgID influxdb.ID, pkg *ast.Package) *notificationCapture {
+	if e.deliverer == nil || len(pkg.Files) == 0 {
+		return nil
+	}
+	f := pkg.Files[0]
+	monitor := importName(f, monitorPackagePath)
+	if monitor == "" || len(notifyPipes(f, monitor)) == 0 {
+		return nil
+	}
+
+	jsonName := importName(f, "json")
+	if jsonName == "" {
+		jsonName = "json"
+	}
+	src := fmt.Sprintf(deliveryOptions, monitor)
+	captured := 0
+	for _, ep := range deliveryEndpoints {
+		name := importName(f, ep.path)
+		if name == "" {
+			continue
+		}
+		replaced := false
+		ast.Visit(f, func(n ast.Node) {
+			if call, ok := n.(*ast.CallExpression); ok && isPackageCall(call, name, "endpoint") {
+				call.Callee = &ast.Identifier{Name: "_deliver_" + ep.path}
+				replaced = true
+			}
+		})
+		if replaced {
+			src += fmt.Sprintf(ep.capture, name, jsonName)
+			captured++
+		}
+	}
+	if captured == 0 {
+		return nil
+	}
+
+	if importName(f, "json") == "" {
+		f.Imports = append(f.Imports, &ast.ImportDeclaration{Path: &ast.StringLiteral{Value: "json"}})
+	}
+	f.Body = append(parser.ParseSource(src).Files[0].Body, f.Body...)
+	return &notificationCapture{orgID: orgID}
+}
+
+func isPackageCall(call *ast.CallExpression, pkg, name string) bool {
+	callee, ok := call.Callee.(*ast.MemberExpression)
+	if !ok {
+		return false
+	}
+	obj, ok := callee.Object.(*ast.Identifier)
+	return ok && obj.Name == pkg && callee.Property.Key() == name
+}
+
+// deliverNotifications hands the notifications captured from a run to the notification deliverer.
+// Failing to deliver them doesn't fail the run, the deliverer retries the failed deliveries.
+func (e *Executor) deliverNotifications(ctx context.Context, task *influxdb.Task, c *notificationCapture) {
+	if c == nil || len(c.notifications) == 0 {
+		return
+	}
+	if err := e.deliverer.DeliverNotifications(ctx, c.notifications); err != nil {
+		e.log.Error("Failed to deliver the notifications of a notification rule", zap.String("taskID", task.ID.String()), zap.Error(err))
+	}
+}
+
+// notificationCapture collects the notifications of a run with the requests delivering them.
+type notificationCapture struct {
+	orgID         influxdb.ID
+	notifications []influxdb.RuleNotification
+}
+
+// matches returns whether tbl holds notifications with their requests.
+func (c *notificationCapture) matches(tbl flux.Table) bool {
+	return colIdx(tbl.Cols(), deliveryURLColumn) >= 0
+}
+
+// captureTable drains tbl, keeping its notifications.
+func (c *notificationCapture) captureTable(tbl flux.Table) error {
+	tags := make(map[string]string)
+	isTag := make(map[string]bool)
+	for j, col := range tbl.Key().Cols() {
+		isTag[col.Label] = true
+		if col.Type == flux.TString && !deliveryIgnoredColumns[col.Label] {
+			tags[col.Label] = tbl.Key().ValueString(j)
+		}
+	}
+
+	cols := tbl.Cols()
+	urlIdx := colIdx(cols, deliveryURLColumn)
+	headersIdx := colIdx(cols, deliveryHeadersColumn)
+	bodyIdx := colIdx(cols, deliveryBodyColumn)
+	ruleIdx := colIdx(cols, "_notification_rule_id")
+	endpointIdx := colIdx(cols, "_notification_endpoint_id")
+	measurementIdx := colIdx(cols, "_measurement")
+	timeIdx := colIdx(cols, "_time")
+	return tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			n := influxdb.RuleNotification{
+				OrgID:  c.orgID,
+				Tags:   tags,
+				Fields: make(map[string]interface{}),
+			}
+			if s, ok := value(cr, urlIdx, i).(string); ok {
+				n.Request.URL = s
+			}
+			if s, ok := value(cr, headersIdx, i).(string); ok && s != "" {
+				if err := json.Unmarshal([]byte(s), &n.Request.Headers); err != nil {
+					return err
+				}
+			}
+			if s, ok := value(cr, bodyIdx, i).(string); ok {
+				n.Request.Body = s
+			}
+			if s, ok := value(cr, ruleIdx, i).(string); ok {
+				if id, err := influxdb.IDFromString(s); err == nil {
+					n.RuleID = *id
+				}
+			}
+			if s, ok := value(cr, endpointIdx, i).(string); ok {
+				if id, err := influxdb.IDFromString(s); err == nil {
+					n.EndpointID = *id
+				}
+			}
+			if s, ok := value(cr, measurementIdx, i).(string); ok {
+				n.Measurement = s
+			}
+			switch v := value(cr, timeIdx, i).(type) {
+			case int64:
+				n.Time = time.Unix(0, v).UTC()
+			case time.Time:
+				n.Time = v
+			}
+			for j, col := range cols {
+				if isTag[col.Label] || deliveryIgnoredColumns[col.Label] {
+					continue
+				}
+				switch v := value(cr, j, i).(type) {
+				case nil:
+				case time.Time:
+					n.Fields[col.Label] = v.UnixNano()
+				default:
+					n.Fields[col.Label] = v
+				}
+			}
+			c.notifications = append(c.notifications, n)
+		}
+		return nil
+	})
+}
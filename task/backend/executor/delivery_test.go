@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/influxdb"
+	_ "github.com/influxdata/influxdb/query/stdlib"
+)
+
+// notificationDelivererFunc delivers the notifications by calling the func.
+type notificationDelivererFunc func(ctx context.Context, ns []influxdb.RuleNotification) error
+
+func (f notificationDelivererFunc) DeliverNotifications(ctx context.Context, ns []influxdb.RuleNotification) error {
+	return f(ctx, ns)
+}
+
+func TestCaptureDeliveries(t *testing.T) {
+	const script = `import "csv"
+import "slack"
+import "influxdata/influxdb/monitor"
+
+data = "#datatype,string,long,dateTime:RFC3339,string,string,string,string
+#group,false,false,false,true,true,false,true
+#default,_result,,,,,,
+,result,table,_time,_check_id,_level,_message,host
+,,0,2019-12-01T09:59:00Z,0000000000000001,crit,cpu is crit,a
+"
+slack_endpoint = slack.endpoint(url: "http://slack.test/hook", token: "tok")
+notification = {
+	_notification_rule_id: "0000000000000002",
+	_notification_rule_name: "rule",
+	_notification_endpoint_id: "0000000000000003",
+	_notification_endpoint_name: "slack",
+}
+
+csv.from(csv: data)
+	|> monitor.notify(data: notification, endpoint: slack_endpoint(mapFn: (r) => ({channel: "#ops", text: "${r.host}: ${r._message}", color: "danger"})))`
+
+	pkg, err := flux.Parse(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := (&Executor{}).captureDeliveries(1, pkg); c != nil {
+		t.Fatal("expected no capture without a notification deliverer")
+	}
+
+	e := &Executor{deliverer: notificationDelivererFunc(func(ctx context.Context, ns []influxdb.RuleNotification) error {
+		return nil
+	})}
+	c := e.captureDeliveries(1, pkg)
+	if c == nil {
+		t.Fatal("expected the notifications to slack to be captured")
+	}
+	got := ast.Format(pkg.Files[0])
+	for _, s := range []string{
+		`import "json"`,
+		"option monitor.log = (tables=<-) =>",
+		`slack_endpoint = _deliver_slack(url: "http://slack.test/hook", token: "tok")`,
+	} {
+		if !strings.Contains(got, s) {
+			t.Errorf("expected the script to contain %q, got:\n%s", s, got)
+		}
+	}
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	now := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	prog, err := lang.ASTCompiler{AST: pkg, Now: now}.Compile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := prog.Start(ctx, &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for res := range q.Results() {
+		if err := res.Tables().Do(c.captureTable); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.notifications) != 1 {
+		t.Fatalf("expected a notification, got %+v", c.notifications)
+	}
+	n := c.notifications[0]
+	if n.Time.IsZero() {
+		t.Error("expected the notification to have the time it was sent at")
+	}
+	n.Time = time.Time{}
+	want := influxdb.RuleNotification{
+		OrgID:      1,
+		RuleID:     2,
+		EndpointID: 3,
+		Request: influxdb.NotificationRequest{
+			URL:     "http://slack.test/hook",
+			Headers: map[string]string{"Authorization": "Bearer tok", "Content-Type": "application/json"},
+			Body:    `{"as_user":false,"attachments":[{"color":"danger","mrkdwn_in":["text"],"text":"a: cpu is crit"}],"channel":"#ops","icon_emoji":"","username":"","workspace":""}`,
+		},
+		Measurement: "notifications",
+		Tags: map[string]string{
+			"_check_id":                   "0000000000000001",
+			"_level":                      "crit",
+			"_notification_rule_id":       "0000000000000002",
+			"_notification_rule_name":     "rule",
+			"_notification_endpoint_id":   "0000000000000003",
+			"_notification_endpoint_name": "slack",
+			"host":                        "a",
+		},
+		Fields: map[string]interface{}{
+			"_message":          "cpu is crit",
+			"_status_timestamp": time.Date(2019, 12, 1, 9, 59, 0, 0, time.UTC).UnixNano(),
+		},
+	}
+	if !cmp.Equal(want, n) {
+		t.Errorf("unexpected notification -want/+got:\n%s", cmp.Diff(want, n))
+	}
+}
@@ -0,0 +1,274 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+var _ influxdb.TaskDryRunService = (*Executor)(nil)
+
+// dryRunSinkPrefix prefixes the names of the results capturing the sinks of a dry run.
+const dryRunSinkPrefix = "_dryrun_sink_"
+
+// DryRunTask executes a task script once with the authorization on ctx.
+// The to() and experimental.to() sinks of the script are replaced by yields,
+// so the tables they would have written are returned instead.
+// Errors of the query are reported in the dry run rather than returned.
+func (e *Executor) DryRunTask(ctx context.Context, req influxdb.TaskDryRunRequest) (*influxdb.TaskDryRun, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if !req.OrganizationID.Valid() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "missing orgID",
+		}
+	}
+
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := auth.(*influxdb.Authorization)
+	if !ok {
+		return nil, influxdb.ErrAuthorizerNotSupported
+	}
+
+	opt, err := options.FromScript(req.Flux)
+	if err != nil {
+		return nil, influxdb.ErrTaskOptionParse(err)
+	}
+
+	pkg, err := flux.Parse(req.Flux)
+	if err != nil {
+		return nil, influxdb.ErrFluxParseError(err)
+	}
+	sinks := captureSinks(pkg)
+
+	sf := req.ScheduledFor.UTC()
+	if sf.IsZero() {
+		sf = time.Now().UTC().Truncate(time.Second)
+	}
+	dr := &influxdb.TaskDryRun{
+		ScheduledFor: sf,
+		Tables:       []influxdb.DryRunTable{},
+		Log:          []influxdb.Log{},
+	}
+	dr.Log = append(dr.Log, dryRunLog(fmt.Sprintf("Started dry run of task %q scheduled for %s", opt.Name, sf.Format(time.RFC3339))))
+	if len(sinks) == 0 {
+		dr.Log = append(dr.Log, dryRunLog("The script has no to() sinks, it writes no data"))
+	}
+
+	// the dry run holds a worker like a run, it waits for one to be available.
+	select {
+	case e.workerLimit <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-e.workerLimit }()
+
+	if opt.Timeout != nil {
+		timeout, err := opt.Timeout.DurationFrom(sf)
+		if err != nil {
+			return nil, influxdb.ErrTaskTimeParse(err)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	qr := &query.Request{
+		Authorization:  a,
+		OrganizationID: req.OrganizationID,
+		Compiler: lang.ASTCompiler{
+			AST: pkg,
+			Now: sf,
+		},
+	}
+	ctx = query.ContextWithKind(ctx, query.KindTask)
+	it, err := e.qs.Query(ctx, qr)
+	if err != nil {
+		dr.Error = influxdb.ErrQueryError(err).Error()
+		return dr, nil
+	}
+
+	var runErr error
+	for it.More() {
+		res := it.Next()
+		sink, ok := sinks[res.Name()]
+		if !ok {
+			// Consume the results that are not written so that we don't leak outstanding iterators.
+			if err := exhaustResultIterators(res); err != nil && runErr == nil {
+				runErr = err
+			}
+			continue
+		}
+		if err := captureResult(dr, sink, res); err != nil && runErr == nil {
+			runErr = err
+		}
+	}
+	it.Release()
+
+	switch {
+	case runErr != nil:
+		dr.Error = influxdb.ErrRunExecutionError(runErr).Error()
+	case it.Err() != nil:
+		dr.Error = influxdb.ErrResultIteratorError(it.Err()).Error()
+	}
+
+	var records int
+	for _, t := range dr.Tables {
+		records += len(t.Records)
+	}
+	dr.Log = append(dr.Log, dryRunLog(fmt.Sprintf("Captured %d tables with %d records", len(dr.Tables), records)))
+	if dr.Truncated {
+		dr.Log = append(dr.Log, dryRunLog(fmt.Sprintf("Records beyond the first %d were dropped", influxdb.MaxDryRunRecords)))
+	}
+	return dr, nil
+}
+
+func dryRunLog(msg string) influxdb.Log {
+	return influxdb.Log{Time: time.Now().UTC().Format(time.RFC3339Nano), Message: msg}
+}
+
+// captureSinks replaces the calls of the to() and experimental.to() sinks of pkg with yields.
+// It returns the formatted sink calls by the name of the result capturing them.
+func captureSinks(pkg *ast.Package) map[string]string {
+	sinks := make(map[string]string)
+	for _, f := range pkg.Files {
+		experimental := importName(f, "experimental")
+		ast.Visit(f, func(n ast.Node) {
+			call, ok := n.(*ast.CallExpression)
+			if !ok || !isSinkCall(call, experimental) {
+				return
+			}
+
+			name := fmt.Sprintf("%s%d", dryRunSinkPrefix, len(sinks))
+			sinks[name] = ast.Format(call)
+
+			args := &ast.ObjectExpression{
+				Properties: []*ast.Property{{
+					Key:   &ast.Identifier{Name: "name"},
+					Value: &ast.StringLiteral{Value: name},
+				}},
+			}
+			// a sink that is not piped into keeps its tables.
+			if len(call.Arguments) == 1 {
+				if obj, ok := call.Arguments[0].(*ast.ObjectExpression); ok {
+					for _, p := range obj.Properties {
+						if p.Key.Key() == "tables" {
+							args.Properties = append(args.Properties, p)
+						}
+					}
+				}
+			}
+			call.Callee = &ast.Identifier{Name: "yield"}
+			call.Arguments = []ast.Expression{args}
+		})
+	}
+	return sinks
+}
+
+// importName returns the name the package at pkgPath is imported as by f, or "" if f doesn't import it.
+func importName(f *ast.File, pkgPath string) string {
+	for _, imp := range f.Imports {
+		if imp.Path == nil || imp.Path.Value != pkgPath {
+			continue
+		}
+		if imp.As != nil {
+			return imp.As.Name
+		}
+		return path.Base(pkgPath)
+	}
+	return ""
+}
+
+func isSinkCall(call *ast.CallExpression, experimental string) bool {
+	switch callee := call.Callee.(type) {
+	case *ast.Identifier:
+		return callee.Name == "to"
+	case *ast.MemberExpression:
+		obj, ok := callee.Object.(*ast.Identifier)
+		return ok && experimental != "" && obj.Name == experimental && callee.Property.Key() == "to"
+	}
+	return false
+}
+
+// captureResult appends the tables of res to the dry run, up to MaxDryRunRecords records in total.
+func captureResult(dr *influxdb.TaskDryRun, sink string, res flux.Result) error {
+	var records int
+	for _, t := range dr.Tables {
+		records += len(t.Records)
+	}
+
+	return res.Tables().Do(func(tbl flux.Table) error {
+		t := influxdb.DryRunTable{
+			Sink:    sink,
+			Columns: make([]influxdb.DryRunColumn, 0, len(tbl.Cols())),
+			Records: [][]interface{}{},
+		}
+		for _, c := range tbl.Cols() {
+			t.Columns = append(t.Columns, influxdb.DryRunColumn{Label: c.Label, Type: c.Type.String()})
+		}
+
+		err := tbl.Do(func(cr flux.ColReader) error {
+			for i := 0; i < cr.Len(); i++ {
+				if records >= influxdb.MaxDryRunRecords {
+					dr.Truncated = true
+					return nil
+				}
+				record := make([]interface{}, len(cr.Cols()))
+				for j := range cr.Cols() {
+					record[j] = columnValue(cr, j, i)
+				}
+				t.Records = append(t.Records, record)
+				records++
+			}
+			return nil
+		})
+		dr.Tables = append(dr.Tables, t)
+		return err
+	})
+}
+
+// columnValue returns the value of column j of row i of cr, nil when it is null.
+func columnValue(cr flux.ColReader, j, i int) interface{} {
+	switch cr.Cols()[j].Type {
+	case flux.TBool:
+		if vs := cr.Bools(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TInt:
+		if vs := cr.Ints(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TUInt:
+		if vs := cr.UInts(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TFloat:
+		if vs := cr.Floats(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TString:
+		if vs := cr.Strings(j); vs.IsValid(i) {
+			return vs.ValueString(i)
+		}
+	case flux.TTime:
+		if vs := cr.Times(j); vs.IsValid(i) {
+			return time.Unix(0, vs.Value(i)).UTC()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap/zaptest"
+)
+
+// dryRunQueryService returns its results for any query, recording the last request.
+type dryRunQueryService struct {
+	query.QueryService
+	req     *query.Request
+	results []flux.Result
+	err     error
+}
+
+func (s *dryRunQueryService) Query(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+	s.req = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return flux.NewSliceResultIterator(s.results), nil
+}
+
+func TestCaptureSinks(t *testing.T) {
+	pkg, err := flux.Parse(`import ex "experimental"
+
+option task = {name: "a", every: 1h}
+
+from(bucket: "b")
+	|> range(start: -1h)
+	|> to(bucket: "c", org: "o")
+from(bucket: "b")
+	|> range(start: -1h)
+	|> ex.to(bucket: "d", org: "o")
+from(bucket: "b")
+	|> range(start: -1h)
+	|> yield(name: "kept")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sinks := captureSinks(pkg)
+	want := map[string]string{
+		"_dryrun_sink_0": `to(bucket: "c", org: "o")`,
+		"_dryrun_sink_1": `ex.to(bucket: "d", org: "o")`,
+	}
+	if !cmp.Equal(want, sinks) {
+		t.Errorf("unexpected sinks -want/+got:\n%s", cmp.Diff(want, sinks))
+	}
+
+	script := ast.Format(pkg)
+	for _, s := range []string{`yield(name: "_dryrun_sink_0")`, `yield(name: "_dryrun_sink_1")`, `yield(name: "kept")`} {
+		if !strings.Contains(script, s) {
+			t.Errorf("expected %s in the rewritten script:\n%s", s, script)
+		}
+	}
+	if strings.Contains(script, "to(") {
+		t.Errorf("expected no sinks left in the rewritten script:\n%s", script)
+	}
+}
+
+func TestDryRunTask(t *testing.T) {
+	var (
+		qs    = &dryRunQueryService{}
+		i     = kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+		ex, _ = NewExecutor(zaptest.NewLogger(t), qs, i, i, &taskControlService{TaskControlService: i})
+		tc    = createCreds(t, i)
+		ctx   = icontext.SetAuthorizer(context.Background(), tc.Auth)
+		sf    = time.Unix(123, 0).UTC()
+		req   = influxdb.TaskDryRunRequest{
+			Flux:           `option task = {name: "a", every: 1h} from(bucket: "b") |> range(start: -1h) |> to(bucket: "c", org: "o")`,
+			OrganizationID: tc.OrgID,
+			ScheduledFor:   sf,
+		}
+	)
+
+	sink := newFakeResult()
+	sink.name = "_dryrun_sink_0"
+	qs.results = []flux.Result{sink, newFakeResult()}
+
+	dr, err := ex.DryRunTask(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c := qs.req.Compiler.(lang.ASTCompiler); !c.Now.Equal(sf) {
+		t.Errorf("expected the query to be scheduled for %v, got %v", sf, c.Now)
+	}
+	if dr.Error != "" {
+		t.Fatalf("unexpected dry run error: %s", dr.Error)
+	}
+	want := []influxdb.DryRunTable{{
+		Sink:    `to(bucket: "c", org: "o")`,
+		Columns: []influxdb.DryRunColumn{{Label: "x", Type: "int"}},
+		Records: [][]interface{}{{int64(1)}},
+	}}
+	if !cmp.Equal(want, dr.Tables) {
+		t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, dr.Tables))
+	}
+	if len(dr.Log) != 2 {
+		t.Errorf("expected 2 log entries, got %d", len(dr.Log))
+	}
+
+	qs.err = errors.New("something went wrong")
+	dr, err = ex.DryRunTask(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dr.Error, "something went wrong") {
+		t.Errorf("expected the query error in the dry run, got %q", dr.Error)
+	}
+
+	if _, err := ex.DryRunTask(ctx, influxdb.TaskDryRunRequest{Flux: "from(bucket: \"b\")", OrganizationID: tc.OrgID}); err == nil {
+		t.Error("expected an error for a script without task options")
+	}
+}
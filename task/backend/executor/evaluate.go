@@ -0,0 +1,323 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+var _ influxdb.MonitorEvaluationService = (*Executor)(nil)
+
+const monitorPackagePath = "influxdata/influxdb/monitor"
+
+// evaluationOptions override the options of the monitor package writing statuses
+// and notifications to the _monitoring bucket, so they are returned instead.
+const evaluationOptions = `option %[1]s.write = (tables=<-) => tables
+option %[1]s.log = (tables=<-) => tables
+`
+
+// evaluationEndpoint replaces the endpoints notifications are sent to, marking them unsent.
+const evaluationEndpoint = `(tables=<-) => tables |> map(fn: (r) => ({r with _sent: "false"}))`
+
+// evaluationIgnoredTags are the group key columns of statuses and notifications that don't identify their series.
+var evaluationIgnoredTags = map[string]bool{
+	"_measurement":                true,
+	"_level":                      true,
+	"_sent":                       true,
+	"_type":                       true,
+	"_check_id":                   true,
+	"_notification_rule_id":       true,
+	"_notification_rule_name":     true,
+	"_notification_endpoint_id":   true,
+	"_notification_endpoint_name": true,
+}
+
+// EvaluateMonitor executes the script of a check or of a notification rule for each time
+// its task would have been scheduled for in the range of the request, with the authorization on ctx.
+// Nothing is written to the _monitoring bucket and no notification is sent, the statuses
+// and the notifications are returned as the timelines of their series instead.
+// Errors of the queries are reported in the evaluation rather than returned.
+func (e *Executor) EvaluateMonitor(ctx context.Context, req influxdb.MonitorEvaluationRequest) (*influxdb.MonitorEvaluation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := req.Validate(); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := auth.(*influxdb.Authorization)
+	if !ok {
+		return nil, influxdb.ErrAuthorizerNotSupported
+	}
+
+	opt, err := options.FromScript(req.Flux)
+	if err != nil {
+		return nil, influxdb.ErrTaskOptionParse(err)
+	}
+	if opt.Every.IsZero() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "only scripts scheduled with every can be evaluated",
+		}
+	}
+	every, err := opt.Every.DurationFrom(req.Start)
+	if err != nil {
+		return nil, influxdb.ErrTaskTimeParse(err)
+	}
+	// the first time the task would have been scheduled for at or after start.
+	first := req.Start.UTC().Truncate(every)
+	if first.Before(req.Start) {
+		first = first.Add(every)
+	}
+	if n := req.Stop.Sub(first)/every + 1; n > influxdb.MaxMonitorEvaluations {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("the range is evaluated %d times every %s, at most %d evaluations are allowed", n, opt.Every.String(), influxdb.MaxMonitorEvaluations),
+		}
+	}
+
+	// the package is parsed once to report the errors of the script,
+	// and for each evaluation as the compiler may modify it.
+	if _, err := evaluationPackage(req.Flux); err != nil {
+		return nil, err
+	}
+
+	ev := &influxdb.MonitorEvaluation{
+		Start:  req.Start.UTC(),
+		Stop:   req.Stop.UTC(),
+		Every:  opt.Every.String(),
+		Series: []influxdb.MonitorSeries{},
+	}
+
+	// the evaluation holds a worker like a run, it waits for one to be available.
+	select {
+	case e.workerLimit <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-e.workerLimit }()
+
+	c := &evaluationCapture{series: make(map[string]int)}
+	ctx = query.ContextWithKind(ctx, query.KindCheck)
+	for sf := first; !sf.After(req.Stop); sf = sf.Add(every) {
+		if err := e.evaluateOnce(ctx, req, a, opt, sf, c); err != nil {
+			ev.Error = fmt.Sprintf("evaluation scheduled for %s: %s", sf.Format(time.RFC3339), err.Error())
+			break
+		}
+		ev.Evaluations++
+	}
+
+	sort.Strings(c.keys)
+	for _, k := range c.keys {
+		ev.Series = append(ev.Series, c.all[c.series[k]])
+	}
+	ev.Truncated = c.truncated
+	return ev, nil
+}
+
+func (e *Executor) evaluateOnce(ctx context.Context, req influxdb.MonitorEvaluationRequest, a *influxdb.Authorization, opt options.Options, sf time.Time, c *evaluationCapture) error {
+	if opt.Timeout != nil {
+		timeout, err := opt.Timeout.DurationFrom(sf)
+		if err != nil {
+			return influxdb.ErrTaskTimeParse(err)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	pkg, err := evaluationPackage(req.Flux)
+	if err != nil {
+		return err
+	}
+	if err := e.silenceNotifications(ctx, req.OrganizationID, pkg, sf); err != nil {
+		return err
+	}
+	qr := &query.Request{
+		Authorization:  a,
+		OrganizationID: req.OrganizationID,
+		Compiler: lang.ASTCompiler{
+			AST: pkg,
+			Now: sf,
+		},
+	}
+	it, err := e.qs.Query(ctx, qr)
+	if err != nil {
+		return influxdb.ErrQueryError(err)
+	}
+
+	var runErr error
+	for it.More() {
+		if err := c.capture(sf, it.Next()); err != nil && runErr == nil {
+			runErr = err
+		}
+	}
+	it.Release()
+
+	if runErr != nil {
+		return influxdb.ErrRunExecutionError(runErr)
+	}
+	if err := it.Err(); err != nil {
+		return influxdb.ErrResultIteratorError(err)
+	}
+	return nil
+}
+
+// evaluationPackage parses script, overriding the options of the monitor package writing to the
+// _monitoring bucket and replacing the endpoints of the notifications with evaluationEndpoint.
+func evaluationPackage(script string) (*ast.Package, error) {
+	pkg, err := flux.Parse(script)
+	if err != nil {
+		return nil, influxdb.ErrFluxParseError(err)
+	}
+
+	f := pkg.Files[0]
+	monitor := importName(f, monitorPackagePath)
+	if monitor == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("the script doesn't import %q, it is not the script of a check or of a notification rule", monitorPackagePath),
+		}
+	}
+
+	opts := parser.ParseSource(fmt.Sprintf(evaluationOptions, monitor))
+	endpoint := parser.ParseSource(evaluationEndpoint).Files[0].Body[0].(*ast.ExpressionStatement).Expression
+	ast.Visit(f, func(n ast.Node) {
+		call, ok := n.(*ast.CallExpression)
+		if !ok || !isMonitorCall(call, monitor, "notify") || len(call.Arguments) != 1 {
+			return
+		}
+		if obj, ok := call.Arguments[0].(*ast.ObjectExpression); ok {
+			for _, p := range obj.Properties {
+				if p.Key.Key() == "endpoint" {
+					p.Value = endpoint
+				}
+			}
+		}
+	})
+	f.Body = append(opts.Files[0].Body, f.Body...)
+	return pkg, nil
+}
+
+func isMonitorCall(call *ast.CallExpression, monitor, name string) bool {
+	callee, ok := call.Callee.(*ast.MemberExpression)
+	if !ok {
+		return false
+	}
+	obj, ok := callee.Object.(*ast.Identifier)
+	return ok && obj.Name == monitor && callee.Property.Key() == name
+}
+
+// evaluationCapture collects the statuses and the notifications of the evaluations by series.
+type evaluationCapture struct {
+	all       []influxdb.MonitorSeries
+	series    map[string]int // series indexes the series of all by key
+	keys      []string
+	points    int
+	truncated bool
+}
+
+// capture appends the statuses or notifications of res to their series.
+func (c *evaluationCapture) capture(sf time.Time, res flux.Result) error {
+	return res.Tables().Do(func(tbl flux.Table) error {
+		tags := make(map[string]string)
+		for j, col := range tbl.Key().Cols() {
+			if col.Type == flux.TString && !evaluationIgnoredTags[col.Label] {
+				tags[col.Label] = tbl.Key().ValueString(j)
+			}
+		}
+		key := seriesKey(tags)
+		i, ok := c.series[key]
+		if !ok {
+			i = len(c.all)
+			c.series[key] = i
+			c.keys = append(c.keys, key)
+			c.all = append(c.all, influxdb.MonitorSeries{Tags: tags, Points: []influxdb.MonitorPoint{}})
+		}
+
+		cols := tbl.Cols()
+		timeIdx := evaluationTimeColumn(cols)
+		levelIdx := colIdx(cols, "_level")
+		messageIdx := colIdx(cols, "_message")
+		return tbl.Do(func(cr flux.ColReader) error {
+			for r := 0; r < cr.Len(); r++ {
+				if c.points >= influxdb.MaxMonitorEvaluationPoints {
+					c.truncated = true
+					return nil
+				}
+				p := influxdb.MonitorPoint{ScheduledFor: sf}
+				switch v := value(cr, timeIdx, r).(type) {
+				case int64:
+					p.Time = time.Unix(0, v).UTC()
+				case time.Time:
+					p.Time = v
+				}
+				if s, ok := value(cr, levelIdx, r).(string); ok {
+					p.Level = s
+				}
+				if s, ok := value(cr, messageIdx, r).(string); ok {
+					p.Message = s
+				}
+				c.all[i].Points = append(c.all[i].Points, p)
+				c.points++
+			}
+			return nil
+		})
+	})
+}
+
+// evaluationTimeColumn returns the index of the column of the time of the status,
+// which is the time of the source of a status, or of the status of a notification.
+func evaluationTimeColumn(cols []flux.ColMeta) int {
+	for _, label := range []string{"_source_timestamp", "_status_timestamp", "_time"} {
+		if j := colIdx(cols, label); j >= 0 {
+			return j
+		}
+	}
+	return -1
+}
+
+// value returns the value of column j of row i of cr, nil when the column doesn't exist.
+func value(cr flux.ColReader, j, i int) interface{} {
+	if j < 0 {
+		return nil
+	}
+	return columnValue(cr, j, i)
+}
+
+func colIdx(cols []flux.ColMeta, label string) int {
+	for j, c := range cols {
+		if c.Label == label {
+			return j
+		}
+	}
+	return -1
+}
+
+func seriesKey(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
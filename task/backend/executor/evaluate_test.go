@@ -0,0 +1,202 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap/zaptest"
+)
+
+// evaluationQueryService returns a status of host a for every query, and a status
+// of host b for the queries after the first one, recording the times and kinds of the queries.
+type evaluationQueryService struct {
+	query.QueryService
+	nows  []time.Time
+	kinds []query.Kind
+	err   error
+}
+
+func (s *evaluationQueryService) Query(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+	now := req.Compiler.(lang.ASTCompiler).Now
+	s.nows = append(s.nows, now)
+	s.kinds = append(s.kinds, query.KindFromContext(ctx))
+	if s.err != nil {
+		return nil, s.err
+	}
+	tbls := tables{newStatusTable("a", "crit", now)}
+	if len(s.nows) > 1 {
+		tbls = append(tbls, newStatusTable("b", "ok", now))
+	}
+	return flux.NewSliceResultIterator([]flux.Result{&fakeResult{name: "_result", table: tbls[0]}, &tablesResult{tbls[1:]}}), nil
+}
+
+type tablesResult struct {
+	tables tables
+}
+
+func (r *tablesResult) Name() string                { return "_result" }
+func (r *tablesResult) Tables() flux.TableIterator  { return r.tables }
+func (r *tablesResult) Statistics() flux.Statistics { return flux.Statistics{} }
+
+// newStatusTable returns a table of a status of host of the check, the source of the status is a minute before now.
+func newStatusTable(host, level string, now time.Time) flux.Table {
+	key := []flux.ColMeta{
+		{Label: "_check_id", Type: flux.TString},
+		{Label: "_level", Type: flux.TString},
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "host", Type: flux.TString},
+	}
+	gk := execute.NewGroupKey(key, []values.Value{
+		values.NewString("000000000000000a"),
+		values.NewString(level),
+		values.NewString("statuses"),
+		values.NewString(host),
+	})
+	b := execute.NewColListTableBuilder(gk, &memory.Allocator{})
+	if err := execute.AddTableKeyCols(gk, b); err != nil {
+		panic(err)
+	}
+	ts, _ := b.AddCol(flux.ColMeta{Label: "_source_timestamp", Type: flux.TInt})
+	msg, _ := b.AddCol(flux.ColMeta{Label: "_message", Type: flux.TString})
+	if err := execute.AppendKeyValues(gk, b); err != nil {
+		panic(err)
+	}
+	_ = b.AppendInt(ts, now.Add(-time.Minute).UnixNano())
+	_ = b.AppendString(msg, host+" is "+level)
+	t, err := b.Table()
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestEvaluationPackage(t *testing.T) {
+	pkg, err := evaluationPackage(`import m "influxdata/influxdb/monitor"
+
+option task = {name: "a", every: 1h}
+
+m.from(start: -2h)
+	|> m.notify(data: {}, endpoint: e(mapFn: (r) => ({text: r._message})))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `import m "influxdata/influxdb/monitor"
+
+option m.write = (tables=<-) =>
+	(tables)
+option m.log = (tables=<-) =>
+	(tables)
+option task = {name: "a", every: 1h}
+
+m.from(start: -2h)
+	|> m.notify(data: {}, endpoint: (tables=<-) =>
+		(tables
+			|> map(fn: (r) =>
+				({r with _sent: "false"}))))`
+	if got := ast.Format(pkg.Files[0]); got != want {
+		t.Errorf("unexpected script -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	pkg, err = evaluationPackage(`import "influxdata/influxdb/monitor"
+
+monitor.from(start: -2h)
+	|> monitor.notify(data: {}, endpoint: e)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ast.Format(pkg.Files[0]); !strings.Contains(got, `_sent: "false"`) {
+		t.Errorf("expected the endpoint of the notifications to be replaced, got:\n%s", got)
+	}
+
+	if _, err := evaluationPackage(`from(bucket: "b") |> range(start: -1h)`); err == nil {
+		t.Error("expected an error for a script that doesn't import the monitor package")
+	}
+}
+
+func TestEvaluateMonitor(t *testing.T) {
+	var (
+		qs    = &evaluationQueryService{}
+		i     = kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+		ex, _ = NewExecutor(zaptest.NewLogger(t), qs, i, i, &taskControlService{TaskControlService: i})
+		tc    = createCreds(t, i)
+		ctx   = icontext.SetAuthorizer(context.Background(), tc.Auth)
+		start = time.Date(2019, 12, 1, 10, 30, 0, 0, time.UTC)
+		req   = influxdb.MonitorEvaluationRequest{
+			Flux:           `import "influxdata/influxdb/monitor" option task = {name: "a", every: 1h} from(bucket: "b") |> range(start: -1h) |> monitor.check(data: {_check_id: "000000000000000a", _check_name: "a", _type: "custom", tags: {}}, messageFn: (r) => "")`,
+			OrganizationID: tc.OrgID,
+			ResourceType:   influxdb.ChecksResourceType,
+			Start:          start,
+			Stop:           start.Add(2 * time.Hour),
+		}
+	)
+
+	ev, err := ex.EvaluateMonitor(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Error != "" {
+		t.Fatalf("unexpected evaluation error: %s", ev.Error)
+	}
+
+	first, second := start.Add(30*time.Minute), start.Add(90*time.Minute)
+	if want := []time.Time{first, second}; !cmp.Equal(want, qs.nows) {
+		t.Errorf("unexpected scheduled times -want/+got:\n%s", cmp.Diff(want, qs.nows))
+	}
+	if want := []query.Kind{query.KindCheck, query.KindCheck}; !cmp.Equal(want, qs.kinds) {
+		t.Errorf("unexpected query kinds -want/+got:\n%s", cmp.Diff(want, qs.kinds))
+	}
+	want := &influxdb.MonitorEvaluation{
+		Start:       req.Start,
+		Stop:        req.Stop,
+		Every:       "1h",
+		Evaluations: 2,
+		Series: []influxdb.MonitorSeries{
+			{
+				Tags: map[string]string{"host": "a"},
+				Points: []influxdb.MonitorPoint{
+					{ScheduledFor: first, Time: first.Add(-time.Minute), Level: "crit", Message: "a is crit"},
+					{ScheduledFor: second, Time: second.Add(-time.Minute), Level: "crit", Message: "a is crit"},
+				},
+			},
+			{
+				Tags: map[string]string{"host": "b"},
+				Points: []influxdb.MonitorPoint{
+					{ScheduledFor: second, Time: second.Add(-time.Minute), Level: "ok", Message: "b is ok"},
+				},
+			},
+		},
+	}
+	if !cmp.Equal(want, ev) {
+		t.Errorf("unexpected evaluation -want/+got:\n%s", cmp.Diff(want, ev))
+	}
+
+	qs.err = errors.New("something went wrong")
+	ev, err = ex.EvaluateMonitor(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ev.Error, "something went wrong") || ev.Evaluations != 0 {
+		t.Errorf("expected the query error in the evaluation, got %q after %d evaluations", ev.Error, ev.Evaluations)
+	}
+
+	req.Stop = start.Add(time.Duration(influxdb.MaxMonitorEvaluations+1) * time.Hour)
+	if _, err := ex.EvaluateMonitor(ctx, req); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error for a range with too many evaluations, got %v", err)
+	}
+}
@@ -19,6 +19,13 @@ import (
 
 var _ scheduler.Executor = (*Executor)(nil)
 
+const (
+	// defaultRetryDelay is the delay before the first retry of a failed run, when its task sets no delay.
+	defaultRetryDelay = 10 * time.Second
+	// defaultRetryMaxDelay is the maximum delay between retries of a failed run, when its task sets no maximum.
+	defaultRetryMaxDelay = 10 * time.Minute
+)
+
 type Promise interface {
 	ID() influxdb.ID
 	Cancel(ctx context.Context)
@@ -26,6 +33,15 @@ type Promise interface {
 	Error() error
 }
 
+// RetriedPromise is the promise of a run that is retried when it fails.
+type RetriedPromise interface {
+	Promise
+
+	// Retry returns the promise of the run retrying the failed run, or nil if it is not retried.
+	// It is only known once the promise is done.
+	Retry() Promise
+}
+
 // MultiLimit allows us to create a single limit func that applies more then one limit.
 func MultiLimit(limits ...LimitFunc) LimitFunc {
 	return func(task *influxdb.Task, run *influxdb.Run) error {
@@ -43,7 +59,11 @@ type LimitFunc func(*influxdb.Task, *influxdb.Run) error
 
 // NewExecutor creates a new task executor
 func NewExecutor(log *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService, tcs backend.TaskControlService) (*Executor, *ExecutorMetrics) {
+	ctx, cancel := context.WithCancel(context.Background())
 	e := &Executor{
+		ctx:    ctx,
+		cancel: cancel,
+
 		log: log,
 		ts:  ts,
 		tcs: tcs,
@@ -75,6 +95,11 @@ type Executor struct {
 	qs query.QueryService
 	as influxdb.AuthorizationService
 
+	// ctx spans the lifetime of the executor. The retries of runs outlive the requests that started them,
+	// so they are run under it instead.
+	ctx    context.Context
+	cancel context.CancelFunc
+
 	metrics *ExecutorMetrics
 
 	// currentPromises are all the promises we are made that have not been fulfilled
@@ -85,6 +110,21 @@ type Executor struct {
 
 	limitFunc LimitFunc
 
+	// chain holds back the runs of tasks until the runs of their upstream tasks succeeded.
+	chain *scheduler.Chain
+
+	// notifier notifies the notification endpoints of tasks of their failed runs.
+	notifier influxdb.TaskRunNotifier
+
+	// silences suppress the notifications of notification rules.
+	silences influxdb.SilenceService
+
+	// alerts tracks the alerts of checks from the statuses of their runs.
+	alerts influxdb.AlertTracker
+
+	// deliverer delivers the notifications of the runs of notification rules.
+	deliverer influxdb.NotificationDeliverer
+
 	// keep a pool of execution workers.
 	workerPool  sync.Pool
 	workerLimit chan struct{}
@@ -95,6 +135,21 @@ func (e *Executor) SetLimitFunc(l LimitFunc) {
 	e.limitFunc = l
 }
 
+// SetChain sets the chain the runs of this task executor wait on and report their outcome to.
+func (e *Executor) SetChain(c *scheduler.Chain) {
+	e.chain = c
+}
+
+// Close cancels the retries of runs that are still pending.
+func (e *Executor) Close() {
+	e.cancel()
+}
+
+// SetNotifier sets the notifier that notifies the notification endpoints of tasks of the outcome of their runs.
+func (e *Executor) SetNotifier(n influxdb.TaskRunNotifier) {
+	e.notifier = n
+}
+
 // Execute is a executor to satisfy the needs of tasks
 func (e *Executor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
 	_, err := e.PromisedExecute(ctx, id, scheduledFor, runAt)
@@ -159,7 +214,52 @@ func (e *Executor) createRun(ctx context.Context, id influxdb.ID, scheduledFor t
 		return nil, err
 	}
 
-	return e.createPromise(ctx, r)
+	if e.chain == nil || !e.chain.HasUpstreams(scheduler.ID(id)) {
+		return e.createPromise(ctx, r)
+	}
+
+	p, err := e.newPromise(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	go e.waitUpstreams(p)
+	return p, nil
+}
+
+// waitUpstreams queues the run of p once the runs of its upstream tasks for the same scheduled time succeeded.
+// If one of them did not, the run is blocked and not executed.
+func (e *Executor) waitUpstreams(p *promise) {
+	err := e.chain.Wait(p.ctx, scheduler.ID(p.task.ID), p.run.ScheduledFor)
+	if err == nil {
+		e.promiseQueue <- p
+		e.startWorker()
+		return
+	}
+
+	rs, msg := backend.RunBlocked, fmt.Sprintf("Run blocked: %s", err.Error())
+	if p.ctx.Err() != nil {
+		rs, msg, err = backend.RunCanceled, "Run canceled", influxdb.ErrRunCanceled
+	} else {
+		err = influxdb.ErrRunBlocked(err)
+	}
+	e.skip(p, rs, msg, err)
+}
+
+// skip finishes the run of p without executing it.
+func (e *Executor) skip(p *promise, rs backend.RunStatus, msg string, err error) {
+	e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), msg)
+	e.tcs.UpdateRunState(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), rs)
+	if _, err := e.tcs.FinishRun(p.ctx, p.task.ID, p.run.ID); err != nil {
+		e.log.Error("Failed to finish run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
+	}
+	e.metrics.FinishRun(p.task, rs, 0)
+	if e.chain != nil {
+		e.chain.RunFinished(scheduler.ID(p.task.ID), p.run.ScheduledFor, false)
+	}
+
+	p.err = err
+	close(p.done)
+	e.currentPromises.Delete(p.run.ID)
 }
 
 func (e *Executor) startWorker() {
@@ -201,6 +301,19 @@ func (e *Executor) Cancel(ctx context.Context, runID influxdb.ID) error {
 }
 
 func (e *Executor) createPromise(ctx context.Context, run *influxdb.Run) (*promise, error) {
+	p, err := e.newPromise(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+
+	// insert promise into queue to be worked
+	// when the queue gets full we will hand and apply back pressure to the scheduler
+	e.promiseQueue <- p
+	return p, nil
+}
+
+// newPromise creates the promise of a run and inserts it into the registry, without queueing it.
+func (e *Executor) newPromise(ctx context.Context, run *influxdb.Run) (*promise, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -221,10 +334,6 @@ func (e *Executor) createPromise(ctx context.Context, run *influxdb.Run) (*promi
 		cancelFunc: cancel,
 	}
 
-	// insert promise into queue to be worked
-	// when the queue gets full we will hand and apply back pressure to the scheduler
-	e.promiseQueue <- p
-
 	// insert the promise into the registry
 	e.currentPromises.Store(run.ID, p)
 	return p, nil
@@ -280,6 +389,9 @@ func (w *worker) work() {
 			case <-prom.ctx.Done():
 				w.e.tcs.AddRunLog(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), "Run canceled")
 				w.e.tcs.UpdateRunState(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), backend.RunCanceled)
+				if w.e.chain != nil {
+					w.e.chain.RunFinished(scheduler.ID(prom.task.ID), prom.run.ScheduledFor, false)
+				}
 				prom.err = influxdb.ErrRunCanceled
 				close(prom.done)
 				return
@@ -351,26 +463,185 @@ func (w *worker) finish(p *promise, rs backend.RunStatus, err error) {
 		w.e.log.Debug("Completed successfully", zap.String("taskID", p.task.ID.String()))
 	}
 
-	if _, err := w.e.tcs.FinishRun(p.ctx, p.task.ID, p.run.ID); err != nil {
-		w.e.log.Error("Failed to finish run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
+	run, ferr := w.e.tcs.FinishRun(p.ctx, p.task.ID, p.run.ID)
+	if ferr != nil {
+		w.e.log.Error("Failed to finish run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(ferr))
+		run = p.run
+	}
+
+	// the outcome of a retried run is the outcome of its last attempt.
+	if rs == backend.RunFail && w.e.retry(p) {
+		return
+	}
+
+	// the failures of the task are only counted once its run finished.
+	if ferr == nil {
+		w.e.notify(p, run, rs, err)
+	}
+
+	if w.e.chain != nil {
+		w.e.chain.RunFinished(scheduler.ID(p.task.ID), p.run.ScheduledFor, rs == backend.RunSuccess)
 	}
 }
 
+// notify notifies the notification endpoint of the task of p of the outcome of its run, as the notification
+// policy of the task requires. The notification is sent in the background.
+func (e *Executor) notify(p *promise, run *influxdb.Run, rs backend.RunStatus, err error) {
+	n := p.task.Notification
+	if n == nil || e.notifier == nil {
+		return
+	}
+
+	msg := influxdb.TaskRunNotification{
+		TaskID:         p.task.ID,
+		TaskName:       p.task.Name,
+		OrganizationID: p.task.OrganizationID,
+		RunID:          run.ID,
+		ScheduledFor:   run.ScheduledFor,
+	}
+	switch rs {
+	case backend.RunFail:
+		msg.ConsecutiveFailures = run.ConsecutiveFailures
+		if !n.NotifyFailure(msg.ConsecutiveFailures) {
+			return
+		}
+		if err != nil {
+			msg.Error = err.Error()
+		}
+	case backend.RunSuccess:
+		if !n.NotifyRecovery(run.ConsecutiveFailures) {
+			return
+		}
+		msg.Recovered = true
+		msg.ConsecutiveFailures = run.ConsecutiveFailures
+	default:
+		return
+	}
+
+	msg.Log = run.Log
+	if len(msg.Log) > influxdb.TaskNotificationLogSize {
+		msg.Log = msg.Log[len(msg.Log)-influxdb.TaskNotificationLogSize:]
+	}
+
+	go func() {
+		if err := e.notifier.NotifyTaskRun(context.Background(), n.EndpointID, msg); err != nil {
+			e.log.Error("Failed to notify of task run", zap.String("taskID", msg.TaskID.String()), zap.String("runID", msg.RunID.String()), zap.Error(err))
+		}
+	}()
+}
+
+// retry creates a run retrying the failed run of p and queues it once its backoff delay elapsed.
+// It reports whether the run is retried, which it is not once the task ran out of attempts or the run was canceled.
+func (e *Executor) retry(p *promise) bool {
+	if p.ctx.Err() != nil || int64(p.run.Attempt+1) >= p.task.Retry {
+		return false
+	}
+
+	delay := retryDelay(p.task, p.run.Attempt)
+	r, err := e.tcs.CreateRetryRun(p.ctx, p.run, time.Now().Add(delay).UTC())
+	if err != nil {
+		e.log.Error("Failed to create retry run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
+		return false
+	}
+	e.tcs.AddRunLog(p.ctx, r.TaskID, r.ID, time.Now().UTC(), fmt.Sprintf("Retry %d of run %s in %s", r.Attempt, r.RetryOf, delay))
+
+	// the retry runs under the authorization of the task, as the request of the first attempt may be gone.
+	ctx := e.ctx
+	if p.task.Authorization != nil {
+		ctx = icontext.SetAuthorizer(ctx, p.task.Authorization)
+	}
+	rp, err := e.newPromise(ctx, r)
+	if err != nil {
+		e.log.Error("Failed to queue retry run", zap.String("taskID", r.TaskID.String()), zap.String("runID", r.ID.String()), zap.Error(err))
+		return false
+	}
+	p.retry = rp
+
+	go func() {
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-rp.ctx.Done():
+			e.skip(rp, backend.RunCanceled, "Run canceled", influxdb.ErrRunCanceled)
+			return
+		}
+		e.promiseQueue <- rp
+		e.startWorker()
+	}()
+	return true
+}
+
+// retryDelay returns the delay before the retry following the given attempt of a run of t.
+// The delay doubles with each attempt, up to the maximum delay of t.
+func retryDelay(t *influxdb.Task, attempt int) time.Duration {
+	delay, maxDelay := t.RetryDelay, t.RetryMaxDelay
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+	if maxDelay < delay {
+		maxDelay = delay
+	}
+	for i := 0; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// queryKind returns the kind of the queries issued by runs of t.
+// Checks and notification rules create tasks of their own type,
+// every other task is a system task.
+func queryKind(t *influxdb.Task) query.Kind {
+	if t.Type == "" || t.Type == influxdb.TaskSystemType {
+		return query.KindTask
+	}
+	return query.KindCheck
+}
+
 func (w *worker) executeQuery(p *promise) {
 	span, ctx := tracing.StartSpanFromContext(p.ctx)
 	defer span.Finish()
 
+	// a run exceeding the timeout of its task is canceled, so it no longer holds a worker.
+	if p.task.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.task.Timeout)
+		defer cancel()
+	}
+	fail := func(err error) {
+		if ctx.Err() == context.DeadlineExceeded && p.ctx.Err() == nil {
+			err = influxdb.ErrRunTimedOut(p.task.Timeout)
+		}
+		w.finish(p, backend.RunFail, err)
+	}
+
 	// start
 	w.start(p)
 
 	pkg, err := flux.Parse(p.task.Flux)
 	if err != nil {
-		w.finish(p, backend.RunFail, influxdb.ErrFluxParseError(err))
+		fail(influxdb.ErrFluxParseError(err))
 		return
 	}
 
 	sf := p.run.ScheduledFor
 
+	if err := w.e.silenceNotifications(ctx, p.task.OrganizationID, pkg, sf); err != nil {
+		fail(err)
+		return
+	}
+
+	var notifications *notificationCapture
+	if queryKind(p.task) == query.KindCheck {
+		notifications = w.e.captureDeliveries(p.task.OrganizationID, pkg)
+	}
+
 	req := &query.Request{
 		Authorization:  p.auth,
 		OrganizationID: p.task.OrganizationID,
@@ -381,25 +652,52 @@ func (w *worker) executeQuery(p *promise) {
 	}
 	req.WithReturnNoContent(true)
 	ctx = icontext.SetAuthorizer(ctx, p.task.Authorization)
+	ctx = query.ContextWithKind(ctx, queryKind(p.task))
 	it, err := w.e.qs.Query(ctx, req)
 	if err != nil {
 		// Assume the error should not be part of the runResult.
-		w.finish(p, backend.RunFail, influxdb.ErrQueryError(err))
+		fail(influxdb.ErrQueryError(err))
 		return
 	}
 
+	// the statuses of checks are captured while draining their results when alerts are tracked,
+	// as are the notifications of notification rules when they are delivered by the executor.
+	exhaust := w.exhaustResultIterators
+	var statuses *statusCapture
+	if w.e.alerts != nil && queryKind(p.task) == query.KindCheck {
+		statuses = newStatusCapture(p.task.OrganizationID)
+	}
+	if statuses != nil || notifications != nil {
+		exhaust = func(res flux.Result) error {
+			return res.Tables().Do(func(tbl flux.Table) error {
+				switch {
+				case notifications != nil && notifications.matches(tbl):
+					return notifications.captureTable(tbl)
+				case statuses != nil:
+					return statuses.captureTable(tbl)
+				}
+				return tbl.Do(func(flux.ColReader) error {
+					return nil
+				})
+			})
+		}
+	}
+
 	var runErr error
 	// Drain the result iterator.
 	for it.More() {
 		// Consume the full iterator so that we don't leak outstanding iterators.
 		res := it.Next()
-		if runErr = w.exhaustResultIterators(res); runErr != nil {
+		if runErr = exhaust(res); runErr != nil {
 			w.e.log.Info("Error exhausting result iterator", zap.Error(runErr), zap.String("name", res.Name()))
 		}
 	}
 
 	it.Release()
 
+	// the notifications captured are delivered whatever the outcome of the run, as they would have been sent.
+	w.e.deliverNotifications(p.ctx, p.task, notifications)
+
 	// log the trace id and whether or not it was sampled into the run log
 	if traceID, isSampled, ok := tracing.InfoFromSpan(span); ok {
 		msg := fmt.Sprintf("trace_id=%s is_sampled=%t", traceID, isSampled)
@@ -407,15 +705,16 @@ func (w *worker) executeQuery(p *promise) {
 	}
 
 	if runErr != nil {
-		w.finish(p, backend.RunFail, influxdb.ErrRunExecutionError(runErr))
+		fail(influxdb.ErrRunExecutionError(runErr))
 		return
 	}
 
 	if it.Err() != nil {
-		w.finish(p, backend.RunFail, influxdb.ErrResultIteratorError(it.Err()))
+		fail(influxdb.ErrResultIteratorError(it.Err()))
 		return
 	}
 
+	w.e.trackAlerts(p.ctx, p.task, statuses)
 	w.finish(p, backend.RunSuccess, nil)
 }
 
@@ -435,6 +734,8 @@ func (e *Executor) PromiseQueueUsage() float64 {
 	return float64(len(e.promiseQueue)) / float64(cap(e.promiseQueue))
 }
 
+var _ RetriedPromise = (*promise)(nil)
+
 // promise represents a promise the executor makes to finish a run's execution asynchronously.
 type promise struct {
 	run  *influxdb.Run
@@ -449,6 +750,9 @@ type promise struct {
 
 	ctx        context.Context
 	cancelFunc context.CancelFunc
+
+	// retry is the promise of the run retrying the failed run, set before done is closed.
+	retry *promise
 }
 
 // ID is the id of the run that was created
@@ -473,6 +777,15 @@ func (p *promise) Done() <-chan struct{} {
 	return p.done
 }
 
+// Retry returns the promise of the run retrying the failed run, or nil if it is not retried.
+func (p *promise) Retry() Promise {
+	<-p.done
+	if p.retry == nil {
+		return nil
+	}
+	return p.retry
+}
+
 // Error returns the error resulting from a run execution.
 // If the execution is not complete error waits on Done().
 func (p *promise) Error() error {
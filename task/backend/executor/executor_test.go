@@ -18,6 +18,7 @@ import (
 	"github.com/influxdata/influxdb/kit/prom/promtest"
 	tracetest "github.com/influxdata/influxdb/kit/tracing/testing"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/notification/endpoint"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/task/backend"
 	"github.com/influxdata/influxdb/task/backend/scheduler"
@@ -76,6 +77,11 @@ func TestTaskExecutor(t *testing.T) {
 	t.Run("Metrics", testMetrics)
 	t.Run("IteratorFailure", testIteratorFailure)
 	t.Run("ErrorHandling", testErrorHandling)
+	t.Run("QueryKind", testQueryKind)
+	t.Run("Upstreams", testUpstreams)
+	t.Run("Timeout", testTimeout)
+	t.Run("Retry", testRetry)
+	t.Run("Notification", testNotification)
 }
 
 func testQuerySuccess(t *testing.T) {
@@ -146,6 +152,44 @@ func testQuerySuccess(t *testing.T) {
 	}
 }
 
+func testQueryKind(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		taskType string
+		want     query.Kind
+	}{
+		{taskType: "", want: query.KindTask},
+		{taskType: influxdb.TaskSystemType, want: query.KindTask},
+		{taskType: "threshold", want: query.KindCheck},
+	} {
+		tes := taskExecutorSystem(t)
+
+		script := fmt.Sprintf(fmtTestScript, t.Name())
+		ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+		task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{Type: tt.taskType, OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tes.svc.WaitForQueryLive(t, script)
+
+		tes.svc.mu.Lock()
+		got := query.KindFromContext(tes.svc.mostRecentCtx)
+		tes.svc.mu.Unlock()
+		if got != tt.want {
+			t.Errorf("unexpected query kind for task type %q; got %q, want %q", tt.taskType, got, tt.want)
+		}
+
+		tes.svc.SucceedQuery(script)
+		<-promise.Done()
+	}
+}
+
 func testQueryFailure(t *testing.T) {
 	t.Parallel()
 	tes := taskExecutorSystem(t)
@@ -527,6 +571,346 @@ func testErrorHandling(t *testing.T) {
 	*/
 }
 
+func testTimeout(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(`option task = {name: %q, every: 1m, timeout: 1s}
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, script)
+
+	// the query never finishes, the run is canceled once it exceeded the timeout.
+	select {
+	case <-promise.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the run to time out")
+	}
+	if err, ok := promise.Error().(*influxdb.Error); !ok || err.Code != influxdb.EUnavailable {
+		t.Fatalf("expected a timeout error, got %v", promise.Error())
+	}
+	if run := tes.tcs.run; run == nil || run.Status != backend.RunFail.String() {
+		t.Fatalf("expected a failed run, got %+v", run)
+	}
+}
+
+func testRetry(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(`option task = {name: %q, every: 1m, retry: 2, retryDelay: 1s}
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tes.svc.FailNextQuery(errors.New("forced"))
+	// the retry outlives the context of the first attempt.
+	rctx, cancel := context.WithCancel(ctx)
+	promise, err := tes.ex.PromisedExecute(rctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-promise.Done()
+	cancel()
+	if promise.Error() == nil {
+		t.Fatal("expected the first attempt to fail")
+	}
+
+	// the failed run is retried by a distinct run.
+	runs, err := tes.i.CurrentlyRunning(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].RetryOf != promise.ID() || runs[0].Attempt != 1 {
+		t.Fatalf("expected a retry of run %s, got %+v", promise.ID(), runs)
+	}
+	retry := runs[0]
+	if r := promise.(RetriedPromise).Retry(); r == nil || r.ID() != retry.ID {
+		t.Fatalf("expected the promise to follow its retry %s, got %v", retry.ID, r)
+	}
+
+	for i := 0; ; i++ {
+		r, err := tes.i.FindRunByID(ctx, task.ID, retry.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Status == backend.RunStarted.String() {
+			break
+		}
+		if i == 100 {
+			t.Fatal("expected the retry to start after its delay")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.SucceedQuery(script)
+
+	for i := 0; ; i++ {
+		if _, err := tes.i.FindRunByID(ctx, task.ID, retry.ID); err == influxdb.ErrRunNotFound || i == 100 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if run := tes.tcs.run; run == nil || run.ID != retry.ID || run.Status != backend.RunSuccess.String() {
+		t.Fatalf("expected the retry to succeed, got %+v", run)
+	}
+
+	// the retry delay doubles with each attempt, up to the maximum delay.
+	if d := retryDelay(&influxdb.Task{RetryDelay: time.Second, RetryMaxDelay: 3 * time.Second}, 1); d != 2*time.Second {
+		t.Fatalf("expected the retry delay to double, got %s", d)
+	}
+	if d := retryDelay(&influxdb.Task{RetryDelay: time.Second, RetryMaxDelay: 3 * time.Second}, 2); d != 3*time.Second {
+		t.Fatalf("expected the retry delay to be capped, got %s", d)
+	}
+}
+
+// notifierFunc notifies of task runs by calling the func.
+type notifierFunc func(ctx context.Context, endpointID influxdb.ID, n influxdb.TaskRunNotification) error
+
+func (f notifierFunc) NotifyTaskRun(ctx context.Context, endpointID influxdb.ID, n influxdb.TaskRunNotification) error {
+	return f(ctx, endpointID, n)
+}
+
+func testNotification(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	orgID := tes.tc.OrgID
+	edp := &endpoint.Slack{
+		Base: endpoint.Base{Name: "slack", OrgID: &orgID, Status: influxdb.Active},
+		URL:  "http://localhost:9999/slack",
+	}
+	if err := tes.i.CreateNotificationEndpoint(ctx, edp, tes.tc.Auth.GetUserID()); err != nil {
+		t.Fatal(err)
+	}
+
+	notifications := make(chan influxdb.TaskRunNotification, 1)
+	tes.ex.SetNotifier(notifierFunc(func(ctx context.Context, endpointID influxdb.ID, n influxdb.TaskRunNotification) error {
+		if endpointID != edp.GetID() {
+			t.Errorf("unexpected endpoint %s", endpointID)
+		}
+		notifications <- n
+		return nil
+	}))
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{
+		OrganizationID: tes.tc.OrgID,
+		OwnerID:        tes.tc.Auth.GetUserID(),
+		Flux:           script,
+		Notification:   &influxdb.TaskNotification{EndpointID: edp.GetID(), Policy: influxdb.TaskNotifyConsecutive, Consecutive: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the fake query service only knows the queries of runs scheduled for 123.
+	execute := func(fail bool) {
+		t.Helper()
+		if fail {
+			tes.svc.FailNextQuery(errors.New("forced"))
+		}
+		promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fail {
+			tes.svc.WaitForQueryLive(t, script)
+			tes.svc.SucceedQuery(script)
+		}
+		<-promise.Done()
+	}
+	expectFailures := func(n int) {
+		t.Helper()
+		task, err := tes.i.FindTaskByID(ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.ConsecutiveFailures != n {
+			t.Fatalf("expected %d consecutive failures, got %d", n, task.ConsecutiveFailures)
+		}
+	}
+	expectNotification := func() influxdb.TaskRunNotification {
+		t.Helper()
+		select {
+		case n := <-notifications:
+			return n
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a notification")
+		}
+		return influxdb.TaskRunNotification{}
+	}
+	expectNoNotification := func() {
+		t.Helper()
+		select {
+		case n := <-notifications:
+			t.Fatalf("unexpected notification %+v", n)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	// the first failure is below the threshold of the task.
+	execute(true)
+	expectFailures(1)
+	expectNoNotification()
+
+	execute(true)
+	expectFailures(2)
+	n := expectNotification()
+	if n.Recovered || n.ConsecutiveFailures != 2 || n.TaskName != t.Name() || !strings.Contains(n.Error, "forced") || len(n.Log) == 0 {
+		t.Fatalf("unexpected failure notification %+v", n)
+	}
+
+	// the following failures were notified already.
+	execute(true)
+	expectFailures(3)
+	expectNoNotification()
+
+	execute(false)
+	expectFailures(0)
+	n = expectNotification()
+	if !n.Recovered || n.ConsecutiveFailures != 3 || n.Error != "" {
+		t.Fatalf("unexpected recovery notification %+v", n)
+	}
+
+	execute(false)
+	expectNoNotification()
+
+	// runs that failed while this run executed are counted by the time it finishes.
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, script)
+	failures := 2
+	if _, err := tes.i.UpdateTask(ctx, task.ID, influxdb.TaskUpdate{ConsecutiveFailures: &failures}); err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.SucceedQuery(script)
+	<-promise.Done()
+	expectFailures(0)
+	n = expectNotification()
+	if !n.Recovered || n.ConsecutiveFailures != 2 {
+		t.Fatalf("unexpected recovery notification %+v", n)
+	}
+}
+
+// upstreamSchedulable is a task scheduled with upstream tasks.
+type upstreamSchedulable struct {
+	id        scheduler.ID
+	sch       scheduler.Schedule
+	upstreams []scheduler.ID
+}
+
+func (s upstreamSchedulable) ID() scheduler.ID             { return s.id }
+func (s upstreamSchedulable) Schedule() scheduler.Schedule { return s.sch }
+func (s upstreamSchedulable) Offset() time.Duration        { return 0 }
+func (s upstreamSchedulable) LastScheduled() time.Time     { return time.Now() }
+func (s upstreamSchedulable) Upstreams() []scheduler.ID    { return s.upstreams }
+
+type noopCheckpointer struct{}
+
+func (noopCheckpointer) UpdateLastScheduled(context.Context, scheduler.ID, time.Time) error {
+	return nil
+}
+
+func testUpstreams(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	upScript := fmt.Sprintf(fmtTestScript, t.Name()+"-upstream")
+	up, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: upScript})
+	if err != nil {
+		t.Fatal(err)
+	}
+	downScript := fmt.Sprintf(`option task = {name: %q, every: 1m, after: [%q]}
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`, t.Name()+"-downstream", up.ID.String())
+	down, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: downScript})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := scheduler.NewChain()
+	tes.ex.SetChain(chain)
+	sch, _, err := scheduler.NewScheduler(tes.ex, noopCheckpointer{}, scheduler.WithChain(chain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sch.Stop()
+	every, _, err := scheduler.NewSchedule("@every 1h", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Schedule(upstreamSchedulable{id: scheduler.ID(up.ID), sch: every}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sch.Schedule(upstreamSchedulable{id: scheduler.ID(down.ID), sch: every, upstreams: []scheduler.ID{scheduler.ID(up.ID)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the downstream run executes after the upstream run succeeded.
+	downPromise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(down.ID), time.Unix(123, 0), time.Unix(123, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, err := tes.i.FindRunByID(context.Background(), down.ID, downPromise.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != backend.RunScheduled.String() {
+		t.Fatalf("expected downstream run to wait, got status %q", run.Status)
+	}
+
+	upPromise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(up.ID), time.Unix(123, 0), time.Unix(123, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tes.svc.WaitForQueryLive(t, upScript)
+	tes.svc.SucceedQuery(upScript)
+	<-upPromise.Done()
+
+	tes.svc.WaitForQueryLive(t, downScript)
+	tes.svc.SucceedQuery(downScript)
+	<-downPromise.Done()
+	if err := downPromise.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the downstream run is blocked when the upstream run failed.
+	tes.svc.FailNextQuery(errors.New("upstream failure"))
+	upPromise, err = tes.ex.PromisedExecute(ctx, scheduler.ID(up.ID), time.Unix(183, 0), time.Unix(183, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-upPromise.Done()
+
+	downPromise, err = tes.ex.PromisedExecute(ctx, scheduler.ID(down.ID), time.Unix(183, 0), time.Unix(183, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-downPromise.Done()
+	if err := downPromise.Error(); err == nil {
+		t.Fatal("expected the downstream run to be blocked")
+	}
+	if run := tes.tcs.run; run == nil || run.Status != backend.RunBlocked.String() {
+		t.Fatalf("expected a blocked run, got %+v", run)
+	}
+}
+
 type taskControlService struct {
 	backend.TaskControlService
 
@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/flux"
+)
+
+// SetSilenceService sets the service the silences suppressing the notifications of notification rules are found with.
+func (e *Executor) SetSilenceService(s influxdb.SilenceService) {
+	e.silences = s
+}
+
+// silenceNotifications filters the statuses notified by the script of pkg out when they match
+// a silence of the organization active at now. The statuses themselves are not affected.
+func (e *Executor) silenceNotifications(ctx context.Context, orgID influxdb.ID, pkg *ast.Package, now time.Time) error {
+	if e.silences == nil || len(pkg.Files) == 0 {
+		return nil
+	}
+	f := pkg.Files[0]
+	monitor := importName(f, monitorPackagePath)
+	if monitor == "" || len(notifyPipes(f, monitor)) == 0 {
+		return nil
+	}
+
+	ss, _, err := e.silences.FindSilences(ctx, influxdb.SilenceFilter{
+		OrgID:    &orgID,
+		ActiveAt: &now,
+	})
+	if err != nil {
+		return err
+	}
+	silence(f, monitor, ss)
+	return nil
+}
+
+// silence wraps the statuses piped to the notify calls of f with a filter dropping the ones matched by ss.
+func silence(f *ast.File, monitor string, ss []*influxdb.Silence) {
+	if len(ss) == 0 {
+		return
+	}
+	var body ast.Expression
+	for _, s := range ss {
+		var e ast.Expression = not(silenceMatch(s))
+		if body != nil {
+			e = flux.And(body, e)
+		}
+		body = e
+	}
+	for _, pipe := range notifyPipes(f, monitor) {
+		pipe.Argument = flux.Pipe(pipe.Argument, flux.Call(
+			flux.Identifier("filter"),
+			flux.Object(flux.Property("fn", flux.Function(flux.FunctionParams("r"), body))),
+		))
+	}
+}
+
+// notifyPipes returns the pipe expressions of f calling the notify function of the monitor package.
+func notifyPipes(f *ast.File, monitor string) []*ast.PipeExpression {
+	var pipes []*ast.PipeExpression
+	ast.Visit(f, func(n ast.Node) {
+		if pipe, ok := n.(*ast.PipeExpression); ok && isMonitorCall(pipe.Call, monitor, "notify") {
+			pipes = append(pipes, pipe)
+		}
+	})
+	return pipes
+}
+
+// silenceMatch returns the expression matching the records of the statuses silenced by s,
+// a record matches when it matches all the matchers of s.
+func silenceMatch(s *influxdb.Silence) ast.Expression {
+	if len(s.Matchers) == 0 {
+		return flux.Bool(true)
+	}
+	var match ast.Expression
+	for _, m := range s.Matchers {
+		e := matcherExpression(m)
+		if match != nil {
+			e = flux.And(match, e)
+		}
+		match = e
+	}
+	return match
+}
+
+// matcherExpression returns the expression of m, a record without the column of m
+// only matches the negative operators.
+func matcherExpression(m influxdb.TagRule) ast.Expression {
+	k := flux.Member("r", m.Key)
+	exists := &ast.UnaryExpression{Operator: ast.ExistsOperator, Argument: k}
+
+	switch m.Operator {
+	case influxdb.NotEqual:
+		return flux.Or(not(exists), binary(ast.NotEqualOperator, k, flux.String(m.Value)))
+	case influxdb.RegexEqual:
+		return flux.And(exists, binary(ast.RegexpMatchOperator, k, regexpLiteral(m.Value)))
+	case influxdb.NotRegexEqual:
+		return flux.Or(not(exists), binary(ast.NotRegexpMatchOperator, k, regexpLiteral(m.Value)))
+	default:
+		return flux.And(exists, flux.Equal(k, flux.String(m.Value)))
+	}
+}
+
+func not(e ast.Expression) *ast.UnaryExpression {
+	return &ast.UnaryExpression{Operator: ast.NotOperator, Argument: e}
+}
+
+func binary(op ast.OperatorKind, lhs, rhs ast.Expression) *ast.BinaryExpression {
+	return &ast.BinaryExpression{Operator: op, Left: lhs, Right: rhs}
+}
+
+// regexpLiteral returns the literal of the regular expression v, which is validated with its silence.
+func regexpLiteral(v string) *ast.RegexpLiteral {
+	return &ast.RegexpLiteral{Value: regexp.MustCompile(v)}
+}
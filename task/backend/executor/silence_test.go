@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestSilenceNotifications(t *testing.T) {
+	const script = `import "influxdata/influxdb/monitor"
+
+statuses = monitor.from(start: -2h)
+
+statuses
+	|> monitor.notify(data: {}, endpoint: e)`
+
+	var (
+		now     = time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+		filters []influxdb.SilenceFilter
+		ss      = mock.NewSilenceService()
+		e       = &Executor{silences: ss}
+	)
+	ss.FindSilencesFn = func(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+		filters = append(filters, filter)
+		return []*influxdb.Silence{
+			{Matchers: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "host", Value: "a"}, Operator: influxdb.Equal},
+				{Tag: influxdb.Tag{Key: "_level", Value: "ok"}, Operator: influxdb.NotEqual},
+			}},
+			{Matchers: []influxdb.TagRule{
+				{Tag: influxdb.Tag{Key: "region", Value: "^us-"}, Operator: influxdb.RegexEqual},
+				{Tag: influxdb.Tag{Key: "env", Value: "prod"}, Operator: influxdb.NotRegexEqual},
+			}},
+		}, 2, nil
+	}
+
+	pkg, err := flux.Parse(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.silenceNotifications(context.Background(), 1, pkg, now); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `import "influxdata/influxdb/monitor"
+
+statuses = monitor.from(start: -2h)
+
+statuses
+	|> filter(fn: (r) =>
+		(not (exists r.host and r.host == "a" and (not exists r._level or r._level != "ok")) and not (exists r.region and r.region =~ /^us-/ and (not exists r.env or r.env !~ /prod/))))
+	|> monitor.notify(data: {}, endpoint: e)`
+	if got := ast.Format(pkg.Files[0]); got != want {
+		t.Errorf("unexpected script -want/+got:\n%s", cmp.Diff(want, got))
+	}
+	orgID := influxdb.ID(1)
+	if want := []influxdb.SilenceFilter{{OrgID: &orgID, ActiveAt: &now}}; !cmp.Equal(want, filters) {
+		t.Errorf("unexpected filters -want/+got:\n%s", cmp.Diff(want, filters))
+	}
+
+	// the silences are only looked up for the scripts sending notifications.
+	pkg, err = flux.Parse(`import "influxdata/influxdb/monitor" from(bucket: "b") |> range(start: -1h) |> monitor.check(data: {}, messageFn: (r) => "")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.silenceNotifications(context.Background(), 1, pkg, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 1 {
+		t.Errorf("expected no silence lookup for a check, got %d lookups", len(filters))
+	}
+}
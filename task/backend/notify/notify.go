@@ -0,0 +1,392 @@
+// Package notify sends the notifications of task runs and of alerts to notification endpoints.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"go.uber.org/zap"
+)
+
+var (
+	_ influxdb.TaskRunNotifier = (*Service)(nil)
+	_ influxdb.AlertNotifier   = (*Service)(nil)
+)
+
+const (
+	// PagerDutyEventsURL is the URL of the PagerDuty events API the notifications of PagerDuty endpoints are sent to.
+	PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+	// DefaultTimeout is the time a notification is given to be delivered.
+	DefaultTimeout = 30 * time.Second
+)
+
+// EndpointFinder finds the notification endpoints of tasks and of notification rules.
+type EndpointFinder interface {
+	FindNotificationEndpointByID(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error)
+}
+
+// SecretLoader loads the secrets of notification endpoints.
+type SecretLoader interface {
+	LoadSecret(ctx context.Context, orgID influxdb.ID, k string) (string, error)
+}
+
+// Service sends the notifications of task runs and of alerts through HTTP, Slack and PagerDuty notification endpoints.
+type Service struct {
+	log       *zap.Logger
+	endpoints EndpointFinder
+	secrets   SecretLoader
+
+	// Client is the client the notifications are sent with.
+	Client *http.Client
+	// PagerDutyURL is the URL of the PagerDuty events API.
+	PagerDutyURL string
+}
+
+// NewService returns a Service sending notifications to the endpoints found by endpoints, with the secrets loaded by secrets.
+func NewService(log *zap.Logger, endpoints EndpointFinder, secrets SecretLoader) *Service {
+	return &Service{
+		log:          log,
+		endpoints:    endpoints,
+		secrets:      secrets,
+		Client:       &http.Client{Timeout: DefaultTimeout},
+		PagerDutyURL: PagerDutyEventsURL,
+	}
+}
+
+// NotifyTaskRun sends n to the notification endpoint endpointID.
+// Nothing is sent to an inactive endpoint.
+func (s *Service) NotifyTaskRun(ctx context.Context, endpointID influxdb.ID, n influxdb.TaskRunNotification) error {
+	return s.send(ctx, endpointID, message{
+		orgID:    n.OrganizationID,
+		title:    n.Title(),
+		details:  details(n),
+		resolved: n.Recovered,
+		payload:  httpPayload{TaskRunNotification: n, Message: n.Title()},
+		// the failures of a task are one alert, which is resolved once the task recovers.
+		dedupKey:  "task-" + n.TaskID.String(),
+		source:    n.TaskName,
+		severity:  "error",
+		timestamp: n.ScheduledFor,
+		customDetails: map[string]interface{}{
+			"taskID":              n.TaskID.String(),
+			"runID":               n.RunID.String(),
+			"consecutiveFailures": n.ConsecutiveFailures,
+			"error":               n.Error,
+			"log":                 n.Log,
+		},
+		logField: zap.Stringer("taskID", n.TaskID),
+	})
+}
+
+// NotifyAlert sends n to the notification endpoint endpointID.
+// Nothing is sent to an inactive endpoint.
+func (s *Service) NotifyAlert(ctx context.Context, endpointID influxdb.ID, n influxdb.AlertNotification) error {
+	return s.send(ctx, endpointID, message{
+		orgID:     n.Alert.OrgID,
+		title:     n.Title(),
+		details:   alertDetails(n),
+		resolved:  n.Alert.Status == influxdb.AlertResolved,
+		payload:   alertHTTPPayload{AlertNotification: n, Message: n.Title()},
+		dedupKey:  "alert-" + n.Alert.ID.String(),
+		source:    n.Alert.CheckName,
+		severity:  pagerDutySeverity(n.Alert.Level),
+		timestamp: n.Alert.LastStatusAt,
+		customDetails: map[string]interface{}{
+			"alertID":        n.Alert.ID.String(),
+			"checkID":        n.Alert.CheckID.String(),
+			"tags":           n.Alert.Tags,
+			"message":        n.Alert.Message,
+			"openedAt":       n.Alert.OpenedAt.UTC().Format(time.RFC3339),
+			"ruleID":         n.RuleID.String(),
+			"ruleName":       n.RuleName,
+			"unacknowledged": n.Unacknowledged.String(),
+		},
+		logField: zap.Stringer("alertID", n.Alert.ID),
+	})
+}
+
+// message is a notification as it is sent to the endpoints of any type.
+type message struct {
+	orgID    influxdb.ID
+	title    string
+	details  string
+	resolved bool
+	// payload is the body sent to HTTP endpoints.
+	payload interface{}
+
+	// dedupKey, source, severity, timestamp and customDetails are the fields of PagerDuty events.
+	dedupKey      string
+	source        string
+	severity      string
+	timestamp     time.Time
+	customDetails map[string]interface{}
+
+	logField zap.Field
+}
+
+func (s *Service) send(ctx context.Context, endpointID influxdb.ID, m message) error {
+	edp, err := s.endpoints.FindNotificationEndpointByID(ctx, endpointID)
+	if err != nil {
+		return err
+	}
+	if edp.GetOrgID() != m.orgID {
+		return &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  fmt.Sprintf("notification endpoint %s not found", endpointID),
+		}
+	}
+	if edp.GetStatus() != influxdb.Active {
+		s.log.Debug("Not notifying inactive notification endpoint", zap.Stringer("endpointID", endpointID), m.logField)
+		return nil
+	}
+
+	var req *http.Request
+	switch e := edp.(type) {
+	case *endpoint.HTTP:
+		req, err = s.httpRequest(ctx, e, m)
+	case *endpoint.Slack:
+		req, err = s.slackRequest(ctx, e, m)
+	case *endpoint.PagerDuty:
+		req, err = s.pagerDutyRequest(ctx, e, m)
+	default:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("notification endpoint type %q can not be notified", edp.Type()),
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  fmt.Sprintf("failed to notify %s endpoint %s", edp.Type(), endpointID),
+			Err:  err,
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  fmt.Sprintf("%s endpoint %s responded with status %d: %s", edp.Type(), endpointID, resp.StatusCode, strings.TrimSpace(string(body))),
+		}
+	}
+	return nil
+}
+
+// httpPayload is the body of the notifications of task runs sent to HTTP endpoints.
+type httpPayload struct {
+	influxdb.TaskRunNotification
+	Message string `json:"message"`
+}
+
+// alertHTTPPayload is the body of the notifications of alerts sent to HTTP endpoints.
+type alertHTTPPayload struct {
+	influxdb.AlertNotification
+	Message string `json:"message"`
+}
+
+func (s *Service) httpRequest(ctx context.Context, e *endpoint.HTTP, m message) (*http.Request, error) {
+	var body io.Reader
+	if e.Method != http.MethodGet {
+		b, err := json.Marshal(m.payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(e.Method, e.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	switch e.AuthMethod {
+	case "basic":
+		username, err := s.secret(ctx, m.orgID, e.Username)
+		if err != nil {
+			return nil, err
+		}
+		password, err := s.secret(ctx, m.orgID, e.Password)
+		if err != nil {
+			return nil, err
+		}
+		req.SetBasicAuth(username, password)
+	case "bearer":
+		token, err := s.secret(ctx, m.orgID, e.Token)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+type slackAttachment struct {
+	Color    string   `json:"color"`
+	Text     string   `json:"text"`
+	MrkdwnIn []string `json:"mrkdwn_in"`
+}
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+func (s *Service) slackRequest(ctx context.Context, e *endpoint.Slack, m message) (*http.Request, error) {
+	color := "danger"
+	if m.resolved {
+		color = "good"
+	}
+	b, err := json.Marshal(slackMessage{
+		Text: m.title,
+		Attachments: []slackAttachment{
+			{Color: color, Text: "```" + m.details + "```", MrkdwnIn: []string{"text"}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.URL, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if e.Token.Key != "" || e.Token.Value != nil {
+		token, err := s.secret(ctx, m.orgID, e.Token)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp"`
+	CustomDetails map[string]interface{} `json:"custom_details"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Client      string           `json:"client"`
+	ClientURL   string           `json:"client_url,omitempty"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+func (s *Service) pagerDutyRequest(ctx context.Context, e *endpoint.PagerDuty, m message) (*http.Request, error) {
+	routingKey, err := s.secret(ctx, m.orgID, e.RoutingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	action := "trigger"
+	if m.resolved {
+		action = "resolve"
+	}
+	b, err := json.Marshal(pagerDutyEvent{
+		RoutingKey:  routingKey,
+		EventAction: action,
+		DedupKey:    m.dedupKey,
+		Client:      "influxdata",
+		ClientURL:   e.ClientURL,
+		Payload: pagerDutyPayload{
+			Summary:       m.title,
+			Source:        m.source,
+			Severity:      m.severity,
+			Timestamp:     m.timestamp.UTC().Format(time.RFC3339),
+			CustomDetails: m.customDetails,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.PagerDutyURL, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// pagerDutySeverity returns the severity of the PagerDuty events of alerts of the level.
+func pagerDutySeverity(level string) string {
+	switch level {
+	case "crit":
+		return "critical"
+	case "warn":
+		return "warning"
+	case "info":
+		return "info"
+	}
+	return "error"
+}
+
+// secret returns the value of f, loaded from the secrets of the organization unless f holds it.
+func (s *Service) secret(ctx context.Context, orgID influxdb.ID, f influxdb.SecretField) (string, error) {
+	if f.Value != nil {
+		return *f.Value, nil
+	}
+	return s.secrets.LoadSecret(ctx, orgID, f.Key)
+}
+
+// details returns the text of n with the run and its log.
+func details(n influxdb.TaskRunNotification) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Task: %s (%s)\n", n.TaskName, n.TaskID)
+	fmt.Fprintf(&b, "Run: %s scheduled for %s\n", n.RunID, n.ScheduledFor.UTC().Format(time.RFC3339))
+	if n.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", n.Error)
+	}
+	if len(n.Log) > 0 {
+		b.WriteString("Log:\n")
+		for _, l := range n.Log {
+			b.WriteString(l.String())
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// alertDetails returns the text of n with the series of the alert and its last status.
+func alertDetails(n influxdb.AlertNotification) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Check: %s (%s)\n", n.Alert.CheckName, n.Alert.CheckID)
+	if series := n.Alert.SeriesKey(); series != "" {
+		fmt.Fprintf(&b, "Series: %s\n", series)
+	}
+	fmt.Fprintf(&b, "Alert: %s opened at %s\n", n.Alert.ID, n.Alert.OpenedAt.UTC().Format(time.RFC3339))
+	if n.Alert.Message != "" {
+		fmt.Fprintf(&b, "Message: %s\n", n.Alert.Message)
+	}
+	fmt.Fprintf(&b, "Escalated by: %s (%s)", n.RuleName, n.RuleID)
+	return b.String()
+}
@@ -0,0 +1,305 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification/endpoint"
+	"github.com/influxdata/influxdb/task/backend/notify"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	orgID      = influxdb.ID(0x100)
+	endpointID = influxdb.ID(0x200)
+)
+
+type request struct {
+	method string
+	header http.Header
+	body   map[string]interface{}
+}
+
+func newNotifier(t *testing.T, edp func(url string) influxdb.NotificationEndpoint, status int) (*notify.Service, <-chan request, func()) {
+	t.Helper()
+
+	reqs := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{method: r.Method, header: r.Header}
+		if b, _ := ioutil.ReadAll(r.Body); len(b) > 0 {
+			if err := json.Unmarshal(b, &req.body); err != nil {
+				t.Error(err)
+			}
+		}
+		reqs <- req
+		w.WriteHeader(status)
+	}))
+
+	endpoints := mock.NewNotificationEndpointService()
+	endpoints.FindNotificationEndpointByIDF = func(ctx context.Context, id influxdb.ID) (influxdb.NotificationEndpoint, error) {
+		if id != endpointID {
+			return nil, &influxdb.Error{Code: influxdb.ENotFound}
+		}
+		return edp(srv.URL), nil
+	}
+	secrets := mock.NewSecretService()
+	secrets.LoadSecretFn = func(ctx context.Context, id influxdb.ID, k string) (string, error) {
+		if id != orgID {
+			t.Errorf("unexpected organization of secret %q: %s", k, id)
+		}
+		return "secret" + k, nil
+	}
+
+	s := notify.NewService(zaptest.NewLogger(t), endpoints, secrets)
+	s.PagerDutyURL = srv.URL
+	return s, reqs, srv.Close
+}
+
+func base(status influxdb.Status) endpoint.Base {
+	id, org := endpointID, orgID
+	return endpoint.Base{ID: &id, OrgID: &org, Name: "endpoint", Status: status}
+}
+
+func notification(recovered bool) influxdb.TaskRunNotification {
+	return influxdb.TaskRunNotification{
+		TaskID:              0x300,
+		TaskName:            "my task",
+		OrganizationID:      orgID,
+		RunID:               0x400,
+		ScheduledFor:        time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC),
+		Recovered:           recovered,
+		ConsecutiveFailures: 2,
+		Error:               "query failed",
+		Log:                 []influxdb.Log{{Time: "2019-12-01T10:00:01Z", Message: "query failed"}},
+	}
+}
+
+func TestService_NotifyTaskRun(t *testing.T) {
+	t.Run("http bearer", func(t *testing.T) {
+		s, reqs, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.HTTP{
+				Base:       base(influxdb.Active),
+				URL:        url,
+				Method:     http.MethodPost,
+				AuthMethod: "bearer",
+				Token:      influxdb.SecretField{Key: "-token"},
+				Headers:    map[string]string{"X-Custom": "custom"},
+			}
+		}, http.StatusNoContent)
+		defer done()
+
+		if err := s.NotifyTaskRun(context.Background(), endpointID, notification(false)); err != nil {
+			t.Fatal(err)
+		}
+		req := <-reqs
+		if req.method != http.MethodPost {
+			t.Fatalf("unexpected method %s", req.method)
+		}
+		if got := req.header.Get("Authorization"); got != "Bearer secret-token" {
+			t.Fatalf("unexpected authorization %q", got)
+		}
+		if got := req.header.Get("X-Custom"); got != "custom" {
+			t.Fatalf("unexpected custom header %q", got)
+		}
+		if req.body["taskID"] != "0000000000000300" || req.body["runID"] != "0000000000000400" || req.body["error"] != "query failed" {
+			t.Fatalf("unexpected body %v", req.body)
+		}
+		if req.body["message"] != `Task "my task" failed 2 runs in a row` {
+			t.Fatalf("unexpected message %v", req.body["message"])
+		}
+	})
+
+	t.Run("http basic", func(t *testing.T) {
+		s, reqs, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.HTTP{
+				Base:       base(influxdb.Active),
+				URL:        url,
+				Method:     http.MethodPut,
+				AuthMethod: "basic",
+				Username:   influxdb.SecretField{Key: "-username"},
+				Password:   influxdb.SecretField{Key: "-password"},
+			}
+		}, http.StatusOK)
+		defer done()
+
+		if err := s.NotifyTaskRun(context.Background(), endpointID, notification(false)); err != nil {
+			t.Fatal(err)
+		}
+		req := <-reqs
+		r := &http.Request{Header: req.header}
+		if u, p, ok := r.BasicAuth(); !ok || u != "secret-username" || p != "secret-password" {
+			t.Fatalf("unexpected basic auth %q %q", u, p)
+		}
+	})
+
+	t.Run("slack", func(t *testing.T) {
+		s, reqs, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.Slack{Base: base(influxdb.Active), URL: url}
+		}, http.StatusOK)
+		defer done()
+
+		if err := s.NotifyTaskRun(context.Background(), endpointID, notification(true)); err != nil {
+			t.Fatal(err)
+		}
+		req := <-reqs
+		if got := req.header.Get("Authorization"); got != "" {
+			t.Fatalf("unexpected authorization %q", got)
+		}
+		if req.body["text"] != `Task "my task" recovered after 2 failed runs` {
+			t.Fatalf("unexpected text %v", req.body["text"])
+		}
+		att := req.body["attachments"].([]interface{})[0].(map[string]interface{})
+		if att["color"] != "good" || !strings.Contains(att["text"].(string), "Run: 0000000000000400") {
+			t.Fatalf("unexpected attachment %v", att)
+		}
+	})
+
+	t.Run("pagerduty", func(t *testing.T) {
+		s, reqs, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.PagerDuty{
+				Base:       base(influxdb.Active),
+				ClientURL:  "http://localhost:9999/tasks",
+				RoutingKey: influxdb.SecretField{Key: "-routing-key"},
+			}
+		}, http.StatusAccepted)
+		defer done()
+
+		for _, recovered := range []bool{false, true} {
+			if err := s.NotifyTaskRun(context.Background(), endpointID, notification(recovered)); err != nil {
+				t.Fatal(err)
+			}
+			req := <-reqs
+			action := "trigger"
+			if recovered {
+				action = "resolve"
+			}
+			if req.body["event_action"] != action || req.body["routing_key"] != "secret-routing-key" || req.body["dedup_key"] != "task-0000000000000300" {
+				t.Fatalf("unexpected event %v", req.body)
+			}
+		}
+	})
+
+	t.Run("inactive endpoint", func(t *testing.T) {
+		s, reqs, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.Slack{Base: base(influxdb.Inactive), URL: url}
+		}, http.StatusOK)
+		defer done()
+
+		if err := s.NotifyTaskRun(context.Background(), endpointID, notification(false)); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case req := <-reqs:
+			t.Fatalf("unexpected request to inactive endpoint %v", req)
+		default:
+		}
+	})
+
+	t.Run("endpoint of other organization", func(t *testing.T) {
+		s, _, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.Slack{Base: base(influxdb.Active), URL: url}
+		}, http.StatusOK)
+		defer done()
+
+		n := notification(false)
+		n.OrganizationID = orgID + 1
+		if err := s.NotifyTaskRun(context.Background(), endpointID, n); influxdb.ErrorCode(err) != influxdb.ENotFound {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		s, reqs, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.Slack{Base: base(influxdb.Active), URL: url}
+		}, http.StatusInternalServerError)
+		defer done()
+
+		err := s.NotifyTaskRun(context.Background(), endpointID, notification(false))
+		<-reqs
+		if influxdb.ErrorCode(err) != influxdb.EUnavailable {
+			t.Fatalf("expected unavailable error, got %v", err)
+		}
+	})
+}
+
+func alertNotification() influxdb.AlertNotification {
+	opened := time.Date(2019, 12, 1, 10, 0, 0, 0, time.UTC)
+	return influxdb.AlertNotification{
+		Alert: influxdb.Alert{
+			ID:           0x500,
+			OrgID:        orgID,
+			CheckID:      0x600,
+			CheckName:    "cpu",
+			Tags:         map[string]string{"host": "a"},
+			Status:       influxdb.AlertOpen,
+			Level:        "crit",
+			Message:      "cpu is high",
+			OpenedAt:     opened,
+			LastStatusAt: opened.Add(10 * time.Minute),
+		},
+		RuleID:         0x700,
+		RuleName:       "page on crit",
+		Unacknowledged: influxdb.Duration{Duration: 15 * time.Minute},
+	}
+}
+
+func TestService_NotifyAlert(t *testing.T) {
+	t.Run("http", func(t *testing.T) {
+		s, reqs, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.HTTP{Base: base(influxdb.Active), URL: url, Method: http.MethodPost, AuthMethod: "none"}
+		}, http.StatusOK)
+		defer done()
+
+		if err := s.NotifyAlert(context.Background(), endpointID, alertNotification()); err != nil {
+			t.Fatal(err)
+		}
+		req := <-reqs
+		alert, _ := req.body["alert"].(map[string]interface{})
+		if alert["id"] != "0000000000000500" || req.body["ruleID"] != "0000000000000700" {
+			t.Fatalf("unexpected body %v", req.body)
+		}
+		if req.body["message"] != `Alert of check "cpu" is crit and unacknowledged for 15m0s` {
+			t.Fatalf("unexpected message %v", req.body["message"])
+		}
+	})
+
+	t.Run("slack", func(t *testing.T) {
+		s, reqs, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.Slack{Base: base(influxdb.Active), URL: url}
+		}, http.StatusOK)
+		defer done()
+
+		if err := s.NotifyAlert(context.Background(), endpointID, alertNotification()); err != nil {
+			t.Fatal(err)
+		}
+		req := <-reqs
+		att := req.body["attachments"].([]interface{})[0].(map[string]interface{})
+		if att["color"] != "danger" || !strings.Contains(att["text"].(string), "Series: host=a") {
+			t.Fatalf("unexpected attachment %v", att)
+		}
+	})
+
+	t.Run("pagerduty", func(t *testing.T) {
+		s, reqs, done := newNotifier(t, func(url string) influxdb.NotificationEndpoint {
+			return &endpoint.PagerDuty{Base: base(influxdb.Active), RoutingKey: influxdb.SecretField{Key: "-routing-key"}}
+		}, http.StatusAccepted)
+		defer done()
+
+		if err := s.NotifyAlert(context.Background(), endpointID, alertNotification()); err != nil {
+			t.Fatal(err)
+		}
+		req := <-reqs
+		payload, _ := req.body["payload"].(map[string]interface{})
+		if req.body["event_action"] != "trigger" || req.body["dedup_key"] != "alert-0000000000000500" || payload["severity"] != "critical" {
+			t.Fatalf("unexpected event %v", req.body)
+		}
+	})
+}
@@ -49,6 +49,13 @@ func (s *StoragePointsWriterRecorder) Record(ctx context.Context, orgID influxdb
 	fields[finishedAtField] = run.FinishedAt.Format(time.RFC3339Nano)
 	fields[scheduledForField] = run.ScheduledFor.Format(time.RFC3339)
 	fields[requestedAtField] = run.RequestedAt.Format(time.RFC3339)
+	if run.RetryOf.Valid() {
+		fields[retryOfField] = run.RetryOf.String()
+		fields[attemptField] = int64(run.Attempt)
+	}
+	if run.TaskVersion > 0 {
+		fields[taskVersionField] = int64(run.TaskVersion)
+	}
 
 	startedAt := run.StartedAt
 	if startedAt.IsZero() {
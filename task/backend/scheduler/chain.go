@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// chainRetention is how long the outcome of a run is kept after a later run of the same ID finished.
+const chainRetention = 24 * time.Hour
+
+// Upstreamer is implemented by a Schedulable whose runs wait on the runs of other Schedulables.
+type Upstreamer interface {
+	// Upstreams returns the IDs of the Schedulables whose runs for the same scheduled time
+	// must succeed before a run of this Schedulable executes.
+	Upstreams() []ID
+}
+
+// ErrUpstreamFailed is returned when a run is blocked because the run of an upstream for the
+// same scheduled time did not succeed.
+type ErrUpstreamFailed struct {
+	Upstream ID
+}
+
+func (e *ErrUpstreamFailed) Error() string {
+	return fmt.Sprintf("run of upstream task %016x for the same scheduled time did not succeed", uint64(e.Upstream))
+}
+
+// Chain tracks the upstreams of the scheduled IDs and the outcome of their runs,
+// so that a run only executes after the runs of its upstreams for the same scheduled time succeeded.
+//
+// An upstream does not hold back a run when it is not scheduled,
+// or when it finished a later run without a run for the same scheduled time.
+type Chain struct {
+	mu        sync.Mutex
+	upstreams map[ID][]ID     // the scheduled IDs and their upstreams
+	outcomes  map[ID]outcomes // the outcome of the latest runs of the scheduled IDs
+	changed   chan struct{}   // closed when an outcome is added or the scheduled IDs change
+}
+
+// outcomes are the outcomes of the runs of an ID by their scheduled time.
+type outcomes struct {
+	latest    int64
+	succeeded map[int64]bool
+}
+
+// NewChain returns an empty Chain.
+func NewChain() *Chain {
+	return &Chain{
+		upstreams: make(map[ID][]ID),
+		outcomes:  make(map[ID]outcomes),
+		changed:   make(chan struct{}),
+	}
+}
+
+// schedule sets the upstreams of a scheduled ID.
+func (c *Chain) schedule(id ID, upstreams []ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.upstreams[id] = append([]ID(nil), upstreams...)
+	c.notify()
+}
+
+// release forgets an ID that is no longer scheduled.
+func (c *Chain) release(id ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.upstreams, id)
+	delete(c.outcomes, id)
+	c.notify()
+}
+
+// notify wakes up the waiting runs. The caller must hold c.mu.
+func (c *Chain) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// HasUpstreams reports whether the runs of id wait on the runs of upstreams.
+func (c *Chain) HasUpstreams(id ID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.upstreams[id]) > 0
+}
+
+// RunFinished records the outcome of the run of id for scheduledFor.
+func (c *Chain) RunFinished(id ID, scheduledFor time.Time, succeeded bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.upstreams[id]; !ok {
+		// nothing waits on the runs of an ID that is not scheduled.
+		return
+	}
+
+	sf := scheduledFor.UTC().Unix()
+	o, ok := c.outcomes[id]
+	if !ok {
+		o.succeeded = make(map[int64]bool)
+	}
+	o.succeeded[sf] = succeeded
+	if sf > o.latest {
+		o.latest = sf
+		for t := range o.succeeded {
+			if o.latest-t > int64(chainRetention/time.Second) {
+				delete(o.succeeded, t)
+			}
+		}
+	}
+	c.outcomes[id] = o
+	c.notify()
+}
+
+// Wait blocks until the runs of the upstreams of id for scheduledFor finished.
+// It returns an *ErrUpstreamFailed if one of them did not succeed,
+// or the error of ctx if it is done before.
+func (c *Chain) Wait(ctx context.Context, id ID, scheduledFor time.Time) error {
+	sf := scheduledFor.UTC().Unix()
+	for {
+		c.mu.Lock()
+		done, err := c.check(id, sf)
+		changed := c.changed
+		c.mu.Unlock()
+		if done {
+			return err
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// check reports whether the upstreams of id no longer hold back its run for sf.
+// The caller must hold c.mu.
+func (c *Chain) check(id ID, sf int64) (bool, error) {
+	done := true
+	for _, up := range c.upstreams[id] {
+		if _, ok := c.upstreams[up]; !ok {
+			continue
+		}
+		o := c.outcomes[up]
+		if succeeded, ok := o.succeeded[sf]; ok {
+			if !succeeded {
+				return true, &ErrUpstreamFailed{Upstream: up}
+			}
+			continue
+		}
+		if o.latest <= sf {
+			done = false
+		}
+	}
+	return done, nil
+}
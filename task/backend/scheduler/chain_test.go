@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChain_Wait(t *testing.T) {
+	sf := time.Date(2019, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewChain()
+	c.schedule(1, nil)
+	c.schedule(2, nil)
+	c.schedule(3, []ID{1, 2})
+
+	if !c.HasUpstreams(3) || c.HasUpstreams(1) {
+		t.Fatal("unexpected upstreams")
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- c.Wait(context.Background(), 3, sf)
+	}()
+
+	c.RunFinished(1, sf, true)
+	select {
+	case err := <-errc:
+		t.Fatalf("expected to wait for all upstreams, got %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	c.RunFinished(2, sf, true)
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected wait to finish once all upstreams succeeded")
+	}
+}
+
+func TestChain_WaitUpstreamFailed(t *testing.T) {
+	sf := time.Date(2019, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewChain()
+	c.schedule(1, nil)
+	c.schedule(2, []ID{1})
+	c.schedule(3, []ID{2})
+
+	c.RunFinished(1, sf, false)
+	err := c.Wait(context.Background(), 2, sf)
+	if e, ok := err.(*ErrUpstreamFailed); !ok || e.Upstream != 1 {
+		t.Fatalf("expected upstream 1 to fail, got %v", err)
+	}
+
+	// a blocked run fails the runs depending on it.
+	c.RunFinished(2, sf, false)
+	err = c.Wait(context.Background(), 3, sf)
+	if e, ok := err.(*ErrUpstreamFailed); !ok || e.Upstream != 2 {
+		t.Fatalf("expected upstream 2 to fail, got %v", err)
+	}
+}
+
+func TestChain_WaitNotHeldBack(t *testing.T) {
+	sf := time.Date(2019, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewChain()
+	c.schedule(1, nil)
+	c.schedule(2, []ID{1, 3})
+
+	// 3 is not scheduled, and 1 finished a later run without a run for sf.
+	c.RunFinished(1, sf.Add(time.Minute), true)
+	if err := c.Wait(context.Background(), 2, sf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// a released upstream no longer holds back a waiting run.
+	errc := make(chan error, 1)
+	go func() {
+		errc <- c.Wait(context.Background(), 2, sf.Add(2*time.Minute))
+	}()
+	c.release(1)
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected wait to finish once the upstream was released")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.schedule(1, nil)
+	if err := c.Wait(ctx, 2, sf.Add(3*time.Minute)); err != context.Canceled {
+		t.Fatalf("expected canceled wait, got %v", err)
+	}
+}
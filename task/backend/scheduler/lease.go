@@ -0,0 +1,440 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/cespare/xxhash"
+)
+
+const (
+	// DefaultLeasePartitions is the default number of partitions the Schedulables are divided in among instances.
+	DefaultLeasePartitions = 64
+
+	// DefaultLeaseDuration is the default time a lease lasts without being renewed.
+	DefaultLeaseDuration = 30 * time.Second
+)
+
+// Lease is the ownership of a partition of the Schedulables by a scheduler instance.
+type Lease struct {
+	Partition int
+	// Owner is the instance holding the lease, empty once the lease was released.
+	Owner   string
+	Expires time.Time
+}
+
+// Expired returns true if the lease doesn't hold at now.
+func (l Lease) Expired(now time.Time) bool {
+	return l.Owner == "" || !now.Before(l.Expires)
+}
+
+// LeaseStore stores the instances and the leases shared by the schedulers of several processes.
+// Its methods must be atomic, it is the only coordination between the instances.
+type LeaseStore interface {
+	// Heartbeat records that instance is alive until expires and returns the instances alive at now.
+	Heartbeat(ctx context.Context, instance string, now, expires time.Time) ([]string, error)
+
+	// Leases returns the leases of the partitions that were ever acquired.
+	Leases(ctx context.Context) ([]Lease, error)
+
+	// AcquireLease makes instance the owner of partition until expires, if the lease of the partition
+	// is expired at now or already owned by instance.
+	// It returns the lease before it was acquired and whether it was acquired.
+	AcquireLease(ctx context.Context, partition int, instance string, now, expires time.Time) (Lease, bool, error)
+
+	// ReleaseLease releases the lease of instance on partition, if it holds it.
+	ReleaseLease(ctx context.Context, partition int, instance string) error
+
+	// ClaimRun claims the run of id scheduled for scheduledFor for instance, which must hold the lease of partition at now.
+	// A run is claimed at most once, and no run scheduled before the last run claimed for id can be claimed.
+	ClaimRun(ctx context.Context, id ID, scheduledFor time.Time, partition int, instance string, now time.Time) (bool, error)
+}
+
+// SchedulableLister lists all the Schedulables that should be scheduled by one of the instances.
+type SchedulableLister interface {
+	ListSchedulables(ctx context.Context) ([]Schedulable, error)
+}
+
+// ResumeFunc resumes the runs of id left unfinished by an instance that died.
+type ResumeFunc func(ctx context.Context, id ID) error
+
+// LeasedScheduler schedules the Schedulables of the partitions it holds a lease on, so that several instances
+// sharing a LeaseStore divide the Schedulables among themselves.
+//
+// A LeasedScheduler wraps a Scheduler, for which it also is the Executor and the SchedulableService.
+// Every heartbeat it renews its leases, acquires or releases leases to hold its share of the partitions
+// among the live instances, and schedules on the wrapped Scheduler the Schedulables listed in its partitions.
+// A run is only executed once it is claimed in the LeaseStore, so a run is never executed twice for a scheduledFor,
+// even while an instance that lost its lease has yet to notice.
+//
+// The partitions of an instance that dies are acquired by the other instances once its leases expire,
+// and the runs it left unfinished are resumed with the ResumeFunc.
+type LeasedScheduler struct {
+	mu        sync.Mutex
+	owned     map[int]bool
+	scheduled map[ID]Schedulable
+
+	sch          Scheduler
+	store        LeaseStore
+	lister       SchedulableLister
+	executor     Executor
+	checkpointer SchedulableService
+	instance     string
+
+	partitions    int
+	leaseDuration time.Duration
+	time          clock.Clock
+	onErr         ErrorFunc
+	resume        ResumeFunc
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+type leasedSchedulerOptFunc func(s *LeasedScheduler) error
+
+// WithLeasePartitions is an option that sets the number of partitions the Schedulables are divided in.
+// All the instances sharing a LeaseStore must use the same number of partitions.
+func WithLeasePartitions(n int) leasedSchedulerOptFunc {
+	return func(s *LeasedScheduler) error {
+		if n < 1 {
+			return errors.New("the number of lease partitions must be positive")
+		}
+		s.partitions = n
+		return nil
+	}
+}
+
+// WithLeaseDuration is an option that sets how long leases last, they are renewed every third of it.
+func WithLeaseDuration(d time.Duration) leasedSchedulerOptFunc {
+	return func(s *LeasedScheduler) error {
+		if d <= 0 {
+			return errors.New("the lease duration must be positive")
+		}
+		s.leaseDuration = d
+		return nil
+	}
+}
+
+// WithLeaseTime is an option that allows you to inject a clock.Clock, for testing purposes.
+func WithLeaseTime(t clock.Clock) leasedSchedulerOptFunc {
+	return func(s *LeasedScheduler) error {
+		s.time = t
+		return nil
+	}
+}
+
+// WithLeaseOnErrorFn is an option that sets the function called with the errors of the heartbeats.
+func WithLeaseOnErrorFn(fn ErrorFunc) leasedSchedulerOptFunc {
+	return func(s *LeasedScheduler) error {
+		s.onErr = fn
+		return nil
+	}
+}
+
+// WithResumeFn is an option that sets the function resuming the runs of the Schedulables
+// taken over from an instance that died.
+func WithResumeFn(fn ResumeFunc) leasedSchedulerOptFunc {
+	return func(s *LeasedScheduler) error {
+		s.resume = fn
+		return nil
+	}
+}
+
+// NewLeasedScheduler returns a LeasedScheduler for instance, which must be unique among the instances sharing store.
+// The runs it claims are executed by executor and checkpointed by checkpointer.
+// The Scheduler it wraps must be set with SetScheduler before it is started.
+func NewLeasedScheduler(instance string, store LeaseStore, lister SchedulableLister, executor Executor, checkpointer SchedulableService, opts ...leasedSchedulerOptFunc) (*LeasedScheduler, error) {
+	s := &LeasedScheduler{
+		owned:         map[int]bool{},
+		scheduled:     map[ID]Schedulable{},
+		store:         store,
+		lister:        lister,
+		executor:      executor,
+		checkpointer:  checkpointer,
+		instance:      instance,
+		partitions:    DefaultLeasePartitions,
+		leaseDuration: DefaultLeaseDuration,
+		time:          clock.New(),
+		onErr:         func(_ context.Context, _ ID, _ time.Time, _ error) {},
+		resume:        func(_ context.Context, _ ID) error { return nil },
+		done:          make(chan struct{}),
+	}
+
+	if instance == "" {
+		return nil, errors.New("instance must not be empty")
+	}
+	for i := range opts {
+		if err := opts[i](s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// SetScheduler sets the Scheduler the leased Schedulables are scheduled on.
+func (s *LeasedScheduler) SetScheduler(sch Scheduler) {
+	s.sch = sch
+}
+
+// Start starts the heartbeats of the scheduler, the first one happens right away.
+func (s *LeasedScheduler) Start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := s.time.Ticker(s.leaseDuration / 3)
+		defer ticker.Stop()
+		for {
+			if err := s.Heartbeat(ctx); err != nil {
+				s.onErr(ctx, 0, s.time.Now(), err)
+			}
+			select {
+			case <-s.done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the heartbeats and releases the leases of the scheduler, so that other instances take over right away.
+func (s *LeasedScheduler) Stop(ctx context.Context) error {
+	close(s.done)
+	s.wg.Wait()
+
+	s.mu.Lock()
+	owned := s.owned
+	s.owned = map[int]bool{}
+	s.mu.Unlock()
+
+	s.sync(nil, map[int]bool{})
+	for p := range owned {
+		if err := s.store.ReleaseLease(ctx, p, s.instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Heartbeat renews the leases of the scheduler, balances the partitions among the live instances
+// and schedules the Schedulables of the partitions the scheduler owns.
+// It is called periodically once the scheduler is started.
+func (s *LeasedScheduler) Heartbeat(ctx context.Context) error {
+	now := s.time.Now().UTC()
+	expires := now.Add(s.leaseDuration)
+
+	instances, err := s.store.Heartbeat(ctx, s.instance, now, expires)
+	if err != nil {
+		return err
+	}
+	leases, err := s.store.Leases(ctx)
+	if err != nil {
+		return err
+	}
+
+	// every instance aims at an even share of the partitions.
+	share := s.partitions / len(instances)
+	if s.partitions%len(instances) != 0 {
+		share++
+	}
+
+	current := make(map[int]Lease, s.partitions)
+	for _, l := range leases {
+		current[l.Partition] = l
+	}
+
+	owned := map[int]bool{}
+	for p := 0; p < s.partitions; p++ {
+		if l, ok := current[p]; ok && l.Owner == s.instance && !l.Expired(now) {
+			owned[p] = true
+		}
+	}
+
+	// release the partitions above the share, so that new instances get theirs.
+	for p := s.partitions - 1; p >= 0 && len(owned) > share; p-- {
+		if !owned[p] {
+			continue
+		}
+		delete(owned, p)
+		s.releasePartition(p)
+		if err := s.store.ReleaseLease(ctx, p, s.instance); err != nil {
+			return err
+		}
+	}
+
+	takenOver := map[int]bool{}
+	for p := 0; p < s.partitions; p++ {
+		if owned[p] {
+			if _, ok, err := s.store.AcquireLease(ctx, p, s.instance, now, expires); err != nil {
+				return err
+			} else if !ok {
+				delete(owned, p)
+			}
+			continue
+		}
+		if len(owned) >= share {
+			continue
+		}
+		if l, ok := current[p]; ok && !l.Expired(now) {
+			continue
+		}
+
+		prev, ok, err := s.store.AcquireLease(ctx, p, s.instance, now, expires)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		owned[p] = true
+		// a partition that was released cleanly has no runs left behind.
+		if prev.Owner != "" || prev.Expires.IsZero() {
+			takenOver[p] = true
+		}
+	}
+
+	schs, err := s.lister.ListSchedulables(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.owned = owned
+	s.mu.Unlock()
+
+	for _, id := range s.sync(schs, owned) {
+		if !takenOver[s.partition(id)] {
+			continue
+		}
+		if err := s.resume(ctx, id); err != nil {
+			s.onErr(ctx, id, now, err)
+		}
+	}
+	return nil
+}
+
+// sync schedules the Schedulables of schs in the owned partitions and releases the others.
+// It returns the IDs of the Schedulables it scheduled for the first time.
+func (s *LeasedScheduler) sync(schs []Schedulable, owned map[int]bool) []ID {
+	listed := make(map[ID]Schedulable, len(schs))
+	for _, sch := range schs {
+		if owned[s.partition(sch.ID())] {
+			listed[sch.ID()] = sch
+		}
+	}
+
+	var added, toRelease []ID
+	var toSchedule []Schedulable
+	s.mu.Lock()
+	for id := range s.scheduled {
+		if _, ok := listed[id]; !ok {
+			toRelease = append(toRelease, id)
+			delete(s.scheduled, id)
+		}
+	}
+	for id, sch := range listed {
+		prev, ok := s.scheduled[id]
+		if !ok {
+			added = append(added, id)
+		}
+		if !ok || !prev.Schedule().Equal(sch.Schedule()) || prev.Offset() != sch.Offset() {
+			toSchedule = append(toSchedule, sch)
+			s.scheduled[id] = sch
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range toRelease {
+		if err := s.sch.Release(id); err != nil {
+			s.onErr(context.Background(), id, time.Time{}, err)
+		}
+	}
+	for _, sch := range toSchedule {
+		if err := s.sch.Schedule(sch); err != nil {
+			s.onErr(context.Background(), sch.ID(), time.Time{}, err)
+		}
+	}
+
+	sort.Slice(added, func(i, j int) bool { return added[i] < added[j] })
+	return added
+}
+
+// releasePartition releases the Schedulables of partition p from the wrapped Scheduler.
+func (s *LeasedScheduler) releasePartition(p int) {
+	var ids []ID
+	s.mu.Lock()
+	for id := range s.scheduled {
+		if s.partition(id) == p {
+			ids = append(ids, id)
+			delete(s.scheduled, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range ids {
+		if err := s.sch.Release(id); err != nil {
+			s.onErr(context.Background(), id, time.Time{}, err)
+		}
+	}
+}
+
+func (s *LeasedScheduler) partition(id ID) int {
+	buf := [8]byte{}
+	binary.LittleEndian.PutUint64(buf[:], uint64(id))
+	return int(xxhash.Sum64(buf[:]) % uint64(s.partitions))
+}
+
+func (s *LeasedScheduler) owns(id ID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.owned[s.partition(id)]
+}
+
+// Schedule schedules sch on the wrapped Scheduler if the scheduler holds the lease of its partition.
+// The other Schedulables are scheduled by the instances holding the leases of their partitions.
+func (s *LeasedScheduler) Schedule(sch Schedulable) error {
+	if !s.owns(sch.ID()) {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.scheduled[sch.ID()] = sch
+	s.mu.Unlock()
+	return s.sch.Schedule(sch)
+}
+
+// Release removes the specified Schedulable from the wrapped Scheduler.
+func (s *LeasedScheduler) Release(id ID) error {
+	s.mu.Lock()
+	delete(s.scheduled, id)
+	s.mu.Unlock()
+	return s.sch.Release(id)
+}
+
+// Execute executes the run if the scheduler claims it, runs claimed by another instance are skipped.
+func (s *LeasedScheduler) Execute(ctx context.Context, id ID, scheduledFor time.Time, runAt time.Time) error {
+	ok, err := s.store.ClaimRun(ctx, id, scheduledFor, s.partition(id), s.instance, s.time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	return s.executor.Execute(ctx, id, scheduledFor, runAt)
+}
+
+// UpdateLastScheduled checkpoints the Schedulable while the scheduler holds the lease of its partition.
+func (s *LeasedScheduler) UpdateLastScheduled(ctx context.Context, id ID, t time.Time) error {
+	if !s.owns(id) {
+		return nil
+	}
+	return s.checkpointer.UpdateLastScheduled(ctx, id, t)
+}
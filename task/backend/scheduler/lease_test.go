@@ -0,0 +1,213 @@
+package scheduler_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/task/backend/scheduler"
+	"go.uber.org/zap/zaptest"
+)
+
+type leaseSchedulable struct {
+	id  scheduler.ID
+	sch scheduler.Schedule
+	lsc time.Time
+}
+
+func (s leaseSchedulable) ID() scheduler.ID             { return s.id }
+func (s leaseSchedulable) Schedule() scheduler.Schedule { return s.sch }
+func (s leaseSchedulable) Offset() time.Duration        { return 0 }
+func (s leaseSchedulable) LastScheduled() time.Time     { return s.lsc }
+
+type leaseLister []scheduler.Schedulable
+
+func (l leaseLister) ListSchedulables(ctx context.Context) ([]scheduler.Schedulable, error) {
+	return l, nil
+}
+
+type leaseCheckpointer struct{}
+
+func (leaseCheckpointer) UpdateLastScheduled(context.Context, scheduler.ID, time.Time) error {
+	return nil
+}
+
+type leaseRun struct {
+	id           scheduler.ID
+	scheduledFor time.Time
+}
+
+// leaseExecutor records the runs executed by all the instances.
+type leaseExecutor struct {
+	mu   sync.Mutex
+	runs map[leaseRun][]string
+}
+
+func (e *leaseExecutor) instance(name string) scheduler.Executor {
+	return leaseExecutorFunc(func(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		r := leaseRun{id: id, scheduledFor: scheduledFor}
+		e.runs[r] = append(e.runs[r], name)
+		return nil
+	})
+}
+
+// executedBy returns the instances that executed the runs of the Schedulables scheduled from after.
+// It fails t if a run was executed more than once.
+func (e *leaseExecutor) executedBy(t *testing.T, after time.Time) map[scheduler.ID]map[string]bool {
+	t.Helper()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	by := map[scheduler.ID]map[string]bool{}
+	for r, instances := range e.runs {
+		if len(instances) > 1 {
+			t.Errorf("run of %d scheduled for %v executed by %v", r.id, r.scheduledFor, instances)
+		}
+		if r.scheduledFor.Before(after) {
+			continue
+		}
+		if by[r.id] == nil {
+			by[r.id] = map[string]bool{}
+		}
+		for _, i := range instances {
+			by[r.id][i] = true
+		}
+	}
+	return by
+}
+
+type leaseExecutorFunc func(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error
+
+func (f leaseExecutorFunc) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
+	return f(ctx, id, scheduledFor, runAt)
+}
+
+func TestLeasedScheduler(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the schedulers for several seconds")
+	}
+
+	ctx := context.Background()
+	store := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := store.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var lister leaseLister
+	for id := scheduler.ID(1); id <= 20; id++ {
+		sch, lsc, err := scheduler.NewSchedule("@every 1s", time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		lister = append(lister, leaseSchedulable{id: id, sch: sch, lsc: lsc})
+	}
+
+	var (
+		leaseClock = clock.NewMock()
+		ex         = &leaseExecutor{runs: map[leaseRun][]string{}}
+		names      = []string{"a", "b", "c"}
+		leased     = map[string]*scheduler.LeasedScheduler{}
+
+		mu      sync.Mutex
+		resumed = map[scheduler.ID]string{}
+	)
+	leaseClock.Set(time.Now())
+	for _, name := range names {
+		name := name
+		ls, err := scheduler.NewLeasedScheduler(name, store, lister, ex.instance(name), leaseCheckpointer{},
+			scheduler.WithLeasePartitions(8),
+			scheduler.WithLeaseDuration(time.Minute),
+			scheduler.WithLeaseTime(leaseClock),
+			scheduler.WithResumeFn(func(ctx context.Context, id scheduler.ID) error {
+				mu.Lock()
+				defer mu.Unlock()
+				resumed[id] = name
+				return nil
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sch, _, err := scheduler.NewScheduler(ls, ls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sch.Stop()
+		ls.SetScheduler(sch)
+		leased[name] = ls
+	}
+
+	heartbeat := func(names ...string) {
+		t.Helper()
+		// the instances converge to their shares in a few rounds.
+		for i := 0; i < 3; i++ {
+			for _, name := range names {
+				if err := leased[name].Heartbeat(ctx); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	heartbeat(names...)
+	leases, err := store.Leases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owners := map[string]int{}
+	for _, l := range leases {
+		owners[l.Owner]++
+	}
+	if len(leases) != 8 || owners["a"] < 2 || owners["b"] < 2 || owners["c"] < 2 {
+		t.Fatalf("expected the partitions to be divided among the instances, got %v", owners)
+	}
+
+	// only the runs scheduled after the partitions were divided are checked.
+	start := time.Now().Truncate(time.Second).Add(time.Second)
+	time.Sleep(2500 * time.Millisecond)
+	by := ex.executedBy(t, start)
+	if len(by) != len(lister) {
+		t.Fatalf("expected the %d schedulables to be executed, got %d", len(lister), len(by))
+	}
+	for id, instances := range by {
+		if len(instances) != 1 {
+			t.Errorf("expected %d to be executed by a single instance, got %v", id, instances)
+		}
+	}
+
+	// c dies, its scheduler keeps going but its leases expire.
+	var ofC []scheduler.ID
+	for id, instances := range by {
+		if instances["c"] {
+			ofC = append(ofC, id)
+		}
+	}
+	leaseClock.Add(2 * time.Minute)
+	heartbeat("a", "b")
+
+	start = time.Now().Truncate(time.Second).Add(time.Second)
+	time.Sleep(2500 * time.Millisecond)
+	by = ex.executedBy(t, start)
+	if len(by) != len(lister) {
+		t.Fatalf("expected the %d schedulables to be executed, got %d", len(lister), len(by))
+	}
+	for id, instances := range by {
+		if len(instances) != 1 || instances["c"] {
+			t.Errorf("expected %d to be executed by a single live instance, got %v", id, instances)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, id := range ofC {
+		if resumed[id] == "" || resumed[id] == "c" {
+			t.Errorf("expected the runs of %d to be resumed by a live instance, got %q", id, resumed[id])
+		}
+	}
+}
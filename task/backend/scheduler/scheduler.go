@@ -53,6 +53,14 @@ type SchedulableService interface {
 }
 
 func NewSchedule(unparsed string, lastScheduledAt time.Time) (Schedule, time.Time, error) {
+	return NewScheduleInLocation(unparsed, time.UTC, lastScheduledAt)
+}
+
+// NewScheduleInLocation is like NewSchedule, but evaluates cron expressions in the wall clock time of loc.
+// A wall clock time that is skipped or repeated by a daylight saving transition of loc is interpreted with the UTC
+// offset in effect before the transition: in a gap it triggers shifted by the length of the gap, in an overlap it
+// triggers only at its first occurrence. @every schedules are not affected by loc.
+func NewScheduleInLocation(unparsed string, loc *time.Location, lastScheduledAt time.Time) (Schedule, time.Time, error) {
 	lastScheduledAt = lastScheduledAt.UTC().Truncate(time.Second)
 	c, err := cron.ParseUTC(unparsed)
 	if err != nil {
@@ -68,31 +76,91 @@ func NewSchedule(unparsed string, lastScheduledAt time.Time) (Schedule, time.Tim
 		err := every.Parse(everyString)
 		if err != nil {
 			// We cannot align a invalid time
-			return Schedule{c}, lastScheduledAt, nil
+			return Schedule{cron: c}, lastScheduledAt, nil
 		}
 
 		// drop nanoseconds
 		lastScheduledAt = time.Unix(lastScheduledAt.UTC().Unix(), 0).UTC()
 		everyDur, err := every.DurationFrom(lastScheduledAt)
 		if err != nil {
-			return Schedule{c}, lastScheduledAt, nil
+			return Schedule{cron: c}, lastScheduledAt, nil
 		}
 
 		// and align
 		lastScheduledAt = lastScheduledAt.Truncate(everyDur).Truncate(time.Second)
+		return Schedule{cron: c}, lastScheduledAt, nil
 	}
 
-	return Schedule{c}, lastScheduledAt, err
+	if loc == nil || loc == time.UTC {
+		return Schedule{cron: c}, lastScheduledAt, nil
+	}
+	return Schedule{cron: c, loc: loc}, lastScheduledAt, nil
 }
 
 // Schedule is an object a valid schedule of runs
 type Schedule struct {
 	cron cron.Parsed
+
+	// loc is the location the cron expression is evaluated in, UTC when nil.
+	loc *time.Location
 }
 
 // Next returns the next time after from that a schedule should trigger on.
 func (s Schedule) Next(from time.Time) (time.Time, error) {
-	return cron.Parsed(s.cron).Next(from)
+	if s.loc == nil {
+		return cron.Parsed(s.cron).Next(from.UTC())
+	}
+
+	// evaluate the cron expression on the wall clock time, represented in UTC so it is not affected by transitions.
+	wall := wallClock(from.In(s.loc))
+	for {
+		next, err := cron.Parsed(s.cron).Next(wall)
+		if err != nil {
+			return time.Time{}, err
+		}
+		// the wall clock time of next can resolve to an earlier occurrence than from in an overlap,
+		// in that case it already triggered and is skipped.
+		if t := resolveWallClock(next, s.loc); t.After(from) {
+			return t, nil
+		}
+		wall = next
+	}
+}
+
+// Equal returns true if s and o trigger at the same times.
+func (s Schedule) Equal(o Schedule) bool {
+	if s.cron != o.cron {
+		return false
+	}
+	if s.loc == nil || o.loc == nil {
+		return s.loc == o.loc
+	}
+	return s.loc.String() == o.loc.String()
+}
+
+// wallClock returns the wall clock time of t, in UTC.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// resolveWallClock returns the time of the wall clock time wall, represented in UTC, in loc.
+// A wall clock time in a gap or overlap of loc is interpreted with the UTC offset in effect before the transition.
+func resolveWallClock(wall time.Time, loc *time.Location) time.Time {
+	// transitions are far enough apart that the offset a day earlier is the offset before any transition at wall.
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	t := wall.Add(-time.Duration(before) * time.Second)
+	if wallClock(t.In(loc)).Equal(wall) {
+		return t
+	}
+
+	// a transition happened in the day before wall.
+	_, after := t.In(loc).Zone()
+	if t2 := wall.Add(-time.Duration(after) * time.Second); wallClock(t2.In(loc)).Equal(wall) {
+		return t2
+	}
+
+	// wall is in a gap, t is shifted by the length of the gap.
+	return t
 }
 
 // ValidSchedule returns an error if the cron string is invalid.
@@ -666,3 +666,79 @@ func TestNewSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestSchedule_NextInLocation(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	tests := []struct {
+		name     string
+		unparsed string
+		from     time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "daily in local time",
+			unparsed: "0 9 * * *",
+			from:     time.Date(2019, 3, 8, 12, 0, 0, 0, ny),
+			want: []time.Time{
+				time.Date(2019, 3, 9, 14, 0, 0, 0, time.UTC),  // 09:00 EST
+				time.Date(2019, 3, 10, 13, 0, 0, 0, time.UTC), // 09:00 EDT
+				time.Date(2019, 3, 11, 13, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "gap is shifted by its length",
+			unparsed: "30 2 * * *",
+			from:     time.Date(2019, 3, 9, 12, 0, 0, 0, ny),
+			want: []time.Time{
+				time.Date(2019, 3, 10, 7, 30, 0, 0, time.UTC), // 03:30 EDT
+				time.Date(2019, 3, 11, 6, 30, 0, 0, time.UTC), // 02:30 EDT
+			},
+		},
+		{
+			name:     "overlap triggers on the first occurrence only",
+			unparsed: "30 * * * *",
+			from:     time.Date(2019, 11, 3, 0, 0, 0, 0, ny),
+			want: []time.Time{
+				time.Date(2019, 11, 3, 4, 30, 0, 0, time.UTC), // 00:30 EDT
+				time.Date(2019, 11, 3, 5, 30, 0, 0, time.UTC), // 01:30 EDT
+				time.Date(2019, 11, 3, 7, 30, 0, 0, time.UTC), // 02:30 EST
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch, _, err := NewScheduleInLocation(tt.unparsed, ny, tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from := tt.from
+			for _, want := range tt.want {
+				next, err := sch.Next(from)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !next.Equal(want) {
+					t.Fatalf("Next(%s) = %s, want %s", from, next.UTC(), want)
+				}
+				from = next
+			}
+		})
+	}
+
+	// a time in the second occurrence of the overlap does not trigger the repeated wall clock time again.
+	sch, _, err := NewScheduleInLocation("45 1 * * *", ny, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := sch.Next(time.Date(2019, 11, 3, 6, 30, 0, 0, time.UTC)) // 01:30 EST
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2019, 11, 4, 6, 45, 0, 0, time.UTC); !next.Equal(want) {
+		t.Fatalf("Next() = %s, want %s", next.UTC(), want)
+	}
+}
@@ -73,6 +73,7 @@ type TreeScheduler struct {
 	workchans     []chan Item
 	wg            sync.WaitGroup
 	checkpointer  SchedulableService
+	chain         *Chain
 
 	sm *SchedulerMetrics
 }
@@ -107,6 +108,15 @@ func WithTime(t clock.Clock) treeSchedulerOptFunc {
 	}
 }
 
+// WithChain is an option that makes a TreeScheduler track the upstreams of the Schedulables it schedules in c.
+// Schedulables declare their upstreams by implementing Upstreamer.
+func WithChain(c *Chain) treeSchedulerOptFunc {
+	return func(t *TreeScheduler) error {
+		t.chain = c
+		return nil
+	}
+}
+
 // NewScheduler gives us a new TreeScheduler and SchedulerMetrics when given an  Executor, a SchedulableService, and zero or more options.
 // Schedulers should be initialized with this function.
 func NewScheduler(executor Executor, checkpointer SchedulableService, opts ...treeSchedulerOptFunc) (*TreeScheduler, *SchedulerMetrics, error) {
@@ -294,6 +304,9 @@ func (s *TreeScheduler) Release(taskID ID) error {
 	s.mu.Lock()
 	s.release(taskID)
 	s.mu.Unlock()
+	if s.chain != nil {
+		s.chain.release(taskID)
+	}
 	return nil
 }
 
@@ -348,6 +361,14 @@ func (s *TreeScheduler) Schedule(sch Schedulable) error {
 	it.next = nt.UTC().Unix()
 	it.when = it.next + it.Offset
 
+	if s.chain != nil {
+		var upstreams []ID
+		if u, ok := sch.(Upstreamer); ok {
+			upstreams = u.Upstreams()
+		}
+		s.chain.schedule(it.id, upstreams)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -15,6 +15,9 @@ type TaskControlService interface {
 	// CreateRun creates a run with a scheduled for time.
 	CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error)
 
+	// CreateRetryRun creates a run that retries the failed run at runAt, for the same scheduled for time.
+	CreateRetryRun(ctx context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error)
+
 	CurrentlyRunning(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error)
 	ManualRuns(ctx context.Context, taskID influxdb.ID) ([]*influxdb.Run, error)
 
@@ -48,6 +51,7 @@ const (
 	RunFail
 	RunCanceled
 	RunScheduled
+	RunBlocked
 )
 
 func (r RunStatus) String() string {
@@ -62,6 +66,8 @@ func (r RunStatus) String() string {
 		return "canceled"
 	case RunScheduled:
 		return "scheduled"
+	case RunBlocked:
+		return "blocked"
 	}
 	panic(fmt.Sprintf("unknown RunStatus: %d", r))
 }
@@ -73,6 +73,31 @@ func (t *TaskControlService) CreateRun(_ context.Context, taskID influxdb.ID, sc
 	return runs[runID], nil
 }
 
+func (t *TaskControlService) CreateRetryRun(_ context.Context, run *influxdb.Run, runAt time.Time) (*influxdb.Run, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	retryOf := run.RetryOf
+	if !retryOf.Valid() {
+		retryOf = run.ID
+	}
+	runID := idgen.ID()
+	runs, ok := t.runs[run.TaskID]
+	if !ok {
+		runs = make(map[influxdb.ID]*influxdb.Run)
+	}
+	runs[runID] = &influxdb.Run{
+		ID:           runID,
+		TaskID:       run.TaskID,
+		ScheduledFor: run.ScheduledFor,
+		RunAt:        runAt,
+		RetryOf:      retryOf,
+		Attempt:      run.Attempt + 1,
+	}
+	t.runs[run.TaskID] = runs
+	return runs[runID], nil
+}
+
 func (t *TaskControlService) StartManualRun(_ context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -141,7 +166,7 @@ func (d *TaskControlService) UpdateRunState(ctx context.Context, taskID, runID i
 	switch state {
 	case backend.RunStarted:
 		run.StartedAt = when
-	case backend.RunSuccess, backend.RunFail, backend.RunCanceled:
+	case backend.RunSuccess, backend.RunFail, backend.RunCanceled, backend.RunBlocked:
 		run.FinishedAt = when
 	case backend.RunScheduled:
 		// nothing
@@ -4,6 +4,7 @@ package options
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +28,9 @@ type Options struct {
 	// Cron is a cron style time schedule that can be used in place of Every.
 	Cron string `json:"cron,omitempty"`
 
+	// Location is the IANA time zone name the Cron schedule is evaluated in, UTC when empty.
+	Location string `json:"location,omitempty"`
+
 	// Every represents a fixed period to repeat execution.
 	// this can be unmarshaled from json as a string i.e.: "1d" will unmarshal as 1 day
 	Every Duration `json:"every,omitempty"`
@@ -37,7 +41,22 @@ type Options struct {
 
 	Concurrency *int64 `json:"concurrency,omitempty"`
 
+	// Retry is the maximum number of attempts of a failed run, including the first one.
 	Retry *int64 `json:"retry,omitempty"`
+
+	// Timeout is how long a run may execute before it is canceled and fails.
+	Timeout *Duration `json:"timeout,omitempty"`
+
+	// RetryDelay is the delay before the first retry of a failed run,
+	// the delay doubles for each following retry.
+	RetryDelay *Duration `json:"retryDelay,omitempty"`
+
+	// RetryMaxDelay is the maximum delay between two retries of a failed run.
+	RetryMaxDelay *Duration `json:"retryMaxDelay,omitempty"`
+
+	// After is the IDs of the upstream tasks whose runs must succeed
+	// before a run of this task for the same scheduled time executes.
+	After []string `json:"after,omitempty"`
 }
 
 // Duration is a time span that supports the same units as the flux parser's time duration, as well as negative length time spans.
@@ -123,30 +142,45 @@ func (a *Duration) Add(t time.Time) (time.Time, error) {
 func (o *Options) Clear() {
 	o.Name = ""
 	o.Cron = ""
+	o.Location = ""
 	o.Every = Duration{}
 	o.Offset = nil
 	o.Concurrency = nil
 	o.Retry = nil
+	o.Timeout = nil
+	o.RetryDelay = nil
+	o.RetryMaxDelay = nil
+	o.After = nil
 }
 
 // IsZero tells us if the options has been zeroed out.
 func (o *Options) IsZero() bool {
 	return o.Name == "" &&
 		o.Cron == "" &&
+		o.Location == "" &&
 		o.Every.IsZero() &&
 		(o.Offset == nil || o.Offset.IsZero()) &&
 		o.Concurrency == nil &&
-		o.Retry == nil
+		o.Retry == nil &&
+		o.Timeout == nil &&
+		o.RetryDelay == nil &&
+		o.RetryMaxDelay == nil &&
+		o.After == nil
 }
 
 // All the task option names we accept.
 const (
-	optName        = "name"
-	optCron        = "cron"
-	optEvery       = "every"
-	optOffset      = "offset"
-	optConcurrency = "concurrency"
-	optRetry       = "retry"
+	optName          = "name"
+	optCron          = "cron"
+	optLocation      = "location"
+	optEvery         = "every"
+	optOffset        = "offset"
+	optConcurrency   = "concurrency"
+	optRetry         = "retry"
+	optTimeout       = "timeout"
+	optRetryDelay    = "retryDelay"
+	optRetryMaxDelay = "retryMaxDelay"
+	optAfter         = "after"
 )
 
 // contains is a helper function to see if an array of strings contains a string
@@ -160,7 +194,7 @@ func contains(s []string, e string) bool {
 }
 
 func grabTaskOptionAST(p *ast.Package, keys ...string) map[string]ast.Expression {
-	res := make(map[string]ast.Expression, 2) // we preallocate two keys for the map, as that is how many we will use at maximum (offset and every)
+	res := make(map[string]ast.Expression, len(keys))
 	for i := range p.Files {
 		for j := range p.Files[i].Body {
 			if p.Files[i].Body[j].Type() != "OptionStatement" {
@@ -210,7 +244,7 @@ func FromScript(script string) (Options, error) {
 	if err != nil {
 		return opt, err
 	}
-	durTypes := grabTaskOptionAST(fluxAST, optEvery, optOffset)
+	durTypes := grabTaskOptionAST(fluxAST, optEvery, optOffset, optTimeout, optRetryDelay, optRetryMaxDelay)
 	// TODO(desa): should be dependencies.NewEmpty(), but for now we'll hack things together
 	ctx := newDeps().Inject(context.Background())
 	_, scope, err := flux.EvalAST(ctx, fluxAST)
@@ -258,6 +292,13 @@ func FromScript(script string) (Options, error) {
 		opt.Cron = crVal.Str()
 	}
 
+	if locVal, ok := optObject.Get(optLocation); ok {
+		if err := checkNature(locVal.PolyType().Nature(), semantic.String); err != nil {
+			return opt, err
+		}
+		opt.Location = locVal.Str()
+	}
+
 	if everyOK {
 		if err := checkNature(everyVal.PolyType().Nature(), semantic.Duration); err != nil {
 			return opt, err
@@ -313,6 +354,54 @@ func FromScript(script string) (Options, error) {
 		opt.Retry = pointer.Int64(retryVal.Int())
 	}
 
+	for _, d := range []struct {
+		name string
+		dst  **Duration
+	}{
+		{optTimeout, &opt.Timeout},
+		{optRetryDelay, &opt.RetryDelay},
+		{optRetryMaxDelay, &opt.RetryMaxDelay},
+	} {
+		val, ok := optObject.Get(d.name)
+		if !ok {
+			continue
+		}
+		if err := checkNature(val.PolyType().Nature(), semantic.Duration); err != nil {
+			return opt, err
+		}
+		dur, ok := durTypes[d.name]
+		if !ok || dur == nil {
+			return opt, ErrParseTaskOptionField(d.name)
+		}
+		durNode, err := parseSignedDuration(dur.Location().Source)
+		if err != nil {
+			return opt, err
+		}
+		durNode.BaseNode = ast.BaseNode{}
+		*d.dst = &Duration{Node: *durNode}
+	}
+
+	if afterVal, ok := optObject.Get(optAfter); ok {
+		if err := checkNature(afterVal.PolyType().Nature(), semantic.Array); err != nil {
+			return opt, err
+		}
+		arr := afterVal.Array()
+		opt.After = make([]string, 0, arr.Len())
+		var err error
+		arr.Range(func(i int, v values.Value) {
+			if err != nil {
+				return
+			}
+			if err = checkNature(v.PolyType().Nature(), semantic.String); err != nil {
+				return
+			}
+			opt.After = append(opt.After, v.Str())
+		})
+		if err != nil {
+			return opt, err
+		}
+	}
+
 	if err := opt.Validate(); err != nil {
 		return opt, err
 	}
@@ -338,6 +427,11 @@ func (o *Options) Validate() error {
 		if err != nil {
 			errs = append(errs, "cron invalid: "+err.Error())
 		}
+		if o.Location != "" {
+			if _, err := time.LoadLocation(o.Location); err != nil {
+				errs = append(errs, "location invalid: "+err.Error())
+			}
+		}
 	} else if everyPresent {
 		every, err := o.Every.DurationFrom(now)
 		if err != nil {
@@ -349,6 +443,9 @@ func (o *Options) Validate() error {
 			errs = append(errs, "every option must be expressible as whole seconds")
 		}
 	}
+	if o.Location != "" && !cronPresent {
+		errs = append(errs, "location option requires cron")
+	}
 	if o.Offset != nil {
 		offset, err := o.Offset.DurationFrom(now)
 		if err != nil {
@@ -373,6 +470,48 @@ func (o *Options) Validate() error {
 			errs = append(errs, fmt.Sprintf("retry exceeded max of %d", maxRetry))
 		}
 	}
+	durs := make(map[string]time.Duration, 3)
+	for _, d := range []struct {
+		name string
+		dur  *Duration
+	}{
+		{optTimeout, o.Timeout},
+		{optRetryDelay, o.RetryDelay},
+		{optRetryMaxDelay, o.RetryMaxDelay},
+	} {
+		if d.dur == nil {
+			continue
+		}
+		dur, err := d.dur.DurationFrom(now)
+		if err != nil {
+			return err
+		}
+		if dur < time.Second {
+			errs = append(errs, fmt.Sprintf("%s option must be at least 1 second", d.name))
+		} else if dur.Truncate(time.Second) != dur {
+			errs = append(errs, fmt.Sprintf("%s option must be expressible as whole seconds", d.name))
+		}
+		durs[d.name] = dur
+	}
+	if maxDelay, ok := durs[optRetryMaxDelay]; ok && maxDelay < durs[optRetryDelay] {
+		errs = append(errs, "retryMaxDelay option must not be less than retryDelay")
+	}
+
+	seen := make(map[string]bool, len(o.After))
+	for _, id := range o.After {
+		if len(id) != 16 {
+			errs = append(errs, fmt.Sprintf("after contains invalid task ID %q", id))
+			continue
+		}
+		if v, err := strconv.ParseUint(id, 16, 64); err != nil || v == 0 {
+			errs = append(errs, fmt.Sprintf("after contains invalid task ID %q", id))
+			continue
+		}
+		if seen[id] {
+			errs = append(errs, fmt.Sprintf("after contains task ID %q more than once", id))
+		}
+		seen[id] = true
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -414,7 +553,7 @@ func validateOptionNames(o values.Object) error {
 	var unexpected []string
 	o.Range(func(name string, _ values.Value) {
 		switch name {
-		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry:
+		case optName, optCron, optLocation, optEvery, optOffset, optConcurrency, optRetry, optTimeout, optRetryDelay, optRetryMaxDelay, optAfter:
 			// Known option. Nothing to do.
 		default:
 			unexpected = append(unexpected, name)
@@ -423,7 +562,7 @@ func validateOptionNames(o values.Object) error {
 
 	if len(unexpected) > 0 {
 		u := strings.Join(unexpected, ", ")
-		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry}, ", ")
+		v := strings.Join([]string{optName, optCron, optLocation, optEvery, optOffset, optConcurrency, optRetry, optTimeout, optRetryDelay, optRetryMaxDelay, optAfter}, ", ")
 		return fmt.Errorf("unknown task option(s): %s. valid options are %s", u, v)
 	}
 
@@ -3,6 +3,7 @@ package options_test
 import (
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -21,6 +22,9 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Cron != "" {
 		taskData = fmt.Sprintf("%s  cron: %q,\n", taskData, opt.Cron)
 	}
+	if opt.Location != "" {
+		taskData = fmt.Sprintf("%s  location: %q,\n", taskData, opt.Location)
+	}
 	if !opt.Every.IsZero() {
 		taskData = fmt.Sprintf("%s  every: %s,\n", taskData, opt.Every.String())
 	}
@@ -33,6 +37,22 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Retry != nil && *opt.Retry != 0 {
 		taskData = fmt.Sprintf("%s  retry: %d,\n", taskData, *opt.Retry)
 	}
+	if opt.Timeout != nil {
+		taskData = fmt.Sprintf("%s  timeout: %s,\n", taskData, opt.Timeout.String())
+	}
+	if opt.RetryDelay != nil {
+		taskData = fmt.Sprintf("%s  retryDelay: %s,\n", taskData, opt.RetryDelay.String())
+	}
+	if opt.RetryMaxDelay != nil {
+		taskData = fmt.Sprintf("%s  retryMaxDelay: %s,\n", taskData, opt.RetryMaxDelay.String())
+	}
+	if len(opt.After) > 0 {
+		after := make([]string, 0, len(opt.After))
+		for _, id := range opt.After {
+			after = append(after, strconv.Quote(id))
+		}
+		taskData = fmt.Sprintf("%s  after: [%s],\n", taskData, strings.Join(after, ", "))
+	}
 	if body == "" {
 		body = `from(bucket: "test")
     |> range(start:-1h)`
@@ -101,7 +121,17 @@ func TestFromScript(t *testing.T) {
 		},
 		{script: "option task = {name:\"test_task_smoke_name\", every:30s} from(bucket:\"test_tasks_smoke_bucket_source\") |> range(start: -1h) |> map(fn: (r) => ({r with _time: r._time, _value:r._value, t : \"quality_rocks\"}))|> to(bucket:\"test_tasks_smoke_bucket_dest\", orgID:\"3e73e749495d37d5\")",
 			exp: options.Options{Name: "test_task_smoke_name", Every: *(options.MustParseDuration("30s")), Retry: pointer.Int64(1), Concurrency: pointer.Int64(1)}, shouldErr: false}, // TODO(docmerlin): remove this once tasks fully supports all flux duration units.
-
+		{script: scriptGenerator(options.Options{Name: "name12", Every: *(options.MustParseDuration("1h")), After: []string{"0000000000000001", "000000000000000a"}}, ""),
+			exp: options.Options{Name: "name12", Every: *(options.MustParseDuration("1h")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1), After: []string{"0000000000000001", "000000000000000a"}}},
+		{script: scriptGenerator(options.Options{Name: "name13", Every: *(options.MustParseDuration("1h")), After: []string{"not an id"}}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), Retry: pointer.Int64(3), Timeout: options.MustParseDuration("10m"), RetryDelay: options.MustParseDuration("30s"), RetryMaxDelay: options.MustParseDuration("5m")}, ""),
+			exp: options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(3), Timeout: options.MustParseDuration("10m"), RetryDelay: options.MustParseDuration("30s"), RetryMaxDelay: options.MustParseDuration("5m")}},
+		{script: scriptGenerator(options.Options{Name: "name16", Every: *(options.MustParseDuration("1h")), RetryDelay: options.MustParseDuration("1m"), RetryMaxDelay: options.MustParseDuration("30s")}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name17", Cron: "0 9 * * 1-5", Location: "America/New_York"}, ""),
+			exp: options.Options{Name: "name17", Cron: "0 9 * * 1-5", Location: "America/New_York", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name18", Cron: "0 9 * * 1-5", Location: "Not/AZone"}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name19", Every: *(options.MustParseDuration("1h")), Location: "America/New_York"}, ""), shouldErr: true},
+		{script: "option task = {\n  name: \"name14\",\n  every: 1h,\n  after: [1, 2],\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 	} {
 		o, err := options.FromScript(c.script)
 		if c.shouldErr && err == nil {
@@ -146,7 +176,7 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 		t.Errorf("expected error to mention unrecognized options, but it said: %v", err)
 	}
 
-	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry"}
+	validOpts := []string{"name", "cron", "location", "every", "offset", "concurrency", "retry", "timeout", "retryDelay", "retryMaxDelay", "after"}
 	for _, o := range validOpts {
 		if !strings.Contains(msg, o) {
 			t.Errorf("expected error to mention valid option %q but it said: %v", o, err)
@@ -222,6 +252,30 @@ func TestValidate(t *testing.T) {
 		t.Error("expected error for retry too large")
 	}
 
+	*bad = good
+	bad.Timeout = options.MustParseDuration("500ms")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for timeout less than 1 second")
+	}
+
+	*bad = good
+	bad.RetryDelay = options.MustParseDuration("-1m")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for negative retry delay")
+	}
+
+	*bad = good
+	bad.After = []string{"0000000000000000"}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for invalid upstream task ID")
+	}
+
+	*bad = good
+	bad.After = []string{"0000000000000001", "0000000000000001"}
+	if err := bad.Validate(); err == nil {
+		t.Error("expected error for duplicate upstream task ID")
+	}
+
 	notbad := new(options.Options)
 	*notbad = good
 	notbad.Cron = ""
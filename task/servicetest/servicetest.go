@@ -98,6 +98,11 @@ func TestTaskService(t *testing.T, fn BackendComponentFactory, testCategory ...s
 					testTaskType(t, sys)
 				})
 
+				t.Run("Task Dependencies", func(t *testing.T) {
+					t.Parallel()
+					testTaskDependencies(t, sys)
+				})
+
 			})
 		case "analytical":
 			t.Run("AnalyticalTaskService", func(t *testing.T) {
@@ -109,6 +114,10 @@ func TestTaskService(t *testing.T, fn BackendComponentFactory, testCategory ...s
 					t.Parallel()
 					testRetryAcrossStorage(t, sys)
 				})
+				t.Run("Task Automatic Retry", func(t *testing.T) {
+					t.Parallel()
+					testAutomaticRetryAcrossStorage(t, sys)
+				})
 				t.Run("task Log Storage", func(t *testing.T) {
 					t.Parallel()
 					testLogsAcrossStorage(t, sys)
@@ -279,6 +288,7 @@ func testTaskCRUD(t *testing.T, sys *System) {
 		Status:          string(backend.DefaultTaskStatus),
 		Flux:            fmt.Sprintf(scriptFmt, 0),
 		Type:            influxdb.TaskSystemType,
+		Version:         1,
 	}
 	for fn, f := range found {
 		if diff := cmp.Diff(f, want); diff != "" {
@@ -612,6 +622,72 @@ from(bucket: "b")
 	}
 }
 
+// testTaskDependencies creates a chain of tasks and ensures dependency cycles are rejected.
+func testTaskDependencies(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+	authorizedCtx := icontext.SetAuthorizer(sys.Ctx, cr.Authorizer())
+
+	createTask := func(name string, after ...influxdb.ID) (*influxdb.Task, error) {
+		opt := fmt.Sprintf("name: %q, every: 1m", name)
+		if len(after) > 0 {
+			ids := make([]string, 0, len(after))
+			for _, id := range after {
+				ids = append(ids, fmt.Sprintf("%q", id.String()))
+			}
+			opt += fmt.Sprintf(", after: [%s]", strings.Join(ids, ", "))
+		}
+		return sys.TaskService.CreateTask(authorizedCtx, influxdb.TaskCreate{
+			OrganizationID: cr.OrgID,
+			OwnerID:        cr.UserID,
+			Flux: fmt.Sprintf(`option task = {%s}
+
+from(bucket: "b")
+	|> to(bucket: "two", orgID: "000000000000000")`, opt),
+		})
+	}
+
+	raw, err := createTask("raw-to-1m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rollup, err := createTask("1m-to-1h", raw.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []influxdb.ID{raw.ID}; !reflect.DeepEqual(rollup.After, exp) {
+		t.Fatalf("expected upstream tasks %v, got %v", exp, rollup.After)
+	}
+	found, err := sys.TaskService.FindTaskByID(sys.Ctx, rollup.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []influxdb.ID{raw.ID}; !reflect.DeepEqual(found.After, exp) {
+		t.Fatalf("expected stored upstream tasks %v, got %v", exp, found.After)
+	}
+
+	if _, err := createTask("missing-upstream", influxdb.ID(1)); err == nil {
+		t.Fatal("expected error creating task with a missing upstream task")
+	}
+
+	// raw-to-1m after 1m-to-1h would be a cycle.
+	_, err = sys.TaskService.UpdateTask(authorizedCtx, raw.ID, influxdb.TaskUpdate{Options: options.Options{After: []string{rollup.ID.String()}}})
+	if influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid error for dependency cycle, got %v", err)
+	}
+	_, err = sys.TaskService.UpdateTask(authorizedCtx, raw.ID, influxdb.TaskUpdate{Options: options.Options{After: []string{raw.ID.String()}}})
+	if influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected invalid error for task depending on itself, got %v", err)
+	}
+
+	updated, err := sys.TaskService.UpdateTask(authorizedCtx, rollup.ID, influxdb.TaskUpdate{Options: options.Options{After: []string{}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.After) != 0 {
+		t.Fatalf("expected upstream tasks to be removed, got %v", updated.After)
+	}
+}
+
 //Create a new task with a Cron and Offset option
 //Update the task to remove the Offset option, and change Cron to Every
 //Retrieve the task again to ensure the options are now Every, without Cron or Offset
@@ -697,6 +773,23 @@ from(bucket: "b")
 			t.Fatal("removing offset failed")
 		}
 	})
+	t.Run("update task cron with location", func(t *testing.T) {
+		f, err := sys.TaskService.UpdateTask(authorizedCtx, task.ID, influxdb.TaskUpdate{Options: options.Options{Cron: "0 9 * * *", Location: "America/New_York"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		savedTask, err := sys.TaskService.FindTaskByID(sys.Ctx, f.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if savedTask.Cron != "0 9 * * *" || savedTask.Location != "America/New_York" {
+			t.Fatalf("expected cron in America/New_York, got cron %q in %q", savedTask.Cron, savedTask.Location)
+		}
+
+		if _, err := sys.TaskService.UpdateTask(authorizedCtx, task.ID, influxdb.TaskUpdate{Options: options.Options{Location: "Not/AZone"}}); err == nil {
+			t.Fatal("expected an error for an unknown location")
+		}
+	})
 
 }
 
@@ -1559,6 +1652,92 @@ func testRetryAcrossStorage(t *testing.T, sys *System) {
 	}
 }
 
+func testAutomaticRetryAcrossStorage(t *testing.T, sys *System) {
+	cr := creds(t, sys)
+
+	ct := influxdb.TaskCreate{
+		OrganizationID: cr.OrgID,
+		Flux: `option task = {
+	name: "task #0",
+	every: 1m,
+	retry: 3,
+	timeout: 10m,
+	retryDelay: 30s,
+	retryMaxDelay: 5m,
+}
+
+from(bucket: "b")
+	|> to(bucket: "two", orgID: "000000000000000")`,
+		OwnerID: cr.UserID,
+	}
+	task, err := sys.TaskService.CreateTask(icontext.SetAuthorizer(sys.Ctx, cr.Authorizer()), ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Retry != 3 || task.Timeout != 10*time.Minute || task.RetryDelay != 30*time.Second || task.RetryMaxDelay != 5*time.Minute {
+		t.Fatalf("unexpected run options: retry %d, timeout %s, retryDelay %s, retryMaxDelay %s", task.Retry, task.Timeout, task.RetryDelay, task.RetryMaxDelay)
+	}
+
+	requestedAt := time.Now().Add(5 * time.Minute).UTC()
+	startedAt := time.Now().UTC()
+
+	// fail runs and retry them; normally the executor would do this.
+	fail := func(r *influxdb.Run) {
+		t.Helper()
+		started := startedAt.Add(-time.Duration(r.Attempt) * time.Minute)
+		if err := sys.TaskControlService.UpdateRunState(sys.Ctx, task.ID, r.ID, started, backend.RunStarted); err != nil {
+			t.Fatal(err)
+		}
+		if err := sys.TaskControlService.UpdateRunState(sys.Ctx, task.ID, r.ID, started.Add(time.Second), backend.RunFail); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sys.TaskControlService.FinishRun(sys.Ctx, task.ID, r.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rc, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fail(rc)
+
+	retry1, err := sys.TaskControlService.CreateRetryRun(sys.Ctx, rc, requestedAt.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry1.ID == rc.ID || retry1.RetryOf != rc.ID || retry1.Attempt != 1 || retry1.ScheduledFor != rc.ScheduledFor {
+		t.Fatalf("unexpected retry run %+v of run %+v", retry1, rc)
+	}
+	fail(retry1)
+
+	retry2, err := sys.TaskControlService.CreateRetryRun(sys.Ctx, retry1, requestedAt.Add(2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry2.RetryOf != rc.ID || retry2.Attempt != 2 {
+		t.Fatalf("expected second retry of run %s, got %+v", rc.ID, retry2)
+	}
+	fail(retry2)
+
+	// every attempt is a distinct run in the system bucket.
+	runs, _, err := sys.TaskService.FindRuns(sys.Ctx, influxdb.RunFilter{Task: task.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("expected 3 runs, got %d", len(runs))
+	}
+
+	r, err := sys.TaskService.FindRunByID(sys.Ctx, task.ID, retry2.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.RetryOf != rc.ID || r.Attempt != 2 {
+		t.Fatalf("expected stored retry of run %s, got %+v", rc.ID, r)
+	}
+}
+
 func testLogsAcrossStorage(t *testing.T, sys *System) {
 	cr := creds(t, sys)
 
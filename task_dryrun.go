@@ -0,0 +1,59 @@
+package influxdb
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MaxDryRunRecords is the maximum number of records of all the tables captured by a dry run.
+const MaxDryRunRecords = 10000
+
+// TaskDryRunRequest is a request to execute a task script once without side effects.
+type TaskDryRunRequest struct {
+	Flux           string    `json:"flux"`
+	OrganizationID ID        `json:"orgID,omitempty"`
+	Organization   string    `json:"org,omitempty"`
+	ScheduledFor   time.Time `json:"scheduledFor,omitempty"` // ScheduledFor is the now time of the query, the current time when zero
+}
+
+// Validate returns an error if the dry run cannot be executed.
+func (r TaskDryRunRequest) Validate() error {
+	switch {
+	case r.Flux == "":
+		return errors.New("missing flux")
+	case !r.OrganizationID.Valid() && r.Organization == "":
+		return errors.New("missing orgID and org")
+	}
+	return nil
+}
+
+// TaskDryRun is the outcome of a dry run of a task script.
+// The tables the script would have written are captured instead of written.
+type TaskDryRun struct {
+	ScheduledFor time.Time     `json:"scheduledFor"`
+	Tables       []DryRunTable `json:"tables"`
+	Truncated    bool          `json:"truncated,omitempty"` // Truncated is set when records beyond MaxDryRunRecords were dropped
+	Log          []Log         `json:"log"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// DryRunTable is a table captured by a dry run in place of a sink of the script.
+type DryRunTable struct {
+	Sink    string          `json:"sink"` // Sink is the call of the sink the table was sent to
+	Columns []DryRunColumn  `json:"columns"`
+	Records [][]interface{} `json:"records"`
+}
+
+// DryRunColumn is a column of a table captured by a dry run.
+type DryRunColumn struct {
+	Label string `json:"label"`
+	Type  string `json:"type"`
+}
+
+// TaskDryRunService executes task scripts without side effects.
+type TaskDryRunService interface {
+	// DryRunTask executes a task script once with the authorization on ctx,
+	// returning the tables its sinks would have written.
+	DryRunTask(ctx context.Context, req TaskDryRunRequest) (*TaskDryRun, error)
+}
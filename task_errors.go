@@ -2,6 +2,7 @@ package influxdb
 
 import (
 	"fmt"
+	"time"
 )
 
 var (
@@ -83,8 +84,32 @@ var (
 		Code: EInvalid,
 		Msg:  "cannot create task with invalid ownerID",
 	}
+
+	// ErrTaskDependencyCycle is returned when the upstream tasks of a task depend on the task itself.
+	ErrTaskDependencyCycle = &Error{
+		Code: EInvalid,
+		Msg:  "task dependencies form a cycle",
+	}
 )
 
+// ErrTaskUpstreamNotFound is returned when an upstream task of a task does not exist in its organization.
+func ErrTaskUpstreamNotFound(id ID) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("upstream task %s not found", id),
+		Op:   "taskOptions",
+	}
+}
+
+// ErrTaskNotificationEndpointNotFound is returned when the notification endpoint of a task does not exist in its organization.
+func ErrTaskNotificationEndpointNotFound(id ID) *Error {
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("notification endpoint %s not found", id),
+		Op:   "taskNotification",
+	}
+}
+
 // ErrFluxParseError is returned when an error is thrown by Flux.Parse in the task executor
 func ErrFluxParseError(err error) *Error {
 	return &Error{
@@ -171,6 +196,25 @@ func ErrRunExecutionError(err error) *Error {
 	}
 }
 
+// ErrRunBlocked is returned when a run is not executed because a run of an upstream task did not succeed.
+func ErrRunBlocked(err error) *Error {
+	return &Error{
+		Code: EConflict,
+		Msg:  fmt.Sprintf("run blocked by upstream task; Err: %v", err),
+		Op:   "taskExecutor",
+		Err:  err,
+	}
+}
+
+// ErrRunTimedOut is returned when a run is canceled because it exceeded the timeout of its task.
+func ErrRunTimedOut(timeout time.Duration) *Error {
+	return &Error{
+		Code: EUnavailable,
+		Msg:  fmt.Sprintf("run exceeded the timeout of %s", timeout),
+		Op:   "taskExecutor",
+	}
+}
+
 func ErrTaskConcurrencyLimitReached(runsInFront int) *Error {
 	return &Error{
 		Code: ETooManyRequests,
@@ -0,0 +1,114 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// TaskNotifyFirst notifies on the first failed run after a successful run.
+	TaskNotifyFirst = "first"
+	// TaskNotifyEvery notifies on every failed run.
+	TaskNotifyEvery = "every"
+	// TaskNotifyConsecutive notifies once a number of consecutive runs failed.
+	TaskNotifyConsecutive = "consecutive"
+)
+
+// TaskNotificationLogSize is the number of the last log entries of a run included in a notification.
+const TaskNotificationLogSize = 10
+
+// TaskNotification is the notification endpoint a task notifies of its failed runs, and when it notifies.
+// The runs of a task that are retried only count once their last attempt finished.
+type TaskNotification struct {
+	EndpointID ID     `json:"endpointID"`
+	Policy     string `json:"policy"`
+	// Consecutive is the number of consecutive failed runs that trigger a notification with the consecutive policy.
+	Consecutive int `json:"consecutive,omitempty"`
+}
+
+// Valid returns an error if the notification has no endpoint or an invalid policy.
+func (n TaskNotification) Valid() error {
+	if !n.EndpointID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "task notification endpoint ID is invalid",
+		}
+	}
+	switch n.Policy {
+	case TaskNotifyFirst, TaskNotifyEvery:
+		if n.Consecutive != 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("task notification policy %q does not take a number of consecutive failures", n.Policy),
+			}
+		}
+	case TaskNotifyConsecutive:
+		if n.Consecutive < 1 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "task notification number of consecutive failures must be at least 1",
+			}
+		}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid task notification policy: %q", n.Policy),
+		}
+	}
+	return nil
+}
+
+// threshold returns the number of consecutive failed runs from which the task is notified as failing.
+func (n TaskNotification) threshold() int {
+	if n.Policy == TaskNotifyConsecutive {
+		return n.Consecutive
+	}
+	return 1
+}
+
+// NotifyFailure returns true if a failed run, which makes failures consecutive failed runs, is notified.
+func (n TaskNotification) NotifyFailure(failures int) bool {
+	if n.Policy == TaskNotifyEvery {
+		return failures >= 1
+	}
+	return failures == n.threshold()
+}
+
+// NotifyRecovery returns true if a successful run following failures consecutive failed runs is notified.
+// A recovery is notified if the failures were notified.
+func (n TaskNotification) NotifyRecovery(failures int) bool {
+	return failures >= n.threshold()
+}
+
+// TaskRunNotification is the notification of a failed run of a task, or of the successful run it recovered with.
+type TaskRunNotification struct {
+	TaskID         ID        `json:"taskID"`
+	TaskName       string    `json:"taskName"`
+	OrganizationID ID        `json:"orgID"`
+	RunID          ID        `json:"runID"`
+	ScheduledFor   time.Time `json:"scheduledFor"`
+	Recovered      bool      `json:"recovered"`
+	// ConsecutiveFailures is the number of consecutive failed runs, including this run unless it recovered.
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+	Error               string `json:"error,omitempty"`
+	// Log is the last log entries of the run.
+	Log []Log `json:"log,omitempty"`
+}
+
+// Title returns a one line summary of the notification.
+func (n TaskRunNotification) Title() string {
+	if n.Recovered {
+		return fmt.Sprintf("Task %q recovered after %d failed runs", n.TaskName, n.ConsecutiveFailures)
+	}
+	if n.ConsecutiveFailures > 1 {
+		return fmt.Sprintf("Task %q failed %d runs in a row", n.TaskName, n.ConsecutiveFailures)
+	}
+	return fmt.Sprintf("Task %q failed", n.TaskName)
+}
+
+// TaskRunNotifier sends the notifications of task runs to the notification endpoints of the tasks.
+type TaskRunNotifier interface {
+	// NotifyTaskRun sends n to endpoint of the organization of the task.
+	NotifyTaskRun(ctx context.Context, endpointID ID, n TaskRunNotification) error
+}
@@ -2,6 +2,7 @@ package influxdb_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -32,6 +33,17 @@ func TestOptionsMarshal(t *testing.T) {
 	if tu.Flux == nil {
 		t.Fatalf("flux not properly unmarshaled, expected not nil but got nil")
 	}
+
+	tu = &platform.TaskUpdate{}
+	if err := json.Unmarshal([]byte(`{"notification":{"endpointID":"0000000000000001","policy":"consecutive","consecutive":3}}`), tu); err != nil {
+		t.Fatal(err)
+	}
+	if n := tu.Notification; n == nil || n.EndpointID != 1 || n.Policy != platform.TaskNotifyConsecutive || n.Consecutive != 3 {
+		t.Fatalf("notification not properly unmarshaled, got %+v", n)
+	}
+	if err := tu.Validate(); err != nil {
+		t.Fatalf("expected an update of the notification alone to be valid, got %v", err)
+	}
 }
 
 func TestOptionsEdit(t *testing.T) {
@@ -136,5 +148,132 @@ from(bucket: "x")
 			t.Fatalf(cmp.Diff(*tu.Flux, expscript))
 		}
 	})
+	t.Run("set location", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		if err := json.Unmarshal([]byte(`{"cron":"0 9 * * 1-5","location":"Europe/Berlin"}`), tu); err != nil {
+			t.Fatal(err)
+		}
+		if err := tu.UpdateFlux(`option task = {every: 20s, name: "foo"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(*tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Cron != "0 9 * * 1-5" || op.Location != "Europe/Berlin" {
+			t.Fatalf("expected cron in Europe/Berlin but got cron %q in %q", op.Cron, op.Location)
+		}
+	})
+	t.Run("switching from cron to every removes location", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.Every = *(options.MustParseDuration("10s"))
+		if err := tu.UpdateFlux(`option task = {cron: "0 9 * * *", location: "Europe/Berlin", name: "foo"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(*tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Location != "" {
+			t.Fatalf("expected location to be removed but was %q", op.Location)
+		}
+	})
+	t.Run("set upstream tasks", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		if err := json.Unmarshal([]byte(`{"after":["0000000000000001","0000000000000002"]}`), tu); err != nil {
+			t.Fatal(err)
+		}
+		if err := tu.UpdateFlux(`option task = {every: 20s, name: "foo", after: ["0000000000000003"]} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(*tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := []string{"0000000000000001", "0000000000000002"}; !cmp.Equal(op.After, exp) {
+			t.Fatalf("unexpected upstream tasks -got/+exp\n%s", cmp.Diff(op.After, exp))
+		}
+	})
+	t.Run("delete upstream tasks", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		if err := json.Unmarshal([]byte(`{"after":[]}`), tu); err != nil {
+			t.Fatal(err)
+		}
+		expscript := `option task = {every: 20s, name: "foo"}
+
+from(bucket: "x")
+	|> range(start: -1h)`
+		if err := tu.UpdateFlux(`option task = {every: 20s, after: ["0000000000000003"], name: "foo"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(*tu.Flux, expscript) {
+			t.Fatalf(cmp.Diff(*tu.Flux, expscript))
+		}
+	})
+
+}
+
+func TestDiffTaskVersions(t *testing.T) {
+	from := &platform.TaskVersion{Version: 1, Flux: "option task = {name: \"a\", every: 1h}\n\nfrom(bucket: \"x\")\n\t|> range(start: -1h)\n"}
+	to := &platform.TaskVersion{Version: 2, Flux: "option task = {name: \"a\", every: 1h}\n\nfrom(bucket: \"y\")\n\t|> range(start: -1h)\n\t|> count()"}
+
+	d := platform.DiffTaskVersions(from, to)
+	exp := " option task = {name: \"a\", every: 1h}\n \n-from(bucket: \"x\")\n+from(bucket: \"y\")\n \t|> range(start: -1h)\n+\t|> count()\n"
+	if d.From != 1 || d.To != 2 || d.Diff != exp {
+		t.Fatalf("unexpected diff -got/+exp\n%s", cmp.Diff(d.Diff, exp))
+	}
+
+	d = platform.DiffTaskVersions(nil, from)
+	if d.From != 0 || strings.Count(d.Diff, "+") != 4 {
+		t.Fatalf("expected every line to be added, got\n%s", d.Diff)
+	}
+}
+
+func TestTaskNotification(t *testing.T) {
+	for _, tt := range []struct {
+		n        platform.TaskNotification
+		failures []bool // whether the failed run that makes i+1 consecutive failures is notified
+		recovery []bool // whether the run succeeding after i consecutive failures is notified
+	}{
+		{
+			n:        platform.TaskNotification{EndpointID: 1, Policy: platform.TaskNotifyFirst},
+			failures: []bool{true, false, false},
+			recovery: []bool{false, true, true},
+		},
+		{
+			n:        platform.TaskNotification{EndpointID: 1, Policy: platform.TaskNotifyEvery},
+			failures: []bool{true, true, true},
+			recovery: []bool{false, true, true},
+		},
+		{
+			n:        platform.TaskNotification{EndpointID: 1, Policy: platform.TaskNotifyConsecutive, Consecutive: 2},
+			failures: []bool{false, true, false},
+			recovery: []bool{false, false, true},
+		},
+	} {
+		if err := tt.n.Valid(); err != nil {
+			t.Fatalf("%s: %v", tt.n.Policy, err)
+		}
+		for i, exp := range tt.failures {
+			if got := tt.n.NotifyFailure(i + 1); got != exp {
+				t.Errorf("%s: expected notify failure %d to be %v", tt.n.Policy, i+1, exp)
+			}
+		}
+		for i, exp := range tt.recovery {
+			if got := tt.n.NotifyRecovery(i); got != exp {
+				t.Errorf("%s: expected notify recovery after %d failures to be %v", tt.n.Policy, i, exp)
+			}
+		}
+	}
 
+	for _, n := range []platform.TaskNotification{
+		{Policy: platform.TaskNotifyFirst},
+		{EndpointID: 1, Policy: "sometimes"},
+		{EndpointID: 1, Policy: platform.TaskNotifyConsecutive},
+		{EndpointID: 1, Policy: platform.TaskNotifyEvery, Consecutive: 2},
+	} {
+		if err := n.Valid(); err == nil {
+			t.Errorf("expected notification %+v to be invalid", n)
+		}
+	}
 }
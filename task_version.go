@@ -0,0 +1,110 @@
+package influxdb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/task/options"
+)
+
+// ErrTaskVersionNotFound is returned when searching for a task version that doesn't exist.
+var ErrTaskVersionNotFound = &Error{
+	Code: ENotFound,
+	Msg:  "task version not found",
+}
+
+// TaskVersion is a revision of the Flux script of a task.
+// A version is recorded when a task is created and every time its script changes.
+type TaskVersion struct {
+	TaskID      ID              `json:"taskID"`
+	Version     int             `json:"version"`
+	Flux        string          `json:"flux"`
+	Options     options.Options `json:"options"`
+	AuthorID    ID              `json:"authorID,omitempty"`    // AuthorID is the ID of the user that made the change
+	Description string          `json:"description,omitempty"` // Description is the description of the change given by its author
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// TaskVersionDiff is the line by line difference between the scripts of two versions of a task.
+type TaskVersionDiff struct {
+	TaskID ID     `json:"taskID"`
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	Diff   string `json:"diff"`
+}
+
+// DiffTaskVersions returns the difference from the script of version from to the script of version to.
+// A nil from is an empty script.
+func DiffTaskVersions(from, to *TaskVersion) *TaskVersionDiff {
+	d := &TaskVersionDiff{
+		TaskID: to.TaskID,
+		To:     to.Version,
+	}
+	var fromFlux string
+	if from != nil {
+		d.From = from.Version
+		fromFlux = from.Flux
+	}
+	d.Diff = diffLines(fromFlux, to.Flux)
+	return d
+}
+
+// diffLines returns the lines of a and b prefixed with "-" when they were removed from a,
+// "+" when they were added in b and a space when they are in both.
+func diffLines(a, b string) string {
+	al, bl := splitLines(a), splitLines(b)
+
+	// lcs[i][j] is the length of the longest common subsequence of al[i:] and bl[j:].
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			switch {
+			case al[i] == bl[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(al) || j < len(bl) {
+		switch {
+		case i < len(al) && j < len(bl) && al[i] == bl[j]:
+			sb.WriteString(" " + al[i] + "\n")
+			i++
+			j++
+		case j == len(bl) || (i < len(al) && lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("-" + al[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+" + bl[j] + "\n")
+			j++
+		}
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// TaskVersionService finds the versions of tasks.
+// A version is restored by updating the task with its script.
+type TaskVersionService interface {
+	// FindTaskVersions returns the versions of a task, the most recent first.
+	FindTaskVersions(ctx context.Context, taskID ID) ([]*TaskVersion, error)
+
+	// FindTaskVersion returns a single version of a task.
+	FindTaskVersion(ctx context.Context, taskID ID, version int) (*TaskVersion, error)
+}
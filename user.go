@@ -68,6 +68,8 @@ type UserService interface {
 type UserUpdate struct {
 	Name   *string `json:"name"`
 	Status *Status `json:"status"`
+	// OAuthID links the user to the subject the identity provider identifies it with.
+	OAuthID *string `json:"oauthID,omitempty"`
 }
 
 // Valid validates UserUpdate